@@ -0,0 +1,303 @@
+package gno_cdn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/exp/slog"
+)
+
+const (
+	// DefaultAssetCacheMaxBytes is the default memory budget for cached assets
+	DefaultAssetCacheMaxBytes = 64 << 20
+	// DefaultAssetCacheTTL is how long assets of branch-like versions are cached by default
+	DefaultAssetCacheTTL = 5 * time.Minute
+	// maxCachedAssets bounds the number of cached assets, whatever their size
+	maxCachedAssets = 10000
+)
+
+// pinnedVersion matches versions that always resolve to the same content: full semantic
+// versions and commit hashes. Ranges like @1 or @1.2 and branches like @main can move.
+var pinnedVersion = regexp.MustCompile(`^(v?\d+\.\d+\.\d+([-+][0-9A-Za-z.-]+)?|[0-9a-f]{7,40})$`)
+
+// cachedHeaders are the response headers kept with a cached asset
+var cachedHeaders = []string{"Content-Type", "Cache-Control", "Last-Modified", "ETag"}
+
+// immutableCacheControl is sent for pinned versions, whose content never changes
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
+// cachedAsset is an upstream response kept in the asset cache
+type cachedAsset struct {
+	Header    http.Header
+	Body      []byte
+	ExpiresAt time.Time // zero for assets that never expire
+}
+
+func (a *cachedAsset) expired(now time.Time) bool {
+	return !a.ExpiresAt.IsZero() && now.After(a.ExpiresAt)
+}
+
+// assetCache keeps proxied assets in memory, bounded in bytes, and optionally on disk so they
+// survive restarts.
+type assetCache struct {
+	mu       sync.Mutex
+	entries  *lru.Cache[string, *cachedAsset]
+	size     int64
+	maxBytes int64
+	ttl      time.Duration
+	dir      string
+}
+
+func newAssetCache(maxBytes int64, ttl time.Duration, dir string) *assetCache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultAssetCacheMaxBytes
+	}
+	if ttl <= 0 {
+		ttl = DefaultAssetCacheTTL
+	}
+	c := &assetCache{maxBytes: maxBytes, ttl: ttl, dir: dir}
+	c.entries, _ = lru.NewWithEvict(maxCachedAssets, func(_ string, asset *cachedAsset) {
+		c.size -= int64(len(asset.Body))
+	})
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			slog.Error("Error creating asset cache directory, caching in memory only", slog.String("dir", dir), slog.String("err", err.Error()))
+			c.dir = ""
+		}
+	}
+	return c
+}
+
+// Get returns the unexpired asset cached under key, from memory or else from disk.
+func (c *assetCache) Get(key string) (*cachedAsset, bool) {
+	now := time.Now()
+	c.mu.Lock()
+	asset, found := c.entries.Get(key)
+	if found && asset.expired(now) {
+		c.entries.Remove(key)
+		found = false
+	}
+	c.mu.Unlock()
+	if found {
+		return asset, true
+	}
+
+	asset, found = c.load(key)
+	if !found || asset.expired(now) {
+		return nil, false
+	}
+	c.remember(key, asset)
+	return asset, true
+}
+
+// Put caches an asset under key, unless it is larger than the whole cache.
+func (c *assetCache) Put(key string, asset *cachedAsset) {
+	if !c.remember(key, asset) {
+		return
+	}
+	c.store(key, asset)
+}
+
+// Len returns the number of assets cached in memory.
+func (c *assetCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// remember keeps asset in memory, evicting the least recently used assets beyond the budget.
+// Assets larger than the whole budget, such as ones stored on disk under a larger budget, are
+// refused and false is returned.
+func (c *assetCache) remember(key string, asset *cachedAsset) bool {
+	if int64(len(asset.Body)) > c.maxBytes {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.Remove(key)
+	c.entries.Add(key, asset)
+	c.size += int64(len(asset.Body))
+	for c.size > c.maxBytes && c.entries.Len() > 0 {
+		c.entries.RemoveOldest()
+	}
+	return true
+}
+
+// diskPath returns the file an asset is stored in on disk.
+func (c *assetCache) diskPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+func (c *assetCache) load(key string) (*cachedAsset, bool) {
+	if c.dir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.diskPath(key))
+	if err != nil {
+		return nil, false
+	}
+	var asset cachedAsset
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&asset); err != nil {
+		slog.Error("Error decoding cached asset", slog.String("key", key), slog.String("err", err.Error()))
+		return nil, false
+	}
+	return &asset, true
+}
+
+func (c *assetCache) store(key string, asset *cachedAsset) {
+	if c.dir == "" {
+		return
+	}
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(asset); err != nil {
+		slog.Error("Error encoding cached asset", slog.String("key", key), slog.String("err", err.Error()))
+		return
+	}
+	// Write to a temporary file first so readers never see a partial asset
+	path := c.diskPath(key)
+	if err := os.WriteFile(path+".tmp", data.Bytes(), 0o644); err != nil {
+		slog.Error("Error writing cached asset", slog.String("key", key), slog.String("err", err.Error()))
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		slog.Error("Error writing cached asset", slog.String("key", key), slog.String("err", err.Error()))
+	}
+}
+
+// expiry returns when an upstream response for an asset of the given version expires, and false
+// if it must not be cached. An upstream max-age is respected; otherwise pinned versions never
+// expire and branch-like versions expire after the cache TTL.
+func (c *assetCache) expiry(version string, header http.Header, now time.Time) (time.Time, bool) {
+	maxAge := -1
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+		switch name {
+		case "no-store", "no-cache", "private":
+			return time.Time{}, false
+		case "max-age", "s-maxage":
+			if seconds, err := strconv.Atoi(value); err == nil && (maxAge < 0 || name == "s-maxage") {
+				maxAge = seconds
+			}
+		}
+	}
+
+	switch {
+	case maxAge == 0:
+		return time.Time{}, false
+	case maxAge > 0:
+		return now.Add(time.Duration(maxAge) * time.Second), true
+	case pinnedVersion.MatchString(version):
+		return time.Time{}, true
+	default:
+		return now.Add(c.ttl), true
+	}
+}
+
+// capture caches a successful upstream response for an asset of the given version, leaving
+// the response readable for the client, and returns the cached asset. Responses that are not
+// cacheable or larger than the cache are passed through and nil is returned.
+func (c *assetCache) capture(key, version string, resp *http.Response) (*cachedAsset, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil
+	}
+	expiresAt, cacheable := c.expiry(version, resp.Header, time.Now())
+	if !cacheable || resp.ContentLength > c.maxBytes {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxBytes {
+		// Too large to cache: hand the client what was read followed by the rest
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return nil, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// A strong validator of our own lets clients revalidate against the cache
+	resp.Header.Set("ETag", assetETag(body))
+	if pinnedVersion.MatchString(version) {
+		resp.Header.Set("Cache-Control", immutableCacheControl)
+	}
+	header := http.Header{}
+	for _, name := range cachedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+	asset := &cachedAsset{Header: header, Body: body, ExpiresAt: expiresAt}
+	c.Put(key, asset)
+	return asset, nil
+}
+
+// writeAsset serves an asset, or 304 Not Modified if the request's If-None-Match matches its
+// ETag. cacheStatus is reported in X-Cache-Status.
+func writeAsset(w http.ResponseWriter, r *http.Request, asset *cachedAsset, cacheStatus string) {
+	for name, values := range asset.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set("X-Cache-Status", cacheStatus)
+	if etagMatches(r.Header.Get("If-None-Match"), asset.Header.Get("ETag")) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(asset.Body)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(asset.Body)
+}
+
+// notModified turns a successful upstream response into 304 Not Modified if the request's
+// If-None-Match matches its ETag.
+func notModified(resp *http.Response) {
+	if resp.StatusCode != http.StatusOK || !etagMatches(resp.Request.Header.Get("If-None-Match"), resp.Header.Get("ETag")) {
+		return
+	}
+	_ = resp.Body.Close()
+	resp.StatusCode = http.StatusNotModified
+	resp.Status = http.StatusText(http.StatusNotModified)
+	resp.Body = http.NoBody
+	resp.ContentLength = 0
+	resp.Header.Del("Content-Length")
+}
+
+// assetETag returns the strong ETag of an asset body: its quoted SHA-256.
+func assetETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag, using the weak
+// comparison RFC 9110 specifies for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+package gno_cdn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestServer returns a server proxying to backend, with the given repo versions already
+// validated so no realm query is needed
+func newTestServer(t *testing.T, backend *httptest.Server, validated ...string) *Server {
+	t.Helper()
+	s := NewCdnServer(&ServerOptions{
+		TargetHost:    backend.URL,
+		GnolandRpcUrl: "http://127.0.0.1:26657",
+		Realm:         "gno.land/r/cdn000",
+		CacheSize:     10,
+	})
+	for _, key := range validated {
+		s.Cache.Add(key, true)
+	}
+	return s
+}
+
+// countingBackend serves a small script, counting the requests it receives
+func countingBackend(t *testing.T, cacheControl string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+		w.Header().Set("Content-Type", "application/javascript")
+		_, _ = w.Write([]byte("console.log('gno')"))
+	}))
+	t.Cleanup(backend.Close)
+	return backend, &requests
+}
+
+func get(t *testing.T, s *Server, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestProxyServesRepeatRequestsFromCache(t *testing.T) {
+	backend, requests := countingBackend(t, "")
+	s := newTestServer(t, backend, "gnoverse/frames@1.0.0")
+	path := "/gh/gnoverse/frames@1.0.0/static/frame.js"
+
+	first := get(t, s, path)
+	if first.Code != http.StatusOK || first.Header().Get("X-Cache-Status") != "MISS" {
+		t.Fatalf("first request = %d %q, want 200 MISS", first.Code, first.Header().Get("X-Cache-Status"))
+	}
+
+	second := get(t, s, path)
+	if second.Code != http.StatusOK || second.Header().Get("X-Cache-Status") != "HIT" {
+		t.Fatalf("second request = %d %q, want 200 HIT", second.Code, second.Header().Get("X-Cache-Status"))
+	}
+	if second.Body.String() != "console.log('gno')" || second.Header().Get("Content-Type") != "application/javascript" {
+		t.Errorf("cached response = %q (%s), want the upstream asset", second.Body.String(), second.Header().Get("Content-Type"))
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("backend requests = %d, want the repeat request served from cache", got)
+	}
+}
+
+func TestProxyRespectsUpstreamNoStore(t *testing.T) {
+	backend, requests := countingBackend(t, "no-store")
+	s := newTestServer(t, backend, "gnoverse/frames@1.0.0")
+	path := "/gh/gnoverse/frames@1.0.0/static/frame.js"
+
+	get(t, s, path)
+	if rec := get(t, s, path); rec.Header().Get("X-Cache-Status") != "MISS" {
+		t.Errorf("X-Cache-Status = %q, want no-store responses never cached", rec.Header().Get("X-Cache-Status"))
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("backend requests = %d, want every request proxied", got)
+	}
+}
+
+func TestProxyHonorsIfNoneMatch(t *testing.T) {
+	backend, requests := countingBackend(t, "")
+	s := newTestServer(t, backend, "gnoverse/frames@1.0.0")
+	path := "/gh/gnoverse/frames@1.0.0/static/frame.js"
+
+	first := get(t, s, path)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag != assetETag([]byte("console.log('gno')")) {
+		t.Fatalf("first request = %d with ETag %q, want 200 with the body hash", first.Code, etag)
+	}
+	if got := first.Header().Get("Cache-Control"); got != immutableCacheControl {
+		t.Errorf("Cache-Control = %q, want pinned versions immutable", got)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("If-None-Match", etag)
+	s.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("conditional request = %d with %d bytes, want 304 without body", rec.Code, rec.Body.Len())
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("backend requests = %d, want the conditional request answered from cache", got)
+	}
+
+	// A cache miss is revalidated against the fetched body
+	rec = httptest.NewRecorder()
+	newTestServer(t, backend, "gnoverse/frames@1.0.0").router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("conditional request on a cache miss = %d with %d bytes, want 304 without body", rec.Code, rec.Body.Len())
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{ifNoneMatch: `"abc"`, want: true},
+		{ifNoneMatch: `W/"abc"`, want: true},
+		{ifNoneMatch: `"xyz", "abc"`, want: true},
+		{ifNoneMatch: `*`, want: true},
+		{ifNoneMatch: `"xyz"`, want: false},
+		{ifNoneMatch: ``, want: false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.ifNoneMatch, `"abc"`); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.ifNoneMatch, got, tt.want)
+		}
+	}
+}
+
+func TestAssetCacheExpiry(t *testing.T) {
+	c := newAssetCache(0, time.Minute, "")
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		version      string
+		cacheControl string
+		wantCached   bool
+		wantExpires  time.Time
+	}{
+		{name: "pinned version", version: "1.2.3", wantCached: true},
+		{name: "commit hash", version: "34b8c56", wantCached: true},
+		{name: "branch", version: "main", wantCached: true, wantExpires: now.Add(time.Minute)},
+		{name: "version range", version: "1", wantCached: true, wantExpires: now.Add(time.Minute)},
+		{name: "upstream max-age", version: "main", cacheControl: "public, max-age=30", wantCached: true, wantExpires: now.Add(30 * time.Second)},
+		{name: "upstream private", version: "1.2.3", cacheControl: "private", wantCached: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.cacheControl != "" {
+				header.Set("Cache-Control", tt.cacheControl)
+			}
+			expires, cached := c.expiry(tt.version, header, now)
+			if cached != tt.wantCached || !expires.Equal(tt.wantExpires) {
+				t.Errorf("expiry() = %v, %v, want %v, %v", expires, cached, tt.wantExpires, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestAssetCacheDiskBacked(t *testing.T) {
+	dir := t.TempDir()
+	newAssetCache(0, time.Minute, dir).Put("gh/a/b@1.0.0/x.js", &cachedAsset{Body: []byte("x")})
+
+	// A fresh cache, as after a restart, finds the asset on disk
+	asset, found := newAssetCache(0, time.Minute, dir).Get("gh/a/b@1.0.0/x.js")
+	if !found || string(asset.Body) != "x" {
+		t.Errorf("Get() after restart = %v, %v, want the asset stored on disk", asset, found)
+	}
+}
+
+func TestAssetCacheEvictsBeyondMaxBytes(t *testing.T) {
+	c := newAssetCache(10, time.Minute, "")
+	c.Put("first", &cachedAsset{Body: []byte("123456")})
+	c.Put("second", &cachedAsset{Body: []byte("123456")})
+
+	if _, found := c.Get("first"); found {
+		t.Error("least recently used asset should be evicted beyond the byte budget")
+	}
+	if _, found := c.Get("second"); !found {
+		t.Error("most recent asset should stay cached")
+	}
+}
+
+func TestAssetCacheRefusesAssetsBeyondMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	newAssetCache(0, time.Minute, dir).Put("large", &cachedAsset{Body: []byte("0123456789abcdef")})
+
+	// Restarted with a smaller budget, the asset stored on disk is served but not kept in memory
+	c := newAssetCache(10, time.Minute, dir)
+	asset, found := c.Get("large")
+	if !found || string(asset.Body) != "0123456789abcdef" {
+		t.Fatalf("Get() = %v, %v, want the asset stored on disk", asset, found)
+	}
+	if c.Len() != 0 || c.size != 0 {
+		t.Errorf("cache holds %d assets of %d bytes, want the oversized asset refused", c.Len(), c.size)
+	}
+
+	c.Put("small", &cachedAsset{Body: []byte("123")})
+	c.Put("huge", &cachedAsset{Body: []byte("0123456789abcdef")})
+	if _, found := c.Get("small"); !found {
+		t.Error("an oversized asset should not evict the cached ones")
+	}
+}
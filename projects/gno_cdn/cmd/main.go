@@ -4,6 +4,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/allinbits/labs/projects/gno_cdn"
 )
@@ -12,6 +15,11 @@ func main() {
 	var targetHost string
 	var listenAddress string
 	var rpcUrl string
+	var cacheMaxBytes int64
+	var cacheTTL time.Duration
+	var cacheDir string
+	var allowedRepos string
+	var readHeaderTimeout, readTimeout, writeTimeout, idleTimeout, shutdownTimeout time.Duration
 
 	defaultTargetHost := os.Getenv("GNO_CDN__TARGET_HOST")
 	if defaultTargetHost == "" {
@@ -26,12 +34,39 @@ func main() {
 		defaultRpcUrl = "http://127.0.0.1:26657"
 	}
 
+	defaultCacheMaxBytes := int64(gno_cdn.DefaultAssetCacheMaxBytes)
+	if value := os.Getenv("GNO_CDN__CACHE_MAX_BYTES"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			panic("Invalid GNO_CDN__CACHE_MAX_BYTES: " + err.Error())
+		}
+		defaultCacheMaxBytes = parsed
+	}
+
 	flag.StringVar(&targetHost, "target-host", defaultTargetHost,
 		"Target host for CDN (or set GNO_CDN__TARGET_HOST)")
 	flag.StringVar(&listenAddress, "addr", defaultListenAddr,
 		"Gno CDN HTTP listen address (or set GNO_CDN__LISTEN_ADDRESS)")
 	flag.StringVar(&rpcUrl, "rpc-url", defaultRpcUrl,
 		"Gno CDN RPC URL (or set GNO_CDN__RPC_URL)")
+	flag.Int64Var(&cacheMaxBytes, "cache-max-bytes", defaultCacheMaxBytes,
+		"Memory budget for cached assets in bytes (or set GNO_CDN__CACHE_MAX_BYTES)")
+	flag.DurationVar(&cacheTTL, "cache-ttl", envDuration("GNO_CDN__CACHE_TTL", gno_cdn.DefaultAssetCacheTTL),
+		"How long assets of branch-like versions are cached (or set GNO_CDN__CACHE_TTL)")
+	flag.StringVar(&cacheDir, "cache-dir", os.Getenv("GNO_CDN__CACHE_DIR"),
+		"Directory keeping cached assets across restarts, empty for memory only (or set GNO_CDN__CACHE_DIR)")
+	flag.StringVar(&allowedRepos, "allowed-repos", os.Getenv("GNO_CDN__ALLOWED_REPOS"),
+		"Comma-separated user/repo globs the proxy may serve, empty allows any (or set GNO_CDN__ALLOWED_REPOS)")
+	flag.DurationVar(&readHeaderTimeout, "read-header-timeout", envDuration("GNO_CDN__READ_HEADER_TIMEOUT", gno_cdn.DefaultReadHeaderTimeout),
+		"Time allowed to read request headers (or set GNO_CDN__READ_HEADER_TIMEOUT)")
+	flag.DurationVar(&readTimeout, "read-timeout", envDuration("GNO_CDN__READ_TIMEOUT", gno_cdn.DefaultReadTimeout),
+		"Time allowed to read a whole request (or set GNO_CDN__READ_TIMEOUT)")
+	flag.DurationVar(&writeTimeout, "write-timeout", envDuration("GNO_CDN__WRITE_TIMEOUT", gno_cdn.DefaultWriteTimeout),
+		"Time allowed to write a response (or set GNO_CDN__WRITE_TIMEOUT)")
+	flag.DurationVar(&idleTimeout, "idle-timeout", envDuration("GNO_CDN__IDLE_TIMEOUT", gno_cdn.DefaultIdleTimeout),
+		"How long idle keep-alive connections stay open (or set GNO_CDN__IDLE_TIMEOUT)")
+	flag.DurationVar(&shutdownTimeout, "shutdown-timeout", envDuration("GNO_CDN__SHUTDOWN_TIMEOUT", gno_cdn.DefaultShutdownTimeout),
+		"Grace period for in-flight requests on SIGINT/SIGTERM (or set GNO_CDN__SHUTDOWN_TIMEOUT)")
 
 	flag.Parse()
 
@@ -45,6 +80,18 @@ func main() {
 		GnolandRpcUrl: rpcUrl,
 		Realm:         "gno.land/r/cdn000",
 		CacheSize:     100,
+
+		AssetCacheMaxBytes: cacheMaxBytes,
+		AssetCacheTTL:      cacheTTL,
+		AssetCacheDir:      cacheDir,
+
+		AllowedRepos: splitList(allowedRepos),
+
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		ShutdownTimeout:   shutdownTimeout,
 	}
 
 	server := gno_cdn.NewCdnServer(&config)
@@ -52,3 +99,27 @@ func main() {
 		panic("Failed to start server: " + err.Error())
 	}
 }
+
+// envDuration returns the duration set in the named environment variable, or fallback if unset.
+func envDuration(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		panic("Invalid " + name + ": " + err.Error())
+	}
+	return parsed
+}
+
+// splitList splits a comma-separated list, dropping blank entries.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
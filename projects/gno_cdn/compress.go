@@ -0,0 +1,138 @@
+package gno_cdn
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compressMinBytes is the size below which assets are not worth compressing
+const compressMinBytes = 1024
+
+// encoders are the content encodings assets can be compressed with, in order of preference
+var encoders = []struct {
+	name string
+	new  func(io.Writer) io.WriteCloser
+}{
+	{name: "gzip", new: func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }},
+}
+
+// compressibleTypes are the non-text media types worth compressing; text/* always is
+var compressibleTypes = map[string]bool{
+	"application/javascript": true,
+	"application/json":       true,
+	"application/xml":        true,
+	"image/svg+xml":          true,
+}
+
+// compressible reports whether an asset is worth compressing: a large enough text-like asset
+// that is not already encoded. Images, fonts and wasm are already compressed.
+func compressible(asset *cachedAsset) bool {
+	if len(asset.Body) < compressMinBytes || asset.Header.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(asset.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || compressibleTypes[mediaType]
+}
+
+// negotiateEncoding returns the preferred encoding the Accept-Encoding header accepts, or "" for
+// the identity encoding.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(coding), ";")
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q > 0
+	}
+	for _, encoder := range encoders {
+		if ok, found := accepted[encoder.name]; found {
+			if ok {
+				return encoder.name
+			}
+			continue
+		}
+		if accepted["*"] {
+			return encoder.name
+		}
+	}
+	return ""
+}
+
+// variant returns the representation of the asset cached under key to serve for the request's
+// Accept-Encoding: a compressed variant, itself cached to avoid compressing again, or the asset.
+func (c *assetCache) variant(key string, asset *cachedAsset, r *http.Request) *cachedAsset {
+	if !compressible(asset) {
+		return asset
+	}
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+	if encoding == "" {
+		return asset
+	}
+
+	variantKey := key + ";" + encoding
+	if compressed, found := c.Get(variantKey); found {
+		return compressed
+	}
+	compressed, err := compress(asset, encoding)
+	if err != nil {
+		return asset
+	}
+	c.Put(variantKey, compressed)
+	return compressed
+}
+
+// compress returns asset encoded with the named encoding, with a distinct ETag.
+func compress(asset *cachedAsset, encoding string) (*cachedAsset, error) {
+	var body bytes.Buffer
+	for _, encoder := range encoders {
+		if encoder.name != encoding {
+			continue
+		}
+		w := encoder.new(&body)
+		if _, err := w.Write(asset.Body); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	header := asset.Header.Clone()
+	header.Set("Content-Encoding", encoding)
+	if etag := header.Get("ETag"); etag != "" {
+		header.Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+encoding+`"`)
+	}
+	return &cachedAsset{Header: header, Body: body.Bytes(), ExpiresAt: asset.ExpiresAt}, nil
+}
+
+// serveAsset serves the representation of the asset cached under key matching the request's
+// Accept-Encoding.
+func (s *Server) serveAsset(w http.ResponseWriter, r *http.Request, key string, asset *cachedAsset, cacheStatus string) {
+	if compressible(asset) {
+		w.Header().Set("Vary", "Accept-Encoding")
+	}
+	writeAsset(w, r, s.assets.variant(key, asset, r), cacheStatus)
+}
+
+// setResponseAsset replaces an upstream response with the given representation of its asset.
+func setResponseAsset(resp *http.Response, asset *cachedAsset) {
+	for name, values := range asset.Header {
+		resp.Header[name] = values
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(asset.Body))
+	resp.ContentLength = int64(len(asset.Body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(asset.Body)))
+}
@@ -0,0 +1,106 @@
+package gno_cdn
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var largeScript = strings.Repeat("console.log('gno');\n", 100)
+
+// assetBackend serves a large script and a large PNG
+func assetBackend(t *testing.T) *httptest.Server {
+	t.Helper()
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".png") {
+			w.Header().Set("Content-Type", "image/png")
+		} else {
+			w.Header().Set("Content-Type", "application/javascript")
+		}
+		_, _ = w.Write([]byte(largeScript))
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+func getEncoded(t *testing.T, s *Server, path, acceptEncoding string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func gunzip(t *testing.T, body io.Reader) string {
+	t.Helper()
+	r, err := gzip.NewReader(body)
+	if err != nil {
+		t.Fatalf("response is not gzip: %v", err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to decompress response: %v", err)
+	}
+	return string(data)
+}
+
+func TestProxyCompressesForGzipClients(t *testing.T) {
+	s := newTestServer(t, assetBackend(t), "gnoverse/frames@1.0.0")
+	path := "/gh/gnoverse/frames@1.0.0/static/frame.js"
+
+	// Both the response of a cache miss and of a hit are compressed
+	for _, status := range []string{"MISS", "HIT"} {
+		rec := getEncoded(t, s, path, "br;q=0, gzip")
+		if rec.Header().Get("X-Cache-Status") != status {
+			t.Fatalf("X-Cache-Status = %q, want %s", rec.Header().Get("X-Cache-Status"), status)
+		}
+		if rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s: Content-Encoding = %q, Vary = %q, want gzip varying on Accept-Encoding", status,
+				rec.Header().Get("Content-Encoding"), rec.Header().Get("Vary"))
+		}
+		if got := gunzip(t, rec.Body); got != largeScript {
+			t.Errorf("%s: decompressed body differs from the upstream asset", status)
+		}
+	}
+}
+
+func TestProxyServesIdentityClientsRawBytes(t *testing.T) {
+	s := newTestServer(t, assetBackend(t), "gnoverse/frames@1.0.0")
+
+	// A gzip client first, so the compressed variant is cached
+	getEncoded(t, s, "/gh/gnoverse/frames@1.0.0/static/frame.js", "gzip")
+	rec := getEncoded(t, s, "/gh/gnoverse/frames@1.0.0/static/frame.js", "identity")
+	if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != largeScript {
+		t.Errorf("identity response encoded as %q, want the raw asset", rec.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestProxySkipsCompressedTypes(t *testing.T) {
+	s := newTestServer(t, assetBackend(t), "gnoverse/frames@1.0.0")
+
+	rec := getEncoded(t, s, "/gh/gnoverse/frames@1.0.0/static/logo.png", "gzip")
+	if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != largeScript {
+		t.Errorf("image encoded as %q, want it served as is", rec.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                    "",
+		"identity":            "",
+		"gzip":                "gzip",
+		"deflate, gzip;q=0.5": "gzip",
+		"gzip;q=0":            "",
+		"*":                   "gzip",
+		"*, gzip;q=0":         "",
+	}
+	for acceptEncoding, want := range tests {
+		if got := negotiateEncoding(acceptEncoding); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", acceptEncoding, got, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package gno_cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/exp/slog"
+)
+
+// proxyError is the JSON body returned when the upstream cannot serve a proxied asset
+type proxyError struct {
+	Error  string `json:"error"`
+	Status int    `json:"status"`
+	// Path is the path originally requested from the CDN
+	Path string `json:"path"`
+}
+
+func (e proxyError) body() []byte {
+	data, _ := json.Marshal(e)
+	return append(data, '\n')
+}
+
+// handleProxyError answers a request the upstream could not be reached for with a JSON error.
+func handleProxyError(backendURL string) func(http.ResponseWriter, *http.Request, error) {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		if errors.Is(err, context.Canceled) {
+			// The client went away, nobody is left to answer
+			slog.Info("Proxy request cancelled", slog.String("url", backendURL))
+			return
+		}
+		slog.Error("Error proxying request", slog.String("url", backendURL), slog.String("err", err.Error()))
+
+		body := proxyError{Error: "upstream unavailable", Status: http.StatusBadGateway, Path: r.URL.Path}.body()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write(body)
+	}
+}
+
+// surfaceUpstreamError replaces the body of an upstream 4xx response with a JSON error keeping
+// its status, so clients get the same error format whatever failed.
+func surfaceUpstreamError(resp *http.Response, backendURL, requestPath string) {
+	if resp.StatusCode < http.StatusBadRequest || resp.StatusCode >= http.StatusInternalServerError {
+		return
+	}
+	slog.Error("Upstream error", slog.String("url", backendURL), slog.Int("status", resp.StatusCode))
+
+	body := proxyError{Error: "upstream returned " + http.StatusText(resp.StatusCode), Status: resp.StatusCode, Path: requestPath}.body()
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header = http.Header{}
+	resp.Header.Set("Content-Type", "application/json")
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+}
@@ -0,0 +1,50 @@
+package gno_cdn
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func decodeProxyError(t *testing.T, rec *httptest.ResponseRecorder) proxyError {
+	t.Helper()
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var body proxyError
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("error body %q is not JSON: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+func TestProxyReportsUnreachableBackend(t *testing.T) {
+	backend := httptest.NewServer(http.NotFoundHandler())
+	backend.Close() // connections are refused from now on
+	s := newTestServer(t, backend, "gnoverse/frames@1.0.0")
+	path := "/gh/gnoverse/frames@1.0.0/static/frame.js"
+
+	rec := get(t, s, path)
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+	if body := decodeProxyError(t, rec); body.Status != http.StatusBadGateway || body.Path != path {
+		t.Errorf("error body = %+v, want status 502 for %s", body, path)
+	}
+}
+
+func TestProxyPassesThroughUpstreamNotFound(t *testing.T) {
+	backend := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(backend.Close)
+	s := newTestServer(t, backend, "gnoverse/frames@1.0.0")
+	path := "/gh/gnoverse/frames@1.0.0/static/missing.js"
+
+	rec := get(t, s, path)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if body := decodeProxyError(t, rec); body.Status != http.StatusNotFound || body.Path != path {
+		t.Errorf("error body = %+v, want status 404 for %s", body, path)
+	}
+}
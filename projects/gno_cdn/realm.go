@@ -0,0 +1,69 @@
+package gno_cdn
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/exp/slog"
+)
+
+// realmFileQuery is the ABCI query returning the content of a file of a deployed package
+const realmFileQuery = "vm/qfile"
+
+// handleRealmAsset serves a file deployed with a realm, such as /r/cdn000/static/app.js for
+// the file static/app.js of gno.land/r/cdn000, fetched from the node over RPC.
+func (s *Server) handleRealmAsset(w http.ResponseWriter, r *http.Request) {
+	filePath := "gno.land/r/" + chi.URLParam(r, "*")
+	// Without an extension the node lists the files of a package instead of returning one
+	if strings.Contains(filePath, "..") || path.Ext(filePath) == "" {
+		http.Error(w, "Realm file not found: "+filePath, http.StatusNotFound)
+		return
+	}
+
+	cacheKey := "r/" + filePath
+	if asset, found := s.assets.Get(cacheKey); found {
+		s.serveAsset(w, r, cacheKey, asset, "HIT")
+		return
+	}
+
+	res, err := s.gnoClient.RPCClient.ABCIQuery(realmFileQuery, []byte(filePath))
+	if err != nil {
+		slog.Error("Error fetching realm file", slog.String("path", filePath), slog.String("err", err.Error()))
+		http.Error(w, "Error fetching realm file", http.StatusBadGateway)
+		return
+	}
+	if res.Response.Error != nil {
+		slog.Info("Realm file not found", slog.String("path", filePath), slog.String("err", res.Response.Error.Error()))
+		http.Error(w, "Realm file not found: "+filePath, http.StatusNotFound)
+		return
+	}
+
+	body := res.Response.Data
+	header := http.Header{}
+	header.Set("Content-Type", realmContentType(filePath))
+	header.Set("ETag", assetETag(body))
+	// A path may be redeployed with other content on a chain reset, so realm files use the TTL
+	asset := &cachedAsset{Header: header, Body: body, ExpiresAt: time.Now().Add(s.assets.ttl)}
+	s.assets.Put(cacheKey, asset)
+	s.serveAsset(w, r, cacheKey, asset, "MISS")
+}
+
+// realmContentType returns the Content-Type of a realm file from its extension. Gno sources and
+// other unknown text files are served as plain text.
+func realmContentType(filePath string) string {
+	ext := path.Ext(filePath)
+	switch ext {
+	case ".gno", ".mod", ".toml":
+		return "text/plain; charset=utf-8"
+	case ".md":
+		return "text/markdown; charset=utf-8"
+	}
+	if contentType := mime.TypeByExtension(ext); contentType != "" {
+		return contentType
+	}
+	return "text/plain; charset=utf-8"
+}
@@ -0,0 +1,118 @@
+package gno_cdn
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// fakeRealmNode starts a node serving the given realm files over vm/qfile, reporting any other
+// file as not available. Returns the server and the number of queries it received.
+func fakeRealmNode(t *testing.T, files map[string]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var queries atomic.Int32
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request rpctypes.RPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		queries.Add(1)
+
+		var params struct {
+			Path string `json:"path"`
+			Data []byte `json:"data"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil || params.Path != realmFileQuery {
+			t.Errorf("unexpected query %s %s", request.Method, request.Params)
+		}
+		result := &ctypes.ResultABCIQuery{}
+		if body, found := files[string(params.Data)]; found {
+			result.Response.Data = []byte(body)
+		} else {
+			result.Response.Error = std.InternalError{}
+		}
+		_ = json.NewEncoder(w).Encode(rpctypes.NewRPCSuccessResponse(request.ID, result))
+	}))
+	t.Cleanup(node.Close)
+	return node, &queries
+}
+
+func newRealmTestServer(t *testing.T, node *httptest.Server) *Server {
+	t.Helper()
+	return NewCdnServer(&ServerOptions{
+		TargetHost:    "https://cdn.jsdelivr.net",
+		GnolandRpcUrl: node.URL,
+		Realm:         "gno.land/r/cdn000",
+		CacheSize:     10,
+	})
+}
+
+func TestRealmAssetServesFileWithContentType(t *testing.T) {
+	node, queries := fakeRealmNode(t, map[string]string{
+		"gno.land/r/cdn000/style.css": "body { color: red; }",
+	})
+	s := newRealmTestServer(t, node)
+
+	first := get(t, s, "/r/cdn000/style.css")
+	if first.Code != http.StatusOK || first.Header().Get("X-Cache-Status") != "MISS" {
+		t.Fatalf("first request = %d %q, want 200 MISS", first.Code, first.Header().Get("X-Cache-Status"))
+	}
+	if first.Body.String() != "body { color: red; }" {
+		t.Errorf("body = %q, want the realm file", first.Body.String())
+	}
+	if got := first.Header().Get("Content-Type"); got != "text/css; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want text/css", got)
+	}
+	if first.Header().Get("ETag") == "" {
+		t.Error("ETag is empty, want the content hash")
+	}
+
+	second := get(t, s, "/r/cdn000/style.css")
+	if second.Code != http.StatusOK || second.Header().Get("X-Cache-Status") != "HIT" {
+		t.Fatalf("second request = %d %q, want 200 HIT", second.Code, second.Header().Get("X-Cache-Status"))
+	}
+	if second.Header().Get("ETag") != first.Header().Get("ETag") {
+		t.Errorf("cached ETag = %q, want %q", second.Header().Get("ETag"), first.Header().Get("ETag"))
+	}
+	if got := queries.Load(); got != 1 {
+		t.Errorf("node queries = %d, want the repeat request served from cache", got)
+	}
+}
+
+func TestRealmAssetNotFound(t *testing.T) {
+	node, queries := fakeRealmNode(t, nil)
+	s := newRealmTestServer(t, node)
+
+	if rec := get(t, s, "/r/cdn000/missing.js"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing file status = %d, want 404", rec.Code)
+	}
+	// A package path would list its files rather than return one
+	if rec := get(t, s, "/r/cdn000"); rec.Code != http.StatusNotFound {
+		t.Errorf("package path status = %d, want 404", rec.Code)
+	}
+	if got := queries.Load(); got != 1 {
+		t.Errorf("node queries = %d, want only the file path queried", got)
+	}
+}
+
+func TestRealmContentType(t *testing.T) {
+	tests := map[string]string{
+		"gno.land/r/cdn000/app.js":    "text/javascript; charset=utf-8",
+		"gno.land/r/cdn000/cdn.gno":   "text/plain; charset=utf-8",
+		"gno.land/r/cdn000/README.md": "text/markdown; charset=utf-8",
+		"gno.land/r/cdn000/logo.svg":  "image/svg+xml",
+		"gno.land/r/cdn000/data.xyz":  "text/plain; charset=utf-8",
+	}
+	for filePath, want := range tests {
+		if got := realmContentType(filePath); got != want {
+			t.Errorf("realmContentType(%q) = %q, want %q", filePath, got, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package gno_cdn
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"github.com/gnolang/gno/gno.land/pkg/gnoclient"
@@ -13,16 +14,31 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
 )
 
 //go:embed index.html
 var indexHtml string
 
+// Default HTTP server timeouts, used when ServerOptions leave them zero
+const (
+	DefaultReadHeaderTimeout = 10 * time.Second
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultWriteTimeout      = 60 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+	DefaultShutdownTimeout   = 30 * time.Second
+)
+
 type Server struct {
-	Cache     *lru.Cache[string, bool]
-	router    *chi.Mux
-	config    *ServerOptions
-	gnoClient *gnoclient.Client
+	Cache      *lru.Cache[string, bool]
+	assets     *assetCache
+	router     *chi.Mux
+	config     *ServerOptions
+	gnoClient  *gnoclient.Client
+	httpServer *http.Server
 }
 
 type ServerOptions struct {
@@ -31,6 +47,18 @@ type ServerOptions struct {
 	GnolandRpcUrl string
 	Realm         string
 	CacheSize     int // Size of the LRU cache for CDN paths
+
+	AssetCacheMaxBytes int64         // Memory budget for cached assets (default 64 MiB)
+	AssetCacheTTL      time.Duration // How long assets of branch-like versions are cached (default 5m)
+	AssetCacheDir      string        // Optional directory keeping cached assets across restarts
+
+	AllowedRepos []string // user/repo globs the proxy may serve (e.g. "gnoverse/*"), empty allows any
+
+	ReadHeaderTimeout time.Duration // Time allowed to read request headers (default 10s)
+	ReadTimeout       time.Duration // Time allowed to read a whole request (default 30s)
+	WriteTimeout      time.Duration // Time allowed to write a response (default 60s)
+	IdleTimeout       time.Duration // How long idle keep-alive connections stay open (default 120s)
+	ShutdownTimeout   time.Duration // Grace period for in-flight requests on shutdown (default 30s)
 }
 
 func NewCdnServer(config *ServerOptions) *Server {
@@ -48,10 +76,20 @@ func NewCdnServer(config *ServerOptions) *Server {
 		router:    chi.NewRouter(),
 		config:    config,
 		gnoClient: &gnoclient.Client{RPCClient: gnolandRpcClient},
+		assets:    newAssetCache(config.AssetCacheMaxBytes, config.AssetCacheTTL, config.AssetCacheDir),
 	}
 
 	s.Cache, err = lru.New[string, bool](config.CacheSize)
 
+	if len(config.AllowedRepos) == 0 {
+		slog.Warn("No allowed repos configured, proxying any GitHub repo")
+	}
+	for _, pattern := range config.AllowedRepos {
+		if _, err := path.Match(pattern, ""); err != nil {
+			slog.Error("Invalid allowed repo pattern, it matches nothing", slog.String("pattern", pattern))
+		}
+	}
+
 	// Middleware setup
 	s.router.Use(middleware.Logger)
 	s.router.Use(middleware.Recoverer)
@@ -66,10 +104,11 @@ func NewCdnServer(config *ServerOptions) *Server {
 	s.router.Get("/badge/r/*", s.handleBadge)
 	s.router.Get("/frame/r/*", s.handleFrame)
 	s.router.Get("/gh/{user}/{repo}@{version}/*", s.handleProxyRequest)
+	s.router.Get("/r/*", s.handleRealmAsset)
 
 	s.router.Get("/status", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		response := fmt.Sprintf(`{"status": "ok", "cache_size": %d }`, s.Cache.Len())
+		response := fmt.Sprintf(`{"status": "ok", "cache_size": %d, "asset_cache_size": %d }`, s.Cache.Len(), s.assets.Len())
 		_, _ = w.Write([]byte(response))
 	})
 
@@ -103,11 +142,52 @@ func NewCdnServer(config *ServerOptions) *Server {
 		_, _ = w.Write([]byte(response))
 	})
 
+	s.httpServer = &http.Server{
+		Addr:              config.ListenAddress,
+		Handler:           s.router,
+		ReadHeaderTimeout: durationOrDefault(config.ReadHeaderTimeout, DefaultReadHeaderTimeout),
+		ReadTimeout:       durationOrDefault(config.ReadTimeout, DefaultReadTimeout),
+		WriteTimeout:      durationOrDefault(config.WriteTimeout, DefaultWriteTimeout),
+		IdleTimeout:       durationOrDefault(config.IdleTimeout, DefaultIdleTimeout),
+	}
+
 	return s
 }
 
+// Run serves until SIGINT or SIGTERM, then shuts down, giving in-flight requests the shutdown
+// timeout to complete.
 func (s *Server) Run() error {
-	return http.ListenAndServe(s.config.ListenAddress, s.router)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down, draining in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), durationOrDefault(s.config.ShutdownTimeout, DefaultShutdownTimeout))
+	defer cancel()
+	return s.Shutdown(shutdownCtx)
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests to complete, or for
+// ctx to be done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+func durationOrDefault(d, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return d
 }
 
 func (s *Server) handleNotFound(w http.ResponseWriter, r *http.Request) {
@@ -128,12 +208,39 @@ func (s *Server) handleProxyRequest(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid backend URL", http.StatusInternalServerError)
 		return
 	}
+	if !s.isAllowedRepo(user, repo) {
+		slog.Error("Repo not allowed", slog.String("user", user), slog.String("repo", repo))
+		http.Error(w, "Repo not allowed: "+user+"/"+repo, http.StatusForbidden)
+		return
+	}
 	if !s.isValidCdnPath(user, repo, version) {
 		slog.Error("Invalid CDN path", slog.String("user", user), slog.String("repo", repo), slog.String("version", version))
 		return
 	}
 
+	cacheKey := "gh/" + user + "/" + repo + "@" + version + "/" + filepath
+	if asset, found := s.assets.Get(cacheKey); found {
+		s.serveAsset(w, r, cacheKey, asset, "HIT")
+		return
+	}
+
 	proxy := s.createReverseProxy(proxyURL)
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		surfaceUpstreamError(resp, backendURL, r.URL.Path)
+		resp.Header.Set("X-Cache-Status", "MISS")
+		asset, err := s.assets.capture(cacheKey, version, resp)
+		if err != nil {
+			return err
+		}
+		if asset != nil {
+			if compressible(asset) {
+				resp.Header.Set("Vary", "Accept-Encoding")
+			}
+			setResponseAsset(resp, s.assets.variant(cacheKey, asset, r))
+		}
+		notModified(resp)
+		return nil
+	}
 	proxy.ServeHTTP(w, r)
 }
 
@@ -148,10 +255,27 @@ func (s *Server) createReverseProxy(proxyURL *url.URL) *httputil.ReverseProxy {
 		req.URL.Scheme = proxyURL.Scheme
 		req.URL.Host = proxyURL.Host
 		req.URL.Path = proxyURL.Path
+		// Let the transport negotiate compression so cached bodies are stored decoded
+		req.Header.Del("Accept-Encoding")
 	}
+	proxy.ErrorHandler = handleProxyError(proxyURL.String())
 	return proxy
 }
 
+// isAllowedRepo reports whether user/repo matches one of the allowed repo globs, or any repo if
+// none are configured.
+func (s *Server) isAllowedRepo(user, repo string) bool {
+	if len(s.config.AllowedRepos) == 0 {
+		return true
+	}
+	for _, pattern := range s.config.AllowedRepos {
+		if matched, _ := path.Match(pattern, user+"/"+repo); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // Use cache to avoid hitting the backend every time
 func (s *Server) isValidCdnPath(user, repo, version string) bool {
 	cacheKey := user + "/" + repo + "@" + version
@@ -0,0 +1,88 @@
+package gno_cdn
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProxyAllowedRepos(t *testing.T) {
+	backend, requests := countingBackend(t, "")
+	s := newTestServer(t, backend, "gnoverse/frames@1.0.0", "allinbits/labs@1.0.0", "someone/else@1.0.0")
+	s.config.AllowedRepos = []string{"gnoverse/*", "allinbits/labs"}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/gh/allinbits/labs@1.0.0/app.js", want: http.StatusOK},
+		{path: "/gh/gnoverse/frames@1.0.0/app.js", want: http.StatusOK},
+		{path: "/gh/someone/else@1.0.0/app.js", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if rec := get(t, s, tt.path); rec.Code != tt.want {
+			t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.want)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("backend requests = %d, want disallowed repos never proxied", got)
+	}
+}
+
+func TestProxyAllowsAnyRepoWithoutAllowlist(t *testing.T) {
+	backend, _ := countingBackend(t, "")
+	s := newTestServer(t, backend, "someone/else@1.0.0")
+
+	if rec := get(t, s, "/gh/someone/else@1.0.0/app.js"); rec.Code != http.StatusOK {
+		t.Errorf("GET without allowlist = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestShutdownWaitsForInFlightRequests(t *testing.T) {
+	backend, _ := countingBackend(t, "")
+	s := newTestServer(t, backend)
+	started, release := make(chan struct{}), make(chan struct{})
+	s.router.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() { _ = s.httpServer.Serve(listener) }()
+
+	responded := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		if err != nil {
+			responded <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		responded <- string(body)
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- s.Shutdown(context.Background()) }()
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown() = %v before the in-flight request completed", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	if body := <-responded; body != "done" {
+		t.Errorf("in-flight response = %q, want it completed before shutdown", body)
+	}
+}
@@ -0,0 +1,314 @@
+package gnocal
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// realmPropertyName stamps each aggregated VEVENT with the realm it was published by
+const realmPropertyName = "X-GNO-REALM"
+
+// eventTypePropertyName is the type realms classify their events with, e.g. "Lunch and Learn"
+const eventTypePropertyName = "X-GNO-EVENT-TYPE"
+
+// icsComponent is a top-level VCALENDAR component (VEVENT, VTIMEZONE, ...) as unfolded lines
+type icsComponent struct {
+	Name  string
+	Lines []string
+}
+
+// uid returns the component's UID property, or "" if it has none
+func (c icsComponent) uid() string {
+	for _, line := range c.Lines {
+		if name, _, value := splitIcsProperty(line); name == "UID" {
+			return value
+		}
+	}
+	return ""
+}
+
+// splitIcsComponents returns the components nested directly inside VCALENDAR
+func splitIcsComponents(ics string) []icsComponent {
+	var components []icsComponent
+	var current *icsComponent
+	depth := 0
+
+	for _, line := range unfoldIcsLines(ics) {
+		name, _, value := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+			if depth == 2 {
+				current = &icsComponent{Name: strings.ToUpper(value)}
+			}
+		case "END":
+			if depth == 2 && current != nil {
+				current.Lines = append(current.Lines, line)
+				components = append(components, *current)
+				current = nil
+				depth--
+				continue
+			}
+			depth--
+		}
+		if current != nil {
+			current.Lines = append(current.Lines, line)
+		}
+	}
+	return components
+}
+
+// AggregateCalendars merges the calendars of several realms into one, keyed by realm path.
+// Every VEVENT is stamped with an X-GNO-REALM property per source realm; events sharing a
+// UID are emitted once and attributed to each realm that published them.
+func AggregateCalendars(realms []string, calendars map[string]string) string {
+	var events []icsComponent
+	eventIndex := make(map[string]int)
+	var timezones []icsComponent
+	seenTimezones := make(map[string]bool)
+
+	for _, realm := range realms {
+		ics, ok := calendars[realm]
+		if !ok {
+			continue
+		}
+
+		for _, component := range splitIcsComponents(ics) {
+			switch component.Name {
+			case "VTIMEZONE":
+				tzid := ""
+				for _, line := range component.Lines {
+					if name, _, value := splitIcsProperty(line); name == "TZID" {
+						tzid = value
+					}
+				}
+				if !seenTimezones[tzid] {
+					seenTimezones[tzid] = true
+					timezones = append(timezones, component)
+				}
+
+			case "VEVENT":
+				stamp := realmPropertyName + ":" + realm
+				uid := component.uid()
+				if i, dup := eventIndex[uid]; dup && uid != "" {
+					events[i].Lines = insertBeforeEnd(events[i].Lines, stamp)
+					continue
+				}
+				component.Lines = insertBeforeEnd(component.Lines, stamp)
+				if uid != "" {
+					eventIndex[uid] = len(events)
+				}
+				events = append(events, component)
+			}
+		}
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//gnocal//aggregate//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:gnocal aggregate",
+	}
+	for _, component := range append(timezones, events...) {
+		lines = append(lines, component.Lines...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldIcsLine(line))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// rewriteIcsEvents replaces the lines of every top-level VEVENT with those returned by rewrite,
+// dropping events it returns nil for. The result is folded with CRLF line endings.
+func rewriteIcsEvents(ics string, rewrite func(event icsComponent) []string) string {
+	var b strings.Builder
+	var event *icsComponent
+	depth := 0
+
+	for _, line := range unfoldIcsLines(ics) {
+		if line == "" {
+			continue
+		}
+		name, _, value := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+			if depth == 2 && strings.EqualFold(value, "VEVENT") {
+				event = &icsComponent{Name: "VEVENT"}
+			}
+		case "END":
+			depth--
+			if depth == 1 && event != nil {
+				event.Lines = append(event.Lines, line)
+				for _, eventLine := range rewrite(*event) {
+					b.WriteString(foldIcsLine(eventLine))
+					b.WriteString("\r\n")
+				}
+				event = nil
+				continue
+			}
+		}
+
+		if event != nil {
+			event.Lines = append(event.Lines, line)
+			continue
+		}
+		b.WriteString(foldIcsLine(line))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// FilterEventsByType keeps the VEVENTs with an X-GNO-EVENT-TYPE value matching one of types,
+// ignoring case and surrounding spaces, and drops the others. Calendars are returned unchanged
+// when types is empty.
+func FilterEventsByType(ics string, types []string) string {
+	if len(types) == 0 {
+		return ics
+	}
+	return rewriteIcsEvents(ics, func(event icsComponent) []string {
+		depth := 0
+		for _, line := range event.Lines {
+			name, _, value := splitIcsProperty(line)
+			switch name {
+			case "BEGIN":
+				depth++
+			case "END":
+				depth--
+			}
+			if depth != 1 || name != eventTypePropertyName {
+				continue
+			}
+			for _, eventType := range splitIcsTextList(value) {
+				if slices.ContainsFunc(types, func(t string) bool { return strings.EqualFold(t, strings.TrimSpace(eventType)) }) {
+					return event.Lines
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// splitIcsTextList splits a comma-separated list of TEXT values on its unescaped commas and
+// unescapes each value (RFC 5545 section 3.3.11)
+func splitIcsTextList(value string) []string {
+	var values []string
+	var current strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '\\' && i+1 < len(value):
+			i++
+			if next := value[i]; next == 'n' || next == 'N' {
+				current.WriteByte('\n')
+			} else {
+				current.WriteByte(next)
+			}
+		case c == ',':
+			values = append(values, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	return append(values, current.String())
+}
+
+// eventTypes returns the event types requested with ?type=, repeated or comma-separated
+func eventTypes(r *http.Request) []string {
+	var types []string
+	for _, value := range r.URL.Query()["type"] {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
+// insertBeforeEnd adds a property line just before the component's END line
+func insertBeforeEnd(lines []string, property string) []string {
+	last := len(lines) - 1
+	out := append([]string{}, lines[:last]...)
+	out = append(out, property)
+	return append(out, lines[last])
+}
+
+// foldIcsLine folds a content line at 75 octets as required by RFC 5545, without splitting UTF-8 sequences
+func foldIcsLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
+
+// RenderAggregate serves the union of all configured aggregate realms' calendars, restricted
+// to the event categories given with ?type= when present.
+// Realms that fail to render are skipped and listed in the X-Gnocal-Failed-Sources header, and
+// realms gated by feed tokens are left out.
+func (s *Server) RenderAggregate(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	query.Set("format", "ics")
+	// Realms always render every event so the filtered and full feeds share their UIDs
+	query.Del("type")
+
+	calendars := make(map[string]string, len(s.config.AggregateRealms))
+	var failed []string
+	for _, realm := range s.config.AggregateRealms {
+		if s.tokens.Gates(realm) {
+			continue
+		}
+		ics, err := s.fetchCalendar(realm, query.Encode())
+		if err != nil {
+			failed = append(failed, realm)
+			continue
+		}
+		calendars[realm] = ics
+	}
+
+	if len(calendars) == 0 {
+		http.Error(w, "no aggregate source realm could be rendered", http.StatusBadGateway)
+		return
+	}
+	if len(failed) > 0 {
+		w.Header().Set("X-Gnocal-Failed-Sources", strings.Join(failed, ","))
+	}
+
+	aggregate := FilterEventsByType(AggregateCalendars(s.config.AggregateRealms, calendars), eventTypes(r))
+	aggregate = s.limitFeed(w, s.localize(w, r, aggregate))
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", "inline; filename=aggregate.ics")
+	w.Write([]byte(aggregate))
+}
+
+// ParseRealmList splits a comma-separated realm path list, dropping blank entries
+func ParseRealmList(value string) []string {
+	var realms []string
+	for _, realm := range strings.Split(value, ",") {
+		if realm = strings.Trim(strings.TrimSpace(realm), "/"); realm != "" {
+			realms = append(realms, realm)
+		}
+	}
+	return realms
+}
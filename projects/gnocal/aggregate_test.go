@@ -0,0 +1,180 @@
+package gnocal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testCalendar(events ...string) string {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//test//EN"}
+	for _, uid := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+uid,
+			"SUMMARY:Event "+uid,
+			"DTSTART:20250601T090000Z",
+			"BEGIN:VALARM",
+			"ACTION:DISPLAY",
+			"END:VALARM",
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func TestRenderAggregate(t *testing.T) {
+	const (
+		summit  = "gno.land/r/demo/summit"
+		meetups = "gno.land/r/demo/meetups"
+		broken  = "gno.land/r/demo/broken"
+	)
+
+	s := NewGnocalServer(&ServerOptions{
+		GnolandRpcUrl:   "http://127.0.0.1:26657",
+		AggregateRealms: []string{summit, meetups, broken},
+	})
+	s.gnoClient = &fakeRealmClient{realms: map[string]string{
+		summit:  testCalendar("keynote@gno.land", "shared@gno.land"),
+		meetups: testCalendar("meetup@gno.land", "shared@gno.land"),
+	}}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/aggregate", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("X-Gnocal-Failed-Sources"); got != broken {
+		t.Errorf("X-Gnocal-Failed-Sources = %q, want %q", got, broken)
+	}
+
+	events := splitIcsComponents(rec.Body.String())
+	attribution := make(map[string][]string)
+	for _, event := range events {
+		if event.Name != "VEVENT" {
+			continue
+		}
+		uid := event.uid()
+		if _, dup := attribution[uid]; dup {
+			t.Errorf("event %q appears more than once", uid)
+		}
+		attribution[uid] = []string{}
+		for _, line := range event.Lines {
+			if realm, ok := strings.CutPrefix(line, realmPropertyName+":"); ok {
+				attribution[uid] = append(attribution[uid], realm)
+			}
+		}
+	}
+
+	want := map[string][]string{
+		"keynote@gno.land": {summit},
+		"meetup@gno.land":  {meetups},
+		"shared@gno.land":  {summit, meetups},
+	}
+	if len(attribution) != len(want) {
+		t.Fatalf("aggregate has events %v, want %v", attribution, want)
+	}
+	for uid, realms := range want {
+		if strings.Join(attribution[uid], ",") != strings.Join(realms, ",") {
+			t.Errorf("event %q attributed to %v, want %v", uid, attribution[uid], realms)
+		}
+	}
+
+	if !strings.Contains(rec.Body.String(), "BEGIN:VALARM\r\nACTION:DISPLAY\r\nEND:VALARM\r\n"+realmPropertyName) {
+		t.Error("nested components should be kept and the realm stamped before END:VEVENT")
+	}
+}
+
+func TestFoldIcsLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 80)
+	for _, folded := range strings.Split(foldIcsLine(line), "\r\n") {
+		if len(folded) > 75 {
+			t.Errorf("folded line is %d octets, want at most 75", len(folded))
+		}
+	}
+	if unfolded := unfoldIcsLines(foldIcsLine(line)); len(unfolded) != 1 || unfolded[0] != line {
+		t.Errorf("unfolding did not restore the original line")
+	}
+}
+
+func TestRenderAggregateFiltersByType(t *testing.T) {
+	const (
+		updates = "gno.land/r/demo/updates"
+		lunches = "gno.land/r/demo/lunches"
+	)
+	typed := func(events map[string]string) string {
+		lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0"}
+		for uid, eventTypes := range events {
+			lines = append(lines,
+				"BEGIN:VEVENT",
+				"UID:"+uid,
+				"DTSTART:20250601T090000Z",
+				// The event name is not a type, so it must never match
+				"CATEGORIES:Product Updates",
+				eventTypePropertyName+":"+eventTypes,
+				"END:VEVENT",
+			)
+		}
+		return strings.Join(append(lines, "END:VCALENDAR"), "\r\n") + "\r\n"
+	}
+
+	s := NewGnocalServer(&ServerOptions{
+		GnolandRpcUrl:   "http://127.0.0.1:26657",
+		AggregateRealms: []string{updates, lunches},
+	})
+	s.gnoClient = &fakeRealmClient{realms: map[string]string{
+		updates: typed(map[string]string{"release@gno.land": "Product Updates"}),
+		lunches: typed(map[string]string{"lunch@gno.land": "Lunch and Learn,Talk", "demo@gno.land": "Talk", "qa@gno.land": `Questions\, Answers`}),
+	}}
+
+	uids := func(target string) []string {
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d, body = %q", target, rec.Code, rec.Body.String())
+		}
+		var uids []string
+		for _, event := range splitIcsComponents(rec.Body.String()) {
+			if event.Name == "VEVENT" {
+				uids = append(uids, event.uid())
+			}
+		}
+		slices.Sort(uids)
+		return uids
+	}
+
+	tests := map[string][]string{
+		"/aggregate":                      {"demo@gno.land", "lunch@gno.land", "qa@gno.land", "release@gno.land"},
+		"/aggregate?type=lunch+and+learn": {"lunch@gno.land"},
+		"/aggregate?type=Product%20Updates,Lunch%20and%20Learn": {"lunch@gno.land", "release@gno.land"},
+		"/aggregate?type=Talk&type=Product%20Updates":           {"demo@gno.land", "lunch@gno.land", "release@gno.land"},
+		"/aggregate?type=Hackathon":                             nil,
+		"/aggregate?type=Answers":                               nil,
+	}
+	for target, want := range tests {
+		if got := uids(target); !slices.Equal(got, want) {
+			t.Errorf("GET %s events = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestFilterEventsByTypeUnescapesValues(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:qa@gno.land",
+		eventTypePropertyName + `:Questions\, Answers,Talk`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+
+	if got := FilterEventsByType(ics, []string{"questions, answers"}); !strings.Contains(got, "UID:qa@gno.land") {
+		t.Errorf("an escaped comma should stay part of the type, got %q", got)
+	}
+	if got := FilterEventsByType(ics, []string{"Questions"}); strings.Contains(got, "UID:qa@gno.land") {
+		t.Errorf("an escaped comma should not split the type, got %q", got)
+	}
+}
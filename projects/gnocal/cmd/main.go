@@ -4,6 +4,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/allinbits/labs/projects/gnocal"
 )
@@ -11,6 +13,11 @@ import (
 func main() {
 	var gnolandRpcUrl string
 	var gnocalAddress string
+	var tokenStorePath string
+	var revisionStorePath string
+	var aggregateRealms string
+	var maxFeedEvents int
+	var defaultEventDuration time.Duration
 
 	defaultRpc := os.Getenv("GNOCAL__GNOLAND_RPC_URL")
 	if defaultRpc == "" {
@@ -26,6 +33,25 @@ func main() {
 	flag.StringVar(&gnocalAddress, "addr", defaultAddr,
 		"Gnocal HTTP listen address (or set GNOCAL__SERVER_ADDRESS)")
 
+	flag.StringVar(&tokenStorePath, "token-store", os.Getenv("GNOCAL__TOKEN_STORE_PATH"),
+		"JSON file for per-subscriber feed tokens (or set GNOCAL__TOKEN_STORE_PATH)")
+	flag.StringVar(&revisionStorePath, "revision-store", os.Getenv("GNOCAL__REVISION_STORE_PATH"),
+		"JSON file keeping event revisions, which date LAST-MODIFIED, across restarts (or set GNOCAL__REVISION_STORE_PATH)")
+
+	flag.StringVar(&aggregateRealms, "aggregate-realms", os.Getenv("GNOCAL__AGGREGATE_REALMS"),
+		"Comma-separated realm paths combined into the /aggregate feed (or set GNOCAL__AGGREGATE_REALMS)")
+
+	defaultMaxFeedEvents, _ := strconv.Atoi(os.Getenv("GNOCAL__MAX_FEED_EVENTS"))
+	flag.IntVar(&maxFeedEvents, "max-feed-events", defaultMaxFeedEvents,
+		"Maximum events per served calendar, keeping the nearest upcoming ones; 0 disables (or set GNOCAL__MAX_FEED_EVENTS)")
+
+	defaultDuration, err := time.ParseDuration(os.Getenv("GNOCAL__DEFAULT_EVENT_DURATION"))
+	if err != nil {
+		defaultDuration = time.Hour
+	}
+	flag.DurationVar(&defaultEventDuration, "default-event-duration", defaultDuration,
+		"Duration of events rendered without an end, unless the realm sets X-GNOCAL-DEFAULT-DURATION (or set GNOCAL__DEFAULT_EVENT_DURATION)")
+
 	flag.Parse()
 
 	fmt.Println("Using GnoLand RPC URL:", gnolandRpcUrl)
@@ -34,6 +60,16 @@ func main() {
 	config := gnocal.ServerOptions{
 		GnolandRpcUrl: gnolandRpcUrl,
 		GnocalAddress: gnocalAddress,
+
+		AdminToken:     os.Getenv("GNOCAL__ADMIN_TOKEN"),
+		TokenStorePath: tokenStorePath,
+
+		RevisionStorePath: revisionStorePath,
+
+		AggregateRealms: gnocal.ParseRealmList(aggregateRealms),
+		MaxFeedEvents:   maxFeedEvents,
+
+		DefaultEventDuration: defaultEventDuration,
 	}
 
 	server := gnocal.NewGnocalServer(&config)
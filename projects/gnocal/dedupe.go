@@ -0,0 +1,184 @@
+package gnocal
+
+import (
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// eventIdentity holds the properties that decide whether two events describe the same occurrence
+type eventIdentity struct {
+	uid          string
+	summary      string
+	startLine    string
+	start        time.Time
+	rrule        string
+	exdates      []time.Time
+	recurrenceID time.Time
+}
+
+// identifyEvent reads an event's top-level properties. The summary is the untagged SUMMARY,
+// in the realm's default language, compared case-insensitively.
+func identifyEvent(event icsComponent) (eventIdentity, bool) {
+	id := eventIdentity{uid: event.uid()}
+	depth := 0
+	for _, line := range event.Lines {
+		name, params, value := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth != 1 {
+			continue
+		}
+		switch name {
+		case "DTSTART":
+			id.startLine = line
+			id.start, _ = parseIcsDateTime(params, value)
+		case "SUMMARY":
+			if _, tagged := params["LANGUAGE"]; !tagged && id.summary == "" {
+				id.summary = strings.ToLower(strings.TrimSpace(value))
+			}
+		case "RRULE":
+			id.rrule = value
+		case "RECURRENCE-ID":
+			id.recurrenceID, _ = parseIcsDateTime(params, value)
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				if exdate, err := parseIcsDateTime(params, v); err == nil {
+					id.exdates = append(id.exdates, exdate)
+				}
+			}
+		}
+	}
+	return id, !id.start.IsZero()
+}
+
+// recurringSeries is a recurring event with its expanded occurrences and existing overrides
+type recurringSeries struct {
+	eventIdentity
+	occurrences []time.Time
+	overridden  []time.Time
+}
+
+// recurrenceIDLine formats one of the series' occurrences as a RECURRENCE-ID in the same form and
+// time zone as its DTSTART, as RFC 5545 requires
+func (series *recurringSeries) recurrenceIDLine(occurrence time.Time) string {
+	head, value, _ := strings.Cut(series.startLine, ":")
+	params := head[len("DTSTART"):]
+	switch {
+	case len(value) == len("20060102"):
+		return "RECURRENCE-ID" + params + ":" + occurrence.Format("20060102")
+	case strings.HasSuffix(value, "Z"):
+		return "RECURRENCE-ID" + params + ":" + occurrence.UTC().Format("20060102T150405Z")
+	default:
+		return "RECURRENCE-ID" + params + ":" + occurrence.Format("20060102T150405")
+	}
+}
+
+// DedupeRecurringEvents resolves one-off events that coincide with an instance of a recurring
+// series, i.e. start at the same time and have the same summary. The one-off becomes an override
+// of that instance: it takes the series' UID and a RECURRENCE-ID, so clients show it in place of
+// the instance instead of next to it. If the series already overrides the instance, or the one-off
+// repeats the series' own UID and start, the one-off is dropped. Instances the series excludes with
+// EXDATE do not coincide with anything. The returned notes describe each resolved event.
+func DedupeRecurringEvents(ics string) (string, []string) {
+	var series []*recurringSeries
+	var overrides []eventIdentity
+	for _, component := range splitIcsComponents(ics) {
+		if component.Name != "VEVENT" {
+			continue
+		}
+		id, ok := identifyEvent(component)
+		if !ok {
+			continue
+		}
+		switch {
+		case !id.recurrenceID.IsZero():
+			overrides = append(overrides, id)
+		case id.rrule != "":
+			rule, err := ParseRRule(id.rrule)
+			if err != nil {
+				continue
+			}
+			occurrences, _ := rule.Expand(id.start, maxRecurrenceScan, id.exdates)
+			series = append(series, &recurringSeries{eventIdentity: id, occurrences: occurrences})
+		}
+	}
+	if len(series) == 0 {
+		return ics, nil
+	}
+	for _, override := range overrides {
+		for _, s := range series {
+			if s.uid == override.uid {
+				s.overridden = append(s.overridden, override.recurrenceID)
+			}
+		}
+	}
+
+	var notes []string
+	out := rewriteIcsEvents(ics, func(event icsComponent) []string {
+		id, ok := identifyEvent(event)
+		if !ok || id.rrule != "" || !id.recurrenceID.IsZero() {
+			return event.Lines
+		}
+
+		for _, s := range series {
+			i := slices.IndexFunc(s.occurrences, id.start.Equal)
+			if s.summary != id.summary || i < 0 {
+				continue
+			}
+			if id.uid == s.uid || slices.ContainsFunc(s.overridden, id.start.Equal) {
+				notes = append(notes, f("event %q duplicates an instance of series %q at %s and is dropped", id.uid, s.uid, id.start.Format(time.RFC3339)))
+				return nil
+			}
+
+			notes = append(notes, f("event %q coincides with an instance of series %q at %s and overrides it", id.uid, s.uid, id.start.Format(time.RFC3339)))
+			s.overridden = append(s.overridden, id.start)
+			return overrideInstance(event, id.startLine, s.uid, s.recurrenceIDLine(s.occurrences[i]))
+		}
+		return event.Lines
+	})
+	return out, notes
+}
+
+// overrideInstance rewrites a one-off event as the override of a series instance, placing the
+// series' UID and the instance's RECURRENCE-ID after its DTSTART
+func overrideInstance(event icsComponent, startLine, seriesUID, recurrenceIDLine string) []string {
+	lines := make([]string, 0, len(event.Lines)+1)
+	depth := 0
+	for _, line := range event.Lines {
+		name, _, _ := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth == 1 && name == "UID" {
+			continue
+		}
+		lines = append(lines, line)
+		if depth == 1 && line == startLine {
+			lines = append(lines, "UID:"+seriesUID, recurrenceIDLine)
+		}
+	}
+	return lines
+}
+
+// dedupeEvents resolves one-off events duplicating a recurring instance, logging each one.
+// Non-ICS output is returned unchanged.
+func (s *Server) dedupeEvents(calendarPath, ics string) string {
+	if !strings.Contains(ics, "BEGIN:VCALENDAR") {
+		return ics
+	}
+
+	out, notes := DedupeRecurringEvents(ics)
+	for _, note := range notes {
+		log.Printf("%s: %s", calendarPath, note)
+	}
+	return out
+}
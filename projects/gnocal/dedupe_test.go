@@ -0,0 +1,133 @@
+package gnocal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func seriesCalendar(events ...string) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"DTSTART;TZID=Europe/Paris:20250602T090000",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"EXDATE;TZID=Europe/Paris:20250616T090000",
+		"SUMMARY:Standup",
+		"END:VEVENT",
+	}
+	lines = append(lines, events...)
+	lines = append(lines, "END:VCALENDAR")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// eventsByUID returns the VEVENTs with the given UID, the series and its overrides
+func eventsByUID(ics, uid string) []icsComponent {
+	var events []icsComponent
+	for _, component := range splitIcsComponents(ics) {
+		if component.Name == "VEVENT" && component.uid() == uid {
+			events = append(events, component)
+		}
+	}
+	return events
+}
+
+func TestDedupeRecurringEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		oneOff        []string
+		wantKept      bool
+		wantOverrides []string
+	}{
+		{
+			name:          "coinciding one-off overrides the instance",
+			oneOff:        []string{"BEGIN:VEVENT", "UID:special", "DTSTART:20250609T070000Z", "SUMMARY:standup ", "LOCATION:Room 2", "END:VEVENT"},
+			wantOverrides: []string{"RECURRENCE-ID;TZID=Europe/Paris:20250609T090000"},
+		},
+		{
+			name:     "different summary is kept",
+			oneOff:   []string{"BEGIN:VEVENT", "UID:special", "DTSTART;TZID=Europe/Paris:20250609T090000", "SUMMARY:Retro", "END:VEVENT"},
+			wantKept: true,
+		},
+		{
+			name:     "different time is kept",
+			oneOff:   []string{"BEGIN:VEVENT", "UID:special", "DTSTART;TZID=Europe/Paris:20250609T100000", "SUMMARY:Standup", "END:VEVENT"},
+			wantKept: true,
+		},
+		{
+			name:     "excluded instance does not coincide",
+			oneOff:   []string{"BEGIN:VEVENT", "UID:special", "DTSTART;TZID=Europe/Paris:20250616T090000", "SUMMARY:Standup", "END:VEVENT"},
+			wantKept: true,
+		},
+		{
+			name: "instance already overridden drops the one-off",
+			oneOff: []string{
+				"BEGIN:VEVENT", "UID:standup", "RECURRENCE-ID;TZID=Europe/Paris:20250609T090000", "DTSTART;TZID=Europe/Paris:20250609T100000", "SUMMARY:Standup", "END:VEVENT",
+				"BEGIN:VEVENT", "UID:special", "DTSTART;TZID=Europe/Paris:20250609T090000", "SUMMARY:Standup", "END:VEVENT",
+			},
+			wantOverrides: []string{"RECURRENCE-ID;TZID=Europe/Paris:20250609T090000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, notes := DedupeRecurringEvents(seriesCalendar(tt.oneOff...))
+
+			if kept := len(eventsByUID(out, "special")) > 0; kept != tt.wantKept {
+				t.Errorf("one-off kept = %v, want %v", kept, tt.wantKept)
+			}
+			if tt.wantKept != (len(notes) == 0) {
+				t.Errorf("notes = %v", notes)
+			}
+
+			var overrides []string
+			for _, event := range eventsByUID(out, "standup") {
+				for _, line := range event.Lines {
+					if name, _, _ := splitIcsProperty(line); name == "RECURRENCE-ID" {
+						overrides = append(overrides, line)
+					}
+				}
+			}
+			if !slices.Equal(overrides, tt.wantOverrides) {
+				t.Errorf("overrides = %v, want %v", overrides, tt.wantOverrides)
+			}
+		})
+	}
+}
+
+func TestDedupeRecurringEvents_OverrideKeepsDetails(t *testing.T) {
+	out, _ := DedupeRecurringEvents(seriesCalendar(
+		"BEGIN:VEVENT", "UID:special", "DTSTART:20250609T070000Z", "SUMMARY:Standup", "LOCATION:Room 2", "END:VEVENT",
+	))
+
+	events := eventsByUID(out, "standup")
+	if len(events) != 2 {
+		t.Fatalf("got %d events with the series UID, want the series and its override", len(events))
+	}
+	override := events[1].Lines
+	want := []string{"BEGIN:VEVENT", "DTSTART:20250609T070000Z", "UID:standup", "RECURRENCE-ID;TZID=Europe/Paris:20250609T090000", "SUMMARY:Standup", "LOCATION:Room 2", "END:VEVENT"}
+	if !slices.Equal(override, want) {
+		t.Errorf("override = %v, want %v", override, want)
+	}
+}
+
+func TestRenderCalFromRealm_DedupesRecurringEvents(t *testing.T) {
+	s := newTestServer(t)
+	s.gnoClient = &fakeRealmClient{realms: map[string]string{"gno.land/r/demo/events": seriesCalendar(
+		"BEGIN:VEVENT", "UID:special", "DTSTART;TZID=Europe/Paris:20250609T090000", "DTEND;TZID=Europe/Paris:20250609T091500", "SUMMARY:Standup", "END:VEVENT",
+	)}}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/gno.land/r/demo/events", nil))
+
+	if strings.Contains(rec.Body.String(), "UID:special") {
+		t.Error("coinciding one-off should be served as an override of the series")
+	}
+	if got := strings.Count(rec.Body.String(), "RECURRENCE-ID;TZID=Europe/Paris:20250609T090000"); got != 1 {
+		t.Errorf("got %d overrides of the instance, want 1", got)
+	}
+}
@@ -0,0 +1,209 @@
+package gnocal
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultDurationPropertyName lets a realm set the duration of events rendered without an end.
+// A CATEGORY parameter scopes the value to events of that category, e.g.
+// "X-GNOCAL-DEFAULT-DURATION;CATEGORY=Workshop:PT3H".
+const defaultDurationPropertyName = "X-GNOCAL-DEFAULT-DURATION"
+
+// defaultEventDuration is used when neither the server nor the realm configures one
+const defaultEventDuration = time.Hour
+
+var icsDurationPattern = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseIcsDuration parses a positive RFC 5545 DURATION value such as "PT1H30M" or "P1D"
+func parseIcsDuration(value string) (time.Duration, error) {
+	match := icsDurationPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if match == nil || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d += time.Duration(n) * unit
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration %q is not positive", value)
+	}
+	return d, nil
+}
+
+// eventDurations is a calendar's default event durations, overall and by upper-cased category
+type eventDurations struct {
+	calendar   time.Duration
+	categories map[string]time.Duration
+}
+
+// calendarDurations reads the realm's X-GNOCAL-DEFAULT-DURATION properties, using fallback
+// when the calendar does not set an overall default
+func calendarDurations(ics string, fallback time.Duration) (eventDurations, []string) {
+	durations := eventDurations{calendar: fallback, categories: make(map[string]time.Duration)}
+	var warnings []string
+	depth := 0
+	for _, line := range unfoldIcsLines(ics) {
+		name, params, value := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth != 1 || name != defaultDurationPropertyName {
+			continue
+		}
+
+		d, err := parseIcsDuration(value)
+		if err != nil {
+			warnings = append(warnings, f("ignoring %s: %s", defaultDurationPropertyName, err))
+			continue
+		}
+		if category, ok := params["CATEGORY"]; ok {
+			durations.categories[strings.ToUpper(category)] = d
+		} else {
+			durations.calendar = d
+		}
+	}
+	return durations, warnings
+}
+
+// forCategories returns the duration of the first listed category with a default, or the calendar's
+func (d eventDurations) forCategories(categories []string) time.Duration {
+	for _, category := range categories {
+		if duration, ok := d.categories[strings.ToUpper(strings.TrimSpace(category))]; ok {
+			return duration
+		}
+	}
+	return d.calendar
+}
+
+// ApplyDefaultDurations gives every VEVENT without a valid end a DTEND, so clients do not render
+// it as instantaneous. Timed events last the category or calendar default set by the realm,
+// or fallback; all-day events last one day. An explicit DTEND or DURATION that does not end
+// after DTSTART is replaced and reported in the returned warnings.
+func ApplyDefaultDurations(ics string, fallback time.Duration) (string, []string) {
+	durations, warnings := calendarDurations(ics, fallback)
+	out := rewriteIcsEvents(ics, func(event icsComponent) []string {
+		lines, warning := applyEventDuration(event, durations)
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+		return lines
+	})
+	return out, warnings
+}
+
+func applyEventDuration(event icsComponent, durations eventDurations) ([]string, string) {
+	var startLine, endLine, durationLine string
+	var categories []string
+	depth := 0
+	for _, line := range event.Lines {
+		name, _, value := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth != 1 {
+			continue
+		}
+		switch name {
+		case "DTSTART":
+			startLine = line
+		case "DTEND":
+			endLine = line
+		case "DURATION":
+			durationLine = line
+		case "CATEGORIES":
+			categories = append(categories, strings.Split(value, ",")...)
+		}
+	}
+
+	startHead, startValue, _ := strings.Cut(startLine, ":")
+	_, startParams, _ := splitIcsProperty(startLine)
+	start, err := parseIcsDateTime(startParams, startValue)
+	if err != nil {
+		return event.Lines, ""
+	}
+
+	warning := ""
+	switch {
+	case endLine != "":
+		_, params, value := splitIcsProperty(endLine)
+		if end, err := parseIcsDateTime(params, value); err == nil && end.After(start) {
+			return event.Lines, ""
+		}
+		warning = f("event %q: DTEND %s does not end after DTSTART %s, using the default duration", event.uid(), value, startValue)
+	case durationLine != "":
+		_, _, value := splitIcsProperty(durationLine)
+		if _, err := parseIcsDuration(value); err == nil {
+			return event.Lines, ""
+		}
+		warning = f("event %q: DURATION %s is not a positive duration, using the default duration", event.uid(), value)
+	}
+
+	// All-day events end on the following date; timed events keep DTSTART's form and time zone
+	var endValue string
+	switch {
+	case len(startValue) == len("20060102"):
+		endValue = start.AddDate(0, 0, 1).Format("20060102")
+	case strings.HasSuffix(startValue, "Z"):
+		endValue = start.Add(durations.forCategories(categories)).UTC().Format("20060102T150405Z")
+	default:
+		endValue = start.Add(durations.forCategories(categories)).Format("20060102T150405")
+	}
+
+	lines := make([]string, 0, len(event.Lines)+1)
+	depth = 0
+	for _, line := range event.Lines {
+		name, _, _ := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth == 1 && (name == "DTEND" || name == "DURATION") {
+			continue
+		}
+		lines = append(lines, line)
+		if depth == 1 && line == startLine {
+			lines = append(lines, "DTEND"+startHead[len("DTSTART"):]+":"+endValue)
+		}
+	}
+	return lines, warning
+}
+
+// applyDefaultDurations sets the configured default duration on a calendar's events without a
+// valid end, logging the invalid ends it replaced. Non-ICS output is returned unchanged.
+func (s *Server) applyDefaultDurations(calendarPath, ics string) string {
+	if !strings.Contains(ics, "BEGIN:VCALENDAR") {
+		return ics
+	}
+
+	fallback := s.config.DefaultEventDuration
+	if fallback <= 0 {
+		fallback = defaultEventDuration
+	}
+	out, warnings := ApplyDefaultDurations(ics, fallback)
+	for _, warning := range warnings {
+		log.Printf("%s: %s", calendarPath, warning)
+	}
+	return out
+}
@@ -0,0 +1,137 @@
+package gnocal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// eventPropertyLines returns every line of an event's property, or none when it is absent
+func eventPropertyLines(t *testing.T, ics, uid, property string) []string {
+	t.Helper()
+	for _, component := range splitIcsComponents(ics) {
+		if component.uid() != uid {
+			continue
+		}
+		var values []string
+		for _, line := range component.Lines {
+			if name, _, _ := splitIcsProperty(line); name == property {
+				values = append(values, line)
+			}
+		}
+		return values
+	}
+	t.Fatalf("event %q not found", uid)
+	return nil
+}
+
+func TestApplyDefaultDurations(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"X-GNOCAL-DEFAULT-DURATION:PT2H",
+		"X-GNOCAL-DEFAULT-DURATION;CATEGORY=Workshop:PT3H30M",
+		"BEGIN:VEVENT",
+		"UID:missing",
+		"DTSTART:20250601T090000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:explicit",
+		"DTSTART:20250601T090000Z",
+		"DTEND:20250601T093000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:duration",
+		"DTSTART:20250601T090000Z",
+		"DURATION:PT45M",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:inverted",
+		"DTSTART:20250601T090000Z",
+		"DTEND:20250601T080000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:zero-duration",
+		"DTSTART:20250601T090000Z",
+		"DURATION:PT0S",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:workshop",
+		"CATEGORIES:Talk,WORKSHOP",
+		"DTSTART;TZID=Europe/Paris:20250601T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:all-day",
+		"DTSTART;VALUE=DATE:20250601",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+
+	out, warnings := ApplyDefaultDurations(ics, time.Hour)
+
+	tests := []struct {
+		uid          string
+		wantEnd      []string
+		wantDuration []string
+	}{
+		{uid: "missing", wantEnd: []string{"DTEND:20250601T110000Z"}},
+		{uid: "explicit", wantEnd: []string{"DTEND:20250601T093000Z"}},
+		{uid: "duration", wantDuration: []string{"DURATION:PT45M"}},
+		{uid: "inverted", wantEnd: []string{"DTEND:20250601T110000Z"}},
+		{uid: "zero-duration", wantEnd: []string{"DTEND:20250601T110000Z"}},
+		{uid: "workshop", wantEnd: []string{"DTEND;TZID=Europe/Paris:20250601T123000"}},
+		{uid: "all-day", wantEnd: []string{"DTEND;VALUE=DATE:20250602"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uid, func(t *testing.T) {
+			if got := eventPropertyLines(t, out, tt.uid, "DTEND"); strings.Join(got, "|") != strings.Join(tt.wantEnd, "|") {
+				t.Errorf("DTEND = %v, want %v", got, tt.wantEnd)
+			}
+			if got := eventPropertyLines(t, out, tt.uid, "DURATION"); strings.Join(got, "|") != strings.Join(tt.wantDuration, "|") {
+				t.Errorf("DURATION = %v, want %v", got, tt.wantDuration)
+			}
+		})
+	}
+
+	if len(warnings) != 2 {
+		t.Fatalf("expected warnings for the inverted end and zero duration, got %v", warnings)
+	}
+	for i, uid := range []string{"inverted", "zero-duration"} {
+		if !strings.Contains(warnings[i], uid) {
+			t.Errorf("warning %d = %q, want it to name event %q", i, warnings[i], uid)
+		}
+	}
+}
+
+func TestApplyDefaultDurations_ServerFallback(t *testing.T) {
+	out, warnings := ApplyDefaultDurations(datedCalendar("20250601T090000Z"), 90*time.Minute)
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+	if got := eventPropertyLines(t, out, "20250601T090000Z@gno.land", "DTEND"); len(got) != 1 || got[0] != "DTEND:20250601T103000Z" {
+		t.Errorf("DTEND = %v, want the server default of 90 minutes", got)
+	}
+}
+
+func TestParseIcsDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "PT1H30M", want: 90 * time.Minute},
+		{value: "P1DT2H", want: 26 * time.Hour},
+		{value: "P2W", want: 14 * 24 * time.Hour},
+		{value: "PT45S", want: 45 * time.Second},
+		{value: "PT0S", wantErr: true},
+		{value: "P1DT", wantErr: true},
+		{value: "-PT1H", wantErr: true},
+		{value: "1h", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseIcsDuration(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseIcsDuration(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package gnocal
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// EventCalendar returns a calendar holding only the event whose UID is eventID and its sessions,
+// the VEVENTs related to it with RELATED-TO;RELTYPE=PARENT, along with the time zones they may
+// use. The calendar is named after the event's SUMMARY. It returns false if ics has no such event.
+func EventCalendar(ics, eventID string) (string, bool) {
+	var timezones, events []icsComponent
+	name := ""
+	for _, component := range splitIcsComponents(ics) {
+		switch component.Name {
+		case "VTIMEZONE":
+			timezones = append(timezones, component)
+		case "VEVENT":
+			if component.uid() == eventID {
+				if name == "" {
+					name = component.property("SUMMARY")
+				}
+				events = append(events, component)
+			} else if isSessionOf(component, eventID) {
+				events = append(events, component)
+			}
+		}
+	}
+	if !slices.ContainsFunc(events, func(event icsComponent) bool { return event.uid() == eventID }) {
+		return "", false
+	}
+	if name == "" {
+		name = eventID
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//gnocal//event//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + name,
+	}
+	for _, component := range append(timezones, events...) {
+		lines = append(lines, component.Lines...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldIcsLine(line))
+		b.WriteString("\r\n")
+	}
+	return b.String(), true
+}
+
+// isSessionOf reports whether event is a session of the event whose UID is parentUID
+func isSessionOf(event icsComponent, parentUID string) bool {
+	depth := 0
+	for _, line := range event.Lines {
+		name, params, value := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		// RELTYPE defaults to PARENT
+		if depth == 1 && name == "RELATED-TO" && value == parentUID &&
+			(params["RELTYPE"] == "" || strings.EqualFold(params["RELTYPE"], "PARENT")) {
+			return true
+		}
+	}
+	return false
+}
+
+// property returns the value of the component's first top-level property with the given name,
+// ignoring parameters, or "" if it has none
+func (c icsComponent) property(name string) string {
+	depth := 0
+	for _, line := range c.Lines {
+		lineName, params, value := splitIcsProperty(line)
+		switch lineName {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth == 1 && lineName == name && len(params) == 0 {
+			return value
+		}
+	}
+	return ""
+}
+
+// eventRealms returns the realms that may publish the event with the given UID: the realm path
+// the UID is scoped to, as in "event-launch@gno.land/r/demo/events", or else the aggregate realms.
+// Calendars gated by feed tokens are left out.
+func (s *Server) eventRealms(eventID string) []string {
+	realms := s.config.AggregateRealms
+	if _, scope, ok := strings.Cut(eventID, "@"); ok && strings.Contains(scope, "/r/") {
+		realms = []string{scope}
+	}
+	var public []string
+	for _, realm := range realms {
+		if !s.tokens.Gates(realm) {
+			public = append(public, realm)
+		}
+	}
+	return public
+}
+
+// RenderEventFeed serves GET /cal/{event UID}.ics, a calendar of a single event and its
+// sessions for subscribers interested in that event only. Unknown events are rejected with 404.
+func (s *Server) RenderEventFeed(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := strings.CutSuffix(chi.URLParam(r, "*"), ".ics")
+	if !ok || eventID == "" {
+		http.Error(w, "expected /cal/{event id}.ics", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	query.Set("format", "ics")
+	for _, realm := range s.eventRealms(eventID) {
+		icsContent, err := s.fetchCalendar(realm, query.Encode())
+		if err != nil {
+			s.renderRealmError(w, realm, err)
+			return
+		}
+
+		calendar, found := EventCalendar(icsContent, eventID)
+		if !found {
+			continue
+		}
+		calendar = s.limitFeed(w, s.localize(w, r, calendar))
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", "inline; filename=event.ics")
+		w.Write([]byte(calendar))
+		return
+	}
+
+	http.Error(w, "unknown event "+eventID, http.StatusNotFound)
+}
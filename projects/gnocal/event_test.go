@@ -0,0 +1,84 @@
+package gnocal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const eventsRealm = "gno.land/r/demo/events"
+
+// sessionsCalendar publishes two events, the first with two sessions and the second with one
+var sessionsCalendar = strings.Join([]string{
+	"BEGIN:VCALENDAR",
+	"VERSION:2.0",
+	"BEGIN:VEVENT",
+	"UID:event-launch@" + eventsRealm,
+	"DTSTART;VALUE=DATE:20250601",
+	"SUMMARY:Gno.land Launch",
+	"SUMMARY;LANGUAGE=fr:Lancement de Gno.land",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:session-000-launch@" + eventsRealm,
+	"DTSTART:20250601T090000Z",
+	"SUMMARY:Keynote",
+	"RELATED-TO;RELTYPE=PARENT:event-launch@" + eventsRealm,
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:session-001-launch@" + eventsRealm,
+	"DTSTART:20250601T110000Z",
+	"SUMMARY:Workshop",
+	"RELATED-TO:event-launch@" + eventsRealm,
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:event-meetup@" + eventsRealm,
+	"DTSTART;VALUE=DATE:20250701",
+	"SUMMARY:Meetup",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:session-000-meetup@" + eventsRealm,
+	"DTSTART:20250701T180000Z",
+	"SUMMARY:Lightning talks",
+	"RELATED-TO;RELTYPE=PARENT:event-meetup@" + eventsRealm,
+	"END:VEVENT",
+	"END:VCALENDAR",
+}, "\r\n")
+
+func TestRenderEventFeed(t *testing.T) {
+	s := newTestServer(t)
+	s.gnoClient = &fakeRealmClient{realms: map[string]string{eventsRealm: sessionsCalendar}}
+
+	get := func(target, acceptLanguage string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		s.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/cal/event-launch@"+eventsRealm+".ics", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+	var uids []string
+	for _, event := range splitIcsComponents(rec.Body.String()) {
+		uids = append(uids, event.uid())
+	}
+	want := []string{"event-launch@" + eventsRealm, "session-000-launch@" + eventsRealm, "session-001-launch@" + eventsRealm}
+	if strings.Join(uids, ",") != strings.Join(want, ",") {
+		t.Errorf("event feed has events %v, want only the event and its sessions %v", uids, want)
+	}
+	if !strings.Contains(rec.Body.String(), "X-WR-CALNAME:Gno.land Launch\r\n") {
+		t.Errorf("event feed is not named after the event:\n%s", rec.Body.String())
+	}
+
+	// The calendar name does not follow the subscriber's language
+	if localized := get("/cal/event-launch@"+eventsRealm+".ics", "fr"); !strings.Contains(localized.Body.String(), "X-WR-CALNAME:Gno.land Launch\r\n") {
+		t.Errorf("calendar name should be stable across languages:\n%s", localized.Body.String())
+	}
+
+	if rec := get("/cal/event-unknown@"+eventsRealm+".ics", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown event status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,132 @@
+package gnocal
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxRecurrenceScan bounds how many occurrences are expanded to find a recurring event's next date
+const maxRecurrenceScan = 1000
+
+// eventSchedule places an event relative to now for feed truncation
+type eventSchedule struct {
+	index    int
+	upcoming bool
+	// at is the next start for upcoming events and the last start otherwise
+	at time.Time
+}
+
+// scheduleEvent finds when an event next happens. Events that are still running count as
+// upcoming, and events whose dates cannot be parsed are treated as upcoming so they are kept.
+func scheduleEvent(event icsComponent, index int, now time.Time) eventSchedule {
+	var start, end time.Time
+	var rrule string
+	var exdates []time.Time
+	depth := 0
+	for _, line := range event.Lines {
+		name, params, value := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth != 1 {
+			continue
+		}
+		switch name {
+		case "DTSTART":
+			start, _ = parseIcsDateTime(params, value)
+		case "DTEND":
+			end, _ = parseIcsDateTime(params, value)
+		case "RRULE":
+			rrule = value
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				if exdate, err := parseIcsDateTime(params, v); err == nil {
+					exdates = append(exdates, exdate)
+				}
+			}
+		}
+	}
+
+	if start.IsZero() {
+		return eventSchedule{index: index, upcoming: true, at: now}
+	}
+	duration := time.Duration(0)
+	if end.After(start) {
+		duration = end.Sub(start)
+	}
+
+	occurrences := []time.Time{start}
+	if rule, err := ParseRRule(rrule); rrule != "" && err == nil {
+		occurrences, _ = rule.Expand(start, maxRecurrenceScan, exdates)
+	}
+	for _, occurrence := range occurrences {
+		if !occurrence.Add(duration).Before(now) {
+			return eventSchedule{index: index, upcoming: true, at: occurrence}
+		}
+	}
+	if len(occurrences) == 0 {
+		return eventSchedule{index: index, at: start}
+	}
+	return eventSchedule{index: index, at: occurrences[len(occurrences)-1]}
+}
+
+// LimitFeedEvents keeps at most limit events of a calendar, preferring the nearest upcoming
+// events and filling any remaining room with the most recent past ones. Kept events stay in
+// their original order. It returns the calendar and the number of events it had.
+func LimitFeedEvents(ics string, limit int, now time.Time) (string, int) {
+	var schedules []eventSchedule
+	for _, component := range splitIcsComponents(ics) {
+		if component.Name == "VEVENT" {
+			schedules = append(schedules, scheduleEvent(component, len(schedules), now))
+		}
+	}
+	total := len(schedules)
+	if limit <= 0 || total <= limit {
+		return ics, total
+	}
+
+	slices.SortStableFunc(schedules, func(a, b eventSchedule) int {
+		switch {
+		case a.upcoming && !b.upcoming:
+			return -1
+		case !a.upcoming && b.upcoming:
+			return 1
+		case a.upcoming:
+			return a.at.Compare(b.at)
+		default:
+			return b.at.Compare(a.at)
+		}
+	})
+	keep := make(map[int]bool, limit)
+	for _, schedule := range schedules[:limit] {
+		keep[schedule.index] = true
+	}
+
+	index := 0
+	return rewriteIcsEvents(ics, func(event icsComponent) []string {
+		defer func() { index++ }()
+		if !keep[index] {
+			return nil
+		}
+		return event.Lines
+	}), total
+}
+
+// limitFeed applies the configured maximum feed size to an ICS response, reporting the
+// truncation in the X-Gnocal-Truncated header as "kept/total"
+func (s *Server) limitFeed(w http.ResponseWriter, ics string) string {
+	if s.config.MaxFeedEvents <= 0 || !strings.Contains(ics, "BEGIN:VCALENDAR") {
+		return ics
+	}
+
+	limited, total := LimitFeedEvents(ics, s.config.MaxFeedEvents, time.Now())
+	if total > s.config.MaxFeedEvents {
+		w.Header().Set("X-Gnocal-Truncated", f("%d/%d", s.config.MaxFeedEvents, total))
+	}
+	return limited
+}
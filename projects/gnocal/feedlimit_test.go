@@ -0,0 +1,151 @@
+package gnocal
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func datedCalendar(starts ...string) string {
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0"}
+	for _, start := range starts {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+start+"@gno.land",
+			"DTSTART:"+start,
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func eventUIDs(ics string) []string {
+	var uids []string
+	for _, component := range splitIcsComponents(ics) {
+		uids = append(uids, component.uid())
+	}
+	return uids
+}
+
+func TestLimitFeedEvents(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		ics   string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "keeps nearest upcoming in document order",
+			ics:   datedCalendar("20250901T090000Z", "20250101T090000Z", "20250620T090000Z", "20250701T090000Z"),
+			limit: 2,
+			want:  []string{"20250620T090000Z@gno.land", "20250701T090000Z@gno.land"},
+		},
+		{
+			name:  "fills with most recent past events",
+			ics:   datedCalendar("20250101T090000Z", "20250601T090000Z", "20250620T090000Z"),
+			limit: 2,
+			want:  []string{"20250601T090000Z@gno.land", "20250620T090000Z@gno.land"},
+		},
+		{
+			name: "recurring event counts by its next occurrence",
+			ics: strings.Replace(datedCalendar("20240101T090000Z", "20251201T090000Z"),
+				"DTSTART:20240101T090000Z", "DTSTART:20240101T090000Z\r\nRRULE:FREQ=WEEKLY", 1),
+			limit: 1,
+			want:  []string{"20240101T090000Z@gno.land"},
+		},
+		{
+			name:  "under the limit is untouched",
+			ics:   datedCalendar("20250101T090000Z", "20250620T090000Z"),
+			limit: 5,
+			want:  []string{"20250101T090000Z@gno.land", "20250620T090000Z@gno.land"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := LimitFeedEvents(tt.ics, tt.limit, now)
+			if uids := eventUIDs(got); strings.Join(uids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("kept events = %v, want %v", uids, tt.want)
+			}
+			if !strings.HasSuffix(got, "END:VCALENDAR\r\n") {
+				t.Errorf("calendar does not end with END:VCALENDAR: %q", got)
+			}
+		})
+	}
+}
+
+func TestRenderCalendar_TruncatesLargeFeeds(t *testing.T) {
+	upcoming := time.Now().AddDate(0, 1, 0).UTC()
+	var starts []string
+	for i := range 5 {
+		starts = append(starts, upcoming.AddDate(0, 0, i).Format("20060102T150405Z"))
+	}
+
+	s := NewGnocalServer(&ServerOptions{GnolandRpcUrl: "http://127.0.0.1:26657", MaxFeedEvents: 3})
+	s.gnoClient = &fakeRealmClient{realms: map[string]string{
+		"gno.land/r/demo/events": datedCalendar(starts...),
+		"gno.land/r/demo/small":  datedCalendar(starts[:2]...),
+	}}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/gno.land/r/demo/events", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("X-Gnocal-Truncated"); got != "3/5" {
+		t.Errorf("X-Gnocal-Truncated = %q, want 3/5", got)
+	}
+	if n := strings.Count(rec.Body.String(), "BEGIN:VEVENT"); n != 3 {
+		t.Errorf("served %d events, want 3", n)
+	}
+
+	rec = httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/gno.land/r/demo/small", nil))
+	if got := rec.Header().Get("X-Gnocal-Truncated"); got != "" {
+		t.Errorf("X-Gnocal-Truncated = %q for a feed under the limit", got)
+	}
+}
+
+func TestRenderCalendar_Gzip(t *testing.T) {
+	s := newTestServer(t)
+	s.gnoClient = &fakeRealmClient{realms: map[string]string{
+		"gno.land/r/demo/events": datedCalendar("20250601T090000Z", "20250602T090000Z"),
+	}}
+
+	req := httptest.NewRequest(http.MethodGet, "/gno.land/r/demo/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("reading gzip body: %v", err)
+	}
+	if !strings.Contains(string(body), "UID:20250601T090000Z@gno.land") {
+		t.Errorf("decompressed body = %q", body)
+	}
+
+	// Clients that don't ask for gzip get plain ICS
+	rec = httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/gno.land/r/demo/events", nil))
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q without Accept-Encoding", got)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "BEGIN:VCALENDAR") {
+		t.Errorf("body = %q, want plain ICS", rec.Body.String())
+	}
+}
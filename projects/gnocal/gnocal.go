@@ -3,15 +3,20 @@ package gnocal
 // use chi v5 for server routing
 
 import (
+	"crypto/subtle"
 	"embed"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoclient"
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
@@ -34,15 +39,48 @@ var (
 	tmplLandingPage          = mustParseTemplate("landing_page.html")
 )
 
+// realmClient is the subset of gnoclient.Client used to evaluate realm calendars and date their events
+type realmClient interface {
+	QEval(pkgPath string, expression string) (string, *ctypes.ResultABCIQuery, error)
+	Block(height int64) (*ctypes.ResultBlock, error)
+}
+
 type Server struct {
 	router    *chi.Mux
-	gnoClient *gnoclient.Client
+	gnoClient realmClient
+	tokens    *TokenStore
+	revisions *RevisionTracker
 	config    *ServerOptions
 }
 
 type ServerOptions struct {
 	GnolandRpcUrl string
 	GnocalAddress string
+
+	// AdminToken authorizes feed token issuance and revocation.
+	// Token management endpoints are disabled when empty.
+	AdminToken string
+	// TokenStorePath is the JSON file feed tokens are persisted to.
+	// Tokens are kept in memory only when empty.
+	TokenStorePath string
+	// RevisionStorePath is the JSON file event revisions, which date LAST-MODIFIED, are
+	// persisted to. Revisions are kept in memory only when empty.
+	RevisionStorePath string
+	// MaxRevisions caps the event revisions kept, evicting the least recently served ones.
+	// DefaultMaxRevisions is used when zero.
+	MaxRevisions int
+
+	// AggregateRealms are the realm paths combined into the /aggregate feed.
+	// The aggregate feed is disabled when empty.
+	AggregateRealms []string
+
+	// MaxFeedEvents caps the number of events in a served calendar, keeping the
+	// nearest upcoming ones. Feeds are not truncated when zero.
+	MaxFeedEvents int
+
+	// DefaultEventDuration is given to timed events rendered without an end, unless the realm
+	// sets its own default. One hour is used when zero.
+	DefaultEventDuration time.Duration
 }
 
 func NewGnocalServer(config *ServerOptions) *Server {
@@ -51,17 +89,47 @@ func NewGnocalServer(config *ServerOptions) *Server {
 		panic(f("Failed to create Gno client: %s", err.Error()))
 	}
 
+	tokens, err := NewTokenStore(config.TokenStorePath)
+	if err != nil {
+		panic(f("Failed to load feed tokens: %s", err.Error()))
+	}
+
+	revisions, err := NewRevisionTracker(config.RevisionStorePath, config.MaxRevisions)
+	if err != nil {
+		panic(f("Failed to load event revisions: %s", err.Error()))
+	}
+
 	s := &Server{
 		router:    chi.NewRouter(),
 		gnoClient: &gnoclient.Client{RPCClient: gnolandRpcClient},
+		tokens:    tokens,
+		revisions: revisions,
 		config:    config,
 	}
 
 	s.router.Use(middleware.Logger)
 	s.router.Use(middleware.Recoverer)
+	s.router.Use(middleware.Compress(5, "text/calendar", "application/json"))
 
 	s.router.Handle("/static/*", http.FileServerFS(static))
 
+	if config.AdminToken != "" {
+		s.router.Post("/tokens", s.IssueFeedToken)
+		s.router.Delete("/tokens/{token}", s.RevokeFeedToken)
+	}
+	s.router.Get("/feed/{token}", s.RenderCalFromToken)
+	s.router.Get("/cal/*", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(chi.URLParam(r, "*"), ".ics") {
+			s.RenderEventFeed(w, r)
+			return
+		}
+		s.RenderOccurrences(w, r)
+	})
+	if len(config.AggregateRealms) > 0 {
+		s.router.Get("/aggregate", s.RenderAggregate)
+	}
+
+	s.router.Get("/openapi.json", s.RenderOpenAPISpec)
 	s.router.Get("/", s.RenderLandingPage)
 	s.router.Get("/*", s.RenderCalFromRealm)
 
@@ -72,63 +140,173 @@ func (s *Server) Run() error {
 	return http.ListenAndServe(s.config.GnocalAddress, s.router)
 }
 
+// RenderCalFromRealm serves the calendar of the realm at the request path. Calendars with feed
+// tokens are not served publicly and are reported as not found.
 func (s *Server) RenderCalFromRealm(w http.ResponseWriter, r *http.Request) {
 	calendarPath := chi.URLParam(r, "*")
 	if calendarPath == "" {
 		http.Error(w, "missing realm path", http.StatusBadRequest)
 		return
 	}
+	if s.tokens.Gates(calendarPath) {
+		http.Error(w, "calendar not found", http.StatusNotFound)
+		return
+	}
+
+	s.renderCalendar(w, r, calendarPath)
+}
 
-	path := strconv.Quote("?" + r.URL.RawQuery)
-	stringToken, _, err := s.gnoClient.QEval(calendarPath, f(`RenderCalendar(%s)`, path))
+// RenderCalFromToken serves the calendar a feed token grants access to.
+// Unknown and revoked tokens are rejected with 403.
+func (s *Server) RenderCalFromToken(w http.ResponseWriter, r *http.Request) {
+	token, err := s.tokens.Lookup(chi.URLParam(r, "token"))
 	if err != nil {
-		w.Header().Set("Content-Type", "text/html")
-		w.WriteHeader(http.StatusInternalServerError)
-
-		errStr := err.Error()
-		switch {
-		case strings.Contains(errStr, "connect: connection refused"):
-			tmplConnectionRefused.Execute(w, map[string]string{
-				"RpcUrl": s.config.GnolandRpcUrl,
-			})
-		case strings.Contains(errStr, "invalid package path"):
-			tmplInvalidRealmPath.Execute(w, map[string]string{
-				"InputPath": calendarPath,
-			})
-		case strings.Contains(errStr, "name RenderCal not declared"):
-			if _, _, renderErr := s.gnoClient.QEval(calendarPath, `Render("")`); renderErr == nil {
-				tmplRenderCalNotDeclared.Execute(w, map[string]string{
-					"RealmPath": calendarPath,
-				})
-			} else {
-				tmplNoRenderDefined.Execute(w, nil)
-			}
-		default:
-			tmplUnknownRenderError.Execute(w, map[string]string{
-				"InputPath":    calendarPath,
-				"ErrorMessage": errStr,
-			})
-		}
+		http.Error(w, "invalid or revoked feed token", http.StatusForbidden)
 		return
 	}
 
-	var out string
-	if removedLParen, cutPrefix := strings.CutPrefix(stringToken, `("`); cutPrefix {
-		out = removedLParen
+	s.renderCalendar(w, r, token.CalendarPath)
+}
+
+// IssuedFeedToken is the body of POST /tokens
+type IssuedFeedToken struct {
+	Token        string `json:"token"`
+	CalendarPath string `json:"calendar_path"`
+	Subscriber   string `json:"subscriber"`
+	FeedURL      string `json:"feed_url"`
+}
+
+// IssueFeedToken creates a per-subscriber token for the calendar given in the
+// "calendar" query parameter and returns it along with its subscription URL.
+func (s *Server) IssueFeedToken(w http.ResponseWriter, r *http.Request) {
+	if !s.isAdmin(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
 	}
-	if removedRParen, cutSuffix := strings.CutSuffix(out, `" string)`); cutSuffix {
-		out = removedRParen
+
+	calendarPath := strings.Trim(r.URL.Query().Get("calendar"), "/")
+	if calendarPath == "" {
+		http.Error(w, "missing calendar path", http.StatusBadRequest)
+		return
+	}
+
+	token, err := s.tokens.Issue(calendarPath, r.URL.Query().Get("subscriber"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(IssuedFeedToken{
+		Token:        token.Token,
+		CalendarPath: token.CalendarPath,
+		Subscriber:   token.Subscriber,
+		FeedURL:      "webcal://" + r.Host + "/feed/" + token.Token,
+	})
+}
+
+// RevokeFeedToken revokes a single subscriber's token, leaving all others valid.
+func (s *Server) RevokeFeedToken(w http.ResponseWriter, r *http.Request) {
+	if !s.isAdmin(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := s.tokens.Revoke(chi.URLParam(r, "token"))
+	switch {
+	case errors.Is(err, ErrTokenNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) isAdmin(r *http.Request) bool {
+	provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || s.config.AdminToken == "" {
+		return false
 	}
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(s.config.AdminToken)) == 1
+}
 
-	icsContent := strings.ReplaceAll(out, `\n`, "\n")
+func (s *Server) renderCalendar(w http.ResponseWriter, r *http.Request, calendarPath string) {
+	icsContent, err := s.fetchCalendar(calendarPath, r.URL.RawQuery)
+	if err != nil {
+		s.renderRealmError(w, calendarPath, err)
+		return
+	}
 	// REVIEW: is metadata like this allowed
 	//icsContent += "\nURL:" + r.URL.String()
 
+	icsContent = s.localize(w, r, icsContent)
+	icsContent = s.limitFeed(w, icsContent)
+
 	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
 	w.Header().Set("Content-Disposition", "inline; filename=calendar.ics")
 	w.Write([]byte(icsContent))
 }
 
+// fetchCalendar evaluates RenderCalendar on the realm and returns the decoded ICS content
+func (s *Server) fetchCalendar(calendarPath, rawQuery string) (string, error) {
+	path := strconv.Quote("?" + rawQuery)
+	stringToken, res, err := s.gnoClient.QEval(calendarPath, f(`RenderCalendar(%s)`, path))
+	if err != nil {
+		return "", err
+	}
+
+	var out string
+	if removedLParen, cutPrefix := strings.CutPrefix(stringToken, `("`); cutPrefix {
+		out = removedLParen
+	}
+	if removedRParen, cutSuffix := strings.CutSuffix(out, `" string)`); cutSuffix {
+		out = removedRParen
+	}
+
+	// The realm returns a quoted Go string; unquote it so \r\n line endings survive
+	ics, err := strconv.Unquote(`"` + out + `"`)
+	if err != nil {
+		ics = strings.ReplaceAll(out, `\n`, "\n")
+	}
+	ics = s.applyDefaultDurations(calendarPath, ics)
+	ics = s.normalizeRecurrence(calendarPath, ics)
+	ics = s.dedupeEvents(calendarPath, ics)
+	return s.stampRevisions(calendarPath, ics, res), nil
+}
+
+// renderRealmError writes the HTML error page matching a realm evaluation error
+func (s *Server) renderRealmError(w http.ResponseWriter, calendarPath string, err error) {
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusInternalServerError)
+
+	errStr := err.Error()
+	switch {
+	case strings.Contains(errStr, "connect: connection refused"):
+		tmplConnectionRefused.Execute(w, map[string]string{
+			"RpcUrl": s.config.GnolandRpcUrl,
+		})
+	case strings.Contains(errStr, "invalid package path"):
+		tmplInvalidRealmPath.Execute(w, map[string]string{
+			"InputPath": calendarPath,
+		})
+	case strings.Contains(errStr, "name RenderCal not declared"):
+		if _, _, renderErr := s.gnoClient.QEval(calendarPath, `Render("")`); renderErr == nil {
+			tmplRenderCalNotDeclared.Execute(w, map[string]string{
+				"RealmPath": calendarPath,
+			})
+		} else {
+			tmplNoRenderDefined.Execute(w, nil)
+		}
+	default:
+		tmplUnknownRenderError.Execute(w, map[string]string{
+			"InputPath":    calendarPath,
+			"ErrorMessage": errStr,
+		})
+	}
+}
+
 func (s *Server) RenderLandingPage(w http.ResponseWriter, r *http.Request) {
 	if query := r.URL.Query().Get("q"); query != "" {
 		if strings.HasPrefix(query, "/r/") {
@@ -0,0 +1,245 @@
+package gnocal
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+)
+
+// blockHeightPropertyName records the first block height gnocal observed an event's current
+// content at. This is not necessarily the block the realm changed it at.
+const blockHeightPropertyName = "X-GNO-LAST-MODIFIED-HEIGHT"
+
+// DefaultMaxRevisions bounds the event revisions a RevisionTracker keeps when no limit is set
+const DefaultMaxRevisions = 10000
+
+// eventRevision is the chain position at which an event's current content was first seen
+type eventRevision struct {
+	Hash   string    `json:"hash"`
+	Height int64     `json:"height"`
+	Time   time.Time `json:"time"`
+
+	// Seen orders revisions by when they were last served, for eviction
+	Seen uint64 `json:"seen"`
+}
+
+// RevisionTracker remembers when gnocal first observed each event's current content. Realms do not
+// expose per-event update heights, so a change is dated to the first block gnocal served it at,
+// which is later than the block it was made at when the calendar was not fetched in between.
+// The least recently served revisions are evicted past maxRevisions. When created with a path,
+// revisions are persisted to that file as JSON so they survive restarts.
+type RevisionTracker struct {
+	mu           sync.Mutex
+	path         string
+	maxRevisions int
+	revisions    map[string]eventRevision
+	seen         uint64
+	dirty        bool
+
+	// the last looked up block, as consecutive requests usually share a height
+	lastHeight int64
+	lastTime   time.Time
+}
+
+// NewRevisionTracker returns a revision tracker keeping up to maxRevisions revisions, backed by
+// the JSON file at path. An empty path keeps revisions in memory only, and a maxRevisions of
+// zero uses DefaultMaxRevisions.
+func NewRevisionTracker(path string, maxRevisions int) (*RevisionTracker, error) {
+	if maxRevisions <= 0 {
+		maxRevisions = DefaultMaxRevisions
+	}
+	t := &RevisionTracker{
+		path:         path,
+		maxRevisions: maxRevisions,
+		revisions:    make(map[string]eventRevision),
+	}
+	if path == "" {
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revision store: %w", err)
+	}
+	if err := json.Unmarshal(data, &t.revisions); err != nil {
+		return nil, fmt.Errorf("failed to parse revision store: %w", err)
+	}
+	for _, rev := range t.revisions {
+		t.seen = max(t.seen, rev.Seen)
+	}
+	t.evict()
+	return t, nil
+}
+
+// observe returns the revision of an event, recording a new one at height when its content changed
+func (t *RevisionTracker) observe(key, hash string, height int64, blockTime time.Time) eventRevision {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.seen++
+	if rev, ok := t.revisions[key]; ok && rev.Hash == hash {
+		rev.Seen = t.seen
+		t.revisions[key] = rev
+		return rev
+	}
+	rev := eventRevision{Hash: hash, Height: height, Time: blockTime, Seen: t.seen}
+	t.revisions[key] = rev
+	t.dirty = true
+	t.evict()
+	return rev
+}
+
+// evict drops the least recently served revisions past maxRevisions. Callers must hold the lock.
+func (t *RevisionTracker) evict() {
+	excess := len(t.revisions) - t.maxRevisions
+	if excess <= 0 {
+		return
+	}
+
+	keys := slices.Collect(maps.Keys(t.revisions))
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Compare(t.revisions[a].Seen, t.revisions[b].Seen)
+	})
+	for _, key := range keys[:excess] {
+		delete(t.revisions, key)
+	}
+	t.dirty = true
+}
+
+// flush writes the revisions to disk when new ones were recorded since the last write
+func (t *RevisionTracker) flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.dirty || t.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(t.revisions)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision store: %w", err)
+	}
+
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write revision store: %w", err)
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return fmt.Errorf("failed to write revision store: %w", err)
+	}
+	t.dirty = false
+	return nil
+}
+
+// blockTime returns the time of the block at height, looking it up with client when not cached
+func (t *RevisionTracker) blockTime(client realmClient, height int64) (time.Time, error) {
+	t.mu.Lock()
+	cached, cachedTime := t.lastHeight == height, t.lastTime
+	t.mu.Unlock()
+	if cached {
+		return cachedTime, nil
+	}
+
+	block, err := client.Block(height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	blockTime := block.Block.Header.Time
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if height > t.lastHeight {
+		t.lastHeight, t.lastTime = height, blockTime
+	}
+	return blockTime, nil
+}
+
+// stampRevisions sets LAST-MODIFIED and DTSTAMP on every event of a calendar fetched by res.
+// The output is returned unchanged when it is not ICS or the query height or block time is unavailable.
+func (s *Server) stampRevisions(calendarPath, ics string, res *ctypes.ResultABCIQuery) string {
+	if res == nil || res.Response.Height <= 0 || !strings.Contains(ics, "BEGIN:VCALENDAR") {
+		return ics
+	}
+	height := res.Response.Height
+	blockTime, err := s.revisions.blockTime(s.gnoClient, height)
+	if err != nil {
+		return ics
+	}
+	stamped := StampLastModified(ics, func(event icsComponent, hash string) eventRevision {
+		return s.revisions.observe(calendarPath+"|"+revisionKey(event, hash), hash, height, blockTime)
+	})
+
+	// A failed write only costs the revisions recorded since the last one on restart
+	if err := s.revisions.flush(); err != nil {
+		log.Printf("%s: %s", calendarPath, err)
+	}
+	return stamped
+}
+
+// revisionKey identifies an event across fetches by its UID, or by its content hash when it has none.
+// Overrides of a recurring event share the series' UID, so their RECURRENCE-ID is part of the key.
+func revisionKey(event icsComponent, hash string) string {
+	uid := event.uid()
+	if uid == "" {
+		return hash
+	}
+	for _, line := range event.Lines {
+		if name, _, _ := splitIcsProperty(line); name == "RECURRENCE-ID" {
+			return uid + "|" + line
+		}
+	}
+	return uid
+}
+
+// StampLastModified rewrites each VEVENT with LAST-MODIFIED and DTSTAMP set to the UTC time of
+// the revision returned by revisionOf, replacing any values the realm rendered. revisionOf is
+// given a hash of the event's content that ignores those generated properties.
+func StampLastModified(ics string, revisionOf func(event icsComponent, hash string) eventRevision) string {
+	return rewriteIcsEvents(ics, func(event icsComponent) []string {
+		return stampEvent(event, revisionOf)
+	})
+}
+
+func stampEvent(event icsComponent, revisionOf func(event icsComponent, hash string) eventRevision) []string {
+	var lines []string
+	digest := sha256.New()
+	depth := 0
+	for _, line := range event.Lines {
+		name, _, _ := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		// Only the event's own generated properties are replaced, not those of nested VALARMs
+		if depth == 1 && (name == "DTSTAMP" || name == "LAST-MODIFIED" || name == blockHeightPropertyName) {
+			continue
+		}
+		lines = append(lines, line)
+		digest.Write([]byte(line + "\n"))
+	}
+	event.Lines = lines
+
+	rev := revisionOf(event, hex.EncodeToString(digest.Sum(nil)))
+	stamp := rev.Time.UTC().Format("20060102T150405Z")
+	lines = insertBeforeEnd(lines, "DTSTAMP:"+stamp)
+	lines = insertBeforeEnd(lines, "LAST-MODIFIED:"+stamp)
+	return insertBeforeEnd(lines, blockHeightPropertyName+":"+strconv.FormatInt(rev.Height, 10))
+}
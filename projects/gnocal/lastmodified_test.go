@@ -0,0 +1,235 @@
+package gnocal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// chainRealmClient serves a calendar as of a mutable chain height, with one block per minute
+type chainRealmClient struct {
+	calendar string
+	height   int64
+}
+
+var chainGenesis = time.Date(2025, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+func (c *chainRealmClient) QEval(pkgPath string, expression string) (string, *ctypes.ResultABCIQuery, error) {
+	res := &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Height: c.height}}
+	return "(" + strconv.Quote(c.calendar) + " string)", res, nil
+}
+
+func (c *chainRealmClient) Block(height int64) (*ctypes.ResultBlock, error) {
+	header := types.Header{Height: height, Time: chainGenesis.Add(time.Duration(height) * time.Minute)}
+	return &ctypes.ResultBlock{Block: &types.Block{Header: header}}, nil
+}
+
+func eventProperty(t *testing.T, ics, uid, property string) string {
+	t.Helper()
+
+	for _, event := range splitIcsComponents(ics) {
+		if event.uid() != uid {
+			continue
+		}
+		for _, line := range event.Lines {
+			if name, _, value := splitIcsProperty(line); name == property {
+				return value
+			}
+		}
+		t.Fatalf("event %s has no %s in %q", uid, property, ics)
+	}
+	t.Fatalf("event %s not found in %q", uid, ics)
+	return ""
+}
+
+func TestLastModifiedTracksChainUpdates(t *testing.T) {
+	s := newTestServer(t)
+	client := &chainRealmClient{height: 100, calendar: strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:keynote@gno.land",
+		"DTSTAMP:20990101T000000Z",
+		"SUMMARY:Keynote",
+		"DTSTART:20250601T090000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:workshop@gno.land",
+		"SUMMARY:Workshop",
+		"DTSTART:20250601T140000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")}
+	s.gnoClient = client
+
+	fetch := func() string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/gno.land/r/demo/events", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %q", rec.Code, rec.Body.String())
+		}
+		return rec.Body.String()
+	}
+
+	// Block 100 is 13:40 CEST, emitted in UTC
+	ics := fetch()
+	if got := eventProperty(t, ics, "keynote@gno.land", "LAST-MODIFIED"); got != "20250601T114000Z" {
+		t.Errorf("LAST-MODIFIED = %s, want 20250601T114000Z", got)
+	}
+	if got := eventProperty(t, ics, "keynote@gno.land", "DTSTAMP"); got != "20250601T114000Z" {
+		t.Errorf("DTSTAMP = %s, want the UTC update time replacing the realm's", got)
+	}
+	if got := eventProperty(t, ics, "keynote@gno.land", blockHeightPropertyName); got != "100" {
+		t.Errorf("%s = %s, want 100", blockHeightPropertyName, got)
+	}
+	if n := strings.Count(ics, "DTSTAMP:"); n != 2 {
+		t.Errorf("calendar has %d DTSTAMP lines, want one per event", n)
+	}
+
+	// Unchanged events keep their revision as the chain advances
+	client.height = 110
+	ics = fetch()
+	if got := eventProperty(t, ics, "keynote@gno.land", "LAST-MODIFIED"); got != "20250601T114000Z" {
+		t.Errorf("unchanged LAST-MODIFIED = %s, want 20250601T114000Z", got)
+	}
+
+	// Editing one event only moves its own LAST-MODIFIED
+	client.height = 130
+	client.calendar = strings.Replace(client.calendar, "SUMMARY:Keynote", "SUMMARY:Opening keynote", 1)
+	ics = fetch()
+	if got := eventProperty(t, ics, "keynote@gno.land", "LAST-MODIFIED"); got != "20250601T121000Z" {
+		t.Errorf("updated LAST-MODIFIED = %s, want 20250601T121000Z", got)
+	}
+	if got := eventProperty(t, ics, "keynote@gno.land", blockHeightPropertyName); got != "130" {
+		t.Errorf("updated %s = %s, want 130", blockHeightPropertyName, got)
+	}
+	if got := eventProperty(t, ics, "workshop@gno.land", "LAST-MODIFIED"); got != "20250601T114000Z" {
+		t.Errorf("untouched event LAST-MODIFIED = %s, want 20250601T114000Z", got)
+	}
+}
+
+func TestRevisionTrackerEvictsLeastRecentlyServed(t *testing.T) {
+	tracker, err := NewRevisionTracker("", 2)
+	if err != nil {
+		t.Fatalf("NewRevisionTracker() error = %v", err)
+	}
+	at := chainGenesis
+
+	tracker.observe("a", "a1", 1, at)
+	tracker.observe("b", "b1", 2, at)
+	tracker.observe("a", "a1", 3, at) // a is served again, so b is now the least recent
+	tracker.observe("c", "c1", 4, at)
+
+	if len(tracker.revisions) != 2 {
+		t.Fatalf("tracker keeps %d revisions, want 2", len(tracker.revisions))
+	}
+	if _, ok := tracker.revisions["b"]; ok {
+		t.Error("least recently served revision was not evicted")
+	}
+	if rev := tracker.observe("a", "a1", 5, at); rev.Height != 1 {
+		t.Errorf("kept revision height = %d, want 1", rev.Height)
+	}
+}
+
+func TestRevisionsSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "revisions.json")
+	client := &chainRealmClient{height: 100, calendar: strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:keynote@gno.land",
+		"SUMMARY:Keynote",
+		"DTSTART:20250601T090000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")}
+
+	fetch := func() string {
+		t.Helper()
+		s := NewGnocalServer(&ServerOptions{GnolandRpcUrl: "http://127.0.0.1:26657", RevisionStorePath: path})
+		s.gnoClient = client
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/gno.land/r/demo/events", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %q", rec.Code, rec.Body.String())
+		}
+		return rec.Body.String()
+	}
+
+	fetch()
+
+	// A restarted server keeps dating the unchanged event to the block it was first served at
+	client.height = 150
+	if got := eventProperty(t, fetch(), "keynote@gno.land", blockHeightPropertyName); got != "100" {
+		t.Errorf("%s after restart = %s, want 100", blockHeightPropertyName, got)
+	}
+}
+
+func TestLastModifiedTracksRecurrenceOverridesSeparately(t *testing.T) {
+	s := newTestServer(t)
+	client := &chainRealmClient{height: 100, calendar: strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:standup@gno.land",
+		"SUMMARY:Standup",
+		"DTSTART:20250602T090000Z",
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@gno.land",
+		"RECURRENCE-ID:20250609T090000Z",
+		"SUMMARY:Standup (moved)",
+		"DTSTART:20250609T110000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")}
+	s.gnoClient = client
+
+	// heights returns the revision height of the series and of its override
+	heights := func() []string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/gno.land/r/demo/events", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %q", rec.Code, rec.Body.String())
+		}
+		var heights []string
+		for _, event := range splitIcsComponents(rec.Body.String()) {
+			if event.Name == "VEVENT" {
+				heights = append(heights, event.property(blockHeightPropertyName))
+			}
+		}
+		return heights
+	}
+
+	if got := heights(); !slices.Equal(got, []string{"100", "100"}) {
+		t.Fatalf("revision heights = %v, want [100 100]", got)
+	}
+
+	// The series and its override share a UID but must not overwrite each other's revision
+	client.height = 110
+	if got := heights(); !slices.Equal(got, []string{"100", "100"}) {
+		t.Errorf("unchanged revision heights = %v, want [100 100]", got)
+	}
+	if n := len(s.revisions.revisions); n != 2 {
+		t.Errorf("tracker keeps %d revisions, want one for the series and one for the override", n)
+	}
+
+	// Editing the override leaves the series' revision alone
+	client.height = 120
+	client.calendar = strings.Replace(client.calendar, "Standup (moved)", "Standup (moved again)", 1)
+	if got := heights(); !slices.Equal(got, []string{"100", "120"}) {
+		t.Errorf("revision heights after editing the override = %v, want [100 120]", got)
+	}
+}
@@ -0,0 +1,153 @@
+package gnocal
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// localizedProperties are the event properties a realm may publish once per language, e.g.
+// "SUMMARY;LANGUAGE=fr:Atelier". The untagged value is in the realm's default language.
+var localizedProperties = []string{"SUMMARY", "DESCRIPTION"}
+
+// preferredLanguages returns the languages requested with ?lang=, followed by those of the
+// Accept-Language header in decreasing order of preference
+func preferredLanguages(r *http.Request) []string {
+	var languages []string
+	for _, lang := range strings.Split(r.URL.Query().Get("lang"), ",") {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			languages = append(languages, lang)
+		}
+	}
+
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var accepted []weighted
+	for _, entry := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if lang == "" || lang == "*" || q <= 0 {
+			continue
+		}
+		accepted = append(accepted, weighted{lang: lang, q: q})
+	}
+	slices.SortStableFunc(accepted, func(a, b weighted) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		default:
+			return 0
+		}
+	})
+	for _, a := range accepted {
+		languages = append(languages, a.lang)
+	}
+	return languages
+}
+
+// selectLocalizedLine picks the line best matching the preferred languages: an exact language tag
+// match, then a match on the primary subtag ("fr-CA" and "fr"), then the untagged default,
+// then the first line
+func selectLocalizedLine(lines []string, languages []string) string {
+	tagOf := func(line string) string {
+		_, params, _ := splitIcsProperty(line)
+		return strings.ToLower(params["LANGUAGE"])
+	}
+	primary := func(tag string) string {
+		p, _, _ := strings.Cut(tag, "-")
+		return p
+	}
+
+	for _, lang := range languages {
+		lang = strings.ToLower(lang)
+		if i := slices.IndexFunc(lines, func(line string) bool { return tagOf(line) == lang }); i >= 0 {
+			return lines[i]
+		}
+		if i := slices.IndexFunc(lines, func(line string) bool {
+			tag := tagOf(line)
+			return tag != "" && primary(tag) == primary(lang)
+		}); i >= 0 {
+			return lines[i]
+		}
+	}
+	if i := slices.IndexFunc(lines, func(line string) bool { return tagOf(line) == "" }); i >= 0 {
+		return lines[i]
+	}
+	return lines[0]
+}
+
+// LocalizeEvents keeps a single SUMMARY and DESCRIPTION per VEVENT, in the first of the preferred
+// languages the realm published, falling back to the realm's default language. RFC 5545 allows
+// each property once per event, so events are collapsed even when no language is requested.
+func LocalizeEvents(ics string, languages []string) string {
+	return rewriteIcsEvents(ics, func(event icsComponent) []string {
+		variants := make(map[string][]string)
+		depth := 0
+		for _, line := range event.Lines {
+			name, _, _ := splitIcsProperty(line)
+			switch name {
+			case "BEGIN":
+				depth++
+			case "END":
+				depth--
+			}
+			if depth == 1 && slices.Contains(localizedProperties, name) {
+				variants[name] = append(variants[name], line)
+			}
+		}
+
+		selected := make(map[string]string, len(variants))
+		for name, lines := range variants {
+			if len(lines) > 1 {
+				selected[name] = selectLocalizedLine(lines, languages)
+			}
+		}
+		if len(selected) == 0 {
+			return event.Lines
+		}
+
+		lines := make([]string, 0, len(event.Lines))
+		emitted := make(map[string]bool, len(selected))
+		depth = 0
+		for _, line := range event.Lines {
+			name, _, _ := splitIcsProperty(line)
+			switch name {
+			case "BEGIN":
+				depth++
+			case "END":
+				depth--
+			}
+			if keep, localized := selected[name]; depth == 1 && localized {
+				// Emit the selected line once even if the realm repeated it
+				if line != keep || emitted[name] {
+					continue
+				}
+				emitted[name] = true
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	})
+}
+
+// localize emits a calendar's events in the languages preferred by the request.
+// Non-ICS output is returned unchanged.
+func (s *Server) localize(w http.ResponseWriter, r *http.Request, ics string) string {
+	if !strings.Contains(ics, "BEGIN:VCALENDAR") {
+		return ics
+	}
+	w.Header().Add("Vary", "Accept-Language")
+	return LocalizeEvents(ics, preferredLanguages(r))
+}
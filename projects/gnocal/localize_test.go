@@ -0,0 +1,106 @@
+package gnocal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func localizedCalendar() string {
+	return strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:workshop",
+		"DTSTART:20250601T090000Z",
+		"SUMMARY:Workshop",
+		"SUMMARY;LANGUAGE=fr:Atelier",
+		"SUMMARY;LANGUAGE=es:Taller",
+		"DESCRIPTION:Build a realm",
+		"DESCRIPTION;LANGUAGE=fr:Construire un realm",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:untranslated",
+		"DTSTART:20250602T090000Z",
+		"SUMMARY:Office hours",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+}
+
+func TestLocalizeEvents(t *testing.T) {
+	tests := []struct {
+		name            string
+		languages       []string
+		wantSummary     string
+		wantDescription string
+	}{
+		{name: "requested language", languages: []string{"fr"}, wantSummary: "SUMMARY;LANGUAGE=fr:Atelier", wantDescription: "DESCRIPTION;LANGUAGE=fr:Construire un realm"},
+		{name: "regional variant matches primary language", languages: []string{"fr-CA"}, wantSummary: "SUMMARY;LANGUAGE=fr:Atelier", wantDescription: "DESCRIPTION;LANGUAGE=fr:Construire un realm"},
+		{name: "missing translation falls back per property", languages: []string{"es"}, wantSummary: "SUMMARY;LANGUAGE=es:Taller", wantDescription: "DESCRIPTION:Build a realm"},
+		{name: "unavailable language falls back to default", languages: []string{"de"}, wantSummary: "SUMMARY:Workshop", wantDescription: "DESCRIPTION:Build a realm"},
+		{name: "next preference is used", languages: []string{"de", "es"}, wantSummary: "SUMMARY;LANGUAGE=es:Taller", wantDescription: "DESCRIPTION:Build a realm"},
+		{name: "no preference uses default", wantSummary: "SUMMARY:Workshop", wantDescription: "DESCRIPTION:Build a realm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := LocalizeEvents(localizedCalendar(), tt.languages)
+
+			if got := eventPropertyLines(t, out, "workshop", "SUMMARY"); !slices.Equal(got, []string{tt.wantSummary}) {
+				t.Errorf("SUMMARY = %v, want [%s]", got, tt.wantSummary)
+			}
+			if got := eventPropertyLines(t, out, "workshop", "DESCRIPTION"); !slices.Equal(got, []string{tt.wantDescription}) {
+				t.Errorf("DESCRIPTION = %v, want [%s]", got, tt.wantDescription)
+			}
+			if got := eventPropertyLines(t, out, "untranslated", "SUMMARY"); !slices.Equal(got, []string{"SUMMARY:Office hours"}) {
+				t.Errorf("untranslated SUMMARY = %v", got)
+			}
+		})
+	}
+}
+
+func TestPreferredLanguages(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/gno.land/r/demo/events?lang=pt", nil)
+	r.Header.Set("Accept-Language", "de;q=0.5, fr-CH, *;q=0.1, en;q=0.8, it;q=0")
+
+	want := []string{"pt", "fr-CH", "en", "de"}
+	if got := preferredLanguages(r); !slices.Equal(got, want) {
+		t.Errorf("preferredLanguages() = %v, want %v", got, want)
+	}
+}
+
+func TestRenderCalFromRealm_Localized(t *testing.T) {
+	s := newTestServer(t)
+	s.gnoClient = &fakeRealmClient{realms: map[string]string{"gno.land/r/demo/events": localizedCalendar()}}
+
+	tests := []struct {
+		name           string
+		target         string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "lang parameter", target: "/gno.land/r/demo/events?lang=es", acceptLanguage: "fr", want: "SUMMARY;LANGUAGE=es:Taller"},
+		{name: "accept-language header", target: "/gno.land/r/demo/events", acceptLanguage: "fr-FR,en;q=0.5", want: "SUMMARY;LANGUAGE=fr:Atelier"},
+		{name: "fallback", target: "/gno.land/r/demo/events?lang=ja", want: "SUMMARY:Workshop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			s.router.ServeHTTP(rec, req)
+
+			if got := eventPropertyLines(t, rec.Body.String(), "workshop", "SUMMARY"); !slices.Equal(got, []string{tt.want}) {
+				t.Errorf("SUMMARY = %v, want [%s]", got, tt.want)
+			}
+			if vary := rec.Header().Get("Vary"); !strings.Contains(vary, "Accept-Language") {
+				t.Errorf("Vary = %q, want it to include Accept-Language", vary)
+			}
+		})
+	}
+}
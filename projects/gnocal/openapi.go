@@ -0,0 +1,174 @@
+package gnocal
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// jsonSchema describes a Go type as serialized by encoding/json. Struct properties come from
+// the json tags, and fields without omitempty are required since they are always emitted.
+// Slices and maps are nullable as encoding/json writes nil ones as null.
+func jsonSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem()), "nullable": true}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchema(t.Elem()), "nullable": true}
+	case reflect.Struct:
+		properties := make(map[string]any)
+		required := []string{}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = jsonSchema(field.Type)
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]any{"type": "object", "properties": properties, "required": required}
+	default:
+		return map[string]any{}
+	}
+}
+
+// OpenAPISpec documents gnocal's HTTP API. JSON response schemas are generated from the
+// structs the handlers encode, so the contract cannot drift from the served bodies.
+func OpenAPISpec(config *ServerOptions) map[string]any {
+	calendarResponse := map[string]any{
+		"description": "The realm calendar as iCalendar",
+		"content":     map[string]any{"text/calendar": map[string]any{"schema": map[string]any{"type": "string"}}},
+	}
+	jsonResponse := func(description string, schema string) map[string]any {
+		return map[string]any{
+			"description": description,
+			"content": map[string]any{"application/json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/" + schema},
+			}},
+		}
+	}
+	realmParam := map[string]any{
+		"name": "realm", "in": "path", "required": true,
+		"description": "Realm path, e.g. gno.land/r/demo/events. Other query parameters are passed to the realm's RenderCalendar.",
+		"schema":      map[string]any{"type": "string"},
+	}
+
+	langParam := map[string]any{
+		"name": "lang", "in": "query",
+		"description": "Preferred languages for localized SUMMARY and DESCRIPTION, before those of Accept-Language",
+		"schema":      map[string]any{"type": "string"},
+	}
+	typeParam := map[string]any{
+		"name": "type", "in": "query",
+		"description": "Only include events with one of these comma-separated X-GNO-EVENT-TYPE values, e.g. \"Lunch and Learn\"",
+		"schema":      map[string]any{"type": "string"},
+	}
+
+	paths := map[string]any{
+		"/{realm}": map[string]any{"get": map[string]any{
+			"summary":    "Render a realm calendar",
+			"parameters": []any{realmParam, langParam},
+			"responses":  map[string]any{"200": calendarResponse, "404": map[string]any{"description": "Calendar only served through feed tokens"}},
+		}},
+		"/feed/{token}": map[string]any{"get": map[string]any{
+			"summary": "Render the calendar a feed token grants access to",
+			"parameters": []any{map[string]any{
+				"name": "token", "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			}, langParam},
+			"responses": map[string]any{"200": calendarResponse, "403": map[string]any{"description": "Unknown or revoked token"}},
+		}},
+		"/cal/{realm}/occurrences": map[string]any{"get": map[string]any{
+			"summary": "Preview the occurrences of a realm's recurring events",
+			"parameters": []any{realmParam, langParam, map[string]any{
+				"name": "count", "in": "query",
+				"schema": map[string]any{"type": "integer", "minimum": 1, "maximum": maxOccurrenceCount, "default": defaultOccurrenceCount},
+			}},
+			"responses": map[string]any{"200": jsonResponse("Occurrences of each recurring event", "OccurrencesResponse")},
+		}},
+		"/cal/{eventID}.ics": map[string]any{"get": map[string]any{
+			"summary": "Render the calendar of a single event and its sessions",
+			"parameters": []any{map[string]any{
+				"name": "eventID", "in": "path", "required": true,
+				"description": "UID of the event, e.g. event-launch@gno.land/r/demo/events",
+				"schema":      map[string]any{"type": "string"},
+			}, langParam},
+			"responses": map[string]any{"200": calendarResponse, "404": map[string]any{"description": "Unknown event"}},
+		}},
+	}
+	if len(config.AggregateRealms) > 0 {
+		paths["/aggregate"] = map[string]any{"get": map[string]any{
+			"summary":    "Render the union of the configured aggregate realms' calendars",
+			"parameters": []any{langParam, typeParam},
+			"responses":  map[string]any{"200": calendarResponse},
+		}}
+	}
+	if config.AdminToken != "" {
+		paths["/tokens"] = map[string]any{"post": map[string]any{
+			"summary":  "Issue a per-subscriber feed token",
+			"security": []any{map[string]any{"adminToken": []any{}}},
+			"parameters": []any{
+				map[string]any{"name": "calendar", "in": "query", "required": true, "schema": map[string]any{"type": "string"}},
+				map[string]any{"name": "subscriber", "in": "query", "schema": map[string]any{"type": "string"}},
+			},
+			"responses": map[string]any{"201": jsonResponse("The issued token", "IssuedFeedToken")},
+		}}
+		paths["/tokens/{token}"] = map[string]any{"delete": map[string]any{
+			"summary":  "Revoke a feed token",
+			"security": []any{map[string]any{"adminToken": []any{}}},
+			"parameters": []any{map[string]any{
+				"name": "token", "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			}},
+			"responses": map[string]any{"204": map[string]any{"description": "Token revoked"}},
+		}}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": "gnocal", "version": "1"},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"OccurrencesResponse": jsonSchema(reflect.TypeOf(OccurrencesResponse{})),
+				"IssuedFeedToken":     jsonSchema(reflect.TypeOf(IssuedFeedToken{})),
+			},
+			"securitySchemes": map[string]any{
+				"adminToken": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+// RenderOpenAPISpec serves the OpenAPI description of the enabled endpoints
+func (s *Server) RenderOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(OpenAPISpec(s.config))
+}
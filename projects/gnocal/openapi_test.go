@@ -0,0 +1,132 @@
+package gnocal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// schemaMatches checks that a decoded JSON value has exactly the properties its schema documents
+// and carries every required one, recursing into nested objects and arrays
+func schemaMatches(t *testing.T, path string, schema map[string]any, value any) {
+	t.Helper()
+	if value == nil && schema["nullable"] == true {
+		return
+	}
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			t.Errorf("%s: expected an object, got %T", path, value)
+			return
+		}
+		properties := schema["properties"].(map[string]any)
+		for name := range object {
+			if _, documented := properties[name]; !documented {
+				t.Errorf("%s: serialized field %q is not documented", path, name)
+			}
+		}
+		for _, name := range schema["required"].([]string) {
+			if _, present := object[name]; !present {
+				t.Errorf("%s: required field %q is not serialized", path, name)
+			}
+		}
+		for name, property := range properties {
+			if v, present := object[name]; present {
+				schemaMatches(t, path+"."+name, property.(map[string]any), v)
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			t.Errorf("%s: expected an array, got %T", path, value)
+			return
+		}
+		for _, item := range items {
+			schemaMatches(t, path+"[]", schema["items"].(map[string]any), item)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			t.Errorf("%s: expected a string, got %T", path, value)
+		}
+	}
+}
+
+func TestJSONSchema_MatchesSerialization(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{
+			name: "occurrences with every field set",
+			value: OccurrencesResponse{Realm: "gno.land/r/demo/events", Events: []EventOccurrences{{
+				UID: "uid-1", Summary: "Standup", RRule: "FREQ=DAILY",
+				Occurrences: []time.Time{time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)},
+				Warnings:    []string{"skipped"},
+			}}},
+		},
+		{
+			name:  "occurrences with optional fields empty",
+			value: OccurrencesResponse{Realm: "gno.land/r/demo/events", Events: []EventOccurrences{{UID: "uid-1", RRule: "FREQ=DAILY"}}},
+		},
+		{
+			name:  "issued feed token",
+			value: IssuedFeedToken{Token: "abc", CalendarPath: "gno.land/r/demo/events", FeedURL: "webcal://host/feed/abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var decoded any
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			schemaMatches(t, reflect.TypeOf(tt.value).Name(), jsonSchema(reflect.TypeOf(tt.value)), decoded)
+		})
+	}
+}
+
+func TestRenderOpenAPISpec(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var spec struct {
+		OpenAPI    string                    `json:"openapi"`
+		Paths      map[string]any            `json:"paths"`
+		Components map[string]map[string]any `json:"components"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("spec is not JSON: %v", err)
+	}
+	if spec.OpenAPI == "" {
+		t.Error("spec should declare its OpenAPI version")
+	}
+
+	var paths []string
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	want := []string{"/cal/{eventID}.ics", "/cal/{realm}/occurrences", "/feed/{token}", "/tokens", "/tokens/{token}", "/{realm}"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+	for _, schema := range []string{"OccurrencesResponse", "IssuedFeedToken"} {
+		if _, ok := spec.Components["schemas"][schema]; !ok {
+			t.Errorf("schema %s is missing", schema)
+		}
+	}
+}
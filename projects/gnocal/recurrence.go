@@ -0,0 +1,519 @@
+package gnocal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultOccurrenceCount = 10
+	maxOccurrenceCount     = 100
+
+	// maxEmptyPeriods stops expansion of rules that can never produce another occurrence
+	maxEmptyPeriods = 1000
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// RecurrenceRule is the subset of RFC 5545 RRULE supported by the occurrence preview
+type RecurrenceRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+
+	// until is the UNTIL value as written, whose form decides how it is read against DTSTART
+	until string
+	// other are the parts not interpreted, kept as written when the rule is formatted
+	other []string
+}
+
+// WeekdayNum is a BYDAY value: a weekday, or with FREQ=MONTHLY its Nth occurrence in the month
+// when N is set, counting from the end of the month when N is negative ("1MO", "-1FR")
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// ParseRRule parses an RRULE value such as "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=12"
+func ParseRRule(value string) (*RecurrenceRule, error) {
+	rule := &RecurrenceRule{Interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseIcsTime(val, time.UTC)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", val)
+			}
+			rule.Until = until
+			rule.until = val
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekdayNum, err := parseWeekdayNum(day)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, weekdayNum)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		default:
+			rule.other = append(rule.other, part)
+		}
+	}
+
+	switch rule.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, errors.New("RRULE is missing FREQ")
+	default:
+		return nil, fmt.Errorf("unsupported FREQ %q", rule.Freq)
+	}
+	if rule.Count > 0 && rule.until != "" {
+		return nil, errors.New("RRULE cannot have both COUNT and UNTIL")
+	}
+	if rule.Freq != "MONTHLY" && slices.ContainsFunc(rule.ByDay, func(day WeekdayNum) bool { return day.N != 0 }) {
+		return nil, fmt.Errorf("BYDAY ordinals are only supported with FREQ=MONTHLY, not %s", rule.Freq)
+	}
+
+	return rule, nil
+}
+
+// parseWeekdayNum parses a BYDAY value such as "WE", "1MO" or "-1FR"
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("unsupported BYDAY %q", value)
+	}
+	weekday, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("unsupported BYDAY %q", value)
+	}
+	day := WeekdayNum{Weekday: weekday}
+	if ordinal := value[:len(value)-2]; ordinal != "" {
+		n, err := strconv.Atoi(ordinal)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+		}
+		day.N = n
+	}
+	return day, nil
+}
+
+// untilFor returns the last instant the rule may recur at for a series starting at dtstart.
+// A DATE UNTIL includes that whole day and a floating one is read in dtstart's location.
+func (rule *RecurrenceRule) untilFor(dtstart time.Time) time.Time {
+	switch {
+	case rule.until == "" || strings.HasSuffix(rule.until, "Z"):
+		return rule.Until
+	case len(rule.until) == len("20060102"):
+		y, m, d := rule.Until.Date()
+		return time.Date(y, m, d, 23, 59, 59, 0, dtstart.Location())
+	default:
+		until, _ := parseIcsTime(rule.until, dtstart.Location())
+		return until
+	}
+}
+
+// Expand computes up to n occurrences starting at dtstart, skipping excluded instants.
+// Occurrences keep dtstart's wall-clock time in its location, so weekly rules stay at the
+// same local time across DST changes. The returned warnings flag dates the rule skips
+// (e.g. the 31st in short months) and local times that do not exist or are ambiguous.
+func (rule *RecurrenceRule) Expand(dtstart time.Time, n int, exdates []time.Time) ([]time.Time, []string) {
+	var occurrences []time.Time
+	var warnings []string
+	warned := make(map[string]bool)
+	warn := func(msg string) {
+		if !warned[msg] {
+			warned[msg] = true
+			warnings = append(warnings, msg)
+		}
+	}
+
+	until := rule.untilFor(dtstart)
+	emitted := 0
+	empty := 0
+	for period := 0; len(occurrences) < n; period++ {
+		candidates := rule.periodDates(dtstart, period, warn)
+		if len(candidates) == 0 {
+			empty++
+			if empty > maxEmptyPeriods {
+				warn("rule produces no further occurrences")
+				break
+			}
+			continue
+		}
+		empty = 0
+
+		for _, date := range candidates {
+			occurrence := wallClock(date, dtstart, warn)
+			if occurrence.Before(dtstart) {
+				continue
+			}
+			if !until.IsZero() && occurrence.After(until) {
+				return occurrences, warnings
+			}
+			if rule.Count > 0 && emitted >= rule.Count {
+				return occurrences, warnings
+			}
+
+			emitted++
+			if slices.ContainsFunc(exdates, occurrence.Equal) {
+				continue
+			}
+			occurrences = append(occurrences, occurrence)
+			if len(occurrences) == n {
+				break
+			}
+		}
+	}
+
+	return occurrences, warnings
+}
+
+// periodDates returns the candidate dates (at midnight UTC) of the given period, in order
+func (rule *RecurrenceRule) periodDates(dtstart time.Time, period int, warn func(string)) []time.Time {
+	y, m, d := dtstart.Date()
+	step := period * rule.Interval
+
+	switch rule.Freq {
+	case "DAILY":
+		return []time.Time{time.Date(y, m, d+step, 0, 0, 0, 0, time.UTC)}
+
+	case "WEEKLY":
+		days := rule.ByDay
+		if len(days) == 0 {
+			days = []WeekdayNum{{Weekday: dtstart.Weekday()}}
+		}
+		// Weeks start on Monday (RFC 5545 default WKST)
+		weekStart := d - (int(dtstart.Weekday())+6)%7 + 7*step
+		var dates []time.Time
+		for _, day := range days {
+			dates = append(dates, time.Date(y, m, weekStart+(int(day.Weekday)+6)%7, 0, 0, 0, 0, time.UTC))
+		}
+		slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+		return dates
+
+	case "MONTHLY":
+		first := time.Date(y, m+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if len(rule.ByDay) > 0 {
+			return rule.monthWeekdays(first)
+		}
+		monthDays := rule.ByMonthDay
+		if len(monthDays) == 0 {
+			monthDays = []int{d}
+		}
+		var dates []time.Time
+		for _, day := range monthDays {
+			if date, ok := monthDay(first, day); ok {
+				dates = append(dates, date)
+			} else {
+				warn(f("day %d does not exist in %s %d and is skipped", day, first.Month(), first.Year()))
+			}
+		}
+		slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+		return dates
+
+	case "YEARLY":
+		date := time.Date(y+step, m, d, 0, 0, 0, 0, time.UTC)
+		if date.Month() != m {
+			warn(f("%s %d does not exist in %d and is skipped", m, d, y+step))
+			return nil
+		}
+		return []time.Time{date}
+	}
+
+	return nil
+}
+
+// monthWeekdays returns the dates of first's month matching BYDAY, restricted to BYMONTHDAY
+// when both are set
+func (rule *RecurrenceRule) monthWeekdays(first time.Time) []time.Time {
+	var dates []time.Time
+	for _, day := range rule.ByDay {
+		var matching []time.Time
+		for date := first.AddDate(0, 0, (int(day.Weekday)-int(first.Weekday())+7)%7); date.Month() == first.Month(); date = date.AddDate(0, 0, 7) {
+			matching = append(matching, date)
+		}
+		switch {
+		case day.N > 0 && day.N <= len(matching):
+			dates = append(dates, matching[day.N-1])
+		case day.N < 0 && -day.N <= len(matching):
+			dates = append(dates, matching[len(matching)+day.N])
+		case day.N == 0:
+			dates = append(dates, matching...)
+		}
+	}
+
+	if len(rule.ByMonthDay) > 0 {
+		dates = slices.DeleteFunc(dates, func(date time.Time) bool {
+			return !slices.ContainsFunc(rule.ByMonthDay, func(day int) bool {
+				resolved, ok := monthDay(first, day)
+				return ok && resolved.Equal(date)
+			})
+		})
+	}
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(dates, time.Time.Equal)
+}
+
+// monthDay resolves a BYMONTHDAY value (negative counts from the end) within first's month
+func monthDay(first time.Time, day int) (time.Time, bool) {
+	daysInMonth := time.Date(first.Year(), first.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day < 0 {
+		day = daysInMonth + day + 1
+	}
+	if day < 1 || day > daysInMonth {
+		return time.Time{}, false
+	}
+	return first.AddDate(0, 0, day-1), true
+}
+
+// wallClock places date at dtstart's local time of day, flagging DST gaps and overlaps
+func wallClock(date, dtstart time.Time, warn func(string)) time.Time {
+	loc := dtstart.Location()
+	hour, minute, sec := dtstart.Clock()
+	t := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, sec, 0, loc)
+
+	if h, m, _ := t.Clock(); h != hour || m != minute {
+		warn(f("%s %02d:%02d does not exist in %s (DST gap), occurs at %s",
+			date.Format(time.DateOnly), hour, minute, loc, t.Format("15:04 MST")))
+		return t
+	}
+
+	for _, shift := range []time.Duration{-time.Hour, time.Hour} {
+		other := t.Add(shift)
+		if oh, om, _ := other.Clock(); oh == hour && om == minute && other.YearDay() == t.YearDay() {
+			warn(f("%s %02d:%02d is ambiguous in %s (DST overlap)",
+				date.Format(time.DateOnly), hour, minute, loc))
+			break
+		}
+	}
+	return t
+}
+
+// RecurringEvent is a VEVENT carrying an RRULE
+type RecurringEvent struct {
+	UID     string
+	Summary string
+	DTStart time.Time
+	RRule   string
+	ExDates []time.Time
+}
+
+// ParseRecurringEvents extracts the recurring VEVENTs from ICS content
+func ParseRecurringEvents(ics string) ([]RecurringEvent, error) {
+	var events []RecurringEvent
+	var current *RecurringEvent
+	var dtstartErr error
+
+	for _, line := range unfoldIcsLines(ics) {
+		name, params, value := splitIcsProperty(line)
+
+		switch {
+		case line == "BEGIN:VEVENT":
+			current = &RecurringEvent{}
+			dtstartErr = nil
+		case line == "END:VEVENT":
+			if current != nil && current.RRule != "" {
+				if dtstartErr != nil {
+					return nil, fmt.Errorf("event %s: invalid DTSTART: %w", current.UID, dtstartErr)
+				}
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = value
+		case name == "RRULE":
+			current.RRule = value
+		case name == "DTSTART":
+			current.DTStart, dtstartErr = parseIcsDateTime(params, value)
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				if exdate, err := parseIcsDateTime(params, v); err == nil {
+					current.ExDates = append(current.ExDates, exdate)
+				}
+			}
+		}
+	}
+
+	return events, nil
+}
+
+func unfoldIcsLines(ics string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(ics, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+// splitIcsProperty splits "NAME;PARAM=X:VALUE" into its name, parameters and value
+func splitIcsProperty(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(k)] = v
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func parseIcsDateTime(params map[string]string, value string) (time.Time, error) {
+	loc := time.UTC
+	if tzid, ok := params["TZID"]; ok {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown TZID %q", tzid)
+		}
+		loc = l
+	}
+	return parseIcsTime(value, loc)
+}
+
+// parseIcsTime parses DATE, floating DATE-TIME (in loc) and UTC DATE-TIME values
+func parseIcsTime(value string, loc *time.Location) (time.Time, error) {
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse("20060102T150405Z", value)
+	case len(value) == len("20060102"):
+		return time.ParseInLocation("20060102", value, loc)
+	default:
+		return time.ParseInLocation("20060102T150405", value, loc)
+	}
+}
+
+// EventOccurrences is the occurrence preview of a single recurring event
+type EventOccurrences struct {
+	UID         string      `json:"uid"`
+	Summary     string      `json:"summary,omitempty"`
+	RRule       string      `json:"rrule"`
+	Occurrences []time.Time `json:"occurrences"`
+	Warnings    []string    `json:"warnings,omitempty"`
+}
+
+// OccurrencesResponse is the body of GET /cal/{realm path}/occurrences
+type OccurrencesResponse struct {
+	Realm  string             `json:"realm"`
+	Events []EventOccurrences `json:"events"`
+}
+
+// PreviewOccurrences expands every recurring event in ics to its next count occurrences
+func PreviewOccurrences(ics string, count int) ([]EventOccurrences, error) {
+	events, err := ParseRecurringEvents(ics)
+	if err != nil {
+		return nil, err
+	}
+
+	previews := []EventOccurrences{}
+	for _, event := range events {
+		preview := EventOccurrences{UID: event.UID, Summary: event.Summary, RRule: event.RRule}
+
+		rule, err := ParseRRule(event.RRule)
+		if err != nil {
+			preview.Warnings = []string{err.Error()}
+		} else {
+			preview.Occurrences, preview.Warnings = rule.Expand(event.DTStart, count, event.ExDates)
+		}
+
+		previews = append(previews, preview)
+	}
+	return previews, nil
+}
+
+// RenderOccurrences serves GET /cal/{realm path}/occurrences?count=N, returning the
+// computed dates of every recurring event in the realm's calendar for verification
+func (s *Server) RenderOccurrences(w http.ResponseWriter, r *http.Request) {
+	calendarPath, ok := strings.CutSuffix(strings.Trim(chi.URLParam(r, "*"), "/"), "/occurrences")
+	if !ok || calendarPath == "" {
+		http.Error(w, "expected /cal/{realm path}/occurrences", http.StatusNotFound)
+		return
+	}
+	if s.tokens.Gates(calendarPath) {
+		http.Error(w, "calendar not found", http.StatusNotFound)
+		return
+	}
+
+	count := defaultOccurrenceCount
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxOccurrenceCount {
+			http.Error(w, f("count must be between 1 and %d", maxOccurrenceCount), http.StatusBadRequest)
+			return
+		}
+		count = n
+	}
+
+	query := r.URL.Query()
+	query.Del("count")
+	query.Set("format", "ics")
+	icsContent, err := s.fetchCalendar(calendarPath, query.Encode())
+	if err != nil {
+		s.renderRealmError(w, calendarPath, err)
+		return
+	}
+
+	previews, err := PreviewOccurrences(LocalizeEvents(icsContent, preferredLanguages(r)), count)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(OccurrencesResponse{Realm: calendarPath, Events: previews})
+}
@@ -0,0 +1,194 @@
+package gnocal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpand_MonthlyOn31stSkipsShortMonths(t *testing.T) {
+	rule, err := ParseRRule("FREQ=MONTHLY;BYMONTHDAY=31")
+	if err != nil {
+		t.Fatalf("ParseRRule() error = %v", err)
+	}
+
+	dtstart := time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC)
+	occurrences, warnings := rule.Expand(dtstart, 4, nil)
+
+	want := []time.Time{
+		time.Date(2025, 1, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 5, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, 7, 31, 18, 0, 0, 0, time.UTC),
+	}
+	if len(occurrences) != len(want) {
+		t.Fatalf("Expand() = %v, want %v", occurrences, want)
+	}
+	for i := range want {
+		if !occurrences[i].Equal(want[i]) {
+			t.Errorf("occurrence %d = %s, want %s", i, occurrences[i], want[i])
+		}
+	}
+
+	for _, month := range []string{"February", "April", "June"} {
+		if !slicesContainsSubstring(warnings, month) {
+			t.Errorf("expected a warning for skipped %s, got %v", month, warnings)
+		}
+	}
+}
+
+func TestExpand_MonthlyLastDay(t *testing.T) {
+	rule, err := ParseRRule("FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3")
+	if err != nil {
+		t.Fatalf("ParseRRule() error = %v", err)
+	}
+
+	occurrences, warnings := rule.Expand(time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC), 10, nil)
+	if len(occurrences) != 3 {
+		t.Fatalf("Expand() returned %d occurrences, want COUNT=3", len(occurrences))
+	}
+	if got := occurrences[1].Format(time.DateOnly); got != "2024-02-29" {
+		t.Errorf("second occurrence = %s, want leap day", got)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+}
+
+func TestExpand_WeeklyAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	rule, err := ParseRRule("FREQ=WEEKLY;BYDAY=TH")
+	if err != nil {
+		t.Fatalf("ParseRRule() error = %v", err)
+	}
+
+	// DST starts on Sunday 2025-03-09
+	dtstart := time.Date(2025, 3, 6, 10, 0, 0, 0, newYork)
+	occurrences, warnings := rule.Expand(dtstart, 2, nil)
+	if len(occurrences) != 2 {
+		t.Fatalf("Expand() returned %d occurrences, want 2", len(occurrences))
+	}
+
+	for _, occurrence := range occurrences {
+		if h, m, _ := occurrence.Clock(); h != 10 || m != 0 {
+			t.Errorf("occurrence %s is not at 10:00 local time", occurrence)
+		}
+	}
+	if got := occurrences[1].Sub(occurrences[0]); got != 7*24*time.Hour-time.Hour {
+		t.Errorf("gap across DST = %s, want 167h", got)
+	}
+	if got := occurrences[1].UTC().Hour(); got != 14 {
+		t.Errorf("second occurrence UTC hour = %d, want 14", got)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+}
+
+func TestExpand_FlagsDSTGapAndOverlap(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	rule, err := ParseRRule("FREQ=WEEKLY;BYDAY=SU")
+	if err != nil {
+		t.Fatalf("ParseRRule() error = %v", err)
+	}
+
+	_, warnings := rule.Expand(time.Date(2025, 3, 2, 2, 30, 0, 0, newYork), 2, nil)
+	if !slicesContainsSubstring(warnings, "DST gap") {
+		t.Errorf("expected a DST gap warning, got %v", warnings)
+	}
+
+	_, warnings = rule.Expand(time.Date(2025, 10, 26, 1, 30, 0, 0, newYork), 2, nil)
+	if !slicesContainsSubstring(warnings, "DST overlap") {
+		t.Errorf("expected a DST overlap warning, got %v", warnings)
+	}
+}
+
+func TestExpand_ExdateAndUntil(t *testing.T) {
+	rule, err := ParseRRule("FREQ=DAILY;UNTIL=20250105T090000Z")
+	if err != nil {
+		t.Fatalf("ParseRRule() error = %v", err)
+	}
+
+	dtstart := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	exdate := time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC)
+	occurrences, _ := rule.Expand(dtstart, 10, []time.Time{exdate})
+
+	if len(occurrences) != 4 {
+		t.Fatalf("Expand() = %v, want 4 occurrences (UNTIL inclusive, one EXDATE)", occurrences)
+	}
+	for _, occurrence := range occurrences {
+		if occurrence.Equal(exdate) {
+			t.Errorf("EXDATE %s was not excluded", exdate)
+		}
+	}
+}
+
+func TestRenderOccurrences(t *testing.T) {
+	s := newTestServer(t)
+	s.gnoClient = &fakeRealmClient{calendar: strings.Join([]string{
+		`BEGIN:VCALENDAR`,
+		`BEGIN:VEVENT`,
+		`UID:meetup@gno.land`,
+		`SUMMARY:Monthly meetup`,
+		`DTSTART:20250131T180000Z`,
+		`RRULE:FREQ=MONTHLY`,
+		`END:VEVENT`,
+		`BEGIN:VEVENT`,
+		`UID:once@gno.land`,
+		`DTSTART:20250131T180000Z`,
+		`END:VEVENT`,
+		`END:VCALENDAR`,
+	}, `\r\n`)}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cal/gno.land/r/demo/events/occurrences?count=3", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Realm  string             `json:"realm"`
+		Events []EventOccurrences `json:"events"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Realm != "gno.land/r/demo/events" {
+		t.Errorf("realm = %q", resp.Realm)
+	}
+	if len(resp.Events) != 1 {
+		t.Fatalf("expected only the recurring event, got %d", len(resp.Events))
+	}
+	if got := len(resp.Events[0].Occurrences); got != 3 {
+		t.Errorf("occurrences = %d, want 3", got)
+	}
+	if len(resp.Events[0].Warnings) == 0 {
+		t.Error("expected warnings for months without a 31st")
+	}
+
+	rec = httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cal/gno.land/r/demo/events/occurrences?count=1000", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("count above limit: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func slicesContainsSubstring(values []string, substr string) bool {
+	for _, v := range values {
+		if strings.Contains(v, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package gnocal
+
+import (
+	"log"
+	"strings"
+	"time"
+)
+
+// icsTimeForm is the value type of a DTSTART, which its RRULE UNTIL and EXDATE values must follow
+type icsTimeForm int
+
+const (
+	formDate     icsTimeForm = iota // DATE, e.g. 20250131
+	formUTC                         // UTC DATE-TIME, e.g. 20250131T180000Z
+	formZoned                       // DATE-TIME with a TZID parameter
+	formFloating                    // DATE-TIME in the attendee's local time
+)
+
+func icsTimeFormOf(params map[string]string, value string) icsTimeForm {
+	switch {
+	case len(value) == len("20060102"):
+		return formDate
+	case strings.HasSuffix(value, "Z"):
+		return formUTC
+	case params["TZID"] != "":
+		return formZoned
+	default:
+		return formFloating
+	}
+}
+
+// format returns the rule as an RRULE value for a series starting at dtstart, with FREQ first
+// and UNTIL in the form RFC 5545 requires for dtstart's: a DATE for all-day series, floating
+// for floating series and UTC otherwise.
+func (rule *RecurrenceRule) format(dtstart time.Time, form icsTimeForm) string {
+	parts := []string{"FREQ=" + rule.Freq}
+	if rule.Interval > 1 {
+		parts = append(parts, f("INTERVAL=%d", rule.Interval))
+	}
+	if rule.Count > 0 {
+		parts = append(parts, f("COUNT=%d", rule.Count))
+	}
+	if until := rule.untilFor(dtstart); !until.IsZero() {
+		switch form {
+		case formDate:
+			parts = append(parts, "UNTIL="+until.In(dtstart.Location()).Format("20060102"))
+		case formFloating:
+			parts = append(parts, "UNTIL="+until.In(dtstart.Location()).Format("20060102T150405"))
+		default:
+			parts = append(parts, "UNTIL="+until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	if len(rule.ByDay) > 0 {
+		days := make([]string, len(rule.ByDay))
+		for i, day := range rule.ByDay {
+			days[i] = strings.ToUpper(day.Weekday.String()[:2])
+			if day.N != 0 {
+				days[i] = f("%d", day.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(rule.ByMonthDay) > 0 {
+		monthDays := make([]string, len(rule.ByMonthDay))
+		for i, day := range rule.ByMonthDay {
+			monthDays[i] = f("%d", day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(monthDays, ","))
+	}
+	parts = append(parts, rule.other...)
+	return strings.Join(parts, ";")
+}
+
+// formatExdate returns an EXDATE value in dtstart's form. A DATE excludes the occurrence on that
+// day, at dtstart's time of day.
+func formatExdate(exdate time.Time, exdateValue string, dtstart time.Time, form icsTimeForm) string {
+	if form != formDate && len(exdateValue) == len("20060102") {
+		hour, minute, sec := dtstart.Clock()
+		exdate = time.Date(exdate.Year(), exdate.Month(), exdate.Day(), hour, minute, sec, 0, dtstart.Location())
+	}
+	switch form {
+	case formDate:
+		return exdate.In(dtstart.Location()).Format("20060102")
+	case formUTC:
+		return exdate.UTC().Format("20060102T150405Z")
+	default:
+		return exdate.In(dtstart.Location()).Format("20060102T150405")
+	}
+}
+
+// NormalizeRecurrence rewrites the RRULE and EXDATE properties of every recurring VEVENT into
+// the form calendar clients expand natively: the RRULE with its UNTIL following DTSTART's value
+// type, and the EXDATEs as a single property in DTSTART's value type and time zone.
+// Events with an invalid RRULE are left unchanged and reported in the returned warnings, as are
+// EXDATE values that cannot be read, which are dropped.
+func NormalizeRecurrence(ics string) (string, []string) {
+	var warnings []string
+	out := rewriteIcsEvents(ics, func(event icsComponent) []string {
+		lines, eventWarnings := normalizeEventRecurrence(event)
+		warnings = append(warnings, eventWarnings...)
+		return lines
+	})
+	return out, warnings
+}
+
+func normalizeEventRecurrence(event icsComponent) ([]string, []string) {
+	var startLine, ruleLine string
+	var exdateLines []string
+	depth := 0
+	for _, line := range event.Lines {
+		name, _, _ := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth != 1 {
+			continue
+		}
+		switch name {
+		case "DTSTART":
+			startLine = line
+		case "RRULE":
+			if ruleLine == "" {
+				ruleLine = line
+			}
+		case "EXDATE":
+			exdateLines = append(exdateLines, line)
+		}
+	}
+	if ruleLine == "" || startLine == "" {
+		return event.Lines, nil
+	}
+
+	_, startParams, startValue := splitIcsProperty(startLine)
+	dtstart, err := parseIcsDateTime(startParams, startValue)
+	if err != nil {
+		return event.Lines, []string{f("event %q: invalid DTSTART %s, leaving its recurrence unchanged", event.uid(), startValue)}
+	}
+	_, _, ruleValue := splitIcsProperty(ruleLine)
+	rule, err := ParseRRule(ruleValue)
+	if err != nil {
+		return event.Lines, []string{f("event %q: invalid RRULE %s, leaving it unchanged: %s", event.uid(), ruleValue, err)}
+	}
+
+	var warnings []string
+	form := icsTimeFormOf(startParams, startValue)
+	var exdates []string
+	for _, line := range exdateLines {
+		_, params, value := splitIcsProperty(line)
+		for _, v := range strings.Split(value, ",") {
+			exdate, err := parseIcsDateTime(params, v)
+			if err != nil {
+				warnings = append(warnings, f("event %q: dropping invalid EXDATE %s", event.uid(), v))
+				continue
+			}
+			exdates = append(exdates, formatExdate(exdate, v, dtstart, form))
+		}
+	}
+
+	exdateHead := "EXDATE"
+	switch form {
+	case formDate:
+		exdateHead += ";VALUE=DATE"
+	case formZoned:
+		exdateHead += ";TZID=" + startParams["TZID"]
+	}
+
+	lines := make([]string, 0, len(event.Lines))
+	depth = 0
+	for _, line := range event.Lines {
+		name, _, _ := splitIcsProperty(line)
+		switch name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth == 1 && name == "EXDATE" {
+			continue
+		}
+		if depth == 1 && line == ruleLine {
+			lines = append(lines, "RRULE:"+rule.format(dtstart, form))
+			if len(exdates) > 0 {
+				lines = append(lines, exdateHead+":"+strings.Join(exdates, ","))
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, warnings
+}
+
+// normalizeRecurrence normalizes the recurring events of a calendar, logging the rules and
+// exception dates it could not read. Non-ICS output is returned unchanged.
+func (s *Server) normalizeRecurrence(calendarPath, ics string) string {
+	if !strings.Contains(ics, "BEGIN:VCALENDAR") {
+		return ics
+	}
+
+	out, warnings := NormalizeRecurrence(ics)
+	for _, warning := range warnings {
+		log.Printf("%s: %s", calendarPath, warning)
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package gnocal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatRRule(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		rrule   string
+		dtstart time.Time
+		form    icsTimeForm
+		want    string
+	}{
+		{
+			name:    "first Monday of each month",
+			rrule:   "freq=monthly;byday=1mo",
+			dtstart: time.Date(2025, 2, 3, 18, 0, 0, 0, time.UTC),
+			form:    formUTC,
+			want:    "FREQ=MONTHLY;BYDAY=1MO",
+		},
+		{
+			name:    "every two weeks on Wednesday ending Dec 31",
+			rrule:   "BYDAY=WE;INTERVAL=2;FREQ=WEEKLY;UNTIL=20251231",
+			dtstart: time.Date(2025, 1, 8, 19, 0, 0, 0, paris),
+			form:    formZoned,
+			want:    "FREQ=WEEKLY;INTERVAL=2;UNTIL=20251231T225959Z;BYDAY=WE",
+		},
+		{
+			name:    "daily ten times",
+			rrule:   "FREQ=DAILY;INTERVAL=1;COUNT=10",
+			dtstart: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			form:    formUTC,
+			want:    "FREQ=DAILY;COUNT=10",
+		},
+		{
+			name:    "last Friday of the month, all day",
+			rrule:   "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231T000000Z",
+			dtstart: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+			form:    formDate,
+			want:    "FREQ=MONTHLY;UNTIL=20261231;BYDAY=-1FR",
+		},
+		{
+			name:    "floating weekly keeps a floating UNTIL",
+			rrule:   "FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20250630T170000",
+			dtstart: time.Date(2025, 1, 7, 17, 0, 0, 0, time.UTC),
+			form:    formFloating,
+			want:    "FREQ=WEEKLY;UNTIL=20250630T170000;BYDAY=TU,TH",
+		},
+		{
+			name:    "uninterpreted parts are kept",
+			rrule:   "FREQ=MONTHLY;BYMONTHDAY=15;WKST=SU",
+			dtstart: time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC),
+			form:    formUTC,
+			want:    "FREQ=MONTHLY;BYMONTHDAY=15;WKST=SU",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rrule)
+			if err != nil {
+				t.Fatalf("ParseRRule() error = %v", err)
+			}
+			if got := rule.format(tt.dtstart, tt.form); got != tt.want {
+				t.Errorf("format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRRule_RejectsUnsupportedRules(t *testing.T) {
+	for _, rrule := range []string{
+		"FREQ=HOURLY",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;COUNT=3;UNTIL=20250101",
+		"FREQ=MONTHLY;BYDAY=6MO",
+	} {
+		if _, err := ParseRRule(rrule); err == nil {
+			t.Errorf("ParseRRule(%q) succeeded, want an error", rrule)
+		}
+	}
+}
+
+func TestExpand_MonthlyByDayOrdinal(t *testing.T) {
+	rule, err := ParseRRule("FREQ=MONTHLY;BYDAY=1MO")
+	if err != nil {
+		t.Fatalf("ParseRRule() error = %v", err)
+	}
+
+	occurrences, _ := rule.Expand(time.Date(2025, 1, 6, 18, 0, 0, 0, time.UTC), 3, nil)
+	want := []string{"2025-01-06", "2025-02-03", "2025-03-03"}
+	if len(occurrences) != len(want) {
+		t.Fatalf("Expand() = %v, want %v", occurrences, want)
+	}
+	for i := range want {
+		if got := occurrences[i].Format(time.DateOnly); got != want[i] {
+			t.Errorf("occurrence %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestNormalizeRecurrence(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:office-hours@gno.land",
+		"DTSTART;TZID=Europe/Paris:20250108T190000",
+		"RRULE:INTERVAL=2;FREQ=WEEKLY;BYDAY=WE;UNTIL=20251231",
+		"EXDATE;TZID=Europe/Paris:20250205T190000",
+		"EXDATE;VALUE=DATE:20250319",
+		"EXDATE:not-a-date",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken@gno.land",
+		"DTSTART:20250108T190000Z",
+		"RRULE:FREQ=SECONDLY",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	if _, err := time.LoadLocation("Europe/Paris"); err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	out, warnings := NormalizeRecurrence(ics)
+
+	officeHours := eventsByUID(out, "office-hours@gno.land")[0].Lines
+	for _, want := range []string{
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=20251231T225959Z;BYDAY=WE",
+		"EXDATE;TZID=Europe/Paris:20250205T190000,20250319T190000",
+	} {
+		if !slicesContainsSubstring(officeHours, want) {
+			t.Errorf("normalized event %v is missing %q", officeHours, want)
+		}
+	}
+	if !slicesContainsSubstring(warnings, "not-a-date") {
+		t.Errorf("expected a warning for the invalid EXDATE, got %v", warnings)
+	}
+
+	broken := eventsByUID(out, "broken@gno.land")[0].Lines
+	if !slicesContainsSubstring(broken, "RRULE:FREQ=SECONDLY") || !slicesContainsSubstring(warnings, "SECONDLY") {
+		t.Errorf("unsupported RRULE should be kept and reported, got %v with warnings %v", broken, warnings)
+	}
+}
@@ -0,0 +1,159 @@
+package gnocal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTokenNotFound = errors.New("feed token not found")
+	ErrTokenRevoked  = errors.New("feed token revoked")
+)
+
+// FeedToken is a per-subscriber secret that grants access to a single calendar feed.
+// Revoking a token only cuts off that subscriber; the calendar itself is unchanged.
+type FeedToken struct {
+	Token        string     `json:"token"`
+	CalendarPath string     `json:"calendar_path"`
+	Subscriber   string     `json:"subscriber,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TokenStore holds the token → calendar mapping.
+// When created with a path, every change is persisted to that file as JSON.
+type TokenStore struct {
+	mu     sync.RWMutex
+	path   string
+	tokens map[string]*FeedToken
+}
+
+// NewTokenStore returns a token store backed by the JSON file at path.
+// An empty path keeps tokens in memory only.
+func NewTokenStore(path string) (*TokenStore, error) {
+	s := &TokenStore{
+		path:   path,
+		tokens: make(map[string]*FeedToken),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token store: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse token store: %w", err)
+	}
+	return s, nil
+}
+
+// Issue creates a new token granting access to calendarPath.
+func (s *TokenStore) Issue(calendarPath, subscriber string) (*FeedToken, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token := &FeedToken{
+		Token:        hex.EncodeToString(secret),
+		CalendarPath: calendarPath,
+		Subscriber:   subscriber,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token.Token] = token
+	if err := s.save(); err != nil {
+		delete(s.tokens, token.Token)
+		return nil, err
+	}
+	return token, nil
+}
+
+// Revoke marks token as revoked. Revoked tokens are kept so that they are
+// reported as revoked rather than unknown.
+func (s *TokenStore) Revoke(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[token]
+	if !ok {
+		return ErrTokenNotFound
+	}
+	if t.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	t.RevokedAt = &now
+	if err := s.save(); err != nil {
+		t.RevokedAt = nil
+		return err
+	}
+	return nil
+}
+
+// Lookup returns the active token, or ErrTokenNotFound / ErrTokenRevoked.
+func (s *TokenStore) Lookup(token string) (*FeedToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tokens[token]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	if t.RevokedAt != nil {
+		return nil, ErrTokenRevoked
+	}
+	tokenCopy := *t
+	return &tokenCopy, nil
+}
+
+// Gates reports whether feed tokens were ever issued for calendarPath, revoked ones included.
+// Such calendars are only served through their tokens, so revoking a token cuts off access.
+func (s *TokenStore) Gates(calendarPath string) bool {
+	calendarPath = strings.Trim(calendarPath, "/")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, t := range s.tokens {
+		if t.CalendarPath == calendarPath {
+			return true
+		}
+	}
+	return false
+}
+
+// save writes the token map to disk. Callers must hold the write lock.
+func (s *TokenStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode token store: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write token store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write token store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package gnocal
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+)
+
+// fakeRealmClient returns a fixed calendar for any realm path, or the
+// per-realm calendar from realms when set
+type fakeRealmClient struct {
+	calendar string
+	realms   map[string]string
+}
+
+func (c *fakeRealmClient) QEval(pkgPath string, expression string) (string, *ctypes.ResultABCIQuery, error) {
+	if c.realms != nil {
+		calendar, ok := c.realms[pkgPath]
+		if !ok {
+			return "", nil, errors.New("invalid package path")
+		}
+		return "(" + strconv.Quote(calendar) + " string)", nil, nil
+	}
+	return `("` + c.calendar + `" string)`, nil, nil
+}
+
+func (c *fakeRealmClient) Block(height int64) (*ctypes.ResultBlock, error) {
+	return nil, errors.New("block not available")
+}
+
+const testAdminToken = "admin-secret"
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	s := NewGnocalServer(&ServerOptions{
+		GnolandRpcUrl: "http://127.0.0.1:26657",
+		AdminToken:    testAdminToken,
+	})
+	s.gnoClient = &fakeRealmClient{calendar: `BEGIN:VCALENDAR\nEND:VCALENDAR`}
+	return s
+}
+
+func issueToken(t *testing.T, s *Server, subscriber string) string {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/tokens?calendar=gno.land/r/demo/events&subscriber="+subscriber, nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("issue token: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+
+	var resp map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("issue token: failed to decode response: %v", err)
+	}
+	if !strings.HasSuffix(resp["feed_url"], "/feed/"+resp["token"]) {
+		t.Errorf("issue token: feed_url = %q does not reference token", resp["feed_url"])
+	}
+	return resp["token"]
+}
+
+func fetchFeed(s *Server, token string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed/"+token, nil))
+	return rec
+}
+
+func TestFeedTokenRevocation(t *testing.T) {
+	s := newTestServer(t)
+
+	alice := issueToken(t, s, "alice")
+	bob := issueToken(t, s, "bob")
+
+	for _, token := range []string{alice, bob} {
+		rec := fetchFeed(s, token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("fetch feed: status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		if !strings.Contains(rec.Body.String(), "BEGIN:VCALENDAR") {
+			t.Errorf("fetch feed: body = %q, want calendar", rec.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/tokens/"+alice, nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke token: status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	if rec := fetchFeed(s, alice); rec.Code != http.StatusForbidden {
+		t.Errorf("revoked token: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := fetchFeed(s, bob); rec.Code != http.StatusOK {
+		t.Errorf("other token: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec := fetchFeed(s, "unknown"); rec.Code != http.StatusForbidden {
+		t.Errorf("unknown token: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestFeedTokenGatesPublicPaths(t *testing.T) {
+	s := newTestServer(t)
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := get("/gno.land/r/demo/events"); code != http.StatusOK {
+		t.Fatalf("public calendar before any token: status = %d, want %d", code, http.StatusOK)
+	}
+
+	alice := issueToken(t, s, "alice")
+	req := httptest.NewRequest(http.MethodDelete, "/tokens/"+alice, nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	s.router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if rec := fetchFeed(s, alice); rec.Code != http.StatusForbidden {
+		t.Errorf("revoked token: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	for _, path := range []string{
+		"/gno.land/r/demo/events",
+		"/gno.land/r/demo/events/",
+		"/cal/gno.land/r/demo/events/occurrences",
+		"/cal/event-launch@gno.land/r/demo/events.ics",
+	} {
+		if code := get(path); code != http.StatusNotFound {
+			t.Errorf("gated calendar at %s: status = %d, want %d", path, code, http.StatusNotFound)
+		}
+	}
+	if code := get("/gno.land/r/demo/other"); code != http.StatusOK {
+		t.Errorf("ungated calendar: status = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestFeedTokenAdminAuth(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, auth := range []string{"", "Bearer wrong"} {
+		req := httptest.NewRequest(http.MethodPost, "/tokens?calendar=gno.land/r/demo/events", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("issue with auth %q: status = %d, want %d", auth, rec.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestTokenStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+
+	store, err := NewTokenStore(path)
+	if err != nil {
+		t.Fatalf("NewTokenStore() error = %v", err)
+	}
+	kept, err := store.Issue("gno.land/r/demo/events", "alice")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	revoked, err := store.Issue("gno.land/r/demo/events", "bob")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if err := store.Revoke(revoked.Token); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	reloaded, err := NewTokenStore(path)
+	if err != nil {
+		t.Fatalf("NewTokenStore() reload error = %v", err)
+	}
+	got, err := reloaded.Lookup(kept.Token)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got.CalendarPath != "gno.land/r/demo/events" {
+		t.Errorf("Lookup() CalendarPath = %q", got.CalendarPath)
+	}
+	if _, err := reloaded.Lookup(revoked.Token); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Lookup() revoked error = %v, want %v", err, ErrTokenRevoked)
+	}
+	if err := reloaded.Revoke("missing"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("Revoke() missing error = %v, want %v", err, ErrTokenNotFound)
+	}
+}
@@ -4,18 +4,32 @@ import (
 	"context"
 	"encoding/hex"
 	"flag"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/config"
 	"github.com/allinbits/labs/projects/gnolinker/core/contracts"
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/allinbits/labs/projects/gnolinker/core/health"
+	"github.com/allinbits/labs/projects/gnolinker/core/linkstatus"
+	"github.com/allinbits/labs/projects/gnolinker/core/metrics"
+	"github.com/allinbits/labs/projects/gnolinker/core/pause"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
 	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
 	"github.com/allinbits/labs/projects/gnolinker/platforms/discord"
 )
 
 func Run() {
+	// One-shot operator commands run instead of the bot
+	if len(os.Args) > 1 && os.Args[1] == "resync-guild" {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		os.Exit(runResyncGuild())
+	}
+
 	// Command line flags
 	var (
 		tokenFlag              = flag.String("token", "", "Discord bot token")
@@ -28,6 +42,18 @@ func Run() {
 		cleanupFlag            = flag.Bool("cleanup-commands", false, "Remove all existing slash commands on startup")
 		graphqlEndpointFlag    = flag.String("graphql-endpoint", "", "GraphQL HTTP endpoint for event monitoring")
 		enableEventMonitorFlag = flag.Bool("enable-event-monitoring", false, "Enable real-time event monitoring")
+		logAPICallsFlag        = flag.Bool("log-api-calls", false, "Log Discord API call counts per event and verification sweep")
+		dryRunFlag             = flag.Bool("dry-run", false, "Log the role changes events and verification would make instead of applying them")
+		rateLimitFlag          = flag.Float64("rate-limit", discord.DefaultRateLimit, "Maximum Discord role changes and DMs per second (0 to disable)")
+		healthAddrFlag         = flag.String("health-addr", "", "Address serving /healthz and /readyz (empty to disable)")
+		metricsAddrFlag        = flag.String("metrics-addr", "", "Address serving Prometheus metrics at /metrics (empty to disable)")
+		linkStatusFlag         = flag.String("link-status", "off", "Public GET /link/{address} lookup on the health server (off, boolean, full)")
+		adminTokenFlag         = flag.String("admin-token", "", "Bearer token for the guild pause and resume endpoints on the health server (empty to disable)")
+		rpcRetriesFlag         = flag.Int("rpc-retries", workflows.DefaultRetryPolicy.Attempts, "Attempts per realm query before giving up on an RPC failure")
+		breakerThresholdFlag   = flag.Int("rpc-breaker-threshold", workflows.DefaultBreakerThreshold, "Consecutive RPC failures that pause realm queries")
+		breakerCooldownFlag    = flag.Duration("rpc-breaker-cooldown", workflows.DefaultBreakerCooldown, "How long realm queries stay paused before probing the RPC again")
+		queryJitterFlag        = flag.Duration("query-jitter", events.DefaultQueryJitter, "Random spread of each guild's event query ticks (0 to disable)")
+		shutdownGraceFlag      = flag.Duration("shutdown-grace", events.DefaultShutdownGrace, "How long shutdown waits for in-flight event queries and verification (0 to wait until done)")
 	)
 	flag.Parse()
 
@@ -55,6 +81,22 @@ func Run() {
 	roleContract := getEnvOrFlag("GNOLINKER__ROLE_CONTRACT", *roleContractFlag)
 	graphqlEndpoint := getEnvOrFlag("GNOLINKER__GRAPHQL_ENDPOINT", *graphqlEndpointFlag)
 	enableEventMonitoring := getEnvOrBool("GNOLINKER__ENABLE_EVENT_MONITORING", *enableEventMonitorFlag)
+	logAPICalls := getEnvOrBool("GNOLINKER__LOG_API_CALLS", *logAPICallsFlag)
+	dryRun := getEnvOrBool("GNOLINKER__DRY_RUN", *dryRunFlag)
+	rateLimit := getEnvOrFloat("GNOLINKER__DISCORD_RATE_LIMIT", *rateLimitFlag)
+	healthAddr := getEnvOrFlag("GNOLINKER__HEALTH_ADDR", *healthAddrFlag)
+	metricsAddr := getEnvOrFlag("GNOLINKER__METRICS_ADDR", *metricsAddrFlag)
+	adminToken := getEnvOrFlag("GNOLINKER__ADMIN_TOKEN", *adminTokenFlag)
+	rpcRetries := getEnvOrInt("GNOLINKER__RPC_RETRIES", *rpcRetriesFlag)
+	breakerThreshold := getEnvOrInt("GNOLINKER__RPC_BREAKER_THRESHOLD", *breakerThresholdFlag)
+	breakerCooldown := getEnvOrDuration("GNOLINKER__RPC_BREAKER_COOLDOWN", *breakerCooldownFlag)
+	queryJitter := getEnvOrDuration("GNOLINKER__QUERY_JITTER", *queryJitterFlag)
+	shutdownGrace := getEnvOrDuration("GNOLINKER__SHUTDOWN_GRACE", *shutdownGraceFlag)
+	linkStatusMode, err := linkstatus.ParseMode(getEnvOrFlag("GNOLINKER__LINK_STATUS", *linkStatusFlag))
+	if err != nil {
+		logger.Error("Invalid link status mode", "error", err)
+		os.Exit(1)
+	}
 
 	// Validate required parameters
 	if token == "" {
@@ -78,20 +120,29 @@ func Run() {
 	} else {
 		logger.Info("GraphQL event monitoring disabled")
 	}
+	if dryRun {
+		logger.Warn("Dry run enabled: role changes are logged, not applied")
+	}
 
-	// Decode signing key
-	signingKeyBytes, err := hex.DecodeString(signingKeyStr)
+	signingKey, err := decodeSigningKey(signingKeyStr)
 	if err != nil {
-		logger.Error("Failed to decode hex signing key", "error", err)
+		logger.Error("Invalid signing key", "error", err)
 		os.Exit(1)
 	}
-	if len(signingKeyBytes) != 64 {
-		logger.Error("Signing key must be 64 bytes", "actual", len(signingKeyBytes))
-		os.Exit(1)
+
+	// Prometheus metrics are opt-in, served separately from the health endpoints
+	var gnolinkerMetrics *metrics.Metrics
+	if metricsAddr != "" {
+		gnolinkerMetrics = metrics.New()
 	}
 
-	var signingKey [64]byte
-	copy(signingKey[:], signingKeyBytes)
+	// Audit role changes in the configured storage backend, buffered so role changes never wait on it
+	auditStore, err := storageConfig.CreateAuditStore(ctx)
+	if err != nil {
+		logger.Error("Failed to create audit store", "error", err)
+		os.Exit(1)
+	}
+	auditLog := storage.NewBufferedAuditLog(auditStore, storage.DefaultAuditFlushInterval, logger)
 
 	// Create Discord config - roles are now managed by ConfigManager
 	discordConfig := discord.Config{
@@ -99,28 +150,38 @@ func Run() {
 		CleanupOldCommands:    *cleanupFlag,
 		GraphQLEndpoint:       graphqlEndpoint,
 		EnableEventMonitoring: enableEventMonitoring,
+		LogAPICalls:           logAPICalls,
+		DryRun:                dryRun,
+		RateLimit:             rateLimit,
+		QueryJitter:           queryJitter,
+		ShutdownGrace:         shutdownGrace,
+		Metrics:               gnolinkerMetrics,
+		AuditLog:              auditLog,
 		// Remove hard-coded roles - these will be managed dynamically per guild
 	}
 
 	// Create Gno client
-	clientConfig := contracts.ClientConfig{
-		RPCURL:       rpcURL,
-		UserContract: userContract,
-		RoleContract: roleContract,
-	}
-
-	gnoClient, err := contracts.NewGnoClient(clientConfig)
+	gnoClient, err := newGnoClient(rpcURL, userContract, roleContract)
 	if err != nil {
 		logger.Error("Failed to create Gno client", "error", err)
 		os.Exit(1)
 	}
 
+	// Realm queries retry RPC failures and pause together when the RPC is down
+	retryPolicy := workflows.DefaultRetryPolicy
+	retryPolicy.Attempts = rpcRetries
+	breaker := workflows.NewCircuitBreaker(breakerThreshold, breakerCooldown, logger)
+	breaker.OnStateChange(gnolinkerMetrics.SetRealmQueryBreakerOpen)
+
 	// Create workflow config
 	workflowConfig := workflows.WorkflowConfig{
-		SigningKey:   &signingKey,
+		SigningKey:   signingKey,
 		BaseURL:      baseURL,
 		UserContract: userContract,
 		RoleContract: roleContract,
+		Retry:        retryPolicy,
+		Breaker:      breaker,
+		Claims:       configManager,
 	}
 
 	// Create workflows
@@ -135,13 +196,94 @@ func Run() {
 		os.Exit(1)
 	}
 
+	// Serve health probes for the lifetime of the bot
+	var healthServer *health.Server
+	if healthAddr != "" {
+		healthServer = health.NewServer(healthAddr, logger)
+		bot.RegisterHealthChecks(healthServer)
+		healthServer.AddReadinessCheck("rpc", func(ctx context.Context) error {
+			_, err := gnoClient.GetCurrentBlockHeight()
+			return err
+		})
+		if linkStatusMode != linkstatus.ModeOff {
+			healthServer.Handle(linkstatus.Pattern, linkstatus.NewHandler(userFlow, linkStatusMode, logger))
+			logger.Info("Serving public link status lookups", "mode", linkStatusMode)
+		}
+		if adminToken != "" {
+			healthServer.Handle(pause.PausePattern, pause.NewHandler(configManager, true, adminToken, logger))
+			healthServer.Handle(pause.ResumePattern, pause.NewHandler(configManager, false, adminToken, logger))
+			logger.Info("Serving guild pause and resume endpoints")
+		}
+		if err := healthServer.Start(); err != nil {
+			logger.Error("Failed to start health server", "addr", healthAddr, "error", err)
+			os.Exit(1)
+		}
+	} else if linkStatusMode != linkstatus.ModeOff || adminToken != "" {
+		logger.Warn("Link status and guild pause endpoints are served on the health server, which is disabled (set GNOLINKER__HEALTH_ADDR)")
+	}
+
+	var metricsServer *metrics.Server
+	if gnolinkerMetrics != nil {
+		metricsServer = metrics.NewServer(metricsAddr, gnolinkerMetrics, logger)
+		if err := metricsServer.Start(); err != nil {
+			logger.Error("Failed to start metrics server", "addr", metricsAddr, "error", err)
+			os.Exit(1)
+		}
+	}
+
 	logger.Info("Starting gnolinker Discord bot", "rpc_url", rpcURL)
-	if err := bot.Start(); err != nil {
+	err = bot.Start()
+
+	if err := auditLog.Close(); err != nil {
+		logger.Warn("Failed to flush audit log", "error", err)
+	}
+
+	if metricsServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Failed to shut down metrics server", "error", err)
+		}
+		cancel()
+	}
+
+	if healthServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		if err := healthServer.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Failed to shut down health server", "error", err)
+		}
+		cancel()
+	}
+
+	if err != nil {
 		logger.Error("Bot error", "error", err)
 		os.Exit(1)
 	}
 }
 
+// decodeSigningKey decodes the hex encoded 64 byte signing key
+func decodeSigningKey(hexKey string) (*[64]byte, error) {
+	keyBytes, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode hex signing key: %w", err)
+	}
+	if len(keyBytes) != 64 {
+		return nil, fmt.Errorf("signing key must be 64 bytes, got %d", len(keyBytes))
+	}
+
+	var signingKey [64]byte
+	copy(signingKey[:], keyBytes)
+	return &signingKey, nil
+}
+
+// newGnoClient creates the client for the user and role linker realms
+func newGnoClient(rpcURL, userContract, roleContract string) (*contracts.GnoClient, error) {
+	return contracts.NewGnoClient(contracts.ClientConfig{
+		RPCURL:       rpcURL,
+		UserContract: userContract,
+		RoleContract: roleContract,
+	})
+}
+
 func getEnvOrFlag(envVar, flagValue string) string {
 	if envValue := os.Getenv(envVar); envValue != "" {
 		return envValue
@@ -149,6 +291,33 @@ func getEnvOrFlag(envVar, flagValue string) string {
 	return flagValue
 }
 
+func getEnvOrFloat(envVar string, flagValue float64) float64 {
+	if envValue := os.Getenv(envVar); envValue != "" {
+		if parsed, err := strconv.ParseFloat(envValue, 64); err == nil {
+			return parsed
+		}
+	}
+	return flagValue
+}
+
+func getEnvOrInt(envVar string, flagValue int) int {
+	if envValue := os.Getenv(envVar); envValue != "" {
+		if parsed, err := strconv.Atoi(envValue); err == nil {
+			return parsed
+		}
+	}
+	return flagValue
+}
+
+func getEnvOrDuration(envVar string, flagValue time.Duration) time.Duration {
+	if envValue := os.Getenv(envVar); envValue != "" {
+		if parsed, err := time.ParseDuration(envValue); err == nil {
+			return parsed
+		}
+	}
+	return flagValue
+}
+
 func getEnvOrBool(envVar string, flagValue bool) bool {
 	if envValue := os.Getenv(envVar); envValue != "" {
 		// Parse boolean from environment variable
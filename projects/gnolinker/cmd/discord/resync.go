@@ -0,0 +1,128 @@
+package discord
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
+	"github.com/allinbits/labs/projects/gnolinker/platforms/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+// runResyncGuild reconciles every member of one guild once, without starting the bot, and returns
+// the process exit code: nonzero if the run failed or any member could not be reconciled.
+//
+// Usage: gnolinker discord resync-guild --guild=<id> [--dry-run]
+func runResyncGuild() int {
+	var (
+		guildFlag        = flag.String("guild", "", "ID of the guild to reconcile")
+		tokenFlag        = flag.String("token", "", "Discord bot token")
+		rpcURLFlag       = flag.String("rpc-url", "https://rpc.gno.land:443", "Gno RPC URL")
+		userContractFlag = flag.String("user-contract", "r/linker000/discord/user/v0", "User contract path")
+		roleContractFlag = flag.String("role-contract", "r/linker000/discord/role/v0", "Role contract path")
+		logLevelFlag     = flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+		rateLimitFlag    = flag.Float64("rate-limit", discord.DefaultRateLimit, "Maximum Discord role changes per second (0 to disable)")
+		dryRunFlag       = flag.Bool("dry-run", false, "Print the role changes without applying them")
+	)
+	flag.Parse()
+
+	logger := core.NewLoggerFromLevel(getEnvOrFlag("GNOLINKER__LOG_LEVEL", *logLevelFlag))
+	guildID := *guildFlag
+	token := getEnvOrFlag("GNOLINKER__DISCORD_TOKEN", *tokenFlag)
+	rpcURL := getEnvOrFlag("GNOLINKER__GNOLAND_RPC_ENDPOINT", *rpcURLFlag)
+	userContract := getEnvOrFlag("GNOLINKER__USER_CONTRACT", *userContractFlag)
+	roleContract := getEnvOrFlag("GNOLINKER__ROLE_CONTRACT", *roleContractFlag)
+	rateLimit := getEnvOrFloat("GNOLINKER__DISCORD_RATE_LIMIT", *rateLimitFlag)
+	dryRun := getEnvOrBool("GNOLINKER__DRY_RUN", *dryRunFlag)
+
+	if guildID == "" {
+		logger.Error("Guild ID is required (use -guild flag)")
+		return 2
+	}
+	if token == "" {
+		logger.Error("Discord token is required (use -token flag or GNOLINKER__DISCORD_TOKEN env var)")
+		return 2
+	}
+
+	// Stop between members on interrupt; role changes already made are kept
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	configManager, err := config.InitializeConfigManager(ctx, logger)
+	if err != nil {
+		logger.Error("Failed to initialize configuration manager", "error", err)
+		return 1
+	}
+
+	gnoClient, err := newGnoClient(rpcURL, userContract, roleContract)
+	if err != nil {
+		logger.Error("Failed to create Gno client", "error", err)
+		return 1
+	}
+	// Reconciliation only reads links, so claims are never signed and no signing key is needed
+	workflowConfig := workflows.WorkflowConfig{
+		UserContract: userContract,
+		RoleContract: roleContract,
+		Retry:        workflows.DefaultRetryPolicy,
+	}
+	userFlow := workflows.NewUserLinkingWorkflow(gnoClient, workflowConfig)
+	roleFlow := workflows.NewRoleLinkingWorkflow(gnoClient, workflowConfig)
+
+	// REST calls are enough: the gateway is never opened
+	session, err := discordgo.New("Bot " + token)
+	if err != nil {
+		logger.Error("Failed to create Discord session", "error", err)
+		return 1
+	}
+	platform := discord.NewDiscordPlatform(session, discord.Config{Token: token, RateLimit: rateLimit}, configManager.GetLockManager(), logger)
+	eventHandlers := events.NewEventHandlers(platform, configManager, session, logger, userFlow, roleFlow)
+
+	auditStore, err := configManager.GetStorageConfig().CreateAuditStore(ctx)
+	if err != nil {
+		logger.Error("Failed to create audit store", "error", err)
+		return 1
+	}
+	auditLog := storage.NewBufferedAuditLog(auditStore, storage.DefaultAuditFlushInterval, logger)
+	eventHandlers.SetAuditLog(auditLog)
+
+	logger.Info("Reconciling guild members", "guild_id", guildID, "dry_run", dryRun)
+	report, err := eventHandlers.VerifyGuild(ctx, guildID, dryRun)
+	if err := auditLog.Close(); err != nil {
+		logger.Warn("Failed to flush audit log", "error", err)
+	}
+	if err != nil {
+		logger.Error("Guild resync failed", "guild_id", guildID, "error", err)
+		return 1
+	}
+
+	printResyncReport(guildID, report)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// printResyncReport writes the role changes of a guild resync to stdout, one per line
+func printResyncReport(guildID string, report *events.VerificationReport) {
+	verb := map[storage.AuditAction]string{storage.AuditActionGrant: "granted", storage.AuditActionRevoke: "removed"}
+	if report.DryRun {
+		verb = map[storage.AuditAction]string{storage.AuditActionGrant: "would grant", storage.AuditActionRevoke: "would remove"}
+	}
+
+	for _, mutation := range report.Mutations {
+		line := fmt.Sprintf("%s role %s for user %s", verb[mutation.Action], mutation.RoleID, mutation.UserID)
+		if mutation.RealmRole != "" {
+			line += fmt.Sprintf(" (%s at %s)", mutation.RealmRole, mutation.RealmPath)
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("guild %s: checked %d members, %d role changes, %d failed\n", guildID, report.Checked, len(report.Mutations), report.Failed)
+}
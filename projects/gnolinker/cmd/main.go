@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/allinbits/labs/projects/gnolinker/cmd/discord"
+	"github.com/allinbits/labs/projects/gnolinker/cmd/slack"
+	"github.com/allinbits/labs/projects/gnolinker/cmd/telegram"
 )
 
 func main() {
@@ -35,11 +37,9 @@ func main() {
 	case "discord":
 		discord.Run()
 	case "telegram":
-		fmt.Println("Telegram support is not yet implemented")
-		os.Exit(1)
+		telegram.Run()
 	case "slack":
-		fmt.Println("Slack support is not yet implemented")
-		os.Exit(1)
+		slack.Run()
 	default:
 		fmt.Printf("Unknown subcommand: %s\n\n", subcommand)
 		printUsage()
@@ -56,8 +56,8 @@ Usage:
 
 Available platforms:
   discord      Run Discord bot
-  telegram     Run Telegram bot (not implemented)
-  slack        Run Slack bot (not implemented)
+  telegram     Run Telegram bot
+  slack        Run Slack bot
 
 Available commands:
   help         Show this help message
@@ -67,14 +67,22 @@ Examples:
   gnolinker discord --token=...
   gnolinker discord --log-level=debug --token=... --admin-role=... --verified-role=...
   gnolinker discord --help
+  gnolinker discord resync-guild --guild=... [--dry-run]
+  gnolinker telegram --token=... --user-contract=... --groups=-1001234567890
+  gnolinker slack --token=... --signing-secret=... --user-contract=... --verified-usergroup=S0123456
   gnolinker version
 
 Environment variables:
   Bot configuration (GNOLINKER__ prefix):
     GNOLINKER__DISCORD_TOKEN, GNOLINKER__SIGNING_KEY
+    GNOLINKER__TELEGRAM_TOKEN, GNOLINKER__TELEGRAM_GROUPS, GNOLINKER__TELEGRAM_USER_CONTRACT
+    GNOLINKER__SLACK_TOKEN, GNOLINKER__SLACK_SIGNING_SECRET, GNOLINKER__SLACK_VERIFIED_USERGROUP
+    GNOLINKER__SLACK_LISTEN_ADDR, GNOLINKER__SLACK_USER_CONTRACT
     GNOLINKER__GNOLAND_RPC_ENDPOINT, GNOLINKER__BASE_URL
     GNOLINKER__LOG_LEVEL (debug, info, warn, error)
     GNOLINKER__GRAPHQL_ENDPOINT, GNOLINKER__ENABLE_EVENT_MONITORING
+    GNOLINKER__HEALTH_ADDR (/healthz and /readyz)
+    GNOLINKER__METRICS_ADDR (Prometheus /metrics)
   
   Storage configuration (GNOLINKER__ prefix):
     GNOLINKER__STORAGE_TYPE (memory, s3)
@@ -93,5 +101,7 @@ Use --admin-role and --verified-role flags only to override defaults.
 
 For platform-specific help:
   gnolinker discord --help
+  gnolinker telegram --help
+  gnolinker slack --help
 `)
 }
@@ -0,0 +1,135 @@
+package slack
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"os"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/contracts"
+	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
+	"github.com/allinbits/labs/projects/gnolinker/platforms/slack"
+)
+
+func Run() {
+	// Command line flags
+	var (
+		tokenFlag         = flag.String("token", "", "Slack bot token (xoxb-...)")
+		signingSecretFlag = flag.String("signing-secret", "", "Slack app signing secret")
+		signingKeyFlag    = flag.String("signing-key", "", "Hex encoded signing key")
+		rpcURLFlag        = flag.String("rpc-url", "https://rpc.gno.land:443", "Gno RPC URL")
+		baseURLFlag       = flag.String("base-url", "https://gno.land", "Base URL for claim links")
+		userContractFlag  = flag.String("user-contract", "", "User contract path of the Slack user linker realm you deployed")
+		logLevelFlag      = flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+		apiURLFlag        = flag.String("api-url", slack.DefaultAPIURL, "Slack Web API URL")
+		listenAddrFlag    = flag.String("listen-addr", slack.DefaultListenAddr, "Address of the slash command server")
+		usergroupFlag     = flag.String("verified-usergroup", "", "ID of the user group linked members join (e.g. S0123456)")
+		graphqlFlag       = flag.String("graphql-endpoint", "", "GraphQL HTTP endpoint for event monitoring and verification sweeps")
+	)
+	flag.Parse()
+
+	// Load log level from environment or flag
+	logLevel := getEnvOrFlag("GNOLINKER__LOG_LEVEL", *logLevelFlag)
+
+	// Initialize logger with configurable level
+	logger := core.NewLoggerFromLevel(logLevel)
+	logger.Info("Starting gnolinker Slack bot", "log_level", logLevel)
+
+	// Initialize configuration manager (includes storage and lock manager)
+	ctx := context.Background()
+	configManager, err := config.InitializeConfigManager(ctx, logger)
+	if err != nil {
+		logger.Error("Failed to initialize configuration manager", "error", err)
+		os.Exit(1)
+	}
+
+	// Load from environment if flags not provided
+	token := getEnvOrFlag("GNOLINKER__SLACK_TOKEN", *tokenFlag)
+	signingSecret := getEnvOrFlag("GNOLINKER__SLACK_SIGNING_SECRET", *signingSecretFlag)
+	signingKeyStr := getEnvOrFlag("GNOLINKER__SIGNING_KEY", *signingKeyFlag)
+	rpcURL := getEnvOrFlag("GNOLINKER__GNOLAND_RPC_ENDPOINT", *rpcURLFlag)
+	baseURL := getEnvOrFlag("GNOLINKER__BASE_URL", *baseURLFlag)
+	userContract := getEnvOrFlag("GNOLINKER__SLACK_USER_CONTRACT", *userContractFlag)
+	apiURL := getEnvOrFlag("GNOLINKER__SLACK_API_URL", *apiURLFlag)
+	listenAddr := getEnvOrFlag("GNOLINKER__SLACK_LISTEN_ADDR", *listenAddrFlag)
+	usergroup := getEnvOrFlag("GNOLINKER__SLACK_VERIFIED_USERGROUP", *usergroupFlag)
+	graphqlEndpoint := getEnvOrFlag("GNOLINKER__GRAPHQL_ENDPOINT", *graphqlFlag)
+
+	// Validate required parameters
+	if token == "" {
+		logger.Error("Slack token is required (use -token flag or GNOLINKER__SLACK_TOKEN env var)")
+		os.Exit(1)
+	}
+	if signingSecret == "" {
+		logger.Error("Slack signing secret is required (use -signing-secret flag or GNOLINKER__SLACK_SIGNING_SECRET env var)")
+		os.Exit(1)
+	}
+	if signingKeyStr == "" {
+		logger.Error("Signing key is required (use -signing-key flag or GNOLINKER__SIGNING_KEY env var)")
+		os.Exit(1)
+	}
+	if userContract == "" {
+		logger.Error("User contract is required (use -user-contract flag or GNOLINKER__SLACK_USER_CONTRACT env var)")
+		os.Exit(1)
+	}
+
+	// Decode signing key
+	signingKeyBytes, err := hex.DecodeString(signingKeyStr)
+	if err != nil {
+		logger.Error("Failed to decode hex signing key", "error", err)
+		os.Exit(1)
+	}
+	if len(signingKeyBytes) != 64 {
+		logger.Error("Signing key must be 64 bytes", "actual", len(signingKeyBytes))
+		os.Exit(1)
+	}
+
+	var signingKey [64]byte
+	copy(signingKey[:], signingKeyBytes)
+
+	// Create Gno client
+	gnoClient, err := contracts.NewGnoClient(contracts.ClientConfig{
+		RPCURL:       rpcURL,
+		UserContract: userContract,
+	})
+	if err != nil {
+		logger.Error("Failed to create Gno client", "error", err)
+		os.Exit(1)
+	}
+
+	userFlow := workflows.NewUserLinkingWorkflow(gnoClient, workflows.WorkflowConfig{
+		SigningKey:   &signingKey,
+		BaseURL:      baseURL,
+		UserContract: userContract,
+		Claims:       configManager,
+	})
+
+	bot, err := slack.NewBot(slack.Config{
+		Token:               token,
+		SigningSecret:       signingSecret,
+		APIURL:              apiURL,
+		ListenAddr:          listenAddr,
+		VerifiedUsergroupID: usergroup,
+		GraphQLEndpoint:     graphqlEndpoint,
+		UserRealmPath:       "gno.land/" + userContract,
+	}, userFlow, configManager, logger)
+	if err != nil {
+		logger.Error("Failed to create Slack bot", "error", err)
+		os.Exit(1)
+	}
+
+	logger.Info("Starting gnolinker Slack bot", "rpc_url", rpcURL, "user_contract", userContract)
+	if err := bot.Start(); err != nil {
+		logger.Error("Bot error", "error", err)
+		os.Exit(1)
+	}
+}
+
+func getEnvOrFlag(envVar, flagValue string) string {
+	if envValue := os.Getenv(envVar); envValue != "" {
+		return envValue
+	}
+	return flagValue
+}
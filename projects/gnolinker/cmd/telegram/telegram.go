@@ -0,0 +1,151 @@
+package telegram
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/contracts"
+	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
+	"github.com/allinbits/labs/projects/gnolinker/platforms/telegram"
+)
+
+func Run() {
+	// Command line flags
+	var (
+		tokenFlag        = flag.String("token", "", "Telegram bot token")
+		signingKeyFlag   = flag.String("signing-key", "", "Hex encoded signing key")
+		rpcURLFlag       = flag.String("rpc-url", "https://rpc.gno.land:443", "Gno RPC URL")
+		baseURLFlag      = flag.String("base-url", "https://gno.land", "Base URL for claim links")
+		userContractFlag = flag.String("user-contract", "", "User contract path of the Telegram user linker realm you deployed")
+		logLevelFlag     = flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+		apiURLFlag       = flag.String("api-url", telegram.DefaultAPIURL, "Telegram Bot API URL")
+		groupsFlag       = flag.String("groups", "", "Comma-separated supergroup chat IDs where linked members get verified status")
+		graphqlFlag      = flag.String("graphql-endpoint", "", "GraphQL HTTP endpoint for event monitoring and verification sweeps")
+	)
+	flag.Parse()
+
+	// Load log level from environment or flag
+	logLevel := getEnvOrFlag("GNOLINKER__LOG_LEVEL", *logLevelFlag)
+
+	// Initialize logger with configurable level
+	logger := core.NewLoggerFromLevel(logLevel)
+	logger.Info("Starting gnolinker Telegram bot", "log_level", logLevel)
+
+	// Initialize configuration manager (includes storage and lock manager)
+	ctx := context.Background()
+	configManager, err := config.InitializeConfigManager(ctx, logger)
+	if err != nil {
+		logger.Error("Failed to initialize configuration manager", "error", err)
+		os.Exit(1)
+	}
+
+	// Load from environment if flags not provided
+	token := getEnvOrFlag("GNOLINKER__TELEGRAM_TOKEN", *tokenFlag)
+	signingKeyStr := getEnvOrFlag("GNOLINKER__SIGNING_KEY", *signingKeyFlag)
+	rpcURL := getEnvOrFlag("GNOLINKER__GNOLAND_RPC_ENDPOINT", *rpcURLFlag)
+	baseURL := getEnvOrFlag("GNOLINKER__BASE_URL", *baseURLFlag)
+	userContract := getEnvOrFlag("GNOLINKER__TELEGRAM_USER_CONTRACT", *userContractFlag)
+	apiURL := getEnvOrFlag("GNOLINKER__TELEGRAM_API_URL", *apiURLFlag)
+	groups := getEnvOrFlag("GNOLINKER__TELEGRAM_GROUPS", *groupsFlag)
+	graphqlEndpoint := getEnvOrFlag("GNOLINKER__GRAPHQL_ENDPOINT", *graphqlFlag)
+
+	// Validate required parameters
+	if token == "" {
+		logger.Error("Telegram token is required (use -token flag or GNOLINKER__TELEGRAM_TOKEN env var)")
+		os.Exit(1)
+	}
+	if signingKeyStr == "" {
+		logger.Error("Signing key is required (use -signing-key flag or GNOLINKER__SIGNING_KEY env var)")
+		os.Exit(1)
+	}
+	if userContract == "" {
+		logger.Error("User contract is required (use -user-contract flag or GNOLINKER__TELEGRAM_USER_CONTRACT env var)")
+		os.Exit(1)
+	}
+
+	groupIDs, err := parseGroupIDs(groups)
+	if err != nil {
+		logger.Error("Invalid Telegram group IDs", "groups", groups, "error", err)
+		os.Exit(1)
+	}
+
+	// Decode signing key
+	signingKeyBytes, err := hex.DecodeString(signingKeyStr)
+	if err != nil {
+		logger.Error("Failed to decode hex signing key", "error", err)
+		os.Exit(1)
+	}
+	if len(signingKeyBytes) != 64 {
+		logger.Error("Signing key must be 64 bytes", "actual", len(signingKeyBytes))
+		os.Exit(1)
+	}
+
+	var signingKey [64]byte
+	copy(signingKey[:], signingKeyBytes)
+
+	// Create Gno client
+	gnoClient, err := contracts.NewGnoClient(contracts.ClientConfig{
+		RPCURL:       rpcURL,
+		UserContract: userContract,
+	})
+	if err != nil {
+		logger.Error("Failed to create Gno client", "error", err)
+		os.Exit(1)
+	}
+
+	// Telegram only links users; roles beyond the verified status are not supported
+	userFlow := workflows.NewUserLinkingWorkflow(gnoClient, workflows.WorkflowConfig{
+		SigningKey:   &signingKey,
+		BaseURL:      baseURL,
+		UserContract: userContract,
+		Claims:       configManager,
+	})
+
+	bot, err := telegram.NewBot(telegram.Config{
+		Token:           token,
+		APIURL:          apiURL,
+		GroupIDs:        groupIDs,
+		GraphQLEndpoint: graphqlEndpoint,
+		UserRealmPath:   "gno.land/" + userContract,
+	}, userFlow, configManager, logger)
+	if err != nil {
+		logger.Error("Failed to create Telegram bot", "error", err)
+		os.Exit(1)
+	}
+
+	logger.Info("Starting gnolinker Telegram bot", "rpc_url", rpcURL, "user_contract", userContract)
+	if err := bot.Start(); err != nil {
+		logger.Error("Bot error", "error", err)
+		os.Exit(1)
+	}
+}
+
+// parseGroupIDs parses a comma-separated list of chat IDs
+func parseGroupIDs(s string) ([]int64, error) {
+	var ids []int64
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func getEnvOrFlag(envVar, flagValue string) string {
+	if envValue := os.Getenv(envVar); envValue != "" {
+		return envValue
+	}
+	return flagValue
+}
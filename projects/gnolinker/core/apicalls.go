@@ -0,0 +1,44 @@
+package core
+
+import (
+	"maps"
+	"sync"
+)
+
+// APICallCounter tallies outbound platform API calls by operation name.
+// Counts are process-wide, so deltas taken around concurrent work include
+// calls made by other goroutines in the same window.
+type APICallCounter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewAPICallCounter creates an empty call counter
+func NewAPICallCounter() *APICallCounter {
+	return &APICallCounter{counts: make(map[string]int64)}
+}
+
+// Record counts one call of the given operation
+func (c *APICallCounter) Record(operation string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[operation]++
+}
+
+// Snapshot returns a copy of the current totals
+func (c *APICallCounter) Snapshot() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.counts)
+}
+
+// Since returns the calls made since the given snapshot, omitting operations with no new calls
+func (c *APICallCounter) Since(before map[string]int64) map[string]int64 {
+	delta := make(map[string]int64)
+	for operation, count := range c.Snapshot() {
+		if n := count - before[operation]; n > 0 {
+			delta[operation] = n
+		}
+	}
+	return delta
+}
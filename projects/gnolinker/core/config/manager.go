@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -97,6 +98,644 @@ func (m *ConfigManager) GetStore() storage.ConfigStore {
 	return m.store
 }
 
+// GetRoleSyncPolicy returns the effective role sync policy for a guild configuration
+func (m *ConfigManager) GetRoleSyncPolicy(config *storage.GuildConfig) storage.RoleSyncPolicy {
+	defaultPolicy := storage.RoleSyncPolicyStrict
+	if m.storageConfig != nil && m.storageConfig.DefaultRoleSyncPolicy != "" {
+		defaultPolicy = m.storageConfig.DefaultRoleSyncPolicy
+	}
+	if config == nil {
+		return defaultPolicy
+	}
+	return config.GetRoleSyncPolicy(defaultPolicy)
+}
+
+// GetRoleSyncOrder returns the effective role sync order for a guild configuration
+func (m *ConfigManager) GetRoleSyncOrder(config *storage.GuildConfig) storage.RoleSyncOrder {
+	defaultOrder := storage.RoleSyncOrderInterleaved
+	if m.storageConfig != nil && m.storageConfig.DefaultRoleSyncOrder != "" {
+		defaultOrder = m.storageConfig.DefaultRoleSyncOrder
+	}
+	if config == nil {
+		return defaultOrder
+	}
+	return config.GetRoleSyncOrder(defaultOrder)
+}
+
+// GetLinkUniqueness returns the effective link uniqueness for a guild configuration
+func (m *ConfigManager) GetLinkUniqueness(config *storage.GuildConfig) storage.LinkUniqueness {
+	defaultUniqueness := storage.LinkUniquenessOff
+	if m.storageConfig != nil && m.storageConfig.DefaultLinkUniqueness != "" {
+		defaultUniqueness = m.storageConfig.DefaultLinkUniqueness
+	}
+	if config == nil {
+		return defaultUniqueness
+	}
+	return config.GetLinkUniqueness(defaultUniqueness)
+}
+
+// GetLinkConflictAction returns the effective link conflict action for a guild configuration
+func (m *ConfigManager) GetLinkConflictAction(config *storage.GuildConfig) storage.LinkConflictAction {
+	defaultAction := storage.LinkConflictActionWarn
+	if m.storageConfig != nil && m.storageConfig.DefaultLinkConflictAction != "" {
+		defaultAction = m.storageConfig.DefaultLinkConflictAction
+	}
+	if config == nil {
+		return defaultAction
+	}
+	return config.GetLinkConflictAction(defaultAction)
+}
+
+// ShouldCleanupDepartedMembers reports whether per-member state is deleted when a member leaves
+func (m *ConfigManager) ShouldCleanupDepartedMembers(config *storage.GuildConfig) bool {
+	cleanup := m.storageConfig != nil && m.storageConfig.CleanupDepartedMembers
+	if config == nil {
+		return cleanup
+	}
+	return config.GetBool(storage.SettingCleanupDepartedMembers, cleanup)
+}
+
+// ShouldNotifyRoleChanges reports whether members are sent a DM when an on-chain event changes their roles
+func (m *ConfigManager) ShouldNotifyRoleChanges(config *storage.GuildConfig) bool {
+	notify := m.storageConfig != nil && m.storageConfig.NotifyRoleChanges
+	if config == nil {
+		return notify
+	}
+	return config.GetBool(storage.SettingNotifyRoleChanges, notify)
+}
+
+// SetNotifyRoleChanges enables or disables role change DMs for a guild
+func (m *ConfigManager) SetNotifyRoleChanges(guildID string, notify bool) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	config.SetBool(storage.SettingNotifyRoleChanges, notify)
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// GetRoleStyle returns how the bot styles the Discord roles it creates for a guild configuration
+func (m *ConfigManager) GetRoleStyle(config *storage.GuildConfig) storage.RoleStyle {
+	if config == nil {
+		return storage.RoleStyle{}
+	}
+	return config.GetRoleStyle()
+}
+
+// SetRoleStyle changes how the bot styles the Discord roles it creates for a guild. Existing
+// roles are left as they are.
+func (m *ConfigManager) SetRoleStyle(guildID string, style storage.RoleStyle) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	config.SetRoleStyle(style)
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// GetRoleNameTemplate returns the effective Discord role name template for a guild configuration.
+// Invalid guild overrides fall back to the default template.
+func (m *ConfigManager) GetRoleNameTemplate(config *storage.GuildConfig) string {
+	defaultTemplate := core.DefaultRoleNameTemplate
+	if m.storageConfig != nil && m.storageConfig.DefaultRoleNameTemplate != "" {
+		defaultTemplate = m.storageConfig.DefaultRoleNameTemplate
+	}
+	if config == nil {
+		return defaultTemplate
+	}
+
+	override := config.GetString(storage.SettingRoleNameTemplate, "")
+	if override == "" {
+		return defaultTemplate
+	}
+	if _, err := core.ParseRoleNameTemplate(override); err != nil {
+		m.logger.Warn("Ignoring invalid guild role name template", "guild_id", config.GuildID, "template", override, "error", err)
+		return defaultTemplate
+	}
+	return override
+}
+
+// RecordLinkedRole persists the Discord role created for a realm role in the guild configuration
+func (m *ConfigManager) RecordLinkedRole(guildID, realmPath, roleName, roleID string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if existing, ok := config.GetLinkedRole(realmPath, roleName); ok && existing == roleID {
+		return nil
+	}
+	config.SetLinkedRole(realmPath, roleName, roleID)
+	if !slices.Contains(config.MonitoredRealms, realmPath) && len(config.MonitoredRealms) > 0 {
+		config.MonitoredRealms = append(config.MonitoredRealms, realmPath)
+	}
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// AddRoleChannel scopes a realm role to a Discord channel or category
+func (m *ConfigManager) AddRoleChannel(guildID, realmPath, roleName, channelID string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if !config.AddRoleChannel(realmPath, roleName, channelID) {
+		return nil
+	}
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// ClearRoleChannels removes the channel scoping of a realm role
+func (m *ConfigManager) ClearRoleChannels(guildID, realmPath, roleName string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	config.ClearRoleChannels(realmPath, roleName)
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// SetMonitoredRealms persists a freshly discovered set of monitored realms
+func (m *ConfigManager) SetMonitoredRealms(guildID string, realmPaths []string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	config.SetMonitoredRealms(realmPaths)
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// RegisterRealm monitors a realm registered by an admin, returning false if it was already registered
+func (m *ConfigManager) RegisterRealm(guildID, realmPath string) (bool, error) {
+	if err := storage.ValidateRealmPath(realmPath); err != nil {
+		return false, err
+	}
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if !config.RegisterRealm(realmPath) {
+		return false, nil
+	}
+	if err := m.store.Set(guildID, config); err != nil {
+		return false, fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return true, nil
+}
+
+// UnregisterRealm stops monitoring a realm, returning false if it was not monitored
+func (m *ConfigManager) UnregisterRealm(guildID, realmPath string) (bool, error) {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if !config.UnregisterRealm(realmPath) {
+		return false, nil
+	}
+	if err := m.store.Set(guildID, config); err != nil {
+		return false, fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return true, nil
+}
+
+// SetRoleAutoSync includes or excludes a realm role from auto-sync
+func (m *ConfigManager) SetRoleAutoSync(guildID, realmPath, roleName string, autoSync bool) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if !config.SetRoleAutoSync(realmPath, roleName, autoSync) {
+		return nil
+	}
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// SetVerificationTier changes how often a verification tier runs and how many members it verifies,
+// taking effect on the tier's next run
+func (m *ConfigManager) SetVerificationTier(guildID, tier string, settings storage.VerificationTierSettings) error {
+	if err := storage.ValidateVerificationTier(tier, settings); err != nil {
+		return err
+	}
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if !config.SetVerificationTier(tier, settings) {
+		return nil
+	}
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// SetVerifiedRoleRule adds a verified-role rule to a guild, or replaces the rule of the same role
+func (m *ConfigManager) SetVerifiedRoleRule(guildID string, rule storage.VerifiedRoleRule) error {
+	if err := storage.ValidateVerifiedRoleRule(rule); err != nil {
+		return err
+	}
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	changed, err := config.SetVerifiedRoleRule(rule)
+	if err != nil || !changed {
+		return err
+	}
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// GetClaimTTL returns how long generated claims remain pending
+func (m *ConfigManager) GetClaimTTL() time.Duration {
+	if m.storageConfig != nil && m.storageConfig.ClaimTTL > 0 {
+		return m.storageConfig.ClaimTTL
+	}
+	return DefaultClaimTTL
+}
+
+// GetEventMaxAttempts returns how many times a failing event transaction is processed before it is dead-lettered
+func (m *ConfigManager) GetEventMaxAttempts() int {
+	if m.storageConfig != nil && m.storageConfig.EventMaxAttempts > 0 {
+		return m.storageConfig.EventMaxAttempts
+	}
+	return DefaultEventMaxAttempts
+}
+
+// RecordPendingClaim stores a user's pending claim in the guild configuration,
+// replacing any claim they previously generated
+func (m *ConfigManager) RecordPendingClaim(guildID, userID string, claim *storage.PendingClaim) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if claim.ExpiresAt.IsZero() {
+		claim.ExpiresAt = claim.CreatedAt.Add(m.GetClaimTTL())
+	}
+
+	// Keep tracking a pending role granted for the claim being replaced
+	if previous, exists := config.PendingClaims[userID]; exists && previous != nil && previous.RoleGranted {
+		claim.RoleGranted = true
+	}
+
+	config.SetPendingClaim(userID, claim)
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save pending claim: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingClaim returns a user's unexpired pending claim, or nil if there is none
+func (m *ConfigManager) GetPendingClaim(guildID, userID string) (*storage.PendingClaim, error) {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	claim, exists := config.GetPendingClaim(userID)
+	if !exists {
+		return nil, nil
+	}
+	return claim, nil
+}
+
+// RevokePendingClaim removes a user's pending claim, returning true if one was removed. The claim
+// is no longer handed out for repeated claim requests either, so the next request signs a new one.
+func (m *ConfigManager) RevokePendingClaim(guildID, userID string) (bool, error) {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	claim, exists := config.PendingClaims[userID]
+	if !exists {
+		return false, nil
+	}
+	if claim != nil && claim.Signature != "" {
+		if err := m.forgetIssuedClaim(claim.Signature); err != nil {
+			return false, err
+		}
+	}
+	config.DeletePendingClaim(userID)
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return false, fmt.Errorf("failed to save guild config: %w", err)
+	}
+
+	m.logger.Info("Removed pending claim", "guild_id", guildID, "user_id", userID)
+	return true, nil
+}
+
+// IssuedClaim returns the unexpired claim issued under an idempotency key. Lookup failures are
+// logged and reported as no claim, so a new claim is signed instead.
+func (m *ConfigManager) IssuedClaim(key string) (*core.Claim, bool) {
+	global, err := m.store.GetGlobal()
+	if err != nil {
+		m.logger.Warn("Failed to look up issued claim", "key", key, "error", err)
+		return nil, false
+	}
+
+	issued, exists := global.GetIssuedClaim(key)
+	if !exists {
+		return nil, false
+	}
+	return &core.Claim{
+		Type:      core.ClaimType(issued.Type),
+		Data:      issued.Data,
+		Signature: issued.Signature,
+		CreatedAt: issued.CreatedAt,
+	}, true
+}
+
+// RecordIssuedClaim keeps a claim under its idempotency key until the claim TTL has passed since
+// it was created. Failures are logged: the claim is still valid, only not handed out again.
+func (m *ConfigManager) RecordIssuedClaim(key string, claim *core.Claim) {
+	global, err := m.store.GetGlobal()
+	if err != nil {
+		m.logger.Warn("Failed to record issued claim", "key", key, "error", err)
+		return
+	}
+
+	global.SetIssuedClaim(key, &storage.IssuedClaim{
+		Type:      string(claim.Type),
+		Data:      claim.Data,
+		Signature: claim.Signature,
+		CreatedAt: claim.CreatedAt,
+		ExpiresAt: claim.CreatedAt.Add(m.GetClaimTTL()),
+	})
+	if err := m.store.SetGlobal(global); err != nil {
+		m.logger.Warn("Failed to record issued claim", "key", key, "error", err)
+	}
+}
+
+// forgetIssuedClaim drops the issued claim with the given signature so it is not handed out again
+func (m *ConfigManager) forgetIssuedClaim(signature string) error {
+	global, err := m.store.GetGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to get global config: %w", err)
+	}
+	if !global.DeleteIssuedClaim(signature) {
+		return nil
+	}
+	if err := m.store.SetGlobal(global); err != nil {
+		return fmt.Errorf("failed to save global config: %w", err)
+	}
+	return nil
+}
+
+// RecordUserLink records that userID linked address in a guild and returns the conflict the link
+// causes under the guild's uniqueness rules, or nil. Conflicts are held for admin review when the
+// guild's conflict action is hold.
+func (m *ConfigManager) RecordUserLink(guildID, userID, address string) (*storage.LinkConflict, error) {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	conflict := config.DetectLinkConflict(userID, address, m.GetLinkUniqueness(config))
+	if conflict != nil {
+		conflict.Held = m.GetLinkConflictAction(config) == storage.LinkConflictActionHold
+		config.SetLinkConflict(userID, conflict)
+	} else {
+		config.DeleteLinkConflict(userID)
+	}
+	config.RecordLinkedAddress(userID, address)
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return nil, fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return conflict, nil
+}
+
+// ForgetUserLink forgets userID's linked address and any conflict it caused in a guild
+func (m *ConfigManager) ForgetUserLink(guildID, userID string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	config.ClearLinkedAddress(userID)
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// ApproveLinkConflict resolves userID's link conflict so the account is granted roles again,
+// returning false if it had none
+func (m *ConfigManager) ApproveLinkConflict(guildID, userID string) (bool, error) {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if !config.DeleteLinkConflict(userID) {
+		return false, nil
+	}
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return false, fmt.Errorf("failed to save guild config: %w", err)
+	}
+
+	m.logger.Info("Approved link conflict", "guild_id", guildID, "user_id", userID)
+	return true, nil
+}
+
+// RecordBotAssignedRole records that the bot granted roleID to userID, so the permissive role
+// sync policy may remove it. The guild config is only saved when the grant is not yet recorded.
+func (m *ConfigManager) RecordBotAssignedRole(guildID, userID, roleID string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if config.IsBotAssignedRole(userID, roleID) {
+		return nil
+	}
+
+	config.RecordBotAssignedRole(userID, roleID)
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// ClearBotAssignedRole forgets that the bot granted roleID to userID
+func (m *ConfigManager) ClearBotAssignedRole(guildID, userID, roleID string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	if !config.IsBotAssignedRole(userID, roleID) {
+		return nil
+	}
+
+	config.ClearBotAssignedRole(userID, roleID)
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// RecordMemberDeparted marks a member as having left a guild, deleting their per-member state
+// when the guild cleans up departed members
+func (m *ConfigManager) RecordMemberDeparted(guildID, userID string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	config.MarkMemberDeparted(userID)
+	if m.ShouldCleanupDepartedMembers(config) {
+		config.ForgetMember(userID)
+	}
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// RecordMemberJoined clears a member's departure from a guild, returning true if they had left
+func (m *ConfigManager) RecordMemberJoined(guildID, userID string) (bool, error) {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if !config.MarkMemberRejoined(userID) {
+		return false, nil
+	}
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return false, fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return true, nil
+}
+
+// ImportRoleBaseline replaces a guild's role baseline with the managed role IDs held by each user ID
+func (m *ConfigManager) ImportRoleBaseline(guildID string, baseline map[string][]string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	config.SetRoleBaseline(baseline)
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+
+	m.logger.Info("Imported role baseline", "guild_id", guildID, "members", len(config.RoleBaseline))
+	return nil
+}
+
+// ConsumeRoleBaseline drops a member's role baseline once they had a verification pass
+func (m *ConfigManager) ConsumeRoleBaseline(guildID, userID string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if !config.ClearRoleBaseline(userID) {
+		return nil
+	}
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// ExpirePendingClaims removes a guild's expired pending claims and returns them keyed by user ID
+func (m *ConfigManager) ExpirePendingClaims(guildID string) (map[string]*storage.PendingClaim, error) {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	expired := config.TakeExpiredPendingClaims()
+	if len(expired) == 0 {
+		return expired, nil
+	}
+
+	if err := m.store.Set(guildID, config); err != nil {
+		return nil, fmt.Errorf("failed to save guild config: %w", err)
+	}
+
+	m.logger.Info("Expired pending claims", "guild_id", guildID, "count", len(expired))
+	return expired, nil
+}
+
+// SetPendingRole configures the role held by members while a link claim is pending.
+// An empty roleID disables the pending role.
+func (m *ConfigManager) SetPendingRole(guildID, roleID string) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	config.PendingRoleID = roleID
+	config.LastUpdated = time.Now()
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// SetGuildPaused pauses or resumes query processing and verification for a guild.
+// The paused state is persisted so it survives restarts.
+func (m *ConfigManager) SetGuildPaused(guildID string, paused bool) error {
+	config, err := m.store.Get(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	config.Paused = paused
+	config.LastUpdated = time.Now()
+	if err := m.store.Set(guildID, config); err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+
+	m.logger.Info("Updated guild processing state", "guild_id", guildID, "paused", paused)
+	return nil
+}
+
 // UpdateGuildConfig updates a guild configuration
 func (m *ConfigManager) UpdateGuildConfig(guildID string, config *storage.GuildConfig) error {
 	return m.store.Set(guildID, config)
@@ -230,13 +869,16 @@ func (m *ConfigManager) ensureVerifiedRole(session DiscordSession, config *stora
 	return m.createVerifiedRole(session, config)
 }
 
+// roleCreationLockTTL is how long the verified role creation lock lasts between renewals
+const roleCreationLockTTL = 30 * time.Second
+
 // ensureVerifiedRoleWithLock creates a verified role using distributed locking
 func (m *ConfigManager) ensureVerifiedRoleWithLock(session DiscordSession, config *storage.GuildConfig) error {
 	ctx := context.Background()
 	lockKey := fmt.Sprintf("role:create:%s:verified", config.GuildID)
 
 	// Try to acquire lock
-	lockAcquired, err := m.lockManager.AcquireLock(ctx, lockKey, 30*time.Second)
+	lockAcquired, err := m.lockManager.AcquireLock(ctx, lockKey, roleCreationLockTTL)
 	if err != nil {
 		// Failed to get lock, wait and re-check if role was created
 		m.logger.Warn("Failed to acquire lock for role creation, retrying", "guild_id", config.GuildID, "error", err)
@@ -252,8 +894,12 @@ func (m *ConfigManager) ensureVerifiedRoleWithLock(session DiscordSession, confi
 		return fmt.Errorf("could not acquire lock for role creation: %w", err)
 	}
 
-	// Always release lock when done
+	// Keep the lock while Discord is slow to answer, and always release it when done
+	stopRenewal := lock.KeepAlive(ctx, m.lockManager, lockAcquired, roleCreationLockTTL)
 	defer func() {
+		if renewErr := stopRenewal(); renewErr != nil {
+			m.logger.Warn("Lost lock while creating role", "lock_key", lockKey, "error", renewErr)
+		}
 		if releaseErr := m.lockManager.ReleaseLock(ctx, lockAcquired); releaseErr != nil {
 			m.logger.Warn("Failed to release lock", "lock_key", lockKey, "error", releaseErr)
 		}
@@ -272,10 +918,20 @@ func (m *ConfigManager) ensureVerifiedRoleWithLock(session DiscordSession, confi
 
 // createVerifiedRole creates a new verified role
 func (m *ConfigManager) createVerifiedRole(session DiscordSession, config *storage.GuildConfig) error {
+	style := config.GetRoleStyle()
 	roleData := &discordgo.RoleParams{
 		Name:  m.storageConfig.DefaultVerifiedRoleName,
 		Color: &[]int{0x00ff00}[0], // Green color
 	}
+	if style.Color != nil {
+		roleData.Color = style.Color
+	}
+	if style.Hoist {
+		roleData.Hoist = &style.Hoist
+	}
+	if style.Mentionable {
+		roleData.Mentionable = &style.Mentionable
+	}
 
 	role, err := session.GuildRoleCreate(config.GuildID, roleData)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/lock"
@@ -450,6 +451,178 @@ func TestConfigManager_DeleteGuildConfig(t *testing.T) {
 	}
 }
 
+func TestConfigManager_PendingClaims(t *testing.T) {
+	t.Parallel()
+	store := storage.NewMemoryConfigStore()
+	storageConfig := &StorageConfig{ClaimTTL: time.Hour}
+	lockManager := lock.NewNoOpLockManager()
+	logger := NewMockLogger()
+	manager := NewConfigManager(store, storageConfig, lockManager, logger)
+
+	guildID := "claims-guild-202"
+	if err := store.Set(guildID, storage.NewGuildConfig(guildID)); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	createdAt := time.Now()
+	err := manager.RecordPendingClaim(guildID, "user-1", &storage.PendingClaim{
+		Type:      "user_link",
+		Address:   "g1useraddress",
+		ClaimURL:  "https://example.com/claim",
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		t.Fatalf("RecordPendingClaim() failed: %v", err)
+	}
+
+	// Pending claim is listed with its expiry
+	claim, err := manager.GetPendingClaim(guildID, "user-1")
+	if err != nil {
+		t.Fatalf("GetPendingClaim() failed: %v", err)
+	}
+	if claim == nil {
+		t.Fatal("GetPendingClaim() should return the recorded claim")
+	}
+	if claim.Address != "g1useraddress" {
+		t.Errorf("claim Address = %q, want %q", claim.Address, "g1useraddress")
+	}
+	if !claim.ExpiresAt.Equal(createdAt.Add(time.Hour)) {
+		t.Errorf("claim ExpiresAt = %v, want %v", claim.ExpiresAt, createdAt.Add(time.Hour))
+	}
+
+	// Claims are scoped per user
+	other, err := manager.GetPendingClaim(guildID, "user-2")
+	if err != nil {
+		t.Fatalf("GetPendingClaim() failed: %v", err)
+	}
+	if other != nil {
+		t.Error("GetPendingClaim() should not return another user's claim")
+	}
+	if revoked, _ := manager.RevokePendingClaim(guildID, "user-2"); revoked {
+		t.Error("RevokePendingClaim() should not revoke another user's claim")
+	}
+
+	// Revocation removes the claim so a fresh link starts clean
+	revoked, err := manager.RevokePendingClaim(guildID, "user-1")
+	if err != nil {
+		t.Fatalf("RevokePendingClaim() failed: %v", err)
+	}
+	if !revoked {
+		t.Error("RevokePendingClaim() should report the claim as revoked")
+	}
+
+	claim, err = manager.GetPendingClaim(guildID, "user-1")
+	if err != nil {
+		t.Fatalf("GetPendingClaim() failed: %v", err)
+	}
+	if claim != nil {
+		t.Error("GetPendingClaim() should return nil after revocation")
+	}
+
+	if revoked, _ := manager.RevokePendingClaim(guildID, "user-1"); revoked {
+		t.Error("RevokePendingClaim() should be a no-op when no claim is pending")
+	}
+}
+
+func TestConfigManager_PendingClaimExpiry(t *testing.T) {
+	t.Parallel()
+	store := storage.NewMemoryConfigStore()
+	manager := NewConfigManager(store, &StorageConfig{ClaimTTL: time.Minute}, lock.NewNoOpLockManager(), NewMockLogger())
+
+	guildID := "claims-guild-303"
+	if err := store.Set(guildID, storage.NewGuildConfig(guildID)); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	err := manager.RecordPendingClaim(guildID, "user-1", &storage.PendingClaim{
+		Type:      "user_link",
+		CreatedAt: time.Now().Add(-2 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("RecordPendingClaim() failed: %v", err)
+	}
+
+	claim, err := manager.GetPendingClaim(guildID, "user-1")
+	if err != nil {
+		t.Fatalf("GetPendingClaim() failed: %v", err)
+	}
+	if claim != nil {
+		t.Error("GetPendingClaim() should not return an expired claim")
+	}
+}
+
+func TestConfigManager_LinkedRoles(t *testing.T) {
+	t.Parallel()
+	store := storage.NewMemoryConfigStore()
+	manager := NewConfigManager(store, &StorageConfig{DefaultRoleNameTemplate: "{{.Role}}"}, lock.NewNoOpLockManager(), NewMockLogger())
+
+	guildID := "linked-guild-303"
+	config := storage.NewGuildConfig(guildID)
+	if err := store.Set(guildID, config); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	if got := manager.GetRoleNameTemplate(config); got != "{{.Role}}" {
+		t.Errorf("GetRoleNameTemplate() = %q, want storage default", got)
+	}
+	config.SetString(storage.SettingRoleNameTemplate, "{{.Role}} @ {{.RealmShort}}")
+	if got := manager.GetRoleNameTemplate(config); got != "{{.Role}} @ {{.RealmShort}}" {
+		t.Errorf("GetRoleNameTemplate() = %q, want guild override", got)
+	}
+	config.SetString(storage.SettingRoleNameTemplate, "{{.Unknown}}")
+	if got := manager.GetRoleNameTemplate(config); got != "{{.Role}}" {
+		t.Errorf("GetRoleNameTemplate() = %q, want default for invalid override", got)
+	}
+
+	if err := manager.RecordLinkedRole(guildID, "gno.land/r/demo/boards", "admin", "role-1"); err != nil {
+		t.Fatalf("RecordLinkedRole() failed: %v", err)
+	}
+
+	stored, err := manager.GetGuildConfig(guildID)
+	if err != nil {
+		t.Fatalf("GetGuildConfig() failed: %v", err)
+	}
+	if roleID, ok := stored.GetLinkedRole("gno.land/r/demo/boards", "admin"); !ok || roleID != "role-1" {
+		t.Errorf("GetLinkedRole() = %q, %v, want role-1", roleID, ok)
+	}
+	if realms := stored.LinkedRealms(); len(realms) != 1 || realms[0] != "gno.land/r/demo/boards" {
+		t.Errorf("LinkedRealms() = %v, want the recorded realm", realms)
+	}
+}
+
+func TestConfigManager_RegisterRealm(t *testing.T) {
+	t.Parallel()
+	store := storage.NewMemoryConfigStore()
+	manager := NewConfigManager(store, &StorageConfig{}, lock.NewNoOpLockManager(), NewMockLogger())
+
+	guildID := "realm-guild-404"
+	if err := store.Set(guildID, storage.NewGuildConfig(guildID)); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	if _, err := manager.RegisterRealm(guildID, "gno.land/p/demo/ufmt"); !errors.Is(err, storage.ErrInvalidRealmPath) {
+		t.Errorf("RegisterRealm() error = %v, want ErrInvalidRealmPath", err)
+	}
+	if changed, err := manager.RegisterRealm(guildID, "gno.land/r/demo/events"); err != nil || !changed {
+		t.Fatalf("RegisterRealm() = %v, %v", changed, err)
+	}
+
+	stored, err := manager.GetGuildConfig(guildID)
+	if err != nil {
+		t.Fatalf("GetGuildConfig() failed: %v", err)
+	}
+	if len(stored.MonitoredRealms) != 1 || stored.MonitoredRealms[0] != "gno.land/r/demo/events" {
+		t.Errorf("MonitoredRealms = %v, want the registered realm persisted", stored.MonitoredRealms)
+	}
+
+	if changed, err := manager.UnregisterRealm(guildID, "gno.land/r/demo/events"); err != nil || !changed {
+		t.Fatalf("UnregisterRealm() = %v, %v", changed, err)
+	}
+	if stored, _ := manager.GetGuildConfig(guildID); len(stored.MonitoredRealms) != 0 || len(stored.RegisteredRealms) != 0 {
+		t.Errorf("realm should no longer be monitored, got %v / %v", stored.MonitoredRealms, stored.RegisteredRealms)
+	}
+}
+
 func TestConfigManager_GetStorageConfig(t *testing.T) {
 	t.Parallel()
 	store := storage.NewMemoryConfigStore()
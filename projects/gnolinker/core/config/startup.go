@@ -143,6 +143,20 @@ func performStorageHealthCheck(ctx context.Context, store storage.ConfigStore, c
 		}
 	}
 
+	// For Redis, check the server is reachable before the store operations
+	if config.Type == "redis" {
+		redisStore, err := storage.NewRedisConfigStore(storage.RedisConfig{URL: config.RedisURL, Prefix: config.S3Prefix})
+		if err != nil {
+			return fmt.Errorf("failed to create temporary Redis store for health check: %w", err)
+		}
+		defer redisStore.Close()
+
+		if err := redisStore.HealthCheck(ctx); err != nil {
+			return err
+		}
+		logger.Info("Redis health check passed")
+	}
+
 	// Test basic store operations
 	if err := testBasicStoreOperations(ctx, store, logger); err != nil {
 		return fmt.Errorf("basic store operations test failed: %w", err)
@@ -8,9 +8,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/storage"
 )
 
+// DefaultClaimTTL approximates the on-chain claim validity window (500 blocks)
+const DefaultClaimTTL = 30 * time.Minute
+
+// DefaultEventMaxAttempts is how many times a failing event transaction is processed before it is dead-lettered
+const DefaultEventMaxAttempts = 5
+
 // StorageConfig holds configuration for the storage backend
 type StorageConfig struct {
 	Type string
@@ -19,9 +26,12 @@ type StorageConfig struct {
 	S3Bucket   string
 	S3Region   string
 	S3Endpoint string // For Minio/Tigris - leave empty for AWS S3
-	S3Prefix   string
+	S3Prefix   string // Also the Redis key prefix
 	// Note: S3AccessKeyID and S3SecretAccessKey removed - AWS SDK handles credentials
 
+	// Redis Configuration
+	RedisURL string
+
 	// Cache Configuration
 	CacheSize int
 	CacheTTL  time.Duration
@@ -29,6 +39,36 @@ type StorageConfig struct {
 	// Default Settings
 	DefaultVerifiedRoleName string
 	AutoCreateRoles         bool
+
+	// DefaultRoleSyncPolicy applies to guilds that have not overridden the role sync policy
+	DefaultRoleSyncPolicy storage.RoleSyncPolicy
+
+	// DefaultRoleSyncOrder applies to guilds that have not overridden the role sync order
+	DefaultRoleSyncOrder storage.RoleSyncOrder
+
+	// DefaultLinkUniqueness applies to guilds that have not overridden which links conflict
+	DefaultLinkUniqueness storage.LinkUniqueness
+
+	// DefaultLinkConflictAction applies to guilds that have not overridden how link conflicts are handled
+	DefaultLinkConflictAction storage.LinkConflictAction
+
+	// CleanupDepartedMembers deletes per-member state when a member leaves a guild that has
+	// not overridden the cleanup_departed_members setting
+	CleanupDepartedMembers bool
+
+	// NotifyRoleChanges sends members a DM when an on-chain event changes their roles, in guilds
+	// that have not overridden the notify_role_changes setting
+	NotifyRoleChanges bool
+
+	// DefaultRoleNameTemplate names Discord roles created for realm roles (see core.RoleNameData)
+	DefaultRoleNameTemplate string
+
+	// ClaimTTL is how long a generated claim is shown as pending before it expires
+	ClaimTTL time.Duration
+
+	// EventMaxAttempts is how many times a transaction whose event handler fails is processed
+	// before it is dead-lettered and the query moves past it
+	EventMaxAttempts int
 }
 
 // LoadStorageConfig loads storage configuration from environment variables
@@ -43,13 +83,24 @@ func LoadStorageConfig() *StorageConfig {
 		S3Prefix:   getEnvWithDefault("GNOLINKER__STORAGE_PREFIX", "configs"),
 		// Note: AWS SDK automatically uses AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_REGION, and AWS_ENDPOINT_URL_S3/AWS_ENDPOINT_URL
 
+		// Redis Configuration
+		RedisURL: getEnvWithDefault("GNOLINKER__REDIS_URL", "redis://localhost:6379"),
+
 		// Cache Configuration
 		CacheSize: getEnvInt("GNOLINKER__CACHE_SIZE", 100),
 		CacheTTL:  getEnvDuration("GNOLINKER__CACHE_TTL", time.Hour),
 
 		// Default Settings
-		DefaultVerifiedRoleName: getEnvWithDefault("GNOLINKER__DEFAULT_VERIFIED_ROLE_NAME", "Gno-Verified"),
-		AutoCreateRoles:         getEnvBool("GNOLINKER__AUTO_CREATE_ROLES", true),
+		DefaultVerifiedRoleName:   getEnvWithDefault("GNOLINKER__DEFAULT_VERIFIED_ROLE_NAME", "Gno-Verified"),
+		AutoCreateRoles:           getEnvBool("GNOLINKER__AUTO_CREATE_ROLES", true),
+		DefaultRoleSyncPolicy:     getEnvRoleSyncPolicy("GNOLINKER__ROLE_SYNC_POLICY", storage.RoleSyncPolicyStrict),
+		DefaultRoleSyncOrder:      getEnvRoleSyncOrder("GNOLINKER__ROLE_SYNC_ORDER", storage.RoleSyncOrderInterleaved),
+		DefaultLinkUniqueness:     getEnvLinkUniqueness("GNOLINKER__LINK_UNIQUENESS", storage.LinkUniquenessOff),
+		DefaultLinkConflictAction: getEnvLinkConflictAction("GNOLINKER__LINK_CONFLICT_ACTION", storage.LinkConflictActionWarn),
+		DefaultRoleNameTemplate:   getEnvWithDefault("GNOLINKER__ROLE_NAME_TEMPLATE", core.DefaultRoleNameTemplate),
+		NotifyRoleChanges:         getEnvBool("GNOLINKER__NOTIFY_ROLE_CHANGES", false),
+		ClaimTTL:                  getEnvDuration("GNOLINKER__CLAIM_TTL", DefaultClaimTTL),
+		EventMaxAttempts:          getEnvInt("GNOLINKER__EVENT_MAX_ATTEMPTS", DefaultEventMaxAttempts),
 	}
 }
 
@@ -71,6 +122,16 @@ func (c *StorageConfig) CreateConfigStore(ctx context.Context) (storage.ConfigSt
 			return nil, fmt.Errorf("failed to create S3 config store: %w", err)
 		}
 
+	case "redis":
+		redisConfig := storage.RedisConfig{
+			URL:    c.RedisURL,
+			Prefix: c.S3Prefix,
+		}
+		baseStore, err = storage.NewRedisConfigStore(redisConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Redis config store: %w", err)
+		}
+
 	case "memory":
 		baseStore = storage.NewMemoryConfigStore()
 
@@ -94,18 +155,61 @@ func (c *StorageConfig) CreateConfigStore(ctx context.Context) (storage.ConfigSt
 	return baseStore, nil
 }
 
+// CreateAuditStore creates the store role change audit entries are written to, in the same backend
+// as the guild configs
+func (c *StorageConfig) CreateAuditStore(ctx context.Context) (storage.AuditStore, error) {
+	switch strings.ToLower(c.Type) {
+	case "s3":
+		s3Config := storage.S3Config{
+			Bucket:   c.S3Bucket,
+			Region:   c.S3Region,
+			Endpoint: c.S3Endpoint,
+			Prefix:   c.S3Prefix,
+		}
+		store, err := storage.NewS3ConfigStore(ctx, s3Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create S3 audit store: %w", err)
+		}
+		return store.AuditStore(), nil
+
+	case "redis":
+		redisConfig := storage.RedisConfig{
+			URL:    c.RedisURL,
+			Prefix: c.S3Prefix,
+		}
+		store, err := storage.NewRedisConfigStore(redisConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Redis audit store: %w", err)
+		}
+		return store.AuditStore(), nil
+
+	case "memory":
+		return storage.NewMemoryAuditStore(), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", c.Type)
+	}
+}
+
 // GetMinioLocalConfig returns a pre-configured StorageConfig for local Minio development
 func GetMinioLocalConfig() *StorageConfig {
 	return &StorageConfig{
-		Type:                    "s3",
-		S3Bucket:                "gnolinker-dev",
-		S3Region:                "us-east-1",
-		S3Endpoint:              "http://localhost:9000",
-		S3Prefix:                "configs",
-		CacheSize:               50,
-		CacheTTL:                30 * time.Minute,
-		DefaultVerifiedRoleName: "Gno-Verified",
-		AutoCreateRoles:         true,
+		Type:                      "s3",
+		S3Bucket:                  "gnolinker-dev",
+		S3Region:                  "us-east-1",
+		S3Endpoint:                "http://localhost:9000",
+		S3Prefix:                  "configs",
+		CacheSize:                 50,
+		CacheTTL:                  30 * time.Minute,
+		DefaultVerifiedRoleName:   "Gno-Verified",
+		AutoCreateRoles:           true,
+		DefaultRoleSyncPolicy:     storage.RoleSyncPolicyStrict,
+		DefaultRoleSyncOrder:      storage.RoleSyncOrderInterleaved,
+		DefaultLinkUniqueness:     storage.LinkUniquenessOff,
+		DefaultLinkConflictAction: storage.LinkConflictActionWarn,
+		DefaultRoleNameTemplate:   core.DefaultRoleNameTemplate,
+		ClaimTTL:                  DefaultClaimTTL,
+		EventMaxAttempts:          DefaultEventMaxAttempts,
 		// Note: AWS_ACCESS_KEY_ID=minioadmin and AWS_SECRET_ACCESS_KEY=minioadmin should be set as env vars
 	}
 }
@@ -113,15 +217,22 @@ func GetMinioLocalConfig() *StorageConfig {
 // GetTigrisProductionConfig returns a pre-configured StorageConfig for Tigris on Fly.io
 func GetTigrisProductionConfig() *StorageConfig {
 	return &StorageConfig{
-		Type:                    "s3",
-		S3Bucket:                getEnvWithDefault("GNOLINKER__STORAGE_BUCKET", "gnolinker-prod"),
-		S3Region:                "auto", // Tigris uses "auto" region
-		S3Endpoint:              "https://fly.storage.tigris.dev",
-		S3Prefix:                "configs",
-		CacheSize:               200,
-		CacheTTL:                time.Hour,
-		DefaultVerifiedRoleName: "Gno-Verified",
-		AutoCreateRoles:         true,
+		Type:                      "s3",
+		S3Bucket:                  getEnvWithDefault("GNOLINKER__STORAGE_BUCKET", "gnolinker-prod"),
+		S3Region:                  "auto", // Tigris uses "auto" region
+		S3Endpoint:                "https://fly.storage.tigris.dev",
+		S3Prefix:                  "configs",
+		CacheSize:                 200,
+		CacheTTL:                  time.Hour,
+		DefaultVerifiedRoleName:   "Gno-Verified",
+		AutoCreateRoles:           true,
+		DefaultRoleSyncPolicy:     storage.RoleSyncPolicyStrict,
+		DefaultRoleSyncOrder:      storage.RoleSyncOrderInterleaved,
+		DefaultLinkUniqueness:     storage.LinkUniquenessOff,
+		DefaultLinkConflictAction: storage.LinkConflictActionWarn,
+		DefaultRoleNameTemplate:   core.DefaultRoleNameTemplate,
+		ClaimTTL:                  DefaultClaimTTL,
+		EventMaxAttempts:          DefaultEventMaxAttempts,
 		// Note: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY env vars used automatically by AWS SDK
 	}
 }
@@ -184,3 +295,39 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvRoleSyncPolicy(key string, defaultValue storage.RoleSyncPolicy) storage.RoleSyncPolicy {
+	if value := os.Getenv(key); value != "" {
+		if parsed, ok := storage.ParseRoleSyncPolicy(value); ok {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvRoleSyncOrder(key string, defaultValue storage.RoleSyncOrder) storage.RoleSyncOrder {
+	if value := os.Getenv(key); value != "" {
+		if parsed, ok := storage.ParseRoleSyncOrder(value); ok {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvLinkUniqueness(key string, defaultValue storage.LinkUniqueness) storage.LinkUniqueness {
+	if value := os.Getenv(key); value != "" {
+		if parsed, ok := storage.ParseLinkUniqueness(value); ok {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvLinkConflictAction(key string, defaultValue storage.LinkConflictAction) storage.LinkConflictAction {
+	if value := os.Getenv(key); value != "" {
+		if parsed, ok := storage.ParseLinkConflictAction(value); ok {
+			return parsed
+		}
+	}
+	return defaultValue
+}
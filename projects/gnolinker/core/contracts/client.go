@@ -8,9 +8,23 @@ import (
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/gnolang/gno/gno.land/pkg/gnoclient"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 )
 
+// ErrUnexpectedResult is returned when a realm answers a query with a value that cannot be parsed
+var ErrUnexpectedResult = errors.New("unexpected query result")
+
+// IsTransient reports whether a query error may succeed when retried: an RPC or network failure
+// rather than an answer from the realm, such as a realm error or an unparsable result
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, ErrUnexpectedResult) {
+		return false
+	}
+	var realmErr abci.Error
+	return !errors.As(err, &realmErr)
+}
+
 // GnoClient wraps a gnoclient for contract interactions
 type GnoClient struct {
 	client gnoclient.Client
@@ -66,6 +80,42 @@ func (c *GnoClient) GetLinkedAddress(platformID string) (string, error) {
 	return address, nil
 }
 
+// ListLinkedAddresses returns every Gno address linked to a platform user ID, for user contracts
+// declaring ListLinkedAddressesJSON(platformID string) string returning them as a JSON array
+func (c *GnoClient) ListLinkedAddresses(platformID string) ([]string, error) {
+	query := fmt.Sprintf(`ListLinkedAddressesJSON("%v")`, platformID)
+	contractPath := "gno.land/" + c.config.UserContract
+
+	c.logger.Debug("Querying ListLinkedAddressesJSON", "platform_id", platformID, "contract", contractPath, "query", query)
+
+	result, _, err := c.client.QEval(contractPath, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list linked addresses: %w", err)
+	}
+
+	addresses, err := parseStringList(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ListLinkedAddressesJSON returned %s: %w", ErrUnexpectedResult, result, err)
+	}
+	return addresses, nil
+}
+
+// GetLinkedPlatformID returns the platform user ID linked to a Gno address, or "" if the address is not linked
+func (c *GnoClient) GetLinkedPlatformID(address string) (string, error) {
+	query := fmt.Sprintf(`GetLinkedDiscordID("%v")`, address)
+	contractPath := "gno.land/" + c.config.UserContract
+
+	c.logger.Debug("Querying GetLinkedDiscordID", "address", address, "contract", contractPath, "query", query)
+
+	result, _, err := c.client.QEval(contractPath, query)
+	if err != nil {
+		c.logger.Error("GetLinkedDiscordID query failed", "error", err, "address", address, "contract", contractPath)
+		return "", fmt.Errorf("failed to get linked platform ID: %w", err)
+	}
+
+	return parseGnoString(result), nil
+}
+
 // GetLinkedRole returns the role mapping for a specific realm role
 func (c *GnoClient) GetLinkedRole(realmPath, roleName, platformGuildID string) (*core.RoleMapping, error) {
 	query := fmt.Sprintf(`GetLinkedDiscordRoleJSON("%v", "%v", "%v")`, realmPath, roleName, platformGuildID)
@@ -73,7 +123,11 @@ func (c *GnoClient) GetLinkedRole(realmPath, roleName, platformGuildID string) (
 	if err != nil {
 		return nil, fmt.Errorf("failed to get linked role: %w", err)
 	}
-	return parseLinkedRole(result)
+	role, err := parseLinkedRole(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnexpectedResult, err)
+	}
+	return role, nil
 }
 
 // ListLinkedRoles returns all role mappings for a realm
@@ -94,7 +148,7 @@ func (c *GnoClient) ListLinkedRoles(realmPath, platformGuildID string) ([]*core.
 	roles, err := parseLinkedRoles(result)
 	if err != nil {
 		c.logger.Error("Failed to parse linked roles", "error", err, "raw_result", result)
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrUnexpectedResult, err)
 	}
 
 	c.logger.Info("ListLinkedRoles parsed", "realm_path", realmPath, "guild_id", platformGuildID, "role_count", len(roles))
@@ -120,7 +174,7 @@ func (c *GnoClient) ListAllRolesByGuild(platformGuildID string) ([]*core.RoleMap
 	roles, err := parseLinkedRoles(result)
 	if err != nil {
 		c.logger.Error("Failed to parse all roles by guild", "error", err, "raw_result", result)
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrUnexpectedResult, err)
 	}
 
 	c.logger.Info("ListAllRolesByGuild parsed", "guild_id", platformGuildID, "role_count", len(roles))
@@ -148,6 +202,46 @@ func (c *GnoClient) HasRole(realmPath, roleName, address string) (bool, error) {
 	return isMember, nil
 }
 
+// ListRoleMembers returns the addresses holding a role in the realm, which must declare
+// RoleMembersJSON(roleName string) string returning them as a JSON array
+func (c *GnoClient) ListRoleMembers(realmPath, roleName string) ([]string, error) {
+	query := fmt.Sprintf(`RoleMembersJSON("%v")`, roleName)
+
+	c.logger.Debug("Querying RoleMembersJSON", "realm_path", realmPath, "role_name", roleName, "query", query)
+
+	result, _, err := c.client.QEval(realmPath, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role members: %w", err)
+	}
+
+	members, err := parseStringList(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: RoleMembersJSON returned %s: %w", ErrUnexpectedResult, result, err)
+	}
+	return members, nil
+}
+
+// EvalPredicate calls a realm function with an address, which must return a bool
+func (c *GnoClient) EvalPredicate(realmPath, function, address string) (bool, error) {
+	query := fmt.Sprintf(`%s("%v")`, function, address)
+
+	c.logger.Debug("Querying realm predicate", "realm_path", realmPath, "query", query)
+
+	result, _, err := c.client.QEval(realmPath, query)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate %s.%s: %w", realmPath, function, err)
+	}
+
+	switch result {
+	case "(true bool)":
+		return true, nil
+	case "(false bool)":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %s.%s returned %s, not a bool", ErrUnexpectedResult, realmPath, function, result)
+	}
+}
+
 // GetCurrentBlockHeight returns the current block height from the chain
 func (c *GnoClient) GetCurrentBlockHeight() (int64, error) {
 	status, err := c.client.RPCClient.Status()
@@ -172,6 +266,37 @@ func parseGnoAddress(s string) string {
 	return s
 }
 
+func parseGnoString(s string) string {
+	s, found := strings.CutPrefix(s, `("`)
+	if !found {
+		return ""
+	}
+	s, found = strings.CutSuffix(s, `" string)`)
+	if !found {
+		return ""
+	}
+	return s
+}
+
+// parseStringList parses a JSON array of strings returned as a Gno string
+func parseStringList(s string) ([]string, error) {
+	s, found := strings.CutPrefix(s, `("`)
+	if !found {
+		return nil, errors.New("parsing error: prefix not found")
+	}
+	s, found = strings.CutSuffix(s, `" string)`)
+	if !found {
+		return nil, errors.New("parsing error: suffix not found")
+	}
+	s = strings.ReplaceAll(s, `\`, "")
+
+	var list []string
+	if err := json.Unmarshal([]byte(s), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 // LinkedRoleJSON is the JSON structure returned by the contract
 type LinkedRoleJSON struct {
 	RealmPath      string
@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+// RoleMutation is a role grant or removal made by the event handlers, or planned in a dry run
+type RoleMutation struct {
+	GuildID   string
+	UserID    string
+	RoleID    string
+	Action    storage.AuditAction
+	RealmPath string // Empty for the verified and pending roles
+	RealmRole string
+}
+
+// mutationLog collects the role mutations of a verification run or an on-chain event.
+// A nil mutationLog discards them.
+type mutationLog struct {
+	mu        sync.Mutex
+	mutations []RoleMutation
+}
+
+func (l *mutationLog) record(mutation RoleMutation) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mutations = append(l.mutations, mutation)
+}
+
+func (l *mutationLog) list() []RoleMutation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.mutations
+}
+
+// SetDryRun makes every role change log and collect what it would do instead of calling the
+// platform, so admins can preview verification on a large guild without touching anyone's roles
+func (eh *EventHandlers) SetDryRun(dryRun bool) {
+	eh.dryRun = dryRun
+}
+
+// skipInDryRun collects and logs a role change instead of applying it when in dry-run mode, and
+// reports whether the caller must skip the change
+func (eh *EventHandlers) skipInDryRun(action storage.AuditAction, guildID, userID, roleID, realmPath, realmRole string) bool {
+	if !eh.dryRun {
+		return false
+	}
+	eh.mutations.record(RoleMutation{
+		GuildID:   guildID,
+		UserID:    userID,
+		RoleID:    roleID,
+		Action:    action,
+		RealmPath: realmPath,
+		RealmRole: realmRole,
+	})
+	eh.logger.Info("Dry run: skipping role change",
+		"guild_id", guildID,
+		"user_id", userID,
+		"role_id", roleID,
+		"action", action,
+		"realm_path", realmPath,
+		"realm_role", realmRole,
+	)
+	return true
+}
+
+// VerificationReport summarizes a verification run over all members of a guild
+type VerificationReport struct {
+	DryRun    bool
+	Checked   int
+	Failed    int
+	Mutations []RoleMutation
+}
+
+// VerifyGuild runs the 4-state verification for every present member of a guild and reports the
+// role changes it made. With dryRun, or when the handlers are in dry-run mode, the changes are
+// only reported: the platform is not called and no guild state is updated.
+func (eh *EventHandlers) VerifyGuild(ctx context.Context, guildID string, dryRun bool) (*VerificationReport, error) {
+	scoped := *eh
+	scoped.dryRun = eh.dryRun || dryRun
+	scoped.mutations = &mutationLog{}
+	scoped.realmRoles = newRealmRoleCache(eh.roleLinkingFlow, eh.logger)
+	defer eh.trackAPICalls("guild verification", "guild_id", guildID, "dry_run", scoped.dryRun)()
+
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild config: %w", err)
+	}
+	members, err := eh.directory().GuildMembers(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild members: %w", err)
+	}
+
+	report := &VerificationReport{DryRun: scoped.dryRun}
+	members = presentMembers(config, members)
+	for i, member := range members {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		report.Checked++
+		if err := scoped.processUserVerification(ctx, guildID, member); err != nil {
+			eh.logger.Error("Failed to verify user", "guild_id", guildID, "user_id", member.User.ID, "dry_run", scoped.dryRun, "error", err)
+			report.Failed++
+		}
+		eh.logger.Info("Guild verification progress", "guild_id", guildID, "verified", i+1, "total", len(members), "failed", report.Failed)
+	}
+	report.Mutations = scoped.mutations.list()
+
+	eh.logger.Info("Completed guild verification",
+		"guild_id", guildID,
+		"dry_run", report.DryRun,
+		"checked", report.Checked,
+		"failed", report.Failed,
+		"role_changes", len(report.Mutations),
+	)
+	return report, nil
+}
@@ -0,0 +1,135 @@
+package events
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestProcessUserVerification_DryRun(t *testing.T) {
+	grant := func(roleID, realmRole string) RoleMutation {
+		m := RoleMutation{GuildID: testGuildID, UserID: testUserID, RoleID: roleID, Action: storage.AuditActionGrant}
+		if realmRole != "" {
+			m.RealmPath, m.RealmRole = testRealmPath, realmRole
+		}
+		return m
+	}
+	revoke := func(roleID, realmRole string) RoleMutation {
+		m := grant(roleID, realmRole)
+		m.Action = storage.AuditActionRevoke
+		return m
+	}
+
+	tests := []struct {
+		name       string
+		roles      []string // Discord roles the user holds
+		registered bool
+		realmRole  bool
+		want       []RoleMutation
+	}{
+		{
+			name:  "state 1: verified but unregistered loses its roles",
+			roles: []string{testVerifiedID, testMemberRole},
+			want:  []RoleMutation{revoke(testVerifiedID, ""), revoke(testMemberRole, "member")},
+		},
+		{
+			name:       "state 2: verified and registered syncs realm roles",
+			roles:      []string{testVerifiedID},
+			registered: true,
+			realmRole:  true,
+			want:       []RoleMutation{grant(testMemberRole, "member")},
+		},
+		{
+			name:  "state 3: unverified and unregistered loses realm roles",
+			roles: []string{testMemberRole},
+			want:  []RoleMutation{revoke(testMemberRole, "member")},
+		},
+		{
+			name:       "state 4: registered but unverified is verified and synced",
+			registered: true,
+			realmRole:  true,
+			want:       []RoleMutation{grant(testVerifiedID, ""), grant(testMemberRole, "member")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := func(dryRun bool) (*mockPlatform, []RoleMutation, *storage.GuildConfig) {
+				eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+				for _, roleID := range tt.roles {
+					_ = platform.AddRole(testGuildID, testUserID, roleID)
+				}
+				platform.ops = nil
+				if !tt.registered {
+					delete(eh.userLinkingFlow.(*mockUserLinkingFlow).addresses, testUserID)
+				}
+				if tt.realmRole {
+					eh.roleLinkingFlow.(*mockRoleLinkingFlow).members[testRealmPath+":member"] = []string{testAddress}
+				}
+				eh.SetDryRun(dryRun)
+				eh.mutations = &mutationLog{}
+
+				if err := eh.processUserVerification(t.Context(), testGuildID, testMember()); err != nil {
+					t.Fatalf("processUserVerification() error = %v", err)
+				}
+				guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+				return platform, eh.mutations.list(), guildConfig
+			}
+
+			platform, planned, guildConfig := run(true)
+			if len(platform.ops) != 0 {
+				t.Errorf("dry run mutated roles: %v", platform.ops)
+			}
+			if len(guildConfig.BotAssignedRoles) != 0 {
+				t.Errorf("dry run recorded bot-assigned roles: %v", guildConfig.BotAssignedRoles)
+			}
+			if !slices.Equal(planned, tt.want) {
+				t.Errorf("dry run planned %+v, want %+v", planned, tt.want)
+			}
+
+			// The real run makes exactly the changes the dry run reported
+			_, applied, _ := run(false)
+			if !slices.Equal(applied, planned) {
+				t.Errorf("real run applied %+v, dry run planned %+v", applied, planned)
+			}
+		})
+	}
+}
+
+func TestVerifyGuild_DryRun(t *testing.T) {
+	eh, platform, _ := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	eh.roleLinkingFlow.(*mockRoleLinkingFlow).members[testRealmPath+":member"] = []string{testAddress}
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: membersTransport{members: []*discordgo.Member{testMember()}}}
+	eh.session = session
+
+	report, err := eh.VerifyGuild(t.Context(), testGuildID, true)
+	if err != nil {
+		t.Fatalf("VerifyGuild() error = %v", err)
+	}
+	if !report.DryRun || report.Checked != 1 || report.Failed != 0 || len(report.Mutations) != 2 {
+		t.Errorf("report = %+v, want a dry run planning the verified and member roles", report)
+	}
+	if len(platform.ops) != 0 {
+		t.Errorf("dry run mutated roles: %v", platform.ops)
+	}
+	if eh.dryRun {
+		t.Error("a dry-run verification should not put the handlers in dry-run mode")
+	}
+
+	// Dry-run mode also applies to role syncs, which report the changes they would make
+	eh.SetDryRun(true)
+	result, err := eh.ResyncRole(testGuildID, testRealmPath, "member", testMemberRole)
+	if err != nil {
+		t.Fatalf("ResyncRole() error = %v", err)
+	}
+	if !slices.Equal(result.Added, []string{testUserID}) || len(platform.ops) != 0 {
+		t.Errorf("dry-run resync added %v with ops %v, want the user planned and no ops", result.Added, platform.ops)
+	}
+}
@@ -2,11 +2,14 @@ package events
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/metrics"
 	"github.com/allinbits/labs/projects/gnolinker/core/storage"
 	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
 	"github.com/allinbits/labs/projects/gnolinker/platforms"
@@ -17,9 +20,23 @@ type EventHandlers struct {
 	platform        platforms.Platform
 	configManager   *config.ConfigManager
 	session         *discordgo.Session
+	members         MemberDirectory
 	logger          core.Logger
 	userLinkingFlow workflows.UserLinkingWorkflow
 	roleLinkingFlow workflows.RoleLinkingWorkflow
+	apiCalls        *core.APICallCounter
+	metrics         *metrics.Metrics
+	auditLog        storage.AuditLog
+	userGuilds      *userGuildsCache
+	dryRun          bool
+	// mutations collects the role changes of a guild verification run for its report, or of an
+	// on-chain event for the affected members' DMs
+	mutations *mutationLog
+	// txHash and correlationID identify the event being handled, for the audit log
+	txHash        string
+	correlationID string
+	// realmRoles caches realm role membership during a verification pass
+	realmRoles *realmRoleCache
 }
 
 func NewEventHandlers(platform platforms.Platform, configManager *config.ConfigManager, session *discordgo.Session, logger core.Logger, userLinkingFlow workflows.UserLinkingWorkflow, roleLinkingFlow workflows.RoleLinkingWorkflow) *EventHandlers {
@@ -30,13 +47,109 @@ func NewEventHandlers(platform platforms.Platform, configManager *config.ConfigM
 		logger:          logger,
 		userLinkingFlow: userLinkingFlow,
 		roleLinkingFlow: roleLinkingFlow,
+		userGuilds:      newUserGuildsCache(userGuildsCacheTTL),
 	}
 }
 
-func (eh *EventHandlers) HandleUserLinked(event Event) error {
+// SetAPICallCounter enables logging of the platform API calls made per event and verification sweep
+func (eh *EventHandlers) SetAPICallCounter(counter *core.APICallCounter) {
+	eh.apiCalls = counter
+}
+
+// SetMetrics enables recording of event and verification metrics
+func (eh *EventHandlers) SetMetrics(m *metrics.Metrics) {
+	eh.metrics = m
+}
+
+// SetAuditLog enables recording every role grant and revoke in the audit log
+func (eh *EventHandlers) SetAuditLog(auditLog storage.AuditLog) {
+	eh.auditLog = auditLog
+}
+
+// audit records a role change made by the bot, tagged with the event that caused it, if any
+func (eh *EventHandlers) audit(action storage.AuditAction, guildID, userID, roleID, realmPath, realmRole string) {
+	eh.mutations.record(RoleMutation{
+		GuildID:   guildID,
+		UserID:    userID,
+		RoleID:    roleID,
+		Action:    action,
+		RealmPath: realmPath,
+		RealmRole: realmRole,
+	})
+	if eh.auditLog == nil {
+		return
+	}
+	eh.auditLog.Record(storage.AuditEntry{
+		Time:          time.Now(),
+		GuildID:       guildID,
+		UserID:        userID,
+		RoleID:        roleID,
+		Action:        action,
+		RealmPath:     realmPath,
+		RealmRole:     realmRole,
+		TxHash:        eh.txHash,
+		CorrelationID: eh.correlationID,
+	})
+}
+
+// getMetrics returns the metrics to record, or nil when they are disabled
+func (eh *EventHandlers) getMetrics() *metrics.Metrics {
+	if eh == nil {
+		return nil
+	}
+	return eh.metrics
+}
+
+// trackAPICalls starts counting platform API calls for an operation; calling the
+// returned function logs the calls made since. It is a no-op without a counter.
+func (eh *EventHandlers) trackAPICalls(operation string, args ...any) func() {
+	if eh.apiCalls == nil {
+		return func() {}
+	}
+
+	before := eh.apiCalls.Snapshot()
+	return func() {
+		calls := eh.apiCalls.Since(before)
+		var total int64
+		for _, n := range calls {
+			total += n
+		}
+		args = append(args, "operation", operation, "total_calls", total, "calls", calls)
+		eh.logger.Info("Platform API calls", args...)
+	}
+}
+
+// withCorrelationID returns a copy of the handlers whose logger tags every line with the
+// event's correlation ID, so all log lines for one event can be traced end to end
+func (eh *EventHandlers) withCorrelationID(event *Event) *EventHandlers {
+	if event.CorrelationID == "" {
+		event.CorrelationID = core.NewCorrelationID()
+	}
+
+	scoped := *eh
+	scoped.logger = eh.logger.With(core.CorrelationIDKey, event.CorrelationID)
+	scoped.txHash = event.TransactionHash
+	scoped.correlationID = event.CorrelationID
+	return &scoped
+}
+
+// eventMaxAttempts returns how many times a transaction whose events fail to be handled is
+// processed before it is dead-lettered
+func (eh *EventHandlers) eventMaxAttempts() int {
+	if eh == nil || eh.configManager == nil {
+		return config.DefaultEventMaxAttempts
+	}
+	return eh.configManager.GetEventMaxAttempts()
+}
+
+func (eh *EventHandlers) HandleUserLinked(event Event) (err error) {
+	defer func() { eh.getMetrics().EventProcessed(string(UserLinkedEvent), err) }()
 	if event.UserLinked == nil {
 		return fmt.Errorf("UserLinked event data is nil")
 	}
+	eh = eh.withCorrelationID(&event)
+	defer eh.notifyRoleChanges(&event)()
+	defer eh.trackAPICalls("UserLinked event", "tx_hash", event.TransactionHash)()
 
 	userLinked := event.UserLinked
 	eh.logger.Info("Processing UserLinked event",
@@ -58,6 +171,23 @@ func (eh *EventHandlers) HandleUserLinked(event Event) error {
 	}
 
 	for _, guild := range guilds {
+		if eh.guildPaused(guild.ID) {
+			eh.logger.Info("Guild processing paused, skipping UserLinked role changes", "guild_id", guild.ID, "discord_id", userLinked.DiscordID)
+			continue
+		}
+
+		// The claim has been completed on-chain, so it is no longer pending
+		if _, err := eh.configManager.RevokePendingClaim(guild.ID, userLinked.DiscordID); err != nil {
+			eh.logger.Warn("Failed to clear pending claim", "guild_id", guild.ID, "discord_id", userLinked.DiscordID, "error", err)
+		}
+		if err := eh.removePendingRoleFromUser(guild.ID, userLinked.DiscordID); err != nil {
+			eh.logger.Warn("Failed to remove pending role", "guild_id", guild.ID, "discord_id", userLinked.DiscordID, "error", err)
+		}
+
+		if held := eh.checkLinkConflict(guild.ID, userLinked.DiscordID, userLinked.Address); held {
+			continue
+		}
+
 		if err := eh.addVerifiedRoleToUser(guild.ID, userLinked.DiscordID); err != nil {
 			eh.logger.Error("Failed to add verified role to user",
 				"guild_id", guild.ID,
@@ -71,6 +201,7 @@ func (eh *EventHandlers) HandleUserLinked(event Event) error {
 			"guild_id", guild.ID,
 			"discord_id", userLinked.DiscordID,
 		)
+		eh.applyVerifiedRoleRules(guild.ID, userLinked.DiscordID, userLinked.Address)
 
 		// NEW: Immediately sync all realm roles for this user
 		if err := eh.syncUserRealmRoles(guild.ID, userLinked.DiscordID, userLinked.Address); err != nil {
@@ -93,10 +224,14 @@ func (eh *EventHandlers) HandleUserLinked(event Event) error {
 	return nil
 }
 
-func (eh *EventHandlers) HandleUserUnlinked(event Event) error {
+func (eh *EventHandlers) HandleUserUnlinked(event Event) (err error) {
+	defer func() { eh.getMetrics().EventProcessed(string(UserUnlinkedEvent), err) }()
 	if event.UserUnlinked == nil {
 		return fmt.Errorf("UserUnlinked event data is nil")
 	}
+	eh = eh.withCorrelationID(&event)
+	defer eh.notifyRoleChanges(&event)()
+	defer eh.trackAPICalls("UserUnlinked event", "tx_hash", event.TransactionHash)()
 
 	userUnlinked := event.UserUnlinked
 	eh.logger.Info("Processing UserUnlinked event",
@@ -114,6 +249,22 @@ func (eh *EventHandlers) HandleUserUnlinked(event Event) error {
 	}
 
 	for _, guild := range guilds {
+		if eh.guildPaused(guild.ID) {
+			eh.logger.Info("Guild processing paused, skipping UserUnlinked role changes", "guild_id", guild.ID, "discord_id", userUnlinked.DiscordID)
+			continue
+		}
+
+		// The claim has been completed on-chain, so it is no longer pending
+		if _, err := eh.configManager.RevokePendingClaim(guild.ID, userUnlinked.DiscordID); err != nil {
+			eh.logger.Warn("Failed to clear pending claim", "guild_id", guild.ID, "discord_id", userUnlinked.DiscordID, "error", err)
+		}
+		if err := eh.removePendingRoleFromUser(guild.ID, userUnlinked.DiscordID); err != nil {
+			eh.logger.Warn("Failed to remove pending role", "guild_id", guild.ID, "discord_id", userUnlinked.DiscordID, "error", err)
+		}
+		if err := eh.configManager.ForgetUserLink(guild.ID, userUnlinked.DiscordID); err != nil {
+			eh.logger.Warn("Failed to forget linked address", "guild_id", guild.ID, "discord_id", userUnlinked.DiscordID, "error", err)
+		}
+
 		if err := eh.removeVerifiedRoleFromUser(guild.ID, userUnlinked.DiscordID); err != nil {
 			eh.logger.Error("Failed to remove verified role from user",
 				"guild_id", guild.ID,
@@ -127,6 +278,7 @@ func (eh *EventHandlers) HandleUserUnlinked(event Event) error {
 			"guild_id", guild.ID,
 			"discord_id", userUnlinked.DiscordID,
 		)
+		eh.applyVerifiedRoleRules(guild.ID, userUnlinked.DiscordID, "")
 
 		// NEW: Remove all realm-based Discord roles from this user
 		if err := eh.removeAllRealmRoles(guild.ID, userUnlinked.DiscordID); err != nil {
@@ -147,21 +299,37 @@ func (eh *EventHandlers) HandleUserUnlinked(event Event) error {
 	return nil
 }
 
-func (eh *EventHandlers) getUserGuilds(userID string) ([]*discordgo.Guild, error) {
-	var userGuilds []*discordgo.Guild
-
-	for _, guild := range eh.session.State.Guilds {
-		member, err := eh.session.GuildMember(guild.ID, userID)
-		if err != nil {
-			continue
-		}
+// guildPaused reports whether an admin paused a guild's processing, which also holds back the
+// role changes other guilds' processors would make in it for cross-guild events
+func (eh *EventHandlers) guildPaused(guildID string) bool {
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	return err == nil && config.Paused
+}
 
-		if member != nil {
-			userGuilds = append(userGuilds, guild)
-		}
+// checkLinkConflict records a user's link in a guild and reports any conflict with the guild's
+// link uniqueness rules, returning true if roles must be withheld pending admin review
+func (eh *EventHandlers) checkLinkConflict(guildID, discordID, address string) bool {
+	conflict, err := eh.configManager.RecordUserLink(guildID, discordID, address)
+	if err != nil {
+		eh.logger.Warn("Failed to record linked address", "guild_id", guildID, "discord_id", discordID, "error", err)
+		return false
+	}
+	if conflict == nil {
+		return false
 	}
 
-	return userGuilds, nil
+	eh.getMetrics().LinkConflict(string(conflict.Kind))
+	eh.logger.Warn("Link conflict detected",
+		"audit", "link_conflict",
+		"guild_id", guildID,
+		"discord_id", discordID,
+		"gno_address", address,
+		"kind", conflict.Kind,
+		"conflicting_discord_id", conflict.ConflictingUserID,
+		"conflicting_address", conflict.ConflictingAddress,
+		"held", conflict.Held,
+	)
+	return conflict.Held
 }
 
 func (eh *EventHandlers) addVerifiedRoleToUser(guildID, userID string) error {
@@ -194,7 +362,7 @@ func (eh *EventHandlers) addVerifiedRoleToUser(guildID, userID string) error {
 	}
 
 	eh.logger.Info("Adding role to user", "guild_id", guildID, "user_id", userID, "role_id", config.VerifiedRoleID)
-	err = eh.platform.AddRole(guildID, userID, config.VerifiedRoleID)
+	err = eh.addManagedRole(guildID, userID, config.VerifiedRoleID, "", "")
 	if err != nil {
 		eh.logger.Error("Failed to add role to user", "guild_id", guildID, "user_id", userID, "role_id", config.VerifiedRoleID, "error", err)
 		return fmt.Errorf("failed to add role: %w", err)
@@ -225,7 +393,179 @@ func (eh *EventHandlers) removeVerifiedRoleFromUser(guildID, userID string) erro
 		return nil
 	}
 
-	return eh.platform.RemoveRole(guildID, userID, config.VerifiedRoleID)
+	_, err = eh.removeManagedRole(guildID, userID, config.VerifiedRoleID, "", "")
+	return err
+}
+
+// removePendingRoleFromUser removes the guild's pending verification role, if configured and held.
+// The pending role only reflects an in-flight claim, so it is removed regardless of the role sync policy.
+func (eh *EventHandlers) removePendingRoleFromUser(guildID, userID string) error {
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	if !config.HasPendingRole() {
+		return nil
+	}
+
+	hasRole, err := eh.platform.HasRole(guildID, userID, config.PendingRoleID)
+	if err != nil {
+		return fmt.Errorf("failed to check if user has role: %w", err)
+	}
+	if !hasRole {
+		return nil
+	}
+
+	if eh.skipInDryRun(storage.AuditActionRevoke, guildID, userID, config.PendingRoleID, "", "") {
+		return nil
+	}
+	if err := eh.platform.RemoveRole(guildID, userID, config.PendingRoleID); err != nil {
+		return fmt.Errorf("failed to remove pending role: %w", err)
+	}
+	eh.audit(storage.AuditActionRevoke, guildID, userID, config.PendingRoleID, "", "")
+
+	eh.logger.Info("Removed pending role from user", "guild_id", guildID, "user_id", userID, "role_id", config.PendingRoleID)
+	return nil
+}
+
+// CleanupExpiredPendingClaims drops expired pending claims for a guild and
+// removes the pending role from users whose claim expired without being completed
+func (eh *EventHandlers) CleanupExpiredPendingClaims(guildID string) error {
+	expired, err := eh.configManager.ExpirePendingClaims(guildID)
+	if err != nil {
+		return err
+	}
+
+	for userID, claim := range expired {
+		if claim == nil || !claim.RoleGranted {
+			continue
+		}
+		if err := eh.removePendingRoleFromUser(guildID, userID); err != nil {
+			eh.logger.Warn("Failed to remove pending role after claim expiry", "guild_id", guildID, "user_id", userID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// addManagedRole grants a managed role to a user and records it as bot-assigned. The grant is
+// recorded first, so a role is never granted without the record that lets the permissive role
+// sync policy remove it again. The realm path and role name identify realm roles in the audit
+// log and are empty for the verified role.
+func (eh *EventHandlers) addManagedRole(guildID, userID, roleID, realmPath, realmRole string) error {
+	if eh.skipInDryRun(storage.AuditActionGrant, guildID, userID, roleID, realmPath, realmRole) {
+		return nil
+	}
+	if err := eh.configManager.RecordBotAssignedRole(guildID, userID, roleID); err != nil {
+		return fmt.Errorf("failed to record bot-assigned role: %w", err)
+	}
+	if err := eh.platform.AddRole(guildID, userID, roleID); err != nil {
+		if clearErr := eh.configManager.ClearBotAssignedRole(guildID, userID, roleID); clearErr != nil {
+			eh.logger.Warn("Failed to clear bot-assigned record of a failed grant", "guild_id", guildID, "user_id", userID, "role_id", roleID, "error", clearErr)
+		}
+		return err
+	}
+	eh.audit(storage.AuditActionGrant, guildID, userID, roleID, realmPath, realmRole)
+	return nil
+}
+
+// removeManagedRole removes a managed role from a user according to the guild's role sync policy.
+// Under the permissive policy, roles the bot did not assign are preserved.
+// Returns true if the role was removed.
+func (eh *EventHandlers) removeManagedRole(guildID, userID, roleID, realmPath, realmRole string) (bool, error) {
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	botAssigned := config.IsBotAssignedRole(userID, roleID)
+	if !botAssigned && eh.configManager.GetRoleSyncPolicy(config) == storage.RoleSyncPolicyPermissive {
+		eh.logger.Info("Preserving manually assigned role under permissive role sync policy",
+			"guild_id", guildID,
+			"user_id", userID,
+			"role_id", roleID,
+		)
+		return false, nil
+	}
+
+	if eh.skipInDryRun(storage.AuditActionRevoke, guildID, userID, roleID, realmPath, realmRole) {
+		return true, nil
+	}
+	if err := eh.platform.RemoveRole(guildID, userID, roleID); err != nil {
+		return false, err
+	}
+	eh.audit(storage.AuditActionRevoke, guildID, userID, roleID, realmPath, realmRole)
+
+	if botAssigned {
+		if err := eh.configManager.ClearBotAssignedRole(guildID, userID, roleID); err != nil {
+			return true, fmt.Errorf("failed to clear bot-assigned role: %w", err)
+		}
+	}
+
+	return true, nil
+}
+
+// adoptManagedRole records a managed role a user already holds as bot-assigned when the guild
+// reconciles roles strictly, where every managed role is the bot's to grant and remove. This
+// migrates grants made before the bot recorded them, so switching the guild to the permissive
+// policy later does not leave them behind as manual grants.
+func (eh *EventHandlers) adoptManagedRole(config *storage.GuildConfig, guildID, userID, roleID string) {
+	if eh.dryRun || config.IsBotAssignedRole(userID, roleID) || eh.configManager.GetRoleSyncPolicy(config) != storage.RoleSyncPolicyStrict {
+		return
+	}
+	if err := eh.configManager.RecordBotAssignedRole(guildID, userID, roleID); err != nil {
+		eh.logger.Warn("Failed to adopt managed role as bot-assigned", "guild_id", guildID, "user_id", userID, "role_id", roleID, "error", err)
+	}
+}
+
+// RefreshMonitoredRealms re-scans a guild's linked roles for the realms to monitor and persists
+// the result, replacing the cached set so realms linked since the last discovery are picked up
+func (eh *EventHandlers) RefreshMonitoredRealms(guildID string) ([]string, error) {
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	linkedRoles, err := eh.roleLinkingFlow.ListAllRolesByGuild(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list linked roles: %w", err)
+	}
+
+	realmPaths := slices.Concat(config.LinkedRealms(), config.RegisteredRealms)
+	for _, roleMapping := range linkedRoles {
+		realmPaths = append(realmPaths, roleMapping.RealmPath)
+	}
+
+	slices.Sort(realmPaths)
+	realmPaths = slices.Compact(realmPaths)
+	if err := eh.configManager.SetMonitoredRealms(guildID, realmPaths); err != nil {
+		return nil, err
+	}
+
+	eh.logger.Info("Refreshed monitored realms",
+		"guild_id", guildID,
+		"previous", config.MonitoredRealms,
+		"realm_paths", realmPaths,
+	)
+	return realmPaths, nil
+}
+
+// keepBaselineRole reports whether a linked user keeps a role they no longer qualify for because
+// they held it when the guild's role baseline was imported. Unlinked users are clearly orphaned
+// and lose baseline roles right away; the baseline is dropped after the user's verification pass.
+func (eh *EventHandlers) keepBaselineRole(guildID, userID, roleID string) bool {
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	if err != nil || !config.IsBaselineRole(userID, roleID) {
+		return false
+	}
+
+	eh.logger.Info("Preserving baseline role until the user's verification pass",
+		"guild_id", guildID,
+		"user_id", userID,
+		"role_id", roleID,
+	)
+	return true
 }
 
 // syncUserRealmRoles immediately syncs all realm roles for a specific user
@@ -258,9 +598,11 @@ func (eh *EventHandlers) syncUserRealmRoles(guildID, discordID, gnoAddress strin
 		return nil
 	}
 
-	// Sync roles for each monitored realm
+	// Collect the changes for every monitored realm, then apply them in the configured order
+	var changes []roleChange
 	for _, realmPath := range monitoredRealms {
-		if err := eh.syncUserRolesByRealm(guildID, discordID, gnoAddress, realmPath); err != nil {
+		realmChanges, err := eh.planUserRolesByRealm(config, guildID, discordID, gnoAddress, realmPath)
+		if err != nil {
 			eh.logger.Error("Failed to sync user roles for realm",
 				"guild_id", guildID,
 				"discord_id", discordID,
@@ -268,23 +610,51 @@ func (eh *EventHandlers) syncUserRealmRoles(guildID, discordID, gnoAddress strin
 				"error", err,
 			)
 			// Continue with other realms
+			continue
 		}
+		changes = append(changes, realmChanges...)
 	}
 
+	eh.applyRoleChanges(guildID, discordID, orderRoleChanges(changes, eh.configManager.GetRoleSyncOrder(config)))
 	return nil
 }
 
-// syncUserRolesByRealm syncs roles for a user within a specific realm
-func (eh *EventHandlers) syncUserRolesByRealm(guildID, discordID, gnoAddress, realmPath string) error {
+// roleChange is a pending grant or removal of a mapped Discord role
+type roleChange struct {
+	add     bool
+	mapping *core.RoleMapping
+}
+
+// orderRoleChanges arranges changes according to the role sync order.
+// The relative order of additions and of removals is preserved.
+func orderRoleChanges(changes []roleChange, order storage.RoleSyncOrder) []roleChange {
+	if order != storage.RoleSyncOrderAddsFirst && order != storage.RoleSyncOrderRemovesFirst {
+		return changes
+	}
+
+	addsFirst := order == storage.RoleSyncOrderAddsFirst
+	ordered := make([]roleChange, 0, len(changes))
+	for _, first := range []bool{true, false} {
+		for _, change := range changes {
+			if (change.add == addsFirst) == first {
+				ordered = append(ordered, change)
+			}
+		}
+	}
+	return ordered
+}
+
+// planUserRolesByRealm determines the role changes needed to sync a user within a specific realm
+func (eh *EventHandlers) planUserRolesByRealm(config *storage.GuildConfig, guildID, discordID, gnoAddress, realmPath string) ([]roleChange, error) {
 	// Get all role mappings for this realm
 	roleMappings, err := eh.roleLinkingFlow.ListLinkedRoles(realmPath, guildID)
 	if err != nil {
-		return fmt.Errorf("failed to list linked roles: %w", err)
+		return nil, fmt.Errorf("failed to list linked roles: %w", err)
 	}
 
 	if len(roleMappings) == 0 {
 		eh.logger.Debug("No role mappings found for realm", "realm_path", realmPath, "guild_id", guildID)
-		return nil
+		return nil, nil
 	}
 
 	eh.logger.Info("Syncing user roles for realm",
@@ -293,9 +663,15 @@ func (eh *EventHandlers) syncUserRolesByRealm(guildID, discordID, gnoAddress, re
 		"discord_id", discordID,
 	)
 
-	// Check membership and sync each role
+	// Check membership for each role
+	var changes []roleChange
 	for _, roleMapping := range roleMappings {
-		hasRealmRole, err := eh.roleLinkingFlow.HasRealmRole(realmPath, roleMapping.RealmRoleName, gnoAddress)
+		if !config.IsRoleAutoSynced(realmPath, roleMapping.RealmRoleName) {
+			eh.logger.Debug("Skipping role excluded from auto-sync", "realm_path", realmPath, "role_name", roleMapping.RealmRoleName)
+			continue
+		}
+
+		hasRealmRole, err := eh.hasRealmRole(realmPath, roleMapping.RealmRoleName, gnoAddress)
 		if err != nil {
 			eh.logger.Error("Failed to check realm role membership",
 				"realm_path", realmPath,
@@ -318,9 +694,23 @@ func (eh *EventHandlers) syncUserRolesByRealm(guildID, discordID, gnoAddress, re
 		}
 
 		// Sync roles based on realm membership
-		if hasRealmRole && !hasDiscordRole {
+		if hasRealmRole != hasDiscordRole {
+			changes = append(changes, roleChange{add: hasRealmRole, mapping: roleMapping})
+		} else if hasRealmRole {
+			eh.adoptManagedRole(config, guildID, discordID, roleMapping.PlatformRole.ID)
+		}
+	}
+
+	return changes, nil
+}
+
+// applyRoleChanges grants or removes each mapped role in turn, logging failures and continuing
+func (eh *EventHandlers) applyRoleChanges(guildID, discordID string, changes []roleChange) {
+	for _, change := range changes {
+		roleMapping := change.mapping
+		if change.add {
 			// User should have Discord role but doesn't - add it
-			err = eh.platform.AddRole(guildID, discordID, roleMapping.PlatformRole.ID)
+			err := eh.addManagedRole(guildID, discordID, roleMapping.PlatformRole.ID, roleMapping.RealmPath, roleMapping.RealmRoleName)
 			if err != nil {
 				eh.logger.Error("Failed to add Discord role",
 					"discord_role_id", roleMapping.PlatformRole.ID,
@@ -334,26 +724,28 @@ func (eh *EventHandlers) syncUserRolesByRealm(guildID, discordID, gnoAddress, re
 					"discord_id", discordID,
 				)
 			}
-		} else if !hasRealmRole && hasDiscordRole {
-			// User has Discord role but shouldn't - remove it
-			err = eh.platform.RemoveRole(guildID, discordID, roleMapping.PlatformRole.ID)
-			if err != nil {
-				eh.logger.Error("Failed to remove Discord role",
-					"discord_role_id", roleMapping.PlatformRole.ID,
-					"discord_id", discordID,
-					"error", err,
-				)
-			} else {
-				eh.logger.Info("Removed Discord role from user",
-					"discord_role_id", roleMapping.PlatformRole.ID,
-					"role_name", roleMapping.RealmRoleName,
-					"discord_id", discordID,
-				)
-			}
+			continue
 		}
-	}
 
-	return nil
+		// User has Discord role but shouldn't - remove it, unless the linked user held it when the baseline was imported
+		if eh.keepBaselineRole(guildID, discordID, roleMapping.PlatformRole.ID) {
+			continue
+		}
+		removed, err := eh.removeManagedRole(guildID, discordID, roleMapping.PlatformRole.ID, roleMapping.RealmPath, roleMapping.RealmRoleName)
+		if err != nil {
+			eh.logger.Error("Failed to remove Discord role",
+				"discord_role_id", roleMapping.PlatformRole.ID,
+				"discord_id", discordID,
+				"error", err,
+			)
+		} else if removed {
+			eh.logger.Info("Removed Discord role from user",
+				"discord_role_id", roleMapping.PlatformRole.ID,
+				"role_name", roleMapping.RealmRoleName,
+				"discord_id", discordID,
+			)
+		}
+	}
 }
 
 // removeAllRealmRoles removes all realm-based Discord roles from a user
@@ -398,6 +790,10 @@ func (eh *EventHandlers) removeAllRealmRoles(guildID, discordID string) error {
 		}
 
 		for _, roleMapping := range roleMappings {
+			if !config.IsRoleAutoSynced(realmPath, roleMapping.RealmRoleName) {
+				continue
+			}
+
 			hasDiscordRole, err := eh.platform.HasRole(guildID, discordID, roleMapping.PlatformRole.ID)
 			if err != nil {
 				eh.logger.Error("Failed to check Discord role for removal",
@@ -409,14 +805,14 @@ func (eh *EventHandlers) removeAllRealmRoles(guildID, discordID string) error {
 			}
 
 			if hasDiscordRole {
-				err = eh.platform.RemoveRole(guildID, discordID, roleMapping.PlatformRole.ID)
+				removed, err := eh.removeManagedRole(guildID, discordID, roleMapping.PlatformRole.ID, roleMapping.RealmPath, roleMapping.RealmRoleName)
 				if err != nil {
 					eh.logger.Error("Failed to remove Discord role from unlinked user",
 						"discord_role_id", roleMapping.PlatformRole.ID,
 						"discord_id", discordID,
 						"error", err,
 					)
-				} else {
+				} else if removed {
 					eh.logger.Info("Removed Discord role from unlinked user",
 						"discord_role_id", roleMapping.PlatformRole.ID,
 						"role_name", roleMapping.RealmRoleName,
@@ -440,8 +836,15 @@ func (eh *EventHandlers) getMonitoredRealms(config *storage.GuildConfig) []strin
 	// Otherwise, discover realms by fetching all linked roles for each guild
 	realmPaths := make(map[string]bool)
 
+	// Realms recorded when roles were linked don't depend on Discord role names
+	if config != nil {
+		for _, realmPath := range config.LinkedRealms() {
+			realmPaths[realmPath] = true
+		}
+	}
+
 	// For each guild we're monitoring
-	for _, guild := range eh.session.State.Guilds {
+	for _, guild := range eh.directory().Guilds() {
 		// Get all linked roles for this guild in one call
 		linkedRoles, err := eh.roleLinkingFlow.ListAllRolesByGuild(guild.ID)
 		if err != nil {
@@ -474,7 +877,7 @@ func (eh *EventHandlers) getMonitoredRealms(config *storage.GuildConfig) []strin
 
 	// Cache the discovered realms in the config
 	if config != nil && len(result) > 0 {
-		config.MonitoredRealms = result
+		config.SetMonitoredRealms(result)
 		// Note: Caller is responsible for saving the config
 		eh.logger.Info("Caching monitored realms in config", "realm_count", len(result))
 	}
@@ -492,12 +895,25 @@ func (eh *EventHandlers) getMonitoredRealms(config *storage.GuildConfig) []strin
 // ProcessTieredVerification implements tiered member verification with 4-state logic
 func (eh *EventHandlers) ProcessTieredVerification(ctx context.Context, guildID string, state *storage.GuildQueryState, priority string, maxUsers int) error {
 	eh.logger.Info("Starting tiered verification", "guild_id", guildID, "priority", priority, "max_users", maxUsers)
+	eh = eh.withRealmRoleCache()
+	eh.metrics.VerificationRun(priority)
+	defer eh.trackAPICalls("verification sweep", "guild_id", guildID, "priority", priority)()
+
+	// Expiring a claim drops it from the guild config, so a dry run leaves claims to a real run
+	if !eh.dryRun {
+		if err := eh.CleanupExpiredPendingClaims(guildID); err != nil {
+			eh.logger.Warn("Failed to clean up expired pending claims", "guild_id", guildID, "error", err)
+		}
+	}
 
 	// Get all Discord members in this guild
-	members, err := eh.session.GuildMembers(guildID, "", 1000)
+	members, err := eh.directory().GuildMembers(guildID)
 	if err != nil {
 		return fmt.Errorf("failed to get guild members: %w", err)
 	}
+	if config, err := eh.configManager.GetGuildConfig(guildID); err == nil {
+		members = presentMembers(config, members)
+	}
 
 	eh.logger.Info("Retrieved guild members for verification",
 		"guild_id", guildID,
@@ -510,8 +926,27 @@ func (eh *EventHandlers) ProcessTieredVerification(ctx context.Context, guildID
 	totalProcessed := 0
 
 	// Process each user with 4-state verification logic
-	for _, member := range usersToProcess {
+	for n, member := range usersToProcess {
+		// Stop between members, so the pass ends with every processed member counted
+		if ctx.Err() != nil {
+			eh.logger.Info("Verification pass stopping early",
+				"guild_id", guildID,
+				"priority", priority,
+				"processed", totalProcessed,
+				"remaining", len(usersToProcess)-n)
+			break
+		}
+
 		if err := eh.processUserVerification(ctx, guildID, member); err != nil {
+			if errors.Is(err, workflows.ErrCircuitOpen) {
+				// The remaining members would fail the same way; the next run picks them up
+				eh.logger.Warn("Realm queries paused, ending verification pass early",
+					"guild_id", guildID,
+					"priority", priority,
+					"processed", totalProcessed,
+					"remaining", len(usersToProcess)-n)
+				return fmt.Errorf("verification pass interrupted: %w", err)
+			}
 			eh.logger.Error("Failed to verify user",
 				"guild_id", guildID,
 				"user_id", member.User.ID,
@@ -521,11 +956,6 @@ func (eh *EventHandlers) ProcessTieredVerification(ctx context.Context, guildID
 		}
 
 		totalProcessed++
-
-		// Check context for cancellation
-		if ctx.Err() != nil {
-			break
-		}
 	}
 
 	// Update incremental processing state for low priority
@@ -572,7 +1002,7 @@ func (eh *EventHandlers) getHighPriorityUsers(state *storage.GuildQueryState, al
 		}
 
 		// Get presence for this guild member
-		presence, err := eh.session.State.Presence(guildID, member.User.ID)
+		presence, err := eh.directory().Presence(guildID, member.User.ID)
 		if err != nil {
 			// If we can't get presence from state, try to request it
 			// This can happen if the bot recently started and state isn't fully populated
@@ -644,7 +1074,7 @@ func (eh *EventHandlers) getMediumPriorityUsers(state *storage.GuildQueryState,
 		}
 
 		// Get presence for this guild member
-		presence, err := eh.session.State.Presence(guildID, member.User.ID)
+		presence, err := eh.directory().Presence(guildID, member.User.ID)
 
 		// If we can't get presence or user is offline, include them in medium priority
 		if err != nil || presence.Status == discordgo.StatusOffline || presence.Status == discordgo.StatusInvisible {
@@ -722,7 +1152,7 @@ func (eh *EventHandlers) updateIncrementalState(state *storage.GuildQueryState,
 }
 
 // processUserVerification implements the 4-state verification logic for a single user
-func (eh *EventHandlers) processUserVerification(_ context.Context, guildID string, member *discordgo.Member) error {
+func (eh *EventHandlers) processUserVerification(_ context.Context, guildID string, member *discordgo.Member) (err error) {
 	userID := member.User.ID
 	username := member.User.Username
 
@@ -755,6 +1185,11 @@ func (eh *EventHandlers) processUserVerification(_ context.Context, guildID stri
 
 	// Check if user is registered in Gno realm
 	gnoAddress, err := eh.userLinkingFlow.GetLinkedAddress(userID)
+	if workflows.IsUnavailable(err) {
+		// Without an answer from the chain the user's roles are left as they are, instead of
+		// being removed as if they had unlinked
+		return fmt.Errorf("failed to get linked address for user %s: %w", userID, err)
+	}
 	if err != nil {
 		eh.logger.Info("Failed to get linked address for user",
 			"user_id", userID,
@@ -766,6 +1201,32 @@ func (eh *EventHandlers) processUserVerification(_ context.Context, guildID stri
 
 	isInGnoRegistry := gnoAddress != ""
 
+	// Accounts held for link review are not granted anything until an admin approves them
+	if isInGnoRegistry && config.IsLinkHeld(userID) {
+		eh.logger.Info("Skipping verification for user held for link review",
+			"guild_id", guildID,
+			"user_id", userID,
+			"gno_address", gnoAddress)
+		return nil
+	}
+
+	// Roles imported as a baseline are only spared on the user's first verification pass,
+	// which a dry run does not count as
+	if len(config.RoleBaseline[userID]) > 0 && !eh.dryRun {
+		defer func() {
+			if err != nil {
+				return
+			}
+			if err := eh.configManager.ConsumeRoleBaseline(guildID, userID); err != nil {
+				eh.logger.Error("Failed to clear role baseline", "guild_id", guildID, "user_id", userID, "error", err)
+			}
+		}()
+	}
+
+	// Further verified-role rules only depend on the link, whatever the state below
+	rulesErr := eh.syncVerifiedRoleRules(guildID, userID, gnoAddress, config)
+	defer func() { err = errors.Join(err, rulesErr) }()
+
 	eh.logger.Info("User verification state determined",
 		"guild_id", guildID,
 		"user_id", userID,
@@ -790,14 +1251,14 @@ func (eh *EventHandlers) processUserVerification(_ context.Context, guildID stri
 				"user_id", userID,
 				"role_id", config.VerifiedRoleID)
 
-			if err := eh.platform.RemoveRole(guildID, userID, config.VerifiedRoleID); err != nil {
+			if removed, err := eh.removeManagedRole(guildID, userID, config.VerifiedRoleID, "", ""); err != nil {
 				eh.logger.Error("Failed to remove verified role from user",
 					"guild_id", guildID,
 					"user_id", userID,
 					"role_id", config.VerifiedRoleID,
 					"error", err)
 				// Continue to remove realm roles even if verified role removal fails
-			} else {
+			} else if removed {
 				eh.logger.Info("Successfully removed verified role",
 					"guild_id", guildID,
 					"user_id", userID,
@@ -815,6 +1276,7 @@ func (eh *EventHandlers) processUserVerification(_ context.Context, guildID stri
 			"guild_id", guildID,
 			"user_id", userID,
 			"gno_address", gnoAddress)
+		eh.adoptManagedRole(config, guildID, userID, config.VerifiedRoleID)
 
 		// Sync realm roles for this user
 		return eh.syncUserRealmRoles(guildID, userID, gnoAddress)
@@ -857,7 +1319,7 @@ func (eh *EventHandlers) processUserVerification(_ context.Context, guildID stri
 					"guild_id", guildID,
 					"user_id", userID)
 			} else {
-				if err := eh.platform.AddRole(guildID, userID, config.VerifiedRoleID); err != nil {
+				if err := eh.addManagedRole(guildID, userID, config.VerifiedRoleID, "", ""); err != nil {
 					eh.logger.Error("Failed to add verified role to user",
 						"guild_id", guildID,
 						"user_id", userID,
@@ -909,10 +1371,14 @@ func (eh *EventHandlers) getPresencePriorityFromState(state *storage.GuildQueryS
 	}
 }
 
-func (eh *EventHandlers) HandleRoleLinked(event Event) error {
+func (eh *EventHandlers) HandleRoleLinked(event Event) (err error) {
+	defer func() { eh.getMetrics().EventProcessed(string(RoleLinkedEvent), err) }()
 	if event.RoleLinked == nil {
 		return fmt.Errorf("RoleLinked event data is nil")
 	}
+	eh = eh.withCorrelationID(&event)
+	defer eh.notifyRoleChanges(&event)()
+	defer eh.trackAPICalls("RoleLinked event", "tx_hash", event.TransactionHash)()
 
 	roleLinked := event.RoleLinked
 	eh.logger.Info("Processing RoleLinked event",
@@ -927,7 +1393,7 @@ func (eh *EventHandlers) HandleRoleLinked(event Event) error {
 	// For role events, we only process if the event is for this specific guild
 	// Check if this guild is actually being managed by this bot instance
 	found := false
-	for _, guild := range eh.session.State.Guilds {
+	for _, guild := range eh.directory().Guilds() {
 		if guild.ID == roleLinked.DiscordGuildID {
 			found = true
 			break
@@ -947,14 +1413,30 @@ func (eh *EventHandlers) HandleRoleLinked(event Event) error {
 		"discord_role_id", roleLinked.DiscordRoleID,
 	)
 
+	// Persist the mapping so the role is found by ID even if its Discord name changes
+	if err := eh.configManager.RecordLinkedRole(roleLinked.DiscordGuildID, roleLinked.RealmPath, roleLinked.RoleName, roleLinked.DiscordRoleID); err != nil {
+		eh.logger.Warn("Failed to record linked role", "guild_id", roleLinked.DiscordGuildID, "error", err)
+	}
+
+	// A paused guild keeps the mapping; its members are synced by verification once resumed
+	if eh.guildPaused(roleLinked.DiscordGuildID) {
+		eh.logger.Info("Guild processing paused, skipping RoleLinked role changes", "guild_id", roleLinked.DiscordGuildID)
+		return nil
+	}
+
 	// Get all members with the realm role and add the Discord role
-	return eh.syncRoleMembers(roleLinked.DiscordGuildID, roleLinked.RealmPath, roleLinked.RoleName, roleLinked.DiscordRoleID, true)
+	_, err = eh.syncRoleMembers(roleLinked.DiscordGuildID, roleLinked.RealmPath, roleLinked.RoleName, roleLinked.DiscordRoleID, roleSyncGrant)
+	return err
 }
 
-func (eh *EventHandlers) HandleRoleUnlinked(event Event) error {
+func (eh *EventHandlers) HandleRoleUnlinked(event Event) (err error) {
+	defer func() { eh.getMetrics().EventProcessed(string(RoleUnlinkedEvent), err) }()
 	if event.RoleUnlinked == nil {
 		return fmt.Errorf("RoleUnlinked event data is nil")
 	}
+	eh = eh.withCorrelationID(&event)
+	defer eh.notifyRoleChanges(&event)()
+	defer eh.trackAPICalls("RoleUnlinked event", "tx_hash", event.TransactionHash)()
 
 	roleUnlinked := event.RoleUnlinked
 	eh.logger.Info("Processing RoleUnlinked event",
@@ -969,7 +1451,7 @@ func (eh *EventHandlers) HandleRoleUnlinked(event Event) error {
 	// For role events, we only process if the event is for this specific guild
 	// Check if this guild is actually being managed by this bot instance
 	found := false
-	for _, guild := range eh.session.State.Guilds {
+	for _, guild := range eh.directory().Guilds() {
 		if guild.ID == roleUnlinked.DiscordGuildID {
 			found = true
 			break
@@ -989,112 +1471,270 @@ func (eh *EventHandlers) HandleRoleUnlinked(event Event) error {
 		"discord_role_id", roleUnlinked.DiscordRoleID,
 	)
 
+	if eh.guildPaused(roleUnlinked.DiscordGuildID) {
+		eh.logger.Info("Guild processing paused, skipping RoleUnlinked role changes", "guild_id", roleUnlinked.DiscordGuildID)
+		return nil
+	}
+
 	// Remove the Discord role from all members
-	return eh.syncRoleMembers(roleUnlinked.DiscordGuildID, roleUnlinked.RealmPath, roleUnlinked.RoleName, roleUnlinked.DiscordRoleID, false)
+	_, err = eh.syncRoleMembers(roleUnlinked.DiscordGuildID, roleUnlinked.RealmPath, roleUnlinked.RoleName, roleUnlinked.DiscordRoleID, roleSyncRevoke)
+	return err
+}
+
+// roleSyncMode selects which side of a role mapping syncRoleMembers reconciles
+type roleSyncMode int
+
+const (
+	// roleSyncGrant gives the Discord role to linked members holding the realm role
+	roleSyncGrant roleSyncMode = iota
+	// roleSyncRevoke removes the Discord role from every member
+	roleSyncRevoke
+	// roleSyncReconcile grants the role to realm role holders and removes it from everyone else
+	roleSyncReconcile
+)
+
+// RoleSyncResult reports the members a role sync checked and changed
+type RoleSyncResult struct {
+	Checked int
+	Added   []string
+	Removed []string
+	Failed  int
 }
 
-func (eh *EventHandlers) syncRoleMembers(guildID, realmPath, roleName, discordRoleID string, shouldHaveRole bool) error {
+// ResyncRole reconciles a single realm role mapping with on-chain membership: linked members
+// holding the realm role get the Discord role and all other members lose it. No other role
+// is checked or changed.
+func (eh *EventHandlers) ResyncRole(guildID, realmPath, roleName, discordRoleID string) (*RoleSyncResult, error) {
+	defer eh.trackAPICalls("role resync", "guild_id", guildID, "realm_path", realmPath, "role_name", roleName)()
+	return eh.syncRoleMembers(guildID, realmPath, roleName, discordRoleID, roleSyncReconcile)
+}
+
+func (eh *EventHandlers) syncRoleMembers(guildID, realmPath, roleName, discordRoleID string, mode roleSyncMode) (*RoleSyncResult, error) {
 	eh.logger.Info("Syncing role members",
 		"guild_id", guildID,
 		"realm_path", realmPath,
 		"role_name", roleName,
 		"discord_role_id", discordRoleID,
-		"should_have_role", shouldHaveRole,
+		"mode", mode,
 	)
 
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	// Admins manage the members of roles excluded from auto-sync themselves
+	if !config.IsRoleAutoSynced(realmPath, roleName) {
+		eh.logger.Info("Role is excluded from auto-sync, leaving its members unchanged",
+			"guild_id", guildID,
+			"realm_path", realmPath,
+			"role_name", roleName,
+		)
+		return &RoleSyncResult{}, nil
+	}
+
 	// Get all Discord members in this guild
-	members, err := eh.session.GuildMembers(guildID, "", 1000)
+	members, err := eh.directory().GuildMembers(guildID)
 	if err != nil {
 		eh.logger.Error("Failed to get guild members", "guild_id", guildID, "error", err)
-		return fmt.Errorf("failed to get guild members: %w", err)
+		return nil, fmt.Errorf("failed to get guild members: %w", err)
 	}
 
 	eh.logger.Info("Found guild members", "guild_id", guildID, "member_count", len(members))
+	members = presentMembers(config, members)
 
-	for _, member := range members {
-		// Get the linked Gno address for this Discord user
-		gnoAddress, err := eh.userLinkingFlow.GetLinkedAddress(member.User.ID)
-		if err != nil {
-			eh.logger.Debug("Failed to get linked address for user", "user_id", member.User.ID, "error", err)
-			continue
-		}
+	// Every member is checked for the same role, which is listed once if the realm supports it
+	eh = eh.withRealmRoleCache()
 
-		if gnoAddress == "" {
-			eh.logger.Debug("User has no linked address", "user_id", member.User.ID)
-			continue
-		}
-
-		// Check if this user has the realm role
-		hasRealmRole, err := eh.roleLinkingFlow.HasRealmRole(realmPath, roleName, gnoAddress)
-		if err != nil {
-			eh.logger.Error("Failed to check realm role",
-				"user_id", member.User.ID,
-				"gno_address", gnoAddress,
-				"realm_path", realmPath,
-				"role_name", roleName,
-				"error", err,
-			)
-			continue
-		}
+	result := &RoleSyncResult{}
+	for _, member := range members {
+		hasDiscordRole := slices.Contains(member.Roles, discordRoleID)
+		result.Checked++
+
+		// Unlinking the role removes it from everyone, whatever their realm roles
+		hasRealmRole := false
+		linked := false
+		if mode != roleSyncRevoke {
+			// Get the linked Gno address for this Discord user
+			gnoAddress, err := eh.userLinkingFlow.GetLinkedAddress(member.User.ID)
+			if err != nil {
+				eh.logger.Debug("Failed to get linked address for user", "user_id", member.User.ID, "error", err)
+				result.Failed++
+				continue
+			}
 
-		// Check if user currently has the Discord role
-		hasDiscordRole := false
-		for _, roleID := range member.Roles {
-			if roleID == discordRoleID {
-				hasDiscordRole = true
-				break
+			linked = gnoAddress != ""
+			if !linked {
+				eh.logger.Debug("User has no linked address", "user_id", member.User.ID)
+			} else if config.IsLinkHeld(member.User.ID) {
+				eh.logger.Debug("User is held for link review", "user_id", member.User.ID)
+			} else {
+				// Check if this user has the realm role
+				hasRealmRole, err = eh.hasRealmRole(realmPath, roleName, gnoAddress)
+				if err != nil {
+					eh.logger.Error("Failed to check realm role",
+						"user_id", member.User.ID,
+						"gno_address", gnoAddress,
+						"realm_path", realmPath,
+						"role_name", roleName,
+						"error", err,
+					)
+					result.Failed++
+					continue
+				}
 			}
 		}
 
 		eh.logger.Debug("Role sync check",
 			"user_id", member.User.ID,
-			"gno_address", gnoAddress,
 			"has_realm_role", hasRealmRole,
 			"has_discord_role", hasDiscordRole,
-			"should_have_role", shouldHaveRole,
+			"mode", mode,
 		)
 
-		// Sync the roles
-		if shouldHaveRole {
-			// Role was linked - users with realm role should get Discord role
-			if hasRealmRole && !hasDiscordRole {
-				err = eh.platform.AddRole(guildID, member.User.ID, discordRoleID)
-				if err != nil {
-					eh.logger.Error("Failed to add Discord role",
-						"user_id", member.User.ID,
-						"discord_role_id", discordRoleID,
-						"error", err,
-					)
-				} else {
-					eh.logger.Info("Added Discord role to user",
-						"user_id", member.User.ID,
-						"discord_role_id", discordRoleID,
-					)
-				}
+		switch {
+		case hasRealmRole && !hasDiscordRole && mode != roleSyncRevoke:
+			if err := eh.addManagedRole(guildID, member.User.ID, discordRoleID, realmPath, roleName); err != nil {
+				eh.logger.Error("Failed to add Discord role",
+					"user_id", member.User.ID,
+					"discord_role_id", discordRoleID,
+					"error", err,
+				)
+				result.Failed++
+				continue
 			}
-		} else {
-			// Role was unlinked - remove Discord role from all users
-			if hasDiscordRole {
-				err = eh.platform.RemoveRole(guildID, member.User.ID, discordRoleID)
-				if err != nil {
-					eh.logger.Error("Failed to remove Discord role",
-						"user_id", member.User.ID,
-						"discord_role_id", discordRoleID,
-						"error", err,
-					)
-				} else {
-					eh.logger.Info("Removed Discord role from user",
-						"user_id", member.User.ID,
-						"discord_role_id", discordRoleID,
-					)
-				}
+			eh.logger.Info("Added Discord role to user",
+				"user_id", member.User.ID,
+				"discord_role_id", discordRoleID,
+			)
+			result.Added = append(result.Added, member.User.ID)
+
+		case !hasRealmRole && hasDiscordRole && mode != roleSyncGrant:
+			if linked && config.IsBaselineRole(member.User.ID, discordRoleID) {
+				eh.logger.Info("Preserving baseline role until the user's verification pass",
+					"user_id", member.User.ID,
+					"discord_role_id", discordRoleID,
+				)
+				continue
+			}
+			removed, err := eh.removeManagedRole(guildID, member.User.ID, discordRoleID, realmPath, roleName)
+			if err != nil {
+				eh.logger.Error("Failed to remove Discord role",
+					"user_id", member.User.ID,
+					"discord_role_id", discordRoleID,
+					"error", err,
+				)
+				result.Failed++
+				continue
+			}
+			if removed {
+				eh.logger.Info("Removed Discord role from user",
+					"user_id", member.User.ID,
+					"discord_role_id", discordRoleID,
+				)
+				result.Removed = append(result.Removed, member.User.ID)
 			}
 		}
 	}
 
+	eh.logger.Info("Synced role members",
+		"guild_id", guildID,
+		"discord_role_id", discordRoleID,
+		"checked", result.Checked,
+		"added", len(result.Added),
+		"removed", len(result.Removed),
+		"failed", result.Failed,
+	)
+	return result, nil
+}
+
+// RoleBaselineResult reports what a role baseline import recorded
+type RoleBaselineResult struct {
+	Members      int
+	ManagedRoles int
+	Assignments  int
+}
+
+// ImportRoleBaseline records the linked realm roles every present member currently holds as the
+// guild's known-good baseline, replacing any previous one. Verification then spares baseline roles
+// of linked members on their first pass instead of removing them all at once, so adopting the bot
+// in a guild with manually assigned roles does not churn.
+func (eh *EventHandlers) ImportRoleBaseline(guildID string) (*RoleBaselineResult, error) {
+	defer eh.trackAPICalls("role baseline import", "guild_id", guildID)()
+
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	managedRoles := make(map[string]bool)
+	for _, realmPath := range eh.getMonitoredRealms(config) {
+		roleMappings, err := eh.roleLinkingFlow.ListLinkedRoles(realmPath, guildID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list linked roles for %s: %w", realmPath, err)
+		}
+		for _, roleMapping := range roleMappings {
+			managedRoles[roleMapping.PlatformRole.ID] = true
+		}
+	}
+
+	members, err := eh.directory().GuildMembers(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild members: %w", err)
+	}
+	members = presentMembers(config, members)
+
+	result := &RoleBaselineResult{ManagedRoles: len(managedRoles)}
+	baseline := make(map[string][]string)
+	for _, member := range members {
+		for _, roleID := range member.Roles {
+			if managedRoles[roleID] {
+				baseline[member.User.ID] = append(baseline[member.User.ID], roleID)
+				result.Assignments++
+			}
+		}
+	}
+	result.Members = len(baseline)
+
+	if err := eh.configManager.ImportRoleBaseline(guildID, baseline); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// HandleMemberLeft stops role processing for a member who left a guild until they rejoin
+func (eh *EventHandlers) HandleMemberLeft(guildID, userID string) error {
+	eh.userGuilds.invalidate(userID)
+	if err := eh.configManager.RecordMemberDeparted(guildID, userID); err != nil {
+		return err
+	}
+	eh.logger.Info("Member left guild, skipping them until they rejoin", "guild_id", guildID, "user_id", userID)
 	return nil
 }
 
+// HandleMemberJoined resumes role processing for a member who rejoined a guild. Discord drops
+// roles when a member leaves, so returning members are verified right away.
+func (eh *EventHandlers) HandleMemberJoined(ctx context.Context, guildID string, member *discordgo.Member) error {
+	eh.userGuilds.invalidate(member.User.ID)
+	rejoined, err := eh.configManager.RecordMemberJoined(guildID, member.User.ID)
+	if err != nil || !rejoined {
+		return err
+	}
+
+	eh.logger.Info("Member rejoined guild, resuming role processing", "guild_id", guildID, "user_id", member.User.ID)
+	return eh.processUserVerification(ctx, guildID, member)
+}
+
+// presentMembers drops the members a guild recorded as having left
+func presentMembers(config *storage.GuildConfig, members []*discordgo.Member) []*discordgo.Member {
+	if len(config.DepartedMembers) == 0 {
+		return members
+	}
+	return slices.DeleteFunc(slices.Clone(members), func(member *discordgo.Member) bool {
+		return config.IsMemberDeparted(member.User.ID)
+	})
+}
+
 // UpdateUserPresence updates the presence state for a user in a specific guild
 func (eh *EventHandlers) UpdateUserPresence(guildID, userID string, isActive bool) error {
 	config, err := eh.configManager.GetGuildConfig(guildID)
@@ -1145,12 +1785,14 @@ func (eh *EventHandlers) updateUserPriority(priorityData map[string][]string, us
 	}
 }
 
-// removeUserFromSlice removes a user ID from a slice
+// removeUserFromSlice returns a copy of a slice without a user ID. The copy never shares a backing
+// array with the input, which may be shared with other priority levels.
 func (eh *EventHandlers) removeUserFromSlice(slice []string, userID string) []string {
-	for i, id := range slice {
-		if id == userID {
-			return append(slice[:i], slice[i+1:]...)
+	kept := make([]string, 0, len(slice))
+	for _, id := range slice {
+		if id != userID {
+			kept = append(kept, id)
 		}
 	}
-	return slice
+	return kept
 }
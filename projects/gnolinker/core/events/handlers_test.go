@@ -0,0 +1,1256 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/metrics"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
+	"github.com/allinbits/labs/projects/gnolinker/platforms"
+	"github.com/bwmarrin/discordgo"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// mockPlatform implements platforms.Platform with in-memory role assignments
+type mockPlatform struct {
+	mu    sync.Mutex
+	roles map[string][]string // "guildID:userID" -> role IDs
+	ops   []string            // "add:roleID" / "remove:roleID" in call order
+	dms   map[string][]string // user ID -> direct messages sent
+	dmErr error               // returned by SendDirectMessage instead of sending
+}
+
+func newMockPlatform() *mockPlatform {
+	return &mockPlatform{roles: make(map[string][]string), dms: make(map[string][]string)}
+}
+
+func (p *mockPlatform) key(guildID, userID string) string {
+	return guildID + ":" + userID
+}
+
+func (p *mockPlatform) GetUserID(message platforms.Message) string { return message.GetAuthorID() }
+
+func (p *mockPlatform) SendDirectMessage(userID, content string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.dmErr != nil {
+		return p.dmErr
+	}
+	p.dms[userID] = append(p.dms[userID], content)
+	return nil
+}
+
+func (p *mockPlatform) HasRole(guildID, userID, roleID string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Contains(p.roles[p.key(guildID, userID)], roleID), nil
+}
+
+func (p *mockPlatform) AddRole(guildID, userID, roleID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ops = append(p.ops, "add:"+roleID)
+	k := p.key(guildID, userID)
+	if !slices.Contains(p.roles[k], roleID) {
+		p.roles[k] = append(p.roles[k], roleID)
+	}
+	return nil
+}
+
+func (p *mockPlatform) RemoveRole(guildID, userID, roleID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ops = append(p.ops, "remove:"+roleID)
+	k := p.key(guildID, userID)
+	p.roles[k] = slices.DeleteFunc(p.roles[k], func(id string) bool { return id == roleID })
+	return nil
+}
+
+func (p *mockPlatform) GetOrCreateRole(guildID, name string) (*core.PlatformRole, error) {
+	return &core.PlatformRole{ID: "role_" + name, Name: name}, nil
+}
+
+func (p *mockPlatform) GetRoleByID(guildID, roleID string) (*core.PlatformRole, error) {
+	return &core.PlatformRole{ID: roleID, Name: roleID}, nil
+}
+
+// mockUserLinkingFlow implements workflows.UserLinkingWorkflow backed by a static address map
+type mockUserLinkingFlow struct {
+	addresses map[string]string // platform ID -> gno address
+	err       error             // returned by GetLinkedAddress when set
+}
+
+func (m *mockUserLinkingFlow) GenerateClaim(platformID, gnoAddress string) (*core.Claim, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockUserLinkingFlow) GenerateUnlinkClaim(platformID, gnoAddress string) (*core.Claim, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockUserLinkingFlow) GetLinkedAddress(platformID string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	return m.addresses[platformID], nil
+}
+
+func (m *mockUserLinkingFlow) ListLinkedAddresses(platformID string) ([]string, error) {
+	address, err := m.GetLinkedAddress(platformID)
+	if err != nil || address == "" {
+		return nil, err
+	}
+	return []string{address}, nil
+}
+
+func (m *mockUserLinkingFlow) GetLinkedPlatformID(gnoAddress string) (string, error) {
+	for platformID, address := range m.addresses {
+		if address == gnoAddress {
+			return platformID, nil
+		}
+	}
+	return "", nil
+}
+
+func (m *mockUserLinkingFlow) GetClaimURL(claim *core.Claim) string { return "" }
+
+// mockRoleLinkingFlow implements workflows.RoleLinkingWorkflow backed by static mappings
+type mockRoleLinkingFlow struct {
+	mappings   []*core.RoleMapping
+	members    map[string][]string // "realmPath:roleName" -> gno addresses
+	enumerable bool                // whether ListRealmRoleMembers lists members
+	queries    int                 // role membership queries made
+}
+
+func (m *mockRoleLinkingFlow) GenerateClaim(userID, platformGuildID, platformRoleID, roleName, realmPath string) (*core.Claim, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockRoleLinkingFlow) GenerateUnlinkClaim(userID, platformGuildID, platformRoleID, roleName, realmPath string) (*core.Claim, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockRoleLinkingFlow) GetLinkedRole(realmPath, roleName, platformGuildID string) (*core.RoleMapping, error) {
+	for _, mapping := range m.mappings {
+		if mapping.RealmPath == realmPath && mapping.RealmRoleName == roleName {
+			return mapping, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRoleLinkingFlow) ListLinkedRoles(realmPath, platformGuildID string) ([]*core.RoleMapping, error) {
+	var result []*core.RoleMapping
+	for _, mapping := range m.mappings {
+		if mapping.RealmPath == realmPath {
+			result = append(result, mapping)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRoleLinkingFlow) ListAllRolesByGuild(platformGuildID string) ([]*core.RoleMapping, error) {
+	return m.mappings, nil
+}
+
+func (m *mockRoleLinkingFlow) HasRealmRole(realmPath, roleName, address string) (bool, error) {
+	m.queries++
+	return slices.Contains(m.members[realmPath+":"+roleName], address), nil
+}
+
+func (m *mockRoleLinkingFlow) ListRealmRoleMembers(realmPath, roleName string) ([]string, error) {
+	m.queries++
+	if !m.enumerable {
+		return nil, fmt.Errorf("%w: RoleMembersJSON not declared", workflows.ErrMembersNotEnumerable)
+	}
+	return m.members[realmPath+":"+roleName], nil
+}
+
+func (m *mockRoleLinkingFlow) EvalRealmPredicate(realmPath, function, address string) (bool, error) {
+	return slices.Contains(m.members[realmPath+"."+function], address), nil
+}
+
+func (m *mockRoleLinkingFlow) GetClaimURL(claim *core.Claim) string { return "" }
+
+const (
+	testGuildID    = "100000000000000001"
+	testUserID     = "user-1"
+	testAddress    = "g1testaddress"
+	testRealmPath  = "gno.land/r/demo/events"
+	testVerifiedID = "verified-role"
+	testMemberRole = "200000000000000001"
+)
+
+// newTestEventHandlers wires EventHandlers with in-memory mocks for a single guild
+// whose only realm role mapping is "member" → testMemberRole. The test user is linked
+// but does not hold the realm role.
+func newTestEventHandlers(t testing.TB, policy storage.RoleSyncPolicy) (*EventHandlers, *mockPlatform, *config.ConfigManager) {
+	t.Helper()
+
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	store := storage.NewMemoryConfigStore()
+	storageConfig := &config.StorageConfig{DefaultRoleSyncPolicy: policy}
+	configManager := config.NewConfigManager(store, storageConfig, nil, logger)
+
+	guildConfig := storage.NewGuildConfig(testGuildID)
+	guildConfig.VerifiedRoleID = testVerifiedID
+	guildConfig.MonitoredRealms = []string{testRealmPath}
+	if err := store.Set(testGuildID, guildConfig); err != nil {
+		t.Fatalf("failed to store guild config: %v", err)
+	}
+
+	platform := newMockPlatform()
+	userFlow := &mockUserLinkingFlow{addresses: map[string]string{testUserID: testAddress}}
+	roleFlow := &mockRoleLinkingFlow{
+		mappings: []*core.RoleMapping{{
+			RealmPath:     testRealmPath,
+			RealmRoleName: "member",
+			PlatformRole:  core.PlatformRole{ID: testMemberRole, Name: "member"},
+		}},
+		members: map[string][]string{},
+	}
+
+	eh := NewEventHandlers(platform, configManager, nil, logger, userFlow, roleFlow)
+	return eh, platform, configManager
+}
+
+func testMember() *discordgo.Member {
+	return &discordgo.Member{User: &discordgo.User{ID: testUserID, Username: "tester"}}
+}
+
+func TestProcessUserVerification_RoleSyncPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     storage.RoleSyncPolicy
+		wantKeeps  bool
+		botAssigns bool
+	}{
+		{name: "strict removes manual grant", policy: storage.RoleSyncPolicyStrict, wantKeeps: false},
+		{name: "permissive preserves manual grant", policy: storage.RoleSyncPolicyPermissive, wantKeeps: true},
+		{name: "permissive removes bot grant", policy: storage.RoleSyncPolicyPermissive, wantKeeps: false, botAssigns: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eh, platform, configManager := newTestEventHandlers(t, tt.policy)
+
+			// The user holds the verified role and the managed member role without the realm role
+			_ = platform.AddRole(testGuildID, testUserID, testVerifiedID)
+			_ = platform.AddRole(testGuildID, testUserID, testMemberRole)
+
+			if tt.botAssigns {
+				guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+				guildConfig.RecordBotAssignedRole(testUserID, testMemberRole)
+				_ = configManager.UpdateGuildConfig(testGuildID, guildConfig)
+			}
+
+			if err := eh.processUserVerification(t.Context(), testGuildID, testMember()); err != nil {
+				t.Fatalf("processUserVerification() error = %v", err)
+			}
+
+			hasRole, _ := platform.HasRole(testGuildID, testUserID, testMemberRole)
+			if hasRole != tt.wantKeeps {
+				t.Errorf("member role present = %v, want %v", hasRole, tt.wantKeeps)
+			}
+
+			guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+			if guildConfig.IsBotAssignedRole(testUserID, testMemberRole) {
+				t.Error("bot-assigned record should not remain after reconciliation")
+			}
+		})
+	}
+}
+
+func TestProcessUserVerification_RecordsBotAssignedRoles(t *testing.T) {
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyPermissive)
+	eh.roleLinkingFlow.(*mockRoleLinkingFlow).members[testRealmPath+":member"] = []string{testAddress}
+
+	if err := eh.processUserVerification(t.Context(), testGuildID, testMember()); err != nil {
+		t.Fatalf("processUserVerification() error = %v", err)
+	}
+
+	for _, roleID := range []string{testVerifiedID, testMemberRole} {
+		if hasRole, _ := platform.HasRole(testGuildID, testUserID, roleID); !hasRole {
+			t.Errorf("expected user to be granted role %s", roleID)
+		}
+	}
+
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	for _, roleID := range []string{testVerifiedID, testMemberRole} {
+		if !guildConfig.IsBotAssignedRole(testUserID, roleID) {
+			t.Errorf("expected role %s to be recorded as bot-assigned", roleID)
+		}
+	}
+
+	// Once the realm role is revoked, the bot-assigned role is removed even under permissive policy
+	eh.roleLinkingFlow.(*mockRoleLinkingFlow).members[testRealmPath+":member"] = nil
+	if err := eh.processUserVerification(t.Context(), testGuildID, testMember()); err != nil {
+		t.Fatalf("processUserVerification() error = %v", err)
+	}
+	if hasRole, _ := platform.HasRole(testGuildID, testUserID, testMemberRole); hasRole {
+		t.Error("expected bot-assigned member role to be removed")
+	}
+}
+
+// conflictingConfigStore rejects guild config writes, like an S3 store losing an ETag race
+type conflictingConfigStore struct {
+	storage.ConfigStore
+}
+
+func (conflictingConfigStore) Set(guildID string, config *storage.GuildConfig) error {
+	return errors.New("precondition failed")
+}
+
+func TestAddManagedRole_FailsWhenGrantCannotBeRecorded(t *testing.T) {
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyPermissive)
+	storageConfig := &config.StorageConfig{DefaultRoleSyncPolicy: storage.RoleSyncPolicyPermissive}
+	eh.configManager = config.NewConfigManager(conflictingConfigStore{configManager.GetStore()}, storageConfig, nil, eh.logger)
+
+	if err := eh.addManagedRole(testGuildID, testUserID, testMemberRole, testRealmPath, "member"); err == nil {
+		t.Fatal("addManagedRole() should fail when the grant cannot be recorded")
+	}
+	if hasRole, _ := platform.HasRole(testGuildID, testUserID, testMemberRole); hasRole {
+		t.Error("role should not be granted without its bot-assigned record")
+	}
+}
+
+func TestProcessUserVerification_AdoptsHeldRolesUnderStrictPolicy(t *testing.T) {
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	roleFlow := eh.roleLinkingFlow.(*mockRoleLinkingFlow)
+	roleFlow.members[testRealmPath+":member"] = []string{testAddress}
+
+	// Granted before the bot recorded its grants
+	_ = platform.AddRole(testGuildID, testUserID, testVerifiedID)
+	_ = platform.AddRole(testGuildID, testUserID, testMemberRole)
+
+	if err := eh.processUserVerification(t.Context(), testGuildID, testMember()); err != nil {
+		t.Fatalf("processUserVerification() error = %v", err)
+	}
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	for _, roleID := range []string{testVerifiedID, testMemberRole} {
+		if !guildConfig.IsBotAssignedRole(testUserID, roleID) {
+			t.Errorf("expected held role %s to be adopted as bot-assigned", roleID)
+		}
+	}
+
+	// After switching to permissive, the adopted role is still removed with the realm role
+	guildConfig.Settings[storage.SettingRoleSyncPolicy] = string(storage.RoleSyncPolicyPermissive)
+	if err := configManager.UpdateGuildConfig(testGuildID, guildConfig); err != nil {
+		t.Fatalf("UpdateGuildConfig() error = %v", err)
+	}
+	roleFlow.members[testRealmPath+":member"] = nil
+	if err := eh.processUserVerification(t.Context(), testGuildID, testMember()); err != nil {
+		t.Fatalf("processUserVerification() error = %v", err)
+	}
+	if hasRole, _ := platform.HasRole(testGuildID, testUserID, testMemberRole); hasRole {
+		t.Error("expected adopted member role to be removed under permissive policy")
+	}
+}
+
+// logRecord is a single captured log line with all of its attributes, including those added via With
+type logRecord struct {
+	msg   string
+	attrs map[string]any
+}
+
+// recordingLogger implements core.Logger and captures every log line into a shared sink
+type recordingLogger struct {
+	mu      *sync.Mutex
+	records *[]logRecord
+	attrs   []any
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{mu: &sync.Mutex{}, records: &[]logRecord{}}
+}
+
+func (l *recordingLogger) log(msg string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	attrs := make(map[string]any)
+	all := append(slices.Clone(l.attrs), args...)
+	for i := 0; i+1 < len(all); i += 2 {
+		attrs[fmt.Sprint(all[i])] = all[i+1]
+	}
+	*l.records = append(*l.records, logRecord{msg: msg, attrs: attrs})
+}
+
+func (l *recordingLogger) Debug(msg string, args ...any) { l.log(msg, args...) }
+func (l *recordingLogger) Info(msg string, args ...any)  { l.log(msg, args...) }
+func (l *recordingLogger) Warn(msg string, args ...any)  { l.log(msg, args...) }
+func (l *recordingLogger) Error(msg string, args ...any) { l.log(msg, args...) }
+
+func (l *recordingLogger) With(args ...any) core.Logger {
+	return &recordingLogger{mu: l.mu, records: l.records, attrs: append(slices.Clone(l.attrs), args...)}
+}
+
+func (l *recordingLogger) WithGroup(name string) core.Logger { return l }
+
+func (l *recordingLogger) snapshot() []logRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(*l.records)
+}
+
+func TestRoleEventsHandler_CorrelationID(t *testing.T) {
+	eh, _, _ := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	logger := newRecordingLogger()
+	eh.logger = logger
+	eh.session = &discordgo.Session{State: discordgo.NewState()}
+
+	registry := CreateCoreQueryRegistry(logger, eh)
+	queryDef, _ := registry.GetQuery(RoleEventsQueryID)
+
+	tx := graphql.Transaction{
+		Hash:        "tx-hash-1",
+		BlockHeight: 10,
+		Response: graphql.TransactionResponse{Events: []graphql.GnoEvent{{
+			Type: "RoleLinked",
+			Attrs: []graphql.EventAttribute{
+				{Key: "realmPath", Value: testRealmPath},
+				{Key: "roleName", Value: "member"},
+				{Key: "discordGuildID", Value: testGuildID},
+				{Key: "discordRoleID", Value: testMemberRole},
+			},
+		}}},
+	}
+
+	guildConfig := storage.NewGuildConfig(testGuildID)
+	state := guildConfig.EnsureQueryState(RoleEventsQueryID, true)
+	if err := queryDef.Handler(t.Context(), []any{tx}, guildConfig, state); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	var correlationID any
+	var tagged []string
+	for _, record := range logger.snapshot() {
+		id, ok := record.attrs[core.CorrelationIDKey]
+		if !ok {
+			continue
+		}
+		if correlationID == nil {
+			correlationID = id
+		}
+		if id != correlationID {
+			t.Errorf("log line %q has correlation ID %v, want %v", record.msg, id, correlationID)
+		}
+		tagged = append(tagged, record.msg)
+	}
+
+	if correlationID == "" || correlationID == nil {
+		t.Fatal("expected log lines to carry a correlation ID")
+	}
+	for _, msg := range []string{"Processing role event transaction", "Found RoleLinked event", "Processing RoleLinked event"} {
+		if !slices.Contains(tagged, msg) {
+			t.Errorf("expected %q to be logged with the correlation ID, tagged lines: %v", msg, tagged)
+		}
+	}
+}
+
+func TestPendingRole_SwappedOnLinkAndCleanedOnExpiry(t *testing.T) {
+	const pendingRoleID = "pending-role"
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	if err := configManager.SetPendingRole(testGuildID, pendingRoleID); err != nil {
+		t.Fatalf("SetPendingRole() error = %v", err)
+	}
+
+	recordClaim := func(userID string, expiresAt time.Time) {
+		t.Helper()
+		_ = platform.AddRole(testGuildID, userID, pendingRoleID)
+		err := configManager.RecordPendingClaim(testGuildID, userID, &storage.PendingClaim{
+			Type:        string(core.ClaimTypeUserLink),
+			CreatedAt:   time.Now().Add(-time.Hour),
+			ExpiresAt:   expiresAt,
+			RoleGranted: true,
+		})
+		if err != nil {
+			t.Fatalf("RecordPendingClaim() error = %v", err)
+		}
+	}
+
+	// Completing the link swaps the pending role for the verified role
+	recordClaim(testUserID, time.Now().Add(time.Hour))
+	if err := eh.removePendingRoleFromUser(testGuildID, testUserID); err != nil {
+		t.Fatalf("removePendingRoleFromUser() error = %v", err)
+	}
+	if err := eh.addVerifiedRoleToUser(testGuildID, testUserID); err != nil {
+		t.Fatalf("addVerifiedRoleToUser() error = %v", err)
+	}
+	if has, _ := platform.HasRole(testGuildID, testUserID, pendingRoleID); has {
+		t.Error("pending role should be removed once the link is observed")
+	}
+	if has, _ := platform.HasRole(testGuildID, testUserID, testVerifiedID); !has {
+		t.Error("verified role should be granted once the link is observed")
+	}
+
+	// Expired claims lose the pending role; active ones keep it
+	recordClaim("expired-user", time.Now().Add(-time.Minute))
+	recordClaim("active-user", time.Now().Add(time.Hour))
+	if err := eh.CleanupExpiredPendingClaims(testGuildID); err != nil {
+		t.Fatalf("CleanupExpiredPendingClaims() error = %v", err)
+	}
+
+	if has, _ := platform.HasRole(testGuildID, "expired-user", pendingRoleID); has {
+		t.Error("pending role should be removed when the claim expires")
+	}
+	if has, _ := platform.HasRole(testGuildID, "active-user", pendingRoleID); !has {
+		t.Error("pending role should be kept while the claim is active")
+	}
+
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	if _, exists := guildConfig.PendingClaims["expired-user"]; exists {
+		t.Error("expired claim should be removed from storage")
+	}
+	if _, exists := guildConfig.PendingClaims["active-user"]; !exists {
+		t.Error("active claim should be kept in storage")
+	}
+}
+
+func TestUserEventsHandler_SkipsMalformedEvents(t *testing.T) {
+	eh, platform, _ := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+
+	registry := CreateCoreQueryRegistry(logger, eh)
+	queryDef, _ := registry.GetQuery(UserEventsQueryID)
+
+	// The indexer renamed discordID, so the event must not be acted on
+	tx := graphql.Transaction{
+		Hash:        "tx-hash-1",
+		BlockHeight: 10,
+		Index:       2,
+		Response: graphql.TransactionResponse{Events: []graphql.GnoEvent{{
+			Type: "UserLinked",
+			Attrs: []graphql.EventAttribute{
+				{Key: "address", Value: testAddress},
+				{Key: "discord_id", Value: testUserID},
+			},
+		}}},
+	}
+
+	guildConfig := storage.NewGuildConfig(testGuildID)
+	state := guildConfig.EnsureQueryState(UserEventsQueryID, true)
+	if err := queryDef.Handler(t.Context(), []any{tx}, guildConfig, state); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	if hasRole, _ := platform.HasRole(testGuildID, testUserID, testVerifiedID); hasRole {
+		t.Error("malformed UserLinked event should not grant the verified role")
+	}
+	if len(state.SkippedEvents) != 1 {
+		t.Fatalf("skipped events = %d, want 1", len(state.SkippedEvents))
+	}
+	skipped := state.SkippedEvents[0]
+	if skipped.TxHash != tx.Hash || skipped.BlockHeight != 10 || skipped.TxIndex != 2 || skipped.EventType != "UserLinked" {
+		t.Errorf("skipped event = %+v", skipped)
+	}
+	if block, index := state.GetProcessingPosition(); block != 10 || index != 2 {
+		t.Errorf("position = %d/%d, want 10/2 so the guild is not stalled", block, index)
+	}
+
+	// Replaying the same transaction does not duplicate the record
+	state.LastProcessedBlock = 0
+	if err := queryDef.Handler(t.Context(), []any{tx}, guildConfig, state); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if len(state.SkippedEvents) != 1 {
+		t.Errorf("skipped events after replay = %d, want 1", len(state.SkippedEvents))
+	}
+}
+
+func TestSyncUserRealmRoles_RoleSyncOrder(t *testing.T) {
+	tests := []struct {
+		order storage.RoleSyncOrder
+		want  []string
+	}{
+		{order: storage.RoleSyncOrderInterleaved, want: []string{"remove:stale-1", "add:new-1", "remove:stale-2", "add:new-2"}},
+		{order: storage.RoleSyncOrderAddsFirst, want: []string{"add:new-1", "add:new-2", "remove:stale-1", "remove:stale-2"}},
+		{order: storage.RoleSyncOrderRemovesFirst, want: []string{"remove:stale-1", "remove:stale-2", "add:new-1", "add:new-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+
+			// Mappings alternate between roles the user should lose and roles they should gain
+			roleFlow := &mockRoleLinkingFlow{members: map[string][]string{}}
+			for _, roleID := range []string{"stale-1", "new-1", "stale-2", "new-2"} {
+				roleFlow.mappings = append(roleFlow.mappings, &core.RoleMapping{
+					RealmPath:     testRealmPath,
+					RealmRoleName: roleID,
+					PlatformRole:  core.PlatformRole{ID: roleID, Name: roleID},
+				})
+				if strings.HasPrefix(roleID, "new") {
+					roleFlow.members[testRealmPath+":"+roleID] = []string{testAddress}
+				} else {
+					_ = platform.AddRole(testGuildID, testUserID, roleID)
+				}
+			}
+			eh.roleLinkingFlow = roleFlow
+			platform.ops = nil
+
+			guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+			guildConfig.SetString(storage.SettingRoleSyncOrder, string(tt.order))
+			_ = configManager.UpdateGuildConfig(testGuildID, guildConfig)
+
+			if err := eh.syncUserRealmRoles(testGuildID, testUserID, testAddress); err != nil {
+				t.Fatalf("syncUserRealmRoles() error = %v", err)
+			}
+			if !slices.Equal(platform.ops, tt.want) {
+				t.Errorf("role changes = %v, want %v", platform.ops, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckLinkConflict_HoldsConflictingAccount(t *testing.T) {
+	const otherUserID = "user-2"
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	eh.roleLinkingFlow.(*mockRoleLinkingFlow).members[testRealmPath+":member"] = []string{testAddress}
+	eh.userLinkingFlow.(*mockUserLinkingFlow).addresses[otherUserID] = testAddress
+
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	guildConfig.SetString(storage.SettingLinkUniqueness, string(storage.LinkUniquenessAddress))
+	guildConfig.SetString(storage.SettingLinkConflictAction, string(storage.LinkConflictActionHold))
+	_ = configManager.UpdateGuildConfig(testGuildID, guildConfig)
+
+	// The first account to link the address is accepted
+	if held := eh.checkLinkConflict(testGuildID, testUserID, testAddress); held {
+		t.Fatal("first link of an address should not be held")
+	}
+
+	// A second account linking the same address is held
+	if held := eh.checkLinkConflict(testGuildID, otherUserID, testAddress); !held {
+		t.Fatal("second link of an address should be held")
+	}
+	guildConfig, _ = configManager.GetGuildConfig(testGuildID)
+	conflict := guildConfig.LinkConflicts[otherUserID]
+	if conflict == nil || conflict.Kind != storage.LinkConflictSharedAddress || conflict.ConflictingUserID != testUserID {
+		t.Fatalf("unexpected conflict record %+v", conflict)
+	}
+
+	// Verification grants nothing to the held account until an admin approves it
+	otherMember := &discordgo.Member{User: &discordgo.User{ID: otherUserID, Username: "other"}}
+	if err := eh.processUserVerification(t.Context(), testGuildID, otherMember); err != nil {
+		t.Fatalf("processUserVerification() error = %v", err)
+	}
+	for _, roleID := range []string{testVerifiedID, testMemberRole} {
+		if hasRole, _ := platform.HasRole(testGuildID, otherUserID, roleID); hasRole {
+			t.Errorf("held account should not be granted role %s", roleID)
+		}
+	}
+
+	if approved, err := configManager.ApproveLinkConflict(testGuildID, otherUserID); err != nil || !approved {
+		t.Fatalf("ApproveLinkConflict() = %v, %v", approved, err)
+	}
+	if err := eh.processUserVerification(t.Context(), testGuildID, otherMember); err != nil {
+		t.Fatalf("processUserVerification() error = %v", err)
+	}
+	for _, roleID := range []string{testVerifiedID, testMemberRole} {
+		if hasRole, _ := platform.HasRole(testGuildID, otherUserID, roleID); !hasRole {
+			t.Errorf("approved account should be granted role %s", roleID)
+		}
+	}
+}
+
+func TestCheckLinkConflict_WarnsWithoutHolding(t *testing.T) {
+	eh, _, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	guildConfig.SetString(storage.SettingLinkUniqueness, string(storage.LinkUniquenessDiscord))
+	_ = configManager.UpdateGuildConfig(testGuildID, guildConfig)
+
+	m := metrics.New()
+	eh.SetMetrics(m)
+	eh.checkLinkConflict(testGuildID, testUserID, testAddress)
+	if held := eh.checkLinkConflict(testGuildID, testUserID, "g1otheraddress"); held {
+		t.Fatal("warn action should not hold the account")
+	}
+
+	guildConfig, _ = configManager.GetGuildConfig(testGuildID)
+	conflict := guildConfig.LinkConflicts[testUserID]
+	if conflict == nil || conflict.Kind != storage.LinkConflictMultipleAddresses || conflict.ConflictingAddress != testAddress {
+		t.Fatalf("unexpected conflict record %+v", conflict)
+	}
+	if guildConfig.IsLinkHeld(testUserID) {
+		t.Error("warn action should not hold the account")
+	}
+	want := `
+		# HELP gnolinker_link_conflicts_total User links breaking a guild's link uniqueness rules, by conflict kind.
+		# TYPE gnolinker_link_conflicts_total counter
+		gnolinker_link_conflicts_total{kind="multiple-addresses"} 1
+	`
+	if err := testutil.GatherAndCompare(m.Registry(), strings.NewReader(want), "gnolinker_link_conflicts_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+// membersTransport answers Discord's guild members endpoint with a fixed member list
+type membersTransport struct {
+	members []*discordgo.Member
+}
+
+func (m membersTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := "[]"
+	if req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/members") {
+		data, err := json.Marshal(m.members)
+		if err != nil {
+			return nil, err
+		}
+		body = string(data)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestResyncRole_ReconcilesOnlyTargetedRole(t *testing.T) {
+	const (
+		otherRealmRole = "300000000000000001"
+		holderID       = "user-holder"
+		staleID        = "user-stale"
+		unlinkedID     = "user-unlinked"
+	)
+	eh, platform, _ := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	userFlow := eh.userLinkingFlow.(*mockUserLinkingFlow)
+	userFlow.addresses[holderID] = "g1holder"
+	userFlow.addresses[staleID] = "g1stale"
+	eh.roleLinkingFlow.(*mockRoleLinkingFlow).members[testRealmPath+":member"] = []string{"g1holder"}
+
+	// The stale and unlinked members hold the targeted role without the realm role, and
+	// everyone holds another managed role they also lack the realm role for
+	members := []*discordgo.Member{
+		{User: &discordgo.User{ID: holderID}, Roles: []string{otherRealmRole}},
+		{User: &discordgo.User{ID: staleID}, Roles: []string{testMemberRole, otherRealmRole}},
+		{User: &discordgo.User{ID: unlinkedID}, Roles: []string{testMemberRole, otherRealmRole}},
+	}
+	for _, member := range members {
+		for _, roleID := range member.Roles {
+			_ = platform.AddRole(testGuildID, member.User.ID, roleID)
+		}
+	}
+	platform.ops = nil
+
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: membersTransport{members: members}}
+	eh.session = session
+
+	result, err := eh.ResyncRole(testGuildID, testRealmPath, "member", testMemberRole)
+	if err != nil {
+		t.Fatalf("ResyncRole() error = %v", err)
+	}
+
+	if result.Checked != 3 || result.Failed != 0 {
+		t.Errorf("checked %d with %d failures, want 3 and 0", result.Checked, result.Failed)
+	}
+	if !slices.Equal(result.Added, []string{holderID}) {
+		t.Errorf("Added = %v, want [%s]", result.Added, holderID)
+	}
+	if removed := slices.Sorted(slices.Values(result.Removed)); !slices.Equal(removed, []string{staleID, unlinkedID}) {
+		t.Errorf("Removed = %v, want [%s %s]", removed, staleID, unlinkedID)
+	}
+
+	for _, op := range platform.ops {
+		if !strings.HasSuffix(op, ":"+testMemberRole) {
+			t.Errorf("resync touched a role other than the target: %s", op)
+		}
+	}
+	for _, member := range members {
+		if has, _ := platform.HasRole(testGuildID, member.User.ID, otherRealmRole); !has {
+			t.Errorf("member %s lost an untargeted role", member.User.ID)
+		}
+	}
+}
+
+func TestDepartedMembers_SkippedUntilRejoin(t *testing.T) {
+	const departedID = "user-departed"
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	eh.userLinkingFlow.(*mockUserLinkingFlow).addresses[departedID] = "g1departed"
+
+	departed := &discordgo.Member{User: &discordgo.User{ID: departedID, Username: "departed"}}
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: membersTransport{members: []*discordgo.Member{testMember(), departed}}}
+	eh.session = session
+
+	if err := eh.HandleMemberLeft(testGuildID, departedID); err != nil {
+		t.Fatalf("HandleMemberLeft() error = %v", err)
+	}
+
+	state := storage.NewGuildQueryState(testGuildID, "verify_low_priority", true)
+	if err := eh.ProcessTieredVerification(t.Context(), testGuildID, state, "low", 10); err != nil {
+		t.Fatalf("ProcessTieredVerification() error = %v", err)
+	}
+	if has, _ := platform.HasRole(testGuildID, testUserID, testVerifiedID); !has {
+		t.Error("present member should be verified by the sweep")
+	}
+	if has, _ := platform.HasRole(testGuildID, departedID, testVerifiedID); has {
+		t.Error("departed member should be excluded from the sweep")
+	}
+
+	// Rejoining clears the departure and verifies the member right away
+	if err := eh.HandleMemberJoined(t.Context(), testGuildID, departed); err != nil {
+		t.Fatalf("HandleMemberJoined() error = %v", err)
+	}
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	if guildConfig.IsMemberDeparted(departedID) {
+		t.Error("rejoined member should no longer be marked as departed")
+	}
+	if has, _ := platform.HasRole(testGuildID, departedID, testVerifiedID); !has {
+		t.Error("rejoined member should be verified again")
+	}
+}
+
+func TestDepartedMembers_CleanupForgetsMemberState(t *testing.T) {
+	eh, _, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	guildConfig.RecordBotAssignedRole(testUserID, testMemberRole)
+	guildConfig.RecordLinkedAddress(testUserID, testAddress)
+	_ = configManager.UpdateGuildConfig(testGuildID, guildConfig)
+
+	// State is kept by default so a quick rejoin loses nothing
+	if err := eh.HandleMemberLeft(testGuildID, testUserID); err != nil {
+		t.Fatalf("HandleMemberLeft() error = %v", err)
+	}
+	guildConfig, _ = configManager.GetGuildConfig(testGuildID)
+	if !guildConfig.IsBotAssignedRole(testUserID, testMemberRole) {
+		t.Fatal("member state should be kept without cleanup")
+	}
+
+	guildConfig.SetBool(storage.SettingCleanupDepartedMembers, true)
+	_ = configManager.UpdateGuildConfig(testGuildID, guildConfig)
+	if err := eh.HandleMemberLeft(testGuildID, testUserID); err != nil {
+		t.Fatalf("HandleMemberLeft() error = %v", err)
+	}
+	guildConfig, _ = configManager.GetGuildConfig(testGuildID)
+	if guildConfig.IsBotAssignedRole(testUserID, testMemberRole) {
+		t.Error("cleanup should forget bot-assigned roles")
+	}
+	if _, exists := guildConfig.LinkedAddresses[testUserID]; exists {
+		t.Error("cleanup should forget the linked address")
+	}
+	if !guildConfig.IsMemberDeparted(testUserID) {
+		t.Error("cleanup should keep the departure record")
+	}
+}
+
+func TestImportRoleBaseline_SparesLinkedMembersOnFirstPass(t *testing.T) {
+	const (
+		unlinkedID    = "user-unlinked"
+		unmanagedRole = "300000000000000001"
+	)
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+
+	// Both members were manually given the managed member role without holding the realm role
+	linked := &discordgo.Member{User: &discordgo.User{ID: testUserID, Username: "tester"}, Roles: []string{testMemberRole, unmanagedRole}}
+	unlinked := &discordgo.Member{User: &discordgo.User{ID: unlinkedID, Username: "unlinked"}, Roles: []string{testMemberRole}}
+	for _, member := range []*discordgo.Member{linked, unlinked} {
+		for _, roleID := range member.Roles {
+			_ = platform.AddRole(testGuildID, member.User.ID, roleID)
+		}
+	}
+
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: membersTransport{members: []*discordgo.Member{linked, unlinked}}}
+	eh.session = session
+
+	result, err := eh.ImportRoleBaseline(testGuildID)
+	if err != nil {
+		t.Fatalf("ImportRoleBaseline() error = %v", err)
+	}
+	if result.Members != 2 || result.Assignments != 2 || result.ManagedRoles != 1 {
+		t.Errorf("result = %+v, want 2 members with 2 assignments of 1 managed role", result)
+	}
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	for _, userID := range []string{testUserID, unlinkedID} {
+		if !guildConfig.IsBaselineRole(userID, testMemberRole) {
+			t.Errorf("baseline should record the member role held by %s", userID)
+		}
+	}
+	if guildConfig.IsBaselineRole(testUserID, unmanagedRole) {
+		t.Error("baseline should only record managed roles")
+	}
+
+	verify := func() {
+		t.Helper()
+		state := storage.NewGuildQueryState(testGuildID, "verify_low_priority", true)
+		if err := eh.ProcessTieredVerification(t.Context(), testGuildID, state, "low", 10); err != nil {
+			t.Fatalf("ProcessTieredVerification() error = %v", err)
+		}
+	}
+
+	// The first pass spares the linked member's baseline role but removes the orphaned one
+	verify()
+	if has, _ := platform.HasRole(testGuildID, testUserID, testMemberRole); !has {
+		t.Error("linked member should keep their baseline role on the first pass")
+	}
+	if has, _ := platform.HasRole(testGuildID, unlinkedID, testMemberRole); has {
+		t.Error("unlinked member's baseline role is orphaned and should be removed")
+	}
+	guildConfig, _ = configManager.GetGuildConfig(testGuildID)
+	if len(guildConfig.RoleBaseline) != 0 {
+		t.Errorf("baseline should be consumed by the first pass, got %v", guildConfig.RoleBaseline)
+	}
+
+	// Later passes reconcile to on-chain truth
+	verify()
+	if has, _ := platform.HasRole(testGuildID, testUserID, testMemberRole); has {
+		t.Error("baseline role should be removed after the first pass")
+	}
+}
+
+func TestRoleAutoSync_ExcludedRoleIsNeverApplied(t *testing.T) {
+	const testAdminRole = "200000000000000003"
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	roleFlow := eh.roleLinkingFlow.(*mockRoleLinkingFlow)
+	roleFlow.mappings = append(roleFlow.mappings, &core.RoleMapping{
+		RealmPath:     testRealmPath,
+		RealmRoleName: "admin",
+		PlatformRole:  core.PlatformRole{ID: testAdminRole, Name: "admin"},
+	})
+	if err := configManager.SetRoleAutoSync(testGuildID, testRealmPath, "member", false); err != nil {
+		t.Fatalf("SetRoleAutoSync() error = %v", err)
+	}
+
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: membersTransport{members: []*discordgo.Member{testMember()}}}
+	eh.session = session
+
+	verify := func() {
+		t.Helper()
+		state := storage.NewGuildQueryState(testGuildID, "verify_low_priority", true)
+		if err := eh.ProcessTieredVerification(t.Context(), testGuildID, state, "low", 10); err != nil {
+			t.Fatalf("ProcessTieredVerification() error = %v", err)
+		}
+	}
+
+	// Holding both realm roles only grants the auto-synced one
+	roleFlow.members[testRealmPath+":member"] = []string{testAddress}
+	roleFlow.members[testRealmPath+":admin"] = []string{testAddress}
+	verify()
+	if has, _ := platform.HasRole(testGuildID, testUserID, testAdminRole); !has {
+		t.Error("auto-synced role should be granted")
+	}
+	if has, _ := platform.HasRole(testGuildID, testUserID, testMemberRole); has {
+		t.Error("role excluded from auto-sync should not be granted")
+	}
+
+	// A manual grant of the excluded role survives losing the realm role
+	_ = platform.AddRole(testGuildID, testUserID, testMemberRole)
+	roleFlow.members[testRealmPath+":member"] = nil
+	roleFlow.members[testRealmPath+":admin"] = nil
+	verify()
+	if has, _ := platform.HasRole(testGuildID, testUserID, testMemberRole); !has {
+		t.Error("role excluded from auto-sync should not be removed")
+	}
+	if has, _ := platform.HasRole(testGuildID, testUserID, testAdminRole); has {
+		t.Error("auto-synced role should be removed")
+	}
+
+	// Resyncing the excluded role leaves its members alone without listing them
+	result, err := eh.ResyncRole(testGuildID, testRealmPath, "member", testMemberRole)
+	if err != nil {
+		t.Fatalf("ResyncRole() error = %v", err)
+	}
+	if result.Checked != 0 || len(result.Added) != 0 || len(result.Removed) != 0 {
+		t.Errorf("result = %+v, want no members checked", result)
+	}
+}
+
+func TestRefreshMonitoredRealms_PicksUpNewRealm(t *testing.T) {
+	const newRealm = "gno.land/r/demo/boards"
+	eh, _, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+
+	// A role linked in another realm after the realms were cached is not monitored yet
+	roleFlow := eh.roleLinkingFlow.(*mockRoleLinkingFlow)
+	roleFlow.mappings = append(roleFlow.mappings, &core.RoleMapping{
+		RealmPath:     newRealm,
+		RealmRoleName: "moderator",
+		PlatformRole:  core.PlatformRole{ID: "200000000000000004", Name: "moderator"},
+	})
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	if got := eh.getMonitoredRealms(guildConfig); slices.Contains(got, newRealm) {
+		t.Fatalf("cached realms %v should not include the new realm before a refresh", got)
+	}
+
+	realms, err := eh.RefreshMonitoredRealms(testGuildID)
+	if err != nil {
+		t.Fatalf("RefreshMonitoredRealms() error = %v", err)
+	}
+	want := []string{newRealm, testRealmPath}
+	if !slices.Equal(realms, want) {
+		t.Errorf("RefreshMonitoredRealms() = %v, want %v", realms, want)
+	}
+
+	guildConfig, _ = configManager.GetGuildConfig(testGuildID)
+	if !slices.Equal(guildConfig.MonitoredRealms, want) {
+		t.Errorf("persisted realms = %v, want %v", guildConfig.MonitoredRealms, want)
+	}
+	if guildConfig.MonitoredRealmsDiscoveredAt.IsZero() {
+		t.Error("refresh should record the discovery time")
+	}
+}
+
+// failingTransport answers every Discord API request with an error
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"message": "Missing Access", "code": 50001}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRoleEventsHandler_DeadLettersPersistentFailures(t *testing.T) {
+	eh, _, _ := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: failingTransport{}}
+	if err := session.State.GuildAdd(&discordgo.Guild{ID: testGuildID}); err != nil {
+		t.Fatalf("GuildAdd() error = %v", err)
+	}
+	eh.session = session
+
+	registry := CreateCoreQueryRegistry(logger, eh)
+	queryDef, _ := registry.GetQuery(RoleEventsQueryID)
+
+	roleLinked := func(hash string, index int64) graphql.Transaction {
+		return graphql.Transaction{
+			Hash:        hash,
+			BlockHeight: 10,
+			Index:       index,
+			Response: graphql.TransactionResponse{Events: []graphql.GnoEvent{{
+				Type: "RoleLinked",
+				Attrs: []graphql.EventAttribute{
+					{Key: "realmPath", Value: testRealmPath},
+					{Key: "roleName", Value: "member"},
+					{Key: "discordGuildID", Value: testGuildID},
+					{Key: "discordRoleID", Value: testMemberRole},
+				},
+			}}},
+		}
+	}
+	bad := roleLinked("tx-bad", 1)
+
+	guildConfig := storage.NewGuildConfig(testGuildID)
+	state := guildConfig.EnsureQueryState(RoleEventsQueryID, true)
+	state.UpdateProcessingPosition(10, 0)
+
+	// Every attempt before the last fails and leaves the position before the transaction
+	for attempt := 1; attempt < config.DefaultEventMaxAttempts; attempt++ {
+		if err := queryDef.Handler(t.Context(), []any{bad}, guildConfig, state); err == nil {
+			t.Fatalf("attempt %d: handler should report the failure so the transaction is retried", attempt)
+		}
+		if block, index := state.GetProcessingPosition(); block != 10 || index != 0 {
+			t.Fatalf("attempt %d: position = %d/%d, want 10/0", attempt, block, index)
+		}
+		if state.FailingTxAttempts != attempt {
+			t.Errorf("attempt %d: failing attempts = %d", attempt, state.FailingTxAttempts)
+		}
+	}
+
+	// The last attempt dead-letters the transaction and moves past it
+	if err := queryDef.Handler(t.Context(), []any{bad}, guildConfig, state); err != nil {
+		t.Fatalf("final attempt: handler error = %v, want the transaction dead-lettered", err)
+	}
+	if block, index := state.GetProcessingPosition(); block != 10 || index != 1 {
+		t.Errorf("position = %d/%d, want 10/1 so the queue is not blocked", block, index)
+	}
+	if len(state.DeadLetters) != 1 {
+		t.Fatalf("dead letters = %d, want 1", len(state.DeadLetters))
+	}
+	deadLetter := state.DeadLetters[0]
+	if deadLetter.TxHash != "tx-bad" || deadLetter.EventType != "RoleLinked" || deadLetter.Attempts != config.DefaultEventMaxAttempts || deadLetter.LastError == "" {
+		t.Errorf("dead letter = %+v", deadLetter)
+	}
+	if state.FailingTxHash != "" || state.FailingTxAttempts != 0 {
+		t.Errorf("failure count should be cleared after dead-lettering, got %s/%d", state.FailingTxHash, state.FailingTxAttempts)
+	}
+}
+
+func TestRefreshMonitoredRealms_HyphenatedRoleName(t *testing.T) {
+	const boardsRealm = "gno.land/r/demo/boards"
+	eh, _, _ := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+
+	// Realm discovery uses the linked role mappings, not Discord role names, so a realm role
+	// with a hyphen in its legacy-named Discord role is discovered with its realm
+	roleFlow := eh.roleLinkingFlow.(*mockRoleLinkingFlow)
+	roleFlow.mappings = append(roleFlow.mappings, &core.RoleMapping{
+		RealmPath:     boardsRealm,
+		RealmRoleName: "core-dev",
+		PlatformRole:  core.PlatformRole{ID: "200000000000000005", Name: "core-dev-" + boardsRealm},
+	})
+
+	realms, err := eh.RefreshMonitoredRealms(testGuildID)
+	if err != nil {
+		t.Fatalf("RefreshMonitoredRealms() error = %v", err)
+	}
+	if !slices.Contains(realms, boardsRealm) {
+		t.Errorf("RefreshMonitoredRealms() = %v, want it to include %s", realms, boardsRealm)
+	}
+}
+
+func TestHandleUserLinked_AuditsVerifiedRoleGrant(t *testing.T) {
+	eh, platform, _ := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	eh.session, _ = newMembershipSession(t, []string{testGuildID}, []string{testGuildID}, nil)
+
+	store := storage.NewMemoryAuditStore()
+	auditLog := storage.NewBufferedAuditLog(store, time.Hour, eh.logger)
+	eh.SetAuditLog(auditLog)
+
+	err := eh.HandleUserLinked(Event{
+		Type:            UserLinkedEvent,
+		TransactionHash: "tx-link",
+		UserLinked:      &graphql.UserLinkedEvent{Address: testAddress, DiscordID: testUserID},
+	})
+	if err != nil {
+		t.Fatalf("HandleUserLinked() error = %v", err)
+	}
+	if has, _ := platform.HasRole(testGuildID, testUserID, testVerifiedID); !has {
+		t.Fatal("verified role was not granted")
+	}
+
+	// Entries are buffered until the log is flushed
+	if err := auditLog.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	entries, err := store.UserEntries(testGuildID, testUserID, 10)
+	if err != nil {
+		t.Fatalf("UserEntries() error = %v", err)
+	}
+	i := slices.IndexFunc(entries, func(entry storage.AuditEntry) bool {
+		return entry.RoleID == testVerifiedID
+	})
+	if i < 0 {
+		t.Fatalf("no audit entry for the verified role in %+v", entries)
+	}
+	if entry := entries[i]; entry.Action != storage.AuditActionGrant || entry.TxHash != "tx-link" || entry.CorrelationID == "" || entry.Time.IsZero() {
+		t.Errorf("verified role audit entry = %+v", entry)
+	}
+}
+
+func TestUserEvents_SkipPausedGuilds(t *testing.T) {
+	const pausedGuild = "100000000000000002"
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	eh.session, _ = newMembershipSession(t, []string{testGuildID, pausedGuild}, []string{testGuildID, pausedGuild}, nil)
+
+	paused := storage.NewGuildConfig(pausedGuild)
+	paused.VerifiedRoleID = testVerifiedID
+	if err := configManager.UpdateGuildConfig(pausedGuild, paused); err != nil {
+		t.Fatalf("failed to store guild config: %v", err)
+	}
+	if err := configManager.SetGuildPaused(pausedGuild, true); err != nil {
+		t.Fatalf("SetGuildPaused() error = %v", err)
+	}
+
+	// A user event handled by the active guild's processor leaves the paused guild alone
+	linked := Event{Type: UserLinkedEvent, UserLinked: &graphql.UserLinkedEvent{Address: testAddress, DiscordID: testUserID}}
+	if err := eh.HandleUserLinked(linked); err != nil {
+		t.Fatalf("HandleUserLinked() error = %v", err)
+	}
+	if has, _ := platform.HasRole(testGuildID, testUserID, testVerifiedID); !has {
+		t.Error("active guild should grant the verified role")
+	}
+	if has, _ := platform.HasRole(pausedGuild, testUserID, testVerifiedID); has {
+		t.Error("paused guild should not get the verified role")
+	}
+
+	_ = platform.AddRole(pausedGuild, testUserID, testVerifiedID)
+	unlinked := Event{Type: UserUnlinkedEvent, UserUnlinked: &graphql.UserUnlinkedEvent{Address: testAddress, DiscordID: testUserID}}
+	if err := eh.HandleUserUnlinked(unlinked); err != nil {
+		t.Fatalf("HandleUserUnlinked() error = %v", err)
+	}
+	if has, _ := platform.HasRole(testGuildID, testUserID, testVerifiedID); has {
+		t.Error("active guild should revoke the verified role")
+	}
+	if has, _ := platform.HasRole(pausedGuild, testUserID, testVerifiedID); !has {
+		t.Error("paused guild should keep the verified role")
+	}
+
+	// Once resumed, events reach the guild again
+	if err := configManager.SetGuildPaused(pausedGuild, false); err != nil {
+		t.Fatalf("SetGuildPaused() error = %v", err)
+	}
+	if err := eh.HandleUserUnlinked(unlinked); err != nil {
+		t.Fatalf("HandleUserUnlinked() error = %v", err)
+	}
+	if has, _ := platform.HasRole(pausedGuild, testUserID, testVerifiedID); has {
+		t.Error("resumed guild should revoke the verified role")
+	}
+}
+
+func TestProcessUserVerification_KeepsRolesWhileRealmQueriesPaused(t *testing.T) {
+	eh, platform, _ := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	_ = platform.AddRole(testGuildID, testUserID, testVerifiedID)
+	eh.userLinkingFlow.(*mockUserLinkingFlow).err = workflows.ErrCircuitOpen
+
+	if err := eh.processUserVerification(t.Context(), testGuildID, testMember()); !errors.Is(err, workflows.ErrCircuitOpen) {
+		t.Fatalf("processUserVerification() error = %v, want %v", err, workflows.ErrCircuitOpen)
+	}
+	if !slices.Contains(platform.roles[platform.key(testGuildID, testUserID)], testVerifiedID) {
+		t.Error("verified role removed although the linked address could not be queried")
+	}
+}
+
+func TestUpdateUserPriority_DoesNotClobberSharedTiers(t *testing.T) {
+	// Priority levels decoded together can share a backing array with spare capacity
+	backing := []string{"high-user", "medium-user", "low-user"}
+	priorityData := map[string][]string{
+		"high":   backing[0:1],
+		"medium": backing[1:2],
+		"low":    backing[2:3],
+	}
+	eh := &EventHandlers{}
+
+	eh.updateUserPriority(priorityData, testUserID, true)
+	if !slices.Contains(priorityData["high"], testUserID) {
+		t.Fatalf("high = %v, want it to contain %s", priorityData["high"], testUserID)
+	}
+	eh.updateUserPriority(priorityData, testUserID, false)
+
+	want := map[string][]string{
+		"high":   {"high-user"},
+		"medium": {"medium-user"},
+		"low":    {"low-user", testUserID},
+	}
+	for level, users := range want {
+		if !slices.Equal(priorityData[level], users) {
+			t.Errorf("%s = %v, want %v", level, priorityData[level], users)
+		}
+	}
+	if !slices.Equal(backing, []string{"high-user", "medium-user", "low-user"}) {
+		t.Errorf("shared backing array modified to %v", backing)
+	}
+}
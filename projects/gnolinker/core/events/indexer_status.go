@@ -0,0 +1,75 @@
+package events
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// IndexerStatus is a guild query loop's latest view of the indexer
+type IndexerStatus struct {
+	// Queried is false until the loop has run an event query
+	Queried bool
+
+	// Connected reports whether the last event query reached the indexer
+	Connected bool
+
+	// LastProcessedBlock is the block height the event queries have processed up to
+	LastProcessedBlock int64
+
+	// LastSuccess is when an event query last reached the indexer, zero if none has
+	LastSuccess time.Time
+
+	// LastError is the error of the last failed event query, cleared once one succeeds
+	LastError string
+}
+
+// indexerState records a query loop's indexer status in atomics, so admin commands can read it
+// while queries run
+type indexerState struct {
+	queried     atomic.Bool
+	connected   atomic.Bool
+	lastBlock   atomic.Int64
+	lastSuccess atomic.Int64 // unix nanoseconds
+	lastError   atomic.Pointer[string]
+}
+
+// succeeded records an event query that reached the indexer
+func (s *indexerState) succeeded(at time.Time) {
+	s.lastSuccess.Store(at.UnixNano())
+	s.lastError.Store(nil)
+	s.connected.Store(true)
+	s.queried.Store(true)
+}
+
+// failed records an event query that could not reach the indexer
+func (s *indexerState) failed(err error) {
+	message := err.Error()
+	s.lastError.Store(&message)
+	s.connected.Store(false)
+	s.queried.Store(true)
+}
+
+func (s *indexerState) status() IndexerStatus {
+	status := IndexerStatus{
+		Queried:            s.queried.Load(),
+		Connected:          s.connected.Load(),
+		LastProcessedBlock: s.lastBlock.Load(),
+	}
+	if last := s.lastSuccess.Load(); last != 0 {
+		status.LastSuccess = time.Unix(0, last)
+	}
+	if message := s.lastError.Load(); message != nil {
+		status.LastError = *message
+	}
+	return status
+}
+
+// IndexerStatus returns the indexer status seen by a guild's query loop, false if the guild has
+// no query processor
+func (qpm *QueryProcessorManager) IndexerStatus(guildID string) (IndexerStatus, bool) {
+	processor, exists := qpm.GetProcessor(guildID)
+	if !exists {
+		return IndexerStatus{}, false
+	}
+	return processor.indexer.status(), true
+}
@@ -0,0 +1,144 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+)
+
+// QueryLeaseTTL is how long a guild's query lease lasts; it is renewed once less than half remains
+const QueryLeaseTTL = 30 * time.Second
+
+// queryLeaseKey is the lock key for a guild's query lease
+func queryLeaseKey(guildID string) string {
+	return "query:guild:" + guildID
+}
+
+// queryLease makes a single instance drive a guild's queries and verification at a time. The
+// instance holding the lease processes; others stand by and take over once it is released or
+// expires. A nil lease is always held, for single-instance deployments.
+type queryLease struct {
+	lockManager lock.LockManager
+	key         string
+	ttl         time.Duration
+	logger      core.Logger
+
+	mu   sync.Mutex
+	held *lock.Lock
+	// keepers counts the running KeepAlive calls, which renew held in the background
+	keepers int
+}
+
+func newQueryLease(lockManager lock.LockManager, guildID string, logger core.Logger) *queryLease {
+	return &queryLease{
+		lockManager: lockManager,
+		key:         queryLeaseKey(guildID),
+		ttl:         QueryLeaseTTL,
+		logger:      logger,
+	}
+}
+
+// Hold reports whether this instance holds the lease, acquiring it if free and renewing it
+// when less than half its TTL remains
+func (l *queryLease) Hold(ctx context.Context) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held != nil && (l.keepers > 0 || l.held.RemainingTTL() > l.ttl/2) {
+		return true
+	}
+
+	if l.held != nil {
+		// Extend the lease in place, unless another instance already took it over
+		err := l.lockManager.RenewLock(ctx, l.held, l.ttl)
+		if err == nil {
+			return true
+		}
+		if errors.Is(err, lock.ErrLockNotHeld) {
+			l.logger.Warn("Query lease taken over by another instance", "key", l.key)
+		} else if !errors.Is(err, lock.ErrLockExpired) && !errors.Is(err, lock.ErrLockNotFound) {
+			l.logger.Warn("Failed to renew query lease", "key", l.key, "error", err)
+		}
+		l.held = nil
+	}
+
+	acquired, err := l.lockManager.AcquireLock(ctx, l.key, l.ttl)
+	if err != nil {
+		if !errors.Is(err, lock.ErrLockAcquisitionFailed) {
+			l.logger.Warn("Failed to acquire query lease", "key", l.key, "error", err)
+		}
+		return false
+	}
+	l.held = acquired
+	l.logger.Debug("Holding query lease", "key", l.key, "expires_at", acquired.ExpiresAt)
+	return true
+}
+
+// KeepAlive keeps the held lease renewed while work that may outlast its TTL runs, such as a
+// verification sweep. The returned context is cancelled as soon as a renewal fails, so the work
+// stops once another instance may have taken over. Call the returned stop function once the
+// work is done. A nil lease needs no renewal; without a held lease the context is cancelled.
+func (l *queryLease) KeepAlive(ctx context.Context) (context.Context, func()) {
+	if l == nil {
+		return context.WithCancel(ctx)
+	}
+	l.mu.Lock()
+	held := l.held
+	if held == nil {
+		l.mu.Unlock()
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		return ctx, cancel
+	}
+	l.keepers++
+	l.mu.Unlock()
+
+	workCtx, stopRenewal := lock.KeepAliveContext(ctx, l.lockManager, held, l.ttl)
+	var once sync.Once
+	return workCtx, func() {
+		once.Do(func() {
+			err := stopRenewal()
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.keepers--
+			if err != nil && l.held == held {
+				l.logger.Warn("Lost query lease while it was kept alive", "key", l.key, "error", err)
+				l.held = nil
+			}
+		})
+	}
+}
+
+// Held reports whether the lease is held and unexpired, without acquiring or renewing it
+func (l *queryLease) Held() bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held != nil && !l.held.IsExpired()
+}
+
+// Release gives up the lease so a standby instance can take over right away
+func (l *queryLease) Release(ctx context.Context) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held == nil {
+		return
+	}
+	if err := l.lockManager.ReleaseLock(ctx, l.held); err != nil && !errors.Is(err, lock.ErrLockNotHeld) && !errors.Is(err, lock.ErrLockNotFound) {
+		l.logger.Warn("Failed to release query lease", "key", l.key, "error", err)
+	}
+	l.held = nil
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// MemberDirectory looks up the guilds the bot manages and their members. Handlers use the
+// Discord session by default; platforms without one provide their own, describing their groups
+// and members with the same types.
+type MemberDirectory interface {
+	// Guilds returns the guilds the bot manages
+	Guilds() []*discordgo.Guild
+
+	// Guild returns a managed guild
+	Guild(guildID string) (*discordgo.Guild, error)
+
+	// Member returns a guild member, or an error if the user is not in the guild
+	Member(guildID, userID string) (*discordgo.Member, error)
+
+	// GuildMembers returns the members of a guild that verification sweeps go through
+	GuildMembers(guildID string) ([]*discordgo.Member, error)
+
+	// Presence returns a member's presence, or an error if it is unknown. Members without one
+	// are swept as offline.
+	Presence(guildID, userID string) (*discordgo.Presence, error)
+}
+
+// sessionDirectory looks guilds and members up in the Discord session's state, asking Discord
+// for what the state doesn't hold
+type sessionDirectory struct {
+	session *discordgo.Session
+}
+
+func (d sessionDirectory) Guilds() []*discordgo.Guild {
+	return d.session.State.Guilds
+}
+
+func (d sessionDirectory) Guild(guildID string) (*discordgo.Guild, error) {
+	return d.session.State.Guild(guildID)
+}
+
+// Member checks the state's member cache, filled with the guild members intent, before asking
+// Discord
+func (d sessionDirectory) Member(guildID, userID string) (*discordgo.Member, error) {
+	if member, err := d.session.State.Member(guildID, userID); err == nil && member != nil {
+		return member, nil
+	}
+	return d.session.GuildMember(guildID, userID)
+}
+
+func (d sessionDirectory) GuildMembers(guildID string) ([]*discordgo.Member, error) {
+	return d.session.GuildMembers(guildID, "", 1000)
+}
+
+func (d sessionDirectory) Presence(guildID, userID string) (*discordgo.Presence, error) {
+	return d.session.State.Presence(guildID, userID)
+}
+
+// SetMemberDirectory makes the handlers look guilds and members up in directory instead of the
+// Discord session
+func (eh *EventHandlers) SetMemberDirectory(directory MemberDirectory) {
+	eh.members = directory
+}
+
+// directory returns where guilds and members are looked up, or nil without a session or directory
+func (eh *EventHandlers) directory() MemberDirectory {
+	if eh.members != nil {
+		return eh.members
+	}
+	if eh.session == nil {
+		return nil
+	}
+	return sessionDirectory{session: eh.session}
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// notifyRoleChanges collects the role changes made while handling an on-chain event. Calling the
+// returned function sends each affected member of a guild with role change DMs enabled a single
+// DM summarizing their changes. eh must be scoped to the event (see withCorrelationID).
+func (eh *EventHandlers) notifyRoleChanges(event *Event) func() {
+	if eh.dryRun {
+		return func() {}
+	}
+	changes := &mutationLog{}
+	eh.mutations = changes
+
+	return func() {
+		type member struct{ guildID, userID string }
+		var members []member
+		byMember := make(map[member][]RoleMutation)
+		for _, mutation := range changes.list() {
+			m := member{mutation.GuildID, mutation.UserID}
+			if _, seen := byMember[m]; !seen {
+				members = append(members, m)
+			}
+			byMember[m] = append(byMember[m], mutation)
+		}
+
+		for _, m := range members {
+			eh.sendRoleChangeDM(event, m.guildID, m.userID, byMember[m])
+		}
+	}
+}
+
+// sendRoleChangeDM sends a member the role changes an event made in a guild, if the guild has role
+// change DMs enabled. Members who do not accept DMs are skipped.
+func (eh *EventHandlers) sendRoleChangeDM(event *Event, guildID, userID string, mutations []RoleMutation) {
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	if err != nil || !eh.configManager.ShouldNotifyRoleChanges(config) {
+		return
+	}
+
+	// The pending role only reflects the claim the member just completed
+	mutations = slices.DeleteFunc(slices.Clone(mutations), func(m RoleMutation) bool { return m.RoleID == config.PendingRoleID })
+	if len(mutations) == 0 {
+		return
+	}
+
+	content := eh.formatRoleChangeDM(event, guildID, mutations)
+	if err := eh.platform.SendDirectMessage(userID, content); err != nil {
+		var restErr *discordgo.RESTError
+		if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusForbidden {
+			eh.logger.Debug("Member does not accept DMs, skipping role change notification", "guild_id", guildID, "user_id", userID)
+			return
+		}
+		eh.logger.Warn("Failed to send role change notification", "guild_id", guildID, "user_id", userID, "error", err)
+	}
+}
+
+// formatRoleChangeDM summarizes a member's role changes in a guild and the transaction that caused them
+func (eh *EventHandlers) formatRoleChangeDM(event *Event, guildID string, mutations []RoleMutation) string {
+	guildName := guildID
+	if directory := eh.directory(); directory != nil {
+		if guild, err := directory.Guild(guildID); err == nil {
+			guildName = guild.Name
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Your roles in **%s** changed after an on-chain transaction:\n", guildName)
+	for _, mutation := range mutations {
+		roleName := mutation.RoleID
+		if role, err := eh.platform.GetRoleByID(guildID, mutation.RoleID); err == nil && role != nil && role.Name != "" {
+			roleName = role.Name
+		}
+
+		sign := "➕ Granted"
+		if mutation.Action == storage.AuditActionRevoke {
+			sign = "➖ Removed"
+		}
+		fmt.Fprintf(&b, "%s **%s**", sign, roleName)
+		if mutation.RealmPath != "" {
+			fmt.Fprintf(&b, " (`%s` at `%s`)", mutation.RealmRole, mutation.RealmPath)
+		}
+		b.WriteString("\n")
+	}
+	if event.TransactionHash != "" {
+		fmt.Fprintf(&b, "Transaction: `%s`", event.TransactionHash)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
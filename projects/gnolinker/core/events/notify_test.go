@@ -0,0 +1,64 @@
+package events
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestHandleUserLinked_NotifiesRoleChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		dmErr   error
+		wantDMs int
+	}{
+		{name: "enabled sends one DM for all changes", enabled: true, wantDMs: 1},
+		{name: "disabled sends nothing"},
+		{
+			name:    "closed DMs are skipped",
+			enabled: true,
+			dmErr:   fmt.Errorf("failed to send DM: %w", &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+			eh.session, _ = newMembershipSession(t, []string{testGuildID}, []string{testGuildID}, nil)
+			eh.roleLinkingFlow.(*mockRoleLinkingFlow).members[testRealmPath+":member"] = []string{testAddress}
+			platform.dmErr = tt.dmErr
+			if err := configManager.SetNotifyRoleChanges(testGuildID, tt.enabled); err != nil {
+				t.Fatalf("SetNotifyRoleChanges() error = %v", err)
+			}
+
+			err := eh.HandleUserLinked(Event{
+				Type:            UserLinkedEvent,
+				TransactionHash: "tx-link",
+				UserLinked:      &graphql.UserLinkedEvent{Address: testAddress, DiscordID: testUserID},
+			})
+			if err != nil {
+				t.Fatalf("HandleUserLinked() error = %v", err)
+			}
+
+			dms := platform.dms[testUserID]
+			if len(dms) != tt.wantDMs {
+				t.Fatalf("sent %d DMs, want %d: %q", len(dms), tt.wantDMs, dms)
+			}
+			if tt.wantDMs == 0 {
+				return
+			}
+			// The verified and realm role grants are batched into one message naming the transaction
+			for _, want := range []string{"➕ Granted **" + testVerifiedID + "**", "➕ Granted **" + testMemberRole + "** (`member` at `" + testRealmPath + "`)", "tx-link"} {
+				if !strings.Contains(dms[0], want) {
+					t.Errorf("DM %q does not mention %q", dms[0], want)
+				}
+			}
+		})
+	}
+}
@@ -2,12 +2,17 @@ package events
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+	"github.com/allinbits/labs/projects/gnolinker/core/metrics"
 	"github.com/allinbits/labs/projects/gnolinker/core/storage"
 )
 
@@ -17,6 +22,40 @@ type contextKey string
 // saveCallbackKey is the context key for the save callback function
 const saveCallbackKey contextKey = "saveCallback"
 
+// QueryStallTimeout is how long the query loops may go without keeping up with the chain before
+// the bot reports itself not ready
+const QueryStallTimeout = 2 * time.Minute
+
+// DefaultShutdownGrace is how long stopping waits for in-flight queries and verification passes
+// to reach a saved position
+const DefaultShutdownGrace = 20 * time.Second
+
+var (
+	// ErrStandby is returned for guild work left to the instance holding the guild's query lease
+	ErrStandby = errors.New("another instance holds the guild's query lease")
+	// ErrProcessorStopped is returned for guild work requested while the guild's processor is stopped
+	ErrProcessorStopped = errors.New("guild query processor is not running")
+)
+
+// queryProgress records when a guild query loop last kept up with the chain. It is lock-free so
+// readiness probes never wait on a running query. A nil *queryProgress records nothing.
+type queryProgress struct {
+	last atomic.Int64 // unix nanoseconds, zero until the first advance
+	now  func() time.Time
+}
+
+func newQueryProgress() *queryProgress {
+	return &queryProgress{now: time.Now}
+}
+
+// advance records that a query loop kept up with the chain
+func (p *queryProgress) advance() {
+	if p == nil {
+		return
+	}
+	p.last.Store(p.now().UnixNano())
+}
+
 // QueryProcessor manages query execution for a specific guild
 type QueryProcessor struct {
 	guildID               string
@@ -25,6 +64,11 @@ type QueryProcessor struct {
 	queryClient           *graphql.QueryClient
 	queryExecutor         *QueryExecutor
 	verificationScheduler *VerificationScheduler
+	lease                 *queryLease
+	stagger               *queryStagger
+	progress              *queryProgress
+	indexer               indexerState
+	metrics               *metrics.Metrics
 	logger                core.Logger
 	ctx                   context.Context
 	cancel                context.CancelFunc
@@ -40,6 +84,11 @@ type QueryProcessorManager struct {
 	store         storage.ConfigStore
 	queryClient   *graphql.QueryClient
 	eventHandlers *EventHandlers
+	lockManager   lock.LockManager
+	queryJitter   time.Duration
+	shutdownGrace time.Duration
+	progress      *queryProgress
+	guildCount    atomic.Int32
 	logger        core.Logger
 	ctx           context.Context
 	cancel        context.CancelFunc
@@ -55,10 +104,32 @@ func NewQueryProcessorManager(registry *QueryRegistry, store storage.ConfigStore
 		store:         store,
 		queryClient:   queryClient,
 		eventHandlers: eventHandlers,
+		queryJitter:   DefaultQueryJitter,
+		shutdownGrace: DefaultShutdownGrace,
+		progress:      newQueryProgress(),
 		logger:        logger,
 	}
 }
 
+// SetLockManager makes processors coordinate through a per-guild lease, so only one instance
+// processes a guild's events and verification at a time. It must be called before AddGuild.
+func (qpm *QueryProcessorManager) SetLockManager(lockManager lock.LockManager) {
+	qpm.lockManager = lockManager
+}
+
+// SetQueryJitter sets how much each query loop tick is moved, at most, to spread indexer load
+// across guilds. Zero keeps the ticks evenly spaced, still offset per guild. It must be called
+// before AddGuild.
+func (qpm *QueryProcessorManager) SetQueryJitter(jitter time.Duration) {
+	qpm.queryJitter = jitter
+}
+
+// SetShutdownGrace sets how long Stop waits for in-flight queries and verification passes to
+// reach a saved position. Zero waits until they do.
+func (qpm *QueryProcessorManager) SetShutdownGrace(grace time.Duration) {
+	qpm.shutdownGrace = grace
+}
+
 // Start starts the query processor manager
 func (qpm *QueryProcessorManager) Start(ctx context.Context) error {
 	qpm.mutex.Lock()
@@ -77,27 +148,53 @@ func (qpm *QueryProcessorManager) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop stops the query processor manager
+// Stop stops the query processor manager. No new query ticks or verification passes start, and
+// running ones stop at the next point where their position is saved. Stop waits for them up to
+// the shutdown grace period and returns an error if they are still running then.
 func (qpm *QueryProcessorManager) Stop() error {
 	qpm.mutex.Lock()
 	defer qpm.mutex.Unlock()
 
-	qpm.logger.Info("Stopping query processor manager")
+	qpm.logger.Info("Stopping query processor manager", "shutdown_grace", qpm.shutdownGrace)
 
 	if qpm.cancel != nil {
 		qpm.cancel()
 	}
 
-	// Stop all processors
-	for guildID, processor := range qpm.processors {
-		qpm.logger.Info("Stopping processor for guild", "guild_id", guildID)
-		if err := processor.Stop(); err != nil {
-			qpm.logger.Error("Failed to stop processor", "guild_id", guildID, "error", err)
+	// Stop all processors together, so each drains its in-flight work within the same grace period
+	processors := make(map[string]*QueryProcessor, len(qpm.processors))
+	maps.Copy(processors, qpm.processors)
+	stopped := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for guildID, processor := range processors {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				qpm.logger.Info("Stopping processor for guild", "guild_id", guildID)
+				if err := processor.Stop(); err != nil {
+					qpm.logger.Error("Failed to stop processor", "guild_id", guildID, "error", err)
+				}
+			}()
 		}
-	}
+		wg.Wait()
 
-	// Wait for all processors to stop
-	qpm.wg.Wait()
+		// Wait for all processors to stop
+		qpm.wg.Wait()
+		close(stopped)
+	}()
+
+	if qpm.shutdownGrace <= 0 {
+		<-stopped
+	} else {
+		timer := time.NewTimer(qpm.shutdownGrace)
+		defer timer.Stop()
+		select {
+		case <-stopped:
+		case <-timer.C:
+			return fmt.Errorf("query processors still running after the %s shutdown grace period", qpm.shutdownGrace)
+		}
+	}
 
 	qpm.logger.Info("Query processor manager stopped")
 	return nil
@@ -113,7 +210,13 @@ func (qpm *QueryProcessorManager) AddGuild(guildID string) error {
 	}
 
 	processor := NewQueryProcessor(guildID, qpm.registry, qpm.store, qpm.queryClient, qpm.eventHandlers, qpm.logger)
+	if qpm.lockManager != nil {
+		processor.useLease(qpm.lockManager)
+	}
+	processor.stagger = newQueryStagger(guildID, QueryLoopInterval, qpm.queryJitter)
+	processor.progress = qpm.progress
 	qpm.processors[guildID] = processor
+	qpm.guildCount.Add(1)
 
 	if qpm.ctx != nil {
 		qpm.wg.Add(1)
@@ -144,6 +247,7 @@ func (qpm *QueryProcessorManager) RemoveGuild(guildID string) error {
 	}
 
 	delete(qpm.processors, guildID)
+	qpm.guildCount.Add(-1)
 	qpm.logger.Info("Removed guild processor", "guild_id", guildID)
 	return nil
 }
@@ -157,6 +261,35 @@ func (qpm *QueryProcessorManager) GetProcessor(guildID string) (*QueryProcessor,
 	return processor, exists
 }
 
+// VerifyGuild runs a verification over every member of a guild on its processor, like the tiered
+// verification passes: it stops when the processor stops, and changes roles only while this
+// instance holds the guild's query lease. A dry run changes nothing, so it also runs on standby.
+func (qpm *QueryProcessorManager) VerifyGuild(ctx context.Context, guildID string, dryRun bool) (*VerificationReport, error) {
+	processor, exists := qpm.GetProcessor(guildID)
+	if !exists {
+		return nil, fmt.Errorf("processor for guild %s does not exist", guildID)
+	}
+	return processor.verifyGuild(ctx, qpm.eventHandlers, dryRun)
+}
+
+// CheckProgress returns an error if no guild query loop has kept up with the chain within maxAge.
+// A loop keeps up when its event stream queries reach the indexer, whether or not new blocks moved
+// its position; standby instances and paused guilds have nothing to keep up with. It does not
+// take the manager's lock, so it can serve readiness probes while processors start or stop.
+func (qpm *QueryProcessorManager) CheckProgress(maxAge time.Duration) error {
+	if qpm.guildCount.Load() == 0 {
+		return nil
+	}
+	last := qpm.progress.last.Load()
+	if last == 0 {
+		return fmt.Errorf("no query loop has advanced yet")
+	}
+	if age := qpm.progress.now().Sub(time.Unix(0, last)); age > maxAge {
+		return fmt.Errorf("no query loop has advanced for %s", age.Round(time.Second))
+	}
+	return nil
+}
+
 // startProcessorsForExistingGuilds starts processors for all existing guilds
 func (qpm *QueryProcessorManager) startProcessorsForExistingGuilds() error {
 	// This would need to be implemented to discover existing guilds
@@ -174,10 +307,21 @@ func NewQueryProcessor(guildID string, registry *QueryRegistry, store storage.Co
 		queryClient:           queryClient,
 		queryExecutor:         NewQueryExecutor(queryClient, logger),
 		verificationScheduler: NewVerificationScheduler(guildID, store, eventHandlers, logger),
+		stagger:               newQueryStagger(guildID, QueryLoopInterval, DefaultQueryJitter),
+		metrics:               eventHandlers.getMetrics(),
 		logger:                logger,
 	}
 }
 
+// useLease makes the processor and its verification scheduler run only while this instance
+// holds the guild's query lease
+func (qp *QueryProcessor) useLease(lockManager lock.LockManager) {
+	qp.lease = newQueryLease(lockManager, qp.guildID, qp.logger)
+	if qp.verificationScheduler != nil {
+		qp.verificationScheduler.lease = qp.lease
+	}
+}
+
 // Start starts the query processor
 func (qp *QueryProcessor) Start(ctx context.Context) error {
 	qp.mutex.Lock()
@@ -219,54 +363,100 @@ func (qp *QueryProcessor) Stop() error {
 
 	qp.logger.Info("Stopping query processor", "guild_id", qp.guildID)
 
-	// Stop verification scheduler first
+	// Cancelling first lets the query loop and a running verification pass drain together
+	if qp.cancel != nil {
+		qp.cancel()
+	}
+
 	if qp.verificationScheduler != nil {
 		if err := qp.verificationScheduler.Stop(); err != nil {
 			qp.logger.Error("Failed to stop verification scheduler", "guild_id", qp.guildID, "error", err)
 		}
 	}
 
-	if qp.cancel != nil {
-		qp.cancel()
-	}
-
 	qp.running = false
 
-	// Wait for query loop to finish
+	// Wait for the query loop to finish its current tick
 	qp.wg.Wait()
 
+	// Hand the guild over to a standby instance
+	qp.lease.Release(context.Background())
+
 	qp.logger.Info("Query processor stopped", "guild_id", qp.guildID)
 	return nil
 }
 
-// queryLoop is the main query execution loop
+// queryLoop is the main query execution loop, ticking on the guild's staggered schedule
 func (qp *QueryProcessor) queryLoop() {
 	defer qp.wg.Done()
 
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+	offset := qp.stagger.offset()
+	timer := time.NewTimer(offset)
+	defer timer.Stop()
 
-	qp.logger.Info("Starting query loop", "guild_id", qp.guildID)
+	qp.logger.Info("Starting query loop", "guild_id", qp.guildID, "offset", offset)
 
 	for {
 		select {
 		case <-qp.ctx.Done():
 			qp.logger.Info("Query loop stopped", "guild_id", qp.guildID)
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			qp.processQueries()
+			timer.Reset(qp.stagger.next())
+		}
+	}
+}
+
+// verifyGuild runs eh.VerifyGuild for the processor's guild until ctx or the processor is done.
+// Stop waits for the run as it does for a query tick.
+func (qp *QueryProcessor) verifyGuild(ctx context.Context, eh *EventHandlers, dryRun bool) (*VerificationReport, error) {
+	qp.mutex.RLock()
+	if !qp.running || qp.ctx.Err() != nil {
+		qp.mutex.RUnlock()
+		return nil, ErrProcessorStopped
+	}
+	qp.wg.Add(1)
+	processorCtx := qp.ctx
+	qp.mutex.RUnlock()
+	defer qp.wg.Done()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(processorCtx, cancel)()
+
+	if !dryRun {
+		if !qp.lease.Hold(runCtx) {
+			return nil, ErrStandby
 		}
+		var stopRenewal func()
+		runCtx, stopRenewal = qp.lease.KeepAlive(runCtx)
+		defer stopRenewal()
 	}
+	return eh.VerifyGuild(runCtx, qp.guildID, dryRun)
 }
 
 // processQueries processes all enabled queries for the guild
 func (qp *QueryProcessor) processQueries() {
+	// Another instance drives this guild while it holds the lease
+	if !qp.lease.Hold(qp.ctx) {
+		qp.logger.Debug("Query lease held by another instance, standing by", "guild_id", qp.guildID)
+		qp.progress.advance()
+		return
+	}
+
 	config, err := qp.store.Get(qp.guildID)
 	if err != nil {
 		qp.logger.Error("Failed to get guild config", "guild_id", qp.guildID, "error", err)
 		return
 	}
 
+	if config.Paused {
+		qp.logger.Debug("Guild processing paused, skipping queries", "guild_id", qp.guildID)
+		qp.progress.advance()
+		return
+	}
+
 	// Clean up old verification queries that are no longer used
 	// These are now handled by VerificationScheduler
 	obsoleteQueries := []string{"verify_high_priority", "verify_medium_priority", "verify_low_priority", "verify_members"}
@@ -304,6 +494,16 @@ func (qp *QueryProcessor) processQueries() {
 
 	// Process each enabled query
 	for _, queryID := range enabledQueries {
+		// Stopping lets the tick finish the query it is on, but starts no other
+		if qp.ctx.Err() != nil {
+			qp.logger.Info("Query processor stopping, skipping remaining queries", "guild_id", qp.guildID)
+			return
+		}
+		// Stop mutating as soon as the lease is lost; it is re-acquired on the next tick if free
+		if !qp.lease.Hold(qp.ctx) {
+			qp.logger.Warn("Lost query lease, stopping query processing", "guild_id", qp.guildID)
+			return
+		}
 		qp.logger.Debug("Processing query", "guild_id", qp.guildID, "query_id", queryID)
 		if err := qp.processQuery(queryID, config); err != nil {
 			qp.logger.Error("Failed to process query", "guild_id", qp.guildID, "query_id", queryID, "error", err)
@@ -334,15 +534,21 @@ func (qp *QueryProcessor) processQuery(queryID string, config *storage.GuildConf
 	// Set execution state to prevent concurrent runs
 	queryState.SetExecuting(true)
 	// Save the state immediately to prevent race conditions
-	if err := qp.store.Set(qp.guildID, config); err != nil {
+	if err := qp.saveQueryState(queryDef.QueryID, queryState); err != nil {
 		qp.logger.Error("Failed to save execution state", "guild_id", qp.guildID, "query_id", queryDef.QueryID, "error", err)
 		return err
 	}
 
 	// Ensure we clear execution state when done
+	started := time.Now()
 	defer func() {
+		qp.metrics.ObserveQuery(queryDef.QueryID, started)
+		if queryDef.QueryType == EventStreamQuery {
+			qp.metrics.SetLastProcessedBlock(qp.guildID, queryDef.QueryID, queryState.LastProcessedBlock)
+			qp.indexer.lastBlock.Store(queryState.LastProcessedBlock)
+		}
 		queryState.SetExecuting(false)
-		if err := qp.store.Set(qp.guildID, config); err != nil {
+		if err := qp.saveQueryState(queryDef.QueryID, queryState); err != nil {
 			qp.logger.Error("Failed to clear execution state", "guild_id", qp.guildID, "query_id", queryDef.QueryID, "error", err)
 		}
 	}()
@@ -364,17 +570,20 @@ func (qp *QueryProcessor) processEventStreamQuery(queryDef *QueryDefinition, que
 	results, err := qp.queryExecutor.ExecuteQuery(qp.ctx, queryDef, queryState)
 	if err != nil {
 		qp.logger.Error("Failed to execute query", "guild_id", qp.guildID, "query_id", queryDef.QueryID, "error", err)
+		qp.indexer.failed(err)
 		queryState.RecordError(err)
 		// Still update timestamp to avoid hammering failed queries
 		queryState.UpdateRunTimestamp(queryDef.Interval)
-		return qp.store.Set(qp.guildID, config)
+		return qp.saveQueryState(queryDef.QueryID, queryState)
 	}
+	qp.indexer.succeeded(time.Now())
+	qp.progress.advance()
 
 	// Process results and save position incrementally
 	if len(results) > 0 && queryDef.Handler != nil {
 		// Create a save callback for incremental state saving
 		saveCallback := func() error {
-			return qp.store.Set(qp.guildID, config)
+			return qp.saveQueryState(queryDef.QueryID, queryState)
 		}
 
 		// Create a wrapper that provides the save callback to the handler
@@ -404,8 +613,8 @@ func (qp *QueryProcessor) processEventStreamQuery(queryDef *QueryDefinition, que
 	// Update run timestamp
 	queryState.UpdateRunTimestamp(queryDef.Interval)
 
-	// Save updated config (final save for timestamp update)
-	return qp.store.Set(qp.guildID, config)
+	// Save updated state (final save for timestamp update)
+	return qp.saveQueryState(queryDef.QueryID, queryState)
 }
 
 // processGenericQuery processes non-event-stream queries (periodic, on-demand)
@@ -419,7 +628,7 @@ func (qp *QueryProcessor) processGenericQuery(queryDef *QueryDefinition, querySt
 		queryState.RecordError(err)
 		// Still update timestamp to avoid hammering failed queries
 		queryState.UpdateRunTimestamp(queryDef.Interval)
-		return qp.store.Set(qp.guildID, config)
+		return qp.saveQueryState(queryDef.QueryID, queryState)
 	}
 
 	// Call the query handler
@@ -436,7 +645,19 @@ func (qp *QueryProcessor) processGenericQuery(queryDef *QueryDefinition, querySt
 	// Update run timestamp
 	queryState.UpdateRunTimestamp(queryDef.Interval)
 
-	// Save updated config
+	// Save updated state
+	return qp.saveQueryState(queryDef.QueryID, queryState)
+}
+
+// saveQueryState saves a query's state into the latest stored config. Handlers record role
+// changes through the config manager while the query runs, so writing back the config read at
+// the start of the tick would discard them.
+func (qp *QueryProcessor) saveQueryState(queryID string, state *storage.GuildQueryState) error {
+	config, err := qp.store.Get(qp.guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild config: %w", err)
+	}
+	config.SetQueryState(queryID, state)
 	return qp.store.Set(qp.guildID, config)
 }
 
@@ -0,0 +1,407 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestQueryProcessor_SkipsPausedGuild(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	store := storage.NewMemoryConfigStore()
+	configManager := config.NewConfigManager(store, &config.StorageConfig{}, nil, logger)
+	if err := store.Set(testGuildID, storage.NewGuildConfig(testGuildID)); err != nil {
+		t.Fatalf("failed to store guild config: %v", err)
+	}
+
+	var handled int
+	registry := NewQueryRegistry()
+	registry.RegisterQuery(&QueryDefinition{
+		QueryID:   UserEventsQueryID,
+		QueryType: EventStreamQuery,
+		Handler: func(ctx context.Context, results []any, guild *storage.GuildConfig, state *storage.GuildQueryState) error {
+			handled += len(results)
+			return nil
+		},
+		Enabled: true,
+	})
+
+	client := &mockEventQueryClient{
+		height: 10,
+		txs:    []graphql.Transaction{{Hash: "tx1", BlockHeight: 5, Index: 1}},
+	}
+	processor := NewQueryProcessor(testGuildID, registry, store, nil, nil, logger)
+	processor.queryExecutor = NewQueryExecutor(client, logger)
+	processor.ctx = context.Background()
+
+	if err := configManager.SetGuildPaused(testGuildID, true); err != nil {
+		t.Fatalf("SetGuildPaused(true) failed: %v", err)
+	}
+	processor.processQueries()
+
+	if handled != 0 {
+		t.Errorf("paused guild handled %d results, want 0", handled)
+	}
+	guildConfig, _ := store.Get(testGuildID)
+	if _, exists := guildConfig.GetQueryState(UserEventsQueryID); exists {
+		t.Error("paused guild should not have its query state touched")
+	}
+
+	if err := configManager.SetGuildPaused(testGuildID, false); err != nil {
+		t.Fatalf("SetGuildPaused(false) failed: %v", err)
+	}
+	processor.processQueries()
+
+	if handled != 1 {
+		t.Errorf("resumed guild handled %d results, want 1", handled)
+	}
+	guildConfig, _ = store.Get(testGuildID)
+	state, exists := guildConfig.GetQueryState(UserEventsQueryID)
+	if !exists {
+		t.Fatal("resumed guild should have a user events query state")
+	}
+	if state.IsExecuting {
+		t.Error("query should not be left executing after a resumed run")
+	}
+}
+
+func TestQueryProcessor_KeepsRoleRecordsMadeDuringQuery(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	store := storage.NewMemoryConfigStore()
+	configManager := config.NewConfigManager(store, &config.StorageConfig{}, nil, logger)
+	if err := store.Set(testGuildID, storage.NewGuildConfig(testGuildID)); err != nil {
+		t.Fatalf("failed to store guild config: %v", err)
+	}
+
+	// The handler grants a role the way event handlers do, through the config manager, and then
+	// saves its position through the processor's save callback
+	registry := NewQueryRegistry()
+	registry.RegisterQuery(&QueryDefinition{
+		QueryID:   RoleEventsQueryID,
+		QueryType: EventStreamQuery,
+		Handler: func(ctx context.Context, results []any, guild *storage.GuildConfig, state *storage.GuildQueryState) error {
+			if err := configManager.RecordBotAssignedRole(testGuildID, "user-1", "role-1"); err != nil {
+				return err
+			}
+			tx := results[0].(graphql.Transaction)
+			state.UpdateProcessingPosition(tx.BlockHeight, tx.Index)
+			return ctx.Value(saveCallbackKey).(func() error)()
+		},
+		Enabled: true,
+	})
+
+	client := &mockEventQueryClient{
+		height: 10,
+		txs:    []graphql.Transaction{{Hash: "tx1", BlockHeight: 5, Index: 1}},
+	}
+	processor := NewQueryProcessor(testGuildID, registry, store, nil, nil, logger)
+	processor.queryExecutor = NewQueryExecutor(client, logger)
+	processor.ctx = context.Background()
+	processor.processQueries()
+
+	guildConfig, _ := store.Get(testGuildID)
+	if !guildConfig.IsBotAssignedRole("user-1", "role-1") {
+		t.Error("role recorded while the query ran was overwritten by the processor's saves")
+	}
+	state, _ := guildConfig.GetQueryState(RoleEventsQueryID)
+	if block, index := state.GetProcessingPosition(); block != 5 || index != 1 {
+		t.Errorf("saved position = (%d, %d), want (5, 1)", block, index)
+	}
+	if state.IsExecuting {
+		t.Error("query should not be left executing")
+	}
+}
+
+func TestQueryProcessor_LeaseSingleInstance(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	store := storage.NewMemoryConfigStore()
+	if err := store.Set(testGuildID, storage.NewGuildConfig(testGuildID)); err != nil {
+		t.Fatalf("failed to store guild config: %v", err)
+	}
+	lockManager := lock.NewMemoryLockManager(lock.LockConfig{})
+
+	// Two instances of the same guild's processor sharing storage and the lock backend
+	handled := make([]int, 2)
+	processors := make([]*QueryProcessor, 2)
+	for i := range processors {
+		registry := NewQueryRegistry()
+		registry.RegisterQuery(&QueryDefinition{
+			QueryID:   UserEventsQueryID,
+			QueryType: EventStreamQuery,
+			Handler: func(ctx context.Context, results []any, guild *storage.GuildConfig, state *storage.GuildQueryState) error {
+				handled[i] += len(results)
+				state.UpdateLastProcessedBlock(5)
+				return nil
+			},
+			Enabled: true,
+		})
+		client := &mockEventQueryClient{
+			height: 10,
+			txs:    []graphql.Transaction{{Hash: "tx1", BlockHeight: 5, Index: 1}},
+		}
+		processors[i] = NewQueryProcessor(testGuildID, registry, store, nil, nil, logger)
+		processors[i].queryExecutor = NewQueryExecutor(client, logger)
+		processors[i].ctx = context.Background()
+		processors[i].useLease(lockManager)
+	}
+
+	processors[0].processQueries()
+	processors[1].processQueries()
+
+	if handled[0] != 1 || handled[1] != 0 {
+		t.Errorf("handled = %v, want only the lease holder to process", handled)
+	}
+	guildConfig, _ := store.Get(testGuildID)
+	if state, _ := guildConfig.GetQueryState(UserEventsQueryID); state == nil || state.LastProcessedBlock != 5 {
+		t.Errorf("position = %+v, want it advanced to 5 by the lease holder", state)
+	}
+
+	// Releasing the lease, as Stop does, lets the standby instance take over
+	processors[0].lease.Release(context.Background())
+	if !processors[1].lease.Hold(context.Background()) {
+		t.Error("standby instance should acquire the released lease")
+	}
+	if processors[0].lease.Hold(context.Background()) {
+		t.Error("previous holder should stand by once the lease is taken over")
+	}
+}
+
+func TestQueryProcessorManager_VerifyGuildFollowsLease(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	eh.roleLinkingFlow.(*mockRoleLinkingFlow).members[testRealmPath+":member"] = []string{testAddress}
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: membersTransport{members: []*discordgo.Member{testMember()}}}
+	eh.session = session
+
+	lockManager := lock.NewMemoryLockManager(lock.LockConfig{})
+	qpm := NewQueryProcessorManager(NewQueryRegistry(), configManager.GetStore(), nil, eh, logger)
+	qpm.SetLockManager(lockManager)
+	if err := qpm.AddGuild(testGuildID); err != nil {
+		t.Fatalf("AddGuild() error = %v", err)
+	}
+
+	if _, err := qpm.VerifyGuild(t.Context(), testGuildID, false); !errors.Is(err, ErrProcessorStopped) {
+		t.Fatalf("VerifyGuild() before start error = %v, want ErrProcessorStopped", err)
+	}
+	processor, _ := qpm.GetProcessor(testGuildID)
+	processor.ctx, processor.cancel = context.WithCancel(t.Context())
+	processor.running = true
+
+	// A standby instance only reports what a run would change
+	standby := newQueryLease(lockManager, testGuildID, logger)
+	if !standby.Hold(t.Context()) {
+		t.Fatal("other instance should acquire the free lease")
+	}
+	if _, err := qpm.VerifyGuild(t.Context(), testGuildID, false); !errors.Is(err, ErrStandby) {
+		t.Fatalf("VerifyGuild() on standby error = %v, want ErrStandby", err)
+	}
+	if report, err := qpm.VerifyGuild(t.Context(), testGuildID, true); err != nil || !report.DryRun || len(report.Mutations) != 2 {
+		t.Fatalf("dry run on standby = %+v, %v, want the verified and member roles planned", report, err)
+	}
+	if len(platform.ops) != 0 {
+		t.Fatalf("standby instance mutated roles: %v", platform.ops)
+	}
+
+	// Once the lease is free the run takes it and changes roles
+	standby.Release(t.Context())
+	report, err := qpm.VerifyGuild(t.Context(), testGuildID, false)
+	if err != nil {
+		t.Fatalf("VerifyGuild() error = %v", err)
+	}
+	if report.DryRun || report.Checked != 1 || len(platform.ops) != 2 {
+		t.Errorf("report = %+v, ops = %v, want the verified and member roles granted", report, platform.ops)
+	}
+	if !processor.lease.Held() {
+		t.Error("verification should leave the lease with the processor")
+	}
+
+	// A stopping processor starts no run
+	processor.cancel()
+	if _, err := qpm.VerifyGuild(t.Context(), testGuildID, false); !errors.Is(err, ErrProcessorStopped) {
+		t.Errorf("VerifyGuild() while stopping error = %v, want ErrProcessorStopped", err)
+	}
+}
+
+func TestQueryProcessorManager_ReadinessFollowsQueryProgress(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	store := storage.NewMemoryConfigStore()
+	configManager := config.NewConfigManager(store, &config.StorageConfig{}, nil, logger)
+	if err := store.Set(testGuildID, storage.NewGuildConfig(testGuildID)); err != nil {
+		t.Fatalf("failed to store guild config: %v", err)
+	}
+
+	registry := NewQueryRegistry()
+	registry.RegisterQuery(&QueryDefinition{
+		QueryID:   UserEventsQueryID,
+		QueryType: EventStreamQuery,
+		Handler: func(ctx context.Context, results []any, guild *storage.GuildConfig, state *storage.GuildQueryState) error {
+			return nil
+		},
+		Enabled: true,
+	})
+	manager := NewQueryProcessorManager(registry, store, nil, nil, logger)
+	now := time.Now()
+	manager.progress.now = func() time.Time { return now }
+
+	if err := manager.CheckProgress(QueryStallTimeout); err != nil {
+		t.Errorf("CheckProgress() without guilds = %v, want ready", err)
+	}
+
+	if err := manager.AddGuild(testGuildID); err != nil {
+		t.Fatalf("AddGuild() failed: %v", err)
+	}
+	processor, _ := manager.GetProcessor(testGuildID)
+	client := &mockEventQueryClient{height: 10, err: errors.New("indexer unreachable")}
+	processor.queryExecutor = NewQueryExecutor(client, logger)
+	processor.ctx = context.Background()
+
+	// Not ready until a query loop reaches the indexer
+	processor.processQueries()
+	if err := manager.CheckProgress(QueryStallTimeout); err == nil {
+		t.Error("CheckProgress() before any query advanced = nil, want not ready")
+	}
+
+	client.err = nil
+	processor.processQueries()
+	if err := manager.CheckProgress(QueryStallTimeout); err != nil {
+		t.Errorf("CheckProgress() after the query advanced = %v, want ready", err)
+	}
+
+	// The indexer dropping stalls the loop until readiness fails
+	client.err = errors.New("indexer unreachable")
+	now = now.Add(QueryStallTimeout / 2)
+	processor.processQueries()
+	if err := manager.CheckProgress(QueryStallTimeout); err != nil {
+		t.Errorf("CheckProgress() within the stall timeout = %v, want ready", err)
+	}
+	now = now.Add(QueryStallTimeout)
+	processor.processQueries()
+	if err := manager.CheckProgress(QueryStallTimeout); err == nil {
+		t.Error("CheckProgress() with the indexer down past the stall timeout = nil, want not ready")
+	}
+
+	// A paused guild has nothing to keep up with
+	if err := configManager.SetGuildPaused(testGuildID, true); err != nil {
+		t.Fatalf("SetGuildPaused(true) failed: %v", err)
+	}
+	processor.processQueries()
+	if err := manager.CheckProgress(QueryStallTimeout); err != nil {
+		t.Errorf("CheckProgress() for a paused guild = %v, want ready", err)
+	}
+}
+
+func TestQueryProcessorManager_IndexerStatusFollowsQueries(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	store := storage.NewMemoryConfigStore()
+	if err := store.Set(testGuildID, storage.NewGuildConfig(testGuildID)); err != nil {
+		t.Fatalf("failed to store guild config: %v", err)
+	}
+
+	registry := NewQueryRegistry()
+	registry.RegisterQuery(&QueryDefinition{
+		QueryID:   UserEventsQueryID,
+		QueryType: EventStreamQuery,
+		Handler: func(ctx context.Context, results []any, guild *storage.GuildConfig, state *storage.GuildQueryState) error {
+			return nil
+		},
+		Enabled: true,
+	})
+	manager := NewQueryProcessorManager(registry, store, nil, nil, logger)
+	if _, monitored := manager.IndexerStatus(testGuildID); monitored {
+		t.Error("IndexerStatus() for an unknown guild reported a query loop")
+	}
+
+	if err := manager.AddGuild(testGuildID); err != nil {
+		t.Fatalf("AddGuild() failed: %v", err)
+	}
+	processor, _ := manager.GetProcessor(testGuildID)
+	client := &mockEventQueryClient{height: 10}
+	processor.queryExecutor = NewQueryExecutor(client, logger)
+	processor.ctx = context.Background()
+
+	if status, _ := manager.IndexerStatus(testGuildID); status.Queried {
+		t.Errorf("IndexerStatus() before any query = %+v, want not queried", status)
+	}
+
+	processor.processQueries()
+	status, _ := manager.IndexerStatus(testGuildID)
+	if !status.Connected || status.LastProcessedBlock != 10 || status.LastSuccess.IsZero() || status.LastError != "" {
+		t.Errorf("IndexerStatus() after a successful query = %+v, want connected at block 10", status)
+	}
+	lastSuccess := status.LastSuccess
+
+	// The indexer dropping keeps the last good position and success time
+	client.height = 20
+	client.err = errors.New("indexer unreachable")
+	processor.processQueries()
+	status, _ = manager.IndexerStatus(testGuildID)
+	if status.Connected || !strings.Contains(status.LastError, "indexer unreachable") {
+		t.Errorf("IndexerStatus() after a failed query = %+v, want disconnected with the error", status)
+	}
+	if status.LastProcessedBlock != 10 || !status.LastSuccess.Equal(lastSuccess) {
+		t.Errorf("IndexerStatus() after a failed query = %+v, want block 10 and the previous success", status)
+	}
+}
+
+func TestUserEventsHandler_StopsAtSavedPosition(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	queryDef, _ := CreateCoreQueryRegistry(logger, nil).GetQuery(UserEventsQueryID)
+	store := storage.NewMemoryConfigStore()
+	guild := storage.NewGuildConfig(testGuildID)
+	state := guild.EnsureQueryState(UserEventsQueryID, true)
+
+	var results []any
+	for block := int64(1); block <= 5; block++ {
+		results = append(results, graphql.Transaction{Hash: fmt.Sprintf("tx%d", block), BlockHeight: block, Index: 1})
+	}
+
+	// Shutdown is requested while the second transaction is being processed
+	ctx, cancel := context.WithCancel(t.Context())
+	saves := 0
+	save := func() error {
+		saves++
+		if saves == 2 {
+			cancel()
+		}
+		return store.Set(testGuildID, guild)
+	}
+	if err := queryDef.Handler(context.WithValue(ctx, saveCallbackKey, save), results, guild, state); err != nil {
+		t.Fatalf("handler error = %v, want a clean stop", err)
+	}
+
+	saved, err := store.Get(testGuildID)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	savedState, _ := saved.GetQueryState(UserEventsQueryID)
+	if block, index := savedState.GetProcessingPosition(); block != 2 || index != 1 {
+		t.Errorf("saved position = (%d, %d), want (2, 1) after the last processed transaction", block, index)
+	}
+	if block, index := state.GetProcessingPosition(); block != 2 || index != 1 {
+		t.Errorf("position = (%d, %d), want it to match the saved position (2, 1)", block, index)
+	}
+
+	// The next run resumes after the saved position
+	save = func() error { return store.Set(testGuildID, saved) }
+	if err := queryDef.Handler(context.WithValue(t.Context(), saveCallbackKey, save), results, saved, savedState); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if block, index := savedState.GetProcessingPosition(); block != 5 || index != 1 {
+		t.Errorf("position after resuming = (%d, %d), want (5, 1)", block, index)
+	}
+}
@@ -18,6 +18,26 @@ const (
 
 // CreateCoreQueryRegistry creates and registers all core queries
 func CreateCoreQueryRegistry(logger core.Logger, eventHandlers *EventHandlers) *QueryRegistry {
+	registry := CreateUserQueryRegistry(logger, eventHandlers)
+
+	// Register role events query (RoleLinked and RoleUnlinked in chronological order)
+	registry.RegisterQuery(&QueryDefinition{
+		QueryID:      RoleEventsQueryID,
+		Name:         "Role Events",
+		Description:  "Monitors blockchain for RoleLinked and RoleUnlinked events in chronological order",
+		QueryType:    EventStreamQuery,
+		GraphQLQuery: `query RoleEvents { getTransactions(where: { success: { eq: true } response: { events: { GnoEvent: { pkg_path: { eq: "gno.land/r/linker000/discord/role/v0" } } } } } order: { heightAndIndex: ASC }) { hash index block_height messages { value { ... on MsgCall { func } } } response { events { ... on GnoEvent { type pkg_path attrs { key value } } } } } }`,
+		Interval:     5 * time.Second,
+		Handler:      createRoleEventsHandler(logger, eventHandlers),
+		Enabled:      true,
+	})
+
+	return registry
+}
+
+// CreateUserQueryRegistry creates a registry with the user events query only, for platforms
+// that link users but not realm roles
+func CreateUserQueryRegistry(logger core.Logger, eventHandlers *EventHandlers) *QueryRegistry {
 	registry := NewQueryRegistry()
 
 	// Register user events query (UserLinked and UserUnlinked in chronological order)
@@ -32,35 +52,77 @@ func CreateCoreQueryRegistry(logger core.Logger, eventHandlers *EventHandlers) *
 		Enabled:      true,
 	})
 
-	// Register role events query (RoleLinked and RoleUnlinked in chronological order)
-	registry.RegisterQuery(&QueryDefinition{
-		QueryID:      RoleEventsQueryID,
-		Name:         "Role Events",
-		Description:  "Monitors blockchain for RoleLinked and RoleUnlinked events in chronological order",
-		QueryType:    EventStreamQuery,
-		GraphQLQuery: `query RoleEvents { getTransactions(where: { success: { eq: true } response: { events: { GnoEvent: { pkg_path: { eq: "gno.land/r/linker000/discord/role/v0" } } } } } order: { heightAndIndex: ASC }) { hash index block_height messages { value { ... on MsgCall { func } } } response { events { ... on GnoEvent { type pkg_path attrs { key value } } } } } }`,
-		Interval:     5 * time.Second,
-		Handler:      createRoleEventsHandler(logger, eventHandlers),
-		Enabled:      true,
-	})
-
 	return registry
 }
 
+// DefaultMaxBlocksPerExecution bounds the block range a single query execution requests from
+// the indexer. Larger backlogs are worked through over subsequent ticks.
+const DefaultMaxBlocksPerExecution = 10000
+
+// EventQueryClient is the subset of the GraphQL query client used to fetch events
+type EventQueryClient interface {
+	QueryLatestBlockHeight(ctx context.Context) (int64, error)
+	QueryUserEvents(ctx context.Context, afterBlockHeight int64, afterTxIndex int64, latestBlockHeight int64) ([]graphql.Transaction, error)
+	QueryRoleEvents(ctx context.Context, afterBlockHeight int64, afterTxIndex int64, latestBlockHeight int64) ([]graphql.Transaction, error)
+}
+
 // QueryExecutor handles the execution of queries
 type QueryExecutor struct {
-	queryClient *graphql.QueryClient
+	queryClient EventQueryClient
+	maxBlocks   int64
 	logger      core.Logger
 }
 
 // NewQueryExecutor creates a new query executor
-func NewQueryExecutor(queryClient *graphql.QueryClient, logger core.Logger) *QueryExecutor {
+func NewQueryExecutor(queryClient EventQueryClient, logger core.Logger) *QueryExecutor {
 	return &QueryExecutor{
 		queryClient: queryClient,
+		maxBlocks:   DefaultMaxBlocksPerExecution,
 		logger:      logger,
 	}
 }
 
+// SetMaxBlocksPerExecution overrides the per-execution block window (0 disables the cap)
+func (qe *QueryExecutor) SetMaxBlocksPerExecution(maxBlocks int64) {
+	qe.maxBlocks = maxBlocks
+}
+
+// queryUpperBound returns the exclusive upper block bound for a query starting after fromBlock:
+// the indexer height, or during backfills the end of a window of maxBlocks blocks, so a single
+// indexer request never fetches the whole backlog. The rest is fetched on the next tick.
+func (qe *QueryExecutor) queryUpperBound(queryType string, fromBlock int64, currentHeight int64) int64 {
+	if qe.maxBlocks <= 0 || currentHeight-fromBlock <= qe.maxBlocks {
+		return currentHeight
+	}
+
+	upperBound := fromBlock + qe.maxBlocks + 1
+	qe.logger.Info("Backlog exceeds the per-execution block window, deferring remainder to next run",
+		"query_type", queryType,
+		"from_block", fromBlock,
+		"to_block", upperBound,
+		"current_height", currentHeight,
+		"max_blocks", qe.maxBlocks)
+	return upperBound
+}
+
+// advanceEmptyWindow moves the processing position past a query window that held no transactions.
+// A window ending at the indexer height advances to it; a capped window only to its last block.
+func (qe *QueryExecutor) advanceEmptyWindow(queryState *storage.GuildQueryState, upperBound int64, currentHeight int64) {
+	target := currentHeight
+	if upperBound < currentHeight {
+		target = upperBound - 1
+	}
+	if target <= queryState.LastProcessedBlock {
+		return
+	}
+
+	qe.logger.Debug("No transactions found, advancing processing position",
+		"from", queryState.LastProcessedBlock,
+		"to", target)
+	queryState.LastProcessedBlock = target
+	queryState.LastProcessedTxIndex = 0
+}
+
 // ExecuteQuery executes a query and returns results
 func (qe *QueryExecutor) ExecuteQuery(ctx context.Context, queryDef *QueryDefinition, queryState *storage.GuildQueryState) ([]any, error) {
 	switch queryDef.QueryID {
@@ -105,23 +167,24 @@ func (qe *QueryExecutor) executeUserEventsQuery(ctx context.Context, queryState
 	// Get processing position
 	blockHeight, txIndex := queryState.GetProcessingPosition()
 
-	// Query from last processed position to current block
-	qe.logger.Debug("Querying user events", "from_block", blockHeight, "from_tx_index", txIndex, "to_block", currentHeight)
+	// Query from last processed position to current block, or to the end of the block window
+	upperBound := qe.queryUpperBound("user_events", blockHeight, currentHeight)
+	qe.logger.Debug("Querying user events", "from_block", blockHeight, "from_tx_index", txIndex, "to_block", upperBound)
 
 	// Log the query range that will be used
 	if txIndex > 0 {
 		qe.logger.Info("Executing UserEvents GraphQL query with block range",
 			"query_type", "user_events",
 			"block_range", fmt.Sprintf("(block > %d AND block < %d) OR (block = %d AND tx_index > %d)",
-				blockHeight, currentHeight, blockHeight, txIndex))
+				blockHeight, upperBound, blockHeight, txIndex))
 	} else {
 		qe.logger.Info("Executing UserEvents GraphQL query with block range",
 			"query_type", "user_events",
 			"block_range", fmt.Sprintf("(block > %d AND block < %d) OR (block = %d AND tx_index > 0)",
-				blockHeight, currentHeight, blockHeight))
+				blockHeight, upperBound, blockHeight))
 	}
 
-	transactions, err := qe.queryClient.QueryUserEvents(ctx, blockHeight, txIndex, currentHeight)
+	transactions, err := qe.queryClient.QueryUserEvents(ctx, blockHeight, txIndex, upperBound)
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +193,7 @@ func (qe *QueryExecutor) executeUserEventsQuery(ctx context.Context, queryState
 	qe.logger.Debug("Raw transactions from GraphQL",
 		"query_type", "user_events",
 		"total_transactions", len(transactions),
-		"block_range", fmt.Sprintf("%d-%d", blockHeight, currentHeight))
+		"block_range", fmt.Sprintf("%d-%d", blockHeight, upperBound))
 
 	for i, tx := range transactions {
 		qe.logger.Debug("Transaction found",
@@ -154,13 +217,9 @@ func (qe *QueryExecutor) executeUserEventsQuery(ctx context.Context, queryState
 		"filtered_valid", len(filteredTransactions),
 		"current_height", currentHeight)
 
-	// If no transactions found, advance to current height to keep queries efficient
-	if len(filteredTransactions) == 0 && currentHeight > queryState.LastProcessedBlock {
-		qe.logger.Debug("No transactions found, advancing to current height",
-			"from", queryState.LastProcessedBlock,
-			"to", currentHeight)
-		queryState.LastProcessedBlock = currentHeight
-		queryState.LastProcessedTxIndex = 0
+	// If no transactions found, advance past the window to keep queries efficient
+	if len(filteredTransactions) == 0 {
+		qe.advanceEmptyWindow(queryState, upperBound, currentHeight)
 	}
 
 	// Convert to []any
@@ -173,6 +232,35 @@ func (qe *QueryExecutor) executeUserEventsQuery(ctx context.Context, queryState
 	return results, nil
 }
 
+// retryOrDeadLetter records a failed attempt at handling the events of tx, which failed with err.
+//
+// While fewer than maxAttempts attempts were made it returns err, so the handler stops before tx
+// and the next run retries it from the same position. Once the attempts are used up it records tx
+// in the query state's dead letters under eventType and returns nil, so processing moves past it
+// instead of stalling the guild on one transaction.
+func retryOrDeadLetter(logger core.Logger, guildID string, state *storage.GuildQueryState, tx graphql.Transaction, eventType string, err error, maxAttempts int) error {
+	attempts := state.RecordTxFailure(tx.Hash)
+	if attempts < maxAttempts {
+		logger.Warn("Transaction will be retried",
+			"guild_id", guildID,
+			"tx_hash", tx.Hash,
+			"attempt", attempts,
+			"max_attempts", maxAttempts)
+		return err
+	}
+
+	logger.Error("Dead-lettering transaction after repeated failures",
+		"guild_id", guildID,
+		"tx_hash", tx.Hash,
+		"block_height", tx.BlockHeight,
+		"tx_index", tx.Index,
+		"event_type", eventType,
+		"attempts", attempts,
+		"error", err)
+	state.RecordDeadLetter(tx.Hash, tx.BlockHeight, tx.Index, eventType, attempts, err)
+	return nil
+}
+
 // createUserEventsHandler creates a handler for user events (UserLinked and UserUnlinked)
 func createUserEventsHandler(logger core.Logger, eventHandlers *EventHandlers) QueryHandler {
 	return func(ctx context.Context, results []any, guild *storage.GuildConfig, state *storage.GuildQueryState) error {
@@ -203,6 +291,10 @@ func createUserEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 				continue
 			}
 
+			// Tag every log line for this transaction so it can be traced through the handlers
+			correlationID := core.NewCorrelationID()
+			logger := logger.With(core.CorrelationIDKey, correlationID)
+
 			logger.Info("Processing user event transaction",
 				"guild_id", guild.GuildID,
 				"hash", tx.Hash,
@@ -210,6 +302,7 @@ func createUserEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 				"tx_index", tx.Index)
 
 			// Process transaction events
+		events:
 			for _, event := range tx.Response.Events {
 				switch event.Type {
 				case "UserLinked":
@@ -219,6 +312,7 @@ func createUserEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 						eventObj := Event{
 							Type:            UserLinkedEvent,
 							TransactionHash: tx.Hash,
+							CorrelationID:   correlationID,
 							BlockHeight:     tx.BlockHeight,
 							UserLinked:      userLinked,
 						}
@@ -228,13 +322,18 @@ func createUserEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 								"guild_id", guild.GuildID,
 								"tx_hash", tx.Hash,
 								"error", err)
-							return err
+							if err := retryOrDeadLetter(logger, guild.GuildID, state, tx, event.Type, err, eventHandlers.eventMaxAttempts()); err != nil {
+								return err
+							}
+							break events
 						}
 					} else {
-						logger.Error("Failed to parse UserLinked event",
+						// Never act on a malformed event; record it so it is not lost when the position advances
+						logger.Error("Skipping malformed UserLinked event",
 							"guild_id", guild.GuildID,
 							"tx_hash", tx.Hash,
 							"error", err)
+						state.RecordSkippedEvent(tx.Hash, tx.BlockHeight, tx.Index, event.Type, err)
 					}
 
 				case "UserUnlinked":
@@ -244,6 +343,7 @@ func createUserEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 						eventObj := Event{
 							Type:            UserUnlinkedEvent,
 							TransactionHash: tx.Hash,
+							CorrelationID:   correlationID,
 							BlockHeight:     tx.BlockHeight,
 							UserUnlinked:    userUnlinked,
 						}
@@ -253,18 +353,24 @@ func createUserEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 								"guild_id", guild.GuildID,
 								"tx_hash", tx.Hash,
 								"error", err)
-							return err
+							if err := retryOrDeadLetter(logger, guild.GuildID, state, tx, event.Type, err, eventHandlers.eventMaxAttempts()); err != nil {
+								return err
+							}
+							break events
 						}
 					} else {
-						logger.Error("Failed to parse UserUnlinked event",
+						// Never act on a malformed event; record it so it is not lost when the position advances
+						logger.Error("Skipping malformed UserUnlinked event",
 							"guild_id", guild.GuildID,
 							"tx_hash", tx.Hash,
 							"error", err)
+						state.RecordSkippedEvent(tx.Hash, tx.BlockHeight, tx.Index, event.Type, err)
 					}
 				}
 			}
 
-			// Update position after successfully processing the transaction
+			// Update position after processing or dead-lettering the transaction
+			state.ClearTxFailure(tx.Hash)
 			state.UpdateProcessingPosition(tx.BlockHeight, tx.Index)
 			logger.Debug("Updated processing position",
 				"guild_id", guild.GuildID,
@@ -285,6 +391,16 @@ func createUserEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 					"guild_id", guild.GuildID,
 					"tx_hash", tx.Hash)
 			}
+
+			// Stopping ends the batch here, where the position has just been saved; the next
+			// run resumes after this transaction
+			if ctx.Err() != nil {
+				logger.Info("Stopping event processing at the saved position",
+					"guild_id", guild.GuildID,
+					"block_height", tx.BlockHeight,
+					"tx_index", tx.Index)
+				return nil
+			}
 		}
 
 		return nil
@@ -323,23 +439,24 @@ func (qe *QueryExecutor) executeRoleEventsQuery(ctx context.Context, queryState
 	// Get processing position
 	blockHeight, txIndex := queryState.GetProcessingPosition()
 
-	// Query from last processed position to current block
-	qe.logger.Debug("Querying role events", "from_block", blockHeight, "from_tx_index", txIndex, "to_block", currentHeight)
+	// Query from last processed position to current block, or to the end of the block window
+	upperBound := qe.queryUpperBound("role_events", blockHeight, currentHeight)
+	qe.logger.Debug("Querying role events", "from_block", blockHeight, "from_tx_index", txIndex, "to_block", upperBound)
 
 	// Log the query range that will be used
 	if txIndex > 0 {
 		qe.logger.Info("Executing RoleEvents GraphQL query with block range",
 			"query_type", "role_events",
 			"block_range", fmt.Sprintf("(block > %d AND block < %d) OR (block = %d AND tx_index > %d)",
-				blockHeight, currentHeight, blockHeight, txIndex))
+				blockHeight, upperBound, blockHeight, txIndex))
 	} else {
 		qe.logger.Info("Executing RoleEvents GraphQL query with block range",
 			"query_type", "role_events",
 			"block_range", fmt.Sprintf("(block > %d AND block < %d) OR (block = %d AND tx_index > 0)",
-				blockHeight, currentHeight, blockHeight))
+				blockHeight, upperBound, blockHeight))
 	}
 
-	transactions, err := qe.queryClient.QueryRoleEvents(ctx, blockHeight, txIndex, currentHeight)
+	transactions, err := qe.queryClient.QueryRoleEvents(ctx, blockHeight, txIndex, upperBound)
 	if err != nil {
 		return nil, err
 	}
@@ -357,13 +474,9 @@ func (qe *QueryExecutor) executeRoleEventsQuery(ctx context.Context, queryState
 		"filtered_valid", len(filteredTransactions),
 		"current_height", currentHeight)
 
-	// If no transactions found, advance to current height to keep queries efficient
-	if len(filteredTransactions) == 0 && currentHeight > queryState.LastProcessedBlock {
-		qe.logger.Debug("No transactions found, advancing to current height",
-			"from", queryState.LastProcessedBlock,
-			"to", currentHeight)
-		queryState.LastProcessedBlock = currentHeight
-		queryState.LastProcessedTxIndex = 0
+	// If no transactions found, advance past the window to keep queries efficient
+	if len(filteredTransactions) == 0 {
+		qe.advanceEmptyWindow(queryState, upperBound, currentHeight)
 	}
 
 	// Convert to []any
@@ -406,6 +519,10 @@ func createRoleEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 				continue
 			}
 
+			// Tag every log line for this transaction so it can be traced through the handlers
+			correlationID := core.NewCorrelationID()
+			logger := logger.With(core.CorrelationIDKey, correlationID)
+
 			logger.Info("Processing role event transaction",
 				"guild_id", guild.GuildID,
 				"hash", tx.Hash,
@@ -413,6 +530,7 @@ func createRoleEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 				"tx_index", tx.Index)
 
 			// Process transaction events
+		events:
 			for _, event := range tx.Response.Events {
 				switch event.Type {
 				case "RoleLinked":
@@ -424,6 +542,7 @@ func createRoleEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 							eventObj := Event{
 								Type:            RoleLinkedEvent,
 								TransactionHash: tx.Hash,
+								CorrelationID:   correlationID,
 								BlockHeight:     tx.BlockHeight,
 								RoleLinked:      roleLinked,
 							}
@@ -433,7 +552,10 @@ func createRoleEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 									"guild_id", guild.GuildID,
 									"tx_hash", tx.Hash,
 									"error", err)
-								return err
+								if err := retryOrDeadLetter(logger, guild.GuildID, state, tx, event.Type, err, eventHandlers.eventMaxAttempts()); err != nil {
+									return err
+								}
+								break events
 							}
 						} else {
 							logger.Debug("RoleLinked event not for this guild, skipping",
@@ -441,10 +563,12 @@ func createRoleEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 								"event_guild_id", roleLinked.DiscordGuildID)
 						}
 					} else {
-						logger.Error("Failed to parse RoleLinked event",
+						// Never act on a malformed event; record it so it is not lost when the position advances
+						logger.Error("Skipping malformed RoleLinked event",
 							"guild_id", guild.GuildID,
 							"tx_hash", tx.Hash,
 							"error", err)
+						state.RecordSkippedEvent(tx.Hash, tx.BlockHeight, tx.Index, event.Type, err)
 					}
 
 				case "RoleUnlinked":
@@ -456,6 +580,7 @@ func createRoleEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 							eventObj := Event{
 								Type:            RoleUnlinkedEvent,
 								TransactionHash: tx.Hash,
+								CorrelationID:   correlationID,
 								BlockHeight:     tx.BlockHeight,
 								RoleUnlinked:    roleUnlinked,
 							}
@@ -465,7 +590,10 @@ func createRoleEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 									"guild_id", guild.GuildID,
 									"tx_hash", tx.Hash,
 									"error", err)
-								return err
+								if err := retryOrDeadLetter(logger, guild.GuildID, state, tx, event.Type, err, eventHandlers.eventMaxAttempts()); err != nil {
+									return err
+								}
+								break events
 							}
 						} else {
 							logger.Debug("RoleUnlinked event not for this guild, skipping",
@@ -473,15 +601,18 @@ func createRoleEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 								"event_guild_id", roleUnlinked.DiscordGuildID)
 						}
 					} else {
-						logger.Error("Failed to parse RoleUnlinked event",
+						// Never act on a malformed event; record it so it is not lost when the position advances
+						logger.Error("Skipping malformed RoleUnlinked event",
 							"guild_id", guild.GuildID,
 							"tx_hash", tx.Hash,
 							"error", err)
+						state.RecordSkippedEvent(tx.Hash, tx.BlockHeight, tx.Index, event.Type, err)
 					}
 				}
 			}
 
-			// Update position after successfully processing the transaction
+			// Update position after processing or dead-lettering the transaction
+			state.ClearTxFailure(tx.Hash)
 			state.UpdateProcessingPosition(tx.BlockHeight, tx.Index)
 			logger.Debug("Updated processing position",
 				"guild_id", guild.GuildID,
@@ -502,6 +633,16 @@ func createRoleEventsHandler(logger core.Logger, eventHandlers *EventHandlers) Q
 					"guild_id", guild.GuildID,
 					"tx_hash", tx.Hash)
 			}
+
+			// Stopping ends the batch here, where the position has just been saved; the next
+			// run resumes after this transaction
+			if ctx.Err() != nil {
+				logger.Info("Stopping event processing at the saved position",
+					"guild_id", guild.GuildID,
+					"block_height", tx.BlockHeight,
+					"tx_index", tx.Index)
+				return nil
+			}
 		}
 
 		return nil
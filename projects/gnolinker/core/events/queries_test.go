@@ -1,11 +1,14 @@
 package events
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
 )
 
 func TestCreateCoreQueryRegistry(t *testing.T) {
@@ -156,3 +159,113 @@ func TestQueryTypeConstants(t *testing.T) {
 		t.Errorf("Expected OnDemandQuery to be 'on_demand', got '%s'", OnDemandQuery)
 	}
 }
+
+// mockEventQueryClient serves transactions from an in-memory chain, honoring the position filter
+type mockEventQueryClient struct {
+	height int64
+	txs    []graphql.Transaction
+	err    error
+
+	// widestRange is the largest block range requested so far
+	widestRange int64
+}
+
+func (m *mockEventQueryClient) QueryLatestBlockHeight(ctx context.Context) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.height, nil
+}
+
+func (m *mockEventQueryClient) QueryUserEvents(ctx context.Context, afterBlockHeight int64, afterTxIndex int64, latestBlockHeight int64) ([]graphql.Transaction, error) {
+	m.widestRange = max(m.widestRange, latestBlockHeight-afterBlockHeight)
+
+	var result []graphql.Transaction
+	for _, tx := range m.txs {
+		if (tx.BlockHeight > afterBlockHeight && tx.BlockHeight < latestBlockHeight) ||
+			(tx.BlockHeight == afterBlockHeight && tx.Index > afterTxIndex) {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockEventQueryClient) QueryRoleEvents(ctx context.Context, afterBlockHeight int64, afterTxIndex int64, latestBlockHeight int64) ([]graphql.Transaction, error) {
+	return m.QueryUserEvents(ctx, afterBlockHeight, afterTxIndex, latestBlockHeight)
+}
+
+func TestQueryExecutor_BoundsBlockRangePerExecution(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+
+	client := &mockEventQueryClient{height: 100}
+	for block := int64(1); block <= 10; block++ {
+		client.txs = append(client.txs, graphql.Transaction{Hash: fmt.Sprintf("tx%d", block), BlockHeight: block, Index: 1})
+	}
+
+	executor := NewQueryExecutor(client, logger)
+	executor.SetMaxBlocksPerExecution(4)
+
+	registry := CreateCoreQueryRegistry(logger, nil)
+	queryDef, _ := registry.GetQuery(UserEventsQueryID)
+	guild := storage.NewGuildConfig("guild-1")
+	state := guild.EnsureQueryState(UserEventsQueryID, true)
+
+	var processed []string
+	for _, wantWindow := range [][]string{{"tx1", "tx2", "tx3", "tx4"}, {"tx5", "tx6", "tx7", "tx8"}, {"tx9", "tx10"}} {
+		results, err := executor.ExecuteQuery(t.Context(), queryDef, state)
+		if err != nil {
+			t.Fatalf("ExecuteQuery() error = %v", err)
+		}
+		if len(results) != len(wantWindow) {
+			t.Fatalf("ExecuteQuery() returned %d results, want %d", len(results), len(wantWindow))
+		}
+
+		if err := queryDef.Handler(t.Context(), results, guild, state); err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+		for i, result := range results {
+			tx := result.(graphql.Transaction)
+			if tx.Hash != wantWindow[i] {
+				t.Errorf("result %d = %s, want %s", i, tx.Hash, wantWindow[i])
+			}
+			processed = append(processed, tx.Hash)
+		}
+
+		// Position only advances to the end of the processed window
+		last := results[len(results)-1].(graphql.Transaction)
+		if block, index := state.GetProcessingPosition(); block != last.BlockHeight || index != last.Index {
+			t.Errorf("position = (%d, %d), want (%d, %d)", block, index, last.BlockHeight, last.Index)
+		}
+	}
+
+	if len(processed) != len(client.txs) {
+		t.Errorf("processed %d transactions, want %d", len(processed), len(client.txs))
+	}
+
+	// Empty windows advance the position one window at a time until it reaches the indexer height
+	for executions := 0; ; executions++ {
+		if executions > 100 {
+			t.Fatal("position never caught up to the indexer height")
+		}
+		before, _ := state.GetProcessingPosition()
+		results, err := executor.ExecuteQuery(t.Context(), queryDef, state)
+		if err != nil {
+			t.Fatalf("ExecuteQuery() error = %v", err)
+		}
+		if len(results) != 0 {
+			t.Fatalf("expected no results once the backlog is drained, got %d", len(results))
+		}
+		block, _ := state.GetProcessingPosition()
+		if block == client.height {
+			break
+		}
+		if block <= before || block-before > 4 {
+			t.Fatalf("position advanced from %d to %d, want at most one 4-block window", before, block)
+		}
+	}
+
+	// The indexer was never asked for more than the window
+	if client.widestRange > 5 {
+		t.Errorf("widest requested block range = %d, want at most the window of 4 blocks", client.widestRange)
+	}
+}
@@ -0,0 +1,89 @@
+package events
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
+)
+
+// realmRoleCache answers realm role membership for the duration of a verification pass, during
+// which a realm's role members are taken to be static. Each role's members are listed once when
+// the realm supports it; otherwise each address is queried once.
+type realmRoleCache struct {
+	roleLinkingFlow workflows.RoleLinkingWorkflow
+	logger          core.Logger
+
+	mu sync.Mutex
+	// members holds each listed role's member set by "realmPath:roleName", nil for roles whose
+	// realm cannot list them
+	members map[string]map[string]bool
+	// answers holds the per-address answers for roles whose realm cannot list them
+	answers map[string]bool
+}
+
+func newRealmRoleCache(roleLinkingFlow workflows.RoleLinkingWorkflow, logger core.Logger) *realmRoleCache {
+	return &realmRoleCache{
+		roleLinkingFlow: roleLinkingFlow,
+		logger:          logger,
+		members:         make(map[string]map[string]bool),
+		answers:         make(map[string]bool),
+	}
+}
+
+// has reports whether an address holds a role in the realm
+func (c *realmRoleCache) has(realmPath, roleName, address string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	role := realmPath + ":" + roleName
+	members, known := c.members[role]
+	if !known {
+		list, err := c.roleLinkingFlow.ListRealmRoleMembers(realmPath, roleName)
+		switch {
+		case err == nil:
+			members = make(map[string]bool, len(list))
+			for _, member := range list {
+				members[member] = true
+			}
+			c.members[role] = members
+		case errors.Is(err, workflows.ErrMembersNotEnumerable):
+			c.logger.Debug("Realm does not list role members, querying per address", "realm_path", realmPath, "role_name", roleName, "error", err)
+			c.members[role] = nil
+		default:
+			// Failing to reach the chain is not remembered; the per-address query reports it
+			c.logger.Warn("Failed to list realm role members", "realm_path", realmPath, "role_name", roleName, "error", err)
+		}
+	}
+	if members != nil {
+		return members[address], nil
+	}
+
+	key := role + ":" + address
+	if hasRole, ok := c.answers[key]; ok {
+		return hasRole, nil
+	}
+	hasRole, err := c.roleLinkingFlow.HasRealmRole(realmPath, roleName, address)
+	if err != nil {
+		return false, err
+	}
+	c.answers[key] = hasRole
+	return hasRole, nil
+}
+
+// withRealmRoleCache returns handlers answering realm role membership from a cache, for a single
+// verification pass
+func (eh *EventHandlers) withRealmRoleCache() *EventHandlers {
+	scoped := *eh
+	scoped.realmRoles = newRealmRoleCache(eh.roleLinkingFlow, eh.logger)
+	return &scoped
+}
+
+// hasRealmRole checks if an address has a role in the realm, through the pass's cache if any
+func (eh *EventHandlers) hasRealmRole(realmPath, roleName, address string) (bool, error) {
+	if eh.realmRoles != nil {
+		return eh.realmRoles.has(realmPath, roleName, address)
+	}
+	return eh.roleLinkingFlow.HasRealmRole(realmPath, roleName, address)
+}
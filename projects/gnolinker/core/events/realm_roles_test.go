@@ -0,0 +1,108 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// newVerificationGuild sets up a guild of linked members and realm role mappings, each role held
+// by every other member
+func newVerificationGuild(tb testing.TB, memberCount, mappingCount int, enumerable bool) (*EventHandlers, *mockRoleLinkingFlow, []*discordgo.Member) {
+	tb.Helper()
+	eh, _, _ := newTestEventHandlers(tb, storage.RoleSyncPolicyStrict)
+	userFlow := eh.userLinkingFlow.(*mockUserLinkingFlow)
+	roleFlow := eh.roleLinkingFlow.(*mockRoleLinkingFlow)
+	roleFlow.enumerable = enumerable
+	roleFlow.mappings = nil
+
+	var members []*discordgo.Member
+	for n := range memberCount {
+		userID := fmt.Sprintf("user-%d", n)
+		userFlow.addresses[userID] = fmt.Sprintf("g1member%d", n)
+		members = append(members, &discordgo.Member{User: &discordgo.User{ID: userID}})
+	}
+	for r := range mappingCount {
+		roleName := fmt.Sprintf("role%d", r)
+		roleFlow.mappings = append(roleFlow.mappings, &core.RoleMapping{
+			RealmPath:     testRealmPath,
+			RealmRoleName: roleName,
+			PlatformRole:  core.PlatformRole{ID: fmt.Sprintf("platform-role-%d", r)},
+		})
+		for n := 0; n < memberCount; n += 2 {
+			roleFlow.members[testRealmPath+":"+roleName] = append(roleFlow.members[testRealmPath+":"+roleName], fmt.Sprintf("g1member%d", n))
+		}
+	}
+	return eh, roleFlow, members
+}
+
+// verificationPass verifies every member, with a realm role cache unless uncached
+func verificationPass(tb testing.TB, eh *EventHandlers, members []*discordgo.Member, uncached bool) {
+	if !uncached {
+		eh = eh.withRealmRoleCache()
+	}
+	for _, member := range members {
+		if err := eh.processUserVerification(tb.Context(), testGuildID, member); err != nil {
+			tb.Fatalf("processUserVerification() error = %v", err)
+		}
+	}
+}
+
+func TestVerificationPass_CachesRealmRoleMembership(t *testing.T) {
+	tests := []struct {
+		name        string
+		uncached    bool
+		enumerable  bool
+		wantQueries int
+	}{
+		{name: "uncached queries every member and role", uncached: true, wantQueries: 20 * 3},
+		{name: "listable realm is listed once per role", enumerable: true, wantQueries: 3},
+		{name: "unlistable realm falls back to per-address queries", wantQueries: 3 + 20*3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eh, roleFlow, members := newVerificationGuild(t, 20, 3, tt.enumerable)
+			platform := eh.platform.(*mockPlatform)
+
+			verificationPass(t, eh, members, tt.uncached)
+
+			if roleFlow.queries != tt.wantQueries {
+				t.Errorf("made %d role membership queries, want %d", roleFlow.queries, tt.wantQueries)
+			}
+			// Every other member holds each role, whichever way membership was checked
+			for n, member := range members {
+				held := platform.roles[platform.key(testGuildID, member.User.ID)]
+				if got, want := len(held), 1+3*((n+1)%2); got != want {
+					t.Errorf("%s holds %v, want %d roles", member.User.ID, held, want)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkVerificationPass_RealmRoleQueries reports the realm role membership queries of a
+// verification pass over 500 linked members and 5 role mappings
+func BenchmarkVerificationPass_RealmRoleQueries(b *testing.B) {
+	for _, bc := range []struct {
+		name       string
+		uncached   bool
+		enumerable bool
+	}{
+		{name: "uncached", uncached: true},
+		{name: "per-address fallback"},
+		{name: "listed", enumerable: true},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			eh, roleFlow, members := newVerificationGuild(b, 500, 5, bc.enumerable)
+			passes := 0
+			for b.Loop() {
+				verificationPass(b, eh, members, bc.uncached)
+				passes++
+			}
+			b.ReportMetric(float64(roleFlow.queries)/float64(passes), "queries/pass")
+		})
+	}
+}
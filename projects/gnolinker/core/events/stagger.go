@@ -0,0 +1,50 @@
+package events
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// QueryLoopInterval is how often a guild's query loop checks its queries
+const QueryLoopInterval = 5 * time.Second
+
+// DefaultQueryJitter is how much each query loop tick is moved, at most, to spread indexer load
+const DefaultQueryJitter = time.Second
+
+// queryStagger spreads a guild's query loop ticks so the loops of many guilds do not hit the
+// indexer in synchronized bursts. The first tick is offset within an interval and later ticks are
+// moved by up to half the jitter either way. Both derive from the guild ID, so a guild keeps the
+// same schedule across restarts.
+type queryStagger struct {
+	interval time.Duration
+	jitter   time.Duration
+	seed     uint64
+	rng      *rand.Rand
+}
+
+func newQueryStagger(guildID string, interval, jitter time.Duration) *queryStagger {
+	hash := fnv.New64a()
+	hash.Write([]byte(guildID))
+	seed := hash.Sum64()
+
+	return &queryStagger{
+		interval: interval,
+		jitter:   min(max(jitter, 0), interval),
+		seed:     seed,
+		rng:      rand.New(rand.NewPCG(seed, 0)),
+	}
+}
+
+// offset returns the delay before the first tick
+func (s *queryStagger) offset() time.Duration {
+	return time.Duration(s.seed % uint64(s.interval))
+}
+
+// next returns the delay until the following tick
+func (s *queryStagger) next() time.Duration {
+	if s.jitter == 0 {
+		return s.interval
+	}
+	return s.interval - s.jitter/2 + time.Duration(s.rng.Int64N(int64(s.jitter)))
+}
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+// fireTimes returns when a processor's first ticks fire, relative to its start
+func fireTimes(processor *QueryProcessor, ticks int) []time.Duration {
+	at := processor.stagger.offset()
+	times := []time.Duration{at}
+	for len(times) < ticks {
+		at += processor.stagger.next()
+		times = append(times, at)
+	}
+	return times
+}
+
+func TestQueryStagger_SpreadsProcessorsStartedTogether(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	manager := NewQueryProcessorManager(NewQueryRegistry(), storage.NewMemoryConfigStore(), nil, nil, logger)
+	for _, guildID := range []string{testGuildID, "100000000000000002"} {
+		if err := manager.AddGuild(guildID); err != nil {
+			t.Fatalf("AddGuild(%s) failed: %v", guildID, err)
+		}
+	}
+	first, _ := manager.GetProcessor(testGuildID)
+	second, _ := manager.GetProcessor("100000000000000002")
+
+	firstTimes, secondTimes := fireTimes(first, 20), fireTimes(second, 20)
+	for _, a := range firstTimes {
+		for _, b := range secondTimes {
+			if (a - b).Abs() < 10*time.Millisecond {
+				t.Fatalf("processors both fire at %s", a.Round(time.Millisecond))
+			}
+		}
+	}
+	for n := 1; n < len(firstTimes); n++ {
+		gap := firstTimes[n] - firstTimes[n-1]
+		if gap < QueryLoopInterval-DefaultQueryJitter/2 || gap >= QueryLoopInterval+DefaultQueryJitter/2 {
+			t.Errorf("tick %d came %s after the previous one, want within %s of %s", n, gap, DefaultQueryJitter/2, QueryLoopInterval)
+		}
+	}
+
+	// A guild keeps its schedule across restarts
+	restarted := NewQueryProcessorManager(NewQueryRegistry(), storage.NewMemoryConfigStore(), nil, nil, logger)
+	if err := restarted.AddGuild(testGuildID); err != nil {
+		t.Fatalf("AddGuild(%s) failed: %v", testGuildID, err)
+	}
+	again, _ := restarted.GetProcessor(testGuildID)
+	for n, at := range fireTimes(again, 20) {
+		if at != firstTimes[n] {
+			t.Fatalf("tick %d fires at %s after a restart, want %s", n, at, firstTimes[n])
+		}
+	}
+}
+
+func TestQueryStagger_WithoutJitter(t *testing.T) {
+	stagger := newQueryStagger(testGuildID, QueryLoopInterval, 0)
+	if offset := stagger.offset(); offset < 0 || offset >= QueryLoopInterval {
+		t.Errorf("offset = %s, want within the interval", offset)
+	}
+	for range 5 {
+		if next := stagger.next(); next != QueryLoopInterval {
+			t.Fatalf("next() = %s without jitter, want %s", next, QueryLoopInterval)
+		}
+	}
+}
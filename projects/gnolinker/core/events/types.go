@@ -16,6 +16,7 @@ const (
 type Event struct {
 	Type            EventType
 	TransactionHash string
+	CorrelationID   string // ties together log lines produced while handling this event
 	BlockHeight     int64
 	UserLinked      *graphql.UserLinkedEvent
 	UserUnlinked    *graphql.UserUnlinkedEvent
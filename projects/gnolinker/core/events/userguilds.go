@@ -0,0 +1,106 @@
+package events
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// userGuildsCacheTTL is how long the guilds a user was found in are reused across events
+const userGuildsCacheTTL = time.Minute
+
+// userGuildsCache remembers which managed guilds a user is in, so bursts of events for the same
+// user don't look them up in every guild again. Entries are dropped when the user joins or
+// leaves a guild. A nil cache caches nothing.
+type userGuildsCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]userGuildsEntry
+}
+
+type userGuildsEntry struct {
+	guildIDs  []string
+	expiresAt time.Time
+}
+
+func newUserGuildsCache(ttl time.Duration) *userGuildsCache {
+	return &userGuildsCache{ttl: ttl, entries: make(map[string]userGuildsEntry)}
+}
+
+func (c *userGuildsCache) get(userID string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.guildIDs, true
+}
+
+func (c *userGuildsCache) set(userID string, guildIDs []string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[userID] = userGuildsEntry{guildIDs: guildIDs, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *userGuildsCache) invalidate(userID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
+
+// getUserGuilds returns the managed guilds a user is a member of
+func (eh *EventHandlers) getUserGuilds(userID string) ([]*discordgo.Guild, error) {
+	if guildIDs, ok := eh.userGuilds.get(userID); ok {
+		var userGuilds []*discordgo.Guild
+		for _, guild := range eh.directory().Guilds() {
+			if slices.Contains(guildIDs, guild.ID) {
+				userGuilds = append(userGuilds, guild)
+			}
+		}
+		return userGuilds, nil
+	}
+
+	var userGuilds []*discordgo.Guild
+	var guildIDs []string
+	for _, guild := range eh.directory().Guilds() {
+		// Departed members are no longer in the guild, so don't ask Discord for them
+		if config, err := eh.configManager.GetGuildConfig(guild.ID); err == nil && config.IsMemberDeparted(userID) {
+			continue
+		}
+
+		if eh.isGuildMember(guild.ID, userID) {
+			userGuilds = append(userGuilds, guild)
+			guildIDs = append(guildIDs, guild.ID)
+		}
+	}
+
+	eh.userGuilds.set(userID, guildIDs)
+	return userGuilds, nil
+}
+
+// isGuildMember reports whether a user is a member of a guild
+func (eh *EventHandlers) isGuildMember(guildID, userID string) bool {
+	member, err := eh.directory().Member(guildID, userID)
+	return err == nil && member != nil
+}
@@ -0,0 +1,142 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// memberTransport answers Discord's get guild member endpoint, counting the calls: members of
+// the listed guilds are found, everyone else is unknown
+type memberTransport struct {
+	calls   atomic.Int64
+	members map[string]bool // guild ID -> whether the user is a member
+}
+
+func (tr *memberTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tr.calls.Add(1)
+	// Path: /api/v9/guilds/{guild.id}/members/{user.id}
+	parts := strings.Split(req.URL.Path, "/")
+	guildID := parts[len(parts)-3]
+
+	status, body := http.StatusNotFound, `{"message": "Unknown Member", "code": 10007}`
+	if tr.members[guildID] {
+		status, body = http.StatusOK, fmt.Sprintf(`{"user": {"id": %q}}`, parts[len(parts)-1])
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// newMembershipSession creates a session with the given guilds; the user is in the state's
+// member cache for stateGuilds and only known to the REST API for restGuilds
+func newMembershipSession(t testing.TB, guildIDs, stateGuilds, restGuilds []string) (*discordgo.Session, *memberTransport) {
+	t.Helper()
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	transport := &memberTransport{members: make(map[string]bool)}
+	session.Client = &http.Client{Transport: transport}
+
+	for _, guildID := range guildIDs {
+		if err := session.State.GuildAdd(&discordgo.Guild{ID: guildID}); err != nil {
+			t.Fatalf("GuildAdd() error = %v", err)
+		}
+	}
+	for _, guildID := range stateGuilds {
+		if err := session.State.MemberAdd(&discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: testUserID}}); err != nil {
+			t.Fatalf("MemberAdd() error = %v", err)
+		}
+	}
+	for _, guildID := range restGuilds {
+		transport.members[guildID] = true
+	}
+	return session, transport
+}
+
+func guildIDs(guilds []*discordgo.Guild) []string {
+	ids := make([]string, len(guilds))
+	for i, guild := range guilds {
+		ids[i] = guild.ID
+	}
+	return ids
+}
+
+func TestGetUserGuilds_StateCacheAndTTL(t *testing.T) {
+	const restGuild, otherGuild = "100000000000000002", "100000000000000003"
+	eh, _, _ := newTestEventHandlers(t, "")
+	session, transport := newMembershipSession(t, []string{testGuildID, restGuild, otherGuild}, []string{testGuildID}, []string{restGuild})
+	eh.session = session
+
+	guilds, _ := eh.getUserGuilds(testUserID)
+	if got := guildIDs(guilds); !slices.Equal(got, []string{testGuildID, restGuild}) {
+		t.Errorf("getUserGuilds() = %v, want the state and REST guilds", got)
+	}
+	// The state cache answers for its guild; the others need a REST call
+	if calls := transport.calls.Load(); calls != 2 {
+		t.Errorf("REST calls = %d, want 2", calls)
+	}
+
+	// A second event for the same user is answered from the cache
+	guilds, _ = eh.getUserGuilds(testUserID)
+	if calls := transport.calls.Load(); calls != 2 || len(guilds) != 2 {
+		t.Errorf("cached lookup made %d REST calls in total and found %d guilds, want 2 and 2", calls, len(guilds))
+	}
+
+	// Leaving a guild invalidates the cached guilds
+	if err := eh.HandleMemberLeft(testGuildID, testUserID); err != nil {
+		t.Fatalf("HandleMemberLeft() error = %v", err)
+	}
+	guilds, _ = eh.getUserGuilds(testUserID)
+	if got := guildIDs(guilds); !slices.Equal(got, []string{restGuild}) {
+		t.Errorf("getUserGuilds() after leaving = %v, want only %s", got, restGuild)
+	}
+}
+
+func BenchmarkGetUserGuilds(b *testing.B) {
+	// A bot in 50 guilds; the member cache knows the user in 40 of them
+	var all, inState, inREST []string
+	for i := range 50 {
+		guildID := fmt.Sprintf("1%017d", i)
+		all = append(all, guildID)
+		switch {
+		case i < 40:
+			inState = append(inState, guildID)
+		case i < 45:
+			inREST = append(inREST, guildID)
+		}
+	}
+
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cached=%v", cached), func(b *testing.B) {
+			logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+			configManager := config.NewConfigManager(storage.NewMemoryConfigStore(), &config.StorageConfig{}, nil, logger)
+			session, transport := newMembershipSession(b, all, inState, inREST)
+			eh := NewEventHandlers(nil, configManager, session, logger, nil, nil)
+
+			for b.Loop() {
+				if !cached {
+					eh.userGuilds.invalidate(testUserID)
+				}
+				if _, err := eh.getUserGuilds(testUserID); err != nil {
+					b.Fatal(err)
+				}
+			}
+			// Without the state and TTL caches every event made one REST call per guild (50/op)
+			b.ReportMetric(float64(transport.calls.Load())/float64(b.N), "rest_calls/op")
+		})
+	}
+}
@@ -14,9 +14,8 @@ type VerificationTask struct {
 	ID          string
 	Name        string
 	Description string
-	Priority    string // "high", "medium", or "low"
-	Interval    time.Duration
-	Handler     func(ctx context.Context, guildID string, state *storage.GuildQueryState) error
+	Priority    string // "high", "medium", or "low", the storage.VerificationTier* names
+	Handler     func(ctx context.Context, guildID string, state *storage.GuildQueryState, batchSize int) error
 	Enabled     bool
 }
 
@@ -25,6 +24,7 @@ type VerificationScheduler struct {
 	guildID       string
 	store         storage.ConfigStore
 	eventHandlers *EventHandlers
+	lease         *queryLease
 	logger        core.Logger
 
 	tasks   map[string]*VerificationTask
@@ -53,16 +53,16 @@ func NewVerificationScheduler(guildID string, store storage.ConfigStore, eventHa
 	return scheduler
 }
 
-// initializeTasks sets up the verification tasks
+// initializeTasks sets up the verification tasks. Their interval and batch size are the guild's
+// verification tier settings, read on every run so changes apply without a restart.
 func (vs *VerificationScheduler) initializeTasks() {
 	// High priority - online/active users
 	vs.tasks["verify_high_priority"] = &VerificationTask{
 		ID:          "verify_high_priority",
 		Name:        "Verify High Priority Members",
 		Description: "Verifies online/active guild members against Gno realm state",
-		Priority:    "high",
-		Interval:    1 * time.Minute,
-		Handler:     vs.createVerificationHandler("high", 0),
+		Priority:    storage.VerificationTierHigh,
+		Handler:     vs.createVerificationHandler(storage.VerificationTierHigh),
 		Enabled:     true,
 	}
 
@@ -71,9 +71,8 @@ func (vs *VerificationScheduler) initializeTasks() {
 		ID:          "verify_medium_priority",
 		Name:        "Verify Medium Priority Members",
 		Description: "Verifies recently active guild members against Gno realm state",
-		Priority:    "medium",
-		Interval:    5 * time.Minute,
-		Handler:     vs.createVerificationHandler("medium", 0),
+		Priority:    storage.VerificationTierMedium,
+		Handler:     vs.createVerificationHandler(storage.VerificationTierMedium),
 		Enabled:     true,
 	}
 
@@ -82,16 +81,15 @@ func (vs *VerificationScheduler) initializeTasks() {
 		ID:          "verify_low_priority",
 		Name:        "Verify Low Priority Members",
 		Description: "Verifies inactive/offline guild members against Gno realm state incrementally",
-		Priority:    "low",
-		Interval:    30 * time.Minute,
-		Handler:     vs.createVerificationHandler("low", 10), // Process 10 users at a time
+		Priority:    storage.VerificationTierLow,
+		Handler:     vs.createVerificationHandler(storage.VerificationTierLow),
 		Enabled:     true,
 	}
 }
 
 // createVerificationHandler creates a handler for a specific priority tier
-func (vs *VerificationScheduler) createVerificationHandler(priority string, maxUsers int) func(context.Context, string, *storage.GuildQueryState) error {
-	return func(ctx context.Context, guildID string, state *storage.GuildQueryState) error {
+func (vs *VerificationScheduler) createVerificationHandler(priority string) func(context.Context, string, *storage.GuildQueryState, int) error {
+	return func(ctx context.Context, guildID string, state *storage.GuildQueryState, maxUsers int) error {
 		vs.logger.Info("Processing verification task",
 			"guild_id", guildID,
 			"priority", priority,
@@ -120,6 +118,15 @@ func (vs *VerificationScheduler) createVerificationHandler(priority string, maxU
 	}
 }
 
+// tierSettings returns the guild's current settings for a task's verification tier
+func (vs *VerificationScheduler) tierSettings(task *VerificationTask) storage.VerificationTierSettings {
+	config, err := vs.store.Get(vs.guildID)
+	if err != nil {
+		return storage.DefaultVerificationTiers[task.Priority]
+	}
+	return config.GetVerificationTier(task.Priority)
+}
+
 // Start begins the verification scheduler
 func (vs *VerificationScheduler) Start(ctx context.Context) error {
 	vs.mutex.Lock()
@@ -141,7 +148,7 @@ func (vs *VerificationScheduler) Start(ctx context.Context) error {
 				"guild_id", vs.guildID,
 				"task_id", task.ID,
 				"priority", task.Priority,
-				"interval", task.Interval)
+				"interval", vs.tierSettings(task).Interval)
 			vs.startTaskTimer(task)
 		}
 	}
@@ -171,7 +178,11 @@ func (vs *VerificationScheduler) Stop() error {
 	vs.timers = make(map[string]*time.Timer)
 
 	vs.running = false
+
+	// A running pass stops at its next saved position and then reschedules, which takes the lock
+	vs.mutex.Unlock()
 	vs.wg.Wait()
+	vs.mutex.Lock()
 
 	vs.logger.Info("Verification scheduler stopped", "guild_id", vs.guildID)
 	return nil
@@ -187,7 +198,7 @@ func (vs *VerificationScheduler) startTaskTimer(task *VerificationTask) {
 	if shouldRunNow {
 		initialDelay = 0
 	} else {
-		initialDelay = task.Interval
+		initialDelay = vs.tierSettings(task).Interval
 	}
 
 	// Create timer
@@ -232,6 +243,15 @@ func (vs *VerificationScheduler) runTask(task *VerificationTask) {
 		"task_id", task.ID,
 		"priority", task.Priority)
 
+	// Only the instance holding the guild's query lease verifies its members
+	if !vs.lease.Hold(vs.ctx) {
+		vs.logger.Debug("Query lease held by another instance, skipping verification task",
+			"guild_id", vs.guildID,
+			"task_id", task.ID)
+		vs.rescheduleTask(task)
+		return
+	}
+
 	// Get or create task state
 	config, err := vs.store.Get(vs.guildID)
 	if err != nil {
@@ -240,6 +260,14 @@ func (vs *VerificationScheduler) runTask(task *VerificationTask) {
 		return
 	}
 
+	if config.Paused {
+		vs.logger.Debug("Guild processing paused, skipping verification task",
+			"guild_id", vs.guildID,
+			"task_id", task.ID)
+		vs.rescheduleTask(task)
+		return
+	}
+
 	// Ensure query state exists for the task
 	queryState := config.EnsureQueryState(task.ID, true)
 
@@ -263,8 +291,12 @@ func (vs *VerificationScheduler) runTask(task *VerificationTask) {
 		return
 	}
 
-	// Run the task handler
-	err = task.Handler(vs.ctx, vs.guildID, queryState)
+	// Run the task handler, renewing the lease meanwhile as a pass can outlast it. The pass stops
+	// if renewal fails, as another instance may then be verifying the guild.
+	settings := config.GetVerificationTier(task.Priority)
+	taskCtx, stopRenewal := vs.lease.KeepAlive(vs.ctx)
+	err = task.Handler(taskCtx, vs.guildID, queryState, settings.BatchSize)
+	stopRenewal()
 
 	// Update state based on result
 	if err != nil {
@@ -282,9 +314,14 @@ func (vs *VerificationScheduler) runTask(task *VerificationTask) {
 
 	// Clear executing state and update next run time
 	queryState.SetExecuting(false)
-	queryState.UpdateRunTimestamp(task.Interval)
+	queryState.UpdateRunTimestamp(settings.Interval)
 
-	// Save final state
+	// Save final state into the latest config, keeping changes made while the task ran
+	// (role records, tier settings) instead of overwriting them with the copy read before it
+	if latest, err := vs.store.Get(vs.guildID); err == nil {
+		latest.SetQueryState(task.ID, queryState)
+		config = latest
+	}
 	if err := vs.store.Set(vs.guildID, config); err != nil {
 		vs.logger.Error("Failed to save final state", "guild_id", vs.guildID, "error", err)
 	}
@@ -307,8 +344,8 @@ func (vs *VerificationScheduler) rescheduleTask(task *VerificationTask) {
 		timer.Stop()
 	}
 
-	// Create new timer
-	timer := time.AfterFunc(task.Interval, func() {
+	// Create new timer, picking up changes to the tier's interval
+	timer := time.AfterFunc(vs.tierSettings(task).Interval, func() {
 		vs.wg.Add(1)
 		go vs.runTask(task)
 	})
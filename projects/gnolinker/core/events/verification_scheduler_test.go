@@ -0,0 +1,130 @@
+package events
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+func TestVerificationScheduler_HonorsTierSettingChanges(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	store := storage.NewMemoryConfigStore()
+	configManager := config.NewConfigManager(store, &config.StorageConfig{}, nil, logger)
+
+	// Intervals this short are below the admin command's bounds, so they are stored directly
+	guildConfig := storage.NewGuildConfig(testGuildID)
+	guildConfig.SetVerificationTier(storage.VerificationTierHigh, storage.VerificationTierSettings{Interval: 10 * time.Millisecond, BatchSize: 7})
+	if err := store.Set(testGuildID, guildConfig); err != nil {
+		t.Fatalf("failed to store guild config: %v", err)
+	}
+
+	scheduler := NewVerificationScheduler(testGuildID, store, nil, logger)
+	var runs, batchSize atomic.Int64
+	for id, task := range scheduler.tasks {
+		task.Enabled = id == "verify_high_priority"
+	}
+	scheduler.tasks["verify_high_priority"].Handler = func(ctx context.Context, guildID string, state *storage.GuildQueryState, maxUsers int) error {
+		batchSize.Store(int64(maxUsers))
+		runs.Add(1)
+		return nil
+	}
+
+	if err := scheduler.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = scheduler.Stop() })
+
+	waitFor(t, func() bool { return runs.Load() >= 3 })
+	if got := batchSize.Load(); got != 7 {
+		t.Errorf("handler got batch size %d, want the guild's 7", got)
+	}
+
+	// Slow the tier down; runs can race with the update, which is then retried
+	slow := storage.VerificationTierSettings{Interval: time.Hour, BatchSize: 7}
+	waitFor(t, func() bool {
+		return configManager.SetVerificationTier(testGuildID, storage.VerificationTierHigh, slow) == nil
+	})
+
+	// A run in flight and the one already scheduled may still happen; after that, the next
+	// tick is an hour away
+	before := runs.Load()
+	time.Sleep(200 * time.Millisecond)
+	if extra := runs.Load() - before; extra > 2 {
+		t.Errorf("tier ran %d more times after its interval was raised to an hour", extra)
+	}
+}
+
+func TestVerificationScheduler_KeepsLeaseDuringPass(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	store := storage.NewMemoryConfigStore()
+	if err := store.Set(testGuildID, storage.NewGuildConfig(testGuildID)); err != nil {
+		t.Fatalf("failed to store guild config: %v", err)
+	}
+	lockManager := lock.NewMemoryLockManager(lock.LockConfig{})
+	ttl := 60 * time.Millisecond
+
+	scheduler := NewVerificationScheduler(testGuildID, store, nil, logger)
+	scheduler.lease = newQueryLease(lockManager, testGuildID, logger)
+	scheduler.lease.ttl = ttl
+	standby := newQueryLease(lockManager, testGuildID, logger)
+	standby.ttl = ttl
+
+	var keptLease, stoppedOnLoss bool
+	task := scheduler.tasks["verify_high_priority"]
+	task.Handler = func(ctx context.Context, guildID string, state *storage.GuildQueryState, maxUsers int) error {
+		// A pass outlasting the lease keeps it
+		time.Sleep(3 * ttl)
+		keptLease = ctx.Err() == nil && !standby.Hold(context.Background())
+
+		// Once the lease is taken over, the pass is told to stop
+		scheduler.lease.mu.Lock()
+		held := scheduler.lease.held
+		scheduler.lease.mu.Unlock()
+		if err := lockManager.ReleaseLock(context.Background(), held); err != nil {
+			t.Errorf("ReleaseLock() error = %v", err)
+		}
+		if !standby.Hold(context.Background()) {
+			t.Error("standby instance should take over the released lease")
+		}
+		select {
+		case <-ctx.Done():
+			stoppedOnLoss = true
+		case <-time.After(time.Second):
+		}
+		return nil
+	}
+
+	scheduler.ctx, scheduler.cancel = context.WithCancel(t.Context())
+	scheduler.running = true
+	scheduler.wg.Add(1)
+	scheduler.runTask(task)
+	scheduler.running = false
+
+	if !keptLease {
+		t.Error("lease should be renewed while the verification pass runs")
+	}
+	if !stoppedOnLoss {
+		t.Error("verification pass should be cancelled once the lease cannot be renewed")
+	}
+	if scheduler.lease.Hold(context.Background()) {
+		t.Error("instance should stand by once its lease was taken over")
+	}
+}
+
+// waitFor polls cond until it holds, failing the test after a second
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within a second")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
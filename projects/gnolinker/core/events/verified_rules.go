@@ -0,0 +1,65 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+// applyVerifiedRoleRules syncs a user's verified-role rules after a link change, logging failures
+func (eh *EventHandlers) applyVerifiedRoleRules(guildID, userID, address string) {
+	config, err := eh.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		eh.logger.Error("Failed to get guild config", "guild_id", guildID, "error", err)
+		return
+	}
+	if err := eh.syncVerifiedRoleRules(guildID, userID, address, config); err != nil {
+		eh.logger.Error("Failed to apply verified-role rules", "guild_id", guildID, "user_id", userID, "error", err)
+	}
+}
+
+// syncVerifiedRoleRules applies the guild's verified-role rules besides the verified role, each
+// independently: a rule's role is held while the user is linked and the rule's predicate returns
+// true, and removed otherwise. An empty address means the user is not linked. A rule whose
+// predicate cannot be evaluated leaves its role unchanged.
+func (eh *EventHandlers) syncVerifiedRoleRules(guildID, userID, address string, config *storage.GuildConfig) error {
+	var errs []error
+	for _, rule := range config.VerifiedRoleRules {
+		want := address != ""
+		if want && rule.IsConditional() {
+			satisfied, err := eh.roleLinkingFlow.EvalRealmPredicate(rule.RealmPath, rule.Function, address)
+			if err != nil {
+				eh.logger.Error("Failed to evaluate verified-role rule",
+					"guild_id", guildID,
+					"user_id", userID,
+					"role_id", rule.RoleID,
+					"predicate", rule.Predicate(),
+					"error", err)
+				errs = append(errs, fmt.Errorf("failed to evaluate rule for role %s: %w", rule.RoleID, err))
+				continue
+			}
+			want = satisfied
+		}
+
+		hasRole, err := eh.platform.HasRole(guildID, userID, rule.RoleID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to check role %s: %w", rule.RoleID, err))
+			continue
+		}
+
+		switch {
+		case want && !hasRole:
+			if err := eh.addManagedRole(guildID, userID, rule.RoleID, "", ""); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			eh.logger.Info("Granted verified-role rule role", "guild_id", guildID, "user_id", userID, "role_id", rule.RoleID)
+		case !want && hasRole:
+			if _, err := eh.removeManagedRole(guildID, userID, rule.RoleID, "", ""); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
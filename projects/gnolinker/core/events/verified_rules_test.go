@@ -0,0 +1,91 @@
+package events
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+func TestProcessUserVerification_VerifiedRoleRules(t *testing.T) {
+	const (
+		holderRole = "holder-role"
+		linkedRole = "linked-role"
+		predicate  = testRealmPath + ".IsHolder"
+	)
+
+	tests := []struct {
+		name      string
+		linked    bool
+		holder    bool     // whether the predicate returns true for the linked address
+		roles     []string // roles the user holds beforehand
+		wantRoles []string
+	}{
+		{
+			name:      "predicate true grants both roles",
+			linked:    true,
+			holder:    true,
+			wantRoles: []string{testVerifiedID, linkedRole, holderRole},
+		},
+		{
+			name:      "predicate false leaves the role unassigned",
+			linked:    true,
+			wantRoles: []string{testVerifiedID, linkedRole},
+		},
+		{
+			name:      "predicate turning false removes the role",
+			linked:    true,
+			roles:     []string{testVerifiedID, linkedRole, holderRole},
+			wantRoles: []string{testVerifiedID, linkedRole},
+		},
+		{
+			name:  "unlinked member loses every rule role",
+			roles: []string{testVerifiedID, linkedRole, holderRole},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eh, platform, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+			for _, rule := range []storage.VerifiedRoleRule{
+				{RoleID: linkedRole},
+				{RoleID: holderRole, RealmPath: testRealmPath, Function: "IsHolder"},
+			} {
+				if err := configManager.SetVerifiedRoleRule(testGuildID, rule); err != nil {
+					t.Fatalf("SetVerifiedRoleRule() error = %v", err)
+				}
+			}
+			for _, roleID := range tt.roles {
+				_ = platform.AddRole(testGuildID, testUserID, roleID)
+			}
+			if !tt.linked {
+				delete(eh.userLinkingFlow.(*mockUserLinkingFlow).addresses, testUserID)
+			}
+			if tt.holder {
+				eh.roleLinkingFlow.(*mockRoleLinkingFlow).members[predicate] = []string{testAddress}
+			}
+
+			if err := eh.processUserVerification(t.Context(), testGuildID, testMember()); err != nil {
+				t.Fatalf("processUserVerification() error = %v", err)
+			}
+
+			got := slices.Sorted(slices.Values(platform.roles[platform.key(testGuildID, testUserID)]))
+			if !slices.Equal(got, slices.Sorted(slices.Values(tt.wantRoles))) {
+				t.Errorf("user holds %v, want %v", got, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestSetVerifiedRoleRule_RejectsVerifiedRole(t *testing.T) {
+	_, _, configManager := newTestEventHandlers(t, storage.RoleSyncPolicyStrict)
+	if err := configManager.SetVerifiedRoleRule(testGuildID, storage.VerifiedRoleRule{RoleID: testVerifiedID}); !errors.Is(err, storage.ErrVerifiedRoleRule) {
+		t.Errorf("SetVerifiedRoleRule() error = %v, want %v", err, storage.ErrVerifiedRoleRule)
+	}
+
+	guildConfig, _ := configManager.GetGuildConfig(testGuildID)
+	if rules := guildConfig.GetVerifiedRoleRules(); len(rules) != 1 || rules[0].IsConditional() {
+		t.Errorf("GetVerifiedRoleRules() = %+v, want only the unconditional verified role", rules)
+	}
+}
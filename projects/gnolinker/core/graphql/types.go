@@ -1,6 +1,10 @@
 package graphql
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 type EventAttribute struct {
 	Key   string `json:"key"`
@@ -81,19 +85,114 @@ type RoleUnlinkedEvent struct {
 	DiscordRoleID  string
 }
 
+// ErrInvalidEvent is matched by every EventParseError
+var ErrInvalidEvent = errors.New("invalid event")
+
+// EventParseError reports an event whose attributes do not match the schema the realm emits,
+// e.g. after an indexer schema change or a partial response
+type EventParseError struct {
+	EventType string
+	Attribute string
+	Reason    string
+}
+
+func (e *EventParseError) Error() string {
+	return fmt.Sprintf("invalid %s event: attribute %q %s", e.EventType, e.Attribute, e.Reason)
+}
+
+func (e *EventParseError) Unwrap() error {
+	return ErrInvalidEvent
+}
+
+// eventAttributes indexes an event's attributes by key, rejecting conflicting duplicates
+func eventAttributes(event GnoEvent) (map[string]string, error) {
+	attrs := make(map[string]string, len(event.Attrs))
+	for _, attr := range event.Attrs {
+		if existing, ok := attrs[attr.Key]; ok && existing != attr.Value {
+			return nil, &EventParseError{EventType: event.Type, Attribute: attr.Key, Reason: "has conflicting values"}
+		}
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs, nil
+}
+
+// requireAttribute returns a required attribute, checking it with valid when given
+func requireAttribute(event GnoEvent, attrs map[string]string, key string, valid func(string) bool) (string, error) {
+	value, ok := attrs[key]
+	if !ok {
+		return "", &EventParseError{EventType: event.Type, Attribute: key, Reason: "is missing"}
+	}
+	if strings.TrimSpace(value) == "" {
+		return "", &EventParseError{EventType: event.Type, Attribute: key, Reason: "is empty"}
+	}
+	if valid != nil && !valid(value) {
+		return "", &EventParseError{EventType: event.Type, Attribute: key, Reason: fmt.Sprintf("is malformed: %q", value)}
+	}
+	return value, nil
+}
+
+// isSnowflake reports whether value is a Discord ID
+func isSnowflake(value string) bool {
+	if len(value) > 20 {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isUserID reports whether value is a platform user ID: a Discord or Telegram ID, or a Slack
+// member ID such as U0123ABCD
+func isUserID(value string) bool {
+	if len(value) > 32 {
+		return false
+	}
+	for _, r := range value {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// isAddress reports whether value looks like a bech32 gno.land address
+func isAddress(value string) bool {
+	data, ok := strings.CutPrefix(value, "g1")
+	if !ok || len(data) < 6 {
+		return false
+	}
+	for _, r := range data {
+		if !strings.ContainsRune("qpzry9x8gf2tvdw0s3jn54khce6mua7l", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isRealmPath reports whether value looks like a realm package path
+func isRealmPath(value string) bool {
+	return strings.Contains(value, "/") && !strings.ContainsAny(value, " \t\r\n")
+}
+
 func ParseUserLinkedEvent(event GnoEvent) (*UserLinkedEvent, error) {
 	if event.Type != "UserLinked" {
 		return nil, fmt.Errorf("event type %s is not UserLinked", event.Type)
 	}
 
+	attrs, err := eventAttributes(event)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &UserLinkedEvent{}
-	for _, attr := range event.Attrs {
-		switch attr.Key {
-		case "address":
-			result.Address = attr.Value
-		case "discordID":
-			result.DiscordID = attr.Value
-		}
+	if result.Address, err = requireAttribute(event, attrs, "address", isAddress); err != nil {
+		return nil, err
+	}
+	if result.DiscordID, err = requireAttribute(event, attrs, "discordID", isUserID); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -104,16 +203,17 @@ func ParseUserUnlinkedEvent(event GnoEvent) (*UserUnlinkedEvent, error) {
 		return nil, fmt.Errorf("event type %s is not UserUnlinked", event.Type)
 	}
 
-	result := &UserUnlinkedEvent{}
-	for _, attr := range event.Attrs {
-		switch attr.Key {
-		case "address":
-			result.Address = attr.Value
-		case "discordID":
-			result.DiscordID = attr.Value
-		case "triggeredBy":
-			result.TriggeredBy = attr.Value
-		}
+	attrs, err := eventAttributes(event)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UserUnlinkedEvent{TriggeredBy: attrs["triggeredBy"]}
+	if result.Address, err = requireAttribute(event, attrs, "address", isAddress); err != nil {
+		return nil, err
+	}
+	if result.DiscordID, err = requireAttribute(event, attrs, "discordID", isUserID); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -124,21 +224,17 @@ func ParseRoleLinkedEvent(event GnoEvent) (*RoleLinkedEvent, error) {
 		return nil, fmt.Errorf("event type %s is not RoleLinked", event.Type)
 	}
 
-	result := &RoleLinkedEvent{}
-	for _, attr := range event.Attrs {
-		switch attr.Key {
-		case "realmPath":
-			result.RealmPath = attr.Value
-		case "roleName":
-			result.RoleName = attr.Value
-		case "discordGuildID":
-			result.DiscordGuildID = attr.Value
-		case "discordRoleID":
-			result.DiscordRoleID = attr.Value
-		}
+	realmPath, roleName, guildID, roleID, err := parseRoleEventAttributes(event)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return &RoleLinkedEvent{
+		RealmPath:      realmPath,
+		RoleName:       roleName,
+		DiscordGuildID: guildID,
+		DiscordRoleID:  roleID,
+	}, nil
 }
 
 func ParseRoleUnlinkedEvent(event GnoEvent) (*RoleUnlinkedEvent, error) {
@@ -146,19 +242,36 @@ func ParseRoleUnlinkedEvent(event GnoEvent) (*RoleUnlinkedEvent, error) {
 		return nil, fmt.Errorf("event type %s is not RoleUnlinked", event.Type)
 	}
 
-	result := &RoleUnlinkedEvent{}
-	for _, attr := range event.Attrs {
-		switch attr.Key {
-		case "realmPath":
-			result.RealmPath = attr.Value
-		case "roleName":
-			result.RoleName = attr.Value
-		case "discordGuildID":
-			result.DiscordGuildID = attr.Value
-		case "discordRoleID":
-			result.DiscordRoleID = attr.Value
-		}
+	realmPath, roleName, guildID, roleID, err := parseRoleEventAttributes(event)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return &RoleUnlinkedEvent{
+		RealmPath:      realmPath,
+		RoleName:       roleName,
+		DiscordGuildID: guildID,
+		DiscordRoleID:  roleID,
+	}, nil
+}
+
+// parseRoleEventAttributes validates the attributes shared by RoleLinked and RoleUnlinked
+func parseRoleEventAttributes(event GnoEvent) (realmPath, roleName, guildID, roleID string, err error) {
+	attrs, err := eventAttributes(event)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	if realmPath, err = requireAttribute(event, attrs, "realmPath", isRealmPath); err != nil {
+		return "", "", "", "", err
+	}
+	if roleName, err = requireAttribute(event, attrs, "roleName", nil); err != nil {
+		return "", "", "", "", err
+	}
+	if guildID, err = requireAttribute(event, attrs, "discordGuildID", isSnowflake); err != nil {
+		return "", "", "", "", err
+	}
+	if roleID, err = requireAttribute(event, attrs, "discordRoleID", isSnowflake); err != nil {
+		return "", "", "", "", err
+	}
+	return realmPath, roleName, guildID, roleID, nil
 }
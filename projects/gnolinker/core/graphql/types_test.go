@@ -0,0 +1,141 @@
+package graphql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseUserLinkedEvent_Validation(t *testing.T) {
+	const (
+		address   = "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"
+		discordID = "123456789012345678"
+	)
+
+	tests := []struct {
+		name      string
+		attrs     []EventAttribute
+		wantAttr  string
+		wantValid bool
+	}{
+		{
+			name:      "valid",
+			attrs:     []EventAttribute{{Key: "address", Value: address}, {Key: "discordID", Value: discordID}},
+			wantValid: true,
+		},
+		{
+			name:      "extra attributes are ignored",
+			attrs:     []EventAttribute{{Key: "address", Value: address}, {Key: "discordID", Value: discordID}, {Key: "linkedAt", Value: "42"}},
+			wantValid: true,
+		},
+		{
+			name:      "repeated identical attribute",
+			attrs:     []EventAttribute{{Key: "address", Value: address}, {Key: "discordID", Value: discordID}, {Key: "discordID", Value: discordID}},
+			wantValid: true,
+		},
+		{
+			name:     "missing discord ID",
+			attrs:    []EventAttribute{{Key: "address", Value: address}},
+			wantAttr: "discordID",
+		},
+		{
+			name:     "renamed discord ID",
+			attrs:    []EventAttribute{{Key: "address", Value: address}, {Key: "discord_id", Value: discordID}},
+			wantAttr: "discordID",
+		},
+		{
+			name:     "empty address",
+			attrs:    []EventAttribute{{Key: "address", Value: ""}, {Key: "discordID", Value: discordID}},
+			wantAttr: "address",
+		},
+		{
+			name:     "malformed address",
+			attrs:    []EventAttribute{{Key: "address", Value: "not-an-address"}, {Key: "discordID", Value: discordID}},
+			wantAttr: "address",
+		},
+		{
+			name:     "malformed discord ID",
+			attrs:    []EventAttribute{{Key: "address", Value: address}, {Key: "discordID", Value: "@someone"}},
+			wantAttr: "discordID",
+		},
+		{
+			name:     "conflicting duplicate",
+			attrs:    []EventAttribute{{Key: "address", Value: address}, {Key: "discordID", Value: discordID}, {Key: "discordID", Value: "876543210987654321"}},
+			wantAttr: "discordID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseUserLinkedEvent(GnoEvent{Type: "UserLinked", Attrs: tt.attrs})
+			if tt.wantValid {
+				if err != nil {
+					t.Fatalf("ParseUserLinkedEvent() error = %v", err)
+				}
+				if event.Address != address || event.DiscordID != discordID {
+					t.Errorf("ParseUserLinkedEvent() = %+v", event)
+				}
+				return
+			}
+
+			if event != nil {
+				t.Errorf("ParseUserLinkedEvent() = %+v, want nil for an invalid event", event)
+			}
+			var parseErr *EventParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("error = %v, want an EventParseError", err)
+			}
+			if parseErr.Attribute != tt.wantAttr {
+				t.Errorf("error attribute = %q, want %q", parseErr.Attribute, tt.wantAttr)
+			}
+			if !errors.Is(err, ErrInvalidEvent) {
+				t.Error("EventParseError should match ErrInvalidEvent")
+			}
+		})
+	}
+}
+
+func TestParseUserUnlinkedEvent_SlackMemberID(t *testing.T) {
+	event, err := ParseUserUnlinkedEvent(GnoEvent{Type: "UserUnlinked", Attrs: []EventAttribute{
+		{Key: "address", Value: "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"},
+		{Key: "discordID", Value: "U0123ABCD"},
+	}})
+	if err != nil {
+		t.Fatalf("ParseUserUnlinkedEvent() error = %v", err)
+	}
+	if event.DiscordID != "U0123ABCD" {
+		t.Errorf("DiscordID = %q, want the Slack member ID", event.DiscordID)
+	}
+}
+
+func TestParseRoleEvents_Validation(t *testing.T) {
+	valid := []EventAttribute{
+		{Key: "realmPath", Value: "gno.land/r/demo/events"},
+		{Key: "roleName", Value: "member"},
+		{Key: "discordGuildID", Value: "100000000000000001"},
+		{Key: "discordRoleID", Value: "200000000000000001"},
+	}
+
+	linked, err := ParseRoleLinkedEvent(GnoEvent{Type: "RoleLinked", Attrs: valid})
+	if err != nil {
+		t.Fatalf("ParseRoleLinkedEvent() error = %v", err)
+	}
+	if linked.RealmPath != "gno.land/r/demo/events" || linked.RoleName != "member" ||
+		linked.DiscordGuildID != "100000000000000001" || linked.DiscordRoleID != "200000000000000001" {
+		t.Errorf("ParseRoleLinkedEvent() = %+v", linked)
+	}
+
+	// Dropping any required attribute must fail both role events
+	for i, attr := range valid {
+		partial := append(append([]EventAttribute{}, valid[:i]...), valid[i+1:]...)
+		if _, err := ParseRoleLinkedEvent(GnoEvent{Type: "RoleLinked", Attrs: partial}); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("RoleLinked without %s: error = %v, want ErrInvalidEvent", attr.Key, err)
+		}
+		if _, err := ParseRoleUnlinkedEvent(GnoEvent{Type: "RoleUnlinked", Attrs: partial}); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("RoleUnlinked without %s: error = %v, want ErrInvalidEvent", attr.Key, err)
+		}
+	}
+
+	if _, err := ParseRoleLinkedEvent(GnoEvent{Type: "RoleUnlinked", Attrs: valid}); err == nil || errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("wrong event type: error = %v, want a type mismatch error", err)
+	}
+}
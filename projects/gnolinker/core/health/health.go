@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+)
+
+// checkTimeout bounds each check so a hung dependency fails the probe instead of stalling it
+const checkTimeout = 5 * time.Second
+
+// Check reports whether a dependency is healthy
+type Check func(ctx context.Context) error
+
+// namedCheck is a check with the name it is reported under
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Status is the body of the health endpoints
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Server serves /healthz (liveness) and /readyz (readiness) for the bot process. Liveness only
+// tells that the process is serving requests, so a lost dependency such as the Discord session
+// marks the bot unready instead of getting it restarted.
+type Server struct {
+	mu        sync.RWMutex
+	readiness []namedCheck
+	mux       *http.ServeMux
+	server    *http.Server
+	logger    core.Logger
+}
+
+// NewServer creates a health server listening on addr once started
+func NewServer(addr string, logger core.Logger) *Server {
+	s := &Server{logger: logger, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		s.serveChecks(w, r, nil)
+	})
+	s.mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		s.serveChecks(w, r, s.readinessChecks())
+	})
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: checkTimeout,
+	}
+	return s
+}
+
+// AddReadinessCheck registers a check that must pass for the process to be ready for work
+func (s *Server) AddReadinessCheck(name string, check Check) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readiness = append(s.readiness, namedCheck{name: name, check: check})
+}
+
+// Handle serves an additional endpoint next to the health endpoints.
+// It must be called before Start.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Handler returns the HTTP handler serving the health endpoints
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// readinessChecks returns a copy of the registered readiness checks
+func (s *Server) readinessChecks() []namedCheck {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]namedCheck(nil), s.readiness...)
+}
+
+// serveChecks runs every check and responds 200 if all passed or 503 with the failures otherwise
+func (s *Server) serveChecks(w http.ResponseWriter, r *http.Request, checks []namedCheck) {
+	status := Status{Status: "ok", Checks: make(map[string]string, len(checks))}
+	for _, c := range checks {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		err := c.check(ctx)
+		cancel()
+
+		if err != nil {
+			status.Status = "unavailable"
+			status.Checks[c.name] = err.Error()
+			s.logger.Warn("Health check failed", "check", c.name, "path", r.URL.Path, "error", err)
+			continue
+		}
+		status.Checks[c.name] = "ok"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if status.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		s.logger.Error("Failed to write health status", "error", err)
+	}
+}
+
+// Start listens on the configured address and serves in the background.
+// Listen errors such as the address being in use are returned right away.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Health server listening", "addr", listener.Addr().String())
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Health server stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the server, waiting for in-flight probes until ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
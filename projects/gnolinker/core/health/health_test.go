@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+)
+
+func newTestServer(connected, indexerUp, rpcUp bool) *Server {
+	s := NewServer("127.0.0.1:0", core.NewSlogLogger(core.ParseLogLevel("error")))
+	status := func(up bool, failure string) Check {
+		return func(ctx context.Context) error {
+			if !up {
+				return errors.New(failure)
+			}
+			return nil
+		}
+	}
+	s.AddReadinessCheck("discord", status(connected, "session disconnected"))
+	s.AddReadinessCheck("indexer", status(indexerUp, "indexer unreachable"))
+	s.AddReadinessCheck("rpc", status(rpcUp, "rpc unreachable"))
+	return s
+}
+
+func TestServer_Checks(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		connected  bool
+		indexerUp  bool
+		rpcUp      bool
+		path       string
+		wantCode   int
+		wantFailed []string
+	}{
+		{name: "alive while dependencies are down", connected: true, path: "/healthz", wantCode: http.StatusOK},
+		{name: "alive while disconnected", path: "/healthz", wantCode: http.StatusOK},
+		{name: "ready", connected: true, indexerUp: true, rpcUp: true, path: "/readyz", wantCode: http.StatusOK},
+		{name: "indexer down", connected: true, rpcUp: true, path: "/readyz", wantCode: http.StatusServiceUnavailable, wantFailed: []string{"indexer"}},
+		{name: "not ready while disconnected", indexerUp: true, rpcUp: true, path: "/readyz", wantCode: http.StatusServiceUnavailable, wantFailed: []string{"discord"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := newTestServer(tt.connected, tt.indexerUp, tt.rpcUp)
+
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+
+			var status Status
+			if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+				t.Fatalf("body is not a health status: %v", err)
+			}
+			var failed []string
+			for name, result := range status.Checks {
+				if result != "ok" {
+					failed = append(failed, name)
+				}
+			}
+			if len(failed) != len(tt.wantFailed) || (len(failed) == 1 && failed[0] != tt.wantFailed[0]) {
+				t.Errorf("failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+			if (status.Status == "ok") != (tt.wantCode == http.StatusOK) {
+				t.Errorf("status %q does not match code %d", status.Status, rec.Code)
+			}
+		})
+	}
+}
+
+func TestServer_StartAndShutdown(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(true, true, true)
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := s.Shutdown(t.Context()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package linkstatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+)
+
+// Mode controls how much of a link the public lookup exposes
+type Mode string
+
+const (
+	// ModeOff disables the lookup endpoint
+	ModeOff Mode = "off"
+	// ModeBoolean only tells whether an address is linked
+	ModeBoolean Mode = "boolean"
+	// ModeFull also returns the linked Discord ID
+	ModeFull Mode = "full"
+)
+
+// ParseMode parses a mode name, case-insensitively. An empty name is ModeOff.
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case "":
+		return ModeOff, nil
+	case ModeOff, ModeBoolean, ModeFull:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid link status mode %q (want off, boolean or full)", name)
+	}
+}
+
+// addressPattern matches a bech32 Gno address. Addresses are interpolated into realm queries,
+// so anything else is rejected before reaching the chain.
+var addressPattern = regexp.MustCompile(`^g1[02-9ac-hj-np-z]{38}$`)
+
+// Lookup resolves the platform user linked to a Gno address from the linker realm
+type Lookup interface {
+	GetLinkedPlatformID(gnoAddress string) (string, error)
+}
+
+// Status is the body of the link lookup endpoint
+type Status struct {
+	Address   string `json:"address"`
+	Linked    bool   `json:"linked"`
+	DiscordID string `json:"discord_id,omitempty"`
+}
+
+// GetStatus looks up whether an address is linked. The Discord ID is only included in ModeFull.
+func GetStatus(lookup Lookup, mode Mode, address string) (*Status, error) {
+	if !addressPattern.MatchString(address) {
+		return nil, fmt.Errorf("invalid gno address %q", address)
+	}
+
+	discordID, err := lookup.GetLinkedPlatformID(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up linked platform ID: %w", err)
+	}
+
+	status := &Status{Address: address, Linked: discordID != ""}
+	if mode == ModeFull {
+		status.DiscordID = discordID
+	}
+	return status, nil
+}
+
+// Pattern is the route the handler is meant to be served on
+const Pattern = "GET /link/{address}"
+
+// Handler serves GET /link/{address}, a public read-only view of the linker realm
+type Handler struct {
+	lookup Lookup
+	mode   Mode
+	logger core.Logger
+}
+
+// NewHandler creates a link lookup handler exposing links as configured by mode
+func NewHandler(lookup Lookup, mode Mode, logger core.Logger) *Handler {
+	return &Handler{lookup: lookup, mode: mode, logger: logger}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.mode == ModeOff {
+		http.NotFound(w, r)
+		return
+	}
+
+	address := r.PathValue("address")
+	if !addressPattern.MatchString(address) {
+		http.Error(w, "invalid gno address", http.StatusBadRequest)
+		return
+	}
+
+	status, err := GetStatus(h.lookup, h.mode, address)
+	if err != nil {
+		h.logger.Warn("Link status lookup failed", "address", address, "error", err)
+		http.Error(w, "link lookup failed", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		h.logger.Error("Failed to write link status", "error", err)
+	}
+}
@@ -0,0 +1,95 @@
+package linkstatus
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+)
+
+const (
+	linkedAddress   = "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"
+	unlinkedAddress = "g1us8428u2a5satrlxzagqqa5m6vmuze025anjlj"
+	linkedDiscordID = "123456789012345678"
+)
+
+// fakeLookup serves links from a map, failing when err is set
+type fakeLookup struct {
+	links map[string]string
+	err   error
+}
+
+func (f *fakeLookup) GetLinkedPlatformID(gnoAddress string) (string, error) {
+	return f.links[gnoAddress], f.err
+}
+
+func serve(t *testing.T, lookup Lookup, mode Mode, address string) *httptest.ResponseRecorder {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(Pattern, NewHandler(lookup, mode, core.NewSlogLogger(core.ParseLogLevel("error"))))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/link/"+address, nil))
+	return rec
+}
+
+func TestHandler_Modes(t *testing.T) {
+	t.Parallel()
+	lookup := &fakeLookup{links: map[string]string{linkedAddress: linkedDiscordID}}
+
+	tests := []struct {
+		name     string
+		mode     Mode
+		address  string
+		wantCode int
+		want     Status
+	}{
+		{name: "full exposes the discord id", mode: ModeFull, address: linkedAddress, wantCode: http.StatusOK, want: Status{Address: linkedAddress, Linked: true, DiscordID: linkedDiscordID}},
+		{name: "boolean hides the discord id", mode: ModeBoolean, address: linkedAddress, wantCode: http.StatusOK, want: Status{Address: linkedAddress, Linked: true}},
+		{name: "unlinked address", mode: ModeFull, address: unlinkedAddress, wantCode: http.StatusOK, want: Status{Address: unlinkedAddress}},
+		{name: "off", mode: ModeOff, address: linkedAddress, wantCode: http.StatusNotFound},
+		{name: "invalid address", mode: ModeFull, address: `g1")`, wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := serve(t, lookup, tt.mode, tt.address)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var got Status
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode body %q: %v", rec.Body.String(), err)
+			}
+			if got != tt.want {
+				t.Errorf("status = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_LookupFailure(t *testing.T) {
+	t.Parallel()
+	rec := serve(t, &fakeLookup{err: errors.New("rpc unreachable")}, ModeBoolean, linkedAddress)
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+	for name, want := range map[string]Mode{"": ModeOff, "off": ModeOff, "Boolean": ModeBoolean, " full ": ModeFull} {
+		if got, err := ParseMode(name); err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseMode("everything"); err == nil {
+		t.Error("ParseMode should reject unknown modes")
+	}
+}
@@ -0,0 +1,64 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// KeepAlive renews a held lock every third of its TTL until stopped, so work that outlasts the
+// TTL keeps it. Renewal ends at the first failure, as the lock is then no longer held.
+//
+// The returned stop function ends renewal, waits for it to finish and returns the renewal error,
+// if any. Call it before releasing the lock.
+func KeepAlive(ctx context.Context, manager LockManager, lock *Lock, ttl time.Duration) (stop func() error) {
+	_, stop = KeepAliveContext(ctx, manager, lock, ttl)
+	return stop
+}
+
+// KeepAliveContext is KeepAlive for work that must stop once the lock is lost: the returned
+// context is cancelled as soon as a renewal fails, and when stop is called.
+func KeepAliveContext(ctx context.Context, manager LockManager, lock *Lock, ttl time.Duration) (context.Context, func() error) {
+	workCtx, cancelWork := context.WithCancel(ctx)
+	renewCtx, cancelRenewal := context.WithCancel(ctx)
+	interval := ttl / 3
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	var (
+		wg       sync.WaitGroup
+		renewErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				if err := manager.RenewLock(renewCtx, lock, ttl); err != nil {
+					if renewCtx.Err() == nil {
+						renewErr = err
+						cancelWork()
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return workCtx, func() error {
+		once.Do(func() {
+			cancelRenewal()
+			wg.Wait()
+			cancelWork()
+		})
+		return renewErr
+	}
+}
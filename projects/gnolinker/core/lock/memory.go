@@ -94,6 +94,37 @@ func (m *MemoryLockManager) ReleaseLock(ctx context.Context, lock *Lock) error {
 	return nil
 }
 
+// RenewLock extends a lock still held by the same token
+func (m *MemoryLockManager) RenewLock(ctx context.Context, lock *Lock, ttl time.Duration) error {
+	if lock == nil {
+		return errors.New("lock cannot be nil")
+	}
+	if ttl == 0 {
+		ttl = m.config.DefaultTTL
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, exists := m.locks[lock.Key]
+	if !exists {
+		return ErrLockNotFound
+	}
+
+	if existing.HolderID != lock.HolderID || existing.Token != lock.Token {
+		return ErrLockNotHeld
+	}
+
+	if existing.IsExpired() {
+		return ErrLockExpired
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	existing.ExpiresAt = expiresAt
+	lock.ExpiresAt = expiresAt
+	return nil
+}
+
 // IsLocked checks if a lock exists and is valid
 func (m *MemoryLockManager) IsLocked(ctx context.Context, key string) (bool, error) {
 	m.mu.RLock()
@@ -259,6 +259,81 @@ func TestMemoryLockManager_ReleaseLock(t *testing.T) {
 	}
 }
 
+func TestMemoryLockManager_RenewLock(t *testing.T) {
+	t.Parallel()
+	manager := NewMemoryLockManager(LockConfig{
+		DefaultTTL:    time.Minute,
+		RetryInterval: 10 * time.Millisecond,
+		MaxRetries:    0,
+	})
+	ctx := context.Background()
+	ttl := 100 * time.Millisecond
+
+	lock, err := manager.AcquireLock(ctx, "renew-key", ttl)
+	if err != nil {
+		t.Fatalf("AcquireLock() failed: %v", err)
+	}
+	originalExpiry := lock.ExpiresAt
+
+	// Kept alive, the lock outlasts its original TTL
+	stop := KeepAlive(ctx, manager, lock, ttl)
+	time.Sleep(3 * ttl)
+
+	if locked, _ := manager.IsLocked(ctx, "renew-key"); !locked {
+		t.Error("Renewed lock should still be held past its original TTL")
+	}
+	if _, err := manager.AcquireLock(ctx, "renew-key", ttl); err != ErrLockAcquisitionFailed {
+		t.Errorf("AcquireLock() of a renewed lock error = %v, want %v", err, ErrLockAcquisitionFailed)
+	}
+	if err := stop(); err != nil {
+		t.Errorf("stop() error = %v, want renewals to succeed", err)
+	}
+	if !lock.ExpiresAt.After(originalExpiry) {
+		t.Errorf("ExpiresAt = %v, want it extended past %v", lock.ExpiresAt, originalExpiry)
+	}
+
+	// Once the lock expires and is taken over, the stale holder cannot renew it
+	time.Sleep(lock.RemainingTTL() + 10*time.Millisecond)
+	if err := manager.RenewLock(ctx, lock, ttl); err != ErrLockExpired {
+		t.Errorf("RenewLock() of an expired lock error = %v, want %v", err, ErrLockExpired)
+	}
+	newHolder, err := manager.AcquireLock(ctx, "renew-key", time.Minute)
+	if err != nil {
+		t.Fatalf("AcquireLock() of the expired lock failed: %v", err)
+	}
+	if err := manager.RenewLock(ctx, lock, ttl); err != ErrLockNotHeld {
+		t.Errorf("RenewLock() with a stale token error = %v, want %v", err, ErrLockNotHeld)
+	}
+	if err := manager.RenewLock(ctx, newHolder, time.Minute); err != nil {
+		t.Errorf("RenewLock() by the new holder failed: %v", err)
+	}
+}
+
+func TestKeepAliveContext_CancelledWhenLockLost(t *testing.T) {
+	t.Parallel()
+	manager := NewMemoryLockManager(LockConfig{DefaultTTL: time.Minute})
+	ctx := context.Background()
+	ttl := 60 * time.Millisecond
+
+	lock, err := manager.AcquireLock(ctx, "keepalive-key", ttl)
+	if err != nil {
+		t.Fatalf("AcquireLock() failed: %v", err)
+	}
+	workCtx, stop := KeepAliveContext(ctx, manager, &Lock{Key: lock.Key, HolderID: lock.HolderID, Token: lock.Token, ExpiresAt: lock.ExpiresAt}, ttl)
+
+	if err := manager.ReleaseLock(ctx, lock); err != nil {
+		t.Fatalf("ReleaseLock() failed: %v", err)
+	}
+	select {
+	case <-workCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context should be cancelled once the lock cannot be renewed")
+	}
+	if err := stop(); err != ErrLockNotFound {
+		t.Errorf("stop() error = %v, want %v", err, ErrLockNotFound)
+	}
+}
+
 func TestMemoryLockManager_IsLocked(t *testing.T) {
 	t.Parallel()
 	manager := NewMemoryLockManager(DefaultLockConfig())
@@ -31,6 +31,12 @@ func (m *NoOpLockManager) ReleaseLock(ctx context.Context, lock *Lock) error {
 	return nil
 }
 
+// RenewLock always succeeds
+func (m *NoOpLockManager) RenewLock(ctx context.Context, lock *Lock, ttl time.Duration) error {
+	lock.ExpiresAt = time.Now().Add(ttl)
+	return nil
+}
+
 // IsLocked always returns false (no locks exist)
 func (m *NoOpLockManager) IsLocked(ctx context.Context, key string) (bool, error) {
 	return false, nil
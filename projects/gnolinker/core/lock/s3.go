@@ -225,8 +225,63 @@ func (m *S3LockManager) IsLocked(ctx context.Context, key string) (bool, error)
 	return !lock.IsExpired(), nil
 }
 
+// RenewLock extends a lock still held by this instance and token. The lock object is only
+// overwritten if unchanged since it was read, so a lock taken over meanwhile is not clobbered.
+func (m *S3LockManager) RenewLock(ctx context.Context, lock *Lock, ttl time.Duration) error {
+	if lock == nil {
+		return errors.New("lock cannot be nil")
+	}
+	if ttl == 0 {
+		ttl = m.config.DefaultTTL
+	}
+
+	currentLock, etag, err := m.getLock(ctx, lock.Key)
+	if err != nil {
+		return err
+	}
+
+	if currentLock.HolderID != m.instanceID || currentLock.Token != lock.Token {
+		return ErrLockNotHeld
+	}
+
+	if currentLock.IsExpired() {
+		return ErrLockExpired
+	}
+
+	renewed := *currentLock
+	renewed.ExpiresAt = time.Now().Add(ttl)
+	lockData, err := json.Marshal(&renewed)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock: %w", err)
+	}
+
+	_, err = m.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(m.bucket),
+		Key:         aws.String(m.getObjectKey(lock.Key)),
+		Body:        bytes.NewReader(lockData),
+		ContentType: aws.String("application/json"),
+		IfMatch:     etag,
+		Metadata: map[string]string{
+			"holder-id": m.instanceID,
+			"token":     lock.Token,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to renew lock: %w", err)
+	}
+
+	lock.ExpiresAt = renewed.ExpiresAt
+	return nil
+}
+
 // GetLock retrieves information about a lock
 func (m *S3LockManager) GetLock(ctx context.Context, key string) (*Lock, error) {
+	lock, _, err := m.getLock(ctx, key)
+	return lock, err
+}
+
+// getLock retrieves a lock along with the ETag of its object
+func (m *S3LockManager) getLock(ctx context.Context, key string) (*Lock, *string, error) {
 	objectKey := m.getObjectKey(key)
 
 	result, err := m.client.GetObject(ctx, &s3.GetObjectInput{
@@ -237,9 +292,9 @@ func (m *S3LockManager) GetLock(ctx context.Context, key string) (*Lock, error)
 	if err != nil {
 		var nsk *types.NoSuchKey
 		if errors.As(err, &nsk) {
-			return nil, ErrLockNotFound
+			return nil, nil, ErrLockNotFound
 		}
-		return nil, fmt.Errorf("failed to get lock from S3: %w", err)
+		return nil, nil, fmt.Errorf("failed to get lock from S3: %w", err)
 	}
 	defer func() {
 		if err := result.Body.Close(); err != nil {
@@ -250,15 +305,15 @@ func (m *S3LockManager) GetLock(ctx context.Context, key string) (*Lock, error)
 
 	body, err := io.ReadAll(result.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read lock data: %w", err)
+		return nil, nil, fmt.Errorf("failed to read lock data: %w", err)
 	}
 
 	var lock Lock
 	if err := json.Unmarshal(body, &lock); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal lock: %w", err)
+		return nil, nil, fmt.Errorf("failed to unmarshal lock: %w", err)
 	}
 
-	return &lock, nil
+	return &lock, result.ETag, nil
 }
 
 // getObjectKey generates the S3 object key for a lock
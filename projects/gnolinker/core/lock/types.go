@@ -24,6 +24,10 @@ type LockManager interface {
 	// This is best-effort - locks will auto-expire based on TTL
 	ReleaseLock(ctx context.Context, lock *Lock) error
 
+	// RenewLock extends a held lock to expire ttl from now, updating lock.ExpiresAt
+	// Returns ErrLockNotHeld if the lock's token no longer holds it, so a stale holder cannot renew
+	RenewLock(ctx context.Context, lock *Lock, ttl time.Duration) error
+
 	// IsLocked checks if a lock exists and is still valid (not expired)
 	IsLocked(ctx context.Context, key string) (bool, error)
 
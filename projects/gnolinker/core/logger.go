@@ -2,6 +2,8 @@ package core
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"log/slog"
 	"os"
 	"strings"
@@ -17,6 +19,16 @@ type Logger interface {
 	WithGroup(name string) Logger
 }
 
+// CorrelationIDKey is the log attribute that ties together all log lines for one handled event
+const CorrelationIDKey = "correlation_id"
+
+// NewCorrelationID generates a short random ID used to correlate log lines across components
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // SlogLogger is a slog-based implementation of Logger
 type SlogLogger struct {
 	logger *slog.Logger
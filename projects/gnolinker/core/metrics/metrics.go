@@ -0,0 +1,230 @@
+// Package metrics registers gnolinker's sync and event processing metrics with Prometheus.
+// The metrics live in their own registry so they can be recorded and scraped in tests without
+// starting a server.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is where the metrics server serves the metrics
+const Path = "/metrics"
+
+// QueryDurationBuckets are the histogram bounds, in seconds, for query execution durations
+var QueryDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics records gnolinker's sync and event processing metrics. A nil *Metrics records
+// nothing, so callers don't need to check whether metrics are enabled.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	eventsProcessed    *prometheus.CounterVec
+	roleChanges        *prometheus.CounterVec
+	verificationRuns   *prometheus.CounterVec
+	linkConflicts      *prometheus.CounterVec
+	queryDuration      *prometheus.HistogramVec
+	lastProcessedBlock *prometheus.GaugeVec
+	realmQueryBreaker  prometheus.Gauge
+}
+
+// New creates the metrics in their own registry, along with the Go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		eventsProcessed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gnolinker_events_processed_total",
+			Help: "On-chain events handled, by event type and result.",
+		}, []string{"type", "result"}),
+		roleChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gnolinker_role_changes_total",
+			Help: "Platform role additions and removals, by action and result.",
+		}, []string{"action", "result"}),
+		verificationRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gnolinker_verification_runs_total",
+			Help: "Tiered member verification runs, by priority tier.",
+		}, []string{"priority"}),
+		linkConflicts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gnolinker_link_conflicts_total",
+			Help: "User links breaking a guild's link uniqueness rules, by conflict kind.",
+		}, []string{"kind"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gnolinker_query_duration_seconds",
+			Help:    "Time spent executing and handling a scheduled query.",
+			Buckets: QueryDurationBuckets,
+		}, []string{"query"}),
+		lastProcessedBlock: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "gnolinker_last_processed_block",
+			Help: "Last block height processed by an event stream query, by guild.",
+		}, []string{"guild_id", "query"}),
+		realmQueryBreaker: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "gnolinker_realm_query_breaker_open",
+			Help: "Whether realm queries are paused by the circuit breaker (1) or not (0).",
+		}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.eventsProcessed,
+		m.roleChanges,
+		m.verificationRuns,
+		m.linkConflicts,
+		m.queryDuration,
+		m.lastProcessedBlock,
+		m.realmQueryBreaker,
+	)
+	return m
+}
+
+// Registry returns the registry the metrics are registered in
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// CollectAPICalls exports the totals of counter as gnolinker_platform_api_calls_total, by operation
+func (m *Metrics) CollectAPICalls(counter *core.APICallCounter) {
+	if m == nil || counter == nil {
+		return
+	}
+	m.registry.MustRegister(&apiCallsCollector{counter: counter})
+}
+
+// EventProcessed counts a handled event of the given type
+func (m *Metrics) EventProcessed(eventType string, err error) {
+	if m == nil {
+		return
+	}
+	m.eventsProcessed.WithLabelValues(eventType, result(err)).Inc()
+}
+
+// RoleChange counts a role addition ("add") or removal ("remove")
+func (m *Metrics) RoleChange(action string, err error) {
+	if m == nil {
+		return
+	}
+	m.roleChanges.WithLabelValues(action, result(err)).Inc()
+}
+
+// VerificationRun counts a verification run of the given priority tier
+func (m *Metrics) VerificationRun(priority string) {
+	if m == nil {
+		return
+	}
+	m.verificationRuns.WithLabelValues(priority).Inc()
+}
+
+// LinkConflict counts a link conflict of the given kind
+func (m *Metrics) LinkConflict(kind string) {
+	if m == nil {
+		return
+	}
+	m.linkConflicts.WithLabelValues(kind).Inc()
+}
+
+// ObserveQuery records how long a query took since it started
+func (m *Metrics) ObserveQuery(queryID string, started time.Time) {
+	if m == nil {
+		return
+	}
+	m.queryDuration.WithLabelValues(queryID).Observe(time.Since(started).Seconds())
+}
+
+// SetLastProcessedBlock records a guild's position in an event stream query
+func (m *Metrics) SetLastProcessedBlock(guildID, queryID string, height int64) {
+	if m == nil {
+		return
+	}
+	m.lastProcessedBlock.WithLabelValues(guildID, queryID).Set(float64(height))
+}
+
+// SetRealmQueryBreakerOpen records whether the realm query circuit breaker is open
+func (m *Metrics) SetRealmQueryBreakerOpen(open bool) {
+	if m == nil {
+		return
+	}
+	value := 0.0
+	if open {
+		value = 1
+	}
+	m.realmQueryBreaker.Set(value)
+}
+
+// apiCallsDesc describes the platform API call totals read from an APICallCounter
+var apiCallsDesc = prometheus.NewDesc("gnolinker_platform_api_calls_total",
+	"Platform REST API calls, by operation.", []string{"operation"}, nil)
+
+// apiCallsCollector reads the totals of an APICallCounter at scrape time
+type apiCallsCollector struct {
+	counter *core.APICallCounter
+}
+
+func (c *apiCallsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- apiCallsDesc
+}
+
+func (c *apiCallsCollector) Collect(ch chan<- prometheus.Metric) {
+	for operation, count := range c.counter.Snapshot() {
+		ch <- prometheus.MustNewConstMetric(apiCallsDesc, prometheus.CounterValue, float64(count), operation)
+	}
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// Server serves the metrics on their own address, separate from the health endpoints
+type Server struct {
+	server *http.Server
+	logger core.Logger
+}
+
+// NewServer creates a metrics server listening on addr
+func NewServer(addr string, m *Metrics, logger core.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET "+Path, m.Handler())
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Start begins listening; it returns once the listener is bound and serves in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Metrics server stopped", "error", err)
+		}
+	}()
+	s.logger.Info("Metrics server listening", "addr", listener.Addr().String(), "path", Path)
+	return nil
+}
+
+// Shutdown gracefully stops the server
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_Scrape(t *testing.T) {
+	m := New()
+	m.EventProcessed("UserLinked", nil)
+	m.EventProcessed("UserLinked", nil)
+	m.EventProcessed("RoleLinked", errors.New("boom"))
+	m.RoleChange("add", nil)
+	m.RoleChange("remove", errors.New("forbidden"))
+	m.VerificationRun("high")
+	m.SetLastProcessedBlock("guild-1", "user_events", 1234)
+	m.ObserveQuery("user_events", time.Now().Add(-300*time.Millisecond))
+	m.LinkConflict("multiple-addresses")
+	m.SetRealmQueryBreakerOpen(true)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		"# TYPE gnolinker_events_processed_total counter",
+		`gnolinker_events_processed_total{result="success",type="UserLinked"} 2`,
+		`gnolinker_events_processed_total{result="error",type="RoleLinked"} 1`,
+		`gnolinker_role_changes_total{action="add",result="success"} 1`,
+		`gnolinker_role_changes_total{action="remove",result="error"} 1`,
+		`gnolinker_verification_runs_total{priority="high"} 1`,
+		"# TYPE gnolinker_last_processed_block gauge",
+		`gnolinker_last_processed_block{guild_id="guild-1",query="user_events"} 1234`,
+		"# TYPE gnolinker_query_duration_seconds histogram",
+		`gnolinker_query_duration_seconds_bucket{query="user_events",le="0.25"} 0`,
+		`gnolinker_query_duration_seconds_bucket{query="user_events",le="0.5"} 1`,
+		`gnolinker_query_duration_seconds_bucket{query="user_events",le="+Inf"} 1`,
+		`gnolinker_query_duration_seconds_count{query="user_events"} 1`,
+		`gnolinker_link_conflicts_total{kind="multiple-addresses"} 1`,
+		"gnolinker_realm_query_breaker_open 1",
+		"# TYPE go_goroutines gauge",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape missing %q\n%s", want, body)
+		}
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
+
+func TestMetrics_NilIsNoop(t *testing.T) {
+	var m *Metrics
+	m.EventProcessed("UserLinked", nil)
+	m.RoleChange("add", nil)
+	m.VerificationRun("low")
+	m.ObserveQuery("user_events", time.Now())
+	m.SetLastProcessedBlock("guild-1", "user_events", 1)
+	m.LinkConflict("multiple-addresses")
+	m.CollectAPICalls(core.NewAPICallCounter())
+}
+
+func TestMetrics_CollectAPICalls(t *testing.T) {
+	m := New()
+	counter := core.NewAPICallCounter()
+	m.CollectAPICalls(counter)
+	counter.Record("GuildMemberRoleAdd")
+	counter.Record("GuildMemberRoleAdd")
+
+	// The totals are read from the counter when scraped
+	want := `
+		# HELP gnolinker_platform_api_calls_total Platform REST API calls, by operation.
+		# TYPE gnolinker_platform_api_calls_total counter
+		gnolinker_platform_api_calls_total{operation="GuildMemberRoleAdd"} 2
+	`
+	if err := testutil.GatherAndCompare(m.Registry(), strings.NewReader(want), "gnolinker_platform_api_calls_total"); err != nil {
+		t.Error(err)
+	}
+}
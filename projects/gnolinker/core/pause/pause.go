@@ -0,0 +1,75 @@
+package pause
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+// Patterns are the routes the handler is meant to be served on
+const (
+	PausePattern  = "POST /guilds/{guildID}/pause"
+	ResumePattern = "POST /guilds/{guildID}/resume"
+)
+
+// Pauser persists whether a guild's processing is paused
+type Pauser interface {
+	SetGuildPaused(guildID string, paused bool) error
+}
+
+// Status is the body of the pause endpoints
+type Status struct {
+	GuildID string `json:"guild_id"`
+	Paused  bool   `json:"paused"`
+}
+
+// Handler serves POST /guilds/{guildID}/pause and /resume for operators, the HTTP
+// counterpart of /gnolinker admin pause and resume. Requests must carry the admin token
+// as a bearer token.
+type Handler struct {
+	pauser Pauser
+	paused bool
+	token  string
+	logger core.Logger
+}
+
+// NewHandler creates a handler that pauses guilds, or resumes them when paused is false.
+// An empty token rejects every request.
+func NewHandler(pauser Pauser, paused bool, token string, logger core.Logger) *Handler {
+	return &Handler{pauser: pauser, paused: paused, token: token, logger: logger}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	guildID := r.PathValue("guildID")
+	if err := h.pauser.SetGuildPaused(guildID, h.paused); err != nil {
+		if errors.Is(err, storage.ErrGuildConfigNotFound) {
+			http.Error(w, "guild not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to update guild processing state", "guild_id", guildID, "paused", h.paused, "error", err)
+		http.Error(w, "failed to update processing state", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Status{GuildID: guildID, Paused: h.paused}); err != nil {
+		h.logger.Error("Failed to write pause status", "error", err)
+	}
+}
+
+// authorized reports whether the request carries the admin token, compared in constant time
+func (h *Handler) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && h.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
@@ -0,0 +1,109 @@
+package pause
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+const (
+	testToken   = "operator-token"
+	testGuildID = "100000000000000001"
+)
+
+// fakePauser records paused state for known guilds
+type fakePauser struct {
+	paused map[string]bool
+}
+
+func (f *fakePauser) SetGuildPaused(guildID string, paused bool) error {
+	if _, ok := f.paused[guildID]; !ok {
+		return fmt.Errorf("failed to get guild config: %w", storage.ErrGuildConfigNotFound)
+	}
+	f.paused[guildID] = paused
+	return nil
+}
+
+func serve(t *testing.T, pauser Pauser, token, path, authorization string) *httptest.ResponseRecorder {
+	t.Helper()
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	mux := http.NewServeMux()
+	mux.Handle(PausePattern, NewHandler(pauser, true, token, logger))
+	mux.Handle(ResumePattern, NewHandler(pauser, false, token, logger))
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_PauseAndResume(t *testing.T) {
+	t.Parallel()
+	pauser := &fakePauser{paused: map[string]bool{testGuildID: false}}
+
+	for _, want := range []bool{true, false} {
+		action := "resume"
+		if want {
+			action = "pause"
+		}
+		rec := serve(t, pauser, testToken, "/guilds/"+testGuildID+"/"+action, "Bearer "+testToken)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d", action, rec.Code, http.StatusOK)
+		}
+
+		var got Status
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("failed to decode body %q: %v", rec.Body.String(), err)
+		}
+		if got != (Status{GuildID: testGuildID, Paused: want}) {
+			t.Errorf("%s: status = %+v", action, got)
+		}
+		if pauser.paused[testGuildID] != want {
+			t.Errorf("%s: stored paused = %v, want %v", action, pauser.paused[testGuildID], want)
+		}
+	}
+}
+
+func TestHandler_RequiresToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+	}{
+		{name: "missing header", token: testToken},
+		{name: "wrong token", token: testToken, authorization: "Bearer guess"},
+		{name: "not a bearer token", token: testToken, authorization: testToken},
+		{name: "no token configured", authorization: "Bearer "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pauser := &fakePauser{paused: map[string]bool{testGuildID: false}}
+			rec := serve(t, pauser, tt.token, "/guilds/"+testGuildID+"/pause", tt.authorization)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+			if pauser.paused[testGuildID] {
+				t.Error("guild should not be paused by an unauthorized request")
+			}
+		})
+	}
+}
+
+func TestHandler_UnknownGuild(t *testing.T) {
+	t.Parallel()
+	rec := serve(t, &fakePauser{paused: map[string]bool{}}, testToken, "/guilds/"+testGuildID+"/pause", "Bearer "+testToken)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
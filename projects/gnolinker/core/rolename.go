@@ -0,0 +1,84 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const (
+	// DefaultRoleNameTemplate names platform roles after the realm role and the last realm path segment
+	DefaultRoleNameTemplate = "{{.Role}} ({{.RealmShort}})"
+	// LegacyRoleNameTemplate is the naming scheme used before role name templates were configurable
+	LegacyRoleNameTemplate = "{{.Role}}-{{.RealmPath}}"
+
+	// maxRoleNameLength is the longest role name Discord accepts
+	maxRoleNameLength = 100
+)
+
+// RoleNameData is the data available to role name templates
+type RoleNameData struct {
+	Role       string // Realm role name, e.g. "admin"
+	RealmPath  string // Full realm path, e.g. "gno.land/r/demo/boards"
+	RealmShort string // Last realm path segment, e.g. "boards"
+}
+
+// NewRoleNameData builds the template data for a realm role
+func NewRoleNameData(roleName, realmPath string) RoleNameData {
+	trimmed := strings.TrimRight(realmPath, "/")
+	short := trimmed[strings.LastIndex(trimmed, "/")+1:]
+	return RoleNameData{
+		Role:       roleName,
+		RealmPath:  realmPath,
+		RealmShort: short,
+	}
+}
+
+// ParseLegacyRoleName splits a role name in the legacy "{roleName}-{realmPath}" format. Realm role
+// names may contain hyphens (e.g. "core-dev"), so it splits on the last "-gno.land/r/" boundary.
+func ParseLegacyRoleName(name string) (roleName, realmPath string, ok bool) {
+	i := strings.LastIndex(name, "-gno.land/r/")
+	if i <= 0 {
+		return "", "", false
+	}
+	return name[:i], name[i+1:], true
+}
+
+// ParseRoleNameTemplate parses and validates a role name template
+func ParseRoleNameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("role_name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid role name template: %w", err)
+	}
+
+	// Render sample data so templates referencing unknown fields are rejected up front
+	if _, err := executeRoleNameTemplate(tmpl, NewRoleNameData("role", "gno.land/r/demo/realm")); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// RenderRoleName renders the platform role name for a realm role using the given template
+func RenderRoleName(text, roleName, realmPath string) (string, error) {
+	tmpl, err := ParseRoleNameTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	return executeRoleNameTemplate(tmpl, NewRoleNameData(roleName, realmPath))
+}
+
+func executeRoleNameTemplate(tmpl *template.Template, data RoleNameData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render role name: %w", err)
+	}
+
+	name := strings.TrimSpace(b.String())
+	if name == "" {
+		return "", fmt.Errorf("role name template rendered an empty name")
+	}
+	if runes := []rune(name); len(runes) > maxRoleNameLength {
+		name = string(runes[:maxRoleNameLength])
+	}
+	return name, nil
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderRoleName(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		roleName  string
+		realmPath string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "default template",
+			template:  DefaultRoleNameTemplate,
+			roleName:  "admin",
+			realmPath: "gno.land/r/demo/boards",
+			want:      "admin (boards)",
+		},
+		{
+			name:      "legacy template",
+			template:  LegacyRoleNameTemplate,
+			roleName:  "admin",
+			realmPath: "gno.land/r/demo/boards",
+			want:      "admin-gno.land/r/demo/boards",
+		},
+		{
+			name:      "trailing slash in realm path",
+			template:  "{{.RealmShort}}: {{.Role}}",
+			roleName:  "member",
+			realmPath: "gno.land/r/demo/boards/",
+			want:      "boards: member",
+		},
+		{
+			name:     "unknown field",
+			template: "{{.Guild}}",
+			wantErr:  true,
+		},
+		{
+			name:     "syntax error",
+			template: "{{.Role",
+			wantErr:  true,
+		},
+		{
+			name:     "empty output",
+			template: " ",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderRoleName(tt.template, tt.roleName, tt.realmPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderRoleName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RenderRoleName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderRoleName_Truncates(t *testing.T) {
+	got, err := RenderRoleName(LegacyRoleNameTemplate, "admin", "gno.land/r/"+strings.Repeat("x", 120))
+	if err != nil {
+		t.Fatalf("RenderRoleName() error = %v", err)
+	}
+	if len(got) != maxRoleNameLength {
+		t.Errorf("len(RenderRoleName()) = %d, want %d", len(got), maxRoleNameLength)
+	}
+}
+
+func TestParseLegacyRoleName(t *testing.T) {
+	tests := []struct {
+		name          string
+		wantRoleName  string
+		wantRealmPath string
+		wantOK        bool
+	}{
+		{name: "admin-gno.land/r/demo/boards", wantRoleName: "admin", wantRealmPath: "gno.land/r/demo/boards", wantOK: true},
+		{name: "core-dev-gno.land/r/demo/boards", wantRoleName: "core-dev", wantRealmPath: "gno.land/r/demo/boards", wantOK: true},
+		{name: "core-dev-gno.land/r/demo/my-realm", wantRoleName: "core-dev", wantRealmPath: "gno.land/r/demo/my-realm", wantOK: true},
+		{name: "gno.land/r/demo/boards"},
+		{name: "-gno.land/r/demo/boards"},
+		{name: "Regular Role"},
+	}
+
+	for _, tt := range tests {
+		roleName, realmPath, ok := ParseLegacyRoleName(tt.name)
+		if roleName != tt.wantRoleName || realmPath != tt.wantRealmPath || ok != tt.wantOK {
+			t.Errorf("ParseLegacyRoleName(%q) = %q, %q, %v, want %q, %q, %v", tt.name, roleName, realmPath, ok, tt.wantRoleName, tt.wantRealmPath, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package selftest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Step is a single check in a self-test run
+type Step struct {
+	Name     string
+	Requires []string // names of earlier steps that must pass for this step to run
+	Run      func(ctx context.Context) (string, error)
+}
+
+// Result records the outcome of a single step
+type Result struct {
+	Name     string
+	Passed   bool
+	Skipped  bool
+	Detail   string
+	Duration time.Duration
+}
+
+// Report is the outcome of a full self-test run
+type Report struct {
+	Results []Result
+}
+
+// Passed returns true if every step ran and passed
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Run executes steps in order and reports each step's outcome.
+// A failing step does not stop the run; only steps that require it are skipped.
+func Run(ctx context.Context, steps []Step) *Report {
+	report := &Report{}
+	passed := make(map[string]bool)
+
+	for _, step := range steps {
+		if failed := slices.IndexFunc(step.Requires, func(name string) bool { return !passed[name] }); failed >= 0 {
+			report.Results = append(report.Results, Result{
+				Name:    step.Name,
+				Skipped: true,
+				Detail:  fmt.Sprintf("skipped: %s did not pass", step.Requires[failed]),
+			})
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			report.Results = append(report.Results, Result{
+				Name:    step.Name,
+				Skipped: true,
+				Detail:  fmt.Sprintf("skipped: %v", err),
+			})
+			continue
+		}
+
+		start := time.Now()
+		detail, err := step.Run(ctx)
+		result := Result{
+			Name:     step.Name,
+			Passed:   err == nil,
+			Detail:   detail,
+			Duration: time.Since(start),
+		}
+		if err != nil {
+			result.Detail = err.Error()
+		}
+
+		passed[step.Name] = result.Passed
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
@@ -0,0 +1,92 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func passingStep(name string, calls *[]string, requires ...string) Step {
+	return Step{
+		Name:     name,
+		Requires: requires,
+		Run: func(ctx context.Context) (string, error) {
+			*calls = append(*calls, name)
+			return name + " ok", nil
+		},
+	}
+}
+
+func failingStep(name string, calls *[]string, requires ...string) Step {
+	return Step{
+		Name:     name,
+		Requires: requires,
+		Run: func(ctx context.Context) (string, error) {
+			*calls = append(*calls, name)
+			return "", errors.New(name + " failed")
+		},
+	}
+}
+
+func TestRun_AllPass(t *testing.T) {
+	var calls []string
+	report := Run(context.Background(), []Step{
+		passingStep("indexer", &calls),
+		passingStep("events", &calls, "indexer"),
+		passingStep("discord", &calls),
+	})
+
+	if !report.Passed() {
+		t.Fatalf("expected report to pass, got %+v", report.Results)
+	}
+	if len(calls) != 3 {
+		t.Errorf("expected all 3 steps to run, got %v", calls)
+	}
+	for _, result := range report.Results {
+		if result.Detail != result.Name+" ok" {
+			t.Errorf("step %s detail = %q", result.Name, result.Detail)
+		}
+	}
+}
+
+func TestRun_FailureSkipsDependents(t *testing.T) {
+	var calls []string
+	report := Run(context.Background(), []Step{
+		failingStep("indexer", &calls),
+		passingStep("events", &calls, "indexer"),
+		passingStep("discord", &calls),
+	})
+
+	if report.Passed() {
+		t.Fatal("expected report to fail")
+	}
+	if len(calls) != 2 || calls[0] != "indexer" || calls[1] != "discord" {
+		t.Errorf("expected indexer and discord to run, got %v", calls)
+	}
+
+	indexer, events, discord := report.Results[0], report.Results[1], report.Results[2]
+	if indexer.Passed || indexer.Detail != "indexer failed" {
+		t.Errorf("indexer result = %+v", indexer)
+	}
+	if !events.Skipped || events.Passed {
+		t.Errorf("events should be skipped when indexer fails, got %+v", events)
+	}
+	if !discord.Passed {
+		t.Errorf("independent step should still run and pass, got %+v", discord)
+	}
+}
+
+func TestRun_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls []string
+	report := Run(ctx, []Step{passingStep("indexer", &calls)})
+
+	if len(calls) != 0 {
+		t.Errorf("expected no steps to run after cancellation, got %v", calls)
+	}
+	if !report.Results[0].Skipped || report.Passed() {
+		t.Errorf("expected step to be skipped, got %+v", report.Results[0])
+	}
+}
@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+)
+
+const (
+	// DefaultAuditFlushInterval is how often buffered audit entries are written to the audit store
+	DefaultAuditFlushInterval = 5 * time.Second
+
+	// auditFlushBatch flushes early once this many entries are buffered
+	auditFlushBatch = 100
+	// auditMaxPending drops entries past this many unflushed ones, rather than growing or blocking
+	auditMaxPending = 10000
+)
+
+// MaxMemoryAuditEntries bounds the entries kept by a MemoryAuditStore
+const MaxMemoryAuditEntries = 10000
+
+// AuditAction is what a role change did
+type AuditAction string
+
+const (
+	AuditActionGrant  AuditAction = "grant"
+	AuditActionRevoke AuditAction = "revoke"
+)
+
+// AuditEntry records a role grant or revoke performed by the bot
+type AuditEntry struct {
+	Time          time.Time   `json:"time"`
+	GuildID       string      `json:"guild_id"`
+	UserID        string      `json:"user_id"`
+	RoleID        string      `json:"role_id"`
+	Action        AuditAction `json:"action"`
+	RealmPath     string      `json:"realm_path,omitempty"` // Empty for the verified and pending roles
+	RealmRole     string      `json:"realm_role,omitempty"`
+	TxHash        string      `json:"tx_hash,omitempty"` // Empty for changes made by verification sweeps
+	CorrelationID string      `json:"correlation_id,omitempty"`
+}
+
+// AuditLog records role changes. Record must not block the caller.
+type AuditLog interface {
+	Record(entry AuditEntry)
+}
+
+// AuditReader reads recorded role changes
+type AuditReader interface {
+	// UserEntries returns up to limit of a user's most recent entries in a guild, newest first
+	UserEntries(guildID, userID string, limit int) ([]AuditEntry, error)
+}
+
+// AuditStore persists audit entries append-only
+type AuditStore interface {
+	AuditReader
+
+	// Append writes entries. When it fails part-way it returns the entries it did not write
+	// along with the error, so retrying them does not write the others twice.
+	Append(entries []AuditEntry) (unwritten []AuditEntry, err error)
+}
+
+// MemoryAuditStore keeps the most recent MaxMemoryAuditEntries audit entries in memory, for
+// single-instance and development deployments
+type MemoryAuditStore struct {
+	mu      sync.RWMutex
+	entries []AuditEntry
+}
+
+// NewMemoryAuditStore creates an empty in-memory audit store
+func NewMemoryAuditStore() *MemoryAuditStore {
+	return &MemoryAuditStore{}
+}
+
+// Append adds entries to the store, dropping the oldest past MaxMemoryAuditEntries
+func (s *MemoryAuditStore) Append(entries []AuditEntry) ([]AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	if excess := len(s.entries) - MaxMemoryAuditEntries; excess > 0 {
+		s.entries = slices.Delete(s.entries, 0, excess)
+	}
+	return nil, nil
+}
+
+// UserEntries returns up to limit of a user's most recent entries in a guild, newest first
+func (s *MemoryAuditStore) UserEntries(guildID, userID string, limit int) ([]AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return latestUserEntries(s.entries, guildID, userID, limit), nil
+}
+
+// latestUserEntries picks up to limit of a user's entries from oldest-first entries, newest first
+func latestUserEntries(entries []AuditEntry, guildID, userID string, limit int) []AuditEntry {
+	var result []AuditEntry
+	for i := len(entries) - 1; i >= 0 && len(result) < limit; i-- {
+		if entries[i].GuildID == guildID && entries[i].UserID == userID {
+			result = append(result, entries[i])
+		}
+	}
+	return result
+}
+
+// BufferedAuditLog buffers audit entries in memory and writes them to an AuditStore in the
+// background, so recording never waits on storage. Entries are flushed periodically, once a
+// batch fills up, and on Close.
+type BufferedAuditLog struct {
+	store  AuditStore
+	logger core.Logger
+
+	mu      sync.Mutex
+	pending []AuditEntry
+	dropped int
+
+	flush chan struct{}
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewBufferedAuditLog starts a log that flushes to store every interval
+func NewBufferedAuditLog(store AuditStore, interval time.Duration, logger core.Logger) *BufferedAuditLog {
+	if interval <= 0 {
+		interval = DefaultAuditFlushInterval
+	}
+	l := &BufferedAuditLog{
+		store:  store,
+		logger: logger,
+		flush:  make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	l.wg.Add(1)
+	go l.run(interval)
+	return l
+}
+
+// Record buffers an entry, stamping its time if unset
+func (l *BufferedAuditLog) Record(entry AuditEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	l.mu.Lock()
+	if len(l.pending) >= auditMaxPending {
+		l.dropped++
+		l.mu.Unlock()
+		return
+	}
+	l.pending = append(l.pending, entry)
+	full := len(l.pending) >= auditFlushBatch
+	l.mu.Unlock()
+
+	if full {
+		select {
+		case l.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// UserEntries returns up to limit of a user's most recent entries, including ones not flushed yet
+func (l *BufferedAuditLog) UserEntries(guildID, userID string, limit int) ([]AuditEntry, error) {
+	l.mu.Lock()
+	result := latestUserEntries(l.pending, guildID, userID, limit)
+	l.mu.Unlock()
+
+	if len(result) >= limit {
+		return result, nil
+	}
+	stored, err := l.store.UserEntries(guildID, userID, limit-len(result))
+	if err != nil {
+		return nil, err
+	}
+	return append(result, stored...), nil
+}
+
+// Close flushes the buffered entries and stops the background writer
+func (l *BufferedAuditLog) Close() error {
+	close(l.done)
+	l.wg.Wait()
+	return nil
+}
+
+func (l *BufferedAuditLog) run(interval time.Duration) {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.writePending()
+		case <-l.flush:
+			l.writePending()
+		case <-l.done:
+			l.writePending()
+			return
+		}
+	}
+}
+
+// writePending writes the buffered entries; on failure the unwritten ones are kept for the next flush
+func (l *BufferedAuditLog) writePending() {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	dropped := l.dropped
+	l.dropped = 0
+	l.mu.Unlock()
+
+	if dropped > 0 {
+		l.logger.Warn("Dropped audit entries while the audit store was behind", "dropped", dropped)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	if unwritten, err := l.store.Append(batch); err != nil {
+		l.logger.Error("Failed to write audit entries, retrying on the next flush",
+			"entries", len(batch),
+			"unwritten", len(unwritten),
+			"error", err)
+		l.mu.Lock()
+		l.pending = slices.Concat(unwritten, l.pending)
+		if len(l.pending) > auditMaxPending {
+			l.dropped += len(l.pending) - auditMaxPending
+			l.pending = l.pending[len(l.pending)-auditMaxPending:]
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+)
+
+// flakyAuditStore writes the first entry of each append and then fails, until fail is cleared
+type flakyAuditStore struct {
+	MemoryAuditStore
+	mu   sync.Mutex
+	fail bool
+}
+
+func (s *flakyAuditStore) Append(entries []AuditEntry) ([]AuditEntry, error) {
+	s.mu.Lock()
+	fail := s.fail
+	s.mu.Unlock()
+	if fail && len(entries) > 1 {
+		if _, err := s.MemoryAuditStore.Append(entries[:1]); err != nil {
+			return entries, err
+		}
+		return entries[1:], errors.New("store unavailable")
+	}
+	return s.MemoryAuditStore.Append(entries)
+}
+
+func auditEntry(userID, roleID string, action AuditAction, at time.Time) AuditEntry {
+	return AuditEntry{Time: at, GuildID: "guild", UserID: userID, RoleID: roleID, Action: action}
+}
+
+func TestMemoryAuditStore_UserEntries(t *testing.T) {
+	t.Parallel()
+	store := NewMemoryAuditStore()
+	now := time.Now()
+	if _, err := store.Append([]AuditEntry{
+		auditEntry("alice", "role-1", AuditActionGrant, now),
+		auditEntry("bob", "role-1", AuditActionGrant, now.Add(time.Second)),
+		auditEntry("alice", "role-1", AuditActionRevoke, now.Add(2*time.Second)),
+		auditEntry("alice", "role-2", AuditActionGrant, now.Add(3*time.Second)),
+	}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	entries, err := store.UserEntries("guild", "alice", 2)
+	if err != nil {
+		t.Fatalf("UserEntries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].RoleID != "role-2" || entries[1].Action != AuditActionRevoke {
+		t.Errorf("UserEntries() = %+v, want alice's two newest entries, newest first", entries)
+	}
+
+	if entries, _ := store.UserEntries("other-guild", "alice", 10); len(entries) != 0 {
+		t.Errorf("UserEntries() for another guild = %+v, want none", entries)
+	}
+}
+
+func TestBufferedAuditLog(t *testing.T) {
+	t.Parallel()
+	store := NewMemoryAuditStore()
+	auditLog := NewBufferedAuditLog(store, time.Hour, core.NewSlogLogger(core.ParseLogLevel("error")))
+
+	auditLog.Record(AuditEntry{GuildID: "guild", UserID: "alice", RoleID: "role-1", Action: AuditActionGrant})
+	auditLog.Record(AuditEntry{GuildID: "guild", UserID: "alice", RoleID: "role-2", Action: AuditActionGrant})
+
+	if stored, _ := store.UserEntries("guild", "alice", 10); len(stored) != 0 {
+		t.Fatalf("entries were written before a flush: %+v", stored)
+	}
+	entries, err := auditLog.UserEntries("guild", "alice", 10)
+	if err != nil {
+		t.Fatalf("UserEntries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].RoleID != "role-2" || entries[0].Time.IsZero() {
+		t.Errorf("UserEntries() = %+v, want pending entries newest first and timestamped", entries)
+	}
+
+	if err := auditLog.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if stored, _ := store.UserEntries("guild", "alice", 10); len(stored) != 2 {
+		t.Errorf("stored entries after Close() = %+v, want both", stored)
+	}
+}
+
+func TestMemoryAuditStore_KeepsMostRecentEntries(t *testing.T) {
+	t.Parallel()
+	store := NewMemoryAuditStore()
+	now := time.Now()
+	for i := range MaxMemoryAuditEntries + 5 {
+		if _, err := store.Append([]AuditEntry{auditEntry("alice", "role-1", AuditActionGrant, now.Add(time.Duration(i)))}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	entries, _ := store.UserEntries("guild", "alice", MaxMemoryAuditEntries+5)
+	if len(entries) != MaxMemoryAuditEntries {
+		t.Fatalf("kept %d entries, want %d", len(entries), MaxMemoryAuditEntries)
+	}
+	if oldest := entries[len(entries)-1]; !oldest.Time.Equal(now.Add(5)) {
+		t.Errorf("oldest kept entry at %v, want the oldest ones dropped", oldest.Time)
+	}
+}
+
+func TestBufferedAuditLog_RetriesFailedWrites(t *testing.T) {
+	t.Parallel()
+	store := &flakyAuditStore{fail: true}
+	auditLog := NewBufferedAuditLog(store, time.Hour, core.NewSlogLogger(core.ParseLogLevel("error")))
+
+	auditLog.Record(AuditEntry{GuildID: "guild", UserID: "alice", RoleID: "role-1", Action: AuditActionGrant})
+	auditLog.Record(AuditEntry{GuildID: "guild", UserID: "alice", RoleID: "role-2", Action: AuditActionGrant})
+	auditLog.Record(AuditEntry{GuildID: "guild", UserID: "alice", RoleID: "role-3", Action: AuditActionGrant})
+	auditLog.writePending()
+
+	if entries, _ := auditLog.UserEntries("guild", "alice", 10); len(entries) != 3 {
+		t.Fatalf("UserEntries() after a partly failed write = %+v, want all three entries", entries)
+	}
+
+	store.mu.Lock()
+	store.fail = false
+	store.mu.Unlock()
+	if err := auditLog.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Only the entries the store did not write are retried, so none is stored twice
+	stored, _ := store.UserEntries("guild", "alice", 10)
+	if len(stored) != 3 || stored[0].RoleID != "role-3" || stored[1].RoleID != "role-2" || stored[2].RoleID != "role-1" {
+		t.Errorf("stored entries = %+v, want each entry once, newest first", stored)
+	}
+}
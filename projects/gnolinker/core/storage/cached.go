@@ -147,6 +147,8 @@ func (s *CachedConfigStore) copyConfig(config *GuildConfig) *GuildConfig {
 		GuildID:        config.GuildID,
 		AdminRoleID:    config.AdminRoleID,
 		VerifiedRoleID: config.VerifiedRoleID,
+		PendingRoleID:  config.PendingRoleID,
+		Paused:         config.Paused,
 		LastUpdated:    config.LastUpdated,
 	}
 
@@ -158,6 +160,91 @@ func (s *CachedConfigStore) copyConfig(config *GuildConfig) *GuildConfig {
 		}
 	}
 
+	// Deep copy the bot-assigned roles map
+	if config.BotAssignedRoles != nil {
+		copy.BotAssignedRoles = make(map[string][]string, len(config.BotAssignedRoles))
+		for userID, roles := range config.BotAssignedRoles {
+			copy.BotAssignedRoles[userID] = append([]string(nil), roles...)
+		}
+	}
+
+	// Deep copy the role baseline map
+	if config.RoleBaseline != nil {
+		copy.RoleBaseline = make(map[string][]string, len(config.RoleBaseline))
+		for userID, roles := range config.RoleBaseline {
+			copy.RoleBaseline[userID] = append([]string(nil), roles...)
+		}
+	}
+
+	// Deep copy the linked roles map
+	if config.LinkedRoles != nil {
+		copy.LinkedRoles = make(map[string]string, len(config.LinkedRoles))
+		for key, roleID := range config.LinkedRoles {
+			copy.LinkedRoles[key] = roleID
+		}
+	}
+
+	// Deep copy the role channels map
+	if config.RoleChannels != nil {
+		copy.RoleChannels = make(map[string][]string, len(config.RoleChannels))
+		for key, channelIDs := range config.RoleChannels {
+			copy.RoleChannels[key] = append([]string(nil), channelIDs...)
+		}
+	}
+	if config.ManualRoles != nil {
+		copy.ManualRoles = make(map[string]bool, len(config.ManualRoles))
+		for key, manual := range config.ManualRoles {
+			copy.ManualRoles[key] = manual
+		}
+	}
+
+	// Deep copy the link uniqueness maps
+	if config.LinkedAddresses != nil {
+		copy.LinkedAddresses = make(map[string]string, len(config.LinkedAddresses))
+		for userID, address := range config.LinkedAddresses {
+			copy.LinkedAddresses[userID] = address
+		}
+	}
+	if config.LinkConflicts != nil {
+		copy.LinkConflicts = make(map[string]*LinkConflict, len(config.LinkConflicts))
+		for userID, conflict := range config.LinkConflicts {
+			if conflict != nil {
+				conflictCopy := *conflict
+				copy.LinkConflicts[userID] = &conflictCopy
+			}
+		}
+	}
+
+	// Deep copy the departed members map
+	if config.DepartedMembers != nil {
+		copy.DepartedMembers = make(map[string]time.Time, len(config.DepartedMembers))
+		for userID, leftAt := range config.DepartedMembers {
+			copy.DepartedMembers[userID] = leftAt
+		}
+	}
+
+	if config.VerifiedRoleRules != nil {
+		copy.VerifiedRoleRules = append([]VerifiedRoleRule(nil), config.VerifiedRoleRules...)
+	}
+
+	if config.VerificationTiers != nil {
+		copy.VerificationTiers = make(map[string]VerificationTierSettings, len(config.VerificationTiers))
+		for tier, settings := range config.VerificationTiers {
+			copy.VerificationTiers[tier] = settings
+		}
+	}
+
+	// Deep copy the pending claims map
+	if config.PendingClaims != nil {
+		copy.PendingClaims = make(map[string]*PendingClaim, len(config.PendingClaims))
+		for userID, claim := range config.PendingClaims {
+			if claim != nil {
+				claimCopy := *claim
+				copy.PendingClaims[userID] = &claimCopy
+			}
+		}
+	}
+
 	// Deep copy the query states map
 	if config.QueryStates != nil {
 		copy.QueryStates = make(map[string]*GuildQueryState, len(config.QueryStates))
@@ -165,15 +252,20 @@ func (s *CachedConfigStore) copyConfig(config *GuildConfig) *GuildConfig {
 			if v != nil {
 				// Deep copy the query state
 				queryCopy := &GuildQueryState{
-					GuildID:            v.GuildID,
-					QueryID:            v.QueryID,
-					LastProcessedBlock: v.LastProcessedBlock,
-					LastRunTimestamp:   v.LastRunTimestamp,
-					NextRunTimestamp:   v.NextRunTimestamp,
-					Enabled:            v.Enabled,
-					ErrorCount:         v.ErrorCount,
-					LastError:          v.LastError,
-					LastErrorTime:      v.LastErrorTime,
+					GuildID:              v.GuildID,
+					QueryID:              v.QueryID,
+					LastProcessedBlock:   v.LastProcessedBlock,
+					LastProcessedTxIndex: v.LastProcessedTxIndex,
+					LastRunTimestamp:     v.LastRunTimestamp,
+					NextRunTimestamp:     v.NextRunTimestamp,
+					Enabled:              v.Enabled,
+					ErrorCount:           v.ErrorCount,
+					LastError:            v.LastError,
+					LastErrorTime:        v.LastErrorTime,
+					SkippedEvents:        append([]SkippedEvent(nil), v.SkippedEvents...),
+					FailingTxHash:        v.FailingTxHash,
+					FailingTxAttempts:    v.FailingTxAttempts,
+					DeadLetters:          append([]DeadLetter(nil), v.DeadLetters...),
 				}
 
 				// Deep copy the state map if it exists
@@ -40,6 +40,90 @@ func (s *MemoryConfigStore) Get(guildID string) (*GuildConfig, error) {
 		}
 	}
 
+	// Deep copy the bot-assigned roles map
+	if config.BotAssignedRoles != nil {
+		configCopy.BotAssignedRoles = make(map[string][]string, len(config.BotAssignedRoles))
+		for userID, roles := range config.BotAssignedRoles {
+			configCopy.BotAssignedRoles[userID] = append([]string(nil), roles...)
+		}
+	}
+
+	// Deep copy the role baseline map
+	if config.RoleBaseline != nil {
+		configCopy.RoleBaseline = make(map[string][]string, len(config.RoleBaseline))
+		for userID, roles := range config.RoleBaseline {
+			configCopy.RoleBaseline[userID] = append([]string(nil), roles...)
+		}
+	}
+
+	// Deep copy the linked roles map
+	if config.LinkedRoles != nil {
+		configCopy.LinkedRoles = make(map[string]string, len(config.LinkedRoles))
+		for key, roleID := range config.LinkedRoles {
+			configCopy.LinkedRoles[key] = roleID
+		}
+	}
+
+	// Deep copy the role channels map
+	if config.RoleChannels != nil {
+		configCopy.RoleChannels = make(map[string][]string, len(config.RoleChannels))
+		for key, channelIDs := range config.RoleChannels {
+			configCopy.RoleChannels[key] = append([]string(nil), channelIDs...)
+		}
+	}
+	if config.ManualRoles != nil {
+		configCopy.ManualRoles = make(map[string]bool, len(config.ManualRoles))
+		for key, manual := range config.ManualRoles {
+			configCopy.ManualRoles[key] = manual
+		}
+	}
+
+	// Deep copy the link uniqueness maps
+	if config.LinkedAddresses != nil {
+		configCopy.LinkedAddresses = make(map[string]string, len(config.LinkedAddresses))
+		for userID, address := range config.LinkedAddresses {
+			configCopy.LinkedAddresses[userID] = address
+		}
+	}
+	if config.LinkConflicts != nil {
+		configCopy.LinkConflicts = make(map[string]*LinkConflict, len(config.LinkConflicts))
+		for userID, conflict := range config.LinkConflicts {
+			if conflict != nil {
+				conflictCopy := *conflict
+				configCopy.LinkConflicts[userID] = &conflictCopy
+			}
+		}
+	}
+
+	// Deep copy the departed members map
+	if config.DepartedMembers != nil {
+		configCopy.DepartedMembers = make(map[string]time.Time, len(config.DepartedMembers))
+		for userID, leftAt := range config.DepartedMembers {
+			configCopy.DepartedMembers[userID] = leftAt
+		}
+	}
+
+	if config.VerifiedRoleRules != nil {
+		configCopy.VerifiedRoleRules = append([]VerifiedRoleRule(nil), config.VerifiedRoleRules...)
+	}
+
+	if config.VerificationTiers != nil {
+		configCopy.VerificationTiers = make(map[string]VerificationTierSettings, len(config.VerificationTiers))
+		for tier, settings := range config.VerificationTiers {
+			configCopy.VerificationTiers[tier] = settings
+		}
+	}
+
+	if config.PendingClaims != nil {
+		configCopy.PendingClaims = make(map[string]*PendingClaim, len(config.PendingClaims))
+		for userID, claim := range config.PendingClaims {
+			if claim != nil {
+				claimCopy := *claim
+				configCopy.PendingClaims[userID] = &claimCopy
+			}
+		}
+	}
+
 	// Deep copy the query states map
 	if config.QueryStates != nil {
 		configCopy.QueryStates = make(map[string]*GuildQueryState, len(config.QueryStates))
@@ -47,15 +131,20 @@ func (s *MemoryConfigStore) Get(guildID string) (*GuildConfig, error) {
 			if v != nil {
 				// Deep copy the query state
 				queryCopy := &GuildQueryState{
-					GuildID:            v.GuildID,
-					QueryID:            v.QueryID,
-					LastProcessedBlock: v.LastProcessedBlock,
-					LastRunTimestamp:   v.LastRunTimestamp,
-					NextRunTimestamp:   v.NextRunTimestamp,
-					Enabled:            v.Enabled,
-					ErrorCount:         v.ErrorCount,
-					LastError:          v.LastError,
-					LastErrorTime:      v.LastErrorTime,
+					GuildID:              v.GuildID,
+					QueryID:              v.QueryID,
+					LastProcessedBlock:   v.LastProcessedBlock,
+					LastProcessedTxIndex: v.LastProcessedTxIndex,
+					LastRunTimestamp:     v.LastRunTimestamp,
+					NextRunTimestamp:     v.NextRunTimestamp,
+					Enabled:              v.Enabled,
+					ErrorCount:           v.ErrorCount,
+					LastError:            v.LastError,
+					LastErrorTime:        v.LastErrorTime,
+					SkippedEvents:        append([]SkippedEvent(nil), v.SkippedEvents...),
+					FailingTxHash:        v.FailingTxHash,
+					FailingTxAttempts:    v.FailingTxAttempts,
+					DeadLetters:          append([]DeadLetter(nil), v.DeadLetters...),
 				}
 
 				// Deep copy the state map if it exists
@@ -92,6 +181,90 @@ func (s *MemoryConfigStore) Set(guildID string, config *GuildConfig) error {
 		}
 	}
 
+	// Deep copy the bot-assigned roles map
+	if config.BotAssignedRoles != nil {
+		configCopy.BotAssignedRoles = make(map[string][]string, len(config.BotAssignedRoles))
+		for userID, roles := range config.BotAssignedRoles {
+			configCopy.BotAssignedRoles[userID] = append([]string(nil), roles...)
+		}
+	}
+
+	// Deep copy the role baseline map
+	if config.RoleBaseline != nil {
+		configCopy.RoleBaseline = make(map[string][]string, len(config.RoleBaseline))
+		for userID, roles := range config.RoleBaseline {
+			configCopy.RoleBaseline[userID] = append([]string(nil), roles...)
+		}
+	}
+
+	// Deep copy the linked roles map
+	if config.LinkedRoles != nil {
+		configCopy.LinkedRoles = make(map[string]string, len(config.LinkedRoles))
+		for key, roleID := range config.LinkedRoles {
+			configCopy.LinkedRoles[key] = roleID
+		}
+	}
+
+	// Deep copy the role channels map
+	if config.RoleChannels != nil {
+		configCopy.RoleChannels = make(map[string][]string, len(config.RoleChannels))
+		for key, channelIDs := range config.RoleChannels {
+			configCopy.RoleChannels[key] = append([]string(nil), channelIDs...)
+		}
+	}
+	if config.ManualRoles != nil {
+		configCopy.ManualRoles = make(map[string]bool, len(config.ManualRoles))
+		for key, manual := range config.ManualRoles {
+			configCopy.ManualRoles[key] = manual
+		}
+	}
+
+	// Deep copy the link uniqueness maps
+	if config.LinkedAddresses != nil {
+		configCopy.LinkedAddresses = make(map[string]string, len(config.LinkedAddresses))
+		for userID, address := range config.LinkedAddresses {
+			configCopy.LinkedAddresses[userID] = address
+		}
+	}
+	if config.LinkConflicts != nil {
+		configCopy.LinkConflicts = make(map[string]*LinkConflict, len(config.LinkConflicts))
+		for userID, conflict := range config.LinkConflicts {
+			if conflict != nil {
+				conflictCopy := *conflict
+				configCopy.LinkConflicts[userID] = &conflictCopy
+			}
+		}
+	}
+
+	// Deep copy the departed members map
+	if config.DepartedMembers != nil {
+		configCopy.DepartedMembers = make(map[string]time.Time, len(config.DepartedMembers))
+		for userID, leftAt := range config.DepartedMembers {
+			configCopy.DepartedMembers[userID] = leftAt
+		}
+	}
+
+	if config.VerifiedRoleRules != nil {
+		configCopy.VerifiedRoleRules = append([]VerifiedRoleRule(nil), config.VerifiedRoleRules...)
+	}
+
+	if config.VerificationTiers != nil {
+		configCopy.VerificationTiers = make(map[string]VerificationTierSettings, len(config.VerificationTiers))
+		for tier, settings := range config.VerificationTiers {
+			configCopy.VerificationTiers[tier] = settings
+		}
+	}
+
+	if config.PendingClaims != nil {
+		configCopy.PendingClaims = make(map[string]*PendingClaim, len(config.PendingClaims))
+		for userID, claim := range config.PendingClaims {
+			if claim != nil {
+				claimCopy := *claim
+				configCopy.PendingClaims[userID] = &claimCopy
+			}
+		}
+	}
+
 	// Deep copy the query states map
 	if config.QueryStates != nil {
 		configCopy.QueryStates = make(map[string]*GuildQueryState, len(config.QueryStates))
@@ -99,15 +272,20 @@ func (s *MemoryConfigStore) Set(guildID string, config *GuildConfig) error {
 			if v != nil {
 				// Deep copy the query state
 				queryCopy := &GuildQueryState{
-					GuildID:            v.GuildID,
-					QueryID:            v.QueryID,
-					LastProcessedBlock: v.LastProcessedBlock,
-					LastRunTimestamp:   v.LastRunTimestamp,
-					NextRunTimestamp:   v.NextRunTimestamp,
-					Enabled:            v.Enabled,
-					ErrorCount:         v.ErrorCount,
-					LastError:          v.LastError,
-					LastErrorTime:      v.LastErrorTime,
+					GuildID:              v.GuildID,
+					QueryID:              v.QueryID,
+					LastProcessedBlock:   v.LastProcessedBlock,
+					LastProcessedTxIndex: v.LastProcessedTxIndex,
+					LastRunTimestamp:     v.LastRunTimestamp,
+					NextRunTimestamp:     v.NextRunTimestamp,
+					Enabled:              v.Enabled,
+					ErrorCount:           v.ErrorCount,
+					LastError:            v.LastError,
+					LastErrorTime:        v.LastErrorTime,
+					SkippedEvents:        append([]SkippedEvent(nil), v.SkippedEvents...),
+					FailingTxHash:        v.FailingTxHash,
+					FailingTxAttempts:    v.FailingTxAttempts,
+					DeadLetters:          append([]DeadLetter(nil), v.DeadLetters...),
 				}
 
 				// Deep copy the state map if it exists
@@ -170,6 +348,7 @@ func (s *MemoryConfigStore) GetGlobal() (*GlobalConfig, error) {
 		ConfigID:                 s.globalConfig.ConfigID,
 		LastProcessedBlockHeight: s.globalConfig.LastProcessedBlockHeight,
 		LastUpdated:              s.globalConfig.LastUpdated,
+		IssuedClaims:             copyIssuedClaims(s.globalConfig.IssuedClaims),
 	}, nil
 }
 
@@ -187,7 +366,21 @@ func (s *MemoryConfigStore) SetGlobal(config *GlobalConfig) error {
 		ConfigID:                 config.ConfigID,
 		LastProcessedBlockHeight: config.LastProcessedBlockHeight,
 		LastUpdated:              config.LastUpdated,
+		IssuedClaims:             copyIssuedClaims(config.IssuedClaims),
 	}
 
 	return nil
 }
+
+// copyIssuedClaims deep copies issued claims so stored claims cannot be modified in place
+func copyIssuedClaims(claims map[string]*IssuedClaim) map[string]*IssuedClaim {
+	if claims == nil {
+		return nil
+	}
+	copied := make(map[string]*IssuedClaim, len(claims))
+	for key, claim := range claims {
+		claimCopy := *claim
+		copied[key] = &claimCopy
+	}
+	return copied
+}
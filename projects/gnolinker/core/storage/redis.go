@@ -0,0 +1,263 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCompareAndSet sets KEYS[1] to ARGV[1] only if its current value hashes to the ETag in ARGV[2]
+var redisCompareAndSet = redis.NewScript(`local current = redis.call('GET', KEYS[1])
+if current == false or redis.sha1hex(current) ~= ARGV[2] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1])
+return 1`)
+
+// RedisConfigStore provides a Redis implementation of ConfigStore. Configs are stored as JSON
+// under a key prefix; their ETag is the SHA-1 of the stored JSON, checked on write.
+type RedisConfigStore struct {
+	client *redis.Client
+	prefix string // Key prefix, e.g. "configs:"
+}
+
+// RedisConfig holds the configuration for Redis storage
+type RedisConfig struct {
+	URL    string // redis://[user:password@]host:port[/db][?pool_size=n], or rediss:// for TLS
+	Prefix string // Optional key prefix (e.g., "configs:")
+}
+
+// NewRedisConfigStore creates a new Redis config store. Connections are pooled and dialed on
+// first use, so guilds read and write concurrently.
+func NewRedisConfigStore(cfg RedisConfig) (*RedisConfigStore, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("redis URL is required")
+	}
+
+	options, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	client := redis.NewClient(options)
+
+	// Ensure prefix ends with ":" if provided
+	prefix := cfg.Prefix
+	if prefix != "" && prefix[len(prefix)-1] != ':' {
+		prefix += ":"
+	}
+
+	return &RedisConfigStore{
+		client: client,
+		prefix: prefix,
+	}, nil
+}
+
+// Get retrieves a guild configuration by guild ID
+func (s *RedisConfigStore) Get(guildID string) (*GuildConfig, error) {
+	data, err := s.get(s.getKey(guildID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config from redis: %w", err)
+	}
+	if data == nil {
+		return nil, ErrGuildConfigNotFound
+	}
+
+	var config GuildConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	config.ETag = redisETag(data)
+
+	return &config, nil
+}
+
+// Set stores a guild configuration
+func (s *RedisConfigStore) Set(guildID string, config *GuildConfig) error {
+	if config == nil {
+		return errors.New("config cannot be nil")
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := s.set(s.getKey(guildID), data, config.ETag); err != nil {
+		if errors.Is(err, ErrConcurrencyConflict) {
+			return err
+		}
+		return fmt.Errorf("failed to set config in redis: %w", err)
+	}
+
+	// Track the stored version so the same config can be saved again
+	config.ETag = redisETag(data)
+	return nil
+}
+
+// Delete removes a guild configuration
+func (s *RedisConfigStore) Delete(guildID string) error {
+	if err := s.client.Del(context.Background(), s.getKey(guildID)).Err(); err != nil {
+		return fmt.Errorf("failed to delete config from redis: %w", err)
+	}
+	return nil
+}
+
+// GetGlobal retrieves the global configuration
+func (s *RedisConfigStore) GetGlobal() (*GlobalConfig, error) {
+	data, err := s.get(s.getGlobalKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global config from redis: %w", err)
+	}
+	if data == nil {
+		// Return default global config if none exists
+		return &GlobalConfig{
+			ConfigID:                 "global",
+			LastProcessedBlockHeight: 0,
+			LastUpdated:              time.Now(),
+		}, nil
+	}
+
+	var config GlobalConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal global config: %w", err)
+	}
+	config.ETag = redisETag(data)
+
+	return &config, nil
+}
+
+// SetGlobal stores the global configuration
+func (s *RedisConfigStore) SetGlobal(config *GlobalConfig) error {
+	if config == nil {
+		return errors.New("global config cannot be nil")
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal global config: %w", err)
+	}
+
+	if err := s.set(s.getGlobalKey(), data, config.ETag); err != nil {
+		if errors.Is(err, ErrConcurrencyConflict) {
+			return err
+		}
+		return fmt.Errorf("failed to set global config in redis: %w", err)
+	}
+
+	config.ETag = redisETag(data)
+	return nil
+}
+
+// HealthCheck verifies the Redis server is reachable
+func (s *RedisConfigStore) HealthCheck(ctx context.Context) error {
+	if err := s.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis health check failed: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connections to Redis
+func (s *RedisConfigStore) Close() error {
+	return s.client.Close()
+}
+
+// get returns the value of key, or nil if it does not exist
+func (s *RedisConfigStore) get(key string) ([]byte, error) {
+	data, err := s.client.Get(context.Background(), key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// set writes key, only if its current value matches etag when one is given
+func (s *RedisConfigStore) set(key string, data []byte, etag string) error {
+	ctx := context.Background()
+	if etag == "" {
+		return s.client.Set(ctx, key, data, 0).Err()
+	}
+
+	swapped, err := redisCompareAndSet.Run(ctx, s.client, []string{key}, data, etag).Int()
+	if err != nil {
+		return err
+	}
+	if swapped != 1 {
+		return ErrConcurrencyConflict
+	}
+	return nil
+}
+
+// getKey generates the Redis key for a guild ID
+func (s *RedisConfigStore) getKey(guildID string) string {
+	return s.prefix + guildID
+}
+
+// getGlobalKey generates the Redis key for global config
+func (s *RedisConfigStore) getGlobalKey() string {
+	return s.prefix + "global"
+}
+
+// redisETag identifies a stored value; it matches redis.sha1hex in the compare-and-set script
+func redisETag(data []byte) string {
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// RedisAuditStore keeps each user's audit entries in a Redis list, appended with RPUSH
+type RedisAuditStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// AuditStore returns an audit store on the same server and key prefix as the config store
+func (s *RedisConfigStore) AuditStore() *RedisAuditStore {
+	return &RedisAuditStore{client: s.client, prefix: s.prefix + "audit:"}
+}
+
+// Append adds the entries to their users' lists. On failure it returns the entries from the
+// first one not pushed.
+func (s *RedisAuditStore) Append(entries []AuditEntry) ([]AuditEntry, error) {
+	ctx := context.Background()
+	for i, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return entries[i:], fmt.Errorf("failed to marshal audit entry: %w", err)
+		}
+		if err := s.client.RPush(ctx, s.userKey(entry.GuildID, entry.UserID), data).Err(); err != nil {
+			return entries[i:], fmt.Errorf("failed to append audit entry in redis: %w", err)
+		}
+	}
+	return nil, nil
+}
+
+// UserEntries returns up to limit of a user's most recent entries in a guild, newest first
+func (s *RedisAuditStore) UserEntries(guildID, userID string, limit int) ([]AuditEntry, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	items, err := s.client.LRange(context.Background(), s.userKey(guildID, userID), int64(-limit), -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit entries from redis: %w", err)
+	}
+
+	entries := make([]AuditEntry, 0, len(items))
+	for i := len(items) - 1; i >= 0; i-- {
+		data := items[i]
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (s *RedisAuditStore) userKey(guildID, userID string) string {
+	return s.prefix + guildID + ":" + userID
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedisStore(t *testing.T, server *miniredis.Miniredis, userinfo string) *RedisConfigStore {
+	t.Helper()
+	store, err := NewRedisConfigStore(RedisConfig{URL: "redis://" + userinfo + server.Addr() + "/2", Prefix: "configs"})
+	if err != nil {
+		t.Fatalf("NewRedisConfigStore() failed: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestRedisConfigStore_SetAndGet(t *testing.T) {
+	t.Parallel()
+	server := miniredis.RunT(t)
+	store := newTestRedisStore(t, server, "")
+
+	if _, err := store.Get("guild-1"); !errors.Is(err, ErrGuildConfigNotFound) {
+		t.Fatalf("Get() on missing guild error = %v, want ErrGuildConfigNotFound", err)
+	}
+
+	config := NewGuildConfig("guild-1")
+	config.AdminRoleID = "admin-456"
+	config.SetString("test_key", "test_value")
+	if err := store.Set("guild-1", config); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if !server.DB(2).Exists("configs:guild-1") {
+		t.Error("config not stored under the prefixed key in the URL's database")
+	}
+
+	retrieved, err := store.Get("guild-1")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if retrieved.AdminRoleID != "admin-456" || retrieved.GetString("test_key", "") != "test_value" {
+		t.Errorf("Get() = %+v, want the stored config", retrieved)
+	}
+	if retrieved.ETag == "" || retrieved.ETag != config.ETag {
+		t.Errorf("ETag = %q, want the stored version %q", retrieved.ETag, config.ETag)
+	}
+
+	if err := store.Delete("guild-1"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := store.Get("guild-1"); !errors.Is(err, ErrGuildConfigNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrGuildConfigNotFound", err)
+	}
+}
+
+func TestRedisConfigStore_ConcurrencyConflict(t *testing.T) {
+	t.Parallel()
+	server := miniredis.RunT(t)
+	store := newTestRedisStore(t, server, "")
+
+	if err := store.Set("guild-1", NewGuildConfig("guild-1")); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	first, _ := store.Get("guild-1")
+	second, _ := store.Get("guild-1")
+
+	first.AdminRoleID = "first"
+	if err := store.Set("guild-1", first); err != nil {
+		t.Fatalf("Set() with current ETag failed: %v", err)
+	}
+	// The same copy can be saved again, as the query processor does
+	first.AdminRoleID = "first-again"
+	if err := store.Set("guild-1", first); err != nil {
+		t.Fatalf("second Set() of the same config failed: %v", err)
+	}
+
+	second.AdminRoleID = "second"
+	if err := store.Set("guild-1", second); !errors.Is(err, ErrConcurrencyConflict) {
+		t.Errorf("Set() with stale ETag error = %v, want ErrConcurrencyConflict", err)
+	}
+}
+
+func TestRedisConfigStore_Global(t *testing.T) {
+	t.Parallel()
+	server := miniredis.RunT(t)
+	store := newTestRedisStore(t, server, "")
+
+	global, err := store.GetGlobal()
+	if err != nil {
+		t.Fatalf("GetGlobal() failed: %v", err)
+	}
+	if global.ConfigID != "global" || global.LastProcessedBlockHeight != 0 {
+		t.Errorf("GetGlobal() on empty store = %+v, want the default", global)
+	}
+
+	global.LastProcessedBlockHeight = 42
+	if err := store.SetGlobal(global); err != nil {
+		t.Fatalf("SetGlobal() failed: %v", err)
+	}
+	global, err = store.GetGlobal()
+	if err != nil || global.LastProcessedBlockHeight != 42 {
+		t.Errorf("GetGlobal() = %+v, %v, want height 42", global, err)
+	}
+}
+
+func TestRedisConfigStore_Auth(t *testing.T) {
+	t.Parallel()
+	server := miniredis.RunT(t)
+	server.RequireAuth("secret")
+
+	store := newTestRedisStore(t, server, ":secret@")
+	if err := store.HealthCheck(t.Context()); err != nil {
+		t.Fatalf("HealthCheck() failed: %v", err)
+	}
+
+	wrong := newTestRedisStore(t, server, ":wrong@")
+	if err := wrong.HealthCheck(t.Context()); err == nil {
+		t.Error("HealthCheck() with a wrong password should fail")
+	}
+}
+
+func TestRedisConfigStore_ConcurrentGuilds(t *testing.T) {
+	t.Parallel()
+	server := miniredis.RunT(t)
+	store := newTestRedisStore(t, server, "")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			guildID := "guild-" + strconv.Itoa(i)
+			if err := store.Set(guildID, NewGuildConfig(guildID)); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := store.Get(guildID); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent guild access failed: %v", err)
+	}
+}
+
+func TestRedisConfigStore_WithCache(t *testing.T) {
+	t.Parallel()
+	cached, err := NewCachedConfigStore(newTestRedisStore(t, miniredis.RunT(t), ""), CacheConfig{Size: 10, TTL: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := NewGuildConfig("guild-1")
+	for i := range 3 {
+		config.SetString("n", strconv.Itoa(i))
+		if err := cached.Set("guild-1", config); err != nil {
+			t.Fatalf("Set() #%d through the cache failed: %v", i, err)
+		}
+		config, err = cached.Get("guild-1")
+		if err != nil {
+			t.Fatalf("Get() through the cache failed: %v", err)
+		}
+	}
+	if got := config.GetString("n", ""); got != "2" {
+		t.Errorf("n = %q, want 2", got)
+	}
+}
+
+func TestNewRedisConfigStore_InvalidURL(t *testing.T) {
+	t.Parallel()
+	for _, url := range []string{"", "http://localhost:6379", "redis://localhost:6379/db"} {
+		if _, err := NewRedisConfigStore(RedisConfig{URL: url}); err == nil {
+			t.Errorf("NewRedisConfigStore(%q) should fail", url)
+		}
+	}
+}
+
+func TestRedisAuditStore_UserEntries(t *testing.T) {
+	t.Parallel()
+	audit := newTestRedisStore(t, miniredis.RunT(t), "").AuditStore()
+	now := time.Now()
+	if _, err := audit.Append([]AuditEntry{
+		auditEntry("alice", "role-1", AuditActionGrant, now),
+		auditEntry("bob", "role-1", AuditActionGrant, now.Add(time.Second)),
+		auditEntry("alice", "role-1", AuditActionRevoke, now.Add(2*time.Second)),
+	}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	entries, err := audit.UserEntries("guild", "alice", 10)
+	if err != nil {
+		t.Fatalf("UserEntries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != AuditActionRevoke || entries[1].Action != AuditActionGrant {
+		t.Errorf("UserEntries() = %+v, want alice's entries newest first", entries)
+	}
+}
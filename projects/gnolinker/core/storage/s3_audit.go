@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3AuditStore writes audit entries as immutable S3 objects, one per user per flushed batch,
+// under {prefix}audit/{guildID}/{userID}/ so a user's history is listed without scanning others
+type S3AuditStore struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// AuditStore returns an audit store in the same bucket and prefix as the config store
+func (s *S3ConfigStore) AuditStore() *S3AuditStore {
+	return &S3AuditStore{client: s.client, bucket: s.bucket, prefix: s.prefix + "audit/"}
+}
+
+// Append writes the entries, grouped into one object per guild and user. On failure it returns
+// the entries of the objects not written.
+func (s *S3AuditStore) Append(entries []AuditEntry) ([]AuditEntry, error) {
+	groups := make(map[string][]AuditEntry)
+	var order []string
+	for _, entry := range entries {
+		userPrefix := s.userPrefix(entry.GuildID, entry.UserID)
+		if _, ok := groups[userPrefix]; !ok {
+			order = append(order, userPrefix)
+		}
+		groups[userPrefix] = append(groups[userPrefix], entry)
+	}
+
+	ctx := context.Background()
+	for i, userPrefix := range order {
+		batch := groups[userPrefix]
+		data, err := json.Marshal(batch)
+		if err != nil {
+			return s.unwritten(groups, order[i:]), fmt.Errorf("failed to marshal audit entries: %w", err)
+		}
+
+		// Zero-padded nanoseconds sort object keys chronologically; the random suffix keeps
+		// batches flushed at the same time by different instances apart
+		key := fmt.Sprintf("%s%020d-%s.json", userPrefix, batch[0].Time.UnixNano(), rand.Text()[:8])
+		if _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(s.bucket),
+			Key:         aws.String(key),
+			Body:        bytes.NewReader(data),
+			ContentType: aws.String("application/json"),
+			IfNoneMatch: aws.String("*"), // Append-only: never overwrite an existing batch
+		}); err != nil {
+			return s.unwritten(groups, order[i:]), fmt.Errorf("failed to put audit entries to S3: %w", err)
+		}
+	}
+	return nil, nil
+}
+
+// unwritten collects the entries of the given user groups
+func (s *S3AuditStore) unwritten(groups map[string][]AuditEntry, userPrefixes []string) []AuditEntry {
+	var entries []AuditEntry
+	for _, userPrefix := range userPrefixes {
+		entries = append(entries, groups[userPrefix]...)
+	}
+	return entries
+}
+
+// UserEntries returns up to limit of a user's most recent entries in a guild, newest first
+func (s *S3AuditStore) UserEntries(guildID, userID string, limit int) ([]AuditEntry, error) {
+	ctx := context.Background()
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.userPrefix(guildID, userID)),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list audit entries in S3: %w", err)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+	slices.Sort(keys)
+
+	var result []AuditEntry
+	for i := len(keys) - 1; i >= 0 && len(result) < limit; i-- {
+		batch, err := s.readBatch(ctx, keys[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, latestUserEntries(batch, guildID, userID, limit-len(result))...)
+	}
+	return result, nil
+}
+
+func (s *S3AuditStore) readBatch(ctx context.Context, key string) ([]AuditEntry, error) {
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit entries from S3: %w", err)
+	}
+	defer result.Body.Close()
+
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit entries: %w", err)
+	}
+
+	var batch []AuditEntry
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal audit entries: %w", err)
+	}
+	return batch, nil
+}
+
+func (s *S3AuditStore) userPrefix(guildID, userID string) string {
+	return s.prefix + strings.Join([]string{guildID, userID}, "/") + "/"
+}
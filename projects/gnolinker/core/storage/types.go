@@ -2,7 +2,10 @@ package storage
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,8 +13,168 @@ import (
 var (
 	ErrConcurrencyConflict = errors.New("concurrent modification detected")
 	ErrGuildConfigNotFound = errors.New("guild config not found")
+	ErrInvalidRealmPath    = errors.New("realm path must start with gno.land/r/")
 )
 
+// RoleSyncPolicy controls how verification treats managed roles the bot did not assign itself
+type RoleSyncPolicy string
+
+const (
+	// RoleSyncPolicyStrict always reconciles managed roles to on-chain truth
+	RoleSyncPolicyStrict RoleSyncPolicy = "strict"
+	// RoleSyncPolicyPermissive only removes managed roles that the bot previously assigned
+	RoleSyncPolicyPermissive RoleSyncPolicy = "permissive"
+)
+
+// SettingRoleSyncPolicy is the guild setting key overriding the default role sync policy
+const SettingRoleSyncPolicy = "role_sync_policy"
+
+// RoleSyncOrder controls the order in which a user's role additions and removals are applied
+type RoleSyncOrder string
+
+const (
+	// RoleSyncOrderInterleaved applies each role change as its mapping is checked
+	RoleSyncOrderInterleaved RoleSyncOrder = "interleaved"
+	// RoleSyncOrderAddsFirst applies all role additions before any removal
+	RoleSyncOrderAddsFirst RoleSyncOrder = "adds-first"
+	// RoleSyncOrderRemovesFirst applies all role removals before any addition
+	RoleSyncOrderRemovesFirst RoleSyncOrder = "removes-first"
+)
+
+// SettingRoleSyncOrder is the guild setting key overriding the default role sync order
+const SettingRoleSyncOrder = "role_sync_order"
+
+// LinkUniqueness controls which identity links a guild treats as conflicting
+type LinkUniqueness string
+
+const (
+	// LinkUniquenessOff accepts every link
+	LinkUniquenessOff LinkUniqueness = "off"
+	// LinkUniquenessAddress allows one Discord account per gno address
+	LinkUniquenessAddress LinkUniqueness = "one-discord-per-address"
+	// LinkUniquenessDiscord allows one gno address per Discord account
+	LinkUniquenessDiscord LinkUniqueness = "one-address-per-discord"
+	// LinkUniquenessBoth enforces both directions
+	LinkUniquenessBoth LinkUniqueness = "both"
+)
+
+// SettingLinkUniqueness is the guild setting key overriding the default link uniqueness
+const SettingLinkUniqueness = "link_uniqueness"
+
+// LinkConflictAction controls what happens to the account whose link caused a conflict
+type LinkConflictAction string
+
+const (
+	// LinkConflictActionWarn logs the conflict and grants roles as usual
+	LinkConflictActionWarn LinkConflictAction = "warn"
+	// LinkConflictActionHold withholds roles from the account until an admin approves the link
+	LinkConflictActionHold LinkConflictAction = "hold"
+)
+
+// SettingLinkConflictAction is the guild setting key overriding the default link conflict action
+const SettingLinkConflictAction = "link_conflict_action"
+
+// SettingCleanupDepartedMembers is the guild setting key overriding whether per-member state
+// is deleted when a member leaves
+const SettingCleanupDepartedMembers = "cleanup_departed_members"
+
+// SettingNotifyRoleChanges is the guild setting key overriding whether members are sent a DM when
+// an on-chain event changes their roles
+const SettingNotifyRoleChanges = "notify_role_changes"
+
+// SettingRoleNameTemplate is the guild setting key overriding the default Discord role name template
+const SettingRoleNameTemplate = "role_name_template"
+
+// Guild setting keys styling the Discord roles the bot creates (see RoleStyle)
+const (
+	SettingRoleColor       = "role_color"
+	SettingRoleHoist       = "role_hoist"
+	SettingRoleMentionable = "role_mentionable"
+)
+
+// RoleStyle is how the bot styles the Discord roles it creates for a guild
+type RoleStyle struct {
+	// Color is the color of created roles, nil for the bot's default color for the kind of role
+	Color *int
+	// Hoist displays members with a created role separately in the member list
+	Hoist bool
+	// Mentionable lets everyone mention a created role
+	Mentionable bool
+}
+
+// ParseRoleColor parses a hex role color such as #5865F2, returning false if it is invalid
+func ParseRoleColor(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "#")
+	if len(value) != 6 {
+		return 0, false
+	}
+	color, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int(color), true
+}
+
+// FormatRoleColor formats a role color as hex, e.g. #5865F2
+func FormatRoleColor(color int) string {
+	return fmt.Sprintf("#%06X", color)
+}
+
+// ParseRoleSyncPolicy parses a role sync policy name, returning false if it is unknown
+func ParseRoleSyncPolicy(value string) (RoleSyncPolicy, bool) {
+	switch RoleSyncPolicy(strings.ToLower(strings.TrimSpace(value))) {
+	case RoleSyncPolicyStrict:
+		return RoleSyncPolicyStrict, true
+	case RoleSyncPolicyPermissive:
+		return RoleSyncPolicyPermissive, true
+	default:
+		return "", false
+	}
+}
+
+// ParseRoleSyncOrder parses a role sync order name, returning false if it is unknown
+func ParseRoleSyncOrder(value string) (RoleSyncOrder, bool) {
+	switch RoleSyncOrder(strings.ToLower(strings.TrimSpace(value))) {
+	case RoleSyncOrderInterleaved:
+		return RoleSyncOrderInterleaved, true
+	case RoleSyncOrderAddsFirst:
+		return RoleSyncOrderAddsFirst, true
+	case RoleSyncOrderRemovesFirst:
+		return RoleSyncOrderRemovesFirst, true
+	default:
+		return "", false
+	}
+}
+
+// ParseLinkUniqueness parses a link uniqueness name, returning false if it is unknown
+func ParseLinkUniqueness(value string) (LinkUniqueness, bool) {
+	switch LinkUniqueness(strings.ToLower(strings.TrimSpace(value))) {
+	case LinkUniquenessOff:
+		return LinkUniquenessOff, true
+	case LinkUniquenessAddress:
+		return LinkUniquenessAddress, true
+	case LinkUniquenessDiscord:
+		return LinkUniquenessDiscord, true
+	case LinkUniquenessBoth:
+		return LinkUniquenessBoth, true
+	default:
+		return "", false
+	}
+}
+
+// ParseLinkConflictAction parses a link conflict action name, returning false if it is unknown
+func ParseLinkConflictAction(value string) (LinkConflictAction, bool) {
+	switch LinkConflictAction(strings.ToLower(strings.TrimSpace(value))) {
+	case LinkConflictActionWarn:
+		return LinkConflictActionWarn, true
+	case LinkConflictActionHold:
+		return LinkConflictActionHold, true
+	default:
+		return "", false
+	}
+}
+
 // GuildQueryState tracks per-guild progress for each query
 type GuildQueryState struct {
 	GuildID              string         `json:"guild_id"`
@@ -26,6 +189,45 @@ type GuildQueryState struct {
 	ErrorCount           int            `json:"error_count"`
 	LastError            string         `json:"last_error,omitempty"`
 	LastErrorTime        time.Time      `json:"last_error_time,omitempty"`
+	// SkippedEvents lists recent events that failed validation and were not acted on
+	SkippedEvents []SkippedEvent `json:"skipped_events,omitempty"`
+	// FailingTxHash is the transaction whose event handling is being retried, FailingTxAttempts
+	// how many times it has failed so far
+	FailingTxHash     string `json:"failing_tx_hash,omitempty"`
+	FailingTxAttempts int    `json:"failing_tx_attempts,omitempty"`
+	// DeadLetters lists recent transactions whose event handling kept failing and was given up on
+	DeadLetters []DeadLetter `json:"dead_letters,omitempty"`
+}
+
+// MaxSkippedEvents bounds the skipped events kept per query state
+const MaxSkippedEvents = 50
+
+// SkippedEvent records an on-chain event the bot did not act on because it was malformed.
+// Processing moves past the transaction so one bad event cannot stall a guild, and the
+// record keeps it available for inspection and replay.
+type SkippedEvent struct {
+	TxHash      string    `json:"tx_hash"`
+	BlockHeight int64     `json:"block_height"`
+	TxIndex     int64     `json:"tx_index"`
+	EventType   string    `json:"event_type"`
+	Reason      string    `json:"reason"`
+	SkippedAt   time.Time `json:"skipped_at"`
+}
+
+// MaxDeadLetters bounds the dead-lettered transactions kept per query state
+const MaxDeadLetters = 50
+
+// DeadLetter records a transaction the bot gave up on after its event handler failed on every
+// attempt. Processing moves past it so it cannot wedge the guild's queue, and the record keeps
+// it available for manual inspection.
+type DeadLetter struct {
+	TxHash         string    `json:"tx_hash"`
+	BlockHeight    int64     `json:"block_height"`
+	TxIndex        int64     `json:"tx_index"`
+	EventType      string    `json:"event_type"`
+	Attempts       int       `json:"attempts"`
+	LastError      string    `json:"last_error"`
+	DeadLetteredAt time.Time `json:"dead_lettered_at"`
 }
 
 // GuildConfig represents the configuration for a Discord guild
@@ -33,26 +235,154 @@ type GuildConfig struct {
 	GuildID         string                      `json:"guild_id"`
 	AdminRoleID     string                      `json:"admin_role_id,omitempty"`
 	VerifiedRoleID  string                      `json:"verified_role_id,omitempty"`
+	PendingRoleID   string                      `json:"pending_role_id,omitempty"` // Optional role held while a link claim is pending
+	Paused          bool                        `json:"paused,omitempty"`          // Skips queries, verification and role changes for the guild
 	Settings        map[string]string           `json:"settings,omitempty"`
 	QueryStates     map[string]*GuildQueryState `json:"query_states,omitempty"`
 	MonitoredRealms []string                    `json:"monitored_realms,omitempty"` // Cached list of realm paths with linked roles
-	LastUpdated     time.Time                   `json:"last_updated"`
+	// MonitoredRealmsDiscoveredAt records when MonitoredRealms was last discovered from the linked roles
+	MonitoredRealmsDiscoveredAt time.Time `json:"monitored_realms_discovered_at,omitzero"`
+	// RegisteredRealms are realm paths an admin registered for monitoring, kept across rediscovery
+	// even when no role is linked in them yet
+	RegisteredRealms []string `json:"registered_realms,omitempty"`
+	// BotAssignedRoles tracks the role IDs the bot granted to each user ID
+	BotAssignedRoles map[string][]string `json:"bot_assigned_roles,omitempty"`
+	// PendingClaims tracks the outstanding link claim for each user ID
+	PendingClaims map[string]*PendingClaim `json:"pending_claims,omitempty"`
+	// LinkedRoles maps realm roles (see LinkedRoleKey) to the Discord role ID created for them,
+	// so that roles are still found after renames or template changes
+	LinkedRoles map[string]string `json:"linked_roles,omitempty"`
+	// RoleChannels scopes realm roles (see LinkedRoleKey) to channel or category IDs
+	// the linked Discord role is granted access to through permission overwrites
+	RoleChannels map[string][]string `json:"role_channels,omitempty"`
+	// ManualRoles marks realm roles (see LinkedRoleKey) excluded from auto-sync: they are still
+	// listed, but sweeps and verifications never grant or remove the linked Discord role
+	ManualRoles map[string]bool `json:"manual_roles,omitempty"`
+	// LinkedAddresses records the gno address each user ID was last seen linking
+	LinkedAddresses map[string]string `json:"linked_addresses,omitempty"`
+	// LinkConflicts tracks the unresolved link conflict for each user ID
+	LinkConflicts map[string]*LinkConflict `json:"link_conflicts,omitempty"`
+	// DepartedMembers records when each user ID left the guild; they are skipped until they rejoin
+	DepartedMembers map[string]time.Time `json:"departed_members,omitempty"`
+	// RoleBaseline holds the managed role IDs each user ID held when the baseline was imported.
+	// Baseline roles are only removed from unlinked members until the user's first verification pass.
+	RoleBaseline map[string][]string `json:"role_baseline,omitempty"`
+	// VerificationTiers overrides the interval and batch size of verification tiers by tier name
+	VerificationTiers map[string]VerificationTierSettings `json:"verification_tiers,omitempty"`
+	// VerifiedRoleRules grant further roles to linked members, each applied independently of the
+	// verified role
+	VerifiedRoleRules []VerifiedRoleRule `json:"verified_role_rules,omitempty"`
+	LastUpdated       time.Time          `json:"last_updated"`
 
 	// ETag is used for optimistic concurrency control
 	// Not serialized to JSON - managed by storage layer
 	ETag string `json:"-"`
 }
 
+// IssuedClaim is a signed claim kept under its idempotency key (see GlobalConfig.IssuedClaims)
+type IssuedClaim struct {
+	Type      string    `json:"type"`
+	Data      string    `json:"data"`
+	Signature string    `json:"signature"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IsExpired returns true once the claim is no longer handed out again
+func (ic *IssuedClaim) IsExpired() bool {
+	return time.Now().After(ic.ExpiresAt)
+}
+
+// PendingClaim tracks a claim issued to a user that has not yet been completed on-chain
+type PendingClaim struct {
+	Type     string `json:"type"`
+	Address  string `json:"address,omitempty"`
+	ClaimURL string `json:"claim_url"`
+	// Signature identifies the claim among the issued claims (see GlobalConfig.IssuedClaims)
+	Signature string    `json:"signature,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// RoleGranted records that the guild's pending role was granted for this claim
+	RoleGranted bool `json:"role_granted,omitempty"`
+}
+
+// IsExpired returns true if the claim can no longer be submitted
+func (pc *PendingClaim) IsExpired() bool {
+	return !pc.ExpiresAt.IsZero() && time.Now().After(pc.ExpiresAt)
+}
+
+// LinkConflictKind names the uniqueness rule a link broke
+type LinkConflictKind string
+
+const (
+	// LinkConflictSharedAddress means another Discord account already linked the address
+	LinkConflictSharedAddress LinkConflictKind = "shared-address"
+	// LinkConflictMultipleAddresses means the Discord account already linked a different address
+	LinkConflictMultipleAddresses LinkConflictKind = "multiple-addresses"
+)
+
+// LinkConflict records a link that broke the guild's link uniqueness rules
+type LinkConflict struct {
+	Kind    LinkConflictKind `json:"kind"`
+	Address string           `json:"address"`
+	// ConflictingUserID is the other account linked to Address, for shared-address conflicts
+	ConflictingUserID string `json:"conflicting_user_id,omitempty"`
+	// ConflictingAddress is the address previously linked by the account, for multiple-addresses conflicts
+	ConflictingAddress string    `json:"conflicting_address,omitempty"`
+	DetectedAt         time.Time `json:"detected_at"`
+	// Held means roles are withheld from the account until an admin approves the link
+	Held bool `json:"held,omitempty"`
+}
+
 // GlobalConfig represents global bot state
 type GlobalConfig struct {
 	ConfigID                 string    `json:"config_id"`
 	LastProcessedBlockHeight int64     `json:"last_processed_block_height"`
 	LastUpdated              time.Time `json:"last_updated"`
+	// IssuedClaims keeps signed claims by idempotency key until they expire, so repeated claim
+	// requests get the claim already issued
+	IssuedClaims map[string]*IssuedClaim `json:"issued_claims,omitempty"`
 
 	// ETag is used for optimistic concurrency control
 	ETag string `json:"-"`
 }
 
+// GetIssuedClaim retrieves the unexpired claim issued under an idempotency key
+func (g *GlobalConfig) GetIssuedClaim(key string) (*IssuedClaim, bool) {
+	claim, exists := g.IssuedClaims[key]
+	if !exists || claim.IsExpired() {
+		return nil, false
+	}
+	return claim, true
+}
+
+// SetIssuedClaim records the claim issued under an idempotency key, dropping expired claims
+func (g *GlobalConfig) SetIssuedClaim(key string, claim *IssuedClaim) {
+	if g.IssuedClaims == nil {
+		g.IssuedClaims = make(map[string]*IssuedClaim)
+	}
+	for existing, issued := range g.IssuedClaims {
+		if issued.IsExpired() {
+			delete(g.IssuedClaims, existing)
+		}
+	}
+	g.IssuedClaims[key] = claim
+	g.LastUpdated = time.Now()
+}
+
+// DeleteIssuedClaim stops handing out the issued claim with the given signature, returning true
+// if one was removed
+func (g *GlobalConfig) DeleteIssuedClaim(signature string) bool {
+	for key, issued := range g.IssuedClaims {
+		if issued.Signature == signature {
+			delete(g.IssuedClaims, key)
+			g.LastUpdated = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
 // ConfigStore defines the interface for guild configuration storage
 type ConfigStore interface {
 	Get(guildID string) (*GuildConfig, error)
@@ -163,6 +493,446 @@ func (c *GuildConfig) HasVerifiedRole() bool {
 	return c.VerifiedRoleID != ""
 }
 
+// HasPendingRole returns true if a pending verification role is configured
+func (c *GuildConfig) HasPendingRole() bool {
+	return c.PendingRoleID != ""
+}
+
+// GetRoleSyncPolicy returns the guild's role sync policy, falling back to defaultPolicy
+func (c *GuildConfig) GetRoleSyncPolicy(defaultPolicy RoleSyncPolicy) RoleSyncPolicy {
+	if policy, ok := ParseRoleSyncPolicy(c.GetString(SettingRoleSyncPolicy, "")); ok {
+		return policy
+	}
+	return defaultPolicy
+}
+
+// GetRoleSyncOrder returns the guild's role sync order, falling back to defaultOrder
+func (c *GuildConfig) GetRoleSyncOrder(defaultOrder RoleSyncOrder) RoleSyncOrder {
+	if order, ok := ParseRoleSyncOrder(c.GetString(SettingRoleSyncOrder, "")); ok {
+		return order
+	}
+	return defaultOrder
+}
+
+// GetLinkUniqueness returns the guild's link uniqueness, falling back to defaultUniqueness
+func (c *GuildConfig) GetLinkUniqueness(defaultUniqueness LinkUniqueness) LinkUniqueness {
+	if uniqueness, ok := ParseLinkUniqueness(c.GetString(SettingLinkUniqueness, "")); ok {
+		return uniqueness
+	}
+	return defaultUniqueness
+}
+
+// GetLinkConflictAction returns the guild's link conflict action, falling back to defaultAction
+func (c *GuildConfig) GetLinkConflictAction(defaultAction LinkConflictAction) LinkConflictAction {
+	if action, ok := ParseLinkConflictAction(c.GetString(SettingLinkConflictAction, "")); ok {
+		return action
+	}
+	return defaultAction
+}
+
+// GetRoleStyle returns how the bot styles the Discord roles it creates for the guild
+func (c *GuildConfig) GetRoleStyle() RoleStyle {
+	style := RoleStyle{
+		Hoist:       c.GetBool(SettingRoleHoist, false),
+		Mentionable: c.GetBool(SettingRoleMentionable, false),
+	}
+	if color, ok := ParseRoleColor(c.GetString(SettingRoleColor, "")); ok {
+		style.Color = &color
+	}
+	return style
+}
+
+// SetRoleStyle sets how the bot styles the Discord roles it creates; a nil color restores the
+// default colors
+func (c *GuildConfig) SetRoleStyle(style RoleStyle) {
+	if style.Color != nil {
+		c.SetString(SettingRoleColor, FormatRoleColor(*style.Color))
+	} else if _, exists := c.Settings[SettingRoleColor]; exists {
+		delete(c.Settings, SettingRoleColor)
+		c.LastUpdated = time.Now()
+	}
+	c.SetBool(SettingRoleHoist, style.Hoist)
+	c.SetBool(SettingRoleMentionable, style.Mentionable)
+}
+
+// Bot-assigned role tracking methods
+
+// RecordBotAssignedRole records that the bot granted roleID to userID
+func (c *GuildConfig) RecordBotAssignedRole(userID, roleID string) {
+	if c.IsBotAssignedRole(userID, roleID) {
+		return
+	}
+	if c.BotAssignedRoles == nil {
+		c.BotAssignedRoles = make(map[string][]string)
+	}
+	c.BotAssignedRoles[userID] = append(c.BotAssignedRoles[userID], roleID)
+	c.LastUpdated = time.Now()
+}
+
+// ClearBotAssignedRole forgets that the bot granted roleID to userID
+func (c *GuildConfig) ClearBotAssignedRole(userID, roleID string) {
+	roles, exists := c.BotAssignedRoles[userID]
+	if !exists {
+		return
+	}
+
+	roles = slices.DeleteFunc(slices.Clone(roles), func(id string) bool { return id == roleID })
+	if len(roles) == 0 {
+		delete(c.BotAssignedRoles, userID)
+	} else {
+		c.BotAssignedRoles[userID] = roles
+	}
+	c.LastUpdated = time.Now()
+}
+
+// IsBotAssignedRole returns true if the bot granted roleID to userID
+func (c *GuildConfig) IsBotAssignedRole(userID, roleID string) bool {
+	return slices.Contains(c.BotAssignedRoles[userID], roleID)
+}
+
+// Role baseline methods
+
+// SetRoleBaseline replaces the imported role baseline with the managed role IDs held by each user ID
+func (c *GuildConfig) SetRoleBaseline(baseline map[string][]string) {
+	c.RoleBaseline = make(map[string][]string, len(baseline))
+	for userID, roles := range baseline {
+		if len(roles) > 0 {
+			c.RoleBaseline[userID] = append([]string(nil), roles...)
+		}
+	}
+	c.LastUpdated = time.Now()
+}
+
+// IsBaselineRole returns true if userID held roleID when the baseline was imported
+func (c *GuildConfig) IsBaselineRole(userID, roleID string) bool {
+	return slices.Contains(c.RoleBaseline[userID], roleID)
+}
+
+// ClearRoleBaseline drops userID's baseline, returning false if they had none
+func (c *GuildConfig) ClearRoleBaseline(userID string) bool {
+	if _, exists := c.RoleBaseline[userID]; !exists {
+		return false
+	}
+	delete(c.RoleBaseline, userID)
+	c.LastUpdated = time.Now()
+	return true
+}
+
+// Linked role tracking methods
+
+// LinkedRoleKey identifies a realm role in GuildConfig.LinkedRoles
+func LinkedRoleKey(realmPath, roleName string) string {
+	return realmPath + "|" + roleName
+}
+
+// SetLinkedRole records the Discord role ID linked to a realm role
+func (c *GuildConfig) SetLinkedRole(realmPath, roleName, roleID string) {
+	if c.LinkedRoles == nil {
+		c.LinkedRoles = make(map[string]string)
+	}
+	c.LinkedRoles[LinkedRoleKey(realmPath, roleName)] = roleID
+	c.LastUpdated = time.Now()
+}
+
+// GetLinkedRole returns the Discord role ID recorded for a realm role
+func (c *GuildConfig) GetLinkedRole(realmPath, roleName string) (string, bool) {
+	roleID, exists := c.LinkedRoles[LinkedRoleKey(realmPath, roleName)]
+	return roleID, exists
+}
+
+// DeleteLinkedRole forgets the Discord role recorded for a realm role
+func (c *GuildConfig) DeleteLinkedRole(realmPath, roleName string) {
+	key := LinkedRoleKey(realmPath, roleName)
+	if _, exists := c.LinkedRoles[key]; !exists {
+		return
+	}
+	delete(c.LinkedRoles, key)
+	c.LastUpdated = time.Now()
+}
+
+// AddRoleChannel scopes a realm role to a channel or category, returning false if it already was
+func (c *GuildConfig) AddRoleChannel(realmPath, roleName, channelID string) bool {
+	key := LinkedRoleKey(realmPath, roleName)
+	if slices.Contains(c.RoleChannels[key], channelID) {
+		return false
+	}
+	if c.RoleChannels == nil {
+		c.RoleChannels = make(map[string][]string)
+	}
+	c.RoleChannels[key] = append(c.RoleChannels[key], channelID)
+	c.LastUpdated = time.Now()
+	return true
+}
+
+// GetRoleChannels returns the channels and categories a realm role is scoped to
+func (c *GuildConfig) GetRoleChannels(realmPath, roleName string) []string {
+	return c.RoleChannels[LinkedRoleKey(realmPath, roleName)]
+}
+
+// ClearRoleChannels removes all channel scoping from a realm role
+func (c *GuildConfig) ClearRoleChannels(realmPath, roleName string) {
+	key := LinkedRoleKey(realmPath, roleName)
+	if _, exists := c.RoleChannels[key]; !exists {
+		return
+	}
+	delete(c.RoleChannels, key)
+	c.LastUpdated = time.Now()
+}
+
+// SetMonitoredRealms replaces the cached monitored realms with a freshly discovered set.
+// Registered realms are always kept.
+func (c *GuildConfig) SetMonitoredRealms(realmPaths []string) {
+	realms := slices.Concat(realmPaths, c.RegisteredRealms)
+	slices.Sort(realms)
+	c.MonitoredRealms = slices.Compact(realms)
+	c.MonitoredRealmsDiscoveredAt = time.Now()
+	c.LastUpdated = c.MonitoredRealmsDiscoveredAt
+}
+
+// ValidateRealmPath checks that a realm path names a realm, i.e. starts with gno.land/r/
+func ValidateRealmPath(realmPath string) error {
+	if name, ok := strings.CutPrefix(realmPath, "gno.land/r/"); !ok || name == "" {
+		return ErrInvalidRealmPath
+	}
+	return nil
+}
+
+// RegisterRealm monitors a realm registered by an admin, returning false if it was already registered
+func (c *GuildConfig) RegisterRealm(realmPath string) bool {
+	if slices.Contains(c.RegisteredRealms, realmPath) {
+		return false
+	}
+	c.RegisteredRealms = slices.Sorted(slices.Values(append(slices.Clone(c.RegisteredRealms), realmPath)))
+	if !slices.Contains(c.MonitoredRealms, realmPath) {
+		c.MonitoredRealms = slices.Sorted(slices.Values(append(slices.Clone(c.MonitoredRealms), realmPath)))
+	}
+	c.LastUpdated = time.Now()
+	return true
+}
+
+// UnregisterRealm stops monitoring a realm, returning false if it was not monitored. A realm with
+// linked roles is monitored again when the realms are next discovered.
+func (c *GuildConfig) UnregisterRealm(realmPath string) bool {
+	if !slices.Contains(c.MonitoredRealms, realmPath) && !slices.Contains(c.RegisteredRealms, realmPath) {
+		return false
+	}
+	isRealm := func(p string) bool { return p == realmPath }
+	c.RegisteredRealms = slices.DeleteFunc(slices.Clone(c.RegisteredRealms), isRealm)
+	c.MonitoredRealms = slices.DeleteFunc(slices.Clone(c.MonitoredRealms), isRealm)
+	c.LastUpdated = time.Now()
+	return true
+}
+
+// SetRoleAutoSync includes or excludes a realm role from auto-sync, returning false if unchanged
+func (c *GuildConfig) SetRoleAutoSync(realmPath, roleName string, autoSync bool) bool {
+	if c.IsRoleAutoSynced(realmPath, roleName) == autoSync {
+		return false
+	}
+	key := LinkedRoleKey(realmPath, roleName)
+	if autoSync {
+		delete(c.ManualRoles, key)
+	} else {
+		if c.ManualRoles == nil {
+			c.ManualRoles = make(map[string]bool)
+		}
+		c.ManualRoles[key] = true
+	}
+	c.LastUpdated = time.Now()
+	return true
+}
+
+// IsRoleAutoSynced reports whether sweeps and verifications manage a realm role's Discord role
+func (c *GuildConfig) IsRoleAutoSynced(realmPath, roleName string) bool {
+	return !c.ManualRoles[LinkedRoleKey(realmPath, roleName)]
+}
+
+// LinkedRealms returns the realm paths that have recorded linked roles
+func (c *GuildConfig) LinkedRealms() []string {
+	var realms []string
+	for key := range c.LinkedRoles {
+		realmPath, _, _ := strings.Cut(key, "|")
+		if !slices.Contains(realms, realmPath) {
+			realms = append(realms, realmPath)
+		}
+	}
+	slices.Sort(realms)
+	return realms
+}
+
+// Link uniqueness methods
+
+// DetectLinkConflict checks a link of address by userID against the links already recorded,
+// returning nil if uniqueness allows it. Relinking the same address is never a conflict.
+func (c *GuildConfig) DetectLinkConflict(userID, address string, uniqueness LinkUniqueness) *LinkConflict {
+	if uniqueness == LinkUniquenessAddress || uniqueness == LinkUniquenessBoth {
+		var others []string
+		for otherID, otherAddress := range c.LinkedAddresses {
+			if otherID != userID && otherAddress == address {
+				others = append(others, otherID)
+			}
+		}
+		if len(others) > 0 {
+			slices.Sort(others)
+			return &LinkConflict{
+				Kind:              LinkConflictSharedAddress,
+				Address:           address,
+				ConflictingUserID: others[0],
+				DetectedAt:        time.Now(),
+			}
+		}
+	}
+
+	if uniqueness == LinkUniquenessDiscord || uniqueness == LinkUniquenessBoth {
+		if previous, exists := c.LinkedAddresses[userID]; exists && previous != address {
+			return &LinkConflict{
+				Kind:               LinkConflictMultipleAddresses,
+				Address:            address,
+				ConflictingAddress: previous,
+				DetectedAt:         time.Now(),
+			}
+		}
+	}
+	return nil
+}
+
+// RecordLinkedAddress records the address userID linked
+func (c *GuildConfig) RecordLinkedAddress(userID, address string) {
+	if c.LinkedAddresses == nil {
+		c.LinkedAddresses = make(map[string]string)
+	}
+	c.LinkedAddresses[userID] = address
+	c.LastUpdated = time.Now()
+}
+
+// ClearLinkedAddress forgets the address userID linked along with any conflict it caused
+func (c *GuildConfig) ClearLinkedAddress(userID string) {
+	_, linked := c.LinkedAddresses[userID]
+	_, conflicted := c.LinkConflicts[userID]
+	if !linked && !conflicted {
+		return
+	}
+	delete(c.LinkedAddresses, userID)
+	delete(c.LinkConflicts, userID)
+	c.LastUpdated = time.Now()
+}
+
+// SetLinkConflict records the conflict caused by userID's link, replacing any previous one
+func (c *GuildConfig) SetLinkConflict(userID string, conflict *LinkConflict) {
+	if c.LinkConflicts == nil {
+		c.LinkConflicts = make(map[string]*LinkConflict)
+	}
+	c.LinkConflicts[userID] = conflict
+	c.LastUpdated = time.Now()
+}
+
+// DeleteLinkConflict resolves userID's link conflict, returning true if one existed
+func (c *GuildConfig) DeleteLinkConflict(userID string) bool {
+	if _, exists := c.LinkConflicts[userID]; !exists {
+		return false
+	}
+	delete(c.LinkConflicts, userID)
+	c.LastUpdated = time.Now()
+	return true
+}
+
+// IsLinkHeld returns true if roles are withheld from userID pending admin review of its link
+func (c *GuildConfig) IsLinkHeld(userID string) bool {
+	conflict, exists := c.LinkConflicts[userID]
+	return exists && conflict != nil && conflict.Held
+}
+
+// Departed member methods
+
+// MarkMemberDeparted records that userID left the guild
+func (c *GuildConfig) MarkMemberDeparted(userID string) {
+	if c.DepartedMembers == nil {
+		c.DepartedMembers = make(map[string]time.Time)
+	}
+	c.DepartedMembers[userID] = time.Now()
+	c.LastUpdated = time.Now()
+}
+
+// MarkMemberRejoined clears userID's departure, returning true if it had left
+func (c *GuildConfig) MarkMemberRejoined(userID string) bool {
+	if _, exists := c.DepartedMembers[userID]; !exists {
+		return false
+	}
+	delete(c.DepartedMembers, userID)
+	c.LastUpdated = time.Now()
+	return true
+}
+
+// IsMemberDeparted returns true if userID left the guild and has not rejoined
+func (c *GuildConfig) IsMemberDeparted(userID string) bool {
+	_, exists := c.DepartedMembers[userID]
+	return exists
+}
+
+// ForgetMember deletes the per-member state kept for userID: bot-assigned roles, baseline
+// roles, pending claims, linked addresses and link conflicts
+func (c *GuildConfig) ForgetMember(userID string) {
+	delete(c.BotAssignedRoles, userID)
+	delete(c.RoleBaseline, userID)
+	delete(c.PendingClaims, userID)
+	delete(c.LinkedAddresses, userID)
+	delete(c.LinkConflicts, userID)
+	c.LastUpdated = time.Now()
+}
+
+// Pending claim management methods
+
+// SetPendingClaim records the pending claim for a user, replacing any previous one
+func (c *GuildConfig) SetPendingClaim(userID string, claim *PendingClaim) {
+	if c.PendingClaims == nil {
+		c.PendingClaims = make(map[string]*PendingClaim)
+	}
+
+	// Drop expired claims from other users so the map doesn't grow unbounded.
+	// Claims holding a pending role are kept until the role is cleaned up.
+	for id, existing := range c.PendingClaims {
+		if existing == nil || (existing.IsExpired() && !existing.RoleGranted) {
+			delete(c.PendingClaims, id)
+		}
+	}
+
+	c.PendingClaims[userID] = claim
+	c.LastUpdated = time.Now()
+}
+
+// GetPendingClaim retrieves the unexpired pending claim for a user
+func (c *GuildConfig) GetPendingClaim(userID string) (*PendingClaim, bool) {
+	claim, exists := c.PendingClaims[userID]
+	if !exists || claim == nil || claim.IsExpired() {
+		return nil, false
+	}
+	return claim, true
+}
+
+// DeletePendingClaim removes a user's pending claim, returning true if one existed
+func (c *GuildConfig) DeletePendingClaim(userID string) bool {
+	if _, exists := c.PendingClaims[userID]; !exists {
+		return false
+	}
+	delete(c.PendingClaims, userID)
+	c.LastUpdated = time.Now()
+	return true
+}
+
+// TakeExpiredPendingClaims removes and returns all expired pending claims keyed by user ID
+func (c *GuildConfig) TakeExpiredPendingClaims() map[string]*PendingClaim {
+	expired := make(map[string]*PendingClaim)
+	for userID, claim := range c.PendingClaims {
+		if claim == nil || claim.IsExpired() {
+			expired[userID] = claim
+			delete(c.PendingClaims, userID)
+		}
+	}
+	if len(expired) > 0 {
+		c.LastUpdated = time.Now()
+	}
+	return expired
+}
+
 // Query state management methods
 
 // GetQueryState retrieves a query state by ID
@@ -281,6 +1051,64 @@ func (gqs *GuildQueryState) ClearErrors() {
 	gqs.LastErrorTime = time.Time{}
 }
 
+// RecordSkippedEvent records a malformed event, keeping the most recent MaxSkippedEvents.
+// Recording the same transaction event again only refreshes its reason.
+func (gqs *GuildQueryState) RecordSkippedEvent(txHash string, blockHeight, txIndex int64, eventType string, reason error) {
+	for i, skipped := range gqs.SkippedEvents {
+		if skipped.TxHash == txHash && skipped.EventType == eventType {
+			gqs.SkippedEvents[i].Reason = reason.Error()
+			return
+		}
+	}
+
+	gqs.SkippedEvents = append(gqs.SkippedEvents, SkippedEvent{
+		TxHash:      txHash,
+		BlockHeight: blockHeight,
+		TxIndex:     txIndex,
+		EventType:   eventType,
+		Reason:      reason.Error(),
+		SkippedAt:   time.Now(),
+	})
+	if excess := len(gqs.SkippedEvents) - MaxSkippedEvents; excess > 0 {
+		gqs.SkippedEvents = slices.Delete(gqs.SkippedEvents, 0, excess)
+	}
+}
+
+// RecordTxFailure counts a failed attempt at handling a transaction's events and returns the
+// number of attempts that failed in a row
+func (gqs *GuildQueryState) RecordTxFailure(txHash string) int {
+	if gqs.FailingTxHash != txHash {
+		gqs.FailingTxHash = txHash
+		gqs.FailingTxAttempts = 0
+	}
+	gqs.FailingTxAttempts++
+	return gqs.FailingTxAttempts
+}
+
+// ClearTxFailure forgets the failed attempts at a transaction once it is handled or dead-lettered
+func (gqs *GuildQueryState) ClearTxFailure(txHash string) {
+	if gqs.FailingTxHash == txHash {
+		gqs.FailingTxHash = ""
+		gqs.FailingTxAttempts = 0
+	}
+}
+
+// RecordDeadLetter records a transaction given up on, keeping the most recent MaxDeadLetters
+func (gqs *GuildQueryState) RecordDeadLetter(txHash string, blockHeight, txIndex int64, eventType string, attempts int, lastErr error) {
+	gqs.DeadLetters = append(gqs.DeadLetters, DeadLetter{
+		TxHash:         txHash,
+		BlockHeight:    blockHeight,
+		TxIndex:        txIndex,
+		EventType:      eventType,
+		Attempts:       attempts,
+		LastError:      lastErr.Error(),
+		DeadLetteredAt: time.Now(),
+	})
+	if excess := len(gqs.DeadLetters) - MaxDeadLetters; excess > 0 {
+		gqs.DeadLetters = slices.Delete(gqs.DeadLetters, 0, excess)
+	}
+}
+
 // IsReady returns true if the query is ready to run
 func (gqs *GuildQueryState) IsReady() bool {
 	return gqs.Enabled &&
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"encoding/json"
+	"slices"
 	"testing"
 	"time"
 )
@@ -337,6 +338,219 @@ func TestGuildConfig_HasVerifiedRole(t *testing.T) {
 	}
 }
 
+func TestGuildConfig_GetRoleSyncPolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		setting string
+		want    RoleSyncPolicy
+	}{
+		{name: "unset uses default", setting: "", want: RoleSyncPolicyStrict},
+		{name: "permissive override", setting: "permissive", want: RoleSyncPolicyPermissive},
+		{name: "case insensitive", setting: "STRICT", want: RoleSyncPolicyStrict},
+		{name: "invalid uses default", setting: "lenient", want: RoleSyncPolicyStrict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			config := NewGuildConfig("12345")
+			if tt.setting != "" {
+				config.SetString(SettingRoleSyncPolicy, tt.setting)
+			}
+
+			if got := config.GetRoleSyncPolicy(RoleSyncPolicyStrict); got != tt.want {
+				t.Errorf("GetRoleSyncPolicy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuildConfig_DetectLinkConflict(t *testing.T) {
+	t.Parallel()
+	config := NewGuildConfig("12345")
+	config.RecordLinkedAddress("user1", "g1first")
+
+	tests := []struct {
+		name       string
+		uniqueness LinkUniqueness
+		userID     string
+		address    string
+		want       LinkConflictKind
+	}{
+		{name: "off accepts shared address", uniqueness: LinkUniquenessOff, userID: "user2", address: "g1first"},
+		{name: "shared address", uniqueness: LinkUniquenessAddress, userID: "user2", address: "g1first", want: LinkConflictSharedAddress},
+		{name: "address rule accepts second address", uniqueness: LinkUniquenessAddress, userID: "user1", address: "g1second"},
+		{name: "multiple addresses", uniqueness: LinkUniquenessDiscord, userID: "user1", address: "g1second", want: LinkConflictMultipleAddresses},
+		{name: "discord rule accepts shared address", uniqueness: LinkUniquenessDiscord, userID: "user2", address: "g1first"},
+		{name: "both catches shared address", uniqueness: LinkUniquenessBoth, userID: "user2", address: "g1first", want: LinkConflictSharedAddress},
+		{name: "both catches multiple addresses", uniqueness: LinkUniquenessBoth, userID: "user1", address: "g1second", want: LinkConflictMultipleAddresses},
+		{name: "relinking the same address", uniqueness: LinkUniquenessBoth, userID: "user1", address: "g1first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conflict := config.DetectLinkConflict(tt.userID, tt.address, tt.uniqueness)
+			if tt.want == "" {
+				if conflict != nil {
+					t.Fatalf("DetectLinkConflict() = %+v, want no conflict", conflict)
+				}
+				return
+			}
+			if conflict == nil || conflict.Kind != tt.want {
+				t.Fatalf("DetectLinkConflict() = %+v, want kind %q", conflict, tt.want)
+			}
+			switch tt.want {
+			case LinkConflictSharedAddress:
+				if conflict.ConflictingUserID != "user1" {
+					t.Errorf("ConflictingUserID = %q, want user1", conflict.ConflictingUserID)
+				}
+			case LinkConflictMultipleAddresses:
+				if conflict.ConflictingAddress != "g1first" {
+					t.Errorf("ConflictingAddress = %q, want g1first", conflict.ConflictingAddress)
+				}
+			}
+		})
+	}
+}
+
+func TestGuildConfig_ClearLinkedAddress(t *testing.T) {
+	t.Parallel()
+	config := NewGuildConfig("12345")
+	config.RecordLinkedAddress("user1", "g1first")
+	config.SetLinkConflict("user1", &LinkConflict{Kind: LinkConflictMultipleAddresses, Address: "g1first", Held: true})
+
+	if !config.IsLinkHeld("user1") {
+		t.Fatal("IsLinkHeld() should be true for a held conflict")
+	}
+
+	config.ClearLinkedAddress("user1")
+	if config.IsLinkHeld("user1") {
+		t.Error("unlinking should resolve the held conflict")
+	}
+	if conflict := config.DetectLinkConflict("user2", "g1first", LinkUniquenessBoth); conflict != nil {
+		t.Errorf("unlinked address should be free to link, got %+v", conflict)
+	}
+}
+
+func TestGuildConfig_BotAssignedRoles(t *testing.T) {
+	t.Parallel()
+	config := NewGuildConfig("12345")
+
+	if config.IsBotAssignedRole("user1", "role1") {
+		t.Error("IsBotAssignedRole() should be false before recording")
+	}
+
+	config.RecordBotAssignedRole("user1", "role1")
+	config.RecordBotAssignedRole("user1", "role1")
+	config.RecordBotAssignedRole("user1", "role2")
+
+	if got := len(config.BotAssignedRoles["user1"]); got != 2 {
+		t.Errorf("expected 2 recorded roles without duplicates, got %d", got)
+	}
+	if !config.IsBotAssignedRole("user1", "role1") {
+		t.Error("IsBotAssignedRole() should be true after recording")
+	}
+
+	config.ClearBotAssignedRole("user1", "role1")
+	if config.IsBotAssignedRole("user1", "role1") {
+		t.Error("IsBotAssignedRole() should be false after clearing")
+	}
+
+	config.ClearBotAssignedRole("user1", "role2")
+	if _, exists := config.BotAssignedRoles["user1"]; exists {
+		t.Error("user entry should be removed once no roles remain")
+	}
+}
+
+func TestGuildConfig_RoleBaseline(t *testing.T) {
+	t.Parallel()
+	config := NewGuildConfig("12345")
+
+	config.SetRoleBaseline(map[string][]string{
+		"user1": {"role1", "role2"},
+		"user2": {},
+	})
+
+	if !config.IsBaselineRole("user1", "role2") {
+		t.Error("IsBaselineRole() should be true for an imported role")
+	}
+	if config.IsBaselineRole("user1", "role3") {
+		t.Error("IsBaselineRole() should be false for a role the user did not hold")
+	}
+	if _, exists := config.RoleBaseline["user2"]; exists {
+		t.Error("users without managed roles should not get a baseline entry")
+	}
+
+	if !config.ClearRoleBaseline("user1") {
+		t.Error("ClearRoleBaseline() should report the cleared baseline")
+	}
+	if config.IsBaselineRole("user1", "role1") || config.ClearRoleBaseline("user1") {
+		t.Error("baseline should be gone after clearing")
+	}
+}
+
+func TestGuildConfig_RoleAutoSync(t *testing.T) {
+	t.Parallel()
+	config := NewGuildConfig("12345")
+
+	if !config.IsRoleAutoSynced("gno.land/r/demo/x", "member") {
+		t.Error("roles should be auto-synced by default")
+	}
+	if !config.SetRoleAutoSync("gno.land/r/demo/x", "member", false) {
+		t.Error("SetRoleAutoSync() should report excluding the role")
+	}
+	if config.IsRoleAutoSynced("gno.land/r/demo/x", "member") || !config.IsRoleAutoSynced("gno.land/r/demo/x", "admin") {
+		t.Error("only the excluded role should stop being auto-synced")
+	}
+	if config.SetRoleAutoSync("gno.land/r/demo/x", "member", false) {
+		t.Error("SetRoleAutoSync() should report no change when already excluded")
+	}
+
+	if !config.SetRoleAutoSync("gno.land/r/demo/x", "member", true) || len(config.ManualRoles) != 0 {
+		t.Errorf("including the role again should drop it from ManualRoles, got %v", config.ManualRoles)
+	}
+}
+
+func TestGuildConfig_RegisteredRealms(t *testing.T) {
+	t.Parallel()
+	config := NewGuildConfig("12345")
+
+	if !config.RegisterRealm("gno.land/r/demo/events") || config.RegisterRealm("gno.land/r/demo/events") {
+		t.Error("RegisterRealm() should report registering the realm once")
+	}
+	if !slices.Equal(config.MonitoredRealms, []string{"gno.land/r/demo/events"}) {
+		t.Errorf("MonitoredRealms = %v, want the registered realm", config.MonitoredRealms)
+	}
+
+	config.SetMonitoredRealms([]string{"gno.land/r/demo/boards"})
+	if !slices.Equal(config.MonitoredRealms, []string{"gno.land/r/demo/boards", "gno.land/r/demo/events"}) {
+		t.Errorf("MonitoredRealms = %v, want rediscovery to keep the registered realm", config.MonitoredRealms)
+	}
+
+	if !config.UnregisterRealm("gno.land/r/demo/events") || config.UnregisterRealm("gno.land/r/demo/events") {
+		t.Error("UnregisterRealm() should report removing the realm once")
+	}
+	if len(config.RegisteredRealms) != 0 || !slices.Equal(config.MonitoredRealms, []string{"gno.land/r/demo/boards"}) {
+		t.Errorf("RegisteredRealms = %v, MonitoredRealms = %v", config.RegisteredRealms, config.MonitoredRealms)
+	}
+}
+
+func TestValidateRealmPath(t *testing.T) {
+	t.Parallel()
+	for realmPath, valid := range map[string]bool{
+		"gno.land/r/demo/events": true,
+		"gno.land/p/demo/ufmt":   false,
+		"gno.land/r/":            false,
+		"r/demo/events":          false,
+	} {
+		if err := ValidateRealmPath(realmPath); (err == nil) != valid {
+			t.Errorf("ValidateRealmPath(%q) = %v, want valid = %v", realmPath, err, valid)
+		}
+	}
+}
+
 func TestGuildConfig_JSONSerialization(t *testing.T) {
 	t.Parallel()
 	// Create a config with various data types
@@ -477,3 +691,76 @@ func TestGuildConfig_IntParsing(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateVerificationTier(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		tier     string
+		settings VerificationTierSettings
+		wantErr  bool
+	}{
+		{name: "valid", tier: VerificationTierMedium, settings: VerificationTierSettings{Interval: 10 * time.Minute, BatchSize: 20}},
+		{name: "unlimited batch", tier: VerificationTierHigh, settings: VerificationTierSettings{Interval: time.Minute}},
+		{name: "unknown tier", tier: "urgent", settings: VerificationTierSettings{Interval: time.Minute}, wantErr: true},
+		{name: "interval too short", tier: VerificationTierHigh, settings: VerificationTierSettings{Interval: time.Second}, wantErr: true},
+		{name: "interval too long", tier: VerificationTierLow, settings: VerificationTierSettings{Interval: 48 * time.Hour, BatchSize: 10}, wantErr: true},
+		{name: "batch too large", tier: VerificationTierMedium, settings: VerificationTierSettings{Interval: time.Minute, BatchSize: MaxVerificationBatchSize + 1}, wantErr: true},
+		{name: "low tier needs a batch", tier: VerificationTierLow, settings: VerificationTierSettings{Interval: time.Hour}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := ValidateVerificationTier(tt.tier, tt.settings); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateVerificationTier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGuildConfig_VerificationTiers(t *testing.T) {
+	t.Parallel()
+	config := NewGuildConfig("12345")
+	if got := config.GetVerificationTier(VerificationTierLow); got != DefaultVerificationTiers[VerificationTierLow] {
+		t.Errorf("GetVerificationTier() = %+v, want the default", got)
+	}
+
+	slow := VerificationTierSettings{Interval: time.Hour, BatchSize: 5}
+	if !config.SetVerificationTier(VerificationTierLow, slow) {
+		t.Error("SetVerificationTier() should report a change")
+	}
+	if config.SetVerificationTier(VerificationTierLow, slow) {
+		t.Error("SetVerificationTier() with the same settings should report no change")
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded GuildConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got := decoded.GetVerificationTier(VerificationTierLow); got != slow {
+		t.Errorf("persisted tier = %+v, want %+v", got, slow)
+	}
+	if got := decoded.GetVerificationTier(VerificationTierHigh); got != DefaultVerificationTiers[VerificationTierHigh] {
+		t.Errorf("unset tier = %+v, want the default", got)
+	}
+}
+
+func TestParseRoleColor(t *testing.T) {
+	for value, want := range map[string]int{"#5865F2": 0x5865F2, "5865f2": 0x5865F2, " #000000 ": 0, "#FFFFFF": 0xFFFFFF} {
+		if color, ok := ParseRoleColor(value); !ok || color != want {
+			t.Errorf("ParseRoleColor(%q) = %d, %v, want %d", value, color, ok, want)
+		}
+	}
+	for _, value := range []string{"", "#FFF", "#12345G", "0x5865F2", "#-12345", "blue"} {
+		if _, ok := ParseRoleColor(value); ok {
+			t.Errorf("ParseRoleColor(%q) accepted an invalid color", value)
+		}
+	}
+	if got := FormatRoleColor(0x5865F2); got != "#5865F2" {
+		t.Errorf("FormatRoleColor() = %q, want #5865F2", got)
+	}
+}
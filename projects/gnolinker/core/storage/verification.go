@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Verification tiers, from online members verified often to inactive ones verified incrementally
+const (
+	VerificationTierHigh   = "high"
+	VerificationTierMedium = "medium"
+	VerificationTierLow    = "low"
+)
+
+// Bounds on a guild's verification tier settings
+const (
+	MinVerificationInterval  = 30 * time.Second
+	MaxVerificationInterval  = 24 * time.Hour
+	MaxVerificationBatchSize = 1000
+)
+
+// VerificationTierSettings controls how often a verification tier runs and how many members it
+// verifies per run
+type VerificationTierSettings struct {
+	Interval time.Duration `json:"interval"`
+	// BatchSize caps the members verified per run; 0 verifies every member of the tier.
+	// The low tier always works through its members in batches.
+	BatchSize int `json:"batch_size"`
+}
+
+// DefaultVerificationTiers are the tier settings of guilds that do not override them
+var DefaultVerificationTiers = map[string]VerificationTierSettings{
+	VerificationTierHigh:   {Interval: 1 * time.Minute},
+	VerificationTierMedium: {Interval: 5 * time.Minute},
+	VerificationTierLow:    {Interval: 30 * time.Minute, BatchSize: 10},
+}
+
+// ValidateVerificationTier checks that tier settings name a known tier and are within bounds
+func ValidateVerificationTier(tier string, settings VerificationTierSettings) error {
+	if _, ok := DefaultVerificationTiers[tier]; !ok {
+		return fmt.Errorf("unknown verification tier %q: must be high, medium or low", tier)
+	}
+	if settings.Interval < MinVerificationInterval || settings.Interval > MaxVerificationInterval {
+		return fmt.Errorf("interval must be between %s and %s", MinVerificationInterval, MaxVerificationInterval)
+	}
+	if settings.BatchSize < 0 || settings.BatchSize > MaxVerificationBatchSize {
+		return fmt.Errorf("batch size must be between 0 and %d", MaxVerificationBatchSize)
+	}
+	if tier == VerificationTierLow && settings.BatchSize == 0 {
+		return errors.New("the low tier verifies members incrementally, so its batch size must be at least 1")
+	}
+	return nil
+}
+
+// GetVerificationTier returns the guild's settings for a verification tier, or the defaults
+func (c *GuildConfig) GetVerificationTier(tier string) VerificationTierSettings {
+	if settings, ok := c.VerificationTiers[tier]; ok && settings.Interval > 0 {
+		return settings
+	}
+	return DefaultVerificationTiers[tier]
+}
+
+// SetVerificationTier overrides the guild's settings for a verification tier, returning false if unchanged
+func (c *GuildConfig) SetVerificationTier(tier string, settings VerificationTierSettings) bool {
+	if c.GetVerificationTier(tier) == settings {
+		return false
+	}
+	if c.VerificationTiers == nil {
+		c.VerificationTiers = make(map[string]VerificationTierSettings)
+	}
+	c.VerificationTiers[tier] = settings
+	c.LastUpdated = time.Now()
+	return true
+}
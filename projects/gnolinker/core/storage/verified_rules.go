@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"slices"
+	"time"
+)
+
+// MaxVerifiedRoleRules bounds the verified-role rules of a guild, as each conditional rule costs a
+// realm query per verified member
+const MaxVerifiedRoleRules = 10
+
+var (
+	ErrTooManyVerifiedRoleRules = fmt.Errorf("a guild can have at most %d verified-role rules", MaxVerifiedRoleRules)
+	ErrVerifiedRoleRule         = errors.New("the verified role is always granted to linked members")
+)
+
+// VerifiedRoleRule grants a Discord role to linked members, optionally only to those whose linked
+// address satisfies a realm function. The guild's verified role is the implicit unconditional rule.
+type VerifiedRoleRule struct {
+	RoleID string `json:"role_id"`
+	// RealmPath and Function name a realm function called with the member's linked address, which
+	// must return a bool; the role is held while it returns true. Both are empty for an
+	// unconditional rule.
+	RealmPath string `json:"realm_path,omitempty"`
+	Function  string `json:"function,omitempty"`
+}
+
+// IsConditional reports whether the rule depends on a realm function
+func (r VerifiedRoleRule) IsConditional() bool {
+	return r.Function != ""
+}
+
+// Predicate describes the realm function call of a conditional rule
+func (r VerifiedRoleRule) Predicate() string {
+	if !r.IsConditional() {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s(address)", r.RealmPath, r.Function)
+}
+
+// ValidateVerifiedRoleRule checks that a rule names a role and, if conditional, an exported
+// function of a realm
+func ValidateVerifiedRoleRule(rule VerifiedRoleRule) error {
+	if rule.RoleID == "" {
+		return errors.New("a verified-role rule needs a role")
+	}
+	if rule.RealmPath == "" && rule.Function == "" {
+		return nil
+	}
+	if err := ValidateRealmPath(rule.RealmPath); err != nil {
+		return err
+	}
+	if !token.IsIdentifier(rule.Function) || !token.IsExported(rule.Function) {
+		return fmt.Errorf("%q is not an exported function name", rule.Function)
+	}
+	return nil
+}
+
+// GetVerifiedRoleRules returns every verified-role rule of the guild, starting with the
+// unconditional rule of its verified role
+func (c *GuildConfig) GetVerifiedRoleRules() []VerifiedRoleRule {
+	var rules []VerifiedRoleRule
+	if c.VerifiedRoleID != "" {
+		rules = append(rules, VerifiedRoleRule{RoleID: c.VerifiedRoleID})
+	}
+	return append(rules, c.VerifiedRoleRules...)
+}
+
+// SetVerifiedRoleRule adds a verified-role rule or replaces the rule of the same role, returning
+// false if unchanged
+func (c *GuildConfig) SetVerifiedRoleRule(rule VerifiedRoleRule) (bool, error) {
+	if rule.RoleID == c.VerifiedRoleID {
+		return false, ErrVerifiedRoleRule
+	}
+	i := slices.IndexFunc(c.VerifiedRoleRules, func(r VerifiedRoleRule) bool { return r.RoleID == rule.RoleID })
+	if i >= 0 && c.VerifiedRoleRules[i] == rule {
+		return false, nil
+	}
+	if i < 0 && len(c.VerifiedRoleRules) >= MaxVerifiedRoleRules {
+		return false, ErrTooManyVerifiedRoleRules
+	}
+	rules := slices.Clone(c.VerifiedRoleRules)
+	if i >= 0 {
+		rules[i] = rule
+	} else {
+		rules = append(rules, rule)
+	}
+	c.VerifiedRoleRules = rules
+	c.LastUpdated = time.Now()
+	return true, nil
+}
@@ -0,0 +1,30 @@
+package workflows
+
+import (
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+)
+
+// claimKey derives the idempotency key of a claim from its type and the values it binds
+func claimKey(claimType core.ClaimType, values ...string) string {
+	return string(claimType) + ":" + strings.Join(values, ",")
+}
+
+// issueClaim returns the unexpired claim already issued under key, or signs a new one and records it
+func issueClaim(config WorkflowConfig, key string, generate func() (*core.Claim, error)) (*core.Claim, error) {
+	if config.Claims != nil {
+		if claim, ok := config.Claims.IssuedClaim(key); ok {
+			return claim, nil
+		}
+	}
+
+	claim, err := generate()
+	if err != nil {
+		return nil, err
+	}
+	if config.Claims != nil {
+		config.Claims.RecordIssuedClaim(key, claim)
+	}
+	return claim, nil
+}
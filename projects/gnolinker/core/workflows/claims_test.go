@@ -0,0 +1,154 @@
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/contracts"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"golang.org/x/crypto/nacl/sign"
+)
+
+// newStatusRPC serves an RPC whose latest block height goes up on every status request
+func newStatusRPC(t *testing.T) (*contracts.GnoClient, *atomic.Int64) {
+	t.Helper()
+	var height atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"sync_info":{"latest_block_height":"%d"}}}`, request.ID, height.Add(1))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := contracts.NewGnoClient(contracts.ClientConfig{RPCURL: server.URL, UserContract: "r/linker/user/v0"})
+	if err != nil {
+		t.Fatalf("NewGnoClient() failed: %v", err)
+	}
+	return client, &height
+}
+
+func TestUserLinkingWorkflow_GenerateClaimIsIdempotent(t *testing.T) {
+	client, height := newStatusRPC(t)
+	_, privKey, _ := sign.GenerateKey(nil)
+	store := storage.NewMemoryConfigStore()
+	configManager := config.NewConfigManager(store, &config.StorageConfig{ClaimTTL: 30 * time.Minute}, nil, core.NewSlogLogger(core.ParseLogLevel("error")))
+	workflow := NewUserLinkingWorkflow(client, WorkflowConfig{SigningKey: privKey, Claims: configManager})
+
+	address := "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"
+	first, err := workflow.GenerateClaim("123456789012345678", address)
+	if err != nil {
+		t.Fatalf("GenerateClaim() failed: %v", err)
+	}
+	second, err := workflow.GenerateClaim("123456789012345678", address)
+	if err != nil {
+		t.Fatalf("GenerateClaim() failed: %v", err)
+	}
+	if second.Data != first.Data || second.Signature != first.Signature || !second.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("second claim = %+v, want the first claim %+v", second, first)
+	}
+	if height.Load() != 1 {
+		t.Errorf("block height queried %d times, want once", height.Load())
+	}
+
+	// Another address or an unlink is a different claim
+	other, err := workflow.GenerateClaim("123456789012345678", "g1other")
+	if err != nil {
+		t.Fatalf("GenerateClaim() failed: %v", err)
+	}
+	unlink, err := workflow.GenerateUnlinkClaim("123456789012345678", address)
+	if err != nil {
+		t.Fatalf("GenerateUnlinkClaim() failed: %v", err)
+	}
+	if other.Signature == first.Signature || unlink.Signature == first.Signature {
+		t.Error("different claims were handed the same signature")
+	}
+
+	// Once the claim expires a new one is signed
+	global, err := store.GetGlobal()
+	if err != nil {
+		t.Fatalf("GetGlobal() failed: %v", err)
+	}
+	for _, issued := range global.IssuedClaims {
+		issued.ExpiresAt = time.Now().Add(-time.Second)
+	}
+	if err := store.SetGlobal(global); err != nil {
+		t.Fatalf("SetGlobal() failed: %v", err)
+	}
+	renewed, err := workflow.GenerateClaim("123456789012345678", address)
+	if err != nil {
+		t.Fatalf("GenerateClaim() failed: %v", err)
+	}
+	if renewed.Signature == first.Signature {
+		t.Error("an expired claim was handed out again")
+	}
+}
+
+func TestUserLinkingWorkflow_GenerateClaimAfterRevoke(t *testing.T) {
+	client, _ := newStatusRPC(t)
+	_, privKey, _ := sign.GenerateKey(nil)
+	store := storage.NewMemoryConfigStore()
+	configManager := config.NewConfigManager(store, &config.StorageConfig{ClaimTTL: 30 * time.Minute}, nil, core.NewSlogLogger(core.ParseLogLevel("error")))
+	workflow := NewUserLinkingWorkflow(client, WorkflowConfig{SigningKey: privKey, Claims: configManager})
+
+	const guildID, userID = "guild-1", "123456789012345678"
+	address := "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"
+	if err := store.Set(guildID, storage.NewGuildConfig(guildID)); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	first, err := workflow.GenerateClaim(userID, address)
+	if err != nil {
+		t.Fatalf("GenerateClaim() failed: %v", err)
+	}
+	err = configManager.RecordPendingClaim(guildID, userID, &storage.PendingClaim{
+		Type:      string(first.Type),
+		Address:   address,
+		Signature: first.Signature,
+		CreatedAt: first.CreatedAt,
+	})
+	if err != nil {
+		t.Fatalf("RecordPendingClaim() failed: %v", err)
+	}
+
+	if revoked, err := configManager.RevokePendingClaim(guildID, userID); err != nil || !revoked {
+		t.Fatalf("RevokePendingClaim() = %v, %v, want true", revoked, err)
+	}
+
+	next, err := workflow.GenerateClaim(userID, address)
+	if err != nil {
+		t.Fatalf("GenerateClaim() failed: %v", err)
+	}
+	if next.Signature == first.Signature {
+		t.Error("the revoked claim was handed out again")
+	}
+}
+
+func TestUserLinkingWorkflow_GenerateClaimWithoutClaimStore(t *testing.T) {
+	client, _ := newStatusRPC(t)
+	_, privKey, _ := sign.GenerateKey(nil)
+	workflow := NewUserLinkingWorkflow(client, WorkflowConfig{SigningKey: privKey})
+
+	first, err := workflow.GenerateClaim("123456789012345678", "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
+	if err != nil {
+		t.Fatalf("GenerateClaim() failed: %v", err)
+	}
+	second, err := workflow.GenerateClaim("123456789012345678", "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
+	if err != nil {
+		t.Fatalf("GenerateClaim() failed: %v", err)
+	}
+	if second.Signature == first.Signature {
+		t.Error("claims were reused without a claim store")
+	}
+}
@@ -9,12 +9,19 @@ type UserLinkingWorkflow interface {
 	// GenerateClaim creates a signed claim for linking a platform user to a Gno address
 	GenerateClaim(platformID, gnoAddress string) (*core.Claim, error)
 
-	// GenerateUnlinkClaim creates a signed claim for unlinking a platform user from their Gno address
+	// GenerateUnlinkClaim creates a signed claim for unlinking a platform user from one of their
+	// Gno addresses, or from all of them if gnoAddress is empty
 	GenerateUnlinkClaim(platformID, gnoAddress string) (*core.Claim, error)
 
 	// GetLinkedAddress retrieves the Gno address linked to a platform user
 	GetLinkedAddress(platformID string) (string, error)
 
+	// ListLinkedAddresses retrieves every Gno address linked to a platform user
+	ListLinkedAddresses(platformID string) ([]string, error)
+
+	// GetLinkedPlatformID retrieves the platform user linked to a Gno address, or "" if it is not linked
+	GetLinkedPlatformID(gnoAddress string) (string, error)
+
 	// GetClaimURL returns the URL where users can submit their claim
 	GetClaimURL(claim *core.Claim) string
 }
@@ -39,6 +46,14 @@ type RoleLinkingWorkflow interface {
 	// HasRealmRole checks if an address has a specific role in the realm
 	HasRealmRole(realmPath, roleName, address string) (bool, error)
 
+	// ListRealmRoleMembers returns the addresses holding a role in the realm, or an error wrapping
+	// ErrMembersNotEnumerable if the realm cannot list them, in which case HasRealmRole is queried
+	// per address instead
+	ListRealmRoleMembers(realmPath, roleName string) ([]string, error)
+
+	// EvalRealmPredicate calls a realm function with an address and reports whether it returned true
+	EvalRealmPredicate(realmPath, function, address string) (bool, error)
+
 	// GetClaimURL returns the URL where admins can submit their claim
 	GetClaimURL(claim *core.Claim) string
 }
@@ -49,6 +64,15 @@ type SyncWorkflow interface {
 	SyncUserRoles(platformID, realmPath, platformGuildID string) ([]core.RoleStatus, error)
 }
 
+// ClaimStore keeps issued claims by idempotency key until they expire
+type ClaimStore interface {
+	// IssuedClaim returns the unexpired claim issued under key, if any
+	IssuedClaim(key string) (*core.Claim, bool)
+
+	// RecordIssuedClaim keeps claim under key until it expires
+	RecordIssuedClaim(key string, claim *core.Claim)
+}
+
 // WorkflowConfig contains configuration for all workflows
 type WorkflowConfig struct {
 	// SigningKey is the bot's private key for signing claims
@@ -62,4 +86,15 @@ type WorkflowConfig struct {
 
 	// RoleContract is the path to the role linking contract
 	RoleContract string
+
+	// Retry retries realm queries that failed on the RPC; the zero policy does not retry
+	Retry RetryPolicy
+
+	// Breaker pauses realm queries after repeated RPC failures; nil never pauses them. Share one
+	// breaker between the workflows querying the same RPC.
+	Breaker *CircuitBreaker
+
+	// Claims hands out the claim already issued when the same claim is requested again before it
+	// expires, so double clicks do not sign duplicate claims; nil signs a new claim every time
+	Claims ClaimStore
 }
@@ -0,0 +1,65 @@
+package workflows
+
+import (
+	"errors"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+)
+
+// ErrRolesNotSupported is returned for realm role operations on platforms that only link users
+var ErrRolesNotSupported = errors.New("realm roles are not supported on this platform")
+
+// NoRoleLinkingWorkflow is the role linking workflow of platforms that only link users, such as
+// Telegram groups, whose only role is the verified status. No guild has linked realm roles, so
+// verification only grants and removes the verified role.
+type NoRoleLinkingWorkflow struct{}
+
+// NewNoRoleLinkingWorkflow creates a role linking workflow without realm roles
+func NewNoRoleLinkingWorkflow() RoleLinkingWorkflow {
+	return NoRoleLinkingWorkflow{}
+}
+
+// GenerateClaim returns ErrRolesNotSupported
+func (NoRoleLinkingWorkflow) GenerateClaim(userID, platformGuildID, platformRoleID, roleName, realmPath string) (*core.Claim, error) {
+	return nil, ErrRolesNotSupported
+}
+
+// GenerateUnlinkClaim returns ErrRolesNotSupported
+func (NoRoleLinkingWorkflow) GenerateUnlinkClaim(userID, platformGuildID, platformRoleID, roleName, realmPath string) (*core.Claim, error) {
+	return nil, ErrRolesNotSupported
+}
+
+// GetLinkedRole returns no role mapping
+func (NoRoleLinkingWorkflow) GetLinkedRole(realmPath, roleName, platformGuildID string) (*core.RoleMapping, error) {
+	return nil, nil
+}
+
+// ListLinkedRoles returns no role mappings
+func (NoRoleLinkingWorkflow) ListLinkedRoles(realmPath, platformGuildID string) ([]*core.RoleMapping, error) {
+	return nil, nil
+}
+
+// ListAllRolesByGuild returns no role mappings
+func (NoRoleLinkingWorkflow) ListAllRolesByGuild(platformGuildID string) ([]*core.RoleMapping, error) {
+	return nil, nil
+}
+
+// HasRealmRole reports that the address holds no realm role
+func (NoRoleLinkingWorkflow) HasRealmRole(realmPath, roleName, address string) (bool, error) {
+	return false, nil
+}
+
+// ListRealmRoleMembers returns no members
+func (NoRoleLinkingWorkflow) ListRealmRoleMembers(realmPath, roleName string) ([]string, error) {
+	return nil, nil
+}
+
+// EvalRealmPredicate returns ErrRolesNotSupported
+func (NoRoleLinkingWorkflow) EvalRealmPredicate(realmPath, function, address string) (bool, error) {
+	return false, ErrRolesNotSupported
+}
+
+// GetClaimURL returns no URL, as no claims are issued
+func (NoRoleLinkingWorkflow) GetClaimURL(claim *core.Claim) string {
+	return ""
+}
@@ -0,0 +1,177 @@
+package workflows
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/contracts"
+)
+
+// ErrCircuitOpen is returned instead of querying the chain while the realm query circuit breaker
+// is open
+var ErrCircuitOpen = errors.New("realm queries paused after repeated RPC failures")
+
+// RetryPolicy retries realm queries that failed on the RPC with exponential backoff
+type RetryPolicy struct {
+	// Attempts is the number of times a query is tried; a zero policy tries once
+	Attempts int
+
+	// InitialBackoff is the pause before the first retry, doubled before each further retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the pause between retries
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy tries a realm query three times over about a second
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:       3,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+// Circuit breaker defaults
+const (
+	DefaultBreakerThreshold = 20
+	DefaultBreakerCooldown  = time.Minute
+)
+
+// CircuitBreaker stops realm queries after a number of consecutive RPC failures. Once open, queries
+// fail fast with ErrCircuitOpen until the cooldown has passed; then a single probe query is let
+// through, closing the breaker if it succeeds and reopening it for another cooldown if it fails.
+// A nil *CircuitBreaker never opens.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	logger    core.Logger
+
+	mu            sync.Mutex
+	failures      int
+	open          bool
+	probing       bool
+	openedAt      time.Time
+	onStateChange func(open bool)
+	now           func() time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker opening after threshold consecutive failures
+func NewCircuitBreaker(threshold int, cooldown time.Duration, logger core.Logger) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// OnStateChange registers a function called whenever the breaker opens or closes, e.g. to export
+// its state as a metric
+func (b *CircuitBreaker) OnStateChange(fn func(open bool)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onStateChange = fn
+}
+
+// Open reports whether the breaker is currently stopping queries
+func (b *CircuitBreaker) Open() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+// allow returns ErrCircuitOpen if a query may not be made now
+func (b *CircuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return nil
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	b.logger.Info("Realm query circuit breaker cooldown over, probing the RPC")
+	return nil
+}
+
+// record updates the breaker with the outcome of a query. Only transient errors count as
+// failures: a realm error still means the RPC answered.
+func (b *CircuitBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !contracts.IsTransient(err) {
+		b.failures = 0
+		b.probing = false
+		if b.open {
+			b.open = false
+			b.logger.Info("Realm query circuit breaker closed, resuming queries")
+			b.notify()
+		}
+		return
+	}
+
+	b.failures++
+	switch {
+	case b.probing:
+		b.probing = false
+		b.openedAt = b.now()
+		b.logger.Warn("Realm query probe failed, circuit breaker stays open", "cooldown", b.cooldown, "error", err)
+	case !b.open && b.failures >= b.threshold:
+		b.open = true
+		b.openedAt = b.now()
+		b.logger.Warn("Realm query circuit breaker opened, pausing realm queries",
+			"consecutive_failures", b.failures,
+			"cooldown", b.cooldown,
+			"error", err)
+		b.notify()
+	}
+}
+
+func (b *CircuitBreaker) notify() {
+	if b.onStateChange != nil {
+		b.onStateChange(b.open)
+	}
+}
+
+// IsUnavailable reports whether a realm query failed because the chain could not be reached,
+// after retries or with the circuit breaker open, rather than because of the realm's answer
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrCircuitOpen) || contracts.IsTransient(err)
+}
+
+// realmQuery runs a realm query through the circuit breaker, retrying RPC failures with
+// exponential backoff
+func realmQuery[T any](config WorkflowConfig, query func() (T, error)) (T, error) {
+	backoff := config.Retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		if err := config.Breaker.allow(); err != nil {
+			var zero T
+			return zero, err
+		}
+
+		result, err := query()
+		config.Breaker.record(err)
+		if !contracts.IsTransient(err) || attempt >= config.Retry.Attempts || config.Breaker.Open() {
+			return result, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if limit := config.Retry.MaxBackoff; limit > 0 && backoff > limit {
+			backoff = limit
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+)
+
+var errRPCDown = errors.New("query qeval: connection refused")
+
+// flakyQuery fails with err for its first failures calls, then returns true
+func flakyQuery(failures int, err error) (func() (bool, error), *int) {
+	calls := 0
+	return func() (bool, error) {
+		calls++
+		if calls <= failures {
+			return false, err
+		}
+		return true, nil
+	}, &calls
+}
+
+func testRetryConfig(breaker *CircuitBreaker) WorkflowConfig {
+	return WorkflowConfig{
+		Retry:   RetryPolicy{Attempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond},
+		Breaker: breaker,
+	}
+}
+
+func TestRealmQuery_RetriesFlakyRPC(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "recovers within the attempts", failures: 2, err: errRPCDown, wantCalls: 3},
+		{name: "gives up after the attempts", failures: 5, err: errRPCDown, wantCalls: 3, wantErr: true},
+		{
+			name:      "realm errors are not retried",
+			failures:  5,
+			err:       fmt.Errorf("QEval failed: %w", abci.StringError("HasRole not declared")),
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, calls := flakyQuery(tt.failures, tt.err)
+			_, err := realmQuery(testRetryConfig(nil), query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("realmQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *calls != tt.wantCalls {
+				t.Errorf("query called %d times, want %d", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_PausesQueriesWhileRPCIsDown(t *testing.T) {
+	now := time.Now()
+	breaker := NewCircuitBreaker(5, time.Minute, core.NewSlogLogger(core.ParseLogLevel("error")))
+	breaker.now = func() time.Time { return now }
+	var states []bool
+	breaker.OnStateChange(func(open bool) { states = append(states, open) })
+	config := testRetryConfig(breaker)
+
+	// Two members' queries exhaust their retries and open the breaker on the fifth failure
+	down, calls := flakyQuery(1000, errRPCDown)
+	for range 2 {
+		if _, err := realmQuery(config, down); !errors.Is(err, errRPCDown) {
+			t.Fatalf("realmQuery() error = %v, want %v", err, errRPCDown)
+		}
+	}
+	if !breaker.Open() || *calls != 5 {
+		t.Fatalf("breaker open = %v after %d calls, want open after 5", breaker.Open(), *calls)
+	}
+
+	// Further queries fail fast without reaching the RPC
+	for range 100 {
+		if _, err := realmQuery(config, down); !errors.Is(err, ErrCircuitOpen) || !IsUnavailable(err) {
+			t.Fatalf("realmQuery() error = %v, want %v", err, ErrCircuitOpen)
+		}
+	}
+	if *calls != 5 {
+		t.Errorf("open breaker let %d queries through", *calls-5)
+	}
+
+	// After the cooldown a failed probe keeps the breaker open for another cooldown
+	now = now.Add(time.Minute)
+	if _, err := realmQuery(config, down); !errors.Is(err, errRPCDown) || *calls != 6 {
+		t.Fatalf("probe error = %v after %d calls, want one failed probe", err, *calls)
+	}
+	if _, err := realmQuery(config, down); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("realmQuery() after failed probe error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	up, _ := flakyQuery(0, nil)
+	if ok, err := realmQuery(config, up); err != nil || !ok {
+		t.Fatalf("realmQuery() = %v, %v after the RPC recovered", ok, err)
+	}
+	if breaker.Open() {
+		t.Error("breaker still open after a successful probe")
+	}
+	if len(states) != 2 || !states[0] || states[1] {
+		t.Errorf("state changes = %v, want [true false]", states)
+	}
+}
@@ -2,6 +2,7 @@ package workflows
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -12,6 +13,9 @@ import (
 	"golang.org/x/crypto/nacl/sign"
 )
 
+// ErrMembersNotEnumerable is returned by ListRealmRoleMembers for realms that cannot list a role's members
+var ErrMembersNotEnumerable = errors.New("realm does not list role members")
+
 // RoleLinkingWorkflowImpl implements the role linking workflow
 type RoleLinkingWorkflowImpl struct {
 	gnoClient *contracts.GnoClient
@@ -38,68 +42,93 @@ func (w *RoleLinkingWorkflowImpl) GenerateClaim(userID, platformGuildID, platfor
 		return nil, fmt.Errorf("user has not linked their Gno address")
 	}
 
-	// Get current block height
-	blockHeight, err := w.gnoClient.GetCurrentBlockHeight()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current block height: %w", err)
-	}
+	return issueClaim(w.config, claimKey(core.ClaimTypeRoleLink, userID, platformGuildID, platformRoleID, gnoAddress, roleName, realmPath), func() (*core.Claim, error) {
+		// Get current block height
+		blockHeight, err := w.gnoClient.GetCurrentBlockHeight()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current block height: %w", err)
+		}
 
-	// Generate the claim
-	timestamp := time.Now()
-	message := fmt.Sprintf("%d,%s,%s,%s,%s,%s,%s",
-		blockHeight, userID, platformGuildID, platformRoleID, gnoAddress, roleName, realmPath)
-	signature := sign.Sign(nil, []byte(message), w.config.SigningKey)[:64] // Only the signature part
-	signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
-
-	return &core.Claim{
-		Type:      core.ClaimTypeRoleLink,
-		Data:      message,
-		Signature: signatureEncoded,
-		CreatedAt: timestamp,
-	}, nil
+		// Generate the claim
+		timestamp := time.Now()
+		message := fmt.Sprintf("%d,%s,%s,%s,%s,%s,%s",
+			blockHeight, userID, platformGuildID, platformRoleID, gnoAddress, roleName, realmPath)
+		signature := sign.Sign(nil, []byte(message), w.config.SigningKey)[:64] // Only the signature part
+		signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
+
+		return &core.Claim{
+			Type:      core.ClaimTypeRoleLink,
+			Data:      message,
+			Signature: signatureEncoded,
+			CreatedAt: timestamp,
+		}, nil
+	})
 }
 
 // GenerateUnlinkClaim creates a signed claim for unlinking a realm role from a platform role
 func (w *RoleLinkingWorkflowImpl) GenerateUnlinkClaim(userID, platformGuildID, platformRoleID, roleName, realmPath string) (*core.Claim, error) {
-	// Get current block height
-	blockHeight, err := w.gnoClient.GetCurrentBlockHeight()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current block height: %w", err)
-	}
+	return issueClaim(w.config, claimKey(core.ClaimTypeRoleUnlink, userID, platformGuildID, roleName, realmPath), func() (*core.Claim, error) {
+		// Get current block height
+		blockHeight, err := w.gnoClient.GetCurrentBlockHeight()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current block height: %w", err)
+		}
 
-	// Generate the unlink claim (doesn't need role ID or address)
-	timestamp := time.Now()
-	message := fmt.Sprintf("%d,%s,%s,%s,%s",
-		blockHeight, userID, platformGuildID, realmPath, roleName)
-	signature := sign.Sign(nil, []byte(message), w.config.SigningKey)[:64] // Only the signature part
-	signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
-
-	return &core.Claim{
-		Type:      core.ClaimTypeRoleUnlink,
-		Data:      message,
-		Signature: signatureEncoded,
-		CreatedAt: timestamp,
-	}, nil
+		// Generate the unlink claim (doesn't need role ID or address)
+		timestamp := time.Now()
+		message := fmt.Sprintf("%d,%s,%s,%s,%s",
+			blockHeight, userID, platformGuildID, realmPath, roleName)
+		signature := sign.Sign(nil, []byte(message), w.config.SigningKey)[:64] // Only the signature part
+		signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
+
+		return &core.Claim{
+			Type:      core.ClaimTypeRoleUnlink,
+			Data:      message,
+			Signature: signatureEncoded,
+			CreatedAt: timestamp,
+		}, nil
+	})
 }
 
 // GetLinkedRole retrieves the role mapping for a specific realm role
 func (w *RoleLinkingWorkflowImpl) GetLinkedRole(realmPath, roleName, platformGuildID string) (*core.RoleMapping, error) {
-	return w.gnoClient.GetLinkedRole(realmPath, roleName, platformGuildID)
+	return realmQuery(w.config, func() (*core.RoleMapping, error) {
+		return w.gnoClient.GetLinkedRole(realmPath, roleName, platformGuildID)
+	})
 }
 
 // ListLinkedRoles retrieves all role mappings for a realm
 func (w *RoleLinkingWorkflowImpl) ListLinkedRoles(realmPath, platformGuildID string) ([]*core.RoleMapping, error) {
-	return w.gnoClient.ListLinkedRoles(realmPath, platformGuildID)
+	return realmQuery(w.config, func() ([]*core.RoleMapping, error) {
+		return w.gnoClient.ListLinkedRoles(realmPath, platformGuildID)
+	})
 }
 
 // ListAllRolesByGuild retrieves all role mappings for a guild across all realms
 func (w *RoleLinkingWorkflowImpl) ListAllRolesByGuild(platformGuildID string) ([]*core.RoleMapping, error) {
-	return w.gnoClient.ListAllRolesByGuild(platformGuildID)
+	return realmQuery(w.config, func() ([]*core.RoleMapping, error) {
+		return w.gnoClient.ListAllRolesByGuild(platformGuildID)
+	})
 }
 
 // HasRealmRole checks if an address has a specific role in the realm
 func (w *RoleLinkingWorkflowImpl) HasRealmRole(realmPath, roleName, address string) (bool, error) {
-	return w.gnoClient.HasRole(realmPath, roleName, address)
+	return realmQuery(w.config, func() (bool, error) { return w.gnoClient.HasRole(realmPath, roleName, address) })
+}
+
+// ListRealmRoleMembers returns the addresses holding a role in the realm
+func (w *RoleLinkingWorkflowImpl) ListRealmRoleMembers(realmPath, roleName string) ([]string, error) {
+	members, err := realmQuery(w.config, func() ([]string, error) { return w.gnoClient.ListRoleMembers(realmPath, roleName) })
+	if err != nil && !IsUnavailable(err) {
+		// The realm answered, so it does not declare or support the listing
+		return nil, fmt.Errorf("%w: %w", ErrMembersNotEnumerable, err)
+	}
+	return members, err
+}
+
+// EvalRealmPredicate calls a realm function with an address and reports whether it returned true
+func (w *RoleLinkingWorkflowImpl) EvalRealmPredicate(realmPath, function, address string) (bool, error) {
+	return realmQuery(w.config, func() (bool, error) { return w.gnoClient.EvalPredicate(realmPath, function, address) })
 }
 
 // GetClaimURL returns the URL where admins can submit their claim
@@ -32,7 +32,7 @@ func (w *SyncWorkflowImpl) SyncUserRoles(platformID, realmPath, platformGuildID
 	w.logger.Debug("SyncUserRoles called", "platform_id", platformID, "realm_path", realmPath, "guild_id", platformGuildID)
 
 	// Get the user's linked Gno address
-	gnoAddress, err := w.gnoClient.GetLinkedAddress(platformID)
+	gnoAddress, err := realmQuery(w.config, func() (string, error) { return w.gnoClient.GetLinkedAddress(platformID) })
 	if err != nil {
 		w.logger.Error("Failed to get linked address", "error", err, "platform_id", platformID)
 		return nil, fmt.Errorf("failed to get linked address: %w", err)
@@ -46,7 +46,9 @@ func (w *SyncWorkflowImpl) SyncUserRoles(platformID, realmPath, platformGuildID
 	w.logger.Info("Found linked address", "platform_id", platformID, "gno_address", gnoAddress)
 
 	// Get all linked roles for the realm
-	linkedRoles, err := w.gnoClient.ListLinkedRoles(realmPath, platformGuildID)
+	linkedRoles, err := realmQuery(w.config, func() ([]*core.RoleMapping, error) {
+		return w.gnoClient.ListLinkedRoles(realmPath, platformGuildID)
+	})
 	if err != nil {
 		w.logger.Error("Failed to list linked roles", "error", err, "realm_path", realmPath, "guild_id", platformGuildID)
 		return nil, fmt.Errorf("failed to list linked roles: %w", err)
@@ -64,7 +66,9 @@ func (w *SyncWorkflowImpl) SyncUserRoles(platformID, realmPath, platformGuildID
 			"role_name", roleMapping.RealmRoleName,
 			"gno_address", gnoAddress)
 
-		isMember, err := w.gnoClient.HasRole(roleMapping.RealmPath, roleMapping.RealmRoleName, gnoAddress)
+		isMember, err := realmQuery(w.config, func() (bool, error) {
+			return w.gnoClient.HasRole(roleMapping.RealmPath, roleMapping.RealmRoleName, gnoAddress)
+		})
 		if err != nil {
 			w.logger.Error("Failed to check role membership",
 				"error", err,
@@ -27,53 +27,81 @@ func NewUserLinkingWorkflow(client *contracts.GnoClient, config WorkflowConfig)
 
 // GenerateClaim creates a signed claim for linking a platform user to a Gno address
 func (w *UserLinkingWorkflowImpl) GenerateClaim(platformID, gnoAddress string) (*core.Claim, error) {
-	// Get current block height
-	blockHeight, err := w.gnoClient.GetCurrentBlockHeight()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current block height: %w", err)
-	}
-
-	// Create message with block height instead of timestamp
-	message := fmt.Sprintf("%d,%s,%s", blockHeight, platformID, gnoAddress)
-
-	// Sign only the message (not the full signed message)
-	signature := sign.Sign(nil, []byte(message), w.config.SigningKey)[:64] // Only the signature part
-	signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
-
-	return &core.Claim{
-		Type:      core.ClaimTypeUserLink,
-		Data:      message,
-		Signature: signatureEncoded,
-		CreatedAt: time.Now(), // Keep for tracking purposes
-	}, nil
+	return issueClaim(w.config, claimKey(core.ClaimTypeUserLink, platformID, gnoAddress), func() (*core.Claim, error) {
+		// Get current block height
+		blockHeight, err := w.gnoClient.GetCurrentBlockHeight()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current block height: %w", err)
+		}
+
+		// Create message with block height instead of timestamp
+		message := fmt.Sprintf("%d,%s,%s", blockHeight, platformID, gnoAddress)
+
+		// Sign only the message (not the full signed message)
+		signature := sign.Sign(nil, []byte(message), w.config.SigningKey)[:64] // Only the signature part
+		signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
+
+		return &core.Claim{
+			Type:      core.ClaimTypeUserLink,
+			Data:      message,
+			Signature: signatureEncoded,
+			CreatedAt: time.Now(), // Keep for tracking purposes
+		}, nil
+	})
 }
 
-// GenerateUnlinkClaim creates a signed claim for unlinking a platform user from their Gno address
+// GenerateUnlinkClaim creates a signed claim for unlinking a platform user from one of their
+// Gno addresses, or from all of them if gnoAddress is empty
 func (w *UserLinkingWorkflowImpl) GenerateUnlinkClaim(platformID, gnoAddress string) (*core.Claim, error) {
-	// Get current block height
-	blockHeight, err := w.gnoClient.GetCurrentBlockHeight()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current block height: %w", err)
-	}
+	return issueClaim(w.config, claimKey(core.ClaimTypeUserUnlink, platformID, gnoAddress), func() (*core.Claim, error) {
+		// Get current block height
+		blockHeight, err := w.gnoClient.GetCurrentBlockHeight()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current block height: %w", err)
+		}
+
+		// Create message with block height and platformID, plus the address when only one is unlinked
+		message := fmt.Sprintf("%d,%s", blockHeight, platformID)
+		if gnoAddress != "" {
+			message += "," + gnoAddress
+		}
+
+		// Sign only the message (not the full signed message)
+		signature := sign.Sign(nil, []byte(message), w.config.SigningKey)[:64] // Only the signature part
+		signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
+
+		return &core.Claim{
+			Type:      core.ClaimTypeUserUnlink,
+			Data:      message,
+			Signature: signatureEncoded,
+			CreatedAt: time.Now(), // Keep for tracking purposes
+		}, nil
+	})
+}
 
-	// Create message with block height and platformID only (no address needed for unlink)
-	message := fmt.Sprintf("%d,%s", blockHeight, platformID)
+// GetLinkedAddress retrieves the Gno address linked to a platform user
+func (w *UserLinkingWorkflowImpl) GetLinkedAddress(platformID string) (string, error) {
+	return realmQuery(w.config, func() (string, error) { return w.gnoClient.GetLinkedAddress(platformID) })
+}
 
-	// Sign only the message (not the full signed message)
-	signature := sign.Sign(nil, []byte(message), w.config.SigningKey)[:64] // Only the signature part
-	signatureEncoded := base64.RawURLEncoding.EncodeToString(signature)
+// ListLinkedAddresses retrieves every Gno address linked to a platform user. User contracts
+// that link a single address do not list them, so their linked address is used instead.
+func (w *UserLinkingWorkflowImpl) ListLinkedAddresses(platformID string) ([]string, error) {
+	addresses, err := realmQuery(w.config, func() ([]string, error) { return w.gnoClient.ListLinkedAddresses(platformID) })
+	if err == nil || IsUnavailable(err) {
+		return addresses, err
+	}
 
-	return &core.Claim{
-		Type:      core.ClaimTypeUserUnlink,
-		Data:      message,
-		Signature: signatureEncoded,
-		CreatedAt: time.Now(), // Keep for tracking purposes
-	}, nil
+	address, err := w.GetLinkedAddress(platformID)
+	if err != nil || address == "" {
+		return nil, err
+	}
+	return []string{address}, nil
 }
 
-// GetLinkedAddress retrieves the Gno address linked to a platform user
-func (w *UserLinkingWorkflowImpl) GetLinkedAddress(platformID string) (string, error) {
-	return w.gnoClient.GetLinkedAddress(platformID)
+// GetLinkedPlatformID retrieves the platform user linked to a Gno address, or "" if it is not linked
+func (w *UserLinkingWorkflowImpl) GetLinkedPlatformID(gnoAddress string) (string, error) {
+	return realmQuery(w.config, func() (string, error) { return w.gnoClient.GetLinkedPlatformID(gnoAddress) })
 }
 
 // GetClaimURL returns the URL where users can submit their claim
@@ -88,7 +116,11 @@ func (w *UserLinkingWorkflowImpl) GetClaimURL(claim *core.Claim) string {
 	discordID := parts[1]
 
 	if claim.Type == core.ClaimTypeUserUnlink {
-		// Unlink URL: /unlink?blockHeight=X&discordID=Y&signature=S
+		// Unlink URL: /unlink?blockHeight=X&discordID=Y&signature=S, with &address=Z for a single address
+		if len(parts) >= 3 {
+			return fmt.Sprintf("%s/%s:unlink?blockHeight=%s&discordID=%s&address=%s&signature=%s",
+				w.config.BaseURL, w.config.UserContract, blockHeight, discordID, parts[2], claim.Signature)
+		}
 		return fmt.Sprintf("%s/%s:unlink?blockHeight=%s&discordID=%s&signature=%s",
 			w.config.BaseURL, w.config.UserContract, blockHeight, discordID, claim.Signature)
 	}
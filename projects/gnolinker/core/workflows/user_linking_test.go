@@ -80,4 +80,20 @@ func TestUserLinkingWorkflow_GetClaimURL(t *testing.T) {
 			}
 		}
 	})
+	t.Run("single address unlink claim URL", func(t *testing.T) {
+		claim := &core.Claim{
+			Type:      core.ClaimTypeUserUnlink,
+			Data:      "1000,123456789012345678,g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5",
+			Signature: "test-signature",
+			CreatedAt: time.Now(),
+		}
+
+		url := workflow.GetClaimURL(claim)
+
+		want := "https://example.com/r/linker/user/v0:unlink?blockHeight=1000&discordID=123456789012345678" +
+			"&address=g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5&signature=test-signature"
+		if url != want {
+			t.Errorf("GetClaimURL() = %q, want %q", url, want)
+		}
+	})
 }
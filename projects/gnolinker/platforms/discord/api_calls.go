@@ -0,0 +1,90 @@
+package discord
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/bwmarrin/discordgo"
+)
+
+var apiVersionPrefix = regexp.MustCompile(`^/api/v\d+`)
+
+// discordOperations maps normalized REST routes to the discordgo method that calls them
+var discordOperations = map[string]string{
+	"GET /guilds/{id}":                                    "Guild",
+	"GET /guilds/{id}/members":                            "GuildMembers",
+	"GET /guilds/{id}/members/{id}":                       "GuildMember",
+	"PUT /guilds/{id}/members/{id}/roles/{id}":            "GuildMemberRoleAdd",
+	"DELETE /guilds/{id}/members/{id}/roles/{id}":         "GuildMemberRoleRemove",
+	"GET /guilds/{id}/roles":                              "GuildRoles",
+	"POST /guilds/{id}/roles":                             "GuildRoleCreate",
+	"PATCH /guilds/{id}/roles/{id}":                       "GuildRoleEdit",
+	"DELETE /guilds/{id}/roles/{id}":                      "GuildRoleDelete",
+	"POST /users/@me/channels":                            "UserChannelCreate",
+	"POST /channels/{id}/messages":                        "ChannelMessageSend",
+	"PUT /channels/{id}/permissions/{id}":                 "ChannelPermissionSet",
+	"DELETE /channels/{id}/permissions/{id}":              "ChannelPermissionDelete",
+	"POST /interactions/{id}/{token}/callback":            "InteractionRespond",
+	"PATCH /webhooks/{id}/{token}/messages/@original":     "InteractionResponseEdit",
+	"GET /applications/{id}/guilds/{id}/commands":         "ApplicationCommands",
+	"POST /applications/{id}/guilds/{id}/commands":        "ApplicationCommandCreate",
+	"DELETE /applications/{id}/guilds/{id}/commands/{id}": "ApplicationCommandDelete",
+}
+
+// countingTransport records every Discord REST request under its operation name
+type countingTransport struct {
+	next    http.RoundTripper
+	counter *core.APICallCounter
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.counter.Record(discordOperation(req.Method, req.URL.Path))
+	return t.next.RoundTrip(req)
+}
+
+// InstrumentSession counts the session's REST calls in counter.
+// Gateway traffic is not counted as it does not consume REST rate limits.
+func InstrumentSession(session *discordgo.Session, counter *core.APICallCounter) {
+	if session.Client == nil {
+		session.Client = &http.Client{}
+	}
+	next := session.Client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	session.Client.Transport = &countingTransport{next: next, counter: counter}
+}
+
+// discordOperation names a REST request after its discordgo method, falling back to
+// the method and route with IDs and tokens elided for unknown endpoints
+func discordOperation(method, path string) string {
+	segments := strings.Split(strings.Trim(apiVersionPrefix.ReplaceAllString(path, ""), "/"), "/")
+	for i, segment := range segments {
+		switch {
+		case isSnowflake(segment):
+			segments[i] = "{id}"
+		case i >= 2 && (segments[i-2] == "interactions" || segments[i-2] == "webhooks"):
+			segments[i] = "{token}"
+		}
+	}
+
+	route := method + " /" + strings.Join(segments, "/")
+	if operation, ok := discordOperations[route]; ok {
+		return operation
+	}
+	return route
+}
+
+func isSnowflake(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package discord
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/bwmarrin/discordgo"
+)
+
+// stubTransport answers every Discord REST request without touching the network
+type stubTransport struct{}
+
+func (stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status, body := http.StatusNoContent, ""
+	if req.Method == http.MethodGet {
+		status, body = http.StatusOK, "[]"
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestInstrumentSession_CountsCallsByOperation(t *testing.T) {
+	t.Parallel()
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() failed: %v", err)
+	}
+	session.Client = &http.Client{Transport: stubTransport{}}
+
+	counter := core.NewAPICallCounter()
+	InstrumentSession(session, counter)
+
+	if _, err := session.GuildRoles("111"); err != nil {
+		t.Fatalf("GuildRoles() failed: %v", err)
+	}
+	if _, err := session.GuildMembers("111", "", 1000); err != nil {
+		t.Fatalf("GuildMembers() failed: %v", err)
+	}
+	before := counter.Snapshot()
+	for _, userID := range []string{"222", "333"} {
+		if err := session.GuildMemberRoleAdd("111", userID, "444"); err != nil {
+			t.Fatalf("GuildMemberRoleAdd() failed: %v", err)
+		}
+	}
+	if err := session.GuildMemberRoleRemove("111", "222", "444"); err != nil {
+		t.Fatalf("GuildMemberRoleRemove() failed: %v", err)
+	}
+
+	want := map[string]int64{
+		"GuildRoles":            1,
+		"GuildMembers":          1,
+		"GuildMemberRoleAdd":    2,
+		"GuildMemberRoleRemove": 1,
+	}
+	got := counter.Snapshot()
+	if len(got) != len(want) {
+		t.Errorf("counts = %v, want %v", got, want)
+	}
+	for operation, n := range want {
+		if got[operation] != n {
+			t.Errorf("%s calls = %d, want %d", operation, got[operation], n)
+		}
+	}
+
+	delta := counter.Since(before)
+	if len(delta) != 2 || delta["GuildMemberRoleAdd"] != 2 || delta["GuildMemberRoleRemove"] != 1 {
+		t.Errorf("Since() = %v, want only the role changes", delta)
+	}
+}
+
+func TestDiscordOperation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{"GET", "/api/v9/guilds/123/members/456", "GuildMember"},
+		{"POST", "/api/v9/interactions/123/aW50ZXJhY3Rpb24/callback", "InteractionRespond"},
+		{"PATCH", "/api/v9/webhooks/123/aW50ZXJhY3Rpb24/messages/@original", "InteractionResponseEdit"},
+		{"GET", "/api/v9/guilds/123/audit-logs", "GET /guilds/{id}/audit-logs"},
+	}
+
+	for _, tt := range tests {
+		if got := discordOperation(tt.method, tt.path); got != tt.want {
+			t.Errorf("discordOperation(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
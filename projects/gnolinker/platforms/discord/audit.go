@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// defaultAuditEntries is how many role changes the admin audit command shows by default
+	defaultAuditEntries = 10
+	// maxAuditEntries keeps the audit embed within Discord's description limit
+	maxAuditEntries = 25
+)
+
+// SetAuditReader enables the admin audit command
+func (h *InteractionHandlers) SetAuditReader(reader storage.AuditReader) {
+	h.auditReader = reader
+}
+
+func (h *InteractionHandlers) handleAdminAuditCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	userID := i.Member.User.ID
+	hasPermission, err := h.hasRoleAdminPermission(s, i.GuildID, userID)
+	if err != nil || !hasPermission {
+		h.respondError(s, i, "You need admin permissions (configured admin role or Discord Administrator) to view the audit log.")
+		return
+	}
+	if h.auditReader == nil {
+		h.respondError(s, i, "The audit log is not enabled.")
+		return
+	}
+
+	var member *discordgo.User
+	count := defaultAuditEntries
+	for _, option := range options {
+		switch option.Name {
+		case "user":
+			member = option.UserValue(nil)
+		case "count":
+			count = min(max(int(option.IntValue()), 1), maxAuditEntries)
+		}
+	}
+
+	entries, err := h.auditReader.UserEntries(i.GuildID, member.ID, count)
+	if err != nil {
+		h.logger.Error("Failed to read audit log", "error", err, "guild_id", i.GuildID, "user_id", member.ID)
+		h.respondError(s, i, "Failed to read the audit log.")
+		return
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{formatAuditEmbed(member.ID, entries)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+// formatAuditEmbed lists a member's role changes, newest first, with the realm role and
+// transaction behind each one when known
+func formatAuditEmbed(userID string, entries []storage.AuditEntry) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: "📜 Role Audit",
+		Color: 0x5865F2,
+	}
+
+	var lines strings.Builder
+	lines.WriteString(fmt.Sprintf("Most recent role changes for <@%s>:\n\n", userID))
+	for _, entry := range entries {
+		action := "➕ Granted"
+		if entry.Action == storage.AuditActionRevoke {
+			action = "➖ Revoked"
+		}
+		lines.WriteString(fmt.Sprintf("<t:%d:R> %s <@&%s>", entry.Time.Unix(), action, entry.RoleID))
+		if entry.RealmRole != "" {
+			lines.WriteString(fmt.Sprintf(" (`%s` at `%s`)", entry.RealmRole, entry.RealmPath))
+		}
+		if entry.TxHash != "" {
+			lines.WriteString(fmt.Sprintf(" · tx `%s`", entry.TxHash))
+		}
+		lines.WriteString("\n")
+	}
+	if len(entries) == 0 {
+		lines.WriteString("No role changes recorded.")
+	}
+	embed.Description = lines.String()
+	return embed
+}
@@ -0,0 +1,78 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// manualRoleMarker flags realm roles excluded from auto-sync in role listings
+const manualRoleMarker = " *(manual, not auto-synced)*"
+
+func (h *InteractionHandlers) handleAdminRoleAutoSyncCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// Choosing which roles the bot manages is bot configuration, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to configure role auto-sync.")
+		return
+	}
+
+	roleName := options[0].StringValue()
+	realmPath := options[1].StringValue()
+	autoSync := options[2].BoolValue()
+
+	if err := h.configManager.SetRoleAutoSync(i.GuildID, realmPath, roleName, autoSync); err != nil {
+		h.logger.Error("Failed to set role auto-sync", "error", err, "guild_id", i.GuildID, "role_name", roleName, "realm_path", realmPath, "auto_sync", autoSync)
+		h.respondError(s, i, "Failed to save the role's auto-sync setting.")
+		return
+	}
+
+	content := fmt.Sprintf("✅ Realm role `%s` at `%s` is auto-synced again. Members are reconciled with on-chain membership by the next verification; run `/gnolinker admin resync-role` to do it now.", roleName, realmPath)
+	if !autoSync {
+		content = fmt.Sprintf("✅ Realm role `%s` at `%s` is excluded from auto-sync. It stays linked and listed, but the bot no longer grants or removes its Discord role; assign it manually.", roleName, realmPath)
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+// formatManagedRoles lists role mappings grouped by realm, marking those excluded from auto-sync
+func formatManagedRoles(guildConfig *storage.GuildConfig, roleMappings []*core.RoleMapping) string {
+	realmMappings := make(map[string][]*core.RoleMapping)
+	var realms []string
+	for _, mapping := range roleMappings {
+		if _, seen := realmMappings[mapping.RealmPath]; !seen {
+			realms = append(realms, mapping.RealmPath)
+		}
+		realmMappings[mapping.RealmPath] = append(realmMappings[mapping.RealmPath], mapping)
+	}
+
+	var roleDisplay strings.Builder
+	for _, realm := range realms {
+		roleDisplay.WriteString(fmt.Sprintf("**%s**\n", realm))
+		for _, mapping := range realmMappings[realm] {
+			roleDisplay.WriteString(fmt.Sprintf("• `%s` → <@&%s>%s\n", mapping.RealmRoleName, mapping.PlatformRole.ID, autoSyncMarker(guildConfig, mapping)))
+		}
+		roleDisplay.WriteString("\n")
+	}
+	return roleDisplay.String()
+}
+
+// autoSyncMarker returns manualRoleMarker for a mapping excluded from auto-sync
+func autoSyncMarker(guildConfig *storage.GuildConfig, mapping *core.RoleMapping) string {
+	if guildConfig == nil || guildConfig.IsRoleAutoSynced(mapping.RealmPath, mapping.RealmRoleName) {
+		return ""
+	}
+	return manualRoleMarker
+}
@@ -0,0 +1,28 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+func TestFormatManagedRoles_MarksManualRoles(t *testing.T) {
+	t.Parallel()
+	guildConfig := storage.NewGuildConfig("guild-1")
+	guildConfig.SetRoleAutoSync("gno.land/r/demo/x", "member", false)
+
+	roleMappings := []*core.RoleMapping{
+		{RealmPath: "gno.land/r/demo/x", RealmRoleName: "member", PlatformRole: core.PlatformRole{ID: "role-1"}},
+		{RealmPath: "gno.land/r/demo/x", RealmRoleName: "admin", PlatformRole: core.PlatformRole{ID: "role-2"}},
+	}
+
+	display := formatManagedRoles(guildConfig, roleMappings)
+	if !strings.Contains(display, "• `member` → <@&role-1>"+manualRoleMarker+"\n") {
+		t.Errorf("excluded role should still be listed and marked as manual, got %q", display)
+	}
+	if !strings.Contains(display, "• `admin` → <@&role-2>\n") {
+		t.Errorf("auto-synced role should be listed unmarked, got %q", display)
+	}
+}
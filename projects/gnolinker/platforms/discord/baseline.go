@@ -0,0 +1,68 @@
+package discord
+
+import (
+	"fmt"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/bwmarrin/discordgo"
+)
+
+// RoleBaselineImporter records current managed role assignments as a guild's baseline, as done by the event handlers
+type RoleBaselineImporter interface {
+	ImportRoleBaseline(guildID string) (*events.RoleBaselineResult, error)
+}
+
+// SetRoleBaselineImporter enables the admin import-baseline command
+func (h *InteractionHandlers) SetRoleBaselineImporter(importer RoleBaselineImporter) {
+	h.baselineImporter = importer
+}
+
+func (h *InteractionHandlers) handleAdminImportBaselineCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := i.Member.User.ID
+	hasPermission, err := h.hasRoleAdminPermission(s, i.GuildID, userID)
+	if err != nil || !hasPermission {
+		h.respondError(s, i, "You need admin permissions (configured admin role or Discord Administrator) to import a role baseline.")
+		return
+	}
+	if h.baselineImporter == nil {
+		h.respondError(s, i, "Importing a role baseline requires event monitoring to be enabled.")
+		return
+	}
+
+	// Defer response as every member of the server is read
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to defer interaction response", "error", err)
+		return
+	}
+
+	result, err := h.baselineImporter.ImportRoleBaseline(i.GuildID)
+	if err != nil {
+		h.logger.Error("Failed to import role baseline", "error", err, "guild_id", i.GuildID)
+		h.respondDeferredError(s, i, "Failed to import the role baseline. Check the bot logs for details.")
+		return
+	}
+
+	embed := formatRoleBaselineEmbed(result)
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	}); err != nil {
+		h.logger.Error("Failed to edit interaction response", "error", err)
+	}
+}
+
+// formatRoleBaselineEmbed reports what a role baseline import recorded
+func formatRoleBaselineEmbed(result *events.RoleBaselineResult) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title: "📥 Role Baseline Imported",
+		Description: fmt.Sprintf("Recorded %d assignments of %d linked roles held by %d members.\n"+
+			"On their next verification, linked members keep baseline roles they no longer qualify for; "+
+			"those roles are reconciled on the following pass. Members without a linked address lose them right away.",
+			result.Assignments, result.ManagedRoles, result.Members),
+		Color: 0x00ff00,
+	}
+}
@@ -3,6 +3,7 @@ package discord
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/allinbits/labs/projects/gnolinker/core/config"
 	"github.com/allinbits/labs/projects/gnolinker/core/events"
 	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/health"
 	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
 	"github.com/allinbits/labs/projects/gnolinker/platforms"
 	"github.com/bwmarrin/discordgo"
@@ -27,6 +29,7 @@ type Bot struct {
 	logger                core.Logger
 	queryProcessorManager *events.QueryProcessorManager
 	eventHandlers         *events.EventHandlers
+	eventQuerier          EventQuerier
 }
 
 // NewBot creates a new Discord bot
@@ -46,15 +49,26 @@ func NewBot(config Config,
 	// Enable presence intents for activity tracking
 	session.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMembers | discordgo.IntentsGuildPresences
 
+	var apiCalls *core.APICallCounter
+	if config.LogAPICalls {
+		apiCalls = core.NewAPICallCounter()
+		config.Metrics.CollectAPICalls(apiCalls)
+		InstrumentSession(session, apiCalls)
+	}
+
 	// Create platform adapter with lock manager for safe role creation
 	platform := NewDiscordPlatform(session, config, configManager.GetLockManager(), logger)
 
 	// Create interaction handlers with config manager
 	interactionHandlers := NewInteractionHandlers(userFlow, roleFlow, syncFlow, configManager, logger)
+	if config.AuditLog != nil {
+		interactionHandlers.SetAuditReader(config.AuditLog)
+	}
 
 	// Initialize event monitoring components
 	var queryProcessorManager *events.QueryProcessorManager
 	var eventHandlers *events.EventHandlers
+	var eventQuerier EventQuerier
 
 	// Check if event monitoring should be enabled
 	if config.GraphQLEndpoint != "" && config.EnableEventMonitoring {
@@ -68,15 +82,33 @@ func NewBot(config Config,
 		}
 
 		queryClient := graphql.NewQueryClient(config.GraphQLEndpoint, realmConfig)
+		interactionHandlers.SetEventQuerier(queryClient)
+		eventQuerier = queryClient
 
 		// Create event handlers with all required parameters
 		eventHandlers = events.NewEventHandlers(platform, configManager, session, logger, userFlow, roleFlow)
+		if apiCalls != nil {
+			eventHandlers.SetAPICallCounter(apiCalls)
+		}
+		eventHandlers.SetMetrics(config.Metrics)
+		eventHandlers.SetDryRun(config.DryRun)
+		if config.AuditLog != nil {
+			eventHandlers.SetAuditLog(config.AuditLog)
+		}
+		interactionHandlers.SetRoleResyncer(eventHandlers)
+		interactionHandlers.SetRoleBaselineImporter(eventHandlers)
+		interactionHandlers.SetRealmRefresher(eventHandlers)
 
 		// Create query registry with event handlers
 		queryRegistry := events.CreateCoreQueryRegistry(logger, eventHandlers)
 
 		// Create query processor manager
 		queryProcessorManager = events.NewQueryProcessorManager(queryRegistry, configManager.GetStore(), queryClient, eventHandlers, logger)
+		queryProcessorManager.SetLockManager(configManager.GetLockManager())
+		queryProcessorManager.SetQueryJitter(config.QueryJitter)
+		queryProcessorManager.SetShutdownGrace(config.ShutdownGrace)
+		interactionHandlers.SetGuildVerifier(queryProcessorManager)
+		interactionHandlers.SetIndexerStatusReader(queryProcessorManager)
 	} else {
 		logger.Info("Event monitoring disabled", "graphql_endpoint", config.GraphQLEndpoint, "enable_monitoring", config.EnableEventMonitoring)
 	}
@@ -90,6 +122,7 @@ func NewBot(config Config,
 		logger:                logger,
 		queryProcessorManager: queryProcessorManager,
 		eventHandlers:         eventHandlers,
+		eventQuerier:          eventQuerier,
 	}
 
 	// Set up event handlers
@@ -98,6 +131,8 @@ func NewBot(config Config,
 	session.AddHandler(bot.onMessageCreate)
 	session.AddHandler(bot.interactionHandlers.HandleInteraction)
 	session.AddHandler(bot.onPresenceUpdate)
+	session.AddHandler(bot.onGuildMemberRemove)
+	session.AddHandler(bot.onGuildMemberAdd)
 
 	return bot, nil
 }
@@ -139,6 +174,7 @@ func (b *Bot) Stop() error {
 	// Stop query processor manager if it's running
 	if b.queryProcessorManager != nil {
 		if err := b.queryProcessorManager.Stop(); err != nil {
+			// Work still running resumes from its last saved position on the next start
 			b.logger.Error("Failed to stop query processor manager", "error", err)
 			// Continue with Discord session close anyway
 		} else {
@@ -146,9 +182,43 @@ func (b *Bot) Stop() error {
 		}
 	}
 
+	// Release role changes waiting on the rate limiter so shutdown doesn't hang on a bulk sync
+	if closer, ok := b.platform.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
 	return b.session.Close()
 }
 
+// RegisterHealthChecks adds the Discord gateway connection and, with event monitoring enabled,
+// the indexer and the progress of the query loops as readiness checks. discordgo reconnects a
+// dropped session by itself, so it does not fail liveness.
+func (b *Bot) RegisterHealthChecks(server *health.Server) {
+	server.AddReadinessCheck("discord", func(ctx context.Context) error {
+		b.session.RLock()
+		defer b.session.RUnlock()
+		if !b.session.DataReady {
+			return fmt.Errorf("discord session is not connected")
+		}
+		return nil
+	})
+
+	if b.eventQuerier != nil {
+		server.AddReadinessCheck("indexer", func(ctx context.Context) error {
+			if _, err := b.eventQuerier.QueryLatestBlockHeight(ctx); err != nil {
+				return fmt.Errorf("failed to query latest block: %w", err)
+			}
+			return nil
+		})
+	}
+
+	if b.queryProcessorManager != nil {
+		server.AddReadinessCheck("query_loops", func(ctx context.Context) error {
+			return b.queryProcessorManager.CheckProgress(events.QueryStallTimeout)
+		})
+	}
+}
+
 // GetPlatform returns the platform adapter
 func (b *Bot) GetPlatform() platforms.Platform {
 	return b.platform
@@ -257,6 +327,34 @@ func (b *Bot) handleDirectMessage(s *discordgo.Session, userID string) {
 	}
 }
 
+// onGuildMemberRemove stops role processing for members who leave a guild
+func (b *Bot) onGuildMemberRemove(s *discordgo.Session, m *discordgo.GuildMemberRemove) {
+	if b.eventHandlers == nil || m.Member == nil || m.User == nil {
+		return
+	}
+
+	if err := b.eventHandlers.HandleMemberLeft(m.GuildID, m.User.ID); err != nil {
+		b.logger.Error("Failed to record departed member",
+			"guild_id", m.GuildID,
+			"user_id", m.User.ID,
+			"error", err)
+	}
+}
+
+// onGuildMemberAdd resumes role processing for members who rejoin a guild
+func (b *Bot) onGuildMemberAdd(s *discordgo.Session, m *discordgo.GuildMemberAdd) {
+	if b.eventHandlers == nil || m.Member == nil || m.User == nil {
+		return
+	}
+
+	if err := b.eventHandlers.HandleMemberJoined(context.Background(), m.GuildID, m.Member); err != nil {
+		b.logger.Error("Failed to resume processing for rejoined member",
+			"guild_id", m.GuildID,
+			"user_id", m.User.ID,
+			"error", err)
+	}
+}
+
 func (b *Bot) onPresenceUpdate(s *discordgo.Session, p *discordgo.PresenceUpdate) {
 	if b.eventHandlers == nil {
 		return
@@ -0,0 +1,50 @@
+package discord
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/health"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestBot_ReadinessFollowsSessionAndIndexer(t *testing.T) {
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	session := &discordgo.Session{}
+	querier := &mockEventQuerier{height: 1500}
+	bot := &Bot{session: session, eventQuerier: querier, logger: logger}
+
+	server := health.NewServer("127.0.0.1:0", logger)
+	bot.RegisterHealthChecks(server)
+	probe := func(path string) int {
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	steps := []struct {
+		name      string
+		connected bool
+		indexer   error
+		wantReady int
+	}{
+		{name: "session not open yet", wantReady: http.StatusServiceUnavailable},
+		{name: "session open and indexer reachable", connected: true, wantReady: http.StatusOK},
+		{name: "indexer connection dropped", connected: true, indexer: errors.New("connection refused"), wantReady: http.StatusServiceUnavailable},
+		{name: "indexer back", connected: true, wantReady: http.StatusOK},
+		{name: "session closed", wantReady: http.StatusServiceUnavailable},
+	}
+	for _, step := range steps {
+		session.Lock()
+		session.DataReady = step.connected
+		session.Unlock()
+		querier.heightErr = step.indexer
+
+		if got := probe("/readyz"); got != step.wantReady {
+			t.Errorf("%s: /readyz = %d, want %d", step.name, got, step.wantReady)
+		}
+	}
+}
@@ -1,5 +1,12 @@
 package discord
 
+import (
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/metrics"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
 // Config holds Discord-specific configuration
 type Config struct {
 	// Token is the Discord bot token
@@ -14,6 +21,32 @@ type Config struct {
 	// EnableEventMonitoring enables real-time event monitoring via GraphQL subscriptions
 	EnableEventMonitoring bool
 
+	// LogAPICalls counts Discord REST calls by operation, logs the totals per event and
+	// verification sweep, and exports them as gnolinker_platform_api_calls_total when Metrics is set
+	LogAPICalls bool
+
+	// DryRun logs the role changes made by events and verification instead of applying them
+	DryRun bool
+
+	// RateLimit caps mutating Discord API calls (role changes and DMs) per second across the bot,
+	// spacing bulk syncs to avoid rate limit errors. Zero disables the limit.
+	RateLimit float64
+
+	// QueryJitter moves each event query loop tick by up to half its value either way, so the
+	// loops of many guilds do not poll the indexer together. Zero disables it.
+	QueryJitter time.Duration
+
+	// ShutdownGrace is how long stopping waits for in-flight event queries and verification
+	// passes to reach a saved position before the session is closed. Zero waits until they do.
+	ShutdownGrace time.Duration
+
+	// Metrics records role changes and event processing for the metrics server; nil disables it
+	Metrics *metrics.Metrics
+
+	// AuditLog records the role changes made by event handlers and verification, and backs the
+	// admin audit command; nil disables auditing
+	AuditLog *storage.BufferedAuditLog
+
 	// Note: AdminRoleID and VerifiedAddressRoleID are now managed per-guild
 	// by the ConfigManager and stored in guild-specific configurations
 }
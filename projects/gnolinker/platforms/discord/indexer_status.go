@@ -0,0 +1,63 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxIndexerErrorLength bounds the indexer error shown in the admin info command
+const maxIndexerErrorLength = 200
+
+// IndexerStatusReader reports the indexer status seen by a guild's event query loop
+type IndexerStatusReader interface {
+	IndexerStatus(guildID string) (events.IndexerStatus, bool)
+}
+
+// SetIndexerStatusReader shows the indexer connection in the admin info command
+func (h *InteractionHandlers) SetIndexerStatusReader(reader IndexerStatusReader) {
+	h.indexerStatus = reader
+}
+
+// indexerStatusField reports a guild's indexer connection for the admin info command
+func (h *InteractionHandlers) indexerStatusField(guildID string) *discordgo.MessageEmbedField {
+	if h.indexerStatus == nil {
+		return &discordgo.MessageEmbedField{Name: "📡 Indexer", Value: "Event monitoring disabled"}
+	}
+	status, monitored := h.indexerStatus.IndexerStatus(guildID)
+	return formatIndexerStatusField(status, monitored, time.Now())
+}
+
+// formatIndexerStatusField reports whether the last event query reached the indexer, the block
+// processed up to and how long ago a query last succeeded
+func formatIndexerStatusField(status events.IndexerStatus, monitored bool, now time.Time) *discordgo.MessageEmbedField {
+	field := &discordgo.MessageEmbedField{Name: "📡 Indexer"}
+	switch {
+	case !monitored:
+		field.Value = "No event query loop for this server"
+		return field
+	case !status.Queried:
+		field.Value = "Waiting for the first event query"
+		return field
+	case status.Connected:
+		field.Value = "🟢 Connected"
+	default:
+		field.Value = "🔴 Disconnected"
+		if message := status.LastError; message != "" {
+			if len(message) > maxIndexerErrorLength {
+				message = message[:maxIndexerErrorLength-len("…")] + "…"
+			}
+			field.Value += fmt.Sprintf(": `%s`", message)
+		}
+	}
+
+	field.Value += fmt.Sprintf("\nLast processed block: %d", status.LastProcessedBlock)
+	if status.LastSuccess.IsZero() {
+		field.Value += "\nNo successful query yet"
+	} else {
+		field.Value += fmt.Sprintf("\nLast successful query: %ds ago", int(now.Sub(status.LastSuccess).Seconds()))
+	}
+	return field
+}
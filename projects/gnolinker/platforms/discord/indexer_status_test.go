@@ -0,0 +1,46 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+)
+
+func TestFormatIndexerStatusField_Disconnected(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+
+	field := formatIndexerStatusField(events.IndexerStatus{
+		Queried:            true,
+		LastProcessedBlock: 1234,
+		LastSuccess:        now.Add(-90 * time.Second),
+		LastError:          "dial tcp: connection refused",
+	}, true, now)
+	for _, want := range []string{"🔴 Disconnected", "connection refused", "Last processed block: 1234", "90s ago"} {
+		if !strings.Contains(field.Value, want) {
+			t.Errorf("field = %q, want it to contain %q", field.Value, want)
+		}
+	}
+
+	field = formatIndexerStatusField(events.IndexerStatus{
+		Queried:            true,
+		Connected:          true,
+		LastProcessedBlock: 1250,
+		LastSuccess:        now.Add(-2 * time.Second),
+	}, true, now)
+	if !strings.Contains(field.Value, "🟢 Connected") || !strings.Contains(field.Value, "2s ago") {
+		t.Errorf("field = %q, want connected 2s ago", field.Value)
+	}
+
+	// Long errors are cut to keep the embed within Discord's field limit
+	field = formatIndexerStatusField(events.IndexerStatus{Queried: true, LastError: strings.Repeat("x", 2000)}, true, now)
+	if len(field.Value) > 1024 {
+		t.Errorf("field is %d characters, want at most 1024", len(field.Value))
+	}
+
+	if field := formatIndexerStatusField(events.IndexerStatus{}, false, now); !strings.Contains(field.Value, "No event query loop") {
+		t.Errorf("field for an unmonitored guild = %q", field.Value)
+	}
+}
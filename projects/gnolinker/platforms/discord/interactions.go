@@ -6,17 +6,25 @@ import (
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
 	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
 	"github.com/bwmarrin/discordgo"
 )
 
 // InteractionHandlers contains all interaction-based command handlers
 type InteractionHandlers struct {
-	userLinkingFlow workflows.UserLinkingWorkflow
-	roleLinkingFlow workflows.RoleLinkingWorkflow
-	syncFlow        workflows.SyncWorkflow
-	configManager   *config.ConfigManager
-	logger          core.Logger
+	userLinkingFlow  workflows.UserLinkingWorkflow
+	roleLinkingFlow  workflows.RoleLinkingWorkflow
+	syncFlow         workflows.SyncWorkflow
+	configManager    *config.ConfigManager
+	eventQuerier     EventQuerier
+	roleResyncer     RoleResyncer
+	baselineImporter RoleBaselineImporter
+	realmRefresher   RealmRefresher
+	guildVerifier    GuildVerifier
+	auditReader      storage.AuditReader
+	indexerStatus    IndexerStatusReader
+	logger           core.Logger
 }
 
 // NewInteractionHandlers creates interaction handlers with workflow dependencies
@@ -62,6 +70,14 @@ func (h *InteractionHandlers) GetExpectedCommands() []*discordgo.ApplicationComm
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
 				Name:        "unlink",
 				Description: "Unlink your Discord account from your gno.land address",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "address",
+						Description: "The linked address to unlink, when you have linked several",
+						Required:    false,
+					},
+				},
 			},
 			// Admin subcommand group
 			{
@@ -93,6 +109,25 @@ func (h *InteractionHandlers) GetExpectedCommands() []*discordgo.ApplicationComm
 							},
 						},
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "preview-link",
+						Description: "Preview the Discord role linking a realm role would create or reuse",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "role",
+								Description: "The realm role name",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "realm",
+								Description: "The realm path",
+								Required:    true,
+							},
+						},
+					},
 					{
 						Type:        discordgo.ApplicationCommandOptionSubCommand,
 						Name:        "unlink-role",
@@ -122,13 +157,302 @@ func (h *InteractionHandlers) GetExpectedCommands() []*discordgo.ApplicationComm
 						Name:        "check-orphans",
 						Description: "Find orphaned roles (deleted or unlinked)",
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "selftest",
+						Description: "Run an end-to-end check of the link→role pipeline",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "pending-role",
+						Description: "Set the role held while a link claim is pending (omit to disable)",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionRole,
+								Name:        "role",
+								Description: "The Discord role to grant while linking",
+								Required:    false,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "role-channel",
+						Description: "Scope a realm role to a channel or category (omit the channel to clear)",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "role",
+								Description: "The realm role name",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "realm",
+								Description: "The realm path",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionChannel,
+								Name:        "channel",
+								Description: "The channel or category the role grants access to",
+								Required:    false,
+								ChannelTypes: []discordgo.ChannelType{
+									discordgo.ChannelTypeGuildText,
+									discordgo.ChannelTypeGuildVoice,
+									discordgo.ChannelTypeGuildCategory,
+								},
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "resync-role",
+						Description: "Reconcile the members of one linked realm role with on-chain membership",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "role",
+								Description: "The realm role name",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "realm",
+								Description: "The realm path",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "role-autosync",
+						Description: "Include or exclude a linked realm role from automatic role sync",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "role",
+								Description: "The realm role name",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "realm",
+								Description: "The realm path",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionBoolean,
+								Name:        "enabled",
+								Description: "Whether the bot grants and removes the role automatically",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "realms",
+						Description: "Show the cached monitored realms, optionally re-scanning linked roles first",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionBoolean,
+								Name:        "refresh",
+								Description: "Re-discover the realms from linked roles and save the result",
+								Required:    false,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "add-realm",
+						Description: "Monitor a realm before any of its roles is linked",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "realm",
+								Description: "The realm path (e.g. gno.land/r/demo/events)",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "remove-realm",
+						Description: "Stop monitoring a realm",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "realm",
+								Description: "The realm path",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "import-baseline",
+						Description: "Record current linked role assignments so the first verification pass does not churn",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "approve-link",
+						Description: "Approve a member whose link was held for sharing an address or linking several",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionUser,
+								Name:        "user",
+								Description: "The member whose link to approve",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "verify",
+						Description: "Verify every member's roles now, or preview the changes with a dry run",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionBoolean,
+								Name:        "dry-run",
+								Description: "Report the role changes verification would make without applying them",
+								Required:    false,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "set-verify-tier",
+						Description: "Change how often a verification tier runs and how many members it verifies",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "tier",
+								Description: "The verification tier",
+								Required:    true,
+								Choices: []*discordgo.ApplicationCommandOptionChoice{
+									{Name: "high (online members)", Value: storage.VerificationTierHigh},
+									{Name: "medium (recently active members)", Value: storage.VerificationTierMedium},
+									{Name: "low (all members, incrementally)", Value: storage.VerificationTierLow},
+								},
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "interval",
+								Description: "Time between runs, e.g. 90s, 5m or 1h",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionInteger,
+								Name:        "batch",
+								Description: "Members verified per run (0 for all; the low tier needs at least 1)",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "add-verified-rule",
+						Description: "Grant a role to linked members, optionally only if a realm function returns true",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionRole,
+								Name:        "role",
+								Description: "The Discord role to grant",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "realm",
+								Description: "The realm path of the predicate (e.g. gno.land/r/demo/holders)",
+								Required:    false,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "function",
+								Description: "A realm function taking the linked address and returning a bool",
+								Required:    false,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "list-verified-rules",
+						Description: "List the roles granted to linked members and their conditions",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "role-dms",
+						Description: "Send members a DM when an on-chain event grants or removes their roles",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionBoolean,
+								Name:        "enabled",
+								Description: "Whether members are notified of role changes",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "set-role-style",
+						Description: "Set the color and display of the roles the bot creates",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "color",
+								Description: "Hex color such as #5865F2, or \"default\" for the bot's colors",
+								Required:    false,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionBoolean,
+								Name:        "hoist",
+								Description: "Display members with the roles separately in the member list",
+								Required:    false,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionBoolean,
+								Name:        "mentionable",
+								Description: "Allow anyone to mention the roles",
+								Required:    false,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "audit",
+						Description: "Show the most recent role grants and revokes for a member",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionUser,
+								Name:        "user",
+								Description: "The member whose role changes to show",
+								Required:    true,
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionInteger,
+								Name:        "count",
+								Description: fmt.Sprintf("How many entries to show, up to %d (default %d)", maxAuditEntries, defaultAuditEntries),
+								Required:    false,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "pause",
+						Description: "Pause event processing, verification and role changes for this server",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "resume",
+						Description: "Resume processing for this server after a pause",
+					},
 				},
 			},
 			// Status subcommand
 			{
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
 				Name:        "status",
-				Description: "Show your personal linking status and roles",
+				Description: "Show your linking status, roles and any pending claim",
 			},
 			// Help subcommand
 			{
@@ -375,7 +699,7 @@ func (h *InteractionHandlers) handleSlashCommand(s *discordgo.Session, i *discor
 		case "link":
 			h.handleLinkAddressCommand(s, i, options[0].Options)
 		case "unlink":
-			h.handleUnlinkAddressCommand(s, i)
+			h.handleUnlinkAddressCommand(s, i, options[0].Options)
 		}
 		return
 	}
@@ -396,12 +720,52 @@ func (h *InteractionHandlers) handleSlashCommand(s *discordgo.Session, i *discor
 				h.handleAdminInfoCommand(s, i)
 			case "link-role":
 				h.handleLinkRoleCommand(s, i, subcommand.Options)
+			case "preview-link":
+				h.handleAdminPreviewLinkCommand(s, i, subcommand.Options)
 			case "unlink-role":
 				h.handleUnlinkRoleCommand(s, i, subcommand.Options)
 			case "list-roles":
 				h.handleAdminListRolesCommand(s, i)
 			case "check-orphans":
 				h.handleAdminCheckOrphansCommand(s, i)
+			case "selftest":
+				h.handleAdminSelfTestCommand(s, i)
+			case "pending-role":
+				h.handleAdminPendingRoleCommand(s, i, subcommand.Options)
+			case "role-channel":
+				h.handleAdminRoleChannelCommand(s, i, subcommand.Options)
+			case "resync-role":
+				h.handleAdminResyncRoleCommand(s, i, subcommand.Options)
+			case "role-autosync":
+				h.handleAdminRoleAutoSyncCommand(s, i, subcommand.Options)
+			case "realms":
+				h.handleAdminRealmsCommand(s, i, subcommand.Options)
+			case "add-realm":
+				h.handleAdminRegisterRealmCommand(s, i, subcommand.Options, true)
+			case "remove-realm":
+				h.handleAdminRegisterRealmCommand(s, i, subcommand.Options, false)
+			case "import-baseline":
+				h.handleAdminImportBaselineCommand(s, i)
+			case "approve-link":
+				h.handleAdminApproveLinkCommand(s, i, subcommand.Options)
+			case "verify":
+				h.handleAdminVerifyCommand(s, i, subcommand.Options)
+			case "set-verify-tier":
+				h.handleAdminSetVerifyTierCommand(s, i, subcommand.Options)
+			case "add-verified-rule":
+				h.handleAdminAddVerifiedRuleCommand(s, i, subcommand.Options)
+			case "list-verified-rules":
+				h.handleAdminListVerifiedRulesCommand(s, i)
+			case "role-dms":
+				h.handleAdminRoleDMsCommand(s, i, subcommand.Options)
+			case "set-role-style":
+				h.handleAdminSetRoleStyleCommand(s, i, subcommand.Options)
+			case "audit":
+				h.handleAdminAuditCommand(s, i, subcommand.Options)
+			case "pause":
+				h.handleAdminPauseCommand(s, i, true)
+			case "resume":
+				h.handleAdminPauseCommand(s, i, false)
 			}
 		}
 	}
@@ -421,6 +785,8 @@ func (h *InteractionHandlers) handleLinkAddressCommand(s *discordgo.Session, i *
 
 	// Create response with claim and URL
 	claimURL := h.userLinkingFlow.GetClaimURL(claim)
+	h.recordPendingClaim(s, i.GuildID, userID, claim, address, claimURL)
+
 	embed := &discordgo.MessageEmbed{
 		Title:       "Link Your Account",
 		Description: fmt.Sprintf("Ready to link your Discord account to `%s`", address),
@@ -455,7 +821,7 @@ func (h *InteractionHandlers) handleLinkAddressCommand(s *discordgo.Session, i *
 	}
 }
 
-func (h *InteractionHandlers) handleUnlinkAddressCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func (h *InteractionHandlers) handleUnlinkAddressCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
 	userID := i.Member.User.ID
 
 	// Defer response to prevent timeout
@@ -470,68 +836,12 @@ func (h *InteractionHandlers) handleUnlinkAddressCommand(s *discordgo.Session, i
 		return
 	}
 
-	// 1. Look up the gno address linked to this Discord ID
-	linkedAddress, err := h.userLinkingFlow.GetLinkedAddress(userID)
-	if err != nil {
-		h.logger.Error("Failed to get linked address", "error", err, "user_id", userID)
-		h.followUpError(s, i, "Failed to check linked address.")
-		return
-	}
-
-	// 2. Check if Discord ID has a linked address
-	if linkedAddress == "" {
-		embed := &discordgo.MessageEmbed{
-			Title:       "No Linked Address",
-			Description: "❌ Your Discord account is not linked to any gno.land address. There's nothing to unlink.",
-			Color:       0xff0000,
-		}
-
-		_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-			Embeds: &[]*discordgo.MessageEmbed{embed},
-		})
-		if err != nil {
-			h.logger.Error("Failed to edit response", "error", err, "user_id", userID)
-		}
-		return
-	}
-
-	// 3. Generate unlink claim
-	claim, err := h.userLinkingFlow.GenerateUnlinkClaim(userID, linkedAddress)
-	if err != nil {
-		h.logger.Error("Failed to generate unlink claim", "error", err, "user_id", userID, "address", linkedAddress)
-		h.followUpError(s, i, "Failed to generate unlink claim. Please try again.")
-		return
-	}
-
-	// Create response with claim and URL
-	claimURL := h.userLinkingFlow.GetClaimURL(claim)
-	embed := &discordgo.MessageEmbed{
-		Title:       "Unlink Your Account",
-		Description: fmt.Sprintf("Ready to unlink your Discord account from `%s`", linkedAddress),
-		Color:       0xff9900, // Orange color for unlink
-	}
-
-	// Add button to claim on gno.land
-	components := []discordgo.MessageComponent{
-		discordgo.ActionsRow{
-			Components: []discordgo.MessageComponent{
-				discordgo.Button{
-					Label: "Unlink on gno.land",
-					Style: discordgo.LinkButton,
-					URL:   claimURL,
-					Emoji: &discordgo.ComponentEmoji{
-						Name: "🔓",
-					},
-				},
-			},
-		},
+	var requested string
+	if len(options) > 0 {
+		requested = options[0].StringValue()
 	}
 
-	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-		Embeds:     &[]*discordgo.MessageEmbed{embed},
-		Components: &components,
-	})
-	if err != nil {
+	if _, err := s.InteractionResponseEdit(i.Interaction, h.unlinkResponse(s, i, userID, requested)); err != nil {
 		h.logger.Error("Failed to edit response", "error", err, "user_id", userID)
 	}
 }
@@ -646,16 +956,40 @@ func (h *InteractionHandlers) handleHelpCommand(s *discordgo.Session, i *discord
 			{
 				Name: "👤 User Commands",
 				Value: "`/gnolinker link <address>` - Link your Discord to a gno.land address\n" +
-					"`/gnolinker unlink` - Unlink your Discord from your gno.land address\n" +
-					"`/gnolinker status` - Show your linking status and roles",
+					"`/gnolinker unlink [address]` - Unlink your Discord from your gno.land address\n" +
+					"`/gnolinker status` - Show your linking status, roles and any pending claim",
 			},
 			{
 				Name: "⚙️ Admin Commands",
 				Value: "`/gnolinker admin info` - Show bot configuration and managed roles\n" +
 					"`/gnolinker admin link-role <role> <realm>` - Link realm role to Discord role\n" +
+					"`/gnolinker admin preview-link <role> <realm>` - Show the Discord role linking would create or reuse\n" +
 					"`/gnolinker admin unlink-role <role> <realm>` - Unlink realm role from Discord role\n" +
 					"`/gnolinker admin list-roles` - List all linked roles across all realms\n" +
-					"`/gnolinker admin check-orphans` - Find orphaned roles (deleted or unlinked)",
+					"`/gnolinker admin check-orphans` - Find orphaned roles (deleted or unlinked)\n" +
+					"`/gnolinker admin selftest` - Check indexer, realm queries and role creation end to end\n" +
+					"`/gnolinker admin pending-role [role]` - Set or clear the role held while a link claim is pending\n" +
+					"`/gnolinker admin role-channel <role> <realm> [channel]` - Scope a realm role to a channel or category\n" +
+					"`/gnolinker admin resync-role <role> <realm>` - Reconcile one linked role with on-chain membership",
+			},
+			{
+				Name: "🔄 Admin Commands: Sync and Verification",
+				Value: "`/gnolinker admin role-autosync <role> <realm> <enabled>` - Include or exclude a linked role from automatic sync\n" +
+					"`/gnolinker admin realms [refresh]` - Show the monitored realms, or re-scan linked roles for them\n" +
+					"`/gnolinker admin add-realm <realm>` / `remove-realm <realm>` - Start or stop monitoring a realm\n" +
+					"`/gnolinker admin import-baseline` - Keep existing linked role assignments through their first verification\n" +
+					"`/gnolinker admin approve-link <user>` - Release a member held by link uniqueness rules\n" +
+					"`/gnolinker admin verify [dry-run]` - Verify every member's roles now, or preview the changes\n" +
+					"`/gnolinker admin set-verify-tier <tier> <interval> <batch>` - Change a verification tier's cadence and batch size",
+			},
+			{
+				Name: "🛠️ Admin Commands: Server",
+				Value: "`/gnolinker admin add-verified-rule <role> [realm] [function]` - Grant a role to linked members, optionally by realm predicate\n" +
+					"`/gnolinker admin list-verified-rules` - List the roles granted to linked members\n" +
+					"`/gnolinker admin role-dms <enabled>` - DM members when on-chain events change their roles\n" +
+					"`/gnolinker admin set-role-style [color] [hoist] [mentionable]` - Style the roles the bot creates\n" +
+					"`/gnolinker admin audit <user> [count]` - Show a member's most recent role grants and revokes\n" +
+					"`/gnolinker admin pause` / `resume` - Pause or resume processing for this server",
 			},
 			{
 				Name: "🔑 Permission Types",
@@ -858,14 +1192,129 @@ func (h *InteractionHandlers) handleStatusCommand(s *discordgo.Session, i *disco
 		})
 	}
 
+	// Show any pending claim with an option to revoke it
+	components := []discordgo.MessageComponent{}
+	pendingClaim, err := h.configManager.GetPendingClaim(i.GuildID, userID)
+	if err != nil {
+		h.logger.Error("Failed to get pending claim", "error", err, "user_id", userID)
+	} else if pendingClaim != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "⏳ Pending Claim",
+			Value:  formatPendingClaim(pendingClaim),
+			Inline: false,
+		})
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Revoke Pending Claim",
+					Style:    discordgo.DangerButton,
+					CustomID: "revoke_claim",
+				},
+			},
+		})
+	}
+
 	// Send response
 	if _, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-		Embeds: &[]*discordgo.MessageEmbed{embed},
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
 	}); err != nil {
 		h.logger.Error("Failed to edit response", "error", err, "user_id", userID)
 	}
 }
 
+// memberRoleEditor is the subset of the Discord session used to grant and remove the pending role
+type memberRoleEditor interface {
+	GuildMemberRoleAdd(guildID, userID, roleID string, options ...discordgo.RequestOption) error
+	GuildMemberRoleRemove(guildID, userID, roleID string, options ...discordgo.RequestOption) error
+}
+
+// recordPendingClaim stores a generated claim so the user can review or revoke it via status.
+// Link claims also grant the guild's pending role, if one is configured.
+func (h *InteractionHandlers) recordPendingClaim(s memberRoleEditor, guildID, userID string, claim *core.Claim, address, claimURL string) {
+	pendingClaim := &storage.PendingClaim{
+		Type:      string(claim.Type),
+		Address:   address,
+		ClaimURL:  claimURL,
+		Signature: claim.Signature,
+		CreatedAt: claim.CreatedAt,
+	}
+
+	if claim.Type == core.ClaimTypeUserLink {
+		pendingClaim.RoleGranted = h.grantPendingRole(s, guildID, userID)
+	}
+
+	if err := h.configManager.RecordPendingClaim(guildID, userID, pendingClaim); err != nil {
+		// Non-fatal: the claim URL is still returned to the user
+		h.logger.Warn("Failed to record pending claim", "error", err, "guild_id", guildID, "user_id", userID)
+	}
+}
+
+// grantPendingRole adds the guild's pending role to a user, returning true if it was granted
+func (h *InteractionHandlers) grantPendingRole(s memberRoleEditor, guildID, userID string) bool {
+	guildConfig, err := h.configManager.GetGuildConfig(guildID)
+	if err != nil || !guildConfig.HasPendingRole() {
+		return false
+	}
+
+	if err := s.GuildMemberRoleAdd(guildID, userID, guildConfig.PendingRoleID); err != nil {
+		h.logger.Warn("Failed to grant pending role", "error", err, "guild_id", guildID, "user_id", userID, "role_id", guildConfig.PendingRoleID)
+		return false
+	}
+
+	h.logger.Info("Granted pending role", "guild_id", guildID, "user_id", userID, "role_id", guildConfig.PendingRoleID)
+	return true
+}
+
+// removePendingRole removes the guild's pending role from a user whose claim was revoked
+func (h *InteractionHandlers) removePendingRole(s memberRoleEditor, guildID, userID string) {
+	guildConfig, err := h.configManager.GetGuildConfig(guildID)
+	if err != nil || !guildConfig.HasPendingRole() {
+		return
+	}
+
+	if err := s.GuildMemberRoleRemove(guildID, userID, guildConfig.PendingRoleID); err != nil {
+		h.logger.Warn("Failed to remove pending role", "error", err, "guild_id", guildID, "user_id", userID, "role_id", guildConfig.PendingRoleID)
+	}
+}
+
+// formatPendingClaim renders a pending claim summary for the status embed
+func formatPendingClaim(claim *storage.PendingClaim) string {
+	action := "Link to"
+	if claim.Type == string(core.ClaimTypeUserUnlink) {
+		action = "Unlink from"
+	}
+
+	return fmt.Sprintf("%s `%s`\nCreated <t:%d:R> • Expires <t:%d:R>\n[Open claim](%s)",
+		action, claim.Address, claim.CreatedAt.Unix(), claim.ExpiresAt.Unix(), claim.ClaimURL)
+}
+
+func (h *InteractionHandlers) handleRevokeClaim(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := i.Member.User.ID
+
+	content := "✅ Your pending claim has been revoked. Use `/gnolinker link` to start again."
+	revoked, err := h.configManager.RevokePendingClaim(i.GuildID, userID)
+	if err != nil {
+		h.logger.Error("Failed to revoke pending claim", "error", err, "user_id", userID)
+		content = "❌ Failed to revoke pending claim. Please try again."
+	} else if !revoked {
+		content = "ℹ️ You have no pending claim to revoke."
+	} else {
+		h.removePendingRole(s, i.GuildID, userID)
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+			Embeds:     []*discordgo.MessageEmbed{},
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
 func (h *InteractionHandlers) handleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	customID := i.MessageComponentData().CustomID
 
@@ -883,6 +1332,21 @@ func (h *InteractionHandlers) handleComponent(s *discordgo.Session, i *discordgo
 		return
 	}
 
+	if customID == unlinkAddressSelectID {
+		h.handleUnlinkAddressSelect(s, i)
+		return
+	}
+
+	if customID == "revoke_claim" {
+		h.handleRevokeClaim(s, i)
+		return
+	}
+
+	if page, ok := strings.CutPrefix(customID, listRolesPagePrefix); ok {
+		h.handleListRolesPage(s, i, page)
+		return
+	}
+
 	// Handle confirm_link_{roleName}_{realmPath}
 	if len(customID) > 13 && customID[:13] == "confirm_link_" {
 		h.handleConfirmLinkRole(s, i, customID[13:])
@@ -924,10 +1388,9 @@ func (h *InteractionHandlers) handleConfirmLinkRole(s *discordgo.Session, i *dis
 	}
 
 	// Create or get the Discord role using safe role creation
-	discordRoleName := roleName + "-" + realmPath
-	platformRole, err := h.getOrCreateRole(s, i.GuildID, discordRoleName)
+	platformRole, err := h.getOrCreateLinkedRole(s, i.GuildID, roleName, realmPath)
 	if err != nil {
-		h.logger.Error("Failed to create role", "error", err, "discord_role_name", discordRoleName)
+		h.logger.Error("Failed to create role", "error", err, "role_name", roleName, "realm_path", realmPath)
 		if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: &[]string{"❌ Failed to create Discord role."}[0],
 		}); err != nil {
@@ -1025,39 +1488,77 @@ func parseRoleLinkParams(params string) []string {
 	return parts
 }
 
-// Helper function to get role by name
-func (h *InteractionHandlers) getRoleByName(s *discordgo.Session, guildID, name string) (*core.PlatformRole, error) {
-	roles, err := s.GuildRoles(guildID)
+// getOrCreateLinkedRole resolves the Discord role for a realm role, naming new roles with the
+// guild's role name template and migrating roles that still use the legacy naming scheme
+func (h *InteractionHandlers) getOrCreateLinkedRole(s DiscordSession, guildID, roleName, realmPath string) (*core.PlatformRole, error) {
+	guildConfig, err := h.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild configuration: %w", err)
+	}
+
+	name, legacyName, err := h.linkedRoleNames(guildConfig, roleName, realmPath)
+	if err != nil {
+		return nil, err
+	}
+	roleID, _ := guildConfig.GetLinkedRole(realmPath, roleName)
+
+	roleManager := NewRoleManager(s, h.configManager.GetLockManager(), h.logger)
+	role, err := roleManager.GetOrCreateLinkedRole(guildID, roleID, name, legacyName, h.configManager.GetRoleStyle(guildConfig))
 	if err != nil {
 		return nil, err
 	}
 
-	for _, role := range roles {
-		if role.Name == name {
-			return &core.PlatformRole{
-				ID:   role.ID,
-				Name: role.Name,
-			}, nil
+	// Scoped roles get their channel access set up as soon as they exist
+	if channelIDs := guildConfig.GetRoleChannels(realmPath, roleName); len(channelIDs) > 0 {
+		if err := roleManager.ApplyChannelOverrides(guildID, role.ID, channelIDs); err != nil {
+			h.logger.Warn("Failed to apply channel overrides for linked role", "error", err, "guild_id", guildID, "role_id", role.ID)
 		}
 	}
+	return role, nil
+}
 
-	return nil, fmt.Errorf("role not found")
+// linkedRoleNames returns the name of the Discord role for a realm role, from the guild's role
+// name template, and its name under the legacy naming scheme
+func (h *InteractionHandlers) linkedRoleNames(guildConfig *storage.GuildConfig, roleName, realmPath string) (name, legacyName string, err error) {
+	name, err = core.RenderRoleName(h.configManager.GetRoleNameTemplate(guildConfig), roleName, realmPath)
+	if err != nil {
+		return "", "", err
+	}
+	legacyName, err = core.RenderRoleName(core.LegacyRoleNameTemplate, roleName, realmPath)
+	if err != nil {
+		return "", "", err
+	}
+	return name, legacyName, nil
 }
 
-// getOrCreateRole gets an existing role or creates a new one with distributed locking
-func (h *InteractionHandlers) getOrCreateRole(s *discordgo.Session, guildID, name string) (*core.PlatformRole, error) {
-	// First try to find existing role
-	if role, err := h.getRoleByName(s, guildID, name); err == nil {
-		return role, nil
+// setRoleChannelScope adds a channel or category to a realm role's scope, or clears the scope
+// when channelID is empty, updating the overwrites of a Discord role already linked to it
+func (h *InteractionHandlers) setRoleChannelScope(s DiscordSession, guildID, roleName, realmPath, channelID string) error {
+	guildConfig, err := h.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get guild configuration: %w", err)
 	}
+	roleID, linked := guildConfig.GetLinkedRole(realmPath, roleName)
+	roleManager := NewRoleManager(s, h.configManager.GetLockManager(), h.logger)
 
-	// Create a temporary role manager for this operation
-	lockManager := h.configManager.GetLockManager()
-	roleManager := NewRoleManager(s, lockManager, h.logger)
+	if channelID == "" {
+		channelIDs := guildConfig.GetRoleChannels(realmPath, roleName)
+		if err := h.configManager.ClearRoleChannels(guildID, realmPath, roleName); err != nil {
+			return err
+		}
+		if linked {
+			return roleManager.RemoveChannelOverrides(guildID, roleID, channelIDs)
+		}
+		return nil
+	}
 
-	// Use the role manager to safely create the role
-	defaultColor := 7506394
-	return roleManager.GetOrCreateRole(guildID, name, &defaultColor)
+	if err := h.configManager.AddRoleChannel(guildID, realmPath, roleName, channelID); err != nil {
+		return err
+	}
+	if linked {
+		return roleManager.ApplyChannelOverrides(guildID, roleID, []string{channelID})
+	}
+	return nil
 }
 
 // Helper function to check if user has a role
@@ -1195,6 +1696,31 @@ func (h *InteractionHandlers) handleAdminInfoCommand(s *discordgo.Session, i *di
 		})
 	}
 
+	// Pending role info
+	pendingRoleValue := "Not configured"
+	if guildConfig.HasPendingRole() {
+		pendingRoleValue = fmt.Sprintf("<@&%s>\n`%s`", guildConfig.PendingRoleID, guildConfig.PendingRoleID)
+	}
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:   "Pending Role",
+		Value:  pendingRoleValue,
+		Inline: true,
+	})
+
+	// Processing state
+	processingValue := "Running"
+	if guildConfig.Paused {
+		processingValue = "⏸️ Paused - use `/gnolinker admin resume` to restart"
+	}
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:   "Processing",
+		Value:  processingValue,
+		Inline: true,
+	})
+
+	// Indexer connection, so a stalled event monitor is not mistaken for a quiet chain
+	fields = append(fields, h.indexerStatusField(i.GuildID))
+
 	// Storage info
 	fields = append(fields, &discordgo.MessageEmbedField{
 		Name:   "Storage",
@@ -1218,25 +1744,9 @@ func (h *InteractionHandlers) handleAdminInfoCommand(s *discordgo.Session, i *di
 			Inline: false,
 		})
 	} else {
-		// Group role mappings by realm
-		realmMappings := make(map[string][]*core.RoleMapping)
-		for _, mapping := range roleMappings {
-			realmMappings[mapping.RealmPath] = append(realmMappings[mapping.RealmPath], mapping)
-		}
-
-		// Build role mappings display
-		var roleDisplay strings.Builder
-		for realm, mappings := range realmMappings {
-			roleDisplay.WriteString(fmt.Sprintf("**%s**\n", realm))
-			for _, mapping := range mappings {
-				roleDisplay.WriteString(fmt.Sprintf("• `%s` → <@&%s>\n", mapping.RealmRoleName, mapping.PlatformRole.ID))
-			}
-			roleDisplay.WriteString("\n")
-		}
-
 		fields = append(fields, &discordgo.MessageEmbedField{
 			Name:   fmt.Sprintf("🎭 Managed Roles (%d total)", len(roleMappings)),
-			Value:  roleDisplay.String(),
+			Value:  truncateManagedRoles(formatManagedRoles(guildConfig, roleMappings)),
 			Inline: false,
 		})
 	}
@@ -1267,6 +1777,171 @@ type OrphanedRole struct {
 	RoleMapping *core.RoleMapping
 }
 
+func (h *InteractionHandlers) handleAdminPendingRoleCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// Changing the pending role is bot configuration, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to configure the pending role.")
+		return
+	}
+
+	roleID := ""
+	content := "✅ Pending role disabled. Members no longer receive a role while their link claim is pending."
+	if len(options) > 0 {
+		role := options[0].RoleValue(s, i.GuildID)
+		roleID = role.ID
+		content = fmt.Sprintf("✅ Members will hold <@&%s> while their link claim is pending. It is swapped for the verified role once the link is confirmed on-chain, or removed when the claim expires.", roleID)
+	}
+
+	if err := h.configManager.SetPendingRole(i.GuildID, roleID); err != nil {
+		h.logger.Error("Failed to set pending role", "error", err, "guild_id", i.GuildID, "role_id", roleID)
+		h.respondError(s, i, "Failed to save the pending role.")
+		return
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+func (h *InteractionHandlers) handleAdminRoleChannelCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// Editing channel permissions is bot configuration, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to scope roles to channels.")
+		return
+	}
+
+	roleName := options[0].StringValue()
+	realmPath := options[1].StringValue()
+	channelID := ""
+	content := fmt.Sprintf("✅ Realm role `%s` at `%s` is no longer scoped to channels. Its channel overrides were removed.", roleName, realmPath)
+	if len(options) > 2 {
+		channelID = options[2].ChannelValue(nil).ID
+		content = fmt.Sprintf("✅ Realm role `%s` at `%s` now grants access to <#%s>. The override is applied as soon as the role is linked.", roleName, realmPath, channelID)
+	}
+
+	if err := h.setRoleChannelScope(s, i.GuildID, roleName, realmPath, channelID); err != nil {
+		h.logger.Error("Failed to update role channel scope", "error", err, "guild_id", i.GuildID, "role_name", roleName, "realm_path", realmPath, "channel_id", channelID)
+		h.respondError(s, i, "Failed to update the role's channel access. Check that the bot can manage permissions in that channel.")
+		return
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+func (h *InteractionHandlers) handleAdminApproveLinkCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// Approving a link overrides the guild's identity rules, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to approve links.")
+		return
+	}
+
+	member := options[0].UserValue(nil)
+	approved, err := h.configManager.ApproveLinkConflict(i.GuildID, member.ID)
+	if err != nil {
+		h.logger.Error("Failed to approve link conflict", "error", err, "guild_id", i.GuildID, "user_id", member.ID)
+		h.respondError(s, i, "Failed to approve the link.")
+		return
+	}
+
+	content := fmt.Sprintf("ℹ️ <@%s> has no link awaiting review.", member.ID)
+	if approved {
+		content = fmt.Sprintf("✅ Link approved for <@%s>. Their roles are granted on the next verification.", member.ID)
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+func (h *InteractionHandlers) handleAdminRoleDMsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// Messaging members on the server's behalf is bot configuration, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to configure role change DMs.")
+		return
+	}
+
+	enabled := options[0].BoolValue()
+	if err := h.configManager.SetNotifyRoleChanges(i.GuildID, enabled); err != nil {
+		h.logger.Error("Failed to update role change DMs", "error", err, "guild_id", i.GuildID, "enabled", enabled)
+		h.respondError(s, i, "Failed to save the role change DM setting.")
+		return
+	}
+
+	content := "🔕 Members are no longer sent a DM when on-chain events change their roles."
+	if enabled {
+		content = "🔔 Members are now sent a DM summarizing the roles an on-chain link or role event granted or removed. Members with DMs closed are skipped."
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+func (h *InteractionHandlers) handleAdminPauseCommand(s *discordgo.Session, i *discordgo.InteractionCreate, paused bool) {
+	// Pausing stops all processing for the guild, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to pause or resume processing.")
+		return
+	}
+
+	if err := h.configManager.SetGuildPaused(i.GuildID, paused); err != nil {
+		h.logger.Error("Failed to update guild processing state", "error", err, "guild_id", i.GuildID, "paused", paused)
+		h.respondError(s, i, "Failed to update the processing state.")
+		return
+	}
+
+	content := "▶️ Processing resumed. Events received while paused are picked up from where processing stopped."
+	if paused {
+		content = "⏸️ Processing paused. No events are processed, no members are verified and no roles are changed for this server until `/gnolinker admin resume`."
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
 func (h *InteractionHandlers) handleAdminCheckOrphansCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// Check guild admin permissions
 	userID := i.Member.User.ID
@@ -1350,18 +2025,14 @@ func (h *InteractionHandlers) findOrphanedRoles(linkedRoles []*core.RoleMapping,
 			continue
 		}
 
-		// Check if the role name contains "gno.land/r/"
-		if strings.Contains(discordRole.Name, "gno.land/r/") {
-			// Try to parse the expected format: {roleName}-{realmPath}
-			parts := strings.SplitN(discordRole.Name, "-", 2)
-			if len(parts) == 2 && strings.HasPrefix(parts[1], "gno.land/r/") {
-				orphans = append(orphans, OrphanedRole{
-					Type:        "discord-side",
-					RoleName:    parts[0],
-					RealmPath:   parts[1],
-					DiscordRole: discordRole,
-				})
-			}
+		// Roles in the expected format {roleName}-{realmPath} look like gno roles
+		if roleName, realmPath, ok := core.ParseLegacyRoleName(discordRole.Name); ok {
+			orphans = append(orphans, OrphanedRole{
+				Type:        "discord-side",
+				RoleName:    roleName,
+				RealmPath:   realmPath,
+				DiscordRole: discordRole,
+			})
 		}
 	}
 
@@ -1459,64 +2130,16 @@ func (h *InteractionHandlers) handleAdminListRolesCommand(s *discordgo.Session,
 		return
 	}
 
-	// Get all linked roles for this guild
-	linkedRoles, err := h.roleLinkingFlow.ListAllRolesByGuild(i.GuildID)
+	embed, components, err := h.roleListPage(i.GuildID, 0)
 	if err != nil {
 		h.logger.Error("Failed to list all roles by guild", "error", err, "guild_id", i.GuildID)
 		h.respondDeferredError(s, i, "Failed to retrieve linked roles.")
 		return
 	}
 
-	if len(linkedRoles) == 0 {
-		embed := &discordgo.MessageEmbed{
-			Title:       "No Linked Roles",
-			Description: "No roles have been linked in this guild yet.",
-			Color:       0xFFA500, // Orange
-		}
-		if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-			Embeds: &[]*discordgo.MessageEmbed{embed},
-		}); err != nil {
-			h.logger.Error("Failed to edit interaction response", "error", err)
-		}
-		return
-	}
-
-	// Group roles by realm
-	rolesByRealm := make(map[string][]*core.RoleMapping)
-	for _, role := range linkedRoles {
-		rolesByRealm[role.RealmPath] = append(rolesByRealm[role.RealmPath], role)
-	}
-
-	// Build embed fields
-	fields := make([]*discordgo.MessageEmbedField, 0, len(rolesByRealm))
-	totalRoles := 0
-
-	for realmPath, roles := range rolesByRealm {
-		var roleList string
-		for _, role := range roles {
-			roleList += fmt.Sprintf("• **%s** → <@&%s>\n", role.RealmRoleName, role.PlatformRole.ID)
-			totalRoles++
-		}
-
-		fields = append(fields, &discordgo.MessageEmbedField{
-			Name:   realmPath,
-			Value:  roleList,
-			Inline: false,
-		})
-	}
-
-	embed := &discordgo.MessageEmbed{
-		Title:       "All Linked Roles",
-		Description: fmt.Sprintf("Found **%d** linked roles across **%d** realms:", totalRoles, len(rolesByRealm)),
-		Fields:      fields,
-		Color:       0x5865F2, // Discord blurple
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Use /gnolinker verify role to check specific role status",
-		},
-	}
-
 	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-		Embeds: &[]*discordgo.MessageEmbed{embed},
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
 	}); err != nil {
 		h.logger.Error("Failed to edit interaction response", "error", err)
 	}
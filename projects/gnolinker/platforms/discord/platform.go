@@ -1,11 +1,13 @@
 package discord
 
 import (
+	"context"
 	"fmt"
 	"slices"
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
 	"github.com/allinbits/labs/projects/gnolinker/platforms"
 	"github.com/bwmarrin/discordgo"
 )
@@ -15,17 +17,39 @@ type DiscordPlatform struct {
 	session     *discordgo.Session
 	config      Config
 	roleManager *RoleManager
+	limiter     *RateLimiter
+	// ctx is cancelled by Close, releasing calls waiting on the rate limiter
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // NewDiscordPlatform creates a new Discord platform adapter
 func NewDiscordPlatform(session *discordgo.Session, config Config, lockManager lock.LockManager, logger core.Logger) platforms.Platform {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &DiscordPlatform{
 		session:     session,
 		config:      config,
 		roleManager: NewRoleManager(session, lockManager, logger),
+		limiter:     NewRateLimiter(config.RateLimit),
+		ctx:         ctx,
+		cancel:      cancel,
 	}
 }
 
+// Close releases calls waiting on the rate limiter; later mutating calls fail right away
+func (p *DiscordPlatform) Close() error {
+	p.cancel()
+	return nil
+}
+
+// waitForRateLimit blocks a mutating call until the rate limiter allows it
+func (p *DiscordPlatform) waitForRateLimit() error {
+	if err := p.limiter.Wait(p.ctx); err != nil {
+		return fmt.Errorf("rate limiter: %w", err)
+	}
+	return nil
+}
+
 // GetUserID returns the user ID from a message
 func (p *DiscordPlatform) GetUserID(message platforms.Message) string {
 	return message.GetAuthorID()
@@ -33,6 +57,9 @@ func (p *DiscordPlatform) GetUserID(message platforms.Message) string {
 
 // SendDirectMessage sends a direct message to a user
 func (p *DiscordPlatform) SendDirectMessage(userID, content string) error {
+	if err := p.waitForRateLimit(); err != nil {
+		return err
+	}
 	channel, err := p.session.UserChannelCreate(userID)
 	if err != nil {
 		return fmt.Errorf("failed to create DM channel: %w", err)
@@ -58,25 +85,33 @@ func (p *DiscordPlatform) HasRole(guildID, userID, roleID string) (bool, error)
 
 // AddRole adds a role to a user
 func (p *DiscordPlatform) AddRole(guildID, userID, roleID string) error {
-	err := p.session.GuildMemberRoleAdd(guildID, userID, roleID)
-	if err != nil {
-		return fmt.Errorf("failed to add role: %w", err)
+	err := p.waitForRateLimit()
+	if err == nil {
+		err = p.session.GuildMemberRoleAdd(guildID, userID, roleID)
+		if err != nil {
+			err = fmt.Errorf("failed to add role: %w", err)
+		}
 	}
-	return nil
+	p.config.Metrics.RoleChange("add", err)
+	return err
 }
 
 // RemoveRole removes a role from a user
 func (p *DiscordPlatform) RemoveRole(guildID, userID, roleID string) error {
-	err := p.session.GuildMemberRoleRemove(guildID, userID, roleID)
-	if err != nil {
-		return fmt.Errorf("failed to remove role: %w", err)
+	err := p.waitForRateLimit()
+	if err == nil {
+		err = p.session.GuildMemberRoleRemove(guildID, userID, roleID)
+		if err != nil {
+			err = fmt.Errorf("failed to remove role: %w", err)
+		}
 	}
-	return nil
+	p.config.Metrics.RoleChange("remove", err)
+	return err
 }
 
 // GetOrCreateRole gets an existing role or creates a new one using distributed locking
 func (p *DiscordPlatform) GetOrCreateRole(guildID, name string) (*core.PlatformRole, error) {
-	return p.roleManager.GetOrCreateRole(guildID, name, nil)
+	return p.roleManager.GetOrCreateRole(guildID, name, storage.RoleStyle{})
 }
 
 // GetRoleByID retrieves a role by its ID
@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *InteractionHandlers) handleAdminPreviewLinkCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	userID := i.Member.User.ID
+	hasPermission, err := h.hasRoleAdminPermission(s, i.GuildID, userID)
+	if err != nil || !hasPermission {
+		h.respondError(s, i, "You need either the configured admin role or Discord admin permissions to preview role links.")
+		return
+	}
+
+	roleName := options[0].StringValue()
+	realmPath := options[1].StringValue()
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to defer interaction response", "error", err)
+		return
+	}
+
+	name, action, role, err := h.previewLinkedRole(s, i.GuildID, roleName, realmPath)
+	if err != nil {
+		h.logger.Error("Failed to preview linked role", "error", err, "guild_id", i.GuildID, "realm_path", realmPath, "role_name", roleName)
+		h.respondDeferredError(s, i, "Failed to preview the Discord role. Check the bot logs for details.")
+		return
+	}
+
+	embed := formatLinkPreviewEmbed(roleName, realmPath, name, action, role)
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	}); err != nil {
+		h.logger.Error("Failed to edit interaction response", "error", err)
+	}
+}
+
+// previewLinkedRole reports the name of the Discord role for a realm role and what linking it
+// would do, as getOrCreateLinkedRole resolves it, without changing any role
+func (h *InteractionHandlers) previewLinkedRole(s DiscordSession, guildID, roleName, realmPath string) (string, LinkedRoleAction, *discordgo.Role, error) {
+	guildConfig, err := h.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to get guild configuration: %w", err)
+	}
+
+	name, legacyName, err := h.linkedRoleNames(guildConfig, roleName, realmPath)
+	if err != nil {
+		return "", "", nil, err
+	}
+	roleID, _ := guildConfig.GetLinkedRole(realmPath, roleName)
+
+	roleManager := NewRoleManager(s, h.configManager.GetLockManager(), h.logger)
+	action, role, err := roleManager.PreviewLinkedRole(guildID, roleID, name, legacyName)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return name, action, role, nil
+}
+
+// formatLinkPreviewEmbed reports the Discord role linking a realm role would use
+func formatLinkPreviewEmbed(roleName, realmPath, name string, action LinkedRoleAction, role *discordgo.Role) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: "🔍 Role Link Preview",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Realm Role", Value: fmt.Sprintf("`%s` at `%s`", roleName, realmPath)},
+			{Name: "Discord Role Name", Value: fmt.Sprintf("`%s`", name)},
+		},
+	}
+
+	switch action {
+	case LinkedRoleKeep:
+		embed.Description = fmt.Sprintf("✅ Already linked to <@&%s>. Linking again keeps it.", role.ID)
+		embed.Color = 0x00ff00
+	case LinkedRoleReuse:
+		embed.Description = fmt.Sprintf("⚠️ <@&%s> is already named `%s`. Linking would reuse it, with its current members and permissions. "+
+			"If it was created by hand, rename it first so a new role is created.", role.ID, role.Name)
+		embed.Color = 0xff9900
+	case LinkedRoleRename:
+		embed.Description = fmt.Sprintf("🔁 <@&%s> from the legacy naming scheme would be renamed to `%s` and reused.", role.ID, name)
+		embed.Color = 0xffff00
+	default:
+		embed.Description = fmt.Sprintf("✅ No role is named `%s`, so a new role would be created.", name)
+		embed.Color = 0x00ff00
+	}
+	return embed
+}
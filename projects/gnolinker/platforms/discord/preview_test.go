@@ -0,0 +1,63 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestPreviewLinkedRole_ReportsCollidingRole(t *testing.T) {
+	t.Parallel()
+	handlers, session, configManager, _ := setupInteractionHandlers()
+
+	guildID := "preview-guild"
+	realmPath := "gno.land/r/demo/boards"
+	session.AddGuild(guildID, "owner")
+	if err := configManager.GetStore().Set(guildID, storage.NewGuildConfig(guildID)); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	guildConfig, _ := configManager.GetGuildConfig(guildID)
+	name, _, err := handlers.linkedRoleNames(guildConfig, "admin", realmPath)
+	if err != nil {
+		t.Fatalf("linkedRoleNames() failed: %v", err)
+	}
+
+	// Nothing named like the role yet
+	_, action, role, err := handlers.previewLinkedRole(session, guildID, "admin", realmPath)
+	if err != nil {
+		t.Fatalf("previewLinkedRole() failed: %v", err)
+	}
+	if action != LinkedRoleCreate || role != nil {
+		t.Errorf("preview = %s, %+v, want %s", action, role, LinkedRoleCreate)
+	}
+
+	// A hand-made role with the same name, differently cased, would be reused
+	session.AddRole(guildID, &discordgo.Role{ID: "manual-role", Name: strings.ToUpper(name)})
+	previewName, action, role, err := handlers.previewLinkedRole(session, guildID, "admin", realmPath)
+	if err != nil {
+		t.Fatalf("previewLinkedRole() failed: %v", err)
+	}
+	if previewName != name || action != LinkedRoleReuse || role == nil || role.ID != "manual-role" {
+		t.Errorf("preview = %q, %s, %+v, want %q reusing manual-role", previewName, action, role, name)
+	}
+	embed := formatLinkPreviewEmbed("admin", realmPath, previewName, action, role)
+	if !strings.Contains(embed.Description, "<@&manual-role>") || !strings.Contains(embed.Description, "reuse") {
+		t.Errorf("preview description = %q, want it to warn about reusing <@&manual-role>", embed.Description)
+	}
+
+	// Previewing is read-only
+	if roles, _ := session.GuildRoles(guildID); len(roles) != 1 || roles[0].Name != strings.ToUpper(name) {
+		t.Errorf("guild roles = %+v, want only the untouched manual role", roles)
+	}
+
+	// Once linked, the linked role is kept whatever it is named
+	session.AddRole(guildID, &discordgo.Role{ID: "linked-role", Name: "Moderators"})
+	if err := configManager.RecordLinkedRole(guildID, realmPath, "admin", "linked-role"); err != nil {
+		t.Fatalf("RecordLinkedRole() failed: %v", err)
+	}
+	if _, action, role, err := handlers.previewLinkedRole(session, guildID, "admin", realmPath); err != nil || action != LinkedRoleKeep || role.ID != "linked-role" {
+		t.Errorf("preview = %s, %+v, %v, want %s linked-role", action, role, err, LinkedRoleKeep)
+	}
+}
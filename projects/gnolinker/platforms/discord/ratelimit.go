@@ -0,0 +1,58 @@
+package discord
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is the default number of mutating Discord API calls allowed per second
+const DefaultRateLimit = 5
+
+// RateLimiter spaces mutating Discord API calls evenly, so bulk syncs stay under Discord's
+// rate limits instead of running into 429s. A nil RateLimiter does not limit.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	// next is the earliest time the next call is allowed
+	next time.Time
+}
+
+// NewRateLimiter allows perSecond calls per second. It returns nil, i.e. no limit, if
+// perSecond is not positive.
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &RateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Wait blocks until a call is allowed, or returns ctx's error if ctx is done first
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	allowedAt := l.next
+	if allowedAt.Before(now) {
+		allowedAt = now
+	}
+	l.next = allowedAt.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := allowedAt.Sub(now)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package discord
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+	"github.com/bwmarrin/discordgo"
+)
+
+// timingTransport accepts every request and records when it was made
+type timingTransport struct {
+	mu    sync.Mutex
+	times []time.Time
+}
+
+func (tr *timingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tr.mu.Lock()
+	tr.times = append(tr.times, time.Now())
+	tr.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func newRateLimitedPlatform(t *testing.T, rateLimit float64) (*DiscordPlatform, *timingTransport) {
+	t.Helper()
+	session, err := discordgo.New("Bot test-token")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	transport := &timingTransport{}
+	session.Client = &http.Client{Transport: transport}
+
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	platform := NewDiscordPlatform(session, Config{RateLimit: rateLimit}, lock.NewNoOpLockManager(), logger).(*DiscordPlatform)
+	t.Cleanup(func() { _ = platform.Close() })
+	return platform, transport
+}
+
+func TestDiscordPlatform_RateLimitsRoleMutations(t *testing.T) {
+	t.Parallel()
+	const rateLimit, adds = 1000, 200
+	platform, transport := newRateLimitedPlatform(t, rateLimit)
+
+	var wg sync.WaitGroup
+	for range adds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := platform.AddRole("guild-1", "user-1", "role-1"); err != nil {
+				t.Errorf("AddRole() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(transport.times) != adds {
+		t.Fatalf("made %d requests, want %d", len(transport.times), adds)
+	}
+	first, last := transport.times[0], transport.times[0]
+	for _, at := range transport.times {
+		if at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+	if want := (adds - 1) * time.Second / rateLimit; last.Sub(first) < want {
+		t.Errorf("%d role adds took %v, want at least %v at %d per second", adds, last.Sub(first), want, rateLimit)
+	}
+}
+
+func TestDiscordPlatform_CloseReleasesRateLimitedCalls(t *testing.T) {
+	t.Parallel()
+	platform, _ := newRateLimitedPlatform(t, 0.01)
+
+	if err := platform.RemoveRole("guild-1", "user-1", "role-1"); err != nil {
+		t.Fatalf("first RemoveRole() error = %v", err)
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- platform.RemoveRole("guild-1", "user-1", "role-1") }()
+	time.Sleep(20 * time.Millisecond)
+	_ = platform.Close()
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("rate-limited RemoveRole() should fail once the platform is closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("rate-limited RemoveRole() still blocked after Close")
+	}
+}
+
+func TestNewRateLimiter_Disabled(t *testing.T) {
+	t.Parallel()
+	if NewRateLimiter(0) != nil {
+		t.Error("a zero rate should disable the limiter")
+	}
+	if err := NewRateLimiter(0).Wait(t.Context()); err != nil {
+		t.Errorf("Wait() on a disabled limiter = %v", err)
+	}
+}
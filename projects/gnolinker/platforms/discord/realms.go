@@ -0,0 +1,154 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// RealmRefresher re-discovers the realms monitored for a guild, as done by the event handlers
+type RealmRefresher interface {
+	RefreshMonitoredRealms(guildID string) ([]string, error)
+}
+
+// SetRealmRefresher enables refreshing the monitored realms from the admin realms command
+func (h *InteractionHandlers) SetRealmRefresher(refresher RealmRefresher) {
+	h.realmRefresher = refresher
+}
+
+func (h *InteractionHandlers) handleAdminRealmsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	userID := i.Member.User.ID
+	hasPermission, err := h.hasRoleAdminPermission(s, i.GuildID, userID)
+	if err != nil || !hasPermission {
+		h.respondError(s, i, "You need admin permissions (configured admin role or Discord Administrator) to view monitored realms.")
+		return
+	}
+
+	refresh := len(options) > 0 && options[0].BoolValue()
+	if refresh && h.realmRefresher == nil {
+		h.respondError(s, i, "Refreshing monitored realms requires event monitoring to be enabled.")
+		return
+	}
+
+	var previous []string
+	if refresh {
+		if guildConfig, err := h.configManager.GetGuildConfig(i.GuildID); err == nil {
+			previous = guildConfig.MonitoredRealms
+		}
+		if _, err := h.realmRefresher.RefreshMonitoredRealms(i.GuildID); err != nil {
+			h.logger.Error("Failed to refresh monitored realms", "error", err, "guild_id", i.GuildID)
+			h.respondError(s, i, "Failed to refresh the monitored realms. Check the bot logs for details.")
+			return
+		}
+	}
+
+	guildConfig, err := h.configManager.GetGuildConfig(i.GuildID)
+	if err != nil {
+		h.respondError(s, i, "Failed to get guild configuration.")
+		return
+	}
+
+	embed := formatMonitoredRealmsEmbed(guildConfig, refresh, previous)
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+func (h *InteractionHandlers) handleAdminRegisterRealmCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption, register bool) {
+	// Choosing what the bot monitors is bot configuration, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to manage monitored realms.")
+		return
+	}
+
+	realmPath := strings.TrimSpace(options[0].StringValue())
+	var changed bool
+	if register {
+		changed, err = h.configManager.RegisterRealm(i.GuildID, realmPath)
+	} else {
+		changed, err = h.configManager.UnregisterRealm(i.GuildID, realmPath)
+	}
+	if errors.Is(err, storage.ErrInvalidRealmPath) {
+		h.respondError(s, i, fmt.Sprintf("`%s` is not a realm path: it must start with `gno.land/r/`.", realmPath))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update monitored realms", "error", err, "guild_id", i.GuildID, "realm_path", realmPath, "register", register)
+		h.respondError(s, i, "Failed to save the monitored realms.")
+		return
+	}
+
+	var content string
+	switch {
+	case register && changed:
+		content = fmt.Sprintf("✅ Realm `%s` is now monitored. Roles linked in it are synced by the verification tiers.", realmPath)
+	case register:
+		content = fmt.Sprintf("Realm `%s` is already registered.", realmPath)
+	case changed:
+		content = fmt.Sprintf("✅ Realm `%s` is no longer monitored. If roles are still linked in it, the next realm discovery picks it up again.", realmPath)
+	default:
+		content = fmt.Sprintf("Realm `%s` is not monitored.", realmPath)
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+// formatMonitoredRealmsEmbed lists the cached monitored realms and when they were discovered.
+// Realms registered by an admin are pinned; after a refresh, realms that were not in the previous
+// cache are marked as new.
+func formatMonitoredRealmsEmbed(guildConfig *storage.GuildConfig, refreshed bool, previous []string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: "🛰️ Monitored Realms",
+		Color: 0x5865F2,
+	}
+
+	var realms strings.Builder
+	for _, realmPath := range guildConfig.MonitoredRealms {
+		realms.WriteString(fmt.Sprintf("• `%s`", realmPath))
+		if refreshed && !slices.Contains(previous, realmPath) {
+			realms.WriteString(" 🆕")
+		}
+		if slices.Contains(guildConfig.RegisteredRealms, realmPath) {
+			realms.WriteString(" 📌")
+		}
+		realms.WriteString("\n")
+	}
+	if realms.Len() == 0 {
+		realms.WriteString("None. Realms are discovered from linked roles, or registered with `/gnolinker admin add-realm`.")
+	}
+	embed.Description = realms.String()
+
+	discovered := "Not discovered yet; the next verification discovers them"
+	if !guildConfig.MonitoredRealmsDiscoveredAt.IsZero() {
+		discovered = fmt.Sprintf("<t:%d:R>", guildConfig.MonitoredRealmsDiscoveredAt.Unix())
+	}
+	embed.Fields = []*discordgo.MessageEmbedField{{Name: "Last discovered", Value: discovered}}
+	if refreshed {
+		embed.Color = 0x00ff00
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Refreshed",
+			Value: fmt.Sprintf("Re-scanned linked roles: %d realms monitored, previously %d.", len(guildConfig.MonitoredRealms), len(previous)),
+		})
+	}
+	return embed
+}
@@ -0,0 +1,30 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+func TestFormatMonitoredRealmsEmbed(t *testing.T) {
+	t.Parallel()
+	guildConfig := storage.NewGuildConfig("guild-1")
+
+	embed := formatMonitoredRealmsEmbed(guildConfig, false, nil)
+	if !strings.HasPrefix(embed.Description, "None") || !strings.HasPrefix(embed.Fields[0].Value, "Not discovered yet") {
+		t.Errorf("empty cache rendered as %q / %q", embed.Description, embed.Fields[0].Value)
+	}
+
+	guildConfig.SetMonitoredRealms([]string{"gno.land/r/demo/x", "gno.land/r/demo/boards"})
+	embed = formatMonitoredRealmsEmbed(guildConfig, true, []string{"gno.land/r/demo/x"})
+	if embed.Description != "• `gno.land/r/demo/boards` 🆕\n• `gno.land/r/demo/x`\n" {
+		t.Errorf("description = %q, want sorted realms with the new one marked", embed.Description)
+	}
+	if !strings.HasPrefix(embed.Fields[0].Value, "<t:") {
+		t.Errorf("discovery time = %q, want a Discord timestamp", embed.Fields[0].Value)
+	}
+	if len(embed.Fields) != 2 || !strings.Contains(embed.Fields[1].Value, "2 realms monitored, previously 1") {
+		t.Errorf("refresh summary missing from %+v", embed.Fields)
+	}
+}
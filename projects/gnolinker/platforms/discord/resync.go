@@ -0,0 +1,110 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxResyncMentions bounds the members listed per change type in the resync report
+const maxResyncMentions = 20
+
+// RoleResyncer reconciles a single realm role mapping, as done by the event handlers
+type RoleResyncer interface {
+	ResyncRole(guildID, realmPath, roleName, discordRoleID string) (*events.RoleSyncResult, error)
+}
+
+// SetRoleResyncer enables the admin resync-role command
+func (h *InteractionHandlers) SetRoleResyncer(resyncer RoleResyncer) {
+	h.roleResyncer = resyncer
+}
+
+func (h *InteractionHandlers) handleAdminResyncRoleCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	userID := i.Member.User.ID
+	hasPermission, err := h.hasRoleAdminPermission(s, i.GuildID, userID)
+	if err != nil || !hasPermission {
+		h.respondError(s, i, "You need admin permissions (configured admin role or Discord Administrator) to resync roles.")
+		return
+	}
+	if h.roleResyncer == nil {
+		h.respondError(s, i, "Role resync requires event monitoring to be enabled.")
+		return
+	}
+
+	roleName := options[0].StringValue()
+	realmPath := options[1].StringValue()
+
+	// Defer response as every member of the server is checked
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to defer interaction response", "error", err)
+		return
+	}
+
+	roleMapping, err := h.roleLinkingFlow.GetLinkedRole(realmPath, roleName, i.GuildID)
+	if err != nil || roleMapping == nil {
+		h.respondDeferredError(s, i, fmt.Sprintf("Realm role `%s` at `%s` is not linked to a Discord role.", roleName, realmPath))
+		return
+	}
+
+	if guildConfig, err := h.configManager.GetGuildConfig(i.GuildID); err == nil && !guildConfig.IsRoleAutoSynced(realmPath, roleName) {
+		h.respondDeferredError(s, i, fmt.Sprintf("Realm role `%s` at `%s` is excluded from auto-sync, so the bot leaves its members unchanged. Run `/gnolinker admin role-autosync` to include it first.", roleName, realmPath))
+		return
+	}
+
+	result, err := h.roleResyncer.ResyncRole(i.GuildID, realmPath, roleName, roleMapping.PlatformRole.ID)
+	if err != nil {
+		h.logger.Error("Failed to resync role", "error", err, "guild_id", i.GuildID, "realm_path", realmPath, "role_name", roleName)
+		h.respondDeferredError(s, i, "Failed to resync the role. Check the bot logs for details.")
+		return
+	}
+
+	embed := formatRoleResyncEmbed(roleName, realmPath, roleMapping.PlatformRole.ID, result)
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	}); err != nil {
+		h.logger.Error("Failed to edit interaction response", "error", err)
+	}
+}
+
+// formatRoleResyncEmbed reports the members a role resync granted the role to and removed it from
+func formatRoleResyncEmbed(roleName, realmPath, discordRoleID string, result *events.RoleSyncResult) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: "🔄 Role Resync Complete",
+		Description: fmt.Sprintf("Reconciled <@&%s> with realm role `%s` at `%s`.\nChecked %d members.",
+			discordRoleID, roleName, realmPath, result.Checked),
+		Color: 0x00ff00,
+	}
+	if result.Failed > 0 {
+		embed.Color = 0xffa500
+		embed.Description += fmt.Sprintf("\n⚠️ %d members could not be checked or updated; they are retried by the next verification.", result.Failed)
+	}
+
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: fmt.Sprintf("➕ Added (%d)", len(result.Added)), Value: formatMentions(result.Added)},
+		{Name: fmt.Sprintf("➖ Removed (%d)", len(result.Removed)), Value: formatMentions(result.Removed)},
+	}
+	return embed
+}
+
+// formatMentions lists user mentions, truncated to maxResyncMentions
+func formatMentions(userIDs []string) string {
+	if len(userIDs) == 0 {
+		return "None"
+	}
+
+	var mentions []string
+	for _, userID := range userIDs[:min(len(userIDs), maxResyncMentions)] {
+		mentions = append(mentions, "<@"+userID+">")
+	}
+	if len(userIDs) > maxResyncMentions {
+		mentions = append(mentions, fmt.Sprintf("and %d more", len(userIDs)-maxResyncMentions))
+	}
+	return strings.Join(mentions, ", ")
+}
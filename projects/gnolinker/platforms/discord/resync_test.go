@@ -0,0 +1,32 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+)
+
+func TestFormatRoleResyncEmbed(t *testing.T) {
+	t.Parallel()
+
+	var added []string
+	for i := range maxResyncMentions + 2 {
+		added = append(added, "user-"+string(rune('a'+i)))
+	}
+	result := &events.RoleSyncResult{Checked: 30, Added: added, Failed: 1}
+
+	embed := formatRoleResyncEmbed("member", "gno.land/r/demo/x", "role-1", result)
+	if !strings.Contains(embed.Description, "<@&role-1>") || !strings.Contains(embed.Description, "Checked 30 members") {
+		t.Errorf("unexpected description %q", embed.Description)
+	}
+	if !strings.Contains(embed.Description, "1 members could not be checked") {
+		t.Errorf("description should report failures, got %q", embed.Description)
+	}
+	if embed.Fields[0].Name != "➕ Added (22)" || !strings.HasSuffix(embed.Fields[0].Value, "and 2 more") {
+		t.Errorf("added field = %q: %q", embed.Fields[0].Name, embed.Fields[0].Value)
+	}
+	if embed.Fields[1].Value != "None" {
+		t.Errorf("removed field = %q, want None", embed.Fields[1].Value)
+	}
+}
@@ -2,12 +2,14 @@ package discord
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -16,8 +18,17 @@ type DiscordSession interface {
 	GuildRoles(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Role, error)
 	GuildRoleCreate(guildID string, data *discordgo.RoleParams, options ...discordgo.RequestOption) (*discordgo.Role, error)
 	GuildRoleDelete(guildID, roleID string, options ...discordgo.RequestOption) error
+	GuildRoleEdit(guildID, roleID string, data *discordgo.RoleParams, options ...discordgo.RequestOption) (*discordgo.Role, error)
+	ChannelPermissionSet(channelID, targetID string, targetType discordgo.PermissionOverwriteType, allow, deny int64, options ...discordgo.RequestOption) error
+	ChannelPermissionDelete(channelID, targetID string, options ...discordgo.RequestOption) error
 }
 
+// scopedRolePermissions are granted to a channel-scoped role in each of its channels
+const scopedRolePermissions = discordgo.PermissionViewChannel |
+	discordgo.PermissionSendMessages |
+	discordgo.PermissionReadMessageHistory |
+	discordgo.PermissionVoiceConnect
+
 // RoleManager handles Discord role creation with distributed locking
 type RoleManager struct {
 	session               DiscordSession
@@ -46,8 +57,9 @@ func NewRoleManagerWithConfig(session DiscordSession, lockManager lock.LockManag
 	}
 }
 
-// GetOrCreateRole safely gets an existing role or creates a new one with distributed locking
-func (rm *RoleManager) GetOrCreateRole(guildID, name string, color *int) (*core.PlatformRole, error) {
+// GetOrCreateRole safely gets an existing role or creates a new one with distributed locking.
+// New roles are created with the given style; existing roles are left as they are.
+func (rm *RoleManager) GetOrCreateRole(guildID, name string, style storage.RoleStyle) (*core.PlatformRole, error) {
 	// First try to find existing role
 	if role, err := rm.getRoleByName(guildID, name); err == nil {
 		rm.logger.Debug("Found existing role", "guild_id", guildID, "role_name", name, "role_id", role.ID)
@@ -59,20 +71,134 @@ func (rm *RoleManager) GetOrCreateRole(guildID, name string, color *int) (*core.
 
 	// Role doesn't exist, create it with locking if available
 	if rm.lockManager != nil {
-		return rm.createRoleWithLock(guildID, name, color)
+		return rm.createRoleWithLock(guildID, name, style)
 	}
 
 	// Fallback to direct creation if no lock manager
-	return rm.createRole(guildID, name, color)
+	return rm.createRole(guildID, name, style)
+}
+
+// GetOrCreateLinkedRole resolves the Discord role for a realm role. The persisted role ID is
+// preferred so renamed roles keep working; otherwise the role is looked up by name, and a role
+// still carrying legacyName is renamed to name before a new role would be created.
+func (rm *RoleManager) GetOrCreateLinkedRole(guildID, roleID, name, legacyName string, style storage.RoleStyle) (*core.PlatformRole, error) {
+	if roleID != "" {
+		if role, err := rm.getRoleByID(guildID, roleID); err == nil {
+			return &core.PlatformRole{
+				ID:   role.ID,
+				Name: role.Name,
+			}, nil
+		}
+		rm.logger.Warn("Linked Discord role no longer exists, resolving by name", "guild_id", guildID, "role_id", roleID, "role_name", name)
+	}
+
+	if legacyName != "" && !strings.EqualFold(legacyName, name) {
+		if _, err := rm.getRoleByName(guildID, name); err != nil {
+			if legacy, err := rm.getRoleByName(guildID, legacyName); err == nil {
+				return rm.renameRole(guildID, legacy, name)
+			}
+		}
+	}
+
+	return rm.GetOrCreateRole(guildID, name, style)
+}
+
+// LinkedRoleAction is what resolving the Discord role for a realm role does to the guild's roles
+type LinkedRoleAction string
+
+const (
+	// LinkedRoleKeep uses the role the realm role is already linked to
+	LinkedRoleKeep LinkedRoleAction = "keep"
+	// LinkedRoleReuse uses an existing role with the same name, which may have been made by hand
+	LinkedRoleReuse LinkedRoleAction = "reuse"
+	// LinkedRoleRename renames a role still named with the legacy naming scheme
+	LinkedRoleRename LinkedRoleAction = "rename"
+	// LinkedRoleCreate creates a new role
+	LinkedRoleCreate LinkedRoleAction = "create"
+)
+
+// PreviewLinkedRole reports what GetOrCreateLinkedRole would do, and the existing role it would
+// use if any, without changing any role
+func (rm *RoleManager) PreviewLinkedRole(guildID, roleID, name, legacyName string) (LinkedRoleAction, *discordgo.Role, error) {
+	roles, err := rm.session.GuildRoles(guildID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get guild roles: %w", err)
+	}
+
+	if roleID != "" {
+		for _, role := range roles {
+			if role.ID == roleID {
+				return LinkedRoleKeep, role, nil
+			}
+		}
+	}
+	if role := findRoleByName(roles, name); role != nil {
+		return LinkedRoleReuse, role, nil
+	}
+	if legacyName != "" && !strings.EqualFold(legacyName, name) {
+		if role := findRoleByName(roles, legacyName); role != nil {
+			return LinkedRoleRename, role, nil
+		}
+	}
+	return LinkedRoleCreate, nil, nil
+}
+
+// ApplyChannelOverrides grants a role access to each channel or category through a role permission
+// overwrite. Overwrites set on a category apply to channels synced with it. All channels are
+// attempted; failures are returned together.
+func (rm *RoleManager) ApplyChannelOverrides(guildID, roleID string, channelIDs []string) error {
+	var errs []error
+	for _, channelID := range channelIDs {
+		if err := rm.session.ChannelPermissionSet(channelID, roleID, discordgo.PermissionOverwriteTypeRole, scopedRolePermissions, 0); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channelID, err))
+			continue
+		}
+		rm.logger.Info("Applied channel override for role", "guild_id", guildID, "role_id", roleID, "channel_id", channelID)
+	}
+	return errors.Join(errs...)
+}
+
+// RemoveChannelOverrides deletes the role's permission overwrites from each channel or category
+func (rm *RoleManager) RemoveChannelOverrides(guildID, roleID string, channelIDs []string) error {
+	var errs []error
+	for _, channelID := range channelIDs {
+		if err := rm.session.ChannelPermissionDelete(channelID, roleID); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channelID, err))
+			continue
+		}
+		rm.logger.Info("Removed channel override for role", "guild_id", guildID, "role_id", roleID, "channel_id", channelID)
+	}
+	return errors.Join(errs...)
+}
+
+// renameRole migrates an existing role to a new name, keeping the old name if the edit fails
+func (rm *RoleManager) renameRole(guildID string, role *discordgo.Role, name string) (*core.PlatformRole, error) {
+	renamed, err := rm.session.GuildRoleEdit(guildID, role.ID, &discordgo.RoleParams{Name: name})
+	if err != nil {
+		rm.logger.Warn("Failed to rename legacy role, keeping its name", "guild_id", guildID, "role_id", role.ID, "role_name", role.Name, "error", err)
+		return &core.PlatformRole{
+			ID:   role.ID,
+			Name: role.Name,
+		}, nil
+	}
+
+	rm.logger.Info("Renamed legacy Discord role", "guild_id", guildID, "role_id", role.ID, "old_name", role.Name, "new_name", renamed.Name)
+	return &core.PlatformRole{
+		ID:   renamed.ID,
+		Name: renamed.Name,
+	}, nil
 }
 
+// roleCreationLockTTL is how long a role creation lock lasts between renewals
+const roleCreationLockTTL = 30 * time.Second
+
 // createRoleWithLock creates a role using distributed locking
-func (rm *RoleManager) createRoleWithLock(guildID, name string, color *int) (*core.PlatformRole, error) {
+func (rm *RoleManager) createRoleWithLock(guildID, name string, style storage.RoleStyle) (*core.PlatformRole, error) {
 	ctx := context.Background()
 	lockKey := fmt.Sprintf("role:create:%s:%s", guildID, strings.ReplaceAll(name, " ", "-"))
 
 	// Try to acquire lock
-	lockAcquired, err := rm.lockManager.AcquireLock(ctx, lockKey, 30*time.Second)
+	lockAcquired, err := rm.lockManager.AcquireLock(ctx, lockKey, roleCreationLockTTL)
 	if err != nil {
 		// Failed to get lock, wait and re-check if role was created
 		rm.logger.Warn("Failed to acquire lock for role creation, retrying", "guild_id", guildID, "role_name", name, "error", err)
@@ -90,8 +216,12 @@ func (rm *RoleManager) createRoleWithLock(guildID, name string, color *int) (*co
 		return nil, fmt.Errorf("could not acquire lock for role creation: %w", err)
 	}
 
-	// Always release lock when done
+	// Keep the lock while Discord is slow to answer, and always release it when done
+	stopRenewal := lock.KeepAlive(ctx, rm.lockManager, lockAcquired, roleCreationLockTTL)
 	defer func() {
+		if renewErr := stopRenewal(); renewErr != nil {
+			rm.logger.Warn("Lost lock while creating role", "lock_key", lockKey, "error", renewErr)
+		}
 		if releaseErr := rm.lockManager.ReleaseLock(ctx, lockAcquired); releaseErr != nil {
 			rm.logger.Warn("Failed to release lock", "lock_key", lockKey, "error", releaseErr)
 		}
@@ -107,22 +237,15 @@ func (rm *RoleManager) createRoleWithLock(guildID, name string, color *int) (*co
 	}
 
 	// Safe to create role now
-	return rm.createRole(guildID, name, color)
+	return rm.createRole(guildID, name, style)
 }
 
-// createRole creates a new Discord role
-func (rm *RoleManager) createRole(guildID, name string, color *int) (*core.PlatformRole, error) {
-	defaultColor := 7506394
-	if color == nil {
-		color = &defaultColor
-	}
-
-	roleData := &discordgo.RoleParams{
-		Name:  name,
-		Color: color,
-	}
+// defaultRoleColor is the color of created roles when the guild has not configured one
+const defaultRoleColor = 7506394
 
-	role, err := rm.session.GuildRoleCreate(guildID, roleData)
+// createRole creates a new Discord role
+func (rm *RoleManager) createRole(guildID, name string, style storage.RoleStyle) (*core.PlatformRole, error) {
+	role, err := rm.session.GuildRoleCreate(guildID, roleParams(name, style))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Discord role: %w", err)
 	}
@@ -134,6 +257,26 @@ func (rm *RoleManager) createRole(guildID, name string, color *int) (*core.Platf
 	}, nil
 }
 
+// roleParams returns the parameters creating a role named name in the given style. Unset flags
+// are left to Discord's defaults.
+func roleParams(name string, style storage.RoleStyle) *discordgo.RoleParams {
+	color := defaultRoleColor
+	if style.Color != nil {
+		color = *style.Color
+	}
+	params := &discordgo.RoleParams{
+		Name:  name,
+		Color: &color,
+	}
+	if style.Hoist {
+		params.Hoist = &style.Hoist
+	}
+	if style.Mentionable {
+		params.Mentionable = &style.Mentionable
+	}
+	return params
+}
+
 // getRoleByName finds a role by name in the guild
 func (rm *RoleManager) getRoleByName(guildID, roleName string) (*discordgo.Role, error) {
 	roles, err := rm.session.GuildRoles(guildID)
@@ -141,13 +284,38 @@ func (rm *RoleManager) getRoleByName(guildID, roleName string) (*discordgo.Role,
 		return nil, fmt.Errorf("failed to get guild roles: %w", err)
 	}
 
+	if role := findRoleByName(roles, roleName); role != nil {
+		return role, nil
+	}
+
+	return nil, fmt.Errorf("role not found: %s", roleName)
+}
+
+// findRoleByName returns the role with a name, compared case-insensitively as Discord users read
+// role names, or nil
+func findRoleByName(roles []*discordgo.Role, roleName string) *discordgo.Role {
 	for _, role := range roles {
 		if strings.EqualFold(role.Name, roleName) {
+			return role
+		}
+	}
+	return nil
+}
+
+// getRoleByID finds a role by ID in the guild
+func (rm *RoleManager) getRoleByID(guildID, roleID string) (*discordgo.Role, error) {
+	roles, err := rm.session.GuildRoles(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild roles: %w", err)
+	}
+
+	for _, role := range roles {
+		if role.ID == roleID {
 			return role, nil
 		}
 	}
 
-	return nil, fmt.Errorf("role not found: %s", roleName)
+	return nil, fmt.Errorf("role not found: %s", roleID)
 }
 
 // DeleteRole safely deletes a role with optional locking
@@ -10,6 +10,7 @@ import (
 
 	"github.com/allinbits/labs/projects/gnolinker/core"
 	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -57,7 +58,7 @@ func TestRoleManager_GetOrCreateRole_ExistingRole(t *testing.T) {
 	session.AddRole(guildID, existingRole)
 
 	// Should return existing role
-	role, err := rm.GetOrCreateRole(guildID, roleName, nil)
+	role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 	if err != nil {
 		t.Fatalf("GetOrCreateRole() failed: %v", err)
 	}
@@ -92,7 +93,7 @@ func TestRoleManager_GetOrCreateRole_CreateNew(t *testing.T) {
 	color := 0xFF0000
 
 	// Should create new role
-	role, err := rm.GetOrCreateRole(guildID, roleName, &color)
+	role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{Color: &color})
 	if err != nil {
 		t.Fatalf("GetOrCreateRole() failed: %v", err)
 	}
@@ -139,7 +140,7 @@ func TestRoleManager_GetOrCreateRole_NoLockManager(t *testing.T) {
 	roleName := "TestRole"
 
 	// Should still work without lock manager
-	role, err := rm.GetOrCreateRole(guildID, roleName, nil)
+	role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 	if err != nil {
 		t.Fatalf("GetOrCreateRole() without lock manager failed: %v", err)
 	}
@@ -176,7 +177,7 @@ func TestRoleManager_GetOrCreateRole_LockAcquisitionFails(t *testing.T) {
 	}
 
 	// Should fail to get lock and return error
-	role, err := rm.GetOrCreateRole(guildID, roleName, nil)
+	role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 	if err == nil {
 		t.Fatal("GetOrCreateRole() should fail when lock acquisition fails")
 	}
@@ -227,7 +228,7 @@ func TestRoleManager_GetOrCreateRole_RoleCreatedByOtherInstance(t *testing.T) {
 	}()
 
 	// Should find the role created by "other instance"
-	role, err := rm.GetOrCreateRole(guildID, roleName, nil)
+	role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 	if err != nil {
 		t.Fatalf("GetOrCreateRole() failed: %v", err)
 	}
@@ -267,7 +268,7 @@ func TestRoleManager_GetOrCreateRole_DoubleCheckAfterLock(t *testing.T) {
 	}()
 
 	// Should either create new role or find the one created by goroutine
-	role, err := rm.GetOrCreateRole(guildID, roleName, nil)
+	role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 	if err != nil {
 		t.Fatalf("GetOrCreateRole() failed: %v", err)
 	}
@@ -300,7 +301,7 @@ func TestRoleManager_CreateRole_DefaultColor(t *testing.T) {
 	roleName := "ColorRole"
 
 	// Should use default color when nil provided
-	_, err := rm.GetOrCreateRole(guildID, roleName, nil)
+	_, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 	if err != nil {
 		t.Fatalf("GetOrCreateRole() failed: %v", err)
 	}
@@ -339,7 +340,7 @@ func TestRoleManager_CreateRole_DiscordError(t *testing.T) {
 	roleName := "ErrorRole"
 
 	// Should return Discord error
-	role, err := rm.GetOrCreateRole(guildID, roleName, nil)
+	role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 	if err == nil {
 		t.Fatal("GetOrCreateRole() should fail when Discord returns error")
 	}
@@ -434,7 +435,7 @@ func TestRoleManager_GetRoleByName_CaseInsensitive(t *testing.T) {
 	})
 
 	// Should find role with different case
-	role, err := rm.GetOrCreateRole(guildID, "VERIFIED", nil)
+	role, err := rm.GetOrCreateRole(guildID, "VERIFIED", storage.RoleStyle{})
 	if err != nil {
 		t.Fatalf("GetOrCreateRole() failed: %v", err)
 	}
@@ -460,7 +461,7 @@ func TestRoleManager_GetRoleByName_GuildRolesError_WithLocking(t *testing.T) {
 
 	// Should succeed by creating the role, since GuildRoles error is ignored
 	// in the double-check (if err == nil logic)
-	role, err := rm.GetOrCreateRole(guildID, roleName, nil)
+	role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 	if err != nil {
 		t.Fatalf("GetOrCreateRole() should succeed even when GuildRoles fails: %v", err)
 	}
@@ -495,7 +496,7 @@ func TestRoleManager_GetRoleByName_GuildRolesError_NoLocking(t *testing.T) {
 
 	// Without locking, it should still succeed by creating the role
 	// (since GuildRoleCreate doesn't use GuildRoles)
-	role, err := rm.GetOrCreateRole(guildID, roleName, nil)
+	role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 	if err != nil {
 		t.Fatalf("GetOrCreateRole() should succeed when only GuildRoles fails: %v", err)
 	}
@@ -529,7 +530,7 @@ func TestRoleManager_ConcurrentRoleCreation(t *testing.T) {
 		wg.Add(1)
 		go func(index int) {
 			defer wg.Done()
-			role, err := rm.GetOrCreateRole(guildID, roleName, nil)
+			role, err := rm.GetOrCreateRole(guildID, roleName, storage.RoleStyle{})
 			results[index] = role
 			errors[index] = err
 		}(i)
@@ -585,3 +586,61 @@ func TestRoleManager_ConcurrentRoleCreation(t *testing.T) {
 		t.Error("Should log either role creation or finding existing role")
 	}
 }
+
+func TestRoleManager_GetOrCreateLinkedRole(t *testing.T) {
+	t.Parallel()
+	guildID := "test-guild-123"
+
+	t.Run("prefers persisted role ID over name", func(t *testing.T) {
+		t.Parallel()
+		session := NewMockDiscordSession()
+		rm := NewRoleManager(session, lock.NewNoOpLockManager(), NewMockLogger())
+		session.AddRole(guildID, &discordgo.Role{ID: "renamed-role", Name: "Moderators"})
+
+		role, err := rm.GetOrCreateLinkedRole(guildID, "renamed-role", "admin (boards)", "admin-gno.land/r/demo/boards", storage.RoleStyle{})
+		if err != nil {
+			t.Fatalf("GetOrCreateLinkedRole() failed: %v", err)
+		}
+		if role.ID != "renamed-role" {
+			t.Errorf("Role ID = %s, want renamed-role", role.ID)
+		}
+	})
+
+	t.Run("renames legacy role", func(t *testing.T) {
+		t.Parallel()
+		session := NewMockDiscordSession()
+		rm := NewRoleManager(session, lock.NewNoOpLockManager(), NewMockLogger())
+		session.AddRole(guildID, &discordgo.Role{ID: "legacy-role", Name: "admin-gno.land/r/demo/boards"})
+
+		role, err := rm.GetOrCreateLinkedRole(guildID, "", "admin (boards)", "admin-gno.land/r/demo/boards", storage.RoleStyle{})
+		if err != nil {
+			t.Fatalf("GetOrCreateLinkedRole() failed: %v", err)
+		}
+		if role.ID != "legacy-role" || role.Name != "admin (boards)" {
+			t.Errorf("Role = %+v, want legacy-role renamed to %q", role, "admin (boards)")
+		}
+
+		roles, _ := session.GuildRoles(guildID)
+		if len(roles) != 1 {
+			t.Errorf("expected the legacy role to be reused, got %d roles", len(roles))
+		}
+	})
+
+	t.Run("creates role when stale ID and no name match", func(t *testing.T) {
+		t.Parallel()
+		session := NewMockDiscordSession()
+		logger := NewMockLogger()
+		rm := NewRoleManager(session, lock.NewNoOpLockManager(), logger)
+
+		role, err := rm.GetOrCreateLinkedRole(guildID, "deleted-role", "admin (boards)", "admin-gno.land/r/demo/boards", storage.RoleStyle{})
+		if err != nil {
+			t.Fatalf("GetOrCreateLinkedRole() failed: %v", err)
+		}
+		if role.Name != "admin (boards)" {
+			t.Errorf("Role Name = %s, want %q", role.Name, "admin (boards)")
+		}
+		if !logger.HasMessage("WARN", "Linked Discord role no longer exists") {
+			t.Error("Should warn that the persisted role is gone")
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package discord
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord embed limits, see https://discord.com/developers/docs/resources/message#embed-object-embed-limits
+const (
+	maxEmbedFieldValue = 1024
+	maxEmbedFieldName  = 256
+)
+
+// Page budget of the role listing: a page holds at most this many characters of role lines and
+// this many fields, well within the 6000 character and 25 field limits of an embed
+const (
+	maxRolePageChars  = 3000
+	maxRolePageFields = 10
+)
+
+// listRolesPagePrefix prefixes the custom ID of the role listing's page buttons, followed by the
+// page the button shows
+const listRolesPagePrefix = "list_roles_page_"
+
+// rolePage is one page of the role listing: fields of role lines grouped by realm
+type rolePage []*discordgo.MessageEmbedField
+
+// paginateRoleMappings splits role mappings into pages of realm fields within Discord's embed limits.
+// Mappings are sorted by realm and role so that pages are stable between button presses; a realm
+// spanning several fields or pages is continued under the same name.
+func paginateRoleMappings(guildConfig *storage.GuildConfig, roleMappings []*core.RoleMapping) []rolePage {
+	mappings := slices.SortedFunc(slices.Values(roleMappings), func(a, b *core.RoleMapping) int {
+		return cmp.Or(cmp.Compare(a.RealmPath, b.RealmPath), cmp.Compare(a.RealmRoleName, b.RealmRoleName))
+	})
+
+	var pages []rolePage
+	var page rolePage
+	var field *discordgo.MessageEmbedField
+	pageChars := 0
+	for _, mapping := range mappings {
+		line := fmt.Sprintf("• **%s** → <@&%s>%s\n", mapping.RealmRoleName, mapping.PlatformRole.ID, autoSyncMarker(guildConfig, mapping))
+		if len(line) > maxEmbedFieldValue {
+			line = line[:maxEmbedFieldValue-len("…\n")] + "…\n"
+		}
+
+		if page != nil && pageChars+len(line) > maxRolePageChars {
+			pages = append(pages, page)
+			page, field, pageChars = nil, nil, 0
+		}
+		name := truncateFieldName(mapping.RealmPath)
+		if field == nil || field.Name != name || len(field.Value)+len(line) > maxEmbedFieldValue {
+			if len(page) == maxRolePageFields {
+				pages = append(pages, page)
+				page, pageChars = nil, 0
+			}
+			field = &discordgo.MessageEmbedField{Name: name}
+			page = append(page, field)
+		}
+		field.Value += line
+		pageChars += len(line)
+	}
+	if page != nil {
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+func truncateFieldName(name string) string {
+	if len(name) <= maxEmbedFieldName {
+		return name
+	}
+	return name[:maxEmbedFieldName-len("…")] + "…"
+}
+
+// formatRoleListPage renders a page of the role listing, with Prev/Next buttons when there are
+// several pages. The page is clamped to the available pages.
+func formatRoleListPage(pages []rolePage, page, totalRoles, totalRealms int) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	page = max(0, min(page, len(pages)-1))
+	embed := &discordgo.MessageEmbed{
+		Title:       "All Linked Roles",
+		Description: fmt.Sprintf("Found **%d** linked roles across **%d** realms:", totalRoles, totalRealms),
+		Fields:      pages[page],
+		Color:       0x5865F2, // Discord blurple
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Use /gnolinker verify role to check specific role status",
+		},
+	}
+	if len(pages) == 1 {
+		return embed, []discordgo.MessageComponent{}
+	}
+
+	embed.Footer.Text = fmt.Sprintf("Page %d of %d • %s", page+1, len(pages), embed.Footer.Text)
+	// Each button carries the page it shows; the disabled button at an edge points past it
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "◀ Prev",
+					Style:    discordgo.SecondaryButton,
+					CustomID: listRolesPagePrefix + strconv.Itoa(page-1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next ▶",
+					Style:    discordgo.SecondaryButton,
+					CustomID: listRolesPagePrefix + strconv.Itoa(page+1),
+					Disabled: page == len(pages)-1,
+				},
+			},
+		},
+	}
+	return embed, components
+}
+
+// roleListPage fetches the guild's role mappings and renders a page of the role listing
+func (h *InteractionHandlers) roleListPage(guildID string, page int) (*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	linkedRoles, err := h.roleLinkingFlow.ListAllRolesByGuild(guildID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(linkedRoles) == 0 {
+		return &discordgo.MessageEmbed{
+			Title:       "No Linked Roles",
+			Description: "No roles have been linked in this guild yet.",
+			Color:       0xFFA500, // Orange
+		}, []discordgo.MessageComponent{}, nil
+	}
+
+	// Roles excluded from auto-sync are still listed, marked as manual
+	guildConfig, err := h.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		h.logger.Warn("Failed to get guild config for role listing", "error", err, "guild_id", guildID)
+	}
+
+	realms := make(map[string]bool)
+	for _, role := range linkedRoles {
+		realms[role.RealmPath] = true
+	}
+	embed, components := formatRoleListPage(paginateRoleMappings(guildConfig, linkedRoles), page, len(linkedRoles), len(realms))
+	return embed, components, nil
+}
+
+// handleListRolesPage shows another page of the role listing in place
+func (h *InteractionHandlers) handleListRolesPage(s *discordgo.Session, i *discordgo.InteractionCreate, pageParam string) {
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to list all roles.")
+		return
+	}
+
+	page, err := strconv.Atoi(pageParam)
+	if err != nil {
+		h.respondError(s, i, "Invalid page.")
+		return
+	}
+
+	embed, components, err := h.roleListPage(i.GuildID, page)
+	if err != nil {
+		h.logger.Error("Failed to list all roles by guild", "error", err, "guild_id", i.GuildID)
+		h.respondError(s, i, "Failed to retrieve linked roles.")
+		return
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+// truncateManagedRoles cuts a role display to fit an embed field, noting how many roles were left out
+func truncateManagedRoles(display string) string {
+	if len(display) <= maxEmbedFieldValue {
+		return display
+	}
+
+	remaining := strings.Count(display, "\n•")
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(display, "\n") {
+		more := fmt.Sprintf("…and %d more; see `/gnolinker admin list-roles`", remaining)
+		if b.Len()+len(line)+len(more) > maxEmbedFieldValue {
+			b.WriteString(more)
+			break
+		}
+		b.WriteString(line)
+		if strings.HasPrefix(line, "•") {
+			remaining--
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestPaginateRoleMappings_StaysWithinEmbedLimits(t *testing.T) {
+	t.Parallel()
+	guildConfig := storage.NewGuildConfig("guild-1")
+
+	var roleMappings []*core.RoleMapping
+	for n := range 100 {
+		realmPath := fmt.Sprintf("gno.land/r/community/realm%d", n%3)
+		roleName := fmt.Sprintf("a-rather-long-realm-role-name-%03d", n)
+		if n%7 == 0 {
+			guildConfig.SetRoleAutoSync(realmPath, roleName, false)
+		}
+		roleMappings = append(roleMappings, &core.RoleMapping{
+			RealmPath:     realmPath,
+			RealmRoleName: roleName,
+			PlatformRole:  core.PlatformRole{ID: fmt.Sprintf("%018d", n)},
+		})
+	}
+
+	pages := paginateRoleMappings(guildConfig, roleMappings)
+	if len(pages) < 2 {
+		t.Fatalf("100 mappings fit on %d page, want several", len(pages))
+	}
+
+	listed := 0
+	for n := range pages {
+		embed, components := formatRoleListPage(pages, n, len(roleMappings), 3)
+		if len(embed.Fields) > 25 {
+			t.Errorf("page %d has %d fields", n, len(embed.Fields))
+		}
+		total := len(embed.Title) + len(embed.Description) + len(embed.Footer.Text)
+		for _, field := range embed.Fields {
+			if len(field.Value) > maxEmbedFieldValue || len(field.Name) > maxEmbedFieldName {
+				t.Errorf("page %d field %q is %d characters long", n, field.Name, len(field.Value))
+			}
+			total += len(field.Name) + len(field.Value)
+			listed += strings.Count(field.Value, "• ")
+		}
+		if total > 6000 {
+			t.Errorf("page %d embed is %d characters long", n, total)
+		}
+
+		// The buttons carry the neighboring pages and are disabled at the edges
+		buttons := components[0].(discordgo.ActionsRow).Components
+		prev, next := buttons[0].(discordgo.Button), buttons[1].(discordgo.Button)
+		if prev.CustomID != listRolesPagePrefix+fmt.Sprint(n-1) || prev.Disabled != (n == 0) {
+			t.Errorf("page %d prev button = %+v", n, prev)
+		}
+		if next.CustomID != listRolesPagePrefix+fmt.Sprint(n+1) || next.Disabled != (n == len(pages)-1) {
+			t.Errorf("page %d next button = %+v", n, next)
+		}
+	}
+	if listed != len(roleMappings) {
+		t.Errorf("pages list %d roles, want %d", listed, len(roleMappings))
+	}
+
+	// The info command shows a single truncated field
+	display := truncateManagedRoles(formatManagedRoles(guildConfig, roleMappings))
+	if len(display) > maxEmbedFieldValue || !strings.Contains(display, "more; see `/gnolinker admin list-roles`") {
+		t.Errorf("managed roles display is %d characters long: %q", len(display), display)
+	}
+}
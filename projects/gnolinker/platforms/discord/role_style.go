@@ -0,0 +1,89 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *InteractionHandlers) handleAdminSetRoleStyleCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// How created roles look is bot configuration, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to configure the role style.")
+		return
+	}
+
+	guildConfig, err := h.configManager.GetGuildConfig(i.GuildID)
+	if err != nil {
+		h.logger.Error("Failed to get guild config", "error", err, "guild_id", i.GuildID)
+		h.respondError(s, i, "Failed to load the server configuration.")
+		return
+	}
+
+	// Options left out keep their current value
+	style := h.configManager.GetRoleStyle(guildConfig)
+	for _, option := range options {
+		switch option.Name {
+		case "color":
+			value := strings.TrimSpace(option.StringValue())
+			if strings.EqualFold(value, "default") {
+				style.Color = nil
+				continue
+			}
+			color, ok := storage.ParseRoleColor(value)
+			if !ok {
+				h.respondError(s, i, fmt.Sprintf("`%s` is not a hex color; use a value like `#5865F2`, or `default`.", value))
+				return
+			}
+			style.Color = &color
+		case "hoist":
+			style.Hoist = option.BoolValue()
+		case "mentionable":
+			style.Mentionable = option.BoolValue()
+		}
+	}
+
+	if len(options) > 0 {
+		if err := h.configManager.SetRoleStyle(i.GuildID, style); err != nil {
+			h.logger.Error("Failed to set role style", "error", err, "guild_id", i.GuildID)
+			h.respondError(s, i, "Failed to save the role style.")
+			return
+		}
+	}
+
+	content := "🎨 Roles the bot creates use " + formatRoleStyle(style) + "."
+	if len(options) > 0 {
+		content = "✅ Roles the bot creates from now on use " + formatRoleStyle(style) + ". Existing roles keep their current style."
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+// formatRoleStyle describes a role style for admin command responses
+func formatRoleStyle(style storage.RoleStyle) string {
+	color := "the default colors"
+	if style.Color != nil {
+		color = fmt.Sprintf("color `%s`", storage.FormatRoleColor(*style.Color))
+	}
+	hoist := "not hoisted"
+	if style.Hoist {
+		hoist = "hoisted"
+	}
+	mentionable := "not mentionable"
+	if style.Mentionable {
+		mentionable = "mentionable"
+	}
+	return fmt.Sprintf("%s, %s and %s", color, hoist, mentionable)
+}
@@ -0,0 +1,66 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestGetOrCreateLinkedRole_UsesConfiguredRoleStyle(t *testing.T) {
+	t.Parallel()
+	handlers, session, configManager, _ := setupInteractionHandlers()
+
+	guildID := "styled-guild"
+	realmPath := "gno.land/r/demo/styled"
+	session.AddGuild(guildID, "owner")
+	if err := configManager.GetStore().Set(guildID, storage.NewGuildConfig(guildID)); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	// Unconfigured guilds keep the default color and Discord's defaults
+	role, err := handlers.getOrCreateLinkedRole(session, guildID, "member", realmPath)
+	if err != nil {
+		t.Fatalf("getOrCreateLinkedRole() failed: %v", err)
+	}
+	if created := createdRole(t, session, guildID, role.ID); created.Color != defaultRoleColor || created.Hoist || created.Mentionable {
+		t.Errorf("created role = %+v, want the default color, not hoisted or mentionable", created)
+	}
+
+	brand := 0x5865F2
+	if err := configManager.SetRoleStyle(guildID, storage.RoleStyle{Color: &brand, Hoist: true, Mentionable: true}); err != nil {
+		t.Fatalf("SetRoleStyle() failed: %v", err)
+	}
+	role, err = handlers.getOrCreateLinkedRole(session, guildID, "admin", realmPath)
+	if err != nil {
+		t.Fatalf("getOrCreateLinkedRole() failed: %v", err)
+	}
+	if created := createdRole(t, session, guildID, role.ID); created.Color != brand || !created.Hoist || !created.Mentionable {
+		t.Errorf("created role = %+v, want color %s, hoisted and mentionable", created, storage.FormatRoleColor(brand))
+	}
+
+	// Restoring the default color keeps the flags
+	if err := configManager.SetRoleStyle(guildID, storage.RoleStyle{Hoist: true}); err != nil {
+		t.Fatalf("SetRoleStyle() failed: %v", err)
+	}
+	guildConfig, _ := configManager.GetGuildConfig(guildID)
+	if style := configManager.GetRoleStyle(guildConfig); style.Color != nil || !style.Hoist || style.Mentionable {
+		t.Errorf("role style = %+v, want default color, hoisted", style)
+	}
+}
+
+// createdRole returns the role with roleID in the mock session
+func createdRole(t *testing.T, session *MockDiscordSession, guildID, roleID string) *discordgo.Role {
+	t.Helper()
+	roles, err := session.GuildRoles(guildID)
+	if err != nil {
+		t.Fatalf("GuildRoles() failed: %v", err)
+	}
+	for _, role := range roles {
+		if role.ID == roleID {
+			return role
+		}
+	}
+	t.Fatalf("role %s was not created", roleID)
+	return nil
+}
@@ -0,0 +1,201 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/selftest"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	stepIndexer     = "Indexer reachable"
+	stepEvents      = "Role events parse"
+	stepRealmRoles  = "Realm role query"
+	stepDiscordRole = "Discord role create/delete"
+
+	// selfTestEventWindow is how many recent blocks the event parsing step scans
+	selfTestEventWindow = 1000
+)
+
+// EventQuerier is the subset of the GraphQL query client used by the self-test
+type EventQuerier interface {
+	QueryLatestBlockHeight(ctx context.Context) (int64, error)
+	QueryRoleEvents(ctx context.Context, afterBlockHeight int64, afterTxIndex int64, latestBlockHeight int64) ([]graphql.Transaction, error)
+}
+
+// SetEventQuerier enables the indexer checks of the admin self-test
+func (h *InteractionHandlers) SetEventQuerier(querier EventQuerier) {
+	h.eventQuerier = querier
+}
+
+// selfTestSteps builds the end-to-end checks for the link→role pipeline of a guild
+func (h *InteractionHandlers) selfTestSteps(session DiscordSession, guildID string) []selftest.Step {
+	var latestHeight int64
+
+	return []selftest.Step{
+		{
+			Name: stepIndexer,
+			Run: func(ctx context.Context) (string, error) {
+				if h.eventQuerier == nil {
+					return "", fmt.Errorf("event monitoring is disabled (no GraphQL endpoint configured)")
+				}
+				height, err := h.eventQuerier.QueryLatestBlockHeight(ctx)
+				if err != nil {
+					return "", fmt.Errorf("failed to query latest block: %w", err)
+				}
+				latestHeight = height
+				return fmt.Sprintf("latest block %d", height), nil
+			},
+		},
+		{
+			Name:     stepEvents,
+			Requires: []string{stepIndexer},
+			Run: func(ctx context.Context) (string, error) {
+				from := max(latestHeight-selfTestEventWindow, 0)
+				txs, err := h.eventQuerier.QueryRoleEvents(ctx, from, 0, latestHeight)
+				if err != nil {
+					return "", fmt.Errorf("failed to query role events: %w", err)
+				}
+
+				parsed := 0
+				for _, tx := range txs {
+					for _, event := range tx.Response.Events {
+						var err error
+						switch event.Type {
+						case "RoleLinked":
+							_, err = graphql.ParseRoleLinkedEvent(event)
+						case "RoleUnlinked":
+							_, err = graphql.ParseRoleUnlinkedEvent(event)
+						default:
+							continue
+						}
+						if err != nil {
+							return "", fmt.Errorf("failed to parse %s event in tx %s: %w", event.Type, tx.Hash, err)
+						}
+						parsed++
+					}
+				}
+				return fmt.Sprintf("parsed %d events from %d transactions in the last %d blocks", parsed, len(txs), selfTestEventWindow), nil
+			},
+		},
+		{
+			Name: stepRealmRoles,
+			Run: func(ctx context.Context) (string, error) {
+				mappings, err := h.roleLinkingFlow.ListAllRolesByGuild(guildID)
+				if err != nil {
+					return "", fmt.Errorf("failed to list linked roles: %w", err)
+				}
+				return fmt.Sprintf("%d linked roles", len(mappings)), nil
+			},
+		},
+		{
+			Name: stepDiscordRole,
+			Run: func(ctx context.Context) (string, error) {
+				return h.selfTestDiscordRole(ctx, session, guildID)
+			},
+		},
+	}
+}
+
+// selfTestDiscordRole creates and deletes a throwaway role under the guild's self-test lock
+func (h *InteractionHandlers) selfTestDiscordRole(ctx context.Context, session DiscordSession, guildID string) (string, error) {
+	if lockManager := h.configManager.GetLockManager(); lockManager != nil {
+		lockKey := fmt.Sprintf("selftest:%s", guildID)
+		acquired, err := lockManager.AcquireLock(ctx, lockKey, 30*time.Second)
+		if err != nil {
+			return "", fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		defer func() {
+			if err := lockManager.ReleaseLock(ctx, acquired); err != nil {
+				h.logger.Warn("Failed to release self-test lock", "lock_key", lockKey, "error", err)
+			}
+		}()
+	}
+
+	color := 0
+	name := fmt.Sprintf("gnolinker-selftest-%d", time.Now().Unix())
+	role, err := session.GuildRoleCreate(guildID, &discordgo.RoleParams{Name: name, Color: &color})
+	if err != nil {
+		return "", fmt.Errorf("failed to create role: %w", err)
+	}
+
+	if err := session.GuildRoleDelete(guildID, role.ID); err != nil {
+		return "", fmt.Errorf("created role %s but failed to delete it: %w", role.ID, err)
+	}
+
+	return fmt.Sprintf("created and deleted %s", name), nil
+}
+
+// formatSelfTestEmbed renders a self-test report with one line per step
+func formatSelfTestEmbed(report *selftest.Report) *discordgo.MessageEmbed {
+	var lines strings.Builder
+	for _, result := range report.Results {
+		icon := "❌"
+		switch {
+		case result.Skipped:
+			icon = "⏭️"
+		case result.Passed:
+			icon = "✅"
+		}
+		lines.WriteString(fmt.Sprintf("%s **%s**", icon, result.Name))
+		if !result.Skipped {
+			lines.WriteString(fmt.Sprintf(" (%s)", result.Duration.Round(time.Millisecond)))
+		}
+		lines.WriteString(fmt.Sprintf("\n%s\n\n", result.Detail))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Self-test passed",
+		Description: lines.String(),
+		Color:       0x00ff00,
+	}
+	if !report.Passed() {
+		embed.Title = "Self-test failed"
+		embed.Color = 0xff0000
+	}
+	return embed
+}
+
+func (h *InteractionHandlers) handleAdminSelfTestCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Check guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to run the self-test.")
+		return
+	}
+
+	// Defer response as the checks make several network calls
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to defer interaction response", "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	report := selftest.Run(ctx, h.selfTestSteps(s, i.GuildID))
+	for _, result := range report.Results {
+		h.logger.Info("Self-test step completed",
+			"guild_id", i.GuildID,
+			"step", result.Name,
+			"passed", result.Passed,
+			"skipped", result.Skipped,
+			"detail", result.Detail)
+	}
+
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{formatSelfTestEmbed(report)},
+	}); err != nil {
+		h.logger.Error("Failed to edit interaction response", "error", err)
+	}
+}
@@ -0,0 +1,126 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/selftest"
+	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
+)
+
+// mockEventQuerier returns a fixed block height and set of role event transactions
+type mockEventQuerier struct {
+	height    int64
+	heightErr error
+	txs       []graphql.Transaction
+}
+
+func (m *mockEventQuerier) QueryLatestBlockHeight(ctx context.Context) (int64, error) {
+	return m.height, m.heightErr
+}
+
+func (m *mockEventQuerier) QueryRoleEvents(ctx context.Context, afterBlockHeight int64, afterTxIndex int64, latestBlockHeight int64) ([]graphql.Transaction, error) {
+	return m.txs, nil
+}
+
+// stubRoleFlow only implements ListAllRolesByGuild; other workflow methods are not used by the self-test
+type stubRoleFlow struct {
+	workflows.RoleLinkingWorkflow
+	mappings []*core.RoleMapping
+	err      error
+}
+
+func (s *stubRoleFlow) ListAllRolesByGuild(platformGuildID string) ([]*core.RoleMapping, error) {
+	return s.mappings, s.err
+}
+
+func roleLinkedTx() graphql.Transaction {
+	return graphql.Transaction{
+		Hash: "tx1",
+		Response: graphql.TransactionResponse{Events: []graphql.GnoEvent{{
+			Type: "RoleLinked",
+			Attrs: []graphql.EventAttribute{
+				{Key: "realmPath", Value: "gno.land/r/demo/events"},
+				{Key: "roleName", Value: "member"},
+				{Key: "discordGuildID", Value: "100000000000000001"},
+				{Key: "discordRoleID", Value: "200000000000000001"},
+			},
+		}}},
+	}
+}
+
+func TestSelfTestSteps_AllPass(t *testing.T) {
+	t.Parallel()
+	handlers, session, _, _ := setupInteractionHandlers()
+	handlers.roleLinkingFlow = &stubRoleFlow{mappings: []*core.RoleMapping{{RealmPath: "gno.land/r/demo/events", RealmRoleName: "member"}}}
+	handlers.SetEventQuerier(&mockEventQuerier{height: 1500, txs: []graphql.Transaction{roleLinkedTx()}})
+
+	report := selftest.Run(context.Background(), handlers.selfTestSteps(session, "100000000000000001"))
+
+	if !report.Passed() {
+		t.Fatalf("expected self-test to pass, got %+v", report.Results)
+	}
+	if got := report.Results[0].Detail; got != "latest block 1500" {
+		t.Errorf("indexer detail = %q", got)
+	}
+	if got := report.Results[1].Detail; !strings.HasPrefix(got, "parsed 1 events from 1 transactions") {
+		t.Errorf("events detail = %q", got)
+	}
+
+	// The throwaway role must not be left behind
+	roles, _ := session.GuildRoles("100000000000000001")
+	if len(roles) != 0 {
+		t.Errorf("expected self-test role to be deleted, found %d roles", len(roles))
+	}
+}
+
+func TestSelfTestSteps_IndexerDown(t *testing.T) {
+	t.Parallel()
+	handlers, session, _, _ := setupInteractionHandlers()
+	handlers.roleLinkingFlow = &stubRoleFlow{}
+	handlers.SetEventQuerier(&mockEventQuerier{heightErr: errors.New("connection refused")})
+
+	report := selftest.Run(context.Background(), handlers.selfTestSteps(session, "100000000000000001"))
+
+	if report.Passed() {
+		t.Fatal("expected self-test to fail")
+	}
+	indexer, events, realm, discord := report.Results[0], report.Results[1], report.Results[2], report.Results[3]
+	if indexer.Passed || !strings.Contains(indexer.Detail, "connection refused") {
+		t.Errorf("indexer result = %+v", indexer)
+	}
+	if !events.Skipped {
+		t.Errorf("event parsing should be skipped when the indexer is down, got %+v", events)
+	}
+	if !realm.Passed || !discord.Passed {
+		t.Errorf("independent steps should still pass, got realm=%+v discord=%+v", realm, discord)
+	}
+
+	embed := formatSelfTestEmbed(report)
+	if embed.Title != "Self-test failed" {
+		t.Errorf("embed title = %q", embed.Title)
+	}
+}
+
+func TestSelfTestSteps_RoleDeleteFailure(t *testing.T) {
+	t.Parallel()
+	handlers, session, _, _ := setupInteractionHandlers()
+	handlers.roleLinkingFlow = &stubRoleFlow{err: errors.New("realm unavailable")}
+	session.SetRoleDeleteError(errors.New("missing permissions"))
+
+	report := selftest.Run(context.Background(), handlers.selfTestSteps(session, "100000000000000001"))
+
+	if report.Results[0].Passed || !strings.Contains(report.Results[0].Detail, "event monitoring is disabled") {
+		t.Errorf("indexer step should fail without a querier, got %+v", report.Results[0])
+	}
+	if report.Results[2].Passed {
+		t.Errorf("realm role step should fail, got %+v", report.Results[2])
+	}
+	if discord := report.Results[3]; discord.Passed || !strings.Contains(discord.Detail, "failed to delete") {
+		t.Errorf("discord step should report the delete failure, got %+v", discord)
+	}
+}
@@ -24,7 +24,9 @@ type MockDiscordSession struct {
 	guildMemberError error
 	permissionsError error
 	commandsError    error
-	permissions      map[string]int64 // userID -> permissions
+	permissions      map[string]int64                            // userID -> permissions
+	overwrites       map[string][]*discordgo.PermissionOverwrite // channelID -> overwrites
+	overwriteError   error
 }
 
 func NewMockDiscordSession() *MockDiscordSession {
@@ -40,6 +42,7 @@ func NewMockDiscordSession() *MockDiscordSession {
 		responses:   make(map[string]*discordgo.InteractionResponse),
 		followups:   make(map[string]*discordgo.WebhookEdit),
 		permissions: make(map[string]int64),
+		overwrites:  make(map[string][]*discordgo.PermissionOverwrite),
 	}
 }
 
@@ -81,6 +84,12 @@ func (m *MockDiscordSession) GuildRoleCreate(guildID string, data *discordgo.Rol
 		Color:    *data.Color,
 		Position: 1,
 	}
+	if data.Hoist != nil {
+		role.Hoist = *data.Hoist
+	}
+	if data.Mentionable != nil {
+		role.Mentionable = *data.Mentionable
+	}
 
 	if m.roles[guildID] == nil {
 		m.roles[guildID] = []*discordgo.Role{}
@@ -109,6 +118,61 @@ func (m *MockDiscordSession) GuildRoleDelete(guildID, roleID string, options ...
 	return errors.New("role not found")
 }
 
+func (m *MockDiscordSession) GuildRoleEdit(guildID, roleID string, data *discordgo.RoleParams, options ...discordgo.RequestOption) (*discordgo.Role, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, role := range m.roles[guildID] {
+		if role.ID == roleID {
+			if data.Name != "" {
+				role.Name = data.Name
+			}
+			if data.Color != nil {
+				role.Color = *data.Color
+			}
+			return role, nil
+		}
+	}
+
+	return nil, errors.New("role not found")
+}
+
+func (m *MockDiscordSession) ChannelPermissionSet(channelID, targetID string, targetType discordgo.PermissionOverwriteType, allow, deny int64, options ...discordgo.RequestOption) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.overwriteError != nil {
+		return m.overwriteError
+	}
+
+	overwrite := &discordgo.PermissionOverwrite{ID: targetID, Type: targetType, Allow: allow, Deny: deny}
+	for i, existing := range m.overwrites[channelID] {
+		if existing.ID == targetID {
+			m.overwrites[channelID][i] = overwrite
+			return nil
+		}
+	}
+	m.overwrites[channelID] = append(m.overwrites[channelID], overwrite)
+	return nil
+}
+
+func (m *MockDiscordSession) ChannelPermissionDelete(channelID, targetID string, options ...discordgo.RequestOption) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.overwriteError != nil {
+		return m.overwriteError
+	}
+
+	for i, existing := range m.overwrites[channelID] {
+		if existing.ID == targetID {
+			m.overwrites[channelID] = append(m.overwrites[channelID][:i], m.overwrites[channelID][i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("overwrite not found")
+}
+
 func (m *MockDiscordSession) Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -313,6 +377,19 @@ func (m *MockDiscordSession) AddRole(guildID string, role *discordgo.Role) {
 	m.roles[guildID] = append(m.roles[guildID], &roleCopy)
 }
 
+// ChannelOverwrites returns the permission overwrites set on a channel
+func (m *MockDiscordSession) ChannelOverwrites(channelID string) []*discordgo.PermissionOverwrite {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*discordgo.PermissionOverwrite(nil), m.overwrites[channelID]...)
+}
+
+func (m *MockDiscordSession) SetOverwriteError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.overwriteError = err
+}
+
 func (m *MockDiscordSession) SetRoleCreateError(err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,163 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// unlinkAddressSelectID is the custom ID of the menu choosing which linked address to unlink
+const unlinkAddressSelectID = "unlink_address"
+
+// maxSelectMenuOptions is the most options Discord shows in a select menu
+const maxSelectMenuOptions = 25
+
+var (
+	errNothingLinked    = errors.New("no linked address")
+	errAddressNotLinked = errors.New("address is not linked")
+	errChooseAddress    = errors.New("several linked addresses, none chosen")
+)
+
+// unlinkTarget picks what an unlink claim is for from the user's linked addresses and the one
+// they asked to unlink, if any. It returns the address to put in the claim and the address to
+// show the user. The claim address is empty, unlinking everything, only when the user has a
+// single address; with several and none requested the user has to choose (errChooseAddress).
+func unlinkTarget(linked []string, requested string) (claimAddress, shown string, err error) {
+	switch {
+	case len(linked) == 0:
+		return "", "", errNothingLinked
+	case requested != "" && !slices.Contains(linked, requested):
+		return "", "", errAddressNotLinked
+	case len(linked) == 1:
+		return "", linked[0], nil
+	case requested != "":
+		return requested, requested, nil
+	default:
+		return "", "", errChooseAddress
+	}
+}
+
+// unlinkAddressMenu lets a user with several linked addresses choose the one to unlink. Beyond
+// what the menu can show, the address option of the unlink command has to be used.
+func unlinkAddressMenu(addresses []string) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	description := "Your Discord account is linked to several gno.land addresses. Choose the one to unlink."
+	if len(addresses) > maxSelectMenuOptions {
+		description += fmt.Sprintf("\nOnly the first %d are listed; use `/gnolinker unlink address:` for the others.", maxSelectMenuOptions)
+		addresses = addresses[:maxSelectMenuOptions]
+	}
+
+	options := make([]discordgo.SelectMenuOption, 0, len(addresses))
+	for _, address := range addresses {
+		options = append(options, discordgo.SelectMenuOption{Label: address, Value: address})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Choose an Address to Unlink",
+		Description: description,
+		Color:       0xff9900,
+	}
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.StringSelectMenu,
+					CustomID:    unlinkAddressSelectID,
+					Placeholder: "gno.land address",
+					Options:     options,
+				},
+			},
+		},
+	}
+	return embed, components
+}
+
+// unlinkClaimMessage shows the link to submit an unlink claim
+func unlinkClaimMessage(address, claimURL string) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	embed := &discordgo.MessageEmbed{
+		Title:       "Unlink Your Account",
+		Description: fmt.Sprintf("Ready to unlink your Discord account from `%s`", address),
+		Color:       0xff9900, // Orange color for unlink
+	}
+
+	// Add button to claim on gno.land
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label: "Unlink on gno.land",
+					Style: discordgo.LinkButton,
+					URL:   claimURL,
+					Emoji: &discordgo.ComponentEmoji{
+						Name: "🔓",
+					},
+				},
+			},
+		},
+	}
+	return embed, components
+}
+
+// unlinkResponse builds the reply to an unlink request: the claim to submit, the menu to choose an
+// address, or why there is nothing to unlink
+func (h *InteractionHandlers) unlinkResponse(s *discordgo.Session, i *discordgo.InteractionCreate, userID, requested string) *discordgo.WebhookEdit {
+	reply := func(embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) *discordgo.WebhookEdit {
+		return &discordgo.WebhookEdit{Embeds: &[]*discordgo.MessageEmbed{embed}, Components: &components}
+	}
+	failure := func(message string) *discordgo.WebhookEdit {
+		return reply(&discordgo.MessageEmbed{Description: "❌ " + message, Color: 0xff0000}, []discordgo.MessageComponent{})
+	}
+
+	// Look up the gno addresses linked to this Discord ID
+	linked, err := h.userLinkingFlow.ListLinkedAddresses(userID)
+	if err != nil {
+		h.logger.Error("Failed to list linked addresses", "error", err, "user_id", userID)
+		return failure("Failed to check linked address.")
+	}
+
+	claimAddress, shown, err := unlinkTarget(linked, requested)
+	switch {
+	case errors.Is(err, errNothingLinked):
+		return reply(&discordgo.MessageEmbed{
+			Title:       "No Linked Address",
+			Description: "❌ Your Discord account is not linked to any gno.land address. There's nothing to unlink.",
+			Color:       0xff0000,
+		}, []discordgo.MessageComponent{})
+	case errors.Is(err, errAddressNotLinked):
+		return failure(fmt.Sprintf("`%s` is not linked to your Discord account.", requested))
+	case errors.Is(err, errChooseAddress):
+		return reply(unlinkAddressMenu(linked))
+	}
+
+	claim, err := h.userLinkingFlow.GenerateUnlinkClaim(userID, claimAddress)
+	if err != nil {
+		h.logger.Error("Failed to generate unlink claim", "error", err, "user_id", userID, "address", shown)
+		return failure("Failed to generate unlink claim. Please try again.")
+	}
+
+	claimURL := h.userLinkingFlow.GetClaimURL(claim)
+	h.recordPendingClaim(s, i.GuildID, userID, claim, shown, claimURL)
+	return reply(unlinkClaimMessage(shown, claimURL))
+}
+
+// handleUnlinkAddressSelect generates the unlink claim for the address chosen in the menu
+func (h *InteractionHandlers) handleUnlinkAddressSelect(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := i.Member.User.ID
+	values := i.MessageComponentData().Values
+	if len(values) == 0 {
+		return
+	}
+
+	// Acknowledge the choice while the chain is queried
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}); err != nil {
+		h.logger.Error("Failed to defer response", "error", err, "user_id", userID)
+		return
+	}
+
+	if _, err := s.InteractionResponseEdit(i.Interaction, h.unlinkResponse(s, i, userID, values[0])); err != nil {
+		h.logger.Error("Failed to edit response", "error", err, "user_id", userID)
+	}
+}
@@ -0,0 +1,79 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestUnlinkTarget(t *testing.T) {
+	t.Parallel()
+	const first, second = "g1first", "g1second"
+	tests := []struct {
+		name      string
+		linked    []string
+		requested string
+		wantClaim string
+		wantShown string
+		wantErr   error
+	}{
+		{name: "nothing linked", wantErr: errNothingLinked},
+		{name: "nothing linked, address requested", requested: first, wantErr: errNothingLinked},
+		{name: "one address unlinks all", linked: []string{first}, wantShown: first},
+		{name: "one address requested unlinks all", linked: []string{first}, requested: first, wantShown: first},
+		{name: "one address, other requested", linked: []string{first}, requested: second, wantErr: errAddressNotLinked},
+		{name: "many addresses must be chosen", linked: []string{first, second}, wantErr: errChooseAddress},
+		{name: "many addresses, one requested", linked: []string{first, second}, requested: second, wantClaim: second, wantShown: second},
+		{name: "many addresses, unknown requested", linked: []string{first, second}, requested: "g1other", wantErr: errAddressNotLinked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			claimAddress, shown, err := unlinkTarget(tt.linked, tt.requested)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unlinkTarget() error = %v, want %v", err, tt.wantErr)
+			}
+			if claimAddress != tt.wantClaim || shown != tt.wantShown {
+				t.Errorf("unlinkTarget() = %q, %q, want %q, %q", claimAddress, shown, tt.wantClaim, tt.wantShown)
+			}
+		})
+	}
+}
+
+func TestUnlinkAddressMenu(t *testing.T) {
+	t.Parallel()
+	var addresses []string
+	for n := range maxSelectMenuOptions + 5 {
+		addresses = append(addresses, fmt.Sprintf("g1address%d", n))
+	}
+
+	for _, count := range []int{2, len(addresses)} {
+		_, components := unlinkAddressMenu(addresses[:count])
+		menu := components[0].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+		if menu.CustomID != unlinkAddressSelectID {
+			t.Errorf("menu custom ID = %q, want %q", menu.CustomID, unlinkAddressSelectID)
+		}
+		if want := min(count, maxSelectMenuOptions); len(menu.Options) != want {
+			t.Errorf("menu for %d addresses has %d options, want %d", count, len(menu.Options), want)
+		}
+		if menu.Options[1].Value != addresses[1] {
+			t.Errorf("option value = %q, want %q", menu.Options[1].Value, addresses[1])
+		}
+	}
+}
+
+func TestGetExpectedCommands_UnlinkAddressIsOptional(t *testing.T) {
+	t.Parallel()
+	for _, opt := range (&InteractionHandlers{}).GetExpectedCommands()[0].Options {
+		if opt.Name != "unlink" {
+			continue
+		}
+		if len(opt.Options) != 1 || opt.Options[0].Name != "address" || opt.Options[0].Required {
+			t.Errorf("unlink options = %+v, want an optional address", opt.Options)
+		}
+		return
+	}
+	t.Fatal("unlink subcommand not found")
+}
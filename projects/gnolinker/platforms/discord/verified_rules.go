@@ -0,0 +1,119 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *InteractionHandlers) handleAdminAddVerifiedRuleCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// Which roles linking grants is bot configuration, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to configure verified roles.")
+		return
+	}
+
+	var rule storage.VerifiedRoleRule
+	for _, option := range options {
+		switch option.Name {
+		case "role":
+			rule.RoleID = option.RoleValue(nil, i.GuildID).ID
+		case "realm":
+			rule.RealmPath = strings.TrimSpace(option.StringValue())
+		case "function":
+			rule.Function = strings.TrimSpace(option.StringValue())
+		}
+	}
+	if (rule.RealmPath == "") != (rule.Function == "") {
+		h.respondError(s, i, "A conditional rule needs both a realm and a function; leave both out to grant the role to every linked member.")
+		return
+	}
+	if err := storage.ValidateVerifiedRoleRule(rule); err != nil {
+		h.respondError(s, i, fmt.Sprintf("Invalid verified-role rule: %s.", err))
+		return
+	}
+
+	err = h.configManager.SetVerifiedRoleRule(i.GuildID, rule)
+	switch {
+	case errors.Is(err, storage.ErrVerifiedRoleRule):
+		h.respondError(s, i, "That is the verified role, which every linked member already receives.")
+		return
+	case errors.Is(err, storage.ErrTooManyVerifiedRoleRules):
+		h.respondError(s, i, fmt.Sprintf("This server already has %d verified-role rules, the maximum.", storage.MaxVerifiedRoleRules))
+		return
+	case err != nil:
+		h.logger.Error("Failed to set verified-role rule", "error", err, "guild_id", i.GuildID, "role_id", rule.RoleID, "predicate", rule.Predicate())
+		h.respondError(s, i, "Failed to save the verified-role rule.")
+		return
+	}
+
+	content := fmt.Sprintf("✅ <@&%s> is now granted to every linked member, starting with their next verification.", rule.RoleID)
+	if rule.IsConditional() {
+		content = fmt.Sprintf("✅ <@&%s> is now granted to linked members for whom `%s` returns true, starting with their next verification.", rule.RoleID, rule.Predicate())
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+func (h *InteractionHandlers) handleAdminListVerifiedRulesCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := i.Member.User.ID
+	hasPermission, err := h.hasRoleAdminPermission(s, i.GuildID, userID)
+	if err != nil || !hasPermission {
+		h.respondError(s, i, "You need admin permissions (configured admin role or Discord Administrator) to view verified roles.")
+		return
+	}
+
+	guildConfig, err := h.configManager.GetGuildConfig(i.GuildID)
+	if err != nil {
+		h.respondError(s, i, "Failed to get guild configuration.")
+		return
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{formatVerifiedRulesEmbed(guildConfig)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
+
+// formatVerifiedRulesEmbed lists the roles granted to linked members and the condition of each
+func formatVerifiedRulesEmbed(guildConfig *storage.GuildConfig) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: "✅ Verified Roles",
+		Color: 0x5865F2,
+	}
+
+	rules := guildConfig.GetVerifiedRoleRules()
+	if len(rules) == 0 {
+		embed.Description = "No verified roles are configured."
+		return embed
+	}
+
+	var lines []string
+	for _, rule := range rules {
+		condition := "every linked member"
+		if rule.IsConditional() {
+			condition = fmt.Sprintf("linked members for whom `%s` returns true", rule.Predicate())
+		}
+		lines = append(lines, fmt.Sprintf("• <@&%s> - %s", rule.RoleID, condition))
+	}
+	embed.Description = strings.Join(lines, "\n")
+	return embed
+}
@@ -0,0 +1,172 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxVerifyChanges bounds the role changes listed per action in the verification report, keeping
+// each field within Discord's 1024 character limit
+const maxVerifyChanges = 12
+
+// GuildVerifier runs the 4-state verification over every member of a guild, as done by the event
+// handlers, under the guild's query processor
+type GuildVerifier interface {
+	VerifyGuild(ctx context.Context, guildID string, dryRun bool) (*events.VerificationReport, error)
+}
+
+// SetGuildVerifier enables the admin verify command
+func (h *InteractionHandlers) SetGuildVerifier(verifier GuildVerifier) {
+	h.guildVerifier = verifier
+}
+
+func (h *InteractionHandlers) handleAdminVerifyCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	userID := i.Member.User.ID
+	hasPermission, err := h.hasRoleAdminPermission(s, i.GuildID, userID)
+	if err != nil || !hasPermission {
+		h.respondError(s, i, "You need admin permissions (configured admin role or Discord Administrator) to run verification.")
+		return
+	}
+	if h.guildVerifier == nil {
+		h.respondError(s, i, "Verification requires event monitoring to be enabled.")
+		return
+	}
+
+	dryRun := len(options) > 0 && options[0].BoolValue()
+
+	// Defer response as every member of the server is checked
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to defer interaction response", "error", err)
+		return
+	}
+
+	report, err := h.guildVerifier.VerifyGuild(context.Background(), i.GuildID, dryRun)
+	switch {
+	case errors.Is(err, events.ErrStandby):
+		h.respondDeferredError(s, i, "Another bot instance is processing this server; verification only changes roles there. Run a dry run here, or try again once this instance takes over.")
+		return
+	case errors.Is(err, events.ErrProcessorStopped), errors.Is(err, context.Canceled):
+		h.respondDeferredError(s, i, "Verification was stopped because the bot is shutting down. Run it again once the bot is back.")
+		return
+	case err != nil:
+		h.logger.Error("Failed to verify guild", "error", err, "guild_id", i.GuildID, "dry_run", dryRun)
+		h.respondDeferredError(s, i, "Failed to run verification. Check the bot logs for details.")
+		return
+	}
+
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{formatVerificationEmbed(report)},
+	}); err != nil {
+		h.logger.Error("Failed to edit interaction response", "error", err)
+	}
+}
+
+// formatVerificationEmbed reports the role changes a verification run made, or in a dry run would make
+func formatVerificationEmbed(report *events.VerificationReport) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "✅ Verification Complete",
+		Description: fmt.Sprintf("Checked %d members.", report.Checked),
+		Color:       0x00ff00,
+	}
+	grantTitle, revokeTitle := "➕ Granted", "➖ Removed"
+	if report.DryRun {
+		embed.Title = "🧪 Verification Dry Run"
+		embed.Description = fmt.Sprintf("Checked %d members. No roles were changed; these are the changes a real run would make.", report.Checked)
+		embed.Color = 0x5865F2
+		grantTitle, revokeTitle = "➕ Would grant", "➖ Would remove"
+	}
+	if report.Failed > 0 {
+		embed.Color = 0xffa500
+		embed.Description += fmt.Sprintf("\n⚠️ %d members could not be verified; check the bot logs for details.", report.Failed)
+	}
+
+	var grants, revokes []events.RoleMutation
+	for _, mutation := range report.Mutations {
+		if mutation.Action == storage.AuditActionGrant {
+			grants = append(grants, mutation)
+		} else {
+			revokes = append(revokes, mutation)
+		}
+	}
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: fmt.Sprintf("%s (%d)", grantTitle, len(grants)), Value: formatRoleMutations(grants)},
+		{Name: fmt.Sprintf("%s (%d)", revokeTitle, len(revokes)), Value: formatRoleMutations(revokes)},
+	}
+	return embed
+}
+
+// formatRoleMutations lists role changes one per line, truncated to maxVerifyChanges
+func formatRoleMutations(mutations []events.RoleMutation) string {
+	if len(mutations) == 0 {
+		return "None"
+	}
+
+	var lines []string
+	for _, mutation := range mutations[:min(len(mutations), maxVerifyChanges)] {
+		line := fmt.Sprintf("<@%s> <@&%s>", mutation.UserID, mutation.RoleID)
+		if mutation.RealmRole != "" {
+			line += fmt.Sprintf(" `%s`", mutation.RealmRole)
+		}
+		lines = append(lines, line)
+	}
+	if len(mutations) > maxVerifyChanges {
+		lines = append(lines, fmt.Sprintf("and %d more", len(mutations)-maxVerifyChanges))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (h *InteractionHandlers) handleAdminSetVerifyTierCommand(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// Verification cadence is bot configuration, so it requires guild admin permissions
+	userID := i.Member.User.ID
+	isGuildAdmin, err := h.hasGuildAdminPermission(s, i.GuildID, userID)
+	if err != nil || !isGuildAdmin {
+		h.respondError(s, i, "You need Discord admin permissions (Administrator role or server owner) to configure verification.")
+		return
+	}
+
+	tier := options[0].StringValue()
+	interval, err := time.ParseDuration(strings.TrimSpace(options[1].StringValue()))
+	if err != nil {
+		h.respondError(s, i, fmt.Sprintf("`%s` is not a duration; use a value like `90s`, `5m` or `1h`.", options[1].StringValue()))
+		return
+	}
+	settings := storage.VerificationTierSettings{Interval: interval, BatchSize: int(options[2].IntValue())}
+	if err := storage.ValidateVerificationTier(tier, settings); err != nil {
+		h.respondError(s, i, fmt.Sprintf("Invalid verification tier settings: %s.", err))
+		return
+	}
+
+	if err := h.configManager.SetVerificationTier(i.GuildID, tier, settings); err != nil {
+		h.logger.Error("Failed to set verification tier", "error", err, "guild_id", i.GuildID, "tier", tier, "interval", interval, "batch_size", settings.BatchSize)
+		h.respondError(s, i, "Failed to save the verification tier settings.")
+		return
+	}
+
+	batch := fmt.Sprintf("up to %d members", settings.BatchSize)
+	if settings.BatchSize == 0 {
+		batch = "every member of the tier"
+	}
+	content := fmt.Sprintf("✅ The %s verification tier now runs every %s and verifies %s per run, starting with its next run.", tier, interval, batch)
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		h.logger.Error("Failed to respond to interaction", "error", err)
+	}
+}
@@ -0,0 +1,215 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultAPIURL is the Slack Web API endpoint
+const DefaultAPIURL = "https://slack.com/api"
+
+// Usergroup is a Slack user group, the analog of a Discord role
+type Usergroup struct {
+	ID     string `json:"id"`
+	TeamID string `json:"team_id"`
+	Name   string `json:"name"`
+	Handle string `json:"handle"`
+}
+
+// Block is a Block Kit layout block. Only mrkdwn section blocks are used.
+type Block struct {
+	Type string    `json:"type"`
+	Text *TextItem `json:"text,omitempty"`
+}
+
+// TextItem is a Block Kit text object
+type TextItem struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// sectionBlocks renders mrkdwn text as a single section block
+func sectionBlocks(text string) []Block {
+	return []Block{{Type: "section", Text: &TextItem{Type: "mrkdwn", Text: text}}}
+}
+
+// apiError is the error part of every Web API response
+type apiError struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// Client calls the Slack Web API with a bot token
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a Web API client for the bot with the given token
+func NewClient(apiURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(apiURL, "/"),
+		token:      token,
+		httpClient: &http.Client{},
+	}
+}
+
+// call invokes a Web API method with form parameters and decodes the response into result, if
+// not nil. Form encoding is used because several read methods do not accept JSON bodies.
+func (c *Client) call(ctx context.Context, method string, params url.Values, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+method, strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(resp.Body); err != nil {
+		return fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+	var apiErr apiError
+	if err := json.Unmarshal(body.Bytes(), &apiErr); err != nil {
+		return fmt.Errorf("failed to decode %s response (HTTP %d): %w", method, resp.StatusCode, err)
+	}
+	if !apiErr.OK {
+		return fmt.Errorf("%s failed: %s", method, apiErr.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body.Bytes(), result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// AuthTest returns the workspace (team) ID and user ID of the bot
+func (c *Client) AuthTest(ctx context.Context) (teamID, userID string, err error) {
+	var result struct {
+		TeamID string `json:"team_id"`
+		UserID string `json:"user_id"`
+	}
+	if err := c.call(ctx, "auth.test", url.Values{}, &result); err != nil {
+		return "", "", err
+	}
+	return result.TeamID, result.UserID, nil
+}
+
+// UsergroupUsers returns the members of a user group, including disabled ones
+func (c *Client) UsergroupUsers(ctx context.Context, usergroupID string) ([]string, error) {
+	var result struct {
+		Users []string `json:"users"`
+	}
+	err := c.call(ctx, "usergroups.users.list", url.Values{
+		"usergroup":        {usergroupID},
+		"include_disabled": {"true"},
+	}, &result)
+	return result.Users, err
+}
+
+// UpdateUsergroupUsers replaces the members of a user group. Slack rejects an empty list.
+func (c *Client) UpdateUsergroupUsers(ctx context.Context, usergroupID string, users []string) error {
+	return c.call(ctx, "usergroups.users.update", url.Values{
+		"usergroup": {usergroupID},
+		"users":     {strings.Join(users, ",")},
+	}, nil)
+}
+
+// EnableUsergroup re-enables a disabled user group
+func (c *Client) EnableUsergroup(ctx context.Context, usergroupID string) error {
+	return c.call(ctx, "usergroups.enable", url.Values{"usergroup": {usergroupID}}, nil)
+}
+
+// DisableUsergroup disables a user group, the only way to leave it without members
+func (c *Client) DisableUsergroup(ctx context.Context, usergroupID string) error {
+	return c.call(ctx, "usergroups.disable", url.Values{"usergroup": {usergroupID}}, nil)
+}
+
+// Usergroups lists the workspace's user groups, including disabled ones
+func (c *Client) Usergroups(ctx context.Context) ([]Usergroup, error) {
+	var result struct {
+		Usergroups []Usergroup `json:"usergroups"`
+	}
+	err := c.call(ctx, "usergroups.list", url.Values{"include_disabled": {"true"}}, &result)
+	return result.Usergroups, err
+}
+
+// CreateUsergroup creates a user group with the given name and mention handle
+func (c *Client) CreateUsergroup(ctx context.Context, name, handle string) (*Usergroup, error) {
+	var result struct {
+		Usergroup Usergroup `json:"usergroup"`
+	}
+	if err := c.call(ctx, "usergroups.create", url.Values{"name": {name}, "handle": {handle}}, &result); err != nil {
+		return nil, err
+	}
+	return &result.Usergroup, nil
+}
+
+// OpenConversation opens the direct message channel with a user and returns its ID
+func (c *Client) OpenConversation(ctx context.Context, userID string) (string, error) {
+	var result struct {
+		Channel struct {
+			ID string `json:"id"`
+		} `json:"channel"`
+	}
+	if err := c.call(ctx, "conversations.open", url.Values{"users": {userID}}, &result); err != nil {
+		return "", err
+	}
+	return result.Channel.ID, nil
+}
+
+// PostMessage posts a mrkdwn message to a channel
+func (c *Client) PostMessage(ctx context.Context, channelID, text string) error {
+	blocks, err := json.Marshal(sectionBlocks(text))
+	if err != nil {
+		return fmt.Errorf("failed to encode blocks: %w", err)
+	}
+	return c.call(ctx, "chat.postMessage", url.Values{
+		"channel":      {channelID},
+		"text":         {text},
+		"blocks":       {string(blocks)},
+		"unfurl_links": {"false"},
+	}, nil)
+}
+
+// Respond answers a slash command through its response_url with an ephemeral mrkdwn message.
+// A response_url accepts up to five responses within 30 minutes of the command.
+func (c *Client) Respond(ctx context.Context, responseURL, text string) error {
+	body, err := json.Marshal(map[string]any{
+		"response_type": "ephemeral",
+		"text":          text,
+		"blocks":        sectionBlocks(text),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create response request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post response: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to post response: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
+	"github.com/allinbits/labs/projects/gnolinker/platforms"
+)
+
+const (
+	// DefaultListenAddr is the default address of the slash command server
+	DefaultListenAddr = ":3000"
+
+	// CommandsPath is the request URL path to configure for the slash command
+	CommandsPath = "/slack/commands"
+
+	// maxRequestAge rejects slash command requests signed longer ago than this, against replays
+	maxRequestAge = 5 * time.Minute
+
+	// maxRequestBody bounds the size of a slash command request
+	maxRequestBody = 64 << 10
+
+	// shutdownTimeout bounds how long Stop waits for in-flight requests
+	shutdownTimeout = 10 * time.Second
+)
+
+const helpText = `gnolinker links your Slack account to a gno.land address.
+
+` + "`/gnolinker link <address>`" + ` - get a claim link to link your account to a gno.land address
+` + "`/gnolinker unlink`" + ` - get a claim link to unlink your account
+` + "`/gnolinker status`" + ` - show your linked address and refresh your verified status`
+
+// errInvalidSignature is returned for requests not signed with the app's signing secret
+var errInvalidSignature = errors.New("invalid request signature")
+
+// Bot represents a Slack bot instance receiving slash commands over HTTP
+type Bot struct {
+	client        *Client
+	platform      platforms.Platform
+	config        Config
+	userFlow      workflows.UserLinkingWorkflow
+	configManager *config.ConfigManager
+	logger        core.Logger
+	server        *http.Server
+
+	// ctx is cancelled on Stop; commands run in the background until then, tracked by pending
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending sync.WaitGroup
+
+	// eventHandlers and queryProcessorManager verify members as users link and unlink on chain
+	// and in periodic sweeps, looking them up in members; all are nil without event monitoring
+	members               *memberDirectory
+	eventHandlers         *events.EventHandlers
+	queryProcessorManager *events.QueryProcessorManager
+}
+
+// NewBot creates a new Slack bot
+func NewBot(config Config,
+	userFlow workflows.UserLinkingWorkflow,
+	configManager *config.ConfigManager,
+	logger core.Logger) (*Bot, error) {
+
+	if config.Token == "" {
+		return nil, errors.New("slack bot token is required")
+	}
+	if config.SigningSecret == "" {
+		return nil, errors.New("slack signing secret is required")
+	}
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+	if config.ListenAddr == "" {
+		config.ListenAddr = DefaultListenAddr
+	}
+
+	client := NewClient(config.APIURL, config.Token)
+	ctx, cancel := context.WithCancel(context.Background())
+	bot := &Bot{
+		client:        client,
+		platform:      NewSlackPlatform(client),
+		config:        config,
+		userFlow:      userFlow,
+		configManager: configManager,
+		logger:        logger,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+
+	// Check if event monitoring should be enabled
+	if config.GraphQLEndpoint != "" {
+		logger.Info("Initializing event monitoring", "graphql_endpoint", config.GraphQLEndpoint, "user_realm", config.UserRealmPath)
+
+		queryClient := graphql.NewQueryClient(config.GraphQLEndpoint, graphql.RealmConfig{UserRealmPath: config.UserRealmPath})
+
+		// Slack workspaces have no realm roles, so only the verified user group is synced
+		bot.members = &memberDirectory{client: client, usergroupID: config.VerifiedUsergroupID}
+		bot.eventHandlers = events.NewEventHandlers(bot.platform, configManager, nil, logger, userFlow, workflows.NewNoRoleLinkingWorkflow())
+		bot.eventHandlers.SetMemberDirectory(bot.members)
+
+		queryRegistry := events.CreateUserQueryRegistry(logger, bot.eventHandlers)
+		bot.queryProcessorManager = events.NewQueryProcessorManager(queryRegistry, configManager.GetStore(), queryClient, bot.eventHandlers, logger)
+		bot.queryProcessorManager.SetLockManager(configManager.GetLockManager())
+	} else {
+		logger.Info("Event monitoring disabled, members are only verified on status")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST "+CommandsPath, bot)
+	bot.server = &http.Server{Addr: config.ListenAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return bot, nil
+}
+
+// Start starts the slash command server and runs until interrupted
+func (b *Bot) Start() error {
+	b.logger.Info("Starting Slack bot...")
+
+	teamID, _, err := b.client.AuthTest(b.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate with Slack: %w", err)
+	}
+	if err := b.ensureWorkspaceConfig(teamID); err != nil {
+		b.logger.Error("Failed to ensure workspace config", "team_id", teamID, "error", err)
+	}
+
+	// Start the workspace's query processor if event monitoring is enabled
+	if b.queryProcessorManager != nil {
+		b.members.setTeamID(teamID)
+		if err := b.queryProcessorManager.Start(b.ctx); err != nil {
+			return fmt.Errorf("failed to start query processor manager: %w", err)
+		}
+		if err := b.queryProcessorManager.AddGuild(teamID); err != nil {
+			b.logger.Error("Failed to add query processor for workspace", "team_id", teamID, "error", err)
+		}
+		b.logger.Info("Query processor manager started")
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := b.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	b.logger.Info("Slack bot is running. Press Ctrl+C to exit.", "team_id", teamID, "listen_addr", b.config.ListenAddr, "path", CommandsPath)
+
+	// Wait for interrupt signal
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	select {
+	case <-signals:
+	case err := <-serverErr:
+		_ = b.Stop()
+		return fmt.Errorf("slash command server failed: %w", err)
+	}
+
+	return b.Stop()
+}
+
+// Stop stops the query processors and shuts down the slash command server, waiting for the
+// commands being handled
+func (b *Bot) Stop() error {
+	b.logger.Info("Stopping Slack bot...")
+
+	if b.queryProcessorManager != nil {
+		if err := b.queryProcessorManager.Stop(); err != nil {
+			// Work still running resumes from its last saved position on the next start
+			b.logger.Error("Failed to stop query processor manager", "error", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := b.server.Shutdown(ctx)
+
+	b.pending.Wait()
+	b.cancel()
+	return err
+}
+
+// GetPlatform returns the platform adapter
+func (b *Bot) GetPlatform() platforms.Platform {
+	return b.platform
+}
+
+// ensureWorkspaceConfig creates the guild configuration of the workspace, with the verified user
+// group as its verified role
+func (b *Bot) ensureWorkspaceConfig(teamID string) error {
+	guildConfig, err := b.configManager.GetGuildConfig(teamID)
+	switch {
+	case errors.Is(err, storage.ErrGuildConfigNotFound):
+		guildConfig = storage.NewGuildConfig(teamID)
+	case err != nil:
+		return err
+	case guildConfig.VerifiedRoleID == b.config.VerifiedUsergroupID:
+		return nil
+	}
+
+	guildConfig.VerifiedRoleID = b.config.VerifiedUsergroupID
+	return b.configManager.UpdateGuildConfig(teamID, guildConfig)
+}
+
+// ServeHTTP receives a slash command. Slack expects an answer within three seconds, so the
+// request is acknowledged at once and the command answered through its response_url.
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	if err != nil {
+		http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := verifySignature(b.config.SigningSecret, r.Header, body, time.Now()); err != nil {
+		b.logger.Warn("Rejected slash command request", "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	cmd := &SlashCommand{
+		Command:     form.Get("command"),
+		Text:        form.Get("text"),
+		TeamID:      form.Get("team_id"),
+		ChannelID:   form.Get("channel_id"),
+		ChannelName: form.Get("channel_name"),
+		UserID:      form.Get("user_id"),
+		ResponseURL: form.Get("response_url"),
+	}
+
+	b.pending.Add(1)
+	go func() {
+		defer b.pending.Done()
+		b.handleCommand(b.ctx, cmd)
+	}()
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleCommand dispatches a /gnolinker subcommand
+func (b *Bot) handleCommand(ctx context.Context, cmd *SlashCommand) {
+	message := NewSlackMessage(cmd)
+	userID := b.platform.GetUserID(message)
+	fields := strings.Fields(message.GetContent())
+	if len(fields) == 0 {
+		b.respond(ctx, cmd, helpText)
+		return
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "help":
+		b.respond(ctx, cmd, helpText)
+	case "link":
+		b.handleLink(ctx, cmd, userID, fields[1:])
+	case "unlink":
+		b.handleUnlink(ctx, cmd, userID)
+	case "status":
+		b.handleStatus(ctx, cmd, userID)
+	default:
+		b.respond(ctx, cmd, "Unknown command.\n\n"+helpText)
+	}
+}
+
+func (b *Bot) handleLink(ctx context.Context, cmd *SlashCommand, userID string, args []string) {
+	if len(args) != 1 {
+		b.respond(ctx, cmd, "Usage: `/gnolinker link <gno.land address>`")
+		return
+	}
+	address := args[0]
+
+	claim, err := b.userFlow.GenerateClaim(userID, address)
+	if err != nil {
+		b.logger.Error("Failed to generate user claim", "error", err, "user_id", userID, "address", address)
+		b.respond(ctx, cmd, "❌ Failed to generate claim. Please try again.")
+		return
+	}
+
+	b.respond(ctx, cmd, fmt.Sprintf("Ready to link your Slack account to `%s`.\n\n🔗 <%s|Claim on gno.land>\n\nRun `/gnolinker status` once the transaction is confirmed.", address, b.userFlow.GetClaimURL(claim)))
+}
+
+func (b *Bot) handleUnlink(ctx context.Context, cmd *SlashCommand, userID string) {
+	linkedAddress, err := b.userFlow.GetLinkedAddress(userID)
+	if err != nil {
+		b.logger.Error("Failed to get linked address", "error", err, "user_id", userID)
+		b.respond(ctx, cmd, "❌ Failed to check linked address.")
+		return
+	}
+	if linkedAddress == "" {
+		b.respond(ctx, cmd, "❌ Your Slack account is not linked to any gno.land address. There's nothing to unlink.")
+		return
+	}
+
+	claim, err := b.userFlow.GenerateUnlinkClaim(userID, "")
+	if err != nil {
+		b.logger.Error("Failed to generate unlink claim", "error", err, "user_id", userID, "address", linkedAddress)
+		b.respond(ctx, cmd, "❌ Failed to generate unlink claim. Please try again.")
+		return
+	}
+
+	b.respond(ctx, cmd, fmt.Sprintf("Ready to unlink your Slack account from `%s`.\n\n🔓 <%s|Unlink on gno.land>", linkedAddress, b.userFlow.GetClaimURL(claim)))
+}
+
+func (b *Bot) handleStatus(ctx context.Context, cmd *SlashCommand, userID string) {
+	linkedAddress, err := b.userFlow.GetLinkedAddress(userID)
+	if err != nil {
+		b.logger.Error("Failed to get linked address", "error", err, "user_id", userID)
+		b.respond(ctx, cmd, "❌ Failed to check linked address.")
+		return
+	}
+
+	var status strings.Builder
+	if linkedAddress == "" {
+		status.WriteString("🔗 Linked address: ❌ not linked\nUse `/gnolinker link <address>` to link your account.")
+	} else {
+		status.WriteString(fmt.Sprintf("🔗 Linked address: ✅ `%s`", linkedAddress))
+	}
+
+	if b.syncVerifiedStatus(cmd.TeamID, userID, linkedAddress != "") {
+		status.WriteString(fmt.Sprintf("\n✅ Verified: member of <!subteam^%s>", b.config.VerifiedUsergroupID))
+	}
+	b.respond(ctx, cmd, status.String())
+}
+
+// syncVerifiedStatus adds the user to the verified user group or removes them from it, depending
+// on whether they are linked. It reports whether the user is verified.
+func (b *Bot) syncVerifiedStatus(teamID, userID string, linked bool) bool {
+	usergroupID := b.config.VerifiedUsergroupID
+	if usergroupID == "" {
+		return false
+	}
+	if guildConfig, err := b.configManager.GetGuildConfig(teamID); err == nil && guildConfig.Paused {
+		return false
+	}
+
+	hasRole, err := b.platform.HasRole(teamID, userID, usergroupID)
+	if err != nil {
+		b.logger.Warn("Failed to check verified status", "team_id", teamID, "user_id", userID, "error", err)
+		return false
+	}
+
+	switch {
+	case linked && !hasRole:
+		// Recorded first, so the permissive role sync policy lets sweeps remove it once they unlink
+		if err := b.configManager.RecordBotAssignedRole(teamID, userID, usergroupID); err != nil {
+			b.logger.Error("Failed to record verified status", "team_id", teamID, "user_id", userID, "error", err)
+			return false
+		}
+		if err := b.platform.AddRole(teamID, userID, usergroupID); err != nil {
+			b.logger.Error("Failed to grant verified status", "team_id", teamID, "user_id", userID, "error", err)
+			if err := b.configManager.ClearBotAssignedRole(teamID, userID, usergroupID); err != nil {
+				b.logger.Warn("Failed to clear verified status record", "team_id", teamID, "user_id", userID, "error", err)
+			}
+			return false
+		}
+		b.logger.Info("Granted verified status", "team_id", teamID, "user_id", userID)
+	case !linked && hasRole:
+		if err := b.platform.RemoveRole(teamID, userID, usergroupID); err != nil {
+			b.logger.Error("Failed to remove verified status", "team_id", teamID, "user_id", userID, "error", err)
+			break
+		}
+		b.logger.Info("Removed verified status", "team_id", teamID, "user_id", userID)
+		if err := b.configManager.ClearBotAssignedRole(teamID, userID, usergroupID); err != nil {
+			b.logger.Warn("Failed to clear verified status record", "team_id", teamID, "user_id", userID, "error", err)
+		}
+	}
+	return linked
+}
+
+func (b *Bot) respond(ctx context.Context, cmd *SlashCommand, text string) {
+	if err := b.client.Respond(ctx, cmd.ResponseURL, text); err != nil {
+		b.logger.Error("Failed to respond to slash command", "user_id", cmd.UserID, "error", err)
+	}
+}
+
+// verifySignature checks a request's X-Slack-Signature, an HMAC-SHA256 of "v0:timestamp:body"
+// keyed with the signing secret, and rejects requests signed too long ago
+func verifySignature(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing timestamp", errInvalidSignature)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("%w: stale timestamp", errInvalidSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(header.Get("X-Slack-Signature")), []byte(want)) {
+		return errInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+const (
+	testTeamID        = "T0123456"
+	testUserID        = "U0123456"
+	testUsergroupID   = "S0123456"
+	testSigningSecret = "test-signing-secret"
+	testAddress       = "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"
+)
+
+// apiCall is a Web API request received by the fake server
+type apiCall struct {
+	method string
+	params url.Values
+}
+
+// fakeSlackAPI serves the Web API methods the bot uses, keeping user group members in usergroups,
+// and records the messages posted to response URLs under /response
+type fakeSlackAPI struct {
+	mu         sync.Mutex
+	calls      []apiCall
+	usergroups map[string][]string
+	responses  []string
+}
+
+func (f *fakeSlackAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/response" {
+		var response struct {
+			ResponseType string `json:"response_type"`
+			Text         string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&response)
+		f.responses = append(f.responses, response.Text)
+		return
+	}
+
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	_ = r.ParseForm()
+	f.calls = append(f.calls, apiCall{method: method, params: r.PostForm})
+
+	result := map[string]any{"ok": true}
+	switch method {
+	case "usergroups.users.list":
+		result["users"] = f.usergroups[r.PostForm.Get("usergroup")]
+	case "usergroups.users.update":
+		f.usergroups[r.PostForm.Get("usergroup")] = strings.Split(r.PostForm.Get("users"), ",")
+	case "usergroups.disable":
+		f.usergroups[r.PostForm.Get("usergroup")] = nil
+	case "auth.test":
+		result["team_id"], result["user_id"] = testTeamID, "UBOT"
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// called returns the calls made to a method
+func (f *fakeSlackAPI) called(method string) []apiCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []apiCall
+	for _, call := range f.calls {
+		if call.method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// mockUserLinkingFlow implements workflows.UserLinkingWorkflow backed by a static address map
+type mockUserLinkingFlow struct {
+	addresses map[string]string // platform ID -> gno address
+}
+
+func (m *mockUserLinkingFlow) GenerateClaim(platformID, gnoAddress string) (*core.Claim, error) {
+	return &core.Claim{Type: core.ClaimTypeUserLink, Data: "1," + platformID + "," + gnoAddress}, nil
+}
+
+func (m *mockUserLinkingFlow) GenerateUnlinkClaim(platformID, gnoAddress string) (*core.Claim, error) {
+	return &core.Claim{Type: core.ClaimTypeUserUnlink, Data: "1," + platformID}, nil
+}
+
+func (m *mockUserLinkingFlow) GetLinkedAddress(platformID string) (string, error) {
+	return m.addresses[platformID], nil
+}
+
+func (m *mockUserLinkingFlow) ListLinkedAddresses(platformID string) ([]string, error) {
+	if address := m.addresses[platformID]; address != "" {
+		return []string{address}, nil
+	}
+	return nil, nil
+}
+
+func (m *mockUserLinkingFlow) GetLinkedPlatformID(gnoAddress string) (string, error) {
+	return "", nil
+}
+
+func (m *mockUserLinkingFlow) GetClaimURL(claim *core.Claim) string {
+	return "https://gno.land/claim?data=" + claim.Data
+}
+
+func newTestBot(t *testing.T, usergroups map[string][]string, addresses map[string]string) (*Bot, *fakeSlackAPI, string) {
+	t.Helper()
+	return newTestBotWithConfig(t, Config{}, usergroups, addresses)
+}
+
+// newTestBotWithConfig creates a bot for testTeamID against a fake Web API, with the rest of its
+// config taken from cfg
+func newTestBotWithConfig(t *testing.T, cfg Config, usergroups map[string][]string, addresses map[string]string) (*Bot, *fakeSlackAPI, string) {
+	t.Helper()
+	api := &fakeSlackAPI{usergroups: usergroups}
+	if api.usergroups == nil {
+		api.usergroups = make(map[string][]string)
+	}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	configManager := config.NewConfigManager(storage.NewMemoryConfigStore(), &config.StorageConfig{}, lock.NewNoOpLockManager(), logger)
+	cfg.Token = "xoxb-test"
+	cfg.SigningSecret = testSigningSecret
+	cfg.APIURL = server.URL
+	cfg.VerifiedUsergroupID = testUsergroupID
+	bot, err := NewBot(cfg, &mockUserLinkingFlow{addresses: addresses}, configManager, logger)
+	if err != nil {
+		t.Fatalf("NewBot() error = %v", err)
+	}
+	if err := bot.ensureWorkspaceConfig(testTeamID); err != nil {
+		t.Fatalf("ensureWorkspaceConfig() error = %v", err)
+	}
+	if bot.members != nil {
+		bot.members.setTeamID(testTeamID)
+	}
+	return bot, api, server.URL + "/response"
+}
+
+// signedCommand builds a slash command request signed with the test signing secret
+func signedCommand(text, responseURL string, signedAt time.Time) *http.Request {
+	body := url.Values{
+		"command":      {"/gnolinker"},
+		"text":         {text},
+		"team_id":      {testTeamID},
+		"channel_id":   {"C0123456"},
+		"user_id":      {testUserID},
+		"response_url": {responseURL},
+	}.Encode()
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(testSigningSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+
+	req := httptest.NewRequest(http.MethodPost, CommandsPath, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+// runCommand serves a slash command and waits for its response
+func runCommand(t *testing.T, bot *Bot, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	bot.server.Handler.ServeHTTP(rec, req)
+	bot.pending.Wait()
+	return rec
+}
+
+func TestBot_LinkRespondsWithClaimURL(t *testing.T) {
+	bot, api, responseURL := newTestBot(t, nil, nil)
+
+	rec := runCommand(t, bot, signedCommand("link "+testAddress, responseURL, time.Now()))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if len(api.responses) != 1 {
+		t.Fatalf("posted %d responses, want 1", len(api.responses))
+	}
+	want := "<https://gno.land/claim?data=1," + testUserID + "," + testAddress + "|"
+	if !strings.Contains(api.responses[0], want) {
+		t.Errorf("response %q does not contain the claim link %q", api.responses[0], want)
+	}
+}
+
+func TestBot_StatusSyncsVerifiedUsergroup(t *testing.T) {
+	tests := []struct {
+		name      string
+		members   []string
+		addresses map[string]string
+		want      []string
+	}{
+		{
+			name:      "linked member joins the group",
+			members:   []string{"U0000001"},
+			addresses: map[string]string{testUserID: testAddress},
+			want:      []string{"U0000001", testUserID},
+		},
+		{
+			name:    "unlinked member leaves the group",
+			members: []string{"U0000001", testUserID},
+			want:    []string{"U0000001"},
+		},
+		{
+			name:    "last member leaving disables the group",
+			members: []string{testUserID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, api, responseURL := newTestBot(t, map[string][]string{testUsergroupID: tt.members}, tt.addresses)
+
+			runCommand(t, bot, signedCommand("status", responseURL, time.Now()))
+
+			if got := api.usergroups[testUsergroupID]; !slices.Equal(got, tt.want) {
+				t.Errorf("user group members = %v, want %v", got, tt.want)
+			}
+			if len(tt.want) == 0 && len(api.called("usergroups.disable")) != 1 {
+				t.Errorf("emptied user group was not disabled")
+			}
+		})
+	}
+}
+
+// newMonitoringTestBot creates a bot with event monitoring whose verified user group holds
+// testUserID, who has since unlinked
+func newMonitoringTestBot(t *testing.T) (*Bot, *fakeSlackAPI) {
+	t.Helper()
+	cfg := Config{GraphQLEndpoint: "http://indexer.invalid/graphql", UserRealmPath: "gno.land/r/linker000/slack/user/v0"}
+	bot, api, _ := newTestBotWithConfig(t, cfg, map[string][]string{testUsergroupID: {"U0000001", testUserID}}, map[string]string{"U0000001": testAddress})
+	if bot.eventHandlers == nil || bot.queryProcessorManager == nil {
+		t.Fatal("event monitoring should be enabled with a GraphQL endpoint")
+	}
+	return bot, api
+}
+
+func TestBot_UserUnlinkedEventRevokesVerifiedStatus(t *testing.T) {
+	bot, api := newMonitoringTestBot(t)
+
+	err := bot.eventHandlers.HandleUserUnlinked(events.Event{
+		Type:         events.UserUnlinkedEvent,
+		UserUnlinked: &graphql.UserUnlinkedEvent{Address: testAddress, DiscordID: testUserID},
+	})
+	if err != nil {
+		t.Fatalf("HandleUserUnlinked() error = %v", err)
+	}
+	if got, want := api.usergroups[testUsergroupID], []string{"U0000001"}; !slices.Equal(got, want) {
+		t.Errorf("user group members = %v, want %v", got, want)
+	}
+}
+
+func TestBot_VerificationSweepRevokesUnlinkedMember(t *testing.T) {
+	bot, api := newMonitoringTestBot(t)
+
+	state := storage.NewGuildQueryState(testTeamID, "verify_low_priority", true)
+	if err := bot.eventHandlers.ProcessTieredVerification(t.Context(), testTeamID, state, "low", 10); err != nil {
+		t.Fatalf("ProcessTieredVerification() error = %v", err)
+	}
+	if got, want := api.usergroups[testUsergroupID], []string{"U0000001"}; !slices.Equal(got, want) {
+		t.Errorf("user group members = %v, want %v", got, want)
+	}
+}
+
+func TestBot_RejectsUnsignedRequests(t *testing.T) {
+	bot, api, responseURL := newTestBot(t, nil, nil)
+
+	stale := signedCommand("status", responseURL, time.Now().Add(-10*time.Minute))
+	forged := signedCommand("status", responseURL, time.Now())
+	forged.Header.Set("X-Slack-Signature", "v0=deadbeef")
+
+	for name, req := range map[string]*http.Request{"stale": stale, "forged": forged} {
+		if rec := runCommand(t, bot, req); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s request: status = %d, want %d", name, rec.Code, http.StatusUnauthorized)
+		}
+	}
+	if len(api.responses) != 0 || len(api.calls) != 0 {
+		t.Errorf("rejected requests were handled: %d responses, %d API calls", len(api.responses), len(api.calls))
+	}
+}
+
+func TestVerifySignature_MissingTimestamp(t *testing.T) {
+	if err := verifySignature(testSigningSecret, http.Header{}, nil, time.Now()); !errors.Is(err, errInvalidSignature) {
+		t.Errorf("verifySignature() error = %v, want %v", err, errInvalidSignature)
+	}
+}
+
+func TestUsergroupHandle(t *testing.T) {
+	tests := map[string]string{
+		"Verified":          "verified",
+		"gno.land members!": "gno.land-members",
+		"core_team-2":       "core_team-2",
+	}
+	for name, want := range tests {
+		if got := usergroupHandle(name); got != want {
+			t.Errorf("usergroupHandle(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package slack
+
+// Config holds Slack-specific configuration
+type Config struct {
+	// Token is the bot user OAuth token (xoxb-...). The app needs the commands, chat:write,
+	// im:write, usergroups:read and usergroups:write scopes.
+	Token string
+
+	// SigningSecret verifies that slash command requests come from Slack
+	SigningSecret string
+
+	// APIURL is the Web API endpoint, DefaultAPIURL unless testing against a fake
+	APIURL string
+
+	// ListenAddr is the address of the HTTP server receiving slash commands
+	ListenAddr string
+
+	// VerifiedUsergroupID is the user group linked members join, the Slack analog of the
+	// verified role. Verified status is not managed when empty.
+	VerifiedUsergroupID string
+
+	// GraphQLEndpoint is the indexer watched for links and unlinks of UserRealmPath. Unlinked
+	// members leave the verified user group as the events arrive and in periodic verification
+	// sweeps. Empty disables both, leaving verification to the status command.
+	GraphQLEndpoint string
+
+	// UserRealmPath is the package path of the user contract, e.g. gno.land/r/linker000/slack/user/v0
+	UserRealmPath string
+}
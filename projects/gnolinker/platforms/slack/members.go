@@ -0,0 +1,96 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// errNoPresence is returned for presences, which the bot does not track
+var errNoPresence = errors.New("slack presence is not tracked")
+
+// memberDirectory describes the workspace and its members to the event handlers, as a guild and
+// guild members. Verification sweeps go through the members of the verified user group, the only
+// ones with anything to revoke. The workspace is known once the bot authenticates.
+type memberDirectory struct {
+	client      *Client
+	usergroupID string
+
+	mu     sync.RWMutex
+	teamID string
+}
+
+// setTeamID sets the workspace the bot token belongs to
+func (d *memberDirectory) setTeamID(teamID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.teamID = teamID
+}
+
+func (d *memberDirectory) Guilds() []*discordgo.Guild {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.teamID == "" {
+		return nil
+	}
+	return []*discordgo.Guild{{ID: d.teamID, Name: d.teamID}}
+}
+
+func (d *memberDirectory) Guild(guildID string) (*discordgo.Guild, error) {
+	if err := d.checkWorkspace(guildID); err != nil {
+		return nil, err
+	}
+	return &discordgo.Guild{ID: guildID, Name: guildID}, nil
+}
+
+// Member returns a member of the workspace. Member IDs are scoped to the workspace, so any of
+// them is a member.
+func (d *memberDirectory) Member(guildID, userID string) (*discordgo.Member, error) {
+	if err := d.checkWorkspace(guildID); err != nil {
+		return nil, err
+	}
+	return &discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: userID}}, nil
+}
+
+// GuildMembers returns the members of the verified user group, in a stable order
+func (d *memberDirectory) GuildMembers(guildID string) ([]*discordgo.Member, error) {
+	if err := d.checkWorkspace(guildID); err != nil {
+		return nil, err
+	}
+	if d.usergroupID == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	users, err := d.client.UsergroupUsers(ctx, d.usergroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user group members: %w", err)
+	}
+
+	slices.Sort(users)
+	members := make([]*discordgo.Member, 0, len(users))
+	for _, userID := range users {
+		members = append(members, &discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: userID}})
+	}
+	return members, nil
+}
+
+// Presence returns errNoPresence, so sweeps treat every member as offline
+func (d *memberDirectory) Presence(guildID, userID string) (*discordgo.Presence, error) {
+	return nil, errNoPresence
+}
+
+// checkWorkspace returns an error unless guildID is the bot's workspace
+func (d *memberDirectory) checkWorkspace(guildID string) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if guildID == "" || guildID != d.teamID {
+		return fmt.Errorf("workspace %s is not served by the bot", guildID)
+	}
+	return nil
+}
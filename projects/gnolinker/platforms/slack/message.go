@@ -0,0 +1,44 @@
+package slack
+
+import "strings"
+
+// SlashCommand is a slash command invocation posted by Slack
+type SlashCommand struct {
+	Command     string
+	Text        string
+	TeamID      string
+	ChannelID   string
+	ChannelName string
+	UserID      string
+	ResponseURL string
+}
+
+// SlackMessage wraps a slash command to implement the Message interface
+type SlackMessage struct {
+	cmd *SlashCommand
+}
+
+// NewSlackMessage creates a new SlackMessage wrapper
+func NewSlackMessage(cmd *SlashCommand) *SlackMessage {
+	return &SlackMessage{cmd: cmd}
+}
+
+// GetAuthorID returns the Slack user ID of the command's author
+func (m *SlackMessage) GetAuthorID() string {
+	return m.cmd.UserID
+}
+
+// GetContent returns the command text after the command name
+func (m *SlackMessage) GetContent() string {
+	return m.cmd.Text
+}
+
+// GetChannelID returns the channel where the command was run
+func (m *SlackMessage) GetChannelID() string {
+	return m.cmd.ChannelID
+}
+
+// IsDirectMessage returns true if the command was run in a direct message
+func (m *SlackMessage) IsDirectMessage() bool {
+	return m.cmd.ChannelName == "directmessage" || strings.HasPrefix(m.cmd.ChannelID, "D")
+}
@@ -0,0 +1,174 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/platforms"
+)
+
+// requestTimeout bounds each Web API call made on behalf of the platform interface
+const requestTimeout = 10 * time.Second
+
+// SlackPlatform implements the Platform interface for Slack. Guild IDs are workspace (team) IDs,
+// user IDs are Slack member IDs and roles are user groups. A bot token belongs to a single
+// workspace, so the guild ID only scopes the guild configuration.
+type SlackPlatform struct {
+	client *Client
+
+	// mu serializes user group updates, which replace the whole member list
+	mu sync.Mutex
+}
+
+// NewSlackPlatform creates a new Slack platform adapter
+func NewSlackPlatform(client *Client) platforms.Platform {
+	return &SlackPlatform{client: client}
+}
+
+// GetUserID returns the user ID from a message
+func (p *SlackPlatform) GetUserID(message platforms.Message) string {
+	return message.GetAuthorID()
+}
+
+// SendDirectMessage sends a message to a user's direct message channel with the bot
+func (p *SlackPlatform) SendDirectMessage(userID, content string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	channelID, err := p.client.OpenConversation(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to open DM channel: %w", err)
+	}
+	if err := p.client.PostMessage(ctx, channelID, content); err != nil {
+		return fmt.Errorf("failed to send DM: %w", err)
+	}
+	return nil
+}
+
+// HasRole checks if a user is a member of the user group
+func (p *SlackPlatform) HasRole(guildID, userID, roleID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	users, err := p.client.UsergroupUsers(ctx, roleID)
+	if err != nil {
+		return false, fmt.Errorf("failed to list user group members: %w", err)
+	}
+	return slices.Contains(users, userID), nil
+}
+
+// AddRole adds a user to the user group, re-enabling the group if it had no members left
+func (p *SlackPlatform) AddRole(guildID, userID, roleID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	users, err := p.client.UsergroupUsers(ctx, roleID)
+	if err != nil {
+		return fmt.Errorf("failed to list user group members: %w", err)
+	}
+	if slices.Contains(users, userID) {
+		return nil
+	}
+	if len(users) == 0 {
+		if err := p.client.EnableUsergroup(ctx, roleID); err != nil {
+			return fmt.Errorf("failed to enable user group: %w", err)
+		}
+	}
+	if err := p.client.UpdateUsergroupUsers(ctx, roleID, append(users, userID)); err != nil {
+		return fmt.Errorf("failed to add user group member: %w", err)
+	}
+	return nil
+}
+
+// RemoveRole removes a user from the user group. Slack user groups cannot be empty, so removing
+// the last member disables the group instead.
+func (p *SlackPlatform) RemoveRole(guildID, userID, roleID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	users, err := p.client.UsergroupUsers(ctx, roleID)
+	if err != nil {
+		return fmt.Errorf("failed to list user group members: %w", err)
+	}
+	if !slices.Contains(users, userID) {
+		return nil
+	}
+
+	remaining := slices.DeleteFunc(users, func(id string) bool { return id == userID })
+	if len(remaining) == 0 {
+		if err := p.client.DisableUsergroup(ctx, roleID); err != nil {
+			return fmt.Errorf("failed to disable user group: %w", err)
+		}
+		return nil
+	}
+	if err := p.client.UpdateUsergroupUsers(ctx, roleID, remaining); err != nil {
+		return fmt.Errorf("failed to remove user group member: %w", err)
+	}
+	return nil
+}
+
+// GetOrCreateRole finds a user group by name or creates it
+func (p *SlackPlatform) GetOrCreateRole(guildID, name string) (*core.PlatformRole, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	usergroups, err := p.client.Usergroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user groups: %w", err)
+	}
+	for _, usergroup := range usergroups {
+		if usergroup.Name == name {
+			return &core.PlatformRole{ID: usergroup.ID, Name: usergroup.Name}, nil
+		}
+	}
+
+	usergroup, err := p.client.CreateUsergroup(ctx, name, usergroupHandle(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user group: %w", err)
+	}
+	return &core.PlatformRole{ID: usergroup.ID, Name: usergroup.Name}, nil
+}
+
+// GetRoleByID retrieves a user group by its ID
+func (p *SlackPlatform) GetRoleByID(guildID, roleID string) (*core.PlatformRole, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	usergroups, err := p.client.Usergroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user groups: %w", err)
+	}
+	for _, usergroup := range usergroups {
+		if usergroup.ID == roleID {
+			return &core.PlatformRole{ID: usergroup.ID, Name: usergroup.Name}, nil
+		}
+	}
+	return nil, fmt.Errorf("role not found: %s", roleID)
+}
+
+// usergroupHandle derives a mention handle from a user group name: lowercase letters, digits,
+// dots, underscores and hyphens
+func usergroupHandle(name string) string {
+	handle := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, name)
+	return strings.Trim(handle, "-")
+}
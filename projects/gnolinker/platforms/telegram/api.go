@@ -0,0 +1,214 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the Telegram Bot API endpoint
+const DefaultAPIURL = "https://api.telegram.org"
+
+// Chat member statuses reported by getChatMember
+const (
+	StatusCreator       = "creator"
+	StatusAdministrator = "administrator"
+	StatusMember        = "member"
+	StatusRestricted    = "restricted"
+	StatusLeft          = "left"
+	StatusKicked        = "kicked"
+)
+
+// Update is an incoming update from getUpdates
+type Update struct {
+	UpdateID   int64              `json:"update_id"`
+	Message    *Message           `json:"message,omitempty"`
+	ChatMember *ChatMemberUpdated `json:"chat_member,omitempty"`
+}
+
+// Message is a Telegram message
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text,omitempty"`
+}
+
+// User is a Telegram user or bot
+type User struct {
+	ID       int64  `json:"id"`
+	IsBot    bool   `json:"is_bot"`
+	Username string `json:"username,omitempty"`
+}
+
+// Chat is a private chat, group or supergroup
+type Chat struct {
+	ID    int64  `json:"id"`
+	Type  string `json:"type"`
+	Title string `json:"title,omitempty"`
+}
+
+// ChatMember is a user's membership in a chat. CanSendMessages and IsMember are only
+// reported for restricted members.
+type ChatMember struct {
+	Status          string `json:"status"`
+	User            User   `json:"user"`
+	IsMember        bool   `json:"is_member,omitempty"`
+	CanSendMessages bool   `json:"can_send_messages,omitempty"`
+}
+
+// ChatMemberUpdated reports a change in a chat member's status
+type ChatMemberUpdated struct {
+	Chat          Chat       `json:"chat"`
+	From          User       `json:"from"`
+	OldChatMember ChatMember `json:"old_chat_member"`
+	NewChatMember ChatMember `json:"new_chat_member"`
+}
+
+// ChatPermissions are the actions a restricted member may take
+type ChatPermissions struct {
+	CanSendMessages       bool `json:"can_send_messages"`
+	CanSendAudios         bool `json:"can_send_audios"`
+	CanSendDocuments      bool `json:"can_send_documents"`
+	CanSendPhotos         bool `json:"can_send_photos"`
+	CanSendVideos         bool `json:"can_send_videos"`
+	CanSendVideoNotes     bool `json:"can_send_video_notes"`
+	CanSendVoiceNotes     bool `json:"can_send_voice_notes"`
+	CanSendPolls          bool `json:"can_send_polls"`
+	CanSendOtherMessages  bool `json:"can_send_other_messages"`
+	CanAddWebPagePreviews bool `json:"can_add_web_page_previews"`
+	CanInviteUsers        bool `json:"can_invite_users"`
+}
+
+// memberPermissions are the permissions of a verified member: everything but managing the group
+var memberPermissions = ChatPermissions{
+	CanSendMessages:       true,
+	CanSendAudios:         true,
+	CanSendDocuments:      true,
+	CanSendPhotos:         true,
+	CanSendVideos:         true,
+	CanSendVideoNotes:     true,
+	CanSendVoiceNotes:     true,
+	CanSendPolls:          true,
+	CanSendOtherMessages:  true,
+	CanAddWebPagePreviews: true,
+	CanInviteUsers:        true,
+}
+
+// apiResponse is the envelope of every Bot API response
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+}
+
+// Client calls the Telegram Bot API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a Bot API client for the bot with the given token
+func NewClient(apiURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(apiURL, "/") + "/bot" + token,
+		httpClient: &http.Client{},
+	}
+}
+
+// call invokes a Bot API method with JSON parameters and decodes its result into result, if not nil
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s parameters: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("failed to decode %s response (HTTP %d): %w", method, resp.StatusCode, err)
+	}
+	if !apiResp.OK {
+		return fmt.Errorf("%s failed: %s", method, apiResp.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(apiResp.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// GetUpdates long-polls for updates after offset, waiting up to timeout for one to arrive
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	var updates []Update
+	err := c.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message", "chat_member"},
+	}, &updates)
+	return updates, err
+}
+
+// GetMe returns the bot's own user
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.call(ctx, "getMe", map[string]any{}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SendMessage sends a plain text message to a chat
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
+	return c.call(ctx, "sendMessage", map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}, nil)
+}
+
+// GetChat returns a chat
+func (c *Client) GetChat(ctx context.Context, chatID int64) (*Chat, error) {
+	var chat Chat
+	if err := c.call(ctx, "getChat", map[string]any{"chat_id": chatID}, &chat); err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+// GetChatMember returns a user's membership in a chat
+func (c *Client) GetChatMember(ctx context.Context, chatID, userID int64) (*ChatMember, error) {
+	var member ChatMember
+	if err := c.call(ctx, "getChatMember", map[string]any{"chat_id": chatID, "user_id": userID}, &member); err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RestrictChatMember sets a member's permissions in a supergroup. The bot must be an admin
+// allowed to restrict members.
+func (c *Client) RestrictChatMember(ctx context.Context, chatID, userID int64, permissions ChatPermissions) error {
+	return c.call(ctx, "restrictChatMember", map[string]any{
+		"chat_id":                          chatID,
+		"user_id":                          userID,
+		"permissions":                      permissions,
+		"use_independent_chat_permissions": true,
+	}, nil)
+}
@@ -0,0 +1,457 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+	"github.com/allinbits/labs/projects/gnolinker/core/workflows"
+	"github.com/allinbits/labs/projects/gnolinker/platforms"
+)
+
+const (
+	// DefaultPollTimeout is how long each getUpdates long poll waits by default
+	DefaultPollTimeout = 30 * time.Second
+
+	// pollRetryDelay is the pause after a failed getUpdates before polling again
+	pollRetryDelay = 5 * time.Second
+)
+
+const helpText = `gnolinker links your Telegram account to a gno.land address.
+
+/link <address> - get a claim link to link your account to a gno.land address
+/unlink - get a claim link to unlink your account
+/status - show your linked address and refresh your verified status in groups`
+
+// Bot represents a Telegram bot instance
+type Bot struct {
+	client        *Client
+	platform      platforms.Platform
+	config        Config
+	userFlow      workflows.UserLinkingWorkflow
+	configManager *config.ConfigManager
+	logger        core.Logger
+	username      string
+	stop          context.CancelFunc
+	done          chan struct{}
+
+	// eventHandlers and queryProcessorManager verify members as users link and unlink on chain
+	// and in periodic sweeps; both are nil without event monitoring
+	eventHandlers         *events.EventHandlers
+	queryProcessorManager *events.QueryProcessorManager
+}
+
+// NewBot creates a new Telegram bot
+func NewBot(config Config,
+	userFlow workflows.UserLinkingWorkflow,
+	configManager *config.ConfigManager,
+	logger core.Logger) (*Bot, error) {
+
+	if config.Token == "" {
+		return nil, errors.New("telegram bot token is required")
+	}
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+	if config.PollTimeout <= 0 {
+		config.PollTimeout = DefaultPollTimeout
+	}
+
+	client := NewClient(config.APIURL, config.Token)
+	bot := &Bot{
+		client:        client,
+		platform:      NewTelegramPlatform(client),
+		config:        config,
+		userFlow:      userFlow,
+		configManager: configManager,
+		logger:        logger,
+	}
+
+	// Check if event monitoring should be enabled
+	if config.GraphQLEndpoint != "" {
+		logger.Info("Initializing event monitoring", "graphql_endpoint", config.GraphQLEndpoint, "user_realm", config.UserRealmPath)
+
+		queryClient := graphql.NewQueryClient(config.GraphQLEndpoint, graphql.RealmConfig{UserRealmPath: config.UserRealmPath})
+
+		// Telegram groups have no realm roles, so only the verified status is synced
+		bot.eventHandlers = events.NewEventHandlers(bot.platform, configManager, nil, logger, userFlow, workflows.NewNoRoleLinkingWorkflow())
+		bot.eventHandlers.SetMemberDirectory(&memberDirectory{client: client, groupIDs: config.GroupIDs, configManager: configManager})
+
+		queryRegistry := events.CreateUserQueryRegistry(logger, bot.eventHandlers)
+		bot.queryProcessorManager = events.NewQueryProcessorManager(queryRegistry, configManager.GetStore(), queryClient, bot.eventHandlers, logger)
+		bot.queryProcessorManager.SetLockManager(configManager.GetLockManager())
+	} else {
+		logger.Info("Event monitoring disabled, members are only verified on join and /status")
+	}
+
+	return bot, nil
+}
+
+// Start starts the Telegram bot and polls for updates until interrupted
+func (b *Bot) Start() error {
+	b.logger.Info("Starting Telegram bot...")
+
+	ctx, stop := context.WithCancel(context.Background())
+	me, err := b.client.GetMe(ctx)
+	if err != nil {
+		stop()
+		return fmt.Errorf("failed to authenticate with Telegram: %w", err)
+	}
+	b.username = me.Username
+
+	for _, groupID := range b.config.GroupIDs {
+		if err := b.ensureGroupConfig(groupID); err != nil {
+			b.logger.Error("Failed to ensure group config", "group_id", groupID, "error", err)
+		}
+	}
+
+	// Start query processors for the gated groups if event monitoring is enabled
+	if b.queryProcessorManager != nil {
+		if err := b.queryProcessorManager.Start(ctx); err != nil {
+			stop()
+			return fmt.Errorf("failed to start query processor manager: %w", err)
+		}
+		for _, groupID := range b.config.GroupIDs {
+			if err := b.queryProcessorManager.AddGuild(strconv.FormatInt(groupID, 10)); err != nil {
+				b.logger.Error("Failed to add query processor for group", "group_id", groupID, "error", err)
+			}
+		}
+		b.logger.Info("Query processor manager started")
+	}
+
+	b.stop = stop
+	b.done = make(chan struct{})
+	go func() {
+		defer close(b.done)
+		b.poll(ctx)
+	}()
+
+	b.logger.Info("Telegram bot is running. Press Ctrl+C to exit.", "username", b.username, "groups", len(b.config.GroupIDs))
+
+	// Wait for interrupt signal
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	<-signals
+
+	return b.Stop()
+}
+
+// Stop stops the query processors and polling for updates, waiting for the update being handled
+func (b *Bot) Stop() error {
+	b.logger.Info("Stopping Telegram bot...")
+
+	if b.queryProcessorManager != nil {
+		if err := b.queryProcessorManager.Stop(); err != nil {
+			// Work still running resumes from its last saved position on the next start
+			b.logger.Error("Failed to stop query processor manager", "error", err)
+		}
+	}
+
+	if b.stop != nil {
+		b.stop()
+		<-b.done
+	}
+	return nil
+}
+
+// GetPlatform returns the platform adapter
+func (b *Bot) GetPlatform() platforms.Platform {
+	return b.platform
+}
+
+// ensureGroupConfig creates the guild configuration of a gated group, with the verified
+// status as its verified role
+func (b *Bot) ensureGroupConfig(groupID int64) error {
+	guildID := strconv.FormatInt(groupID, 10)
+	if _, err := b.configManager.GetGuildConfig(guildID); !errors.Is(err, storage.ErrGuildConfigNotFound) {
+		return err
+	}
+
+	guildConfig := storage.NewGuildConfig(guildID)
+	guildConfig.VerifiedRoleID = VerifiedRoleID
+	return b.configManager.UpdateGuildConfig(guildID, guildConfig)
+}
+
+// poll long-polls for updates and handles them one at a time until ctx is done
+func (b *Bot) poll(ctx context.Context) {
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := b.client.GetUpdates(ctx, offset, b.config.PollTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.logger.Warn("Failed to get updates", "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			b.handleUpdate(ctx, update)
+		}
+	}
+}
+
+func (b *Bot) handleUpdate(ctx context.Context, update Update) {
+	switch {
+	case update.Message != nil:
+		b.onMessage(ctx, update.Message)
+	case update.ChatMember != nil:
+		b.onChatMember(ctx, update.ChatMember)
+	}
+}
+
+// onMessage answers commands sent in private chats with the bot
+func (b *Bot) onMessage(ctx context.Context, msg *Message) {
+	message := NewTelegramMessage(msg)
+	command, args := parseCommand(message.GetContent(), b.username)
+	if command == "" || msg.From == nil {
+		return
+	}
+	if !message.IsDirectMessage() {
+		if command == "/link" || command == "/unlink" || command == "/status" {
+			b.reply(ctx, msg.Chat.ID, fmt.Sprintf("Send %s to @%s in a private chat.", command, b.username))
+		}
+		return
+	}
+
+	userID := b.platform.GetUserID(message)
+	switch command {
+	case "/start", "/help":
+		b.reply(ctx, msg.Chat.ID, helpText)
+	case "/link":
+		b.handleLink(ctx, msg.Chat.ID, userID, args)
+	case "/unlink":
+		b.handleUnlink(ctx, msg.Chat.ID, userID)
+	case "/status":
+		b.handleStatus(ctx, msg.Chat.ID, userID)
+	default:
+		b.reply(ctx, msg.Chat.ID, "Unknown command.\n\n"+helpText)
+	}
+}
+
+func (b *Bot) handleLink(ctx context.Context, chatID int64, userID string, args []string) {
+	if len(args) != 1 {
+		b.reply(ctx, chatID, "Usage: /link <gno.land address>")
+		return
+	}
+	address := args[0]
+
+	claim, err := b.userFlow.GenerateClaim(userID, address)
+	if err != nil {
+		b.logger.Error("Failed to generate user claim", "error", err, "user_id", userID, "address", address)
+		b.reply(ctx, chatID, "❌ Failed to generate claim. Please try again.")
+		return
+	}
+
+	b.reply(ctx, chatID, fmt.Sprintf("Ready to link your Telegram account to %s.\n\n🔗 Claim on gno.land: %s\n\nSend /status once the transaction is confirmed.", address, b.userFlow.GetClaimURL(claim)))
+}
+
+func (b *Bot) handleUnlink(ctx context.Context, chatID int64, userID string) {
+	linkedAddress, err := b.userFlow.GetLinkedAddress(userID)
+	if err != nil {
+		b.logger.Error("Failed to get linked address", "error", err, "user_id", userID)
+		b.reply(ctx, chatID, "❌ Failed to check linked address.")
+		return
+	}
+	if linkedAddress == "" {
+		b.reply(ctx, chatID, "❌ Your Telegram account is not linked to any gno.land address. There's nothing to unlink.")
+		return
+	}
+
+	claim, err := b.userFlow.GenerateUnlinkClaim(userID, "")
+	if err != nil {
+		b.logger.Error("Failed to generate unlink claim", "error", err, "user_id", userID, "address", linkedAddress)
+		b.reply(ctx, chatID, "❌ Failed to generate unlink claim. Please try again.")
+		return
+	}
+
+	b.reply(ctx, chatID, fmt.Sprintf("Ready to unlink your Telegram account from %s.\n\n🔓 Unlink on gno.land: %s", linkedAddress, b.userFlow.GetClaimURL(claim)))
+}
+
+func (b *Bot) handleStatus(ctx context.Context, chatID int64, userID string) {
+	linkedAddress, err := b.userFlow.GetLinkedAddress(userID)
+	if err != nil {
+		b.logger.Error("Failed to get linked address", "error", err, "user_id", userID)
+		b.reply(ctx, chatID, "❌ Failed to check linked address.")
+		return
+	}
+
+	var status strings.Builder
+	if linkedAddress == "" {
+		status.WriteString("🔗 Linked address: ❌ not linked\nUse /link <address> to link your account.")
+	} else {
+		status.WriteString(fmt.Sprintf("🔗 Linked address: ✅ %s", linkedAddress))
+	}
+
+	if verified := b.syncVerifiedStatus(ctx, userID, linkedAddress != ""); verified > 0 {
+		status.WriteString(fmt.Sprintf("\n✅ Verified in %d group(s)", verified))
+	}
+	b.reply(ctx, chatID, status.String())
+}
+
+// syncVerifiedStatus grants or removes the verified status in each gated group the user is a
+// member of, depending on whether they are linked. Group admins are never restricted. It returns
+// the number of groups the user is verified in.
+func (b *Bot) syncVerifiedStatus(ctx context.Context, userID string, linked bool) int {
+	id, err := parseID(userID)
+	if err != nil {
+		return 0
+	}
+
+	verified := 0
+	for _, groupID := range b.config.GroupIDs {
+		guildID := strconv.FormatInt(groupID, 10)
+		if guildConfig, err := b.configManager.GetGuildConfig(guildID); err == nil && guildConfig.Paused {
+			continue
+		}
+
+		member, err := b.client.GetChatMember(ctx, groupID, id)
+		if err != nil {
+			b.logger.Warn("Failed to get chat member", "group_id", guildID, "user_id", userID, "error", err)
+			continue
+		}
+		if !inGroup(member) {
+			continue
+		}
+
+		hasRole := isVerified(member)
+		switch {
+		case linked && !hasRole:
+			// Recorded first, so verification sweeps revisit the member once they unlink
+			if err := b.configManager.RecordBotAssignedRole(guildID, userID, VerifiedRoleID); err != nil {
+				b.logger.Error("Failed to record verified status", "group_id", guildID, "user_id", userID, "error", err)
+				continue
+			}
+			if err := b.platform.AddRole(guildID, userID, VerifiedRoleID); err != nil {
+				b.logger.Error("Failed to grant verified status", "group_id", guildID, "user_id", userID, "error", err)
+				if err := b.configManager.ClearBotAssignedRole(guildID, userID, VerifiedRoleID); err != nil {
+					b.logger.Warn("Failed to clear verified status record", "group_id", guildID, "user_id", userID, "error", err)
+				}
+				continue
+			}
+			b.logger.Info("Granted verified status", "group_id", guildID, "user_id", userID)
+			verified++
+		case linked:
+			// Members who could post before the group was gated are swept from now on as well
+			if !isAdmin(member) {
+				if err := b.configManager.RecordBotAssignedRole(guildID, userID, VerifiedRoleID); err != nil {
+					b.logger.Warn("Failed to record verified status", "group_id", guildID, "user_id", userID, "error", err)
+				}
+			}
+			verified++
+		case hasRole && !isAdmin(member):
+			if err := b.platform.RemoveRole(guildID, userID, VerifiedRoleID); err != nil {
+				b.logger.Error("Failed to remove verified status", "group_id", guildID, "user_id", userID, "error", err)
+				continue
+			}
+			b.logger.Info("Removed verified status", "group_id", guildID, "user_id", userID)
+			if err := b.configManager.ClearBotAssignedRole(guildID, userID, VerifiedRoleID); err != nil {
+				b.logger.Warn("Failed to clear verified status record", "group_id", guildID, "user_id", userID, "error", err)
+			}
+		}
+	}
+	return verified
+}
+
+// onChatMember restricts members joining a gated group until they link an address, and keeps
+// verification sweeps away from members who left
+func (b *Bot) onChatMember(ctx context.Context, update *ChatMemberUpdated) {
+	if !b.isGatedGroup(update.Chat.ID) || update.NewChatMember.User.IsBot {
+		return
+	}
+	guildID := strconv.FormatInt(update.Chat.ID, 10)
+	userID := strconv.FormatInt(update.NewChatMember.User.ID, 10)
+	if guildConfig, err := b.configManager.GetGuildConfig(guildID); err == nil && guildConfig.Paused {
+		return
+	}
+
+	if left(update) {
+		if b.eventHandlers != nil {
+			if err := b.eventHandlers.HandleMemberLeft(guildID, userID); err != nil {
+				b.logger.Error("Failed to record member departure", "group_id", guildID, "user_id", userID, "error", err)
+			}
+		}
+		return
+	}
+	if !joined(update) {
+		return
+	}
+	if b.eventHandlers != nil {
+		if err := b.eventHandlers.HandleMemberJoined(ctx, guildID, guildMember(guildID, &update.NewChatMember.User)); err != nil {
+			b.logger.Error("Failed to verify rejoining member", "group_id", guildID, "user_id", userID, "error", err)
+		}
+	}
+
+	linkedAddress, err := b.userFlow.GetLinkedAddress(userID)
+	if err != nil {
+		b.logger.Error("Failed to get linked address", "error", err, "group_id", guildID, "user_id", userID)
+		return
+	}
+	if linkedAddress != "" {
+		return
+	}
+
+	if err := b.platform.RemoveRole(guildID, userID, VerifiedRoleID); err != nil {
+		b.logger.Error("Failed to restrict unverified member", "group_id", guildID, "user_id", userID, "error", err)
+		return
+	}
+	b.logger.Info("Restricted unverified member", "group_id", guildID, "user_id", userID)
+	b.reply(ctx, update.Chat.ID, fmt.Sprintf("Welcome! To post here, link your gno.land address: send /link <address> to @%s in a private chat, then /status.", b.username))
+}
+
+// joined reports whether a chat member update is a user joining the chat
+func joined(update *ChatMemberUpdated) bool {
+	wasMember := update.OldChatMember.Status != StatusLeft && update.OldChatMember.Status != StatusKicked
+	return !wasMember && update.NewChatMember.Status == StatusMember
+}
+
+// left reports whether a chat member update is a user leaving the chat or being removed from it
+func left(update *ChatMemberUpdated) bool {
+	return inGroup(&update.OldChatMember) && !inGroup(&update.NewChatMember)
+}
+
+func (b *Bot) isGatedGroup(chatID int64) bool {
+	for _, groupID := range b.config.GroupIDs {
+		if groupID == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bot) reply(ctx context.Context, chatID int64, text string) {
+	if err := b.client.SendMessage(ctx, chatID, text); err != nil {
+		b.logger.Error("Failed to send message", "chat_id", chatID, "error", err)
+	}
+}
+
+// parseCommand splits a "/command@bot arg..." message into the command and its arguments.
+// Commands addressed to another bot are ignored.
+func parseCommand(text, botUsername string) (string, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", nil
+	}
+
+	command, target, addressed := strings.Cut(fields[0], "@")
+	if addressed && !strings.EqualFold(target, botUsername) {
+		return "", nil
+	}
+	return strings.ToLower(command), fields[1:]
+}
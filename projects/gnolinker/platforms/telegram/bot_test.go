@@ -0,0 +1,325 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/allinbits/labs/projects/gnolinker/core/events"
+	"github.com/allinbits/labs/projects/gnolinker/core/graphql"
+	"github.com/allinbits/labs/projects/gnolinker/core/lock"
+	"github.com/allinbits/labs/projects/gnolinker/core/storage"
+)
+
+const (
+	testGroupID = int64(-1001234567890)
+	testUserID  = int64(4242)
+	testAddress = "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"
+)
+
+// apiCall is a Bot API request received by the fake server
+type apiCall struct {
+	method string
+	params map[string]any
+}
+
+// fakeBotAPI serves the Bot API methods the bot uses, answering getChatMember with members
+type fakeBotAPI struct {
+	mu      sync.Mutex
+	calls   []apiCall
+	members map[int64]ChatMember
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	var params map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&params)
+
+	f.mu.Lock()
+	f.calls = append(f.calls, apiCall{method: method, params: params})
+	f.mu.Unlock()
+
+	var result any = true
+	switch method {
+	case "getChatMember":
+		member, ok := f.members[int64(params["user_id"].(float64))]
+		if !ok {
+			member = ChatMember{Status: StatusLeft}
+		}
+		result = member
+	case "getMe":
+		result = User{ID: 1, IsBot: true, Username: "gnolinker_bot"}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+// called returns the calls made to a method
+func (f *fakeBotAPI) called(method string) []apiCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []apiCall
+	for _, call := range f.calls {
+		if call.method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// mockUserLinkingFlow implements workflows.UserLinkingWorkflow backed by a static address map
+type mockUserLinkingFlow struct {
+	addresses map[string]string // platform ID -> gno address
+}
+
+func (m *mockUserLinkingFlow) GenerateClaim(platformID, gnoAddress string) (*core.Claim, error) {
+	return &core.Claim{Type: core.ClaimTypeUserLink, Data: "1," + platformID + "," + gnoAddress}, nil
+}
+
+func (m *mockUserLinkingFlow) GenerateUnlinkClaim(platformID, gnoAddress string) (*core.Claim, error) {
+	return &core.Claim{Type: core.ClaimTypeUserUnlink, Data: "1," + platformID}, nil
+}
+
+func (m *mockUserLinkingFlow) GetLinkedAddress(platformID string) (string, error) {
+	return m.addresses[platformID], nil
+}
+
+func (m *mockUserLinkingFlow) ListLinkedAddresses(platformID string) ([]string, error) {
+	if address := m.addresses[platformID]; address != "" {
+		return []string{address}, nil
+	}
+	return nil, nil
+}
+
+func (m *mockUserLinkingFlow) GetLinkedPlatformID(gnoAddress string) (string, error) {
+	return "", nil
+}
+
+func (m *mockUserLinkingFlow) GetClaimURL(claim *core.Claim) string {
+	return "https://gno.land/claim?data=" + claim.Data
+}
+
+func newTestBot(t *testing.T, members map[int64]ChatMember, addresses map[string]string) (*Bot, *fakeBotAPI) {
+	t.Helper()
+	return newTestBotWithConfig(t, Config{}, members, addresses)
+}
+
+// newTestBotWithConfig creates a bot gating testGroupID against a fake Bot API, with the rest of
+// its config taken from cfg
+func newTestBotWithConfig(t *testing.T, cfg Config, members map[int64]ChatMember, addresses map[string]string) (*Bot, *fakeBotAPI) {
+	t.Helper()
+	api := &fakeBotAPI{members: members}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	logger := core.NewSlogLogger(core.ParseLogLevel("error"))
+	configManager := config.NewConfigManager(storage.NewMemoryConfigStore(), &config.StorageConfig{}, lock.NewNoOpLockManager(), logger)
+	cfg.Token = "test-token"
+	cfg.APIURL = server.URL
+	cfg.GroupIDs = []int64{testGroupID}
+	bot, err := NewBot(cfg, &mockUserLinkingFlow{addresses: addresses}, configManager, logger)
+	if err != nil {
+		t.Fatalf("NewBot() error = %v", err)
+	}
+	if err := bot.ensureGroupConfig(testGroupID); err != nil {
+		t.Fatalf("ensureGroupConfig() error = %v", err)
+	}
+	bot.username = "gnolinker_bot"
+	return bot, api
+}
+
+func privateCommand(text string) Update {
+	return Update{Message: &Message{From: &User{ID: testUserID}, Chat: Chat{ID: testUserID, Type: "private"}, Text: text}}
+}
+
+func TestBot_LinkReturnsClaimURL(t *testing.T) {
+	bot, api := newTestBot(t, nil, nil)
+
+	bot.handleUpdate(t.Context(), privateCommand("/link@gnolinker_bot "+testAddress))
+
+	sent := api.called("sendMessage")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(sent))
+	}
+	want := "https://gno.land/claim?data=1," + strconv.FormatInt(testUserID, 10) + "," + testAddress
+	if text := sent[0].params["text"].(string); !strings.Contains(text, want) {
+		t.Errorf("reply %q does not contain the claim URL %q", text, want)
+	}
+}
+
+func TestBot_StatusGrantsVerifiedStatus(t *testing.T) {
+	members := map[int64]ChatMember{testUserID: {Status: StatusRestricted, IsMember: true}}
+	bot, api := newTestBot(t, members, map[string]string{strconv.FormatInt(testUserID, 10): testAddress})
+
+	bot.handleUpdate(t.Context(), privateCommand("/status"))
+
+	restricted := api.called("restrictChatMember")
+	if len(restricted) != 1 {
+		t.Fatalf("got %d restrictChatMember calls, want 1", len(restricted))
+	}
+	permissions := restricted[0].params["permissions"].(map[string]any)
+	if permissions["can_send_messages"] != true {
+		t.Errorf("linked member should be allowed to post, got permissions %v", permissions)
+	}
+	if text := api.called("sendMessage")[0].params["text"].(string); !strings.Contains(text, "Verified in 1 group") {
+		t.Errorf("status reply = %q", text)
+	}
+
+	guildConfig, err := bot.configManager.GetGuildConfig(strconv.FormatInt(testGroupID, 10))
+	if err != nil {
+		t.Fatalf("GetGuildConfig() error = %v", err)
+	}
+	if !guildConfig.IsBotAssignedRole(strconv.FormatInt(testUserID, 10), VerifiedRoleID) {
+		t.Error("granted verified status should be recorded, so sweeps revisit the member")
+	}
+}
+
+// newMonitoringTestBot creates a bot with event monitoring whose member testUserID was verified
+// by the bot and has since unlinked
+func newMonitoringTestBot(t *testing.T) (*Bot, *fakeBotAPI) {
+	t.Helper()
+	members := map[int64]ChatMember{testUserID: {Status: StatusMember, User: User{ID: testUserID}}}
+	bot, api := newTestBotWithConfig(t, Config{GraphQLEndpoint: "http://indexer.invalid/graphql", UserRealmPath: "gno.land/r/linker000/telegram/user/v0"}, members, nil)
+	if bot.eventHandlers == nil || bot.queryProcessorManager == nil {
+		t.Fatal("event monitoring should be enabled with a GraphQL endpoint")
+	}
+	if err := bot.configManager.RecordBotAssignedRole(strconv.FormatInt(testGroupID, 10), strconv.FormatInt(testUserID, 10), VerifiedRoleID); err != nil {
+		t.Fatalf("RecordBotAssignedRole() error = %v", err)
+	}
+	return bot, api
+}
+
+// assertRestricted checks that testUserID was restricted from posting exactly once
+func assertRestricted(t *testing.T, api *fakeBotAPI) {
+	t.Helper()
+	restricted := api.called("restrictChatMember")
+	if len(restricted) != 1 {
+		t.Fatalf("got %d restrictChatMember calls, want 1", len(restricted))
+	}
+	if restricted[0].params["permissions"].(map[string]any)["can_send_messages"] != false {
+		t.Errorf("unlinked member should not be allowed to post")
+	}
+}
+
+func TestBot_UserUnlinkedEventRevokesVerifiedStatus(t *testing.T) {
+	bot, api := newMonitoringTestBot(t)
+
+	err := bot.eventHandlers.HandleUserUnlinked(events.Event{
+		Type:         events.UserUnlinkedEvent,
+		UserUnlinked: &graphql.UserUnlinkedEvent{Address: testAddress, DiscordID: strconv.FormatInt(testUserID, 10)},
+	})
+	if err != nil {
+		t.Fatalf("HandleUserUnlinked() error = %v", err)
+	}
+	assertRestricted(t, api)
+}
+
+func TestBot_VerificationSweepRevokesUnlinkedMember(t *testing.T) {
+	bot, api := newMonitoringTestBot(t)
+	guildID := strconv.FormatInt(testGroupID, 10)
+
+	state := storage.NewGuildQueryState(guildID, "verify_low_priority", true)
+	if err := bot.eventHandlers.ProcessTieredVerification(t.Context(), guildID, state, "low", 10); err != nil {
+		t.Fatalf("ProcessTieredVerification() error = %v", err)
+	}
+	assertRestricted(t, api)
+
+	guildConfig, err := bot.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		t.Fatalf("GetGuildConfig() error = %v", err)
+	}
+	if guildConfig.IsBotAssignedRole(strconv.FormatInt(testUserID, 10), VerifiedRoleID) {
+		t.Error("revoked verified status should no longer be recorded")
+	}
+}
+
+func TestBot_DepartedMemberIsNotSwept(t *testing.T) {
+	bot, api := newMonitoringTestBot(t)
+	guildID := strconv.FormatInt(testGroupID, 10)
+
+	bot.handleUpdate(t.Context(), Update{ChatMember: &ChatMemberUpdated{
+		Chat:          Chat{ID: testGroupID, Type: "supergroup"},
+		OldChatMember: ChatMember{Status: StatusMember, User: User{ID: testUserID}},
+		NewChatMember: ChatMember{Status: StatusLeft, User: User{ID: testUserID}},
+	}})
+
+	state := storage.NewGuildQueryState(guildID, "verify_low_priority", true)
+	if err := bot.eventHandlers.ProcessTieredVerification(t.Context(), guildID, state, "low", 10); err != nil {
+		t.Fatalf("ProcessTieredVerification() error = %v", err)
+	}
+	if restricted := api.called("restrictChatMember"); len(restricted) != 0 {
+		t.Errorf("departed member should not be swept, got %d restrictChatMember calls", len(restricted))
+	}
+}
+
+func TestBot_UnlinkedMemberIsRestrictedOnJoin(t *testing.T) {
+	tests := []struct {
+		name         string
+		addresses    map[string]string
+		wantRestrict bool
+	}{
+		{name: "unlinked", wantRestrict: true},
+		{name: "linked", addresses: map[string]string{strconv.FormatInt(testUserID, 10): testAddress}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, api := newTestBot(t, nil, tt.addresses)
+
+			bot.handleUpdate(t.Context(), Update{ChatMember: &ChatMemberUpdated{
+				Chat:          Chat{ID: testGroupID, Type: "supergroup"},
+				OldChatMember: ChatMember{Status: StatusLeft, User: User{ID: testUserID}},
+				NewChatMember: ChatMember{Status: StatusMember, User: User{ID: testUserID}},
+			}})
+
+			restricted := api.called("restrictChatMember")
+			if got := len(restricted) == 1; got != tt.wantRestrict {
+				t.Fatalf("restricted = %v, want %v", got, tt.wantRestrict)
+			}
+			if tt.wantRestrict && restricted[0].params["permissions"].(map[string]any)["can_send_messages"] != false {
+				t.Errorf("unlinked member should not be allowed to post")
+			}
+		})
+	}
+}
+
+func TestIsVerified(t *testing.T) {
+	tests := []struct {
+		member ChatMember
+		want   bool
+	}{
+		{ChatMember{Status: StatusCreator}, true},
+		{ChatMember{Status: StatusMember}, true},
+		{ChatMember{Status: StatusRestricted, IsMember: true, CanSendMessages: true}, true},
+		{ChatMember{Status: StatusRestricted, IsMember: true}, false},
+		{ChatMember{Status: StatusLeft}, false},
+	}
+	for _, tt := range tests {
+		if got := isVerified(&tt.member); got != tt.want {
+			t.Errorf("isVerified(%+v) = %v, want %v", tt.member, got, tt.want)
+		}
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		text        string
+		wantCommand string
+		wantArgs    []string
+	}{
+		{"/link g1abc", "/link", []string{"g1abc"}},
+		{"/Status@GnoLinker_Bot", "/status", []string{}},
+		{"/link@other_bot g1abc", "", nil},
+		{"hello", "", nil},
+	}
+	for _, tt := range tests {
+		command, args := parseCommand(tt.text, "gnolinker_bot")
+		if command != tt.wantCommand || strings.Join(args, " ") != strings.Join(tt.wantArgs, " ") {
+			t.Errorf("parseCommand(%q) = %q, %v, want %q, %v", tt.text, command, args, tt.wantCommand, tt.wantArgs)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package telegram
+
+import "time"
+
+// Config holds Telegram-specific configuration
+type Config struct {
+	// Token is the Telegram bot token from @BotFather
+	Token string
+
+	// APIURL is the Bot API endpoint, DefaultAPIURL unless a local Bot API server is used
+	APIURL string
+
+	// PollTimeout is how long each getUpdates long poll waits for updates
+	PollTimeout time.Duration
+
+	// GroupIDs are the supergroups whose members get verified status once they link an address.
+	// The bot must be an admin allowed to restrict members in each of them.
+	GroupIDs []int64
+
+	// GraphQLEndpoint is the indexer watched for links and unlinks of UserRealmPath. Unlinked
+	// members lose their verified status as the events arrive and in periodic verification
+	// sweeps. Empty disables both, leaving verification to joins and /status.
+	GraphQLEndpoint string
+
+	// UserRealmPath is the package path of the user contract, e.g. gno.land/r/linker000/telegram/user/v0
+	UserRealmPath string
+}
@@ -0,0 +1,124 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnolinker/core/config"
+	"github.com/bwmarrin/discordgo"
+)
+
+// errNoPresence is returned for presences, which the Bot API does not report
+var errNoPresence = errors.New("telegram does not report member presence")
+
+// memberDirectory describes the gated groups and their members to the event handlers, as guilds
+// and guild members. The Bot API cannot list a group's members, so verification sweeps go through
+// the members the bot verified, which are recorded in the group's config. Other members are
+// verified when they join, send /status or link or unlink on chain.
+type memberDirectory struct {
+	client        *Client
+	groupIDs      []int64
+	configManager *config.ConfigManager
+}
+
+func (d *memberDirectory) Guilds() []*discordgo.Guild {
+	guilds := make([]*discordgo.Guild, 0, len(d.groupIDs))
+	for _, groupID := range d.groupIDs {
+		guilds = append(guilds, &discordgo.Guild{ID: strconv.FormatInt(groupID, 10)})
+	}
+	return guilds
+}
+
+// Guild returns a gated group, named after the chat's title
+func (d *memberDirectory) Guild(guildID string) (*discordgo.Guild, error) {
+	chatID, err := d.groupID(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	chat, err := d.client.GetChat(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat: %w", err)
+	}
+	return &discordgo.Guild{ID: guildID, Name: chat.Title}, nil
+}
+
+// Member returns a user's membership in a gated group, or an error if they are not in it
+func (d *memberDirectory) Member(guildID, userID string) (*discordgo.Member, error) {
+	chatID, err := d.groupID(guildID)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	member, err := d.client.GetChatMember(ctx, chatID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat member: %w", err)
+	}
+	if !inGroup(member) {
+		return nil, fmt.Errorf("user %s is not in group %s", userID, guildID)
+	}
+	return guildMember(guildID, &member.User), nil
+}
+
+// GuildMembers returns the members the bot verified in a gated group, in a stable order
+func (d *memberDirectory) GuildMembers(guildID string) ([]*discordgo.Member, error) {
+	if _, err := d.groupID(guildID); err != nil {
+		return nil, err
+	}
+	guildConfig, err := d.configManager.GetGuildConfig(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild config: %w", err)
+	}
+
+	var members []*discordgo.Member
+	for userID, roleIDs := range guildConfig.BotAssignedRoles {
+		if slices.Contains(roleIDs, VerifiedRoleID) {
+			members = append(members, &discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: userID}})
+		}
+	}
+	slices.SortFunc(members, func(a, b *discordgo.Member) int {
+		return strings.Compare(a.User.ID, b.User.ID)
+	})
+	return members, nil
+}
+
+// Presence returns errNoPresence, so sweeps treat every member as offline
+func (d *memberDirectory) Presence(guildID, userID string) (*discordgo.Presence, error) {
+	return nil, errNoPresence
+}
+
+// groupID parses a guild ID, which must be one of the gated groups
+func (d *memberDirectory) groupID(guildID string) (int64, error) {
+	chatID, err := parseID(guildID)
+	if err != nil {
+		return 0, err
+	}
+	if !slices.Contains(d.groupIDs, chatID) {
+		return 0, fmt.Errorf("group %s is not gated by the bot", guildID)
+	}
+	return chatID, nil
+}
+
+// guildMember describes a Telegram user as a member of a gated group
+func guildMember(guildID string, user *User) *discordgo.Member {
+	return &discordgo.Member{
+		GuildID: guildID,
+		User: &discordgo.User{
+			ID:       strconv.FormatInt(user.ID, 10),
+			Username: user.Username,
+			Bot:      user.IsBot,
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package telegram
+
+import "strconv"
+
+// TelegramMessage wraps a Telegram message to implement the Message interface
+type TelegramMessage struct {
+	msg *Message
+}
+
+// NewTelegramMessage creates a new TelegramMessage wrapper
+func NewTelegramMessage(msg *Message) *TelegramMessage {
+	return &TelegramMessage{msg: msg}
+}
+
+// GetAuthorID returns the Telegram user ID of the message author
+func (m *TelegramMessage) GetAuthorID() string {
+	if m.msg.From == nil {
+		return ""
+	}
+	return strconv.FormatInt(m.msg.From.ID, 10)
+}
+
+// GetContent returns the message text
+func (m *TelegramMessage) GetContent() string {
+	return m.msg.Text
+}
+
+// GetChannelID returns the chat ID where the message was sent
+func (m *TelegramMessage) GetChannelID() string {
+	return strconv.FormatInt(m.msg.Chat.ID, 10)
+}
+
+// IsDirectMessage returns true if the message was sent in a private chat with the bot
+func (m *TelegramMessage) IsDirectMessage() bool {
+	return m.msg.Chat.Type == "private"
+}
@@ -0,0 +1,182 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnolinker/core"
+	"github.com/allinbits/labs/projects/gnolinker/platforms"
+)
+
+// VerifiedRoleID identifies the verified status, the only role Telegram groups support.
+// Telegram has no custom roles: a verified member may post, an unverified one is restricted.
+const VerifiedRoleID = "verified"
+
+// requestTimeout bounds each Bot API call made on behalf of the platform interface
+const requestTimeout = 10 * time.Second
+
+// ErrUnsupportedRole is returned for roles other than the verified status
+var ErrUnsupportedRole = errors.New("telegram groups only support the verified role")
+
+// TelegramPlatform implements the Platform interface for Telegram. Guild IDs are supergroup
+// chat IDs and user IDs are Telegram user IDs, both in decimal.
+type TelegramPlatform struct {
+	client *Client
+}
+
+// NewTelegramPlatform creates a new Telegram platform adapter
+func NewTelegramPlatform(client *Client) platforms.Platform {
+	return &TelegramPlatform{client: client}
+}
+
+// GetUserID returns the user ID from a message
+func (p *TelegramPlatform) GetUserID(message platforms.Message) string {
+	return message.GetAuthorID()
+}
+
+// SendDirectMessage sends a message to a user's private chat with the bot. The user
+// must have started the bot first.
+func (p *TelegramPlatform) SendDirectMessage(userID, content string) error {
+	id, err := parseID(userID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	if err := p.client.SendMessage(ctx, id, content); err != nil {
+		return fmt.Errorf("failed to send DM: %w", err)
+	}
+	return nil
+}
+
+// HasRole checks if a user is a verified member of the group, i.e. a member who is not
+// restricted from posting
+func (p *TelegramPlatform) HasRole(guildID, userID, roleID string) (bool, error) {
+	if roleID != VerifiedRoleID {
+		return false, ErrUnsupportedRole
+	}
+	chatID, id, err := parseIDs(guildID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	member, err := p.client.GetChatMember(ctx, chatID, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get chat member: %w", err)
+	}
+	return isVerified(member), nil
+}
+
+// AddRole verifies a user by lifting their posting restrictions
+func (p *TelegramPlatform) AddRole(guildID, userID, roleID string) error {
+	return p.restrict(guildID, userID, roleID, memberPermissions)
+}
+
+// RemoveRole unverifies a user by restricting them from posting. Group admins cannot be
+// restricted and are left as they are.
+func (p *TelegramPlatform) RemoveRole(guildID, userID, roleID string) error {
+	if roleID != VerifiedRoleID {
+		return ErrUnsupportedRole
+	}
+	chatID, id, err := parseIDs(guildID, userID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	member, err := p.client.GetChatMember(ctx, chatID, id)
+	if err != nil {
+		return fmt.Errorf("failed to get chat member: %w", err)
+	}
+	if isAdmin(member) {
+		return nil
+	}
+	return p.restrict(guildID, userID, roleID, ChatPermissions{})
+}
+
+func (p *TelegramPlatform) restrict(guildID, userID, roleID string, permissions ChatPermissions) error {
+	if roleID != VerifiedRoleID {
+		return ErrUnsupportedRole
+	}
+	chatID, id, err := parseIDs(guildID, userID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	if err := p.client.RestrictChatMember(ctx, chatID, id, permissions); err != nil {
+		return fmt.Errorf("failed to restrict chat member: %w", err)
+	}
+	return nil
+}
+
+// GetOrCreateRole returns the verified status under the given name. Other roles cannot be
+// created in Telegram groups, so any name maps to the verified status.
+func (p *TelegramPlatform) GetOrCreateRole(guildID, name string) (*core.PlatformRole, error) {
+	return &core.PlatformRole{ID: VerifiedRoleID, Name: name}, nil
+}
+
+// GetRoleByID retrieves a role by its ID
+func (p *TelegramPlatform) GetRoleByID(guildID, roleID string) (*core.PlatformRole, error) {
+	if roleID != VerifiedRoleID {
+		return nil, fmt.Errorf("role not found: %s", roleID)
+	}
+	return &core.PlatformRole{ID: VerifiedRoleID, Name: "Verified"}, nil
+}
+
+// isVerified reports whether a chat member can post: admins and unrestricted members
+func isVerified(member *ChatMember) bool {
+	switch member.Status {
+	case StatusCreator, StatusAdministrator, StatusMember:
+		return true
+	case StatusRestricted:
+		return member.IsMember && member.CanSendMessages
+	default:
+		return false
+	}
+}
+
+// isAdmin reports whether a chat member administers the chat
+func isAdmin(member *ChatMember) bool {
+	return member.Status == StatusCreator || member.Status == StatusAdministrator
+}
+
+// inGroup reports whether a chat member is currently in the chat
+func inGroup(member *ChatMember) bool {
+	switch member.Status {
+	case StatusLeft, StatusKicked:
+		return false
+	case StatusRestricted:
+		return member.IsMember
+	default:
+		return true
+	}
+}
+
+func parseID(id string) (int64, error) {
+	parsed, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid telegram ID %q: %w", id, err)
+	}
+	return parsed, nil
+}
+
+func parseIDs(chatID, userID string) (int64, int64, error) {
+	chat, err := parseID(chatID)
+	if err != nil {
+		return 0, 0, err
+	}
+	user, err := parseID(userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return chat, user, nil
+}
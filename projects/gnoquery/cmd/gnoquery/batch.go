@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnoquery"
+)
+
+// batchResult is the outcome of one function call of a batch run
+type batchResult struct {
+	Call string `json:"call"`
+	// Height is the block height the call was evaluated at, omitted if the client does not report it
+	Height int64 `json:"height,omitempty"`
+	// Result is the realm result, nested as-is when it is JSON and as a string otherwise
+	Result any    `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// runBatch evaluates each call against realmPath in order with the same client, each bounded by
+// timeout. A failed call is recorded in its result and does not stop the remaining calls.
+func runBatch(ctx context.Context, client gnoquery.Client, realmPath string, calls []string, timeout time.Duration) []batchResult {
+	results := make([]batchResult, 0, len(calls))
+	for _, call := range calls {
+		queryCtx, cancel := withTimeout(ctx, timeout)
+		result, height, err := query(queryCtx, client, realmPath, call)
+		cancel()
+
+		if err != nil {
+			results = append(results, batchResult{Call: call, Error: err.Error()})
+			continue
+		}
+		results = append(results, batchResult{Call: call, Height: height, Result: jsonResult(result)})
+	}
+	return results
+}
+
+// batchFailed reports whether any call of a batch run failed.
+func batchFailed(results []batchResult) bool {
+	for _, result := range results {
+		if result.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// printBatch writes the results of a batch run to out as a JSON array.
+func printBatch(out io.Writer, results []batchResult) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// readBatchFile reads newline-separated function calls from path, or from stdin if path is "-".
+// Blank lines are skipped.
+func readBatchFile(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open batch file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var calls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if call := strings.TrimSpace(scanner.Text()); call != "" {
+			calls = append(calls, call)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+	return calls, nil
+}
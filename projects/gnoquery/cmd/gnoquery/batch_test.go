@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// callClient answers each function call from a fixed table and records the calls it served
+type callClient struct {
+	results map[string]string
+	errs    map[string]error
+	calls   []string
+}
+
+func (c *callClient) Query(realmPath, functionCall string) (string, error) {
+	return c.QueryContext(context.Background(), realmPath, functionCall)
+}
+
+func (c *callClient) QueryContext(ctx context.Context, realmPath, functionCall string) (string, error) {
+	c.calls = append(c.calls, functionCall)
+	return c.results[functionCall], c.errs[functionCall]
+}
+
+func TestRunBatchReportsPerCallErrors(t *testing.T) {
+	client := &callClient{
+		results: map[string]string{
+			"Count()":  "(3 int)",
+			"Config()": `("{\"open\":true}" string)`,
+		},
+		errs: map[string]error{
+			"Missing()": errors.New("name Missing not declared"),
+		},
+	}
+	calls := []string{"Count()", "Missing()", "Config()"}
+
+	results := runBatch(context.Background(), client, "gno.land/r/test", calls, time.Second)
+
+	if !reflect.DeepEqual(client.calls, calls) {
+		t.Errorf("calls = %v, want every call in order %v", client.calls, calls)
+	}
+	if !batchFailed(results) {
+		t.Error("batchFailed() = false, want the failed call reported")
+	}
+
+	var out bytes.Buffer
+	if err := printBatch(&out, results); err != nil {
+		t.Fatalf("printBatch() error = %v", err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a JSON array: %v\n%s", err, out.String())
+	}
+	want := []map[string]any{
+		{"call": "Count()", "result": "(3 int)"},
+		{"call": "Missing()", "result": nil, "error": "name Missing not declared"},
+		{"call": "Config()", "result": map[string]any{"open": true}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("printBatch() = %v, want %v", got, want)
+	}
+}
+
+func TestParseArgsBatch(t *testing.T) {
+	batchFile := filepath.Join(t.TempDir(), "calls.txt")
+	if err := os.WriteFile(batchFile, []byte("Count()\n\nConfig()\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := parseArgs([]string{"gno.land/r/test", "Count()", "Config()"})
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if want := []string{"Count()", "Config()"}; !reflect.DeepEqual(opts.batch, want) {
+		t.Errorf("batch = %v, want %v", opts.batch, want)
+	}
+
+	opts, err = parseArgs([]string{"-batch", batchFile, "gno.land/r/test"})
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if want := []string{"Count()", "Config()"}; !reflect.DeepEqual(opts.batch, want) {
+		t.Errorf("batch from file = %v, want %v", opts.batch, want)
+	}
+
+	// A single call is not a batch
+	opts, err = parseArgs([]string{"gno.land/r/test", "Count()"})
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if opts.batch != nil || opts.functionCall != "Count()" {
+		t.Errorf("single call parsed as batch = %v, function = %q", opts.batch, opts.functionCall)
+	}
+
+	if _, err := parseArgs([]string{"-watch", "5s", "gno.land/r/test", "Count()", "Config()"}); err == nil {
+		t.Error("parseArgs() should reject -watch with several calls")
+	}
+}
@@ -1,41 +1,108 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/allinbits/labs/projects/gnoquery"
 )
 
+// options holds the parsed command line configuration for a gnoquery invocation.
+type options struct {
+	remote       string
+	realmPath    string
+	functionCall string
+	// watch is the polling interval for watch mode; zero disables watching
+	watch time.Duration
+	// output is the output format, outputText or outputJSON
+	output string
+	// timeout bounds each query; zero disables it
+	timeout time.Duration
+	// batch holds the function calls of a batch run; nil for a single call
+	batch []string
+	// retries is how many times a query failing to reach the node is retried
+	retries int
+	// retryInterval is the wait before the first retry, doubled for each further one
+	retryInterval time.Duration
+	// height is the block height to query the realm state at; zero is the latest block
+	height int64
+	// repl reads function calls from stdin instead of running one from the arguments
+	repl bool
+}
+
 func main() {
 	// Remove date and time from log output
 	log.SetFlags(0)
 
 	// Parse command line arguments and environment variables
-	remote, realmPath, functionCall, err := parseArgs(os.Args[1:])
+	opts, err := parseArgs(os.Args[1:])
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// Create the Gno client and query the realm, handling any errors
-	result, err := gnoquery.NewClient(remote).Query(realmPath, functionCall)
+	// Create the Gno client once so repeated polls reuse the same connection
+	client := gnoquery.NewClient(opts.remote,
+		gnoquery.WithRetry(opts.retries, opts.retryInterval),
+		gnoquery.AtHeight(opts.height),
+	)
+
+	// Stop on Ctrl-C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if opts.repl {
+		if err := repl(ctx, client, opts, os.Stdin, os.Stdout, os.Stderr); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if opts.batch != nil {
+		results := runBatch(ctx, client, opts.realmPath, opts.batch, opts.timeout)
+		if err := printBatch(os.Stdout, results); err != nil {
+			log.Fatal(fmt.Errorf("error printing results: %v", err))
+		}
+		if batchFailed(results) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if opts.watch > 0 {
+		if err := watch(ctx, client, opts.realmPath, opts.functionCall, opts.watch, opts.timeout, os.Stdout, os.Stderr); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Query the realm, handling any errors
+	queryCtx, cancel := withTimeout(ctx, opts.timeout)
+	result, height, err := query(queryCtx, client, opts.realmPath, opts.functionCall)
+	cancel()
 	if err != nil {
 		log.Fatal(fmt.Errorf("error executing query: %v", err))
 	}
 
 	// Print the result of the query to stdout
-	fmt.Println(result)
+	if err := printResult(os.Stdout, opts, result, height); err != nil {
+		log.Fatal(fmt.Errorf("error printing result: %v", err))
+	}
 }
 
 // parseArgs parses command line arguments and environment variables for the gnoquery CLI.
 // args contains the command line arguments to parse (typically os.Args[1:]).
-// Returns remote URL, realm path, function call, and error.
+// Returns the parsed options and error.
 // The remote URL can be overridden by the GNOQUERY_REMOTE environment variable.
-// Expects exactly two positional arguments: realm_path and function_call.
+// Expects a realm_path and a function_call positional argument. Further function calls, or
+// calls read from a -batch file, make a batch run.
 // Returns an error if parsing fails or required arguments are missing.
-func parseArgs(args []string) (string, string, string, error) {
+func parseArgs(args []string) (options, error) {
 	fs := flag.NewFlagSet("gnoquery", flag.ContinueOnError)
 
 	// Get default remote from environment or use default
@@ -45,10 +112,18 @@ func parseArgs(args []string) (string, string, string, error) {
 	}
 
 	remote := fs.String("remote", defaultRemote, "Remote node URL (can also be set via GNOQUERY_REMOTE env var)")
+	watchInterval := fs.Duration("watch", 0, "Re-run the query at this interval (e.g. 5s) and print the result when it changes")
+	timeout := fs.Duration("timeout", 30*time.Second, "Give up on a query after this long (0 waits indefinitely)")
+	retries := fs.Int("retries", gnoquery.DefaultRetries, "Retry a query this many times when the node cannot be reached or returns a server error")
+	retryInterval := fs.Duration("retry-interval", gnoquery.DefaultRetryInterval, "Wait before the first retry, doubled for each further retry")
+	height := fs.Int64("height", 0, "Query the realm state at this block height (0 for the latest block)")
+	output := fs.String("output", outputText, "Output format: text prints the raw result, json wraps it with the query metadata")
+	batchFile := fs.String("batch", "", "Read newline-separated function calls from this file (- for stdin) and print the results as a JSON array")
 
 	// Set custom usage
 	fs.Usage = func() {
-		fmt.Fprintln(fs.Output(), "Usage: gnoquery [flags] <realm_path> <function_call>")
+		fmt.Fprintln(fs.Output(), "Usage: gnoquery [flags] <realm_path> <function_call> [function_call...]")
+		fmt.Fprintln(fs.Output(), "       gnoquery [flags] repl <realm_path>")
 		fmt.Fprintln(fs.Output(), "")
 		fmt.Fprintln(fs.Output(), `Example: gnoquery gno.land/r/linker000/discord/user/v0 'GetLinkedAddress("123456789")'`)
 		fmt.Fprintln(fs.Output(), "\nFlags:")
@@ -56,13 +131,92 @@ func parseArgs(args []string) (string, string, string, error) {
 	}
 
 	if err := fs.Parse(args); err != nil {
-		return "", "", "", err
+		return options{}, err
+	}
+
+	if *watchInterval < 0 {
+		return options{}, fmt.Errorf("invalid watch interval: %v", *watchInterval)
+	}
+
+	if *timeout < 0 {
+		return options{}, fmt.Errorf("invalid timeout: %v", *timeout)
+	}
+
+	if *retries < 0 {
+		return options{}, fmt.Errorf("invalid retries: %d", *retries)
+	}
+
+	if *retryInterval < 0 {
+		return options{}, fmt.Errorf("invalid retry interval: %v", *retryInterval)
+	}
+
+	if *height < 0 {
+		return options{}, fmt.Errorf("invalid height: %d", *height)
 	}
 
-	if fs.NArg() < 2 {
+	if *output != outputText && *output != outputJSON {
+		return options{}, fmt.Errorf("invalid output format %q: must be %s or %s", *output, outputText, outputJSON)
+	}
+
+	if *output == outputJSON && *watchInterval > 0 {
+		return options{}, fmt.Errorf("-output %s cannot be combined with -watch", outputJSON)
+	}
+
+	if fs.NArg() < 2 && (*batchFile == "" || fs.NArg() < 1) {
 		fs.Usage()
 		os.Exit(1)
 	}
 
-	return *remote, fs.Arg(0), fs.Arg(1), nil
+	opts := options{
+		remote:    *remote,
+		realmPath: fs.Arg(0),
+		watch:     *watchInterval,
+		output:    *output,
+		timeout:   *timeout,
+
+		retries:       *retries,
+		retryInterval: *retryInterval,
+		height:        *height,
+	}
+
+	if fs.Arg(0) == replCommand {
+		if fs.NArg() != 2 || *batchFile != "" || *watchInterval > 0 {
+			return options{}, fmt.Errorf("repl takes a single realm path and cannot be combined with -batch or -watch")
+		}
+		opts.repl = true
+		opts.realmPath = fs.Arg(1)
+		return opts, nil
+	}
+
+	if fs.NArg() == 2 && *batchFile == "" {
+		opts.functionCall = fs.Arg(1)
+		return opts, nil
+	}
+
+	opts.batch = fs.Args()[1:]
+	if *batchFile != "" {
+		calls, err := readBatchFile(*batchFile)
+		if err != nil {
+			return options{}, err
+		}
+		opts.batch = append(opts.batch, calls...)
+	}
+
+	if len(opts.batch) == 0 {
+		return options{}, fmt.Errorf("no function calls in batch file %s", *batchFile)
+	}
+
+	if *watchInterval > 0 {
+		return options{}, fmt.Errorf("-watch cannot be combined with several function calls")
+	}
+
+	return opts, nil
+}
+
+// withTimeout returns a context bounded by timeout, or ctx itself if timeout is zero.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestParseArgs(t *testing.T) {
@@ -23,6 +24,8 @@ func TestParseArgs(t *testing.T) {
 		wantRemote   string
 		wantRealm    string
 		wantFunction string
+		wantWatch    time.Duration
+		wantOutput   string
 		wantErr      bool
 	}{
 		{
@@ -50,6 +53,54 @@ func TestParseArgs(t *testing.T) {
 			wantFunction: "GetInfo()",
 			wantErr:      false,
 		},
+		{
+			name:         "args with watch interval",
+			args:         []string{"-watch", "5s", "gno.land/r/test", "GetInfo()"},
+			wantRemote:   "tcp://0.0.0.0:26657",
+			wantRealm:    "gno.land/r/test",
+			wantFunction: "GetInfo()",
+			wantWatch:    5 * time.Second,
+			wantErr:      false,
+		},
+		{
+			name:         "args with json output",
+			args:         []string{"-output", "json", "gno.land/r/test", "GetInfo()"},
+			wantRemote:   "tcp://0.0.0.0:26657",
+			wantRealm:    "gno.land/r/test",
+			wantFunction: "GetInfo()",
+			wantOutput:   "json",
+			wantErr:      false,
+		},
+		{
+			name:    "unknown output format",
+			args:    []string{"-output", "yaml", "gno.land/r/test", "GetInfo()"},
+			wantErr: true,
+		},
+		{
+			name:    "json output with watch",
+			args:    []string{"-output", "json", "-watch", "5s", "gno.land/r/test", "GetInfo()"},
+			wantErr: true,
+		},
+		{
+			name:    "negative timeout",
+			args:    []string{"-timeout", "-1s", "gno.land/r/test", "GetInfo()"},
+			wantErr: true,
+		},
+		{
+			name:    "negative retries",
+			args:    []string{"-retries", "-1", "gno.land/r/test", "GetInfo()"},
+			wantErr: true,
+		},
+		{
+			name:    "negative height",
+			args:    []string{"-height", "-5", "gno.land/r/test", "GetInfo()"},
+			wantErr: true,
+		},
+		{
+			name:    "negative watch interval",
+			args:    []string{"-watch", "-1s", "gno.land/r/test", "GetInfo()"},
+			wantErr: true,
+		},
 		// Note: We can't easily test missing/no arguments because parseArgs calls os.Exit(1)
 		// instead of returning an error. This would require refactoring to make it testable.
 		{
@@ -68,7 +119,7 @@ func TestParseArgs(t *testing.T) {
 				os.Unsetenv("GNOQUERY_REMOTE")
 			}
 
-			remote, realm, function, err := parseArgs(tt.args)
+			opts, err := parseArgs(tt.args)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
@@ -76,14 +127,24 @@ func TestParseArgs(t *testing.T) {
 			}
 
 			if !tt.wantErr {
-				if remote != tt.wantRemote {
-					t.Errorf("parseArgs() remote = %v, want %v", remote, tt.wantRemote)
+				if opts.remote != tt.wantRemote {
+					t.Errorf("parseArgs() remote = %v, want %v", opts.remote, tt.wantRemote)
+				}
+				if opts.realmPath != tt.wantRealm {
+					t.Errorf("parseArgs() realm = %v, want %v", opts.realmPath, tt.wantRealm)
+				}
+				if opts.functionCall != tt.wantFunction {
+					t.Errorf("parseArgs() function = %v, want %v", opts.functionCall, tt.wantFunction)
+				}
+				if opts.watch != tt.wantWatch {
+					t.Errorf("parseArgs() watch = %v, want %v", opts.watch, tt.wantWatch)
 				}
-				if realm != tt.wantRealm {
-					t.Errorf("parseArgs() realm = %v, want %v", realm, tt.wantRealm)
+				wantOutput := tt.wantOutput
+				if wantOutput == "" {
+					wantOutput = "text"
 				}
-				if function != tt.wantFunction {
-					t.Errorf("parseArgs() function = %v, want %v", function, tt.wantFunction)
+				if opts.output != wantOutput {
+					t.Errorf("parseArgs() output = %v, want %v", opts.output, wantOutput)
 				}
 			}
 		})
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnoquery"
+)
+
+// Output formats accepted by the -output flag
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// queryOutput is the envelope printed for a query in JSON output mode
+type queryOutput struct {
+	Realm    string `json:"realm"`
+	Function string `json:"function"`
+	Remote   string `json:"remote"`
+	// Height is the block height the query was evaluated at, omitted if the client does not report it
+	Height int64 `json:"height,omitempty"`
+	// Result is the realm result, nested as-is when it is JSON and as a string otherwise
+	Result any `json:"result"`
+}
+
+// query executes functionCall against realmPath until ctx is done, returning the block height
+// as well when the client reports it.
+func query(ctx context.Context, client gnoquery.Client, realmPath, functionCall string) (string, int64, error) {
+	if hq, ok := client.(gnoquery.HeightQuerier); ok {
+		return hq.QueryWithHeight(ctx, realmPath, functionCall)
+	}
+	result, err := gnoquery.QueryContext(ctx, client, realmPath, functionCall)
+	return result, 0, err
+}
+
+// printResult writes a query result to out in the selected output format.
+func printResult(out io.Writer, opts options, result string, height int64) error {
+	if opts.output != outputJSON {
+		_, err := fmt.Fprintln(out, result)
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(queryOutput{
+		Realm:    opts.realmPath,
+		Function: opts.functionCall,
+		Remote:   opts.remote,
+		Height:   height,
+		Result:   jsonResult(result),
+	})
+}
+
+// jsonResult returns result as a nested JSON value when it is valid JSON, either as is or as a
+// single Gno string value such as ("{\"a\":1}" string). Any other result is returned as a string.
+func jsonResult(result string) any {
+	value := strings.TrimSpace(result)
+	if json.Valid([]byte(value)) {
+		return json.RawMessage(value)
+	}
+
+	if quoted, ok := strings.CutPrefix(value, "("); ok {
+		if quoted, ok = strings.CutSuffix(quoted, " string)"); ok {
+			if unquoted, err := strconv.Unquote(quoted); err == nil && json.Valid([]byte(unquoted)) {
+				return json.RawMessage(unquoted)
+			}
+		}
+	}
+
+	return result
+}
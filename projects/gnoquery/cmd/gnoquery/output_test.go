@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// heightClient returns a fixed result along with the height it was evaluated at
+type heightClient struct {
+	result string
+	height int64
+}
+
+func (h *heightClient) Query(realmPath, functionCall string) (string, error) {
+	return h.result, nil
+}
+
+func (h *heightClient) QueryContext(ctx context.Context, realmPath, functionCall string) (string, error) {
+	return h.result, nil
+}
+
+func (h *heightClient) QueryWithHeight(ctx context.Context, realmPath, functionCall string) (string, int64, error) {
+	return h.result, h.height, nil
+}
+
+func TestPrintResultJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     string
+		wantResult string
+	}{
+		{
+			name:       "scalar result",
+			result:     "(42 int)",
+			wantResult: `"(42 int)"`,
+		},
+		{
+			name:       "json returning function",
+			result:     `("{\"name\":\"alice\",\"roles\":[\"admin\"]}" string)`,
+			wantResult: `{"name":"alice","roles":["admin"]}`,
+		},
+		{
+			name:       "raw json result",
+			result:     `[1,2,3]`,
+			wantResult: `[1,2,3]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options{
+				remote:       "tcp://localhost:26657",
+				realmPath:    "gno.land/r/test",
+				functionCall: "GetInfo()",
+				output:       outputJSON,
+			}
+			result, height, err := query(context.Background(), &heightClient{result: tt.result, height: 1234}, opts.realmPath, opts.functionCall)
+			if err != nil {
+				t.Fatalf("query() error = %v", err)
+			}
+
+			var out bytes.Buffer
+			if err := printResult(&out, opts, result, height); err != nil {
+				t.Fatalf("printResult() error = %v", err)
+			}
+
+			var got struct {
+				Realm    string          `json:"realm"`
+				Function string          `json:"function"`
+				Remote   string          `json:"remote"`
+				Height   int64           `json:"height"`
+				Result   json.RawMessage `json:"result"`
+			}
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatalf("output is not valid JSON: %v\n%s", err, out.String())
+			}
+			if got.Realm != opts.realmPath || got.Function != opts.functionCall || got.Remote != opts.remote || got.Height != 1234 {
+				t.Errorf("metadata = %+v, want the query realm, function, remote and height", got)
+			}
+
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, got.Result); err != nil {
+				t.Fatalf("json.Compact() error = %v", err)
+			}
+			if compact.String() != tt.wantResult {
+				t.Errorf("result = %s, want %s", compact.String(), tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestPrintResultText(t *testing.T) {
+	var out bytes.Buffer
+	if err := printResult(&out, options{output: outputText}, "(42 int)", 1234); err != nil {
+		t.Fatalf("printResult() error = %v", err)
+	}
+	if out.String() != "(42 int)\n" {
+		t.Errorf("printResult() = %q, want the raw result", out.String())
+	}
+}
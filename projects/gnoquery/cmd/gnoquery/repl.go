@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/allinbits/labs/projects/gnoquery"
+)
+
+// replCommand is the positional argument that starts the interactive mode
+const replCommand = "repl"
+
+// repl reads function calls line by line from in and evaluates each against the current realm
+// with the same client, printing results to out in the selected output format. Query errors
+// are reported to errOut and do not end the session.
+//
+// ":realm <path>" switches the realm queried and ":quit" ends the session, as does the end of
+// in or ctx being cancelled. The prompt is written to errOut so out only holds results.
+func repl(ctx context.Context, client gnoquery.Client, opts options, in io.Reader, out, errOut io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(errOut, "%s> ", opts.realmPath)
+		if !scanner.Scan() {
+			fmt.Fprintln(errOut)
+			return scanner.Err()
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case line == ":quit":
+			return nil
+		case line == ":realm" || strings.HasPrefix(line, ":realm "):
+			if realmPath := strings.TrimSpace(strings.TrimPrefix(line, ":realm")); realmPath != "" {
+				opts.realmPath = realmPath
+			}
+			fmt.Fprintf(errOut, "realm: %s\n", opts.realmPath)
+			continue
+		case strings.HasPrefix(line, ":"):
+			fmt.Fprintf(errOut, "unknown command %s, use :realm <path> or :quit\n", line)
+			continue
+		}
+
+		opts.functionCall = line
+		queryCtx, cancel := withTimeout(ctx, opts.timeout)
+		result, height, err := query(queryCtx, client, opts.realmPath, opts.functionCall)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(errOut, "error executing query: %v\n", err)
+			continue
+		}
+
+		if err := printResult(out, opts, result, height); err != nil {
+			return fmt.Errorf("error printing result: %w", err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// echoClient answers each query with the realm and call it was made for, failing calls to Fail()
+type echoClient struct {
+	queries int
+}
+
+func (e *echoClient) Query(realmPath, functionCall string) (string, error) {
+	return e.QueryContext(context.Background(), realmPath, functionCall)
+}
+
+func (e *echoClient) QueryContext(ctx context.Context, realmPath, functionCall string) (string, error) {
+	e.queries++
+	if functionCall == "Fail()" {
+		return "", errors.New("realm panicked")
+	}
+	return realmPath + "." + functionCall, nil
+}
+
+func TestREPLRunsScriptedInput(t *testing.T) {
+	client := &echoClient{}
+	input := strings.Join([]string{
+		"Count()",
+		"",
+		"Fail()",
+		":realm gno.land/r/other",
+		"Render(\"\")",
+		":quit",
+		"Ignored()",
+	}, "\n")
+	opts := options{realmPath: "gno.land/r/test", output: outputText, timeout: time.Second}
+
+	var out, errOut bytes.Buffer
+	if err := repl(context.Background(), client, opts, strings.NewReader(input), &out, &errOut); err != nil {
+		t.Fatalf("repl() error = %v", err)
+	}
+
+	want := "gno.land/r/test.Count()\ngno.land/r/other.Render(\"\")\n"
+	if out.String() != want {
+		t.Errorf("repl() output = %q, want %q", out.String(), want)
+	}
+	if client.queries != 3 {
+		t.Errorf("queries = %d, want 3 with nothing run after :quit", client.queries)
+	}
+	if !strings.Contains(errOut.String(), "realm panicked") {
+		t.Errorf("expected the query error on stderr, got %q", errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "realm: gno.land/r/other") {
+		t.Errorf("expected the realm switch to be confirmed, got %q", errOut.String())
+	}
+}
+
+func TestParseArgsREPL(t *testing.T) {
+	opts, err := parseArgs([]string{"repl", "gno.land/r/test"})
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if !opts.repl || opts.realmPath != "gno.land/r/test" {
+		t.Errorf("parseArgs() = %+v, want repl mode on gno.land/r/test", opts)
+	}
+
+	if _, err := parseArgs([]string{"-watch", "5s", "repl", "gno.land/r/test"}); err == nil {
+		t.Error("parseArgs() should reject repl with -watch")
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/allinbits/labs/projects/gnoquery"
+)
+
+// watcher tracks the last observed query result so that repeated polls only
+// report output when the realm state actually changes.
+type watcher struct {
+	last string
+	seen bool
+}
+
+// observe records result and reports whether it differs from the previous one.
+// The first observation is always considered a change.
+// Returns the diff against the previous result (or the full result on the first call)
+// and true if the result changed.
+func (w *watcher) observe(result string) (string, bool) {
+	if w.seen && result == w.last {
+		return "", false
+	}
+
+	var out string
+	if !w.seen {
+		out = result
+	} else {
+		out = diffLines(w.last, result)
+	}
+
+	w.last = result
+	w.seen = true
+	return out, true
+}
+
+// watch re-evaluates functionCall against realmPath every interval until ctx is cancelled.
+// Output is written to out only when the result changes, prefixed with a timestamp.
+// Each poll is bounded by timeout, zero disables it.
+// Query errors are reported to errOut and do not stop the watch loop.
+// Returns nil when ctx is cancelled.
+func watch(ctx context.Context, client gnoquery.Client, realmPath, functionCall string, interval, timeout time.Duration, out, errOut io.Writer) error {
+	w := &watcher{}
+
+	poll := func() {
+		queryCtx, cancel := withTimeout(ctx, timeout)
+		result, err := gnoquery.QueryContext(queryCtx, client, realmPath, functionCall)
+		cancel()
+		now := time.Now().Format(time.RFC3339)
+		if err != nil {
+			fmt.Fprintf(errOut, "[%s] error executing query: %v\n", now, err)
+			return
+		}
+
+		if diff, changed := w.observe(result); changed {
+			fmt.Fprintf(out, "[%s]\n%s\n", now, diff)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	poll()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+// diffLines produces a minimal line-based diff between prev and next.
+// Unchanged lines are prefixed with two spaces, removed lines with "- " and added lines with "+ ".
+func diffLines(prev, next string) string {
+	a := strings.Split(prev, "\n")
+	b := strings.Split(next, "\n")
+
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+ "+b[j])
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatcherObserve(t *testing.T) {
+	w := &watcher{}
+
+	out, changed := w.observe("(1 int)")
+	if !changed {
+		t.Fatal("observe() first result should be reported as changed")
+	}
+	if out != "(1 int)" {
+		t.Errorf("observe() first output = %q, want full result", out)
+	}
+
+	if _, changed := w.observe("(1 int)"); changed {
+		t.Error("observe() identical result should not be reported as changed")
+	}
+
+	out, changed = w.observe("(2 int)")
+	if !changed {
+		t.Fatal("observe() different result should be reported as changed")
+	}
+	if out != "- (1 int)\n+ (2 int)" {
+		t.Errorf("observe() diff output = %q", out)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+		want string
+	}{
+		{
+			name: "identical",
+			prev: "a\nb",
+			next: "a\nb",
+			want: "  a\n  b",
+		},
+		{
+			name: "line changed",
+			prev: "a\nb\nc",
+			next: "a\nx\nc",
+			want: "  a\n- b\n+ x\n  c",
+		},
+		{
+			name: "line appended",
+			prev: "a",
+			next: "a\nb",
+			want: "  a\n+ b",
+		},
+		{
+			name: "line removed",
+			prev: "a\nb\nc",
+			next: "a\nc",
+			want: "  a\n- b\n  c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.prev, tt.next); got != tt.want {
+				t.Errorf("diffLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// sequenceClient returns a predefined sequence of results, repeating the last one
+type sequenceClient struct {
+	mu      sync.Mutex
+	results []string
+	errs    []error
+	calls   int
+}
+
+func (s *sequenceClient) Query(realmPath, functionCall string) (string, error) {
+	return s.QueryContext(context.Background(), realmPath, functionCall)
+}
+
+func (s *sequenceClient) QueryContext(ctx context.Context, realmPath, functionCall string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := min(s.calls, len(s.results)-1)
+	s.calls++
+	return s.results[i], s.errs[i]
+}
+
+func TestWatchPrintsOnlyOnChange(t *testing.T) {
+	client := &sequenceClient{
+		results: []string{"(1 int)", "(1 int)", "", "(2 int)"},
+		errs:    []error{nil, nil, errors.New("rpc unavailable"), nil},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var out, errOut bytes.Buffer
+	if err := watch(ctx, client, "gno.land/r/test", "Count()", 10*time.Millisecond, time.Second, &out, &errOut); err != nil {
+		t.Fatalf("watch() error = %v", err)
+	}
+
+	if got := strings.Count(out.String(), "(1 int)"); got != 2 {
+		t.Errorf("expected initial result and its removal in diff, got %d occurrences in %q", got, out.String())
+	}
+	if !strings.Contains(out.String(), "+ (2 int)") {
+		t.Errorf("expected change to be reported as diff, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "rpc unavailable") {
+		t.Errorf("expected query error to be reported, got %q", errOut.String())
+	}
+}
@@ -1,8 +1,31 @@
 package gnoquery
 
 import (
-	"github.com/gnolang/gno/gno.land/pkg/gnoclient"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	_ "github.com/gnolang/gno/gno.land/pkg/sdk/vm" // registers the VM error types query responses carry
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	jsonrpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/client"
+	rpchttp "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/client/http"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// ErrTimeout is returned when a query does not complete before its context deadline.
+var ErrTimeout = errors.New("query timed out")
+
+// ErrHeightUnavailable is returned when the node has no state at the requested height, because
+// it is beyond the latest block or has been pruned.
+var ErrHeightUnavailable = errors.New("height not available on the node")
+
+// Defaults for retrying queries that fail to reach the node
+const (
+	DefaultRetries       = 3
+	DefaultRetryInterval = 500 * time.Millisecond
 )
 
 // Client provides an interface for executing queries against Gno blockchain realms.
@@ -15,29 +38,260 @@ type Client interface {
 	Query(realmPath, functionCall string) (string, error)
 }
 
-// gnoClient wraps gnoclient.Client to implement our Client interface
+// ContextClient is implemented by clients whose queries can be cancelled. The client returned
+// by NewClient implements it.
+type ContextClient interface {
+	Client
+
+	// QueryContext executes a function call like Query, giving up once ctx is done.
+	// A query cut short by the context deadline returns an error wrapping ErrTimeout.
+	QueryContext(ctx context.Context, realmPath, functionCall string) (string, error)
+}
+
+// HeightQuerier is implemented by clients that also report the block height a query was
+// evaluated at. The client returned by NewClient implements it.
+type HeightQuerier interface {
+	// QueryWithHeight executes a function call like QueryContext and also returns the block
+	// height of the state it was evaluated against.
+	QueryWithHeight(ctx context.Context, realmPath, functionCall string) (string, int64, error)
+}
+
+// QueryContext executes a function call with client, giving up once ctx is done. Clients that do
+// not implement ContextClient cannot be cancelled, so their query is left running in the
+// background once ctx is done. A query cut short by the context deadline returns an error
+// wrapping ErrTimeout.
+func QueryContext(ctx context.Context, client Client, realmPath, functionCall string) (string, error) {
+	if cc, ok := client.(ContextClient); ok {
+		return cc.QueryContext(ctx, realmPath, functionCall)
+	}
+
+	type response struct {
+		result string
+		err    error
+	}
+	done := make(chan response, 1)
+	go func() {
+		result, err := client.Query(realmPath, functionCall)
+		done <- response{result: result, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.result, res.err
+	case <-ctx.Done():
+		return "", contextError(ctx, realmPath, functionCall)
+	}
+}
+
+// gnoClient implements our Client interface with gnoclient QEval calls over one shared
+// JSON-RPC caller, so all queries reuse the same connections.
 type gnoClient struct {
-	client *gnoclient.Client
+	caller jsonrpcclient.Client
+	// err is the error creating the caller, returned by every query
+	err error
+
+	// retries is how many times a query failing to reach the node is retried
+	retries int
+	// retryInterval is the wait before the first retry, doubled for each further one
+	retryInterval time.Duration
+
+	// height is the block height queries are evaluated at; zero is the latest block
+	height int64
+}
+
+// Option configures a Client created by NewClient.
+type Option func(*gnoClient)
+
+// WithRetry sets how many times a query that fails to reach the node, or gets a 5xx or 429
+// response, is retried and how long to wait before the first retry. The wait doubles for each
+// further retry. Errors returned by the realm itself, such as panics, are never retried.
+func WithRetry(retries int, interval time.Duration) Option {
+	return func(g *gnoClient) {
+		g.retries = retries
+		g.retryInterval = interval
+	}
+}
+
+// AtHeight evaluates queries against the realm state at the given block height instead of the
+// latest block. Zero keeps the latest block.
+func AtHeight(height int64) Option {
+	return func(g *gnoClient) {
+		g.height = height
+	}
 }
 
 // Query executes a function call against the specified Gno realm.
 // It uses the underlying gnoclient to perform a QEval operation.
 // Returns the query result as a string or an error if the operation fails.
 func (g *gnoClient) Query(realmPath, functionCall string) (string, error) {
-	result, _, err := g.client.QEval(realmPath, functionCall)
+	return g.QueryContext(context.Background(), realmPath, functionCall)
+}
+
+// QueryContext executes a function call against the specified Gno realm until ctx is done.
+func (g *gnoClient) QueryContext(ctx context.Context, realmPath, functionCall string) (string, error) {
+	result, _, err := g.QueryWithHeight(ctx, realmPath, functionCall)
 	return result, err
 }
 
+// QueryWithHeight executes a function call against the specified Gno realm until ctx is done,
+// retrying transient failures to reach the node.
+// Returns the query result as a string and the block height it was evaluated at, if known.
+func (g *gnoClient) QueryWithHeight(ctx context.Context, realmPath, functionCall string) (string, int64, error) {
+	if g.err != nil {
+		return "", 0, g.err
+	}
+
+	wait := g.retryInterval
+	for attempt := 0; ; attempt++ {
+		result, height, err := g.queryOnce(ctx, realmPath, functionCall)
+		if err == nil || attempt >= g.retries || !isRetryable(err) {
+			return result, height, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", 0, contextError(ctx, realmPath, functionCall)
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+// queryOnce executes a single QEval of a function call against the specified Gno realm, as
+// gnoclient's QEval does but at the configured height.
+func (g *gnoClient) queryOnce(ctx context.Context, realmPath, functionCall string) (string, int64, error) {
+	client := rpcclient.NewRPCClient(contextCaller{ctx: ctx, Client: g.caller})
+
+	if g.height > 0 {
+		status, err := client.Status()
+		if err != nil {
+			return "", 0, queryError(ctx, realmPath, functionCall, fmt.Errorf("failed to get node status: %w", err))
+		}
+		if latest := status.SyncInfo.LatestBlockHeight; g.height > latest {
+			return "", 0, fmt.Errorf("%w: height %d is beyond the latest block %d", ErrHeightUnavailable, g.height, latest)
+		}
+	}
+
+	data := fmt.Appendf(nil, "%s.%s", realmPath, functionCall)
+	res, err := client.ABCIQueryWithOptions("vm/qeval", data, rpcclient.ABCIQueryOptions{Height: g.height})
+	if err != nil {
+		return "", 0, queryError(ctx, realmPath, functionCall, fmt.Errorf("query qeval: %w", err))
+	}
+	if res.Response.Error != nil {
+		// The node reports state it cannot load as an internal error
+		var internalErr std.InternalError
+		if g.height > 0 && errors.As(res.Response.Error, &internalErr) {
+			return "", 0, fmt.Errorf("%w: no state at height %d, it may have been pruned", ErrHeightUnavailable, g.height)
+		}
+		return "", 0, fmt.Errorf("QEval failed: log:%s: %w", res.Response.Log, res.Response.Error)
+	}
+
+	height := res.Response.Height
+	if height == 0 {
+		height = g.height
+	}
+	return string(res.Response.Data), height, nil
+}
+
+// queryError returns err, or the context error if the query failed because ctx is done.
+func queryError(ctx context.Context, realmPath, functionCall string, err error) error {
+	if ctx.Err() != nil {
+		return contextError(ctx, realmPath, functionCall)
+	}
+	return err
+}
+
+// contextError returns the error for a query cut short because ctx is done.
+func contextError(ctx context.Context, realmPath, functionCall string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s.%s", ErrTimeout, realmPath, functionCall)
+	}
+	return ctx.Err()
+}
+
+// transportError is a failure to get a response from the node, as opposed to an error returned
+// by the node or the realm.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+
+func (e *transportError) Unwrap() error { return e.err }
+
+// isRetryable reports whether a query error may go away on retry: the node could not be
+// reached or answered with a server error or rate limit.
+func isRetryable(err error) bool {
+	var transportErr *transportError
+	if !errors.As(err, &transportErr) {
+		return false
+	}
+
+	// The RPC client reports unexpected HTTP statuses only in its error message
+	var status int
+	if _, scanErr := fmt.Sscanf(transportErr.Error(), "invalid status code received, %d", &status); scanErr == nil {
+		return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// contextCaller sends requests through the wrapped caller, cancelling them once ctx is done.
+// The RPC client only bounds requests by its own timeout, so this threads the query context
+// through to the HTTP request.
+type contextCaller struct {
+	ctx context.Context
+	jsonrpcclient.Client
+}
+
+func (c contextCaller) SendRequest(ctx context.Context, request rpctypes.RPCRequest) (*rpctypes.RPCResponse, error) {
+	ctx, cancel := c.merge(ctx)
+	defer cancel()
+	response, err := c.Client.SendRequest(ctx, request)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+	return response, nil
+}
+
+func (c contextCaller) SendBatch(ctx context.Context, requests rpctypes.RPCRequests) (rpctypes.RPCResponses, error) {
+	ctx, cancel := c.merge(ctx)
+	defer cancel()
+	responses, err := c.Client.SendBatch(ctx, requests)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+	return responses, nil
+}
+
+// merge returns a context done when either the request context or the query context is.
+func (c contextCaller) merge(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
 // NewClient creates a new Client for querying Gno blockchain realms.
 // remote specifies the RPC endpoint URL (e.g., "tcp://localhost:26657" or "https://aiblabs.net:8443").
-// Returns a Client implementation that can execute queries against Gno realms.
-// This function cannot fail with the current gno implementation.
-func NewClient(remote string) Client {
-	rpcClient, _ := rpcclient.NewHTTPClient(remote)
+// Returns a Client implementation that can execute queries against Gno realms, retrying
+// transient failures DefaultRetries times unless configured otherwise with WithRetry.
+// An invalid remote is reported by the first query.
+func NewClient(remote string, opts ...Option) Client {
+	g := &gnoClient{
+		retries:       DefaultRetries,
+		retryInterval: DefaultRetryInterval,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
 
-	return &gnoClient{
-		client: &gnoclient.Client{
-			RPCClient: rpcClient,
-		},
+	g.caller, g.err = rpchttp.NewClient(remote)
+	if g.err != nil {
+		g.err = fmt.Errorf("invalid remote %q: %w", remote, g.err)
 	}
+	return g
 }
@@ -1,8 +1,20 @@
 package gnoquery
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 func TestNewClient(t *testing.T) {
@@ -14,6 +26,168 @@ func TestNewClient(t *testing.T) {
 	if _, ok := client.(*gnoClient); !ok {
 		t.Error("NewClient() did not return a *gnoClient")
 	}
+	if _, ok := client.(ContextClient); !ok {
+		t.Error("NewClient() should return a ContextClient")
+	}
+	if _, ok := client.(HeightQuerier); !ok {
+		t.Error("NewClient() should return a HeightQuerier")
+	}
+}
+
+func TestQueryContextDeadline(t *testing.T) {
+	// A node that never answers within the deadline
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := QueryContext(ctx, client, "gno.land/r/test", "GetInfo()")
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("QueryContext() error = %v, want %v", err, ErrTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("QueryContext() returned after %v, want it to give up at the deadline", elapsed)
+	}
+}
+
+// fakeRPC starts a node answering the nth request (counting from 1) with respond's HTTP status,
+// and with its result when the status is 200. Returns the server and the number of requests it
+// received.
+func fakeRPC(t *testing.T, respond func(n int32, request rpctypes.RPCRequest) (int, any)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request rpctypes.RPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		status, result := respond(requests.Add(1), request)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(rpctypes.NewRPCSuccessResponse(request.ID, result))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// queryResult is the abci_query result for a query response
+func queryResult(response abci.ResponseBase) *ctypes.ResultABCIQuery {
+	return &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{ResponseBase: response}}
+}
+
+func TestQueryAtHeight(t *testing.T) {
+	var queriedHeight string
+	server, _ := fakeRPC(t, func(n int32, request rpctypes.RPCRequest) (int, any) {
+		if request.Method == "status" {
+			return http.StatusOK, &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: 100}}
+		}
+		var params struct {
+			Height string `json:"height"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			t.Errorf("failed to decode abci_query params: %v", err)
+		}
+		queriedHeight = params.Height
+		if params.Height == "10" {
+			// Pruned state is reported as an internal error
+			return http.StatusOK, queryResult(abci.ResponseBase{Error: std.InternalError{}})
+		}
+		return http.StatusOK, queryResult(abci.ResponseBase{Data: []byte("(1 int)")})
+	})
+
+	client := NewClient(server.URL, AtHeight(42))
+	result, height, err := client.(HeightQuerier).QueryWithHeight(context.Background(), "gno.land/r/test", "Count()")
+	if err != nil {
+		t.Fatalf("QueryWithHeight() error = %v", err)
+	}
+	if queriedHeight != "42" {
+		t.Errorf("abci_query height = %q, want the requested height forwarded", queriedHeight)
+	}
+	if result != "(1 int)" || height != 42 {
+		t.Errorf("QueryWithHeight() = %q at %d, want (1 int) at 42", result, height)
+	}
+
+	// A height the node has not reached yet is rejected before querying
+	_, err = NewClient(server.URL, AtHeight(500)).Query("gno.land/r/test", "Count()")
+	if !errors.Is(err, ErrHeightUnavailable) {
+		t.Errorf("Query() beyond the latest block error = %v, want %v", err, ErrHeightUnavailable)
+	}
+
+	_, err = NewClient(server.URL, AtHeight(10)).Query("gno.land/r/test", "Count()")
+	if !errors.Is(err, ErrHeightUnavailable) {
+		t.Errorf("Query() at a pruned height error = %v, want %v", err, ErrHeightUnavailable)
+	}
+}
+
+func TestQueryRetriesTransientErrors(t *testing.T) {
+	server, requests := fakeRPC(t, func(n int32, _ rpctypes.RPCRequest) (int, any) {
+		if n <= 2 {
+			return http.StatusServiceUnavailable, nil
+		}
+		return http.StatusOK, queryResult(abci.ResponseBase{Data: []byte("(1 int)")})
+	})
+
+	client := NewClient(server.URL, WithRetry(3, time.Millisecond))
+	result, err := client.Query("gno.land/r/test", "Count()")
+	if err != nil {
+		t.Fatalf("Query() error = %v, want it to succeed after retries", err)
+	}
+	if result != "(1 int)" {
+		t.Errorf("Query() = %q, want %q", result, "(1 int)")
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("requests = %d, want 2 failures and 1 success", got)
+	}
+}
+
+func TestQueryDoesNotRetryRealmErrors(t *testing.T) {
+	server, requests := fakeRPC(t, func(n int32, _ rpctypes.RPCRequest) (int, any) {
+		return http.StatusOK, queryResult(abci.ResponseBase{
+			Error: abci.StringError("panic: boom"),
+			Log:   "realm panicked",
+		})
+	})
+
+	client := NewClient(server.URL, WithRetry(3, time.Millisecond))
+	_, err := client.Query("gno.land/r/test", "Boom()")
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("Query() error = %v, want the realm panic", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want a realm panic not to be retried", got)
+	}
+}
+
+func TestQueryRetriesStopAtDeadline(t *testing.T) {
+	server, requests := fakeRPC(t, func(n int32, _ rpctypes.RPCRequest) (int, any) {
+		return http.StatusBadGateway, nil
+	})
+
+	client := NewClient(server.URL, WithRetry(10, 40*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := QueryContext(ctx, client, "gno.land/r/test", "Count()")
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("QueryContext() error = %v, want %v", err, ErrTimeout)
+	}
+	if got := requests.Load(); got >= 10 {
+		t.Errorf("requests = %d, want retries cut short by the deadline", got)
+	}
 }
 
 // mockClient implements Client interface for testing
@@ -26,6 +200,32 @@ func (m *mockClient) Query(realmPath, functionCall string) (string, error) {
 	return m.result, m.err
 }
 
+// blockingClient implements only Client, answering once release is closed
+type blockingClient struct {
+	release chan struct{}
+}
+
+func (b *blockingClient) Query(realmPath, functionCall string) (string, error) {
+	<-b.release
+	return "(1 int)", nil
+}
+
+func TestQueryContextPlainClient(t *testing.T) {
+	client := &blockingClient{release: make(chan struct{})}
+	defer close(client.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := QueryContext(ctx, client, "gno.land/r/test", "Count()"); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("QueryContext() error = %v, want %v", err, ErrTimeout)
+	}
+
+	result, err := QueryContext(context.Background(), &mockClient{result: "(true bool)"}, "gno.land/r/test", "GetInfo()")
+	if err != nil || result != "(true bool)" {
+		t.Errorf("QueryContext() = %q, %v, want the client's result", result, err)
+	}
+}
+
 func TestClientQuery(t *testing.T) {
 	tests := []struct {
 		name         string